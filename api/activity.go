@@ -65,6 +65,10 @@ const (
 
 	// ActivityDatabaseRecoveryPITRDone is the type for performing PITR on the database successfully.
 	ActivityDatabaseRecoveryPITRDone ActivityType = "bb.database.recovery.pitr.done"
+	// ActivityDatabaseDataExport is the type for exporting the query result of the database.
+	ActivityDatabaseDataExport ActivityType = "bb.database.data.export"
+	// ActivityDatabaseDataExportDownload is the type for downloading the exported data.
+	ActivityDatabaseDataExportDownload ActivityType = "bb.database.data.export.download"
 )
 
 // ActivityLevel is the level of activities.
@@ -218,6 +222,21 @@ type ActivitySQLEditorQueryPayload struct {
 	DatabaseName           string           `json:"databaseName"`
 	Error                  string           `json:"error"`
 	AdviceList             []advisor.Advice `json:"adviceList"`
+	// Canceled is true if the query is canceled by the user or exceeds the max execution time.
+	Canceled bool `json:"canceled"`
+}
+
+// ActivityDatabaseDataExportPayload is the API message payloads for exporting and downloading the query result of the database.
+type ActivityDatabaseDataExportPayload struct {
+	IssueID int `json:"issueId"`
+	TaskID  int `json:"taskId"`
+	// Used by activity table to display info without paying the join cost
+	DatabaseID   int              `json:"databaseId"`
+	DatabaseName string           `json:"databaseName"`
+	Statement    string           `json:"statement"`
+	Format       DataExportFormat `json:"format"`
+	RowCount     int              `json:"rowCount"`
+	Error        string           `json:"error,omitempty"`
 }
 
 // Activity is the API message for an activity.
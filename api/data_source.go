@@ -41,12 +41,38 @@ const (
 	RO DataSourceType = "RO"
 )
 
+// CredentialProviderType is the type of the provider getting the password of a data source.
+type CredentialProviderType string
+
+const (
+	// CredentialProviderStatic uses the password stored in the data source.
+	CredentialProviderStatic CredentialProviderType = "STATIC"
+	// CredentialProviderAWSRDSIAM generates the AWS RDS IAM authentication token as the password.
+	CredentialProviderAWSRDSIAM CredentialProviderType = "AWS_RDS_IAM"
+	// CredentialProviderGCPCloudSQLIAM uses the OAuth2 access token of the GCP default credentials as the password.
+	CredentialProviderGCPCloudSQLIAM CredentialProviderType = "GCP_CLOUD_SQL_IAM"
+	// CredentialProviderCommand uses the output of an external command configured by the server flag as the password.
+	CredentialProviderCommand CredentialProviderType = "COMMAND"
+)
+
 // DataSourceOptions is the options for a data source.
 type DataSourceOptions struct {
 	// SRV is used for MongoDB only.
 	SRV bool `json:"srv" jsonapi:"attr,srv"`
 	// AuthenticationDatabase is used for MongoDB only.
 	AuthenticationDatabase string `json:"authenticationDatabase" jsonapi:"attr,authenticationDatabase"`
+	// ServiceName is used for Oracle only.
+	ServiceName string `json:"serviceName" jsonapi:"attr,serviceName"`
+	// CredentialProvider gets the password before connecting, the empty value is the same as CredentialProviderStatic.
+	CredentialProvider CredentialProviderType `json:"credentialProvider" jsonapi:"attr,credentialProvider"`
+	// Region is the AWS region used by CredentialProviderAWSRDSIAM, the default region of the AWS config is used if empty.
+	Region string `json:"region" jsonapi:"attr,region"`
+	// CredentialCommandName is the name of the command used by CredentialProviderCommand, which is configured by the --credential-command server flag.
+	// Only the workspace owner and DBA can set it.
+	CredentialCommandName string `json:"credentialCommandName" jsonapi:"attr,credentialCommandName"`
+	// PasswordSecret is the reference to the password in the external secret manager, such as "env:BYTEBASE_SECRET_MYSQL" or "vault:secret/mysql#password".
+	// It's used by CredentialProviderStatic instead of the stored password if not empty. Only the workspace owner and DBA can set it.
+	PasswordSecret string `json:"passwordSecret" jsonapi:"attr,passwordSecret"`
 }
 
 // getDefaultDataSourceOptions returns the default data source options.
@@ -54,6 +80,7 @@ func getDefaultDataSourceOptions() DataSourceOptions {
 	return DataSourceOptions{
 		SRV:                    false,
 		AuthenticationDatabase: "",
+		ServiceName:            "",
 	}
 }
 
@@ -100,6 +127,14 @@ type DataSource struct {
 	Port     string            `jsonapi:"attr,port"`
 	Options  DataSourceOptions `jsonapi:"attr,options"`
 	Database string            `jsonapi:"attr,database"`
+	// The SSH tunnel is used if the SSH host isn't empty.
+	SSHHost    string `jsonapi:"attr,sshHost"`
+	SSHPort    string `jsonapi:"attr,sshPort"`
+	SSHUser    string `jsonapi:"attr,sshUser"`
+	SSHHostKey string `jsonapi:"attr,sshHostKey"`
+	// Do not return the SSH password and private key to client
+	SSHPassword   string
+	SSHPrivateKey string
 }
 
 // DataSourceCreate is the API message for creating a data source.
@@ -124,6 +159,13 @@ type DataSourceCreate struct {
 	Port     string            `jsonapi:"attr,port"`
 	Options  DataSourceOptions `jsonapi:"attr,options"`
 	Database string            `jsonapi:"attr,database"`
+	// The SSH tunnel is used if the SSH host isn't empty.
+	SSHHost       string `jsonapi:"attr,sshHost"`
+	SSHPort       string `jsonapi:"attr,sshPort"`
+	SSHUser       string `jsonapi:"attr,sshUser"`
+	SSHHostKey    string `jsonapi:"attr,sshHostKey"`
+	SSHPassword   string `jsonapi:"attr,sshPassword"`
+	SSHPrivateKey string `jsonapi:"attr,sshPrivateKey"`
 }
 
 // DataSourceFind is the API message for finding data sources.
@@ -165,6 +207,12 @@ type DataSourcePatch struct {
 	Port             *string            `jsonapi:"attr,port"`
 	Options          *DataSourceOptions `jsonapi:"attr,options"`
 	Database         *string            `jsonapi:"attr,database"`
+	SSHHost          *string            `jsonapi:"attr,sshHost"`
+	SSHPort          *string            `jsonapi:"attr,sshPort"`
+	SSHUser          *string            `jsonapi:"attr,sshUser"`
+	SSHHostKey       *string            `jsonapi:"attr,sshHostKey"`
+	SSHPassword      *string            `jsonapi:"attr,sshPassword"`
+	SSHPrivateKey    *string            `jsonapi:"attr,sshPrivateKey"`
 }
 
 // DataSourceDelete is the API message for deleting data sources.
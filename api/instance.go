@@ -65,6 +65,24 @@ type InstanceCreate struct {
 	SRV bool `jsonapi:"attr,srv"`
 	// AuthenticationDatabase is used for MongoDB only.
 	AuthenticationDatabase string `jsonapi:"attr,authenticationDatabase"`
+	// ServiceName is used for Oracle only.
+	ServiceName string `jsonapi:"attr,serviceName"`
+	// CredentialProvider gets the password before connecting.
+	CredentialProvider CredentialProviderType `jsonapi:"attr,credentialProvider"`
+	// Region is used by the AWS RDS IAM credential provider only.
+	Region string `jsonapi:"attr,region"`
+	// CredentialCommandName is used by the command credential provider only.
+	CredentialCommandName string `jsonapi:"attr,credentialCommandName"`
+	// PasswordSecret is the reference to the password in the external secret manager.
+	PasswordSecret string `jsonapi:"attr,passwordSecret"`
+
+	// The SSH tunnel is used if the SSH host isn't empty.
+	SSHHost       string `jsonapi:"attr,sshHost"`
+	SSHPort       string `jsonapi:"attr,sshPort"`
+	SSHUser       string `jsonapi:"attr,sshUser"`
+	SSHHostKey    string `jsonapi:"attr,sshHostKey"`
+	SSHPassword   string `jsonapi:"attr,sshPassword"`
+	SSHPrivateKey string `jsonapi:"attr,sshPrivateKey"`
 }
 
 // InstanceFind is the API message for finding instances.
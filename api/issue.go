@@ -39,6 +39,8 @@ const (
 	IssueDatabaseRestorePITR IssueType = "bb.issue.database.restore.pitr"
 	// IssueDatabaseRollback is the issue type for a generated rollback issue.
 	IssueDatabaseRollback IssueType = "bb.issue.database.rollback"
+	// IssueDatabaseDataExport is the issue type for exporting the query result of a database.
+	IssueDatabaseDataExport IssueType = "bb.issue.database.data.export"
 )
 
 // IssueFieldID is the field ID for an issue.
@@ -217,6 +219,20 @@ type RollbackContext struct {
 	TaskIDList []int `json:"taskIdList"`
 }
 
+// DataExportContext is the issue create context for exporting the query result of a database.
+type DataExportContext struct {
+	// DatabaseID is the ID of the database to query.
+	DatabaseID int `json:"databaseId"`
+	// Statement is the SELECT statement to export the result of.
+	Statement string `json:"statement"`
+	// Format is the format of the exported file.
+	Format DataExportFormat `json:"format"`
+	// Limit is the maximum number of the exported rows, zero means the maximum allowed.
+	Limit int `json:"limit"`
+	// TableName is the table name in the INSERT statements of the SQL format.
+	TableName string `json:"tableName"`
+}
+
 // IssueFind is the API message for finding issues.
 type IssueFind struct {
 	ID *int
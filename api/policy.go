@@ -6,6 +6,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/db"
 )
 
 // PolicyType is the type or name of a policy.
@@ -42,6 +43,8 @@ const (
 	PolicyTypeSensitiveData PolicyType = "bb.policy.sensitive-data"
 	// PolicyTypeAccessControl is the access control policy type.
 	PolicyTypeAccessControl PolicyType = "bb.policy.access-control"
+	// PolicyTypeQueryTimeout is the policy type for the maximum execution time of the SQL editor queries.
+	PolicyTypeQueryTimeout PolicyType = "bb.policy.query-timeout"
 
 	// PipelineApprovalValueManualNever means the pipeline will automatically be approved without user intervention.
 	PipelineApprovalValueManualNever PipelineApprovalValue = "MANUAL_APPROVAL_NEVER"
@@ -88,6 +91,7 @@ var (
 		PolicyTypeEnvironmentTier:  {PolicyResourceTypeEnvironment},
 		PolicyTypeSensitiveData:    {PolicyResourceTypeDatabase},
 		PolicyTypeAccessControl:    {PolicyResourceTypeEnvironment, PolicyResourceTypeDatabase},
+		PolicyTypeQueryTimeout:     {PolicyResourceTypeEnvironment},
 	}
 )
 
@@ -207,6 +211,9 @@ type BackupPlanPolicy struct {
 	Schedule BackupPlanPolicySchedule `json:"schedule"`
 	// RetentionPeriodTs is the minimum allowed period that backup data is kept for databases in an environment.
 	RetentionPeriodTs int `json:"retentionPeriodTs"`
+	// PITR requires the WAL archiving and base backups for PostgreSQL instances in an environment,
+	// so that the databases can be recovered to any point in time within the retention period.
+	PITR bool `json:"pitr,omitempty"`
 }
 
 func (bp *BackupPlanPolicy) String() (string, error) {
@@ -256,9 +263,22 @@ type SensitiveDataPolicy struct {
 
 // SensitiveData is the value for sensitive data.
 type SensitiveData struct {
+	// Schema is only used for Postgres, and the empty schema means "public".
+	Schema string                `json:"schema,omitempty"`
 	Table  string                `json:"table"`
 	Column string                `json:"column"`
 	Type   SensitiveDataMaskType `json:"maskType"`
+	// MaskOption is the option for the PARTIAL and RANGE mask types.
+	MaskOption *SensitiveDataMaskOption `json:"maskOption,omitempty"`
+}
+
+// NormalizeSensitiveDataSchema returns the schema used to compare the sensitive columns,
+// since the empty schema in the sensitive data policy means "public" for Postgres.
+func NormalizeSensitiveDataSchema(dbType db.Type, schema string) string {
+	if dbType == db.Postgres && schema == "" {
+		return "public"
+	}
+	return schema
 }
 
 // SensitiveDataMaskType is the mask type for sensitive data.
@@ -268,8 +288,84 @@ const (
 	// SensitiveDataMaskTypeDefault is the sensitive data type to hide data with a default method.
 	// The default method is subject to change.
 	SensitiveDataMaskTypeDefault SensitiveDataMaskType = "DEFAULT"
+	// SensitiveDataMaskTypeFull is the sensitive data type to hide the whole value.
+	SensitiveDataMaskTypeFull SensitiveDataMaskType = "FULL"
+	// SensitiveDataMaskTypePartial is the sensitive data type to hide the value except the first and the last characters.
+	SensitiveDataMaskTypePartial SensitiveDataMaskType = "PARTIAL"
+	// SensitiveDataMaskTypeHash is the sensitive data type to replace the value with its SHA-256 hash salted by the workspace.
+	SensitiveDataMaskTypeHash SensitiveDataMaskType = "HASH"
+	// SensitiveDataMaskTypeRange is the sensitive data type to replace the number or the date with the range containing it.
+	SensitiveDataMaskTypeRange SensitiveDataMaskType = "RANGE"
+	// SensitiveDataMaskTypeEmail is the sensitive data type to hide the local part of the email address except the first character.
+	SensitiveDataMaskTypeEmail SensitiveDataMaskType = "EMAIL"
+	// SensitiveDataMaskTypePhone is the sensitive data type to hide the digits of the phone number except the last four.
+	SensitiveDataMaskTypePhone SensitiveDataMaskType = "PHONE"
+)
+
+// SensitiveDataMaskOption is the option for the mask type.
+type SensitiveDataMaskOption struct {
+	// PrefixLength and SuffixLength are the numbers of the characters kept by the PARTIAL mask.
+	PrefixLength int `json:"prefixLength,omitempty"`
+	SuffixLength int `json:"suffixLength,omitempty"`
+	// RangeWidth is the width of the number ranges by the RANGE mask, such as 42 is masked as "[40, 50)" with the width 10.
+	RangeWidth float64 `json:"rangeWidth,omitempty"`
+	// RangeUnit is the unit of the date ranges by the RANGE mask.
+	RangeUnit SensitiveDataRangeUnit `json:"rangeUnit,omitempty"`
+}
+
+// SensitiveDataRangeUnit is the unit of the date ranges by the RANGE mask.
+type SensitiveDataRangeUnit string
+
+const (
+	// SensitiveDataRangeUnitYear masks the date with its year.
+	SensitiveDataRangeUnitYear SensitiveDataRangeUnit = "YEAR"
+	// SensitiveDataRangeUnitMonth masks the date with its month.
+	SensitiveDataRangeUnitMonth SensitiveDataRangeUnit = "MONTH"
+	// SensitiveDataRangeUnitDay masks the date with its day.
+	SensitiveDataRangeUnitDay SensitiveDataRangeUnit = "DAY"
 )
 
+func (data *SensitiveData) validate() error {
+	if data.Table == "" || data.Column == "" {
+		return errors.Errorf("sensitive data policy rule cannot have empty table or column name")
+	}
+	if err := validateSensitiveDataMask(data.Type, data.MaskOption); err != nil {
+		return errors.Wrapf(err, "invalid mask for %q.%q", data.Table, data.Column)
+	}
+	return nil
+}
+
+func validateSensitiveDataMask(maskType SensitiveDataMaskType, option *SensitiveDataMaskOption) error {
+	if option == nil {
+		option = &SensitiveDataMaskOption{}
+	}
+	switch maskType {
+	case SensitiveDataMaskTypeDefault, SensitiveDataMaskTypeFull, SensitiveDataMaskTypeHash, SensitiveDataMaskTypeEmail, SensitiveDataMaskTypePhone:
+	case SensitiveDataMaskTypePartial:
+		if option.PrefixLength < 0 || option.SuffixLength < 0 {
+			return errors.Errorf("the kept prefix and suffix length of the %s mask cannot be negative", maskType)
+		}
+		if option.PrefixLength == 0 && option.SuffixLength == 0 {
+			return errors.Errorf("the %s mask must keep the prefix or the suffix, use the %s mask to hide the whole value", maskType, SensitiveDataMaskTypeFull)
+		}
+	case SensitiveDataMaskTypeRange:
+		if option.RangeWidth < 0 {
+			return errors.Errorf("the range width of the %s mask cannot be negative", maskType)
+		}
+		switch option.RangeUnit {
+		case "", SensitiveDataRangeUnitYear, SensitiveDataRangeUnitMonth, SensitiveDataRangeUnitDay:
+		default:
+			return errors.Errorf("invalid range unit %q of the %s mask", option.RangeUnit, maskType)
+		}
+		if option.RangeWidth == 0 && option.RangeUnit == "" {
+			return errors.Errorf("the %s mask must have the range width for numbers or the range unit for dates", maskType)
+		}
+	default:
+		return errors.Errorf("invalid mask type %q", maskType)
+	}
+	return nil
+}
+
 // UnmarshalSensitiveDataPolicy will unmarshal payload to sensitive data policy.
 func UnmarshalSensitiveDataPolicy(payload string) (*SensitiveDataPolicy, error) {
 	var p SensitiveDataPolicy
@@ -319,6 +415,29 @@ func (p *AccessControlPolicy) String() (string, error) {
 	return string(s), nil
 }
 
+// QueryTimeoutPolicy is the policy configuration for the maximum execution time of the SQL editor queries.
+type QueryTimeoutPolicy struct {
+	// MaxExecutionSeconds is the maximum execution time of a query. No timeout enforced if it's 0.
+	MaxExecutionSeconds int `json:"maxExecutionSeconds"`
+}
+
+// UnmarshalQueryTimeoutPolicy will unmarshal payload to query timeout policy.
+func UnmarshalQueryTimeoutPolicy(payload string) (*QueryTimeoutPolicy, error) {
+	var p QueryTimeoutPolicy
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal query timeout policy %q", payload)
+	}
+	return &p, nil
+}
+
+func (p *QueryTimeoutPolicy) String() (string, error) {
+	s, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
+
 // UnmarshalEnvironmentTierPolicy will unmarshal payload to environment tier policy.
 func UnmarshalEnvironmentTierPolicy(payload string) (*EnvironmentTierPolicy, error) {
 	var p EnvironmentTierPolicy
@@ -382,6 +501,9 @@ func ValidatePolicy(resourceType PolicyResourceType, pType PolicyType, payload *
 		if bp.Schedule != BackupPlanPolicyScheduleUnset && bp.Schedule != BackupPlanPolicyScheduleDaily && bp.Schedule != BackupPlanPolicyScheduleWeekly {
 			return errors.Errorf("invalid backup plan policy schedule: %q", bp.Schedule)
 		}
+		if bp.PITR && bp.Schedule == BackupPlanPolicyScheduleUnset {
+			return errors.Errorf("backup plan policy schedule must be set to require PITR")
+		}
 		return nil
 	case PolicyTypeSQLReview:
 		sr, err := UnmarshalSQLReviewPolicy(*payload)
@@ -407,11 +529,8 @@ func ValidatePolicy(resourceType PolicyResourceType, pType PolicyType, payload *
 			return err
 		}
 		for _, v := range p.SensitiveDataList {
-			if v.Table == "" || v.Column == "" {
-				return errors.Errorf("sensitive data policy rule cannot have empty table or column name")
-			}
-			if v.Type != SensitiveDataMaskTypeDefault {
-				return errors.Errorf("sensitive data policy rule must have mask type %q", SensitiveDataMaskTypeDefault)
+			if err := v.validate(); err != nil {
+				return err
 			}
 		}
 		return nil
@@ -420,6 +539,15 @@ func ValidatePolicy(resourceType PolicyResourceType, pType PolicyType, payload *
 			return err
 		}
 		return nil
+	case PolicyTypeQueryTimeout:
+		p, err := UnmarshalQueryTimeoutPolicy(*payload)
+		if err != nil {
+			return err
+		}
+		if p.MaxExecutionSeconds < 0 {
+			return errors.Errorf("invalid max execution seconds %d, it must not be negative", p.MaxExecutionSeconds)
+		}
+		return nil
 	}
 	return nil
 }
@@ -449,6 +577,9 @@ func GetDefaultPolicy(pType PolicyType) (string, error) {
 	case PolicyTypeSensitiveData:
 		policy := SensitiveDataPolicy{}
 		return policy.String()
+	case PolicyTypeQueryTimeout:
+		policy := QueryTimeoutPolicy{}
+		return policy.String()
 	}
 	return "", nil
 }
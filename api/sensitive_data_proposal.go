@@ -0,0 +1,192 @@
+package api
+
+import (
+	"encoding/json"
+	"regexp"
+
+	"github.com/pkg/errors"
+)
+
+// SensitiveDataProposalStatus is the status of a sensitive data proposal.
+type SensitiveDataProposalStatus string
+
+const (
+	// SensitiveDataProposalPending is the status of the proposal waiting for review.
+	SensitiveDataProposalPending SensitiveDataProposalStatus = "PENDING"
+	// SensitiveDataProposalApproved is the status of the proposal added to the sensitive data policy.
+	SensitiveDataProposalApproved SensitiveDataProposalStatus = "APPROVED"
+	// SensitiveDataProposalRejected is the status of the proposal rejected by the reviewer.
+	// The rejected column is not proposed again.
+	SensitiveDataProposalRejected SensitiveDataProposalStatus = "REJECTED"
+)
+
+// SensitiveDataProposalPayload is the payload for sensitive data proposals.
+type SensitiveDataProposalPayload struct {
+	// RuleTitle is the title of the classification rule proposing the column.
+	RuleTitle string `json:"ruleTitle,omitempty"`
+	// Reason is how the rule matches the column, such as "column name".
+	Reason string `json:"reason,omitempty"`
+	// The mask type and option added to the sensitive data policy once the proposal is approved.
+	MaskType   SensitiveDataMaskType    `json:"maskType,omitempty"`
+	MaskOption *SensitiveDataMaskOption `json:"maskOption,omitempty"`
+}
+
+// SensitiveDataProposal is the API message for a sensitive data proposal, which is a sensitive column found by the classifier.
+type SensitiveDataProposal struct {
+	ID int `jsonapi:"primary,sensitiveDataProposal"`
+
+	// Standard fields
+	CreatorID int
+	Creator   *Principal `jsonapi:"relation,creator"`
+	CreatedTs int64      `jsonapi:"attr,createdTs"`
+	UpdaterID int
+	Updater   *Principal `jsonapi:"relation,updater"`
+	UpdatedTs int64      `jsonapi:"attr,updatedTs"`
+
+	// Related fields
+	DatabaseID int `jsonapi:"attr,databaseId"`
+
+	// Domain specific fields
+	Schema  string                      `jsonapi:"attr,schema"`
+	Table   string                      `jsonapi:"attr,table"`
+	Column  string                      `jsonapi:"attr,column"`
+	Status  SensitiveDataProposalStatus `jsonapi:"attr,status"`
+	Payload string                      `jsonapi:"attr,payload"`
+}
+
+// SensitiveDataProposalCreate is the API message for creating a sensitive data proposal.
+type SensitiveDataProposalCreate struct {
+	// Standard fields
+	CreatorID int
+
+	// Related fields
+	DatabaseID int
+
+	// Domain specific fields
+	Schema  string
+	Table   string
+	Column  string
+	Payload string
+}
+
+// SensitiveDataProposalFind is the API message for finding sensitive data proposals.
+type SensitiveDataProposalFind struct {
+	ID *int
+
+	// Related fields
+	DatabaseID *int
+
+	// Domain specific fields
+	Status *SensitiveDataProposalStatus
+}
+
+// SensitiveDataProposalPatch is the API message for patching a sensitive data proposal.
+type SensitiveDataProposalPatch struct {
+	ID int `jsonapi:"primary,sensitiveDataProposalPatch"`
+
+	// Standard fields
+	// Value is assigned from the jwt subject field passed by the client.
+	UpdaterID int
+
+	// Domain specific fields
+	Status SensitiveDataProposalStatus `jsonapi:"attr,status"`
+}
+
+// SensitiveDataClassificationRule is the rule to classify the sensitive columns.
+// The column matches the rule if the type pattern matches its type,
+// and the name pattern, the comment pattern or the value pattern matches.
+// The value pattern matches if all sampled non-NULL values match it.
+type SensitiveDataClassificationRule struct {
+	Title             string `json:"title"`
+	ColumnNamePattern string `json:"columnNamePattern,omitempty"`
+	CommentPattern    string `json:"commentPattern,omitempty"`
+	TypePattern       string `json:"typePattern,omitempty"`
+	ValuePattern      string `json:"valuePattern,omitempty"`
+	// The mask type and option proposed for the matched columns.
+	MaskType   SensitiveDataMaskType    `json:"maskType"`
+	MaskOption *SensitiveDataMaskOption `json:"maskOption,omitempty"`
+}
+
+// SettingSensitiveDataClassificationValue is the setting value of the sensitive data classification.
+type SettingSensitiveDataClassificationValue struct {
+	// Enabled is whether to classify the columns after the schema sync.
+	Enabled bool `json:"enabled"`
+	// SampleLimit is the maximum number of the values sampled for each column, zero means no sampling.
+	SampleLimit int                               `json:"sampleLimit"`
+	RuleList    []SensitiveDataClassificationRule `json:"ruleList"`
+}
+
+// maxSensitiveDataSampleLimit is the maximum sample limit, the sampling queries run against the production databases.
+const maxSensitiveDataSampleLimit = 100
+
+// UnmarshalSensitiveDataClassification unmarshals and validates the sensitive data classification setting value.
+func UnmarshalSensitiveDataClassification(value string) (*SettingSensitiveDataClassificationValue, error) {
+	var v SettingSensitiveDataClassificationValue
+	if err := json.Unmarshal([]byte(value), &v); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal sensitive data classification %q", value)
+	}
+	if v.SampleLimit < 0 || v.SampleLimit > maxSensitiveDataSampleLimit {
+		return nil, errors.Errorf("sample limit must be between 0 and %d", maxSensitiveDataSampleLimit)
+	}
+	for _, rule := range v.RuleList {
+		if rule.Title == "" {
+			return nil, errors.Errorf("classification rule must have a title")
+		}
+		if rule.ColumnNamePattern == "" && rule.CommentPattern == "" && rule.ValuePattern == "" {
+			return nil, errors.Errorf("classification rule %q must have a column name, comment or value pattern", rule.Title)
+		}
+		for _, pattern := range []string{rule.ColumnNamePattern, rule.CommentPattern, rule.TypePattern, rule.ValuePattern} {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return nil, errors.Wrapf(err, "invalid pattern %q in classification rule %q", pattern, rule.Title)
+			}
+		}
+		if err := validateSensitiveDataMask(rule.MaskType, rule.MaskOption); err != nil {
+			return nil, errors.Wrapf(err, "invalid mask in classification rule %q", rule.Title)
+		}
+	}
+	return &v, nil
+}
+
+// GetDefaultSensitiveDataClassification returns the default classification rules for the common personal data.
+func GetDefaultSensitiveDataClassification() *SettingSensitiveDataClassificationValue {
+	return &SettingSensitiveDataClassificationValue{
+		Enabled:     false,
+		SampleLimit: 0,
+		RuleList: []SensitiveDataClassificationRule{
+			{
+				Title:             "Email",
+				ColumnNamePattern: `(?i)(^|_)e?mail(_?address)?$`,
+				ValuePattern:      `^[^@\s]+@[^@\s]+\.[^@\s]+$`,
+				MaskType:          SensitiveDataMaskTypeEmail,
+			},
+			{
+				Title:             "Phone number",
+				ColumnNamePattern: `(?i)(^|_)(phone|mobile|tel|telephone)(_?(number|no))?$`,
+				MaskType:          SensitiveDataMaskTypePhone,
+			},
+			{
+				Title:             "Identity number",
+				ColumnNamePattern: `(?i)(^|_)(ssn|id_?card|passport|national_?id)(_?(number|no))?$`,
+				MaskType:          SensitiveDataMaskTypeFull,
+			},
+			{
+				Title:             "Bank card",
+				ColumnNamePattern: `(?i)(^|_)(card|credit_?card|bank_?card|iban)(_?(number|no))?$`,
+				ValuePattern:      `^\d{4}[ -]?\d{4}[ -]?\d{4}[ -]?\d{1,7}$`,
+				MaskType:          SensitiveDataMaskTypePartial,
+				MaskOption:        &SensitiveDataMaskOption{SuffixLength: 4},
+			},
+			{
+				Title:             "Secret",
+				ColumnNamePattern: `(?i)(^|_)(password|passwd|secret|token)$`,
+				MaskType:          SensitiveDataMaskTypeFull,
+			},
+			{
+				Title:             "Birthday",
+				ColumnNamePattern: `(?i)(^|_)(birthday|birth_?date|date_of_birth|dob)$`,
+				MaskType:          SensitiveDataMaskTypeRange,
+				MaskOption:        &SensitiveDataMaskOption{RangeUnit: SensitiveDataRangeUnitYear},
+			},
+		},
+	}
+}
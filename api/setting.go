@@ -20,6 +20,10 @@ const (
 	SettingEnterpriseTrial SettingName = "bb.enterprise.trial"
 	// SettingAppIM is the setting name for IM applications.
 	SettingAppIM SettingName = "bb.app.im"
+	// SettingMaskingSalt is the setting name for the salt of the HASH sensitive data mask.
+	SettingMaskingSalt SettingName = "bb.masking.salt"
+	// SettingSensitiveDataClassification is the setting name for the rules to classify the sensitive columns.
+	SettingSensitiveDataClassification SettingName = "bb.sensitive-data.classification"
 )
 
 // IMType is the type of IM.
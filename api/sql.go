@@ -24,6 +24,22 @@ type ConnectionInfo struct {
 	SRV bool `jsonapi:"attr,srv"`
 	// AuthenticationDatabase is used for MongoDB only.
 	AuthenticationDatabase string `json:"authenticationDatabase" jsonapi:"attr,authenticationDatabase"`
+	// ServiceName is used for Oracle only.
+	ServiceName string `json:"serviceName" jsonapi:"attr,serviceName"`
+	// CredentialProvider gets the password before connecting, the command credential provider isn't supported for testing the connection.
+	CredentialProvider CredentialProviderType `jsonapi:"attr,credentialProvider"`
+	// Region is used by the AWS RDS IAM credential provider only.
+	Region string `jsonapi:"attr,region"`
+	// PasswordSecret is the reference to the password in the external secret manager, which isn't supported for testing the connection.
+	PasswordSecret string `jsonapi:"attr,passwordSecret"`
+	// The SSH tunnel is used if the SSH host isn't empty.
+	SSHHost    string `jsonapi:"attr,sshHost"`
+	SSHPort    string `jsonapi:"attr,sshPort"`
+	SSHUser    string `jsonapi:"attr,sshUser"`
+	SSHHostKey string `jsonapi:"attr,sshHostKey"`
+	// The existing SSH password and private key of the instance are used if both are empty.
+	SSHPassword   string `jsonapi:"attr,sshPassword"`
+	SSHPrivateKey string `jsonapi:"attr,sshPrivateKey"`
 }
 
 // SQLSyncSchema is the API message for sync schemas.
@@ -44,11 +60,15 @@ type SQLExecute struct {
 	// The maximum row count returned, only applicable to SELECT query.
 	// Not enforced if limit <= 0.
 	Limit int `jsonapi:"attr,limit"`
+	// ExecutionID is the unique ID chosen by the client to cancel the execution.
+	// The execution cannot be canceled if it's empty.
+	ExecutionID string `jsonapi:"attr,executionId"`
 }
 
 // SQLResultSet is the API message for SQL results.
 type SQLResultSet struct {
 	// A list of rows marshalled into a JSON.
+	// The rows may be partial if the query fails halfway, for example, the result exceeds the size limit.
 	Data string `jsonapi:"attr,data"`
 	// SQL operation may fail for connection issue and there is no proper http status code for it, so we return error in the response body.
 	Error string `jsonapi:"attr,error"`
@@ -51,8 +51,37 @@ const (
 	TaskDatabaseRestorePITRRestore TaskType = "bb.task.database.restore.pitr.restore"
 	// TaskDatabaseRestorePITRCutover is the task type for swapping the pitr and original database.
 	TaskDatabaseRestorePITRCutover TaskType = "bb.task.database.restore.pitr.cutover"
+	// TaskDatabaseDataExport is the task type for exporting the query result of a database.
+	TaskDatabaseDataExport TaskType = "bb.task.database.data.export"
 )
 
+// DataExportFormat is the format of the exported data.
+type DataExportFormat string
+
+const (
+	// DataExportFormatCSV is the CSV format with a header line of the column names.
+	DataExportFormatCSV DataExportFormat = "CSV"
+	// DataExportFormatJSON is the JSON array of the objects keyed by the column names.
+	DataExportFormatJSON DataExportFormat = "JSON"
+	// DataExportFormatSQL is the INSERT statements of the rows.
+	DataExportFormatSQL DataExportFormat = "SQL"
+)
+
+// MaxDataExportLimit is the maximum number of the rows exported by a data export task.
+const MaxDataExportLimit = 1000000
+
+// Extension returns the file extension of the format.
+func (f DataExportFormat) Extension() string {
+	switch f {
+	case DataExportFormatCSV:
+		return "csv"
+	case DataExportFormatJSON:
+		return "json"
+	default:
+		return "sql"
+	}
+}
+
 // These payload types are only used when marshalling to the json format for saving into the database.
 // So we annotate with json tag using camelCase naming which is consistent with normal
 // json naming convention
@@ -207,6 +236,30 @@ type TaskDatabaseBackupPayload struct {
 	BackupID int `json:"backupId,omitempty"`
 }
 
+// TaskDatabaseDataExportPayload is the task payload for database data export.
+type TaskDatabaseDataExportPayload struct {
+	// Common fields
+	Skipped       bool   `json:"skipped,omitempty"`
+	SkippedReason string `json:"skippedReason,omitempty"`
+
+	Statement string           `json:"statement,omitempty"`
+	Format    DataExportFormat `json:"format,omitempty"`
+	Limit     int              `json:"limit,omitempty"`
+	TableName string           `json:"tableName,omitempty"`
+
+	// The fields below are set once the data is exported.
+
+	// StorageBackend is the storage backend of the exported file.
+	StorageBackend BackupStorageBackend `json:"storageBackend,omitempty"`
+	// Path is the path of the exported file relative to the data directory or the bucket.
+	Path     string `json:"path,omitempty"`
+	RowCount int    `json:"rowCount,omitempty"`
+	// ExpireTs is the time when the exported file can no longer be downloaded.
+	ExpireTs int64 `json:"expireTs,omitempty"`
+	// Purged is true if the expired exported file has been removed from the storage backend.
+	Purged bool `json:"purged,omitempty"`
+}
+
 // Task is the API message for a task.
 type Task struct {
 	ID int `jsonapi:"primary,task"`
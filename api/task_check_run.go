@@ -35,11 +35,13 @@ const (
 	TaskCheckStatusWarn TaskCheckStatus = "WARN"
 	// TaskCheckStatusError is the task check status for ERROR.
 	TaskCheckStatusError TaskCheckStatus = "ERROR"
+	// TaskCheckStatusSuppressed is the task check status for SUPPRESSED, the warning or error is suppressed by the SQL review suppression comment.
+	TaskCheckStatusSuppressed TaskCheckStatus = "SUPPRESSED"
 )
 
 func (t TaskCheckStatus) level() int {
 	switch t {
-	case TaskCheckStatusSuccess:
+	case TaskCheckStatusSuccess, TaskCheckStatusSuppressed:
 		return 2
 	case TaskCheckStatusWarn:
 		return 1
@@ -79,6 +81,8 @@ const (
 	TaskCheckIssueLGTM TaskCheckType = "bb.task-check.issue.lgtm"
 	// TaskCheckPITRMySQL is the task check type for MySQL PITR.
 	TaskCheckPITRMySQL TaskCheckType = "bb.task-check.pitr.mysql"
+	// TaskCheckPITRPostgres is the task check type for PostgreSQL PITR.
+	TaskCheckPITRPostgres TaskCheckType = "bb.task-check.pitr.postgres"
 )
 
 // TaskCheckEarliestAllowedTimePayload is the task check payload for earliest allowed time.
@@ -206,7 +210,7 @@ type TaskCheckRunStatusPatch struct {
 
 // IsSyntaxCheckSupported checks the engine type if syntax check supports it.
 func IsSyntaxCheckSupported(dbType db.Type) bool {
-	if dbType == db.Postgres || dbType == db.MySQL || dbType == db.TiDB {
+	if dbType == db.Postgres || dbType == db.MySQL || dbType == db.TiDB || dbType == db.MariaDB || dbType == db.OceanBase || dbType == db.Snowflake || dbType == db.ClickHouse {
 		advisorDB, err := advisorDB.ConvertToAdvisorDBType(string(dbType))
 		if err != nil {
 			return false
@@ -220,7 +224,7 @@ func IsSyntaxCheckSupported(dbType db.Type) bool {
 
 // IsSQLReviewSupported checks the engine type if SQL review supports it.
 func IsSQLReviewSupported(dbType db.Type) bool {
-	if dbType == db.Postgres || dbType == db.MySQL || dbType == db.TiDB {
+	if dbType == db.Postgres || dbType == db.MySQL || dbType == db.TiDB || dbType == db.MariaDB || dbType == db.OceanBase || dbType == db.Snowflake || dbType == db.ClickHouse {
 		advisorDB, err := advisorDB.ConvertToAdvisorDBType(string(dbType))
 		if err != nil {
 			return false
@@ -235,7 +239,7 @@ func IsSQLReviewSupported(dbType db.Type) bool {
 // IsStatementTypeCheckSupported checks the engine type if statement type check supports it.
 func IsStatementTypeCheckSupported(dbType db.Type) bool {
 	switch dbType {
-	case db.Postgres, db.TiDB, db.MySQL:
+	case db.Postgres, db.TiDB, db.MySQL, db.MariaDB, db.OceanBase:
 		return true
 	default:
 		return false
@@ -40,5 +40,7 @@ func getBaseProfile() config.Profile {
 		BackupBucket:         flags.backupBucket,
 		BackupCredentialFile: flags.backupCredential,
 		FeishuAPIURL:         feishu.APIPath,
+		SecretEnvPrefix:      flags.secretEnvPrefix,
+		SecretVaultMount:     flags.secretVaultMount,
 	}
 }
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -70,3 +71,23 @@ func TestNormalizeExternalURL(t *testing.T) {
 		})
 	}
 }
+
+func TestParseCredentialCommands(t *testing.T) {
+	got, err := parseCredentialCommands([]string{"prod=/opt/bin/password prod --format=raw", " dev = echo dev "})
+	if err != nil {
+		t.Fatalf("expect no error, got %s", err.Error())
+	}
+	want := map[string]string{
+		"prod": "/opt/bin/password prod --format=raw",
+		"dev":  "echo dev",
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("expect %v, got %v", want, got)
+	}
+
+	for _, invalid := range [][]string{{"prod"}, {"=echo"}, {"prod="}, {"prod=a", "prod=b"}} {
+		if _, err := parseCredentialCommands(invalid); err == nil {
+			t.Errorf("expect error for %v", invalid)
+		}
+	}
+}
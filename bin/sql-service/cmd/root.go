@@ -22,11 +22,17 @@ import (
 	_ "github.com/bytebase/bytebase/plugin/advisor/fake"
 	// Register mysql advisor.
 	_ "github.com/bytebase/bytebase/plugin/advisor/mysql"
+	// Register clickhouse advisor.
+	_ "github.com/bytebase/bytebase/plugin/advisor/clickhouse"
 	// Register postgresql advisor.
 	_ "github.com/bytebase/bytebase/plugin/advisor/pg"
+	// Register snowflake advisor.
+	_ "github.com/bytebase/bytebase/plugin/advisor/snowflake"
 
 	// Register postgres parser driver.
 	_ "github.com/bytebase/bytebase/plugin/parser/engine/pg"
+	// Register snowflake and clickhouse parser driver.
+	_ "github.com/bytebase/bytebase/plugin/parser/engine/standard"
 )
 
 // -----------------------------------Global constant BEGIN----------------------------------------.
@@ -85,8 +85,9 @@ const useExecuteSQL = () => {
       for (const advice of sqlResultSet.adviceList) {
         if (advice.status === "ERROR") {
           adviceStatus = "ERROR";
-        } else if (adviceStatus !== "ERROR") {
-          adviceStatus = advice.status;
+        } else if (advice.status === "WARN" && adviceStatus !== "ERROR") {
+          // The suppressed advices are listed but don't raise the status.
+          adviceStatus = "WARN";
         }
 
         adviceNotifyMessage += `${advice.status}: ${advice.title}\n`;
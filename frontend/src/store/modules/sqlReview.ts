@@ -168,9 +168,16 @@ export const useSQLReviewStore = defineStore("sqlReview", {
         policyUpsert.rowStatus = rowStatus;
       }
       if (name && ruleList) {
+        // The custom rules are managed by the API only, keep them as they are.
+        const customRuleList = targetPolicy.ruleList.filter(
+          (r) => r.type === "custom"
+        );
         const payload: SQLReviewPolicyPayload = {
           name,
-          ruleList: ruleList.map((r) => ({
+          ruleList: [
+            ...ruleList.filter((r) => r.type !== "custom"),
+            ...customRuleList,
+          ].map((r) => ({
             ...r,
             payload: r.payload ? JSON.stringify(r.payload) : "{}",
           })),
@@ -280,7 +280,12 @@ export type TaskCheckDatabaseStatementTypePayload = {
   dbType: string;
 };
 
-export type TaskCheckStatus = "SUCCESS" | "WARN" | "ERROR";
+export type TaskCheckStatus =
+  | "SUCCESS"
+  | "WARN"
+  | "ERROR"
+  // The warning or error suppressed by the SQL review suppression comment.
+  | "SUPPRESSED";
 
 export type TaskCheckNamespace = "bb.advisor" | "bb.core";
 
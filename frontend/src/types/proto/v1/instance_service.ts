@@ -15,6 +15,12 @@ export enum Engine {
   SQLITE = 5,
   TIDB = 6,
   MONGODB = 7,
+  REDIS = 8,
+  ORACLE = 9,
+  SPANNER = 10,
+  MSSQL = 11,
+  MARIADB = 12,
+  OCEANBASE = 13,
   UNRECOGNIZED = -1,
 }
 
@@ -44,6 +50,24 @@ export function engineFromJSON(object: any): Engine {
     case 7:
     case "MONGODB":
       return Engine.MONGODB;
+    case 8:
+    case "REDIS":
+      return Engine.REDIS;
+    case 9:
+    case "ORACLE":
+      return Engine.ORACLE;
+    case 10:
+    case "SPANNER":
+      return Engine.SPANNER;
+    case 11:
+    case "MSSQL":
+      return Engine.MSSQL;
+    case 12:
+    case "MARIADB":
+      return Engine.MARIADB;
+    case 13:
+    case "OCEANBASE":
+      return Engine.OCEANBASE;
     case -1:
     case "UNRECOGNIZED":
     default:
@@ -69,6 +93,18 @@ export function engineToJSON(object: Engine): string {
       return "TIDB";
     case Engine.MONGODB:
       return "MONGODB";
+    case Engine.REDIS:
+      return "REDIS";
+    case Engine.ORACLE:
+      return "ORACLE";
+    case Engine.SPANNER:
+      return "SPANNER";
+    case Engine.MSSQL:
+      return "MSSQL";
+    case Engine.MARIADB:
+      return "MARIADB";
+    case Engine.OCEANBASE:
+      return "OCEANBASE";
     case Engine.UNRECOGNIZED:
     default:
       return "UNRECOGNIZED";
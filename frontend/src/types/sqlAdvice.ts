@@ -2,7 +2,12 @@ import { SQLAdviceCode } from "./sqlAdviceCode";
 
 export * from "./sqlAdviceCode";
 
-export type AdviceStatus = "SUCCESS" | "WARN" | "ERROR";
+export type AdviceStatus =
+  | "SUCCESS"
+  | "WARN"
+  | "ERROR"
+  // The warning or error suppressed by the SQL review suppression comment.
+  | "SUPPRESSED";
 
 export type Advice = {
   status: AdviceStatus;
@@ -9,7 +9,12 @@ import sqlReviewProdTemplate from "./sql-review.prod.yaml";
 import sqlReviewDevTemplate from "./sql-review.dev.yaml";
 
 // The engine type for rule template
-export type SchemaRuleEngineType = "MYSQL" | "POSTGRES" | "TIDB";
+export type SchemaRuleEngineType =
+  | "MYSQL"
+  | "POSTGRES"
+  | "TIDB"
+  | "SNOWFLAKE"
+  | "CLICKHOUSE";
 
 // The category type for rule template
 export type CategoryType =
@@ -92,6 +97,9 @@ export interface RuleConfigComponent {
 // The identifier for rule template
 export type RuleType =
   | "engine.mysql.use-innodb"
+  | "engine.clickhouse.require-order-by"
+  | "engine.clickhouse.on-cluster-consistency"
+  | "engine.clickhouse.disallow-mutation"
   | "table.require-pk"
   | "table.no-foreign-key"
   | "table.drop-naming-convention"
@@ -132,6 +140,7 @@ export type RuleType =
   | "statement.affected-row-limit"
   | "statement.dml-dry-run"
   | "schema.backward-compatibility"
+  | "schema.lock-risk"
   | "database.drop-empty-database"
   | "system.charset.allowlist"
   | "system.collation.allowlist"
@@ -139,7 +148,8 @@ export type RuleType =
   | "index.type-no-blob"
   | "index.key-number-limit"
   | "index.total-number-limit"
-  | "index.pk-type-limit";
+  | "index.pk-type-limit"
+  | "custom";
 
 export const availableRulesForFreePlan: RuleType[] = [
   "statement.where.require",
@@ -172,6 +182,15 @@ interface NumberLimitPayload {
   number: number;
 }
 
+// The payload for the user-defined rule.
+// The expression matches the violating statements, see CustomRulePayload in the backend.
+export interface CustomRulePayload {
+  title: string;
+  code: number;
+  expression: string;
+  message?: string;
+}
+
 // The SchemaPolicyRule stores the rule configuration by users.
 // Used by the backend
 export interface SchemaPolicyRule {
@@ -181,7 +200,8 @@ export interface SchemaPolicyRule {
     | NamingFormatPayload
     | StringArrayLimitPayload
     | CommentFormatPayload
-    | NumberLimitPayload;
+    | NumberLimitPayload
+    | CustomRulePayload;
 }
 
 // The API for SQL review policy in backend.
@@ -25,6 +25,8 @@ const (
 	Warn Status = "WARN"
 	// Error is the advisor status for errors.
 	Error Status = "ERROR"
+	// Suppressed is the advisor status for the warnings and errors suppressed by the suppression comments.
+	Suppressed Status = "SUPPRESSED"
 
 	// SyntaxErrorTitle is the error title for syntax error.
 	SyntaxErrorTitle string = "Syntax error"
@@ -198,6 +200,12 @@ const (
 	// MySQLStatementDMLDryRun is an advisor type for MySQL DML dry run.
 	MySQLStatementDMLDryRun Type = "bb.plugin.advisor.mysql.statement.dml-dry-run"
 
+	// MySQLLockRisk is an advisor type for MySQL DDL taking blocking locks on large tables.
+	MySQLLockRisk Type = "bb.plugin.advisor.mysql.schema.lock-risk"
+
+	// MySQLCustomRule is an advisor type for MySQL user-defined rules.
+	MySQLCustomRule Type = "bb.plugin.advisor.mysql.custom"
+
 	// PostgreSQL Advisor.
 
 	// PostgreSQLSyntax is an advisor type for PostgreSQL syntax.
@@ -265,6 +273,73 @@ const (
 
 	// PostgreSQLColumnDisallowChangingType is an advisor type for PostgreSQL disallow changing column type.
 	PostgreSQLColumnDisallowChangingType Type = "bb.plugin.advisor.postgresql.column.disallow-changing-type"
+
+	// PostgreSQLLockRisk is an advisor type for PostgreSQL DDL taking blocking locks on large tables.
+	PostgreSQLLockRisk Type = "bb.plugin.advisor.postgresql.schema.lock-risk"
+
+	// PostgreSQLCustomRule is an advisor type for PostgreSQL user-defined rules.
+	PostgreSQLCustomRule Type = "bb.plugin.advisor.postgresql.custom"
+
+	// Snowflake Advisor.
+
+	// SnowflakeSyntax is an advisor type for Snowflake syntax.
+	SnowflakeSyntax Type = "bb.plugin.advisor.snowflake.syntax"
+
+	// SnowflakeNamingTableConvention is an advisor type for Snowflake table naming convention.
+	SnowflakeNamingTableConvention Type = "bb.plugin.advisor.snowflake.naming.table"
+
+	// SnowflakeNamingColumnConvention is an advisor type for Snowflake column naming convention.
+	SnowflakeNamingColumnConvention Type = "bb.plugin.advisor.snowflake.naming.column"
+
+	// SnowflakeWhereRequirement is an advisor type for Snowflake WHERE clause requirement.
+	SnowflakeWhereRequirement Type = "bb.plugin.advisor.snowflake.where.require"
+
+	// SnowflakeNoSelectAll is an advisor type for Snowflake no select all.
+	SnowflakeNoSelectAll Type = "bb.plugin.advisor.snowflake.select.no-select-all"
+
+	// SnowflakeTableCommentConvention is an advisor type for Snowflake table comment convention.
+	SnowflakeTableCommentConvention Type = "bb.plugin.advisor.snowflake.table.comment"
+
+	// SnowflakeColumnCommentConvention is an advisor type for Snowflake column comment convention.
+	SnowflakeColumnCommentConvention Type = "bb.plugin.advisor.snowflake.column.comment"
+
+	// SnowflakeCustomRule is an advisor type for Snowflake user-defined rules.
+	SnowflakeCustomRule Type = "bb.plugin.advisor.snowflake.custom"
+
+	// ClickHouse Advisor.
+
+	// ClickHouseSyntax is an advisor type for ClickHouse syntax.
+	ClickHouseSyntax Type = "bb.plugin.advisor.clickhouse.syntax"
+
+	// ClickHouseNamingTableConvention is an advisor type for ClickHouse table naming convention.
+	ClickHouseNamingTableConvention Type = "bb.plugin.advisor.clickhouse.naming.table"
+
+	// ClickHouseNamingColumnConvention is an advisor type for ClickHouse column naming convention.
+	ClickHouseNamingColumnConvention Type = "bb.plugin.advisor.clickhouse.naming.column"
+
+	// ClickHouseWhereRequirement is an advisor type for ClickHouse WHERE clause requirement.
+	ClickHouseWhereRequirement Type = "bb.plugin.advisor.clickhouse.where.require"
+
+	// ClickHouseNoSelectAll is an advisor type for ClickHouse no select all.
+	ClickHouseNoSelectAll Type = "bb.plugin.advisor.clickhouse.select.no-select-all"
+
+	// ClickHouseTableCommentConvention is an advisor type for ClickHouse table comment convention.
+	ClickHouseTableCommentConvention Type = "bb.plugin.advisor.clickhouse.table.comment"
+
+	// ClickHouseColumnCommentConvention is an advisor type for ClickHouse column comment convention.
+	ClickHouseColumnCommentConvention Type = "bb.plugin.advisor.clickhouse.column.comment"
+
+	// ClickHouseRequireOrderBy is an advisor type for ClickHouse MergeTree family tables requiring the ORDER BY key.
+	ClickHouseRequireOrderBy Type = "bb.plugin.advisor.clickhouse.engine.require-order-by"
+
+	// ClickHouseOnClusterConsistency is an advisor type for ClickHouse ON CLUSTER consistency.
+	ClickHouseOnClusterConsistency Type = "bb.plugin.advisor.clickhouse.engine.on-cluster-consistency"
+
+	// ClickHouseDisallowMutation is an advisor type for ClickHouse disallow mutation.
+	ClickHouseDisallowMutation Type = "bb.plugin.advisor.clickhouse.engine.disallow-mutation"
+
+	// ClickHouseCustomRule is an advisor type for ClickHouse user-defined rules.
+	ClickHouseCustomRule Type = "bb.plugin.advisor.clickhouse.custom"
 )
 
 // Advice is the result of an advisor.
@@ -371,7 +446,7 @@ func Check(dbType db.Type, advType Type, ctx Context, statement string) (adviceL
 // IsSyntaxCheckSupported checks the engine type if syntax check supports it.
 func IsSyntaxCheckSupported(dbType db.Type) bool {
 	switch dbType {
-	case db.MySQL, db.TiDB, db.Postgres:
+	case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase, db.Postgres, db.Snowflake, db.ClickHouse:
 		return true
 	}
 	return false
@@ -380,7 +455,7 @@ func IsSyntaxCheckSupported(dbType db.Type) bool {
 // IsSQLReviewSupported checks the engine type if SQL review supports it.
 func IsSQLReviewSupported(dbType db.Type) bool {
 	switch dbType {
-	case db.MySQL, db.TiDB, db.Postgres:
+	case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase, db.Postgres, db.Snowflake, db.ClickHouse:
 		return true
 	}
 	return false
@@ -0,0 +1,851 @@
+package catalog
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/plugin/parser"
+	"github.com/bytebase/bytebase/plugin/parser/ast"
+)
+
+const (
+	// pgPublicSchemaName is the default schema for PostgreSQL.
+	pgPublicSchemaName = "public"
+	// pgExpressionKeyName is the name PostgreSQL uses for the expression keys when it generates the index name.
+	pgExpressionKeyName = "expr"
+	// pgSearchPathName is the run-time parameter of the schema search path.
+	pgSearchPathName = "search_path"
+	// pgUserSchemaName is the search path item referring to the schema with the same name as the current user.
+	pgUserSchemaName = "$user"
+)
+
+// pgDefaultSearchPath is the default schema search path of PostgreSQL.
+var pgDefaultSearchPath = []string{pgUserSchemaName, pgPublicSchemaName}
+
+func (d *DatabaseState) pgWalkThrough(stmts string) error {
+	// PostgreSQL always has the public schema.
+	// If there is no public schema, create it to avoid corner cases.
+	if _, exists := d.schemaSet[pgPublicSchemaName]; !exists {
+		d.createSchema(pgPublicSchemaName)
+	}
+
+	nodeList, err := pgParse(stmts)
+	if err != nil {
+		return err
+	}
+
+	for _, node := range nodeList {
+		if node == nil {
+			continue
+		}
+		// change state
+		if err := d.pgChangeState(node); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func pgParse(stmts string) ([]ast.Node, *WalkThroughError) {
+	nodeList, err := parser.Parse(parser.Postgres, parser.ParseContext{}, stmts)
+	if err != nil {
+		return nil, NewParseError(err.Error())
+	}
+	return nodeList, nil
+}
+
+func (d *DatabaseState) pgChangeState(in ast.Node) (err *WalkThroughError) {
+	defer func() {
+		if err == nil {
+			return
+		}
+		if err.Line == 0 {
+			err.Line = in.LastLine()
+		}
+	}()
+	if d.deleted {
+		return &WalkThroughError{
+			Type:    ErrorTypeDatabaseIsDeleted,
+			Content: fmt.Sprintf("Database `%s` is deleted", d.name),
+		}
+	}
+	switch node := in.(type) {
+	case *ast.CreateSchemaStmt:
+		return d.pgCreateSchema(node)
+	case *ast.DropSchemaStmt:
+		return d.pgDropSchema(node)
+	case *ast.CreateTableStmt:
+		return d.pgCreateTable(node, d.pgCreationSchemaName(node.Name.Schema))
+	case *ast.DropTableStmt:
+		return d.pgDropTable(node)
+	case *ast.AlterTableStmt:
+		return d.pgAlterTable(node)
+	case *ast.CreateIndexStmt:
+		return d.pgCreateIndex(node)
+	case *ast.DropIndexStmt:
+		return d.pgDropIndex(node)
+	case *ast.RenameIndexStmt:
+		return d.pgRenameIndex(node)
+	case *ast.CreateSequenceStmt:
+		return d.pgCreateSequence(node)
+	case *ast.DropSequenceStmt:
+		return d.pgDropSequence(node)
+	case *ast.DropDatabaseStmt:
+		return d.pgDropDatabase(node)
+	case *ast.VariableSetStmt:
+		if strings.EqualFold(node.Name, pgSearchPathName) {
+			d.searchPath = node.ValueList
+		}
+		return nil
+	default:
+		return nil
+	}
+}
+
+// pgSearchPath returns the schemas to look up the unqualified names in order, and whether the search path is resolved.
+// The "$user" item refers to the schema named after the current user, which we don't know. Besides, the default
+// search path can be changed by ALTER DATABASE or ALTER ROLE. So the search path containing "$user" is resolved only
+// if the database has no schema out of it, otherwise the unqualified names may refer to the schemas we cannot tell.
+func (d *DatabaseState) pgSearchPath() ([]string, bool) {
+	searchPath := d.searchPath
+	if len(searchPath) == 0 {
+		searchPath = pgDefaultSearchPath
+	}
+
+	var schemaList []string
+	hasUserSchema := false
+	for _, name := range searchPath {
+		if name == pgUserSchemaName {
+			hasUserSchema = true
+			continue
+		}
+		schemaList = append(schemaList, name)
+	}
+	if !hasUserSchema {
+		return schemaList, true
+	}
+	for name := range d.schemaSet {
+		if !containsString(schemaList, name) {
+			return schemaList, false
+		}
+	}
+	return schemaList, true
+}
+
+// pgCreationSchemaName returns the schema to create the object in.
+// The unqualified object is created in the first existing schema in the search path.
+func (d *DatabaseState) pgCreationSchemaName(name string) string {
+	if name != "" {
+		return name
+	}
+	schemaList, _ := d.pgSearchPath()
+	for _, schemaName := range schemaList {
+		if _, exists := d.schemaSet[schemaName]; exists {
+			return schemaName
+		}
+	}
+	if len(schemaList) > 0 {
+		return schemaList[0]
+	}
+	return pgPublicSchemaName
+}
+
+// pgLookupSchemaName returns the schema containing the object, and whether the schema is resolved.
+// The unqualified object is looked up in the schemas in the search path, if not found, it returns the creation schema.
+// The caller should not report the object does not exist if the schema is not resolved.
+func (d *DatabaseState) pgLookupSchemaName(name string, contains func(*SchemaState) bool) (string, bool) {
+	if name != "" {
+		return name, true
+	}
+	schemaList, resolved := d.pgSearchPath()
+	for _, schemaName := range schemaList {
+		if schema, exists := d.schemaSet[schemaName]; exists && contains(schema) {
+			return schemaName, true
+		}
+	}
+	return d.pgCreationSchemaName(name), resolved
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *DatabaseState) pgDropDatabase(node *ast.DropDatabaseStmt) *WalkThroughError {
+	if node.DatabaseName != d.name {
+		return NewAccessOtherDatabaseError(d.name, node.DatabaseName)
+	}
+
+	d.deleted = true
+	return nil
+}
+
+func (d *DatabaseState) pgCreateSchema(node *ast.CreateSchemaStmt) *WalkThroughError {
+	if _, exists := d.schemaSet[node.Name]; exists {
+		if node.IfNotExists {
+			return nil
+		}
+		return &WalkThroughError{
+			Type:    ErrorTypeSchemaExists,
+			Content: fmt.Sprintf("Schema `%s` already exists", node.Name),
+		}
+	}
+
+	d.createSchema(node.Name)
+	// The objects in the schema element list are created in the new schema by default.
+	for _, element := range node.SchemaElementList {
+		if table, ok := element.(*ast.CreateTableStmt); ok {
+			schemaName := table.Name.Schema
+			if schemaName == "" {
+				schemaName = node.Name
+			}
+			if err := d.pgCreateTable(table, schemaName); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (d *DatabaseState) pgDropSchema(node *ast.DropSchemaStmt) *WalkThroughError {
+	for _, name := range node.SchemaList {
+		if _, exists := d.schemaSet[name]; !exists {
+			if node.IfExists || !d.ctx.CheckIntegrity {
+				continue
+			}
+			return NewSchemaNotExistsError(name)
+		}
+		delete(d.schemaSet, name)
+	}
+	return nil
+}
+
+// pgFindSchemaState finds the schema, it creates an incomplete schema if the schema does not exist and we don't check integrity.
+func (d *DatabaseState) pgFindSchemaState(name string) (*SchemaState, *WalkThroughError) {
+	schema, exists := d.schemaSet[name]
+	if !exists {
+		if d.ctx.CheckIntegrity {
+			return nil, NewSchemaNotExistsError(name)
+		}
+		schema = d.createSchema(name)
+	}
+	return schema, nil
+}
+
+func (d *DatabaseState) pgFindTableState(tableDef *ast.TableDef, createIncompleteTable bool) (*SchemaState, *TableState, *WalkThroughError) {
+	if tableDef.Database != "" && tableDef.Database != d.name {
+		return nil, nil, NewAccessOtherDatabaseError(d.name, tableDef.Database)
+	}
+
+	schemaName, resolved := d.pgLookupSchemaName(tableDef.Schema, func(schema *SchemaState) bool {
+		_, exists := schema.tableSet[tableDef.Name]
+		return exists
+	})
+	schema, exists := d.schemaSet[schemaName]
+	if !exists {
+		if d.ctx.CheckIntegrity && resolved {
+			return nil, nil, NewSchemaNotExistsError(schemaName)
+		}
+		schema = d.createSchema(schemaName)
+	}
+
+	table, exists := schema.tableSet[tableDef.Name]
+	if !exists {
+		if schema.ctx.CheckIntegrity && resolved {
+			return nil, nil, NewTableNotExistsError(tableDef.Name)
+		}
+		if !createIncompleteTable {
+			return schema, nil, nil
+		}
+		table = schema.createIncompleteTable(tableDef.Name)
+		table.incomplete = true
+	}
+
+	return schema, table, nil
+}
+
+func (d *DatabaseState) pgCreateTable(node *ast.CreateTableStmt, schemaName string) *WalkThroughError {
+	if node.Name.Database != "" && node.Name.Database != d.name {
+		return NewAccessOtherDatabaseError(d.name, node.Name.Database)
+	}
+
+	schema, err := d.pgFindSchemaState(schemaName)
+	if err != nil {
+		return err
+	}
+
+	if _, exists := schema.tableSet[node.Name.Name]; exists {
+		if node.IfNotExists {
+			return nil
+		}
+		return NewTableExistsError(node.Name.Name)
+	}
+
+	table := &TableState{
+		name:      node.Name.Name,
+		comment:   newEmptyStringPointer(),
+		columnSet: make(columnStateMap),
+		indexSet:  make(indexStateMap),
+	}
+	schema.tableSet[table.name] = table
+
+	for _, column := range node.ColumnList {
+		if err := schema.pgCreateColumn(table, column); err != nil {
+			err.Line = column.LastLine()
+			return err
+		}
+	}
+
+	for _, constraint := range node.ConstraintList {
+		if err := schema.pgCreateConstraint(table, constraint); err != nil {
+			err.Line = constraint.LastLine()
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *DatabaseState) pgDropTable(node *ast.DropTableStmt) *WalkThroughError {
+	for _, tableDef := range node.TableList {
+		// TODO: deal with DROP VIEW statement.
+		if tableDef.Type == ast.TableTypeView {
+			continue
+		}
+		if tableDef.Database != "" && tableDef.Database != d.name {
+			return NewAccessOtherDatabaseError(d.name, tableDef.Database)
+		}
+
+		schemaName, resolved := d.pgLookupSchemaName(tableDef.Schema, func(schema *SchemaState) bool {
+			_, exists := schema.tableSet[tableDef.Name]
+			return exists
+		})
+		schema, exists := d.schemaSet[schemaName]
+		if !exists {
+			if node.IfExists || !d.ctx.CheckIntegrity || !resolved {
+				continue
+			}
+			return NewSchemaNotExistsError(schemaName)
+		}
+
+		if _, exists := schema.tableSet[tableDef.Name]; !exists {
+			if node.IfExists || !d.ctx.CheckIntegrity || !resolved {
+				continue
+			}
+			return NewTableNotExistsError(tableDef.Name)
+		}
+
+		delete(schema.tableSet, tableDef.Name)
+	}
+	return nil
+}
+
+func (d *DatabaseState) pgAlterTable(node *ast.AlterTableStmt) *WalkThroughError {
+	// TODO: deal with ALTER VIEW statement.
+	if node.Table.Type == ast.TableTypeView {
+		return nil
+	}
+
+	schema, table, err := d.pgFindTableState(node.Table, true /* createIncompleteTable */)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range node.AlterItemList {
+		switch cmd := item.(type) {
+		case *ast.AddColumnListStmt:
+			for _, column := range cmd.ColumnList {
+				if err := schema.pgCreateColumn(table, column); err != nil {
+					return err
+				}
+			}
+		case *ast.DropColumnStmt:
+			if err := table.pgDropColumn(d.ctx, cmd.ColumnName); err != nil {
+				return err
+			}
+		case *ast.AddConstraintStmt:
+			if err := schema.pgCreateConstraint(table, cmd.Constraint); err != nil {
+				return err
+			}
+		case *ast.DropConstraintStmt:
+			// We only collect the constraints implemented by indexes, e.g. PRIMARY KEY and UNIQUE.
+			// So we don't know whether the other constraints exist, just ignore them.
+			delete(table.indexSet, cmd.ConstraintName)
+		case *ast.RenameConstraintStmt:
+			if err := schema.pgRenameConstraint(table, cmd.ConstraintName, cmd.NewName); err != nil {
+				return err
+			}
+		case *ast.SetNotNullStmt:
+			column, err := table.pgFindColumnState(d.ctx, cmd.ColumnName)
+			if err != nil {
+				return err
+			}
+			column.nullable = newFalsePointer()
+		case *ast.DropNotNullStmt:
+			column, err := table.pgFindColumnState(d.ctx, cmd.ColumnName)
+			if err != nil {
+				return err
+			}
+			column.nullable = newTruePointer()
+		case *ast.SetDefaultStmt:
+			column, err := table.pgFindColumnState(d.ctx, cmd.ColumnName)
+			if err != nil {
+				return err
+			}
+			column.defaultValue = newStringPointer(cmd.Expression.Text())
+		case *ast.DropDefaultStmt:
+			column, err := table.pgFindColumnState(d.ctx, cmd.ColumnName)
+			if err != nil {
+				return err
+			}
+			column.defaultValue = nil
+		case *ast.AlterColumnTypeStmt:
+			column, err := table.pgFindColumnState(d.ctx, cmd.ColumnName)
+			if err != nil {
+				return err
+			}
+			columnType, err := pgDeparseDataType(cmd.Type)
+			if err != nil {
+				return err
+			}
+			column.columnType = newStringPointer(columnType)
+		case *ast.RenameColumnStmt:
+			if err := table.renameColumn(d.ctx, cmd.ColumnName, cmd.NewName); err != nil {
+				return err
+			}
+		case *ast.RenameTableStmt:
+			if err := schema.renameTable(d.ctx, table.name, cmd.NewName); err != nil {
+				return err
+			}
+		case *ast.SetSchemaStmt:
+			if err := d.pgMoveTable(schema, table, cmd.NewSchema); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (d *DatabaseState) pgMoveTable(schema *SchemaState, table *TableState, newSchemaName string) *WalkThroughError {
+	if schema.name == newSchemaName {
+		return nil
+	}
+
+	newSchema, err := d.pgFindSchemaState(newSchemaName)
+	if err != nil {
+		return err
+	}
+	if _, exists := newSchema.tableSet[table.name]; exists {
+		return NewTableExistsError(table.name)
+	}
+	// The indexes are moved to the new schema along with the table.
+	for _, index := range table.indexSet {
+		if tableName, _ := newSchema.pgFindIndex(index.name); tableName != "" {
+			return NewIndexExistsError(table.name, index.name)
+		}
+	}
+
+	delete(schema.tableSet, table.name)
+	newSchema.tableSet[table.name] = table
+	return nil
+}
+
+func (t *TableState) pgFindColumnState(ctx *FinderContext, columnName string) (*ColumnState, *WalkThroughError) {
+	column, exists := t.columnSet[columnName]
+	if !exists {
+		if t.checkIntegrity(ctx) {
+			return nil, NewColumnNotExistsError(t.name, columnName)
+		}
+		column = t.createIncompleteColumn(columnName)
+	}
+	return column, nil
+}
+
+func (s *SchemaState) pgCreateColumn(t *TableState, column *ast.ColumnDef) *WalkThroughError {
+	if _, exists := t.columnSet[column.ColumnName]; exists {
+		return &WalkThroughError{
+			Type:    ErrorTypeColumnExists,
+			Content: fmt.Sprintf("Column `%s` already exists in table `%s`", column.ColumnName, t.name),
+		}
+	}
+
+	columnType, err := pgDeparseDataType(column.Type)
+	if err != nil {
+		return err
+	}
+
+	// PostgreSQL never reuses the position of the dropped columns.
+	position := 0
+	for _, col := range t.columnSet {
+		if col.position != nil && *col.position > position {
+			position = *col.position
+		}
+	}
+	position++
+
+	col := &ColumnState{
+		name:       column.ColumnName,
+		position:   &position,
+		nullable:   newTruePointer(),
+		columnType: newStringPointer(columnType),
+		collation:  newEmptyStringPointer(),
+		comment:    newEmptyStringPointer(),
+	}
+	t.columnSet[col.name] = col
+
+	for _, constraint := range column.ConstraintList {
+		switch constraint.Type {
+		case ast.ConstraintTypeNotNull:
+			col.nullable = newFalsePointer()
+		case ast.ConstraintTypeDefault:
+			col.defaultValue = newStringPointer(constraint.Expression.Text())
+		default:
+			if err := s.pgCreateConstraint(t, constraint); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func pgDeparseDataType(tp ast.DataType) (string, *WalkThroughError) {
+	text, err := parser.Deparse(parser.Postgres, parser.DeparseContext{}, tp)
+	if err != nil {
+		return "", &WalkThroughError{
+			Type:    ErrorTypeRestoreError,
+			Content: err.Error(),
+		}
+	}
+	return text, nil
+}
+
+func (t *TableState) pgDropColumn(ctx *FinderContext, columnName string) *WalkThroughError {
+	if _, exists := t.columnSet[columnName]; !exists && t.checkIntegrity(ctx) {
+		return NewColumnNotExistsError(t.name, columnName)
+	}
+
+	// Unlike MySQL, PostgreSQL drops the whole index if the index involves the dropped column.
+	for _, index := range t.indexSet {
+		for _, key := range index.expressionList {
+			if key == columnName {
+				delete(t.indexSet, index.name)
+				break
+			}
+		}
+	}
+
+	delete(t.columnSet, columnName)
+	return nil
+}
+
+func (s *SchemaState) pgCreateConstraint(t *TableState, constraint *ast.ConstraintDef) *WalkThroughError {
+	switch constraint.Type {
+	case ast.ConstraintTypePrimary:
+		if err := t.pgValidateKeyList(s.ctx, constraint.KeyList, true /* primary */); err != nil {
+			return err
+		}
+		name := constraint.Name
+		if name == "" {
+			name = s.pgGenerateIndexName(t.name, nil /* keyList */, "pkey")
+		}
+		return s.pgCreateIndex(t, name, constraint.KeyList, true /* unique */, true /* primary */, ast.IndexMethodTypeBTree)
+	case ast.ConstraintTypeUnique:
+		if err := t.pgValidateKeyList(s.ctx, constraint.KeyList, false /* primary */); err != nil {
+			return err
+		}
+		name := constraint.Name
+		if name == "" {
+			name = s.pgGenerateIndexName(t.name, constraint.KeyList, "key")
+		}
+		return s.pgCreateIndex(t, name, constraint.KeyList, true /* unique */, false /* primary */, ast.IndexMethodTypeBTree)
+	case ast.ConstraintTypePrimaryUsingIndex, ast.ConstraintTypeUniqueUsingIndex:
+		return s.pgCreateConstraintUsingIndex(t, constraint)
+	default:
+		// We don't collect the other constraints, such as FOREIGN KEY and CHECK.
+		return nil
+	}
+}
+
+// pgCreateConstraintUsingIndex converts an existing unique index into the PRIMARY KEY or UNIQUE constraint.
+func (s *SchemaState) pgCreateConstraintUsingIndex(t *TableState, constraint *ast.ConstraintDef) *WalkThroughError {
+	primary := constraint.Type == ast.ConstraintTypePrimaryUsingIndex
+	index, exists := t.indexSet[constraint.IndexName]
+	if !exists {
+		if t.checkIntegrity(s.ctx) {
+			return NewIndexNotExistsError(t.name, constraint.IndexName)
+		}
+		index = t.createIncompleteIndex(constraint.IndexName)
+	}
+	if primary {
+		if err := t.pgCheckPrimaryKeyNotExists(); err != nil {
+			return err
+		}
+		index.primary = newTruePointer()
+		if err := t.pgValidateKeyList(s.ctx, index.expressionList, true /* primary */); err != nil {
+			return err
+		}
+	}
+	index.unique = newTruePointer()
+
+	// The index is renamed to the constraint name if the constraint name is specified.
+	if constraint.Name != "" {
+		return s.pgRenameConstraint(t, index.name, constraint.Name)
+	}
+	return nil
+}
+
+func (s *SchemaState) pgRenameConstraint(t *TableState, oldName string, newName string) *WalkThroughError {
+	// We only collect the constraints implemented by indexes, e.g. PRIMARY KEY and UNIQUE.
+	// So we don't know whether the other constraints exist, just ignore them.
+	index, exists := t.indexSet[oldName]
+	if !exists || oldName == newName {
+		return nil
+	}
+	if tableName, _ := s.pgFindIndex(newName); tableName != "" {
+		return NewIndexExistsError(tableName, newName)
+	}
+
+	index.name = newName
+	delete(t.indexSet, oldName)
+	t.indexSet[newName] = index
+	return nil
+}
+
+// pgValidateKeyList checks the columns exist, and the columns in primary key are NOT NULL.
+func (t *TableState) pgValidateKeyList(ctx *FinderContext, keyList []string, primary bool) *WalkThroughError {
+	for _, key := range keyList {
+		column, exists := t.columnSet[key]
+		if !exists {
+			if t.checkIntegrity(ctx) {
+				return NewColumnNotExistsError(t.name, key)
+			}
+			continue
+		}
+		if primary {
+			column.nullable = newFalsePointer()
+		}
+	}
+	return nil
+}
+
+func (t *TableState) pgCheckPrimaryKeyNotExists() *WalkThroughError {
+	for _, index := range t.indexSet {
+		if index.Primary() {
+			return &WalkThroughError{
+				Type:    ErrorTypePrimaryKeyExists,
+				Content: fmt.Sprintf("Primary key exists in table `%s`", t.name),
+			}
+		}
+	}
+	return nil
+}
+
+func (s *SchemaState) pgCreateIndex(t *TableState, name string, keyList []string, unique bool, primary bool, method ast.IndexMethodType) *WalkThroughError {
+	if len(keyList) == 0 {
+		return &WalkThroughError{
+			Type:    ErrorTypeIndexEmptyKeys,
+			Content: fmt.Sprintf("Index `%s` in table `%s` has empty key", name, t.name),
+		}
+	}
+	if primary {
+		if err := t.pgCheckPrimaryKeyNotExists(); err != nil {
+			return err
+		}
+	}
+	// In PostgreSQL, the index name is unique in a schema, not a table.
+	if tableName, _ := s.pgFindIndex(name); tableName != "" {
+		return NewIndexExistsError(tableName, name)
+	}
+
+	t.indexSet[name] = &IndexState{
+		name:           name,
+		expressionList: copyStringSlice(keyList),
+		indexType:      newStringPointer(pgIndexMethodName(method)),
+		unique:         newBoolPointer(unique),
+		primary:        newBoolPointer(primary),
+		comment:        newEmptyStringPointer(),
+	}
+	return nil
+}
+
+// pgFindIndex finds the index in the schema, and returns the table name and the index.
+func (s *SchemaState) pgFindIndex(name string) (string, *IndexState) {
+	for _, table := range s.tableSet {
+		if index, exists := table.indexSet[name]; exists {
+			return table.name, index
+		}
+	}
+	return "", nil
+}
+
+// pgGenerateIndexName generates the index name in the same way as PostgreSQL.
+// It's "table_column1_column2_suffix", and PostgreSQL appends a number if the name exists.
+func (s *SchemaState) pgGenerateIndexName(tableName string, keyList []string, suffix string) string {
+	base := strings.Join(append(append([]string{tableName}, keyList...), suffix), "_")
+	name := base
+	for i := 1; ; i++ {
+		if tableName, _ := s.pgFindIndex(name); tableName == "" {
+			return name
+		}
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+}
+
+func pgIndexMethodName(method ast.IndexMethodType) string {
+	switch method {
+	case ast.IndexMethodTypeHash:
+		return "hash"
+	case ast.IndexMethodTypeGiST:
+		return "gist"
+	case ast.IndexMethodTypeSpGiST:
+		return "spgist"
+	case ast.IndexMethodTypeGin:
+		return "gin"
+	case ast.IndexMethodTypeBrin:
+		return "brin"
+	default:
+		return "btree"
+	}
+}
+
+func (d *DatabaseState) pgCreateIndex(node *ast.CreateIndexStmt) *WalkThroughError {
+	schema, table, err := d.pgFindTableState(node.Index.Table, true /* createIncompleteTable */)
+	if err != nil {
+		return err
+	}
+
+	var keyList []string
+	var nameList []string
+	for _, key := range node.Index.KeyList {
+		switch key.Type {
+		case ast.IndexKeyTypeColumn:
+			if _, exists := table.columnSet[key.Key]; !exists && table.checkIntegrity(d.ctx) {
+				return NewColumnNotExistsError(table.name, key.Key)
+			}
+			nameList = append(nameList, key.Key)
+		case ast.IndexKeyTypeExpression:
+			nameList = append(nameList, pgExpressionKeyName)
+		}
+		keyList = append(keyList, key.Key)
+	}
+
+	name := node.Index.Name
+	if name == "" {
+		name = schema.pgGenerateIndexName(table.name, nameList, "idx")
+	} else if tableName, _ := schema.pgFindIndex(name); tableName != "" && node.IfNotExists {
+		return nil
+	}
+
+	return schema.pgCreateIndex(table, name, keyList, node.Index.Unique, false /* primary */, node.Index.Method)
+}
+
+func (d *DatabaseState) pgDropIndex(node *ast.DropIndexStmt) *WalkThroughError {
+	for _, indexDef := range node.IndexList {
+		qualifiedSchemaName := ""
+		if indexDef.Table != nil {
+			qualifiedSchemaName = indexDef.Table.Schema
+		}
+		schemaName, resolved := d.pgLookupSchemaName(qualifiedSchemaName, func(schema *SchemaState) bool {
+			tableName, _ := schema.pgFindIndex(indexDef.Name)
+			return tableName != ""
+		})
+		schema, exists := d.schemaSet[schemaName]
+		if !exists {
+			if node.IfExists || !d.ctx.CheckIntegrity || !resolved {
+				continue
+			}
+			return NewSchemaNotExistsError(schemaName)
+		}
+
+		tableName, _ := schema.pgFindIndex(indexDef.Name)
+		if tableName == "" {
+			if node.IfExists || !d.ctx.CheckIntegrity || !resolved {
+				continue
+			}
+			return &WalkThroughError{
+				Type:    ErrorTypeIndexNotExists,
+				Content: fmt.Sprintf("Index `%s` does not exist in schema `%s`", indexDef.Name, schemaName),
+			}
+		}
+
+		delete(schema.tableSet[tableName].indexSet, indexDef.Name)
+	}
+	return nil
+}
+
+func (d *DatabaseState) pgRenameIndex(node *ast.RenameIndexStmt) *WalkThroughError {
+	schemaName, resolved := d.pgLookupSchemaName(node.Table.Schema, func(schema *SchemaState) bool {
+		tableName, _ := schema.pgFindIndex(node.IndexName)
+		return tableName != ""
+	})
+	schema, exists := d.schemaSet[schemaName]
+	if !exists {
+		if d.ctx.CheckIntegrity && resolved {
+			return NewSchemaNotExistsError(schemaName)
+		}
+		schema = d.createSchema(schemaName)
+	}
+
+	tableName, _ := schema.pgFindIndex(node.IndexName)
+	if tableName == "" {
+		if d.ctx.CheckIntegrity && resolved {
+			return &WalkThroughError{
+				Type:    ErrorTypeIndexNotExists,
+				Content: fmt.Sprintf("Index `%s` does not exist in schema `%s`", node.IndexName, schemaName),
+			}
+		}
+		// We don't know which table the index belongs to, so we cannot create the incomplete index.
+		return nil
+	}
+
+	return schema.pgRenameConstraint(schema.tableSet[tableName], node.IndexName, node.NewName)
+}
+
+func (d *DatabaseState) pgCreateSequence(node *ast.CreateSequenceStmt) *WalkThroughError {
+	sequenceName := node.SequenceDef.SequenceName
+	schema, err := d.pgFindSchemaState(d.pgCreationSchemaName(sequenceName.Schema))
+	if err != nil {
+		return err
+	}
+
+	if _, exists := schema.sequenceSet[sequenceName.Name]; exists {
+		if node.IfNotExists {
+			return nil
+		}
+		return &WalkThroughError{
+			Type:    ErrorTypeSequenceExists,
+			Content: fmt.Sprintf("Sequence `%s` already exists in schema `%s`", sequenceName.Name, schema.name),
+		}
+	}
+
+	schema.sequenceSet[sequenceName.Name] = &SequenceState{
+		name: sequenceName.Name,
+	}
+	return nil
+}
+
+func (d *DatabaseState) pgDropSequence(node *ast.DropSequenceStmt) *WalkThroughError {
+	for _, sequenceName := range node.SequenceNameList {
+		schemaName, resolved := d.pgLookupSchemaName(sequenceName.Schema, func(schema *SchemaState) bool {
+			_, exists := schema.sequenceSet[sequenceName.Name]
+			return exists
+		})
+		schema, exists := d.schemaSet[schemaName]
+		if !exists {
+			if node.IfExists || !d.ctx.CheckIntegrity || !resolved {
+				continue
+			}
+			return NewSchemaNotExistsError(schemaName)
+		}
+		// The sequences in the database metadata are unknown, so we cannot tell whether the sequence exists.
+		delete(schema.sequenceSet, sequenceName.Name)
+	}
+	return nil
+}
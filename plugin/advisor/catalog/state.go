@@ -5,6 +5,7 @@ package catalog
 //   2. the underlying implementation of Finder
 
 import (
+	"sort"
 	"strings"
 
 	"github.com/bytebase/bytebase/plugin/advisor/db"
@@ -30,10 +31,11 @@ func newDatabaseState(d *storepb.DatabaseMetadata, context *FinderContext) *Data
 
 func newSchemaState(s *storepb.SchemaMetadata, context *FinderContext) *SchemaState {
 	schema := &SchemaState{
-		ctx:      context.Copy(),
-		name:     s.Name,
-		tableSet: make(tableStateMap),
-		viewSet:  make(viewStateMap),
+		ctx:         context.Copy(),
+		name:        s.Name,
+		tableSet:    make(tableStateMap),
+		viewSet:     make(viewStateMap),
+		sequenceSet: make(sequenceStateMap),
 	}
 
 	for _, table := range s.Tables {
@@ -61,6 +63,8 @@ func newTableState(t *storepb.TableMetadata) *TableState {
 		engine:    newStringPointer(t.Engine),
 		collation: newStringPointer(t.Collation),
 		comment:   newStringPointer(t.Comment),
+		rowCount:  t.RowCount,
+		dataSize:  t.DataSize,
 		columnSet: make(columnStateMap),
 		indexSet:  make(indexStateMap),
 	}
@@ -115,6 +119,8 @@ type DatabaseState struct {
 	dbType       db.Type
 	schemaSet    schemaStateMap
 	deleted      bool
+	// searchPath is the schema search path set by SET search_path for PostgreSQL, nil if it isn't set.
+	searchPath []string
 }
 
 // HasNoTable returns true if the current database has no table.
@@ -172,7 +178,7 @@ func (d *DatabaseState) FindIndex(find *IndexFind) (string, *IndexState) {
 			// no need to further match table name because index is already unique in the schema
 			index, exists := table.indexSet[find.IndexName]
 			if !exists {
-				return "", nil
+				continue
 			}
 			return table.name, index
 		}
@@ -275,12 +281,34 @@ func (d *DatabaseState) FindTable(find *TableFind) *TableState {
 	return table
 }
 
+// SequenceFind is for finding sequence.
+type SequenceFind struct {
+	SchemaName   string
+	SequenceName string
+}
+
+// FindSequence finds the sequence.
+func (d *DatabaseState) FindSequence(find *SequenceFind) *SequenceState {
+	schema, exists := d.schemaSet[find.SchemaName]
+	if !exists {
+		return nil
+	}
+	sequence, exists := schema.sequenceSet[find.SequenceName]
+	if !exists {
+		return nil
+	}
+	return sequence
+}
+
 // SchemaState is the state for walk-through.
 type SchemaState struct {
 	ctx      *FinderContext
 	name     string
 	tableSet tableStateMap
 	viewSet  viewStateMap
+	// sequenceSet is only for Postgres.
+	// The database metadata doesn't contain sequences, so it only collects the sequences created during walk-through.
+	sequenceSet sequenceStateMap
 }
 type schemaStateMap map[string]*SchemaState
 
@@ -292,10 +320,21 @@ type TableState struct {
 	// collation isn't supported for Postgres, ClickHouse, Snowflake, SQLite.
 	collation *string
 	// comment isn't supported for SQLite.
-	comment   *string
+	comment *string
+	// rowCount and dataSize come from the synced table metadata, tables created in the walk-through are empty.
+	rowCount  int64
+	dataSize  int64
 	columnSet columnStateMap
 	// indexSet isn't supported for ClickHouse, Snowflake.
 	indexSet indexStateMap
+	// incomplete is true if the table is created by the walk-through for the table we cannot resolve,
+	// e.g. the unqualified table name in PostgreSQL with the unresolved search path, so its columns and indexes are unknown.
+	incomplete bool
+}
+
+// checkIntegrity returns true if we should report the columns and indexes not existing in the table.
+func (table *TableState) checkIntegrity(ctx *FinderContext) bool {
+	return ctx.CheckIntegrity && !table.incomplete
 }
 
 // CountIndex return the index total number.
@@ -303,12 +342,34 @@ func (table *TableState) CountIndex() int {
 	return len(table.indexSet)
 }
 
+// RowCount returns the estimated row count of the table.
+func (table *TableState) RowCount() int64 {
+	return table.rowCount
+}
+
+// DataSize returns the data size of the table in bytes.
+func (table *TableState) DataSize() int64 {
+	return table.dataSize
+}
+
+// ColumnNameList returns the column names of the table in alphabetical order.
+func (table *TableState) ColumnNameList() []string {
+	var res []string
+	for _, column := range table.columnSet {
+		res = append(res, column.name)
+	}
+	sort.Strings(res)
+	return res
+}
+
 func (table *TableState) copy() *TableState {
 	return &TableState{
 		name:      table.name,
 		engine:    copyStringPointer(table.engine),
 		collation: copyStringPointer(table.collation),
 		comment:   copyStringPointer(table.comment),
+		rowCount:  table.rowCount,
+		dataSize:  table.dataSize,
 		columnSet: table.columnSet.copy(),
 		indexSet:  table.indexSet.copy(),
 	}
@@ -434,6 +495,12 @@ type ViewState struct {
 }
 type viewStateMap map[string]*ViewState
 
+// SequenceState is the state for walk-through.
+type SequenceState struct {
+	name string
+}
+type sequenceStateMap map[string]*SequenceState
+
 func copyStringPointer(p *string) *string {
 	if p != nil {
 		v := *p
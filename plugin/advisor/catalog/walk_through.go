@@ -94,6 +94,18 @@ const (
 	ErrorTypeInsertSpecifiedColumnTwice = 602
 	// ErrorTypeInsertNullIntoNotNullColumn is the error that insert NULL into NOT NULL columns.
 	ErrorTypeInsertNullIntoNotNullColumn = 603
+
+	// 701 ~ 799 schema error type.
+
+	// ErrorTypeSchemaNotExists is the error that schema does not exist.
+	ErrorTypeSchemaNotExists = 701
+	// ErrorTypeSchemaExists is the error that schema already exists.
+	ErrorTypeSchemaExists = 702
+
+	// 801 ~ 899 sequence error type.
+
+	// ErrorTypeSequenceExists is the error that sequence already exists.
+	ErrorTypeSequenceExists = 801
 )
 
 // WalkThroughError is the error for walking-through.
@@ -159,6 +171,14 @@ func NewTableExistsError(tableName string) *WalkThroughError {
 	}
 }
 
+// NewSchemaNotExistsError returns a new ErrorTypeSchemaNotExists.
+func NewSchemaNotExistsError(schemaName string) *WalkThroughError {
+	return &WalkThroughError{
+		Type:    ErrorTypeSchemaNotExists,
+		Content: fmt.Sprintf("Schema `%s` does not exist", schemaName),
+	}
+}
+
 // Error implements the error interface.
 func (e *WalkThroughError) Error() string {
 	return e.Content
@@ -166,13 +186,20 @@ func (e *WalkThroughError) Error() string {
 
 // WalkThrough will collect the catalog schema in the databaseState as it walks through the stmts.
 func (d *DatabaseState) WalkThrough(stmts string) error {
-	if d.dbType != db.MySQL && d.dbType != db.TiDB {
+	switch d.dbType {
+	case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
+		return d.mysqlWalkThrough(stmts)
+	case db.Postgres:
+		return d.pgWalkThrough(stmts)
+	default:
 		return &WalkThroughError{
 			Type:    ErrorTypeUnsupported,
 			Content: fmt.Sprintf("Walk-through doesn't support engine type: %s", d.dbType),
 		}
 	}
+}
 
+func (d *DatabaseState) mysqlWalkThrough(stmts string) error {
 	// We define the Catalog as Database -> Schema -> Table. The Schema is only for PostgreSQL.
 	// So we use a Schema whose name is empty for other engines, such as MySQL.
 	// If there is no empty-string-name schema, create it to avoid corner cases.
@@ -624,7 +651,7 @@ func (t *TableState) renameColumn(ctx *FinderContext, oldName string, newName st
 
 	column, exists := t.columnSet[oldName]
 	if !exists {
-		if ctx.CheckIntegrity {
+		if t.checkIntegrity(ctx) {
 			return &WalkThroughError{
 				Type:    ErrorTypeColumnNotExists,
 				Content: fmt.Sprintf("Column `%s` does not exist in table `%s`", oldName, t.name),
@@ -1241,10 +1268,11 @@ func (t *TableState) createPrimaryKey(keys []string, tp string) *WalkThroughErro
 
 func (d *DatabaseState) createSchema(name string) *SchemaState {
 	schema := &SchemaState{
-		ctx:      d.ctx.Copy(),
-		name:     name,
-		tableSet: make(tableStateMap),
-		viewSet:  make(viewStateMap),
+		ctx:         d.ctx.Copy(),
+		name:        name,
+		tableSet:    make(tableStateMap),
+		viewSet:     make(viewStateMap),
+		sequenceSet: make(sequenceStateMap),
 	}
 
 	d.schemaSet[name] = schema
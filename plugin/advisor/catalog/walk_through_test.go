@@ -14,6 +14,8 @@ import (
 	"github.com/bytebase/bytebase/plugin/advisor/db"
 	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
 
+	// Register postgresql parser engine.
+	_ "github.com/bytebase/bytebase/plugin/parser/engine/pg"
 	// Register pingcap parser driver.
 	_ "github.com/pingcap/tidb/types/parser_driver"
 )
@@ -34,7 +36,70 @@ func TestMySQLWalkThrough(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		runWalkThroughTest(t, test, originDatabase, false /* record */)
+		runWalkThroughTest(t, test, db.MySQL, originDatabase, false /* record */)
+	}
+}
+
+func TestPostgreSQLWalkThrough(t *testing.T) {
+	originDatabase := &storepb.DatabaseMetadata{
+		Name: "test",
+		Schemas: []*storepb.SchemaMetadata{
+			{
+				Name: "public",
+			},
+		},
+	}
+
+	tests := []string{
+		"pg_walk_through",
+	}
+
+	for _, test := range tests {
+		runWalkThroughTest(t, test, db.Postgres, originDatabase, false /* record */)
+	}
+}
+
+func TestPostgreSQLWalkThroughSearchPath(t *testing.T) {
+	originDatabase := &storepb.DatabaseMetadata{
+		Name: "test",
+		Schemas: []*storepb.SchemaMetadata{
+			{
+				Name: "public",
+			},
+			{
+				Name: "app",
+				Tables: []*storepb.TableMetadata{
+					{
+						Name: "book",
+						Columns: []*storepb.ColumnMetadata{
+							{
+								Name:     "id",
+								Position: 1,
+								Type:     "integer",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []string{
+		"pg_walk_through_search_path",
+	}
+
+	for _, test := range tests {
+		runWalkThroughTest(t, test, db.Postgres, originDatabase, false /* record */)
+	}
+}
+
+func TestPostgreSQLWalkThroughForIncomplete(t *testing.T) {
+	tests := []string{
+		"pg_walk_through_for_incomplete",
+	}
+
+	for _, test := range tests {
+		runWalkThroughTest(t, test, db.Postgres, nil, false /* record */)
 	}
 }
 
@@ -44,11 +109,11 @@ func TestMySQLWalkThroughForIncomplete(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		runWalkThroughTest(t, test, nil, false /* record */)
+		runWalkThroughTest(t, test, db.MySQL, nil, false /* record */)
 	}
 }
 
-func runWalkThroughTest(t *testing.T, file string, originDatabase *storepb.DatabaseMetadata, record bool) {
+func runWalkThroughTest(t *testing.T, file string, engineType db.Type, originDatabase *storepb.DatabaseMetadata, record bool) {
 	tests := []testData{}
 	filepath := filepath.Join("test", file+".yaml")
 	yamlFile, err := os.Open(filepath)
@@ -63,9 +128,9 @@ func runWalkThroughTest(t *testing.T, file string, originDatabase *storepb.Datab
 	for i, test := range tests {
 		var state *DatabaseState
 		if originDatabase != nil {
-			state = newDatabaseState(originDatabase, &FinderContext{CheckIntegrity: true, EngineType: db.MySQL})
+			state = newDatabaseState(originDatabase, &FinderContext{CheckIntegrity: true, EngineType: engineType})
 		} else {
-			finder := NewEmptyFinder(&FinderContext{CheckIntegrity: false, EngineType: db.MySQL})
+			finder := NewEmptyFinder(&FinderContext{CheckIntegrity: false, EngineType: engineType})
 			state = finder.Origin
 		}
 
@@ -0,0 +1,77 @@
+package clickhouse
+
+import (
+	"fmt"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+	"github.com/bytebase/bytebase/plugin/parser/ast"
+)
+
+var (
+	_ advisor.Advisor = (*DisallowMutationAdvisor)(nil)
+	_ ast.Visitor     = (*disallowMutationChecker)(nil)
+)
+
+func init() {
+	advisor.Register(db.ClickHouse, advisor.ClickHouseDisallowMutation, &DisallowMutationAdvisor{})
+}
+
+// DisallowMutationAdvisor is the advisor checking for the mutation, i.e. ALTER TABLE UPDATE and ALTER TABLE DELETE.
+type DisallowMutationAdvisor struct {
+}
+
+// Check checks for the mutation.
+func (*DisallowMutationAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmts, errAdvice := parseStatement(statement)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+	checker := &disallowMutationChecker{
+		level: level,
+		title: string(ctx.Rule.Type),
+	}
+
+	for _, stmt := range stmts {
+		checker.text = stmt.Text()
+		checker.line = stmt.LastLine()
+		ast.Walk(checker, stmt)
+	}
+
+	if len(checker.adviceList) == 0 {
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return checker.adviceList, nil
+}
+
+type disallowMutationChecker struct {
+	adviceList []advisor.Advice
+	level      advisor.Status
+	title      string
+	text       string
+	line       int
+}
+
+// Visit implements the ast.Visitor interface.
+func (checker *disallowMutationChecker) Visit(node ast.Node) ast.Visitor {
+	if _, ok := node.(*ast.MutationStmt); ok {
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  checker.level,
+			Code:    advisor.StatementMutation,
+			Title:   checker.title,
+			Content: fmt.Sprintf("\"%s\" is a mutation, which rewrites all data parts containing the affected rows asynchronously and cannot be rolled back", checker.text),
+			Line:    checker.line,
+		})
+	}
+	return checker
+}
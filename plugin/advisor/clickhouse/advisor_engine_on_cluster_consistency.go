@@ -0,0 +1,103 @@
+package clickhouse
+
+import (
+	"fmt"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+	"github.com/bytebase/bytebase/plugin/parser/ast"
+)
+
+var (
+	_ advisor.Advisor = (*OnClusterConsistencyAdvisor)(nil)
+	_ ast.Visitor     = (*onClusterConsistencyChecker)(nil)
+)
+
+func init() {
+	advisor.Register(db.ClickHouse, advisor.ClickHouseOnClusterConsistency, &OnClusterConsistencyAdvisor{})
+}
+
+// OnClusterConsistencyAdvisor is the advisor checking for all DDL statements using the same ON CLUSTER clause.
+// Mixing the local DDL and the distributed DDL leaves the replicas with different schemas.
+type OnClusterConsistencyAdvisor struct {
+}
+
+// Check checks for all DDL statements using the same ON CLUSTER clause.
+func (*OnClusterConsistencyAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmts, errAdvice := parseStatement(statement)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+	checker := &onClusterConsistencyChecker{
+		level: level,
+		title: string(ctx.Rule.Type),
+	}
+
+	for _, stmt := range stmts {
+		checker.text = stmt.Text()
+		checker.line = stmt.LastLine()
+		ast.Walk(checker, stmt)
+	}
+
+	if len(checker.adviceList) == 0 {
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return checker.adviceList, nil
+}
+
+type onClusterConsistencyChecker struct {
+	adviceList []advisor.Advice
+	level      advisor.Status
+	title      string
+	text       string
+	line       int
+	// cluster is the cluster of the first DDL statement, nil if there is no DDL statement yet.
+	cluster *string
+}
+
+// Visit implements the ast.Visitor interface.
+func (checker *onClusterConsistencyChecker) Visit(node ast.Node) ast.Visitor {
+	var cluster string
+	switch n := node.(type) {
+	case *ast.CreateTableStmt:
+		cluster = n.Cluster
+	case *ast.AlterTableStmt:
+		cluster = n.Cluster
+	case *ast.DropTableStmt:
+		cluster = n.Cluster
+	default:
+		return checker
+	}
+
+	if checker.cluster == nil {
+		checker.cluster = &cluster
+		return checker
+	}
+	if cluster != *checker.cluster {
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  checker.level,
+			Code:    advisor.StatementOnClusterInconsistent,
+			Title:   checker.title,
+			Content: fmt.Sprintf("\"%s\" uses %s, which is inconsistent with %s in the previous DDL statements", checker.text, onClusterClause(cluster), onClusterClause(*checker.cluster)),
+			Line:    checker.line,
+		})
+	}
+	return checker
+}
+
+func onClusterClause(cluster string) string {
+	if cluster == "" {
+		return "no ON CLUSTER clause"
+	}
+	return fmt.Sprintf("ON CLUSTER %s", cluster)
+}
@@ -0,0 +1,80 @@
+package clickhouse
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+	"github.com/bytebase/bytebase/plugin/parser/ast"
+)
+
+var (
+	_ advisor.Advisor = (*RequireOrderByAdvisor)(nil)
+	_ ast.Visitor     = (*requireOrderByChecker)(nil)
+)
+
+func init() {
+	advisor.Register(db.ClickHouse, advisor.ClickHouseRequireOrderBy, &RequireOrderByAdvisor{})
+}
+
+// RequireOrderByAdvisor is the advisor checking for the ORDER BY sorting key of MergeTree family tables.
+type RequireOrderByAdvisor struct {
+}
+
+// Check checks for the ORDER BY sorting key of MergeTree family tables.
+func (*RequireOrderByAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmts, errAdvice := parseStatement(statement)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+	checker := &requireOrderByChecker{
+		level: level,
+		title: string(ctx.Rule.Type),
+	}
+
+	for _, stmt := range stmts {
+		ast.Walk(checker, stmt)
+	}
+
+	if len(checker.adviceList) == 0 {
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return checker.adviceList, nil
+}
+
+type requireOrderByChecker struct {
+	adviceList []advisor.Advice
+	level      advisor.Status
+	title      string
+}
+
+// Visit implements the ast.Visitor interface.
+func (checker *requireOrderByChecker) Visit(node ast.Node) ast.Visitor {
+	n, ok := node.(*ast.CreateTableStmt)
+	if !ok || !strings.HasSuffix(n.Engine, "MergeTree") {
+		return checker
+	}
+	// ORDER BY tuple() means no sorting key, the data is stored in the insertion order.
+	sortingKey := strings.ToLower(strings.Join(strings.Fields(n.SortingKey), ""))
+	if sortingKey == "" || sortingKey == "tuple()" {
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  checker.level,
+			Code:    advisor.MergeTreeNoSortingKey,
+			Title:   checker.title,
+			Content: fmt.Sprintf("Table `%s` with %s engine requires the ORDER BY sorting key", n.Name.Name, n.Engine),
+			Line:    n.LastLine(),
+		})
+	}
+	return checker
+}
@@ -0,0 +1,48 @@
+package clickhouse
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/advisor/standard"
+
+	_ "github.com/bytebase/bytebase/plugin/parser/engine/standard"
+)
+
+func TestClickHouseSyntax(t *testing.T) {
+	tests := []advisor.TestCase{
+		{
+			Statement: "CREATE TABLE book(id UInt64, name String) ENGINE = MergeTree ORDER BY id;",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "Syntax OK",
+					Content: "OK",
+				},
+			},
+		},
+		{
+			Statement: "SELECT 1;\nSELECT 'it\\'s;",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Error,
+					Code:    advisor.StatementSyntaxError,
+					Title:   "Syntax error",
+					Content: "line 2: syntax error, unterminated quoted string",
+				},
+			},
+		},
+	}
+
+	adv := &standard.SyntaxAdvisor{Dialect: dialect}
+
+	for _, tc := range tests {
+		adviceList, err := adv.Check(advisor.Context{}, tc.Statement)
+		require.NoError(t, err)
+		assert.Equal(t, tc.Want, adviceList)
+	}
+}
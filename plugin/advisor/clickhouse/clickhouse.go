@@ -0,0 +1,37 @@
+// Package clickhouse implements the SQL advisor rules for ClickHouse.
+package clickhouse
+
+import (
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/advisor/catalog"
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+	"github.com/bytebase/bytebase/plugin/advisor/standard"
+	"github.com/bytebase/bytebase/plugin/parser"
+	"github.com/bytebase/bytebase/plugin/parser/ast"
+)
+
+// dialect is the ClickHouse dialect of the advisors shared with the other engines.
+var dialect = standard.Dialect{
+	EngineType:      parser.ClickHouse,
+	DBType:          db.ClickHouse,
+	IdentifierQuote: "`",
+	TableFind: func(table *ast.TableDef) (string, *catalog.TableFind) {
+		// The ClickHouse catalog has only one schema without the name.
+		return table.Database, &catalog.TableFind{TableName: table.Name}
+	},
+}
+
+func init() {
+	advisor.Register(db.ClickHouse, advisor.ClickHouseSyntax, &standard.SyntaxAdvisor{Dialect: dialect})
+	advisor.Register(db.ClickHouse, advisor.ClickHouseNamingTableConvention, &standard.NamingTableConventionAdvisor{Dialect: dialect})
+	advisor.Register(db.ClickHouse, advisor.ClickHouseNamingColumnConvention, &standard.NamingColumnConventionAdvisor{Dialect: dialect})
+	advisor.Register(db.ClickHouse, advisor.ClickHouseWhereRequirement, &standard.WhereRequirementAdvisor{Dialect: dialect})
+	advisor.Register(db.ClickHouse, advisor.ClickHouseNoSelectAll, &standard.NoSelectAllAdvisor{Dialect: dialect})
+	advisor.Register(db.ClickHouse, advisor.ClickHouseTableCommentConvention, &standard.TableCommentConventionAdvisor{Dialect: dialect})
+	advisor.Register(db.ClickHouse, advisor.ClickHouseColumnCommentConvention, &standard.ColumnCommentConventionAdvisor{Dialect: dialect})
+	advisor.Register(db.ClickHouse, advisor.ClickHouseCustomRule, &standard.CustomRuleAdvisor{Dialect: dialect})
+}
+
+func parseStatement(statement string) ([]ast.Node, []advisor.Advice) {
+	return dialect.ParseStatement(statement)
+}
@@ -0,0 +1,27 @@
+package clickhouse
+
+import (
+	"testing"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+)
+
+func TestClickHouseRules(t *testing.T) {
+	clickhouseRules := []advisor.SQLReviewRuleType{
+		advisor.SchemaRuleTableNaming,
+		advisor.SchemaRuleColumnNaming,
+		advisor.SchemaRuleStatementRequireWhere,
+		advisor.SchemaRuleStatementNoSelectAll,
+		advisor.SchemaRuleTableCommentConvention,
+		advisor.SchemaRuleColumnCommentConvention,
+		advisor.SchemaRuleClickHouseRequireOrderBy,
+		advisor.SchemaRuleClickHouseOnClusterConsistency,
+		advisor.SchemaRuleClickHouseDisallowMutation,
+		advisor.SchemaRuleCustom,
+	}
+
+	for _, rule := range clickhouseRules {
+		advisor.RunSQLReviewRuleTest(t, rule, db.ClickHouse, false /* record */)
+	}
+}
@@ -33,6 +33,8 @@ const (
 	StatementRedundantAlterTable     Code = 207
 	StatementDMLDryRunFailed         Code = 208
 	StatementAffectedRowExceedsLimit Code = 209
+	StatementOnClusterInconsistent   Code = 210
+	StatementMutation                Code = 211
 
 	// 301 ～ 399 naming error code
 	// 301 table naming advisor error code.
@@ -72,8 +74,9 @@ const (
 	OnUpdateCurrentTimeColumnCountExceedsLimit Code = 419
 	NoDefault                                  Code = 420
 
-	// 501 engine error code.
-	NotInnoDBEngine Code = 501
+	// 501 ~ 599 engine error code.
+	NotInnoDBEngine       Code = 501
+	MergeTreeNoSortingKey Code = 502
 
 	// 601 ~ 699 table rule advisor error code.
 	TableNoPK                         Code = 601
@@ -123,6 +126,28 @@ const (
 
 	// 1301 ~ 1399 comment error code.
 	CommentTooLong Code = 1301
+
+	// 1401 ~ 1499 schema error code.
+	SchemaNotExists Code = 1401
+	SchemaExists    Code = 1402
+
+	// 1501 ~ 1599 sequence error code.
+	SequenceExists Code = 1501
+
+	// 1601 ~ 1699 lock risk error code.
+	LockRiskCreateIndex          Code = 1601
+	LockRiskAddColumnWithDefault Code = 1602
+	LockRiskValidateConstraint   Code = 1603
+	LockRiskSetNotNull           Code = 1604
+	LockRiskChangeColumnType     Code = 1605
+	LockRiskTableCopy            Code = 1606
+
+	// 1701 ~ 1799 suppression error code.
+	SuppressionInvalid Code = 1701
+
+	// 10000 ~ 19999 custom rule error code, the code is defined by the user in the rule payload.
+	CustomRuleCodeMin Code = 10000
+	CustomRuleCodeMax Code = 19999
 )
 
 // Int returns the int type of code.
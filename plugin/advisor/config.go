@@ -71,6 +71,25 @@ func MergeSQLReviewRules(override *SQLReviewConfigOverride) ([]*SQLReviewRule, e
 		res = append(res, rule)
 	}
 
+	// The custom rules aren't in the template, append them as they are.
+	for _, ruleUpdate := range override.RuleList {
+		if ruleUpdate.Type != SchemaRuleCustom {
+			continue
+		}
+		rule, err := mergeRule(&SQLReviewRuleData{
+			Type:    ruleUpdate.Type,
+			Level:   SchemaRuleLevelError,
+			Payload: ruleUpdate.Payload,
+		}, ruleUpdate)
+		if err != nil {
+			return nil, err
+		}
+		if err := rule.Validate(); err != nil {
+			return nil, err
+		}
+		res = append(res, rule)
+	}
+
 	return res, nil
 }
 
@@ -29,6 +29,12 @@ ruleList:
     payload:
       list:
         - name
+  - type: custom
+    level: WARNING
+    payload:
+      title: no-truncate
+      code: 10001
+      expression: statement_type == 'TRUNCATE'
 `
 
 func TestConfigOverride(t *testing.T) {
@@ -39,6 +45,7 @@ func TestConfigOverride(t *testing.T) {
 	ruleList, err := MergeSQLReviewRules(override)
 	require.NoError(t, err)
 
+	customRuleCount := 0
 	for _, rule := range ruleList {
 		switch rule.Type {
 		case "statement.select.no-select-all":
@@ -74,6 +81,18 @@ func TestConfigOverride(t *testing.T) {
 
 			assert.Equal(t, 1, len(payload.List))
 			assert.Equal(t, "name", payload.List[0])
+		case "custom":
+			customRuleCount++
+			assert.Equal(t, SchemaRuleLevelWarning, rule.Level)
+
+			var payload CustomRulePayload
+			err := json.Unmarshal([]byte(rule.Payload), &payload)
+			require.NoError(t, err)
+
+			assert.Equal(t, "no-truncate", payload.Title)
+			assert.Equal(t, 10001, payload.Code)
+			assert.Equal(t, "statement_type == 'TRUNCATE'", payload.Expression)
 		}
 	}
+	assert.Equal(t, 1, customRuleCount)
 }
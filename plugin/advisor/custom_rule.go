@@ -0,0 +1,252 @@
+package advisor
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/Knetic/govaluate"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/plugin/advisor/catalog"
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+)
+
+// The statement types for the statement_type parameter of the custom rule expression.
+// Other statements use their leading keyword in upper case, e.g. "GRANT".
+const (
+	CustomRuleStatementCreateTable = "CREATE_TABLE"
+	CustomRuleStatementAlterTable  = "ALTER_TABLE"
+	CustomRuleStatementDropTable   = "DROP_TABLE"
+	CustomRuleStatementRenameTable = "RENAME_TABLE"
+	CustomRuleStatementTruncate    = "TRUNCATE"
+	CustomRuleStatementCreateIndex = "CREATE_INDEX"
+	CustomRuleStatementDropIndex   = "DROP_INDEX"
+	CustomRuleStatementInsert      = "INSERT"
+	CustomRuleStatementUpdate      = "UPDATE"
+	CustomRuleStatementDelete      = "DELETE"
+	CustomRuleStatementSelect      = "SELECT"
+)
+
+// The parameters available in the custom rule expression.
+const (
+	// customRuleParamEngine is the database engine, e.g. "MYSQL", "POSTGRES".
+	customRuleParamEngine = "engine"
+	// customRuleParamStatementType is the statement type, e.g. "CREATE_TABLE", "TRUNCATE".
+	customRuleParamStatementType = "statement_type"
+	// customRuleParamStatement is the statement text.
+	customRuleParamStatement = "statement"
+	// customRuleParamSchema is the schema of the table, it's the database for MySQL and ClickHouse.
+	customRuleParamSchema = "schema"
+	// customRuleParamTable is the table name, empty if the statement doesn't touch any table.
+	customRuleParamTable = "table"
+	// customRuleParamColumns is the column name list of the table after the change.
+	customRuleParamColumns = "columns"
+	// customRuleParamTableExists is true if the table exists before the change.
+	customRuleParamTableExists = "table_exists"
+	// customRuleParamRowCount is the estimated row count of the table before the change.
+	customRuleParamRowCount = "row_count"
+	// customRuleParamHasWhere is true if the UPDATE or DELETE statement has the WHERE clause.
+	customRuleParamHasWhere = "has_where"
+)
+
+var (
+	customRuleParamList = []string{
+		customRuleParamEngine,
+		customRuleParamStatementType,
+		customRuleParamStatement,
+		customRuleParamSchema,
+		customRuleParamTable,
+		customRuleParamColumns,
+		customRuleParamTableExists,
+		customRuleParamRowCount,
+		customRuleParamHasWhere,
+	}
+	customRuleFunctionMap = map[string]govaluate.ExpressionFunction{
+		"lower": func(args ...interface{}) (interface{}, error) {
+			s, err := customRuleStringArgument("lower", args)
+			if err != nil {
+				return nil, err
+			}
+			return strings.ToLower(s), nil
+		},
+		"upper": func(args ...interface{}) (interface{}, error) {
+			s, err := customRuleStringArgument("upper", args)
+			if err != nil {
+				return nil, err
+			}
+			return strings.ToUpper(s), nil
+		},
+	}
+)
+
+// CustomRulePayload is the payload for the custom rule.
+type CustomRulePayload struct {
+	// Title is the title of the advice.
+	Title string `json:"title"`
+	// Code is the code of the advice, it should be in [CustomRuleCodeMin, CustomRuleCodeMax].
+	Code int `json:"code"`
+	// Expression is the boolean expression matching the statements violating the rule, such as
+	// "statement_type == 'CREATE_TABLE' && schema == 'billing' && !('audited_at' IN columns)".
+	// See https://github.com/Knetic/govaluate for the syntax.
+	Expression string `json:"expression"`
+	// Message is the content of the advice, "{{statement}}", "{{schema}}" and "{{table}}" are replaced with the matched statement.
+	Message string `json:"message"`
+}
+
+// CustomRuleStatement is the statement matched by the custom rule expression.
+// The statement touching multiple tables, such as DROP TABLE t1, t2, is matched once for each table.
+type CustomRuleStatement struct {
+	Type   string
+	Text   string
+	Line   int
+	Schema string
+	Table  string
+	// TableFind finds the table in the catalog, nil if the statement doesn't touch any table.
+	TableFind *catalog.TableFind
+	// ColumnList is the column list defined by the statement, it's used if the table is missing in the catalog.
+	ColumnList []string
+	HasWhere   bool
+}
+
+// CustomRule is the compiled custom rule.
+type CustomRule struct {
+	payload    *CustomRulePayload
+	expression *govaluate.EvaluableExpression
+}
+
+// NewCustomRule unmarshals the payload to CustomRulePayload and compiles the expression.
+func NewCustomRule(payload string) (*CustomRule, error) {
+	var crp CustomRulePayload
+	if err := json.Unmarshal([]byte(payload), &crp); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal custom rule payload %q", payload)
+	}
+	if crp.Title == "" {
+		return nil, errors.Errorf("invalid custom rule payload, title cannot be empty")
+	}
+	if crp.Code < CustomRuleCodeMin.Int() || crp.Code > CustomRuleCodeMax.Int() {
+		return nil, errors.Errorf("invalid custom rule payload, code %d should be between %d and %d", crp.Code, CustomRuleCodeMin, CustomRuleCodeMax)
+	}
+	if crp.Expression == "" {
+		return nil, errors.Errorf("invalid custom rule payload, expression cannot be empty")
+	}
+	expression, err := govaluate.NewEvaluableExpressionWithFunctions(crp.Expression, customRuleFunctionMap)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compile custom rule expression %q", crp.Expression)
+	}
+	for _, name := range expression.Vars() {
+		if !isCustomRuleParam(name) {
+			return nil, errors.Errorf("unknown parameter %q in custom rule expression %q, available parameters are %s", name, crp.Expression, strings.Join(customRuleParamList, ", "))
+		}
+	}
+	return &CustomRule{payload: &crp, expression: expression}, nil
+}
+
+// Check returns the advice if the statement matches the rule expression, otherwise returns nil.
+func (r *CustomRule) Check(engine db.Type, finder *catalog.Finder, level Status, stmt *CustomRuleStatement) (*Advice, error) {
+	columnList := stmt.ColumnList
+	tableExists := false
+	var rowCount int64
+	if stmt.TableFind != nil && finder != nil {
+		if table := finder.Origin.FindTable(stmt.TableFind); table != nil {
+			tableExists = true
+			rowCount = table.RowCount()
+		}
+		if table := finder.Final.FindTable(stmt.TableFind); table != nil {
+			columnList = table.ColumnNameList()
+		}
+	}
+	var columns []interface{}
+	for _, column := range columnList {
+		columns = append(columns, column)
+	}
+
+	result, err := r.expression.Evaluate(map[string]interface{}{
+		customRuleParamEngine:        string(engine),
+		customRuleParamStatementType: stmt.Type,
+		customRuleParamStatement:     stmt.Text,
+		customRuleParamSchema:        stmt.Schema,
+		customRuleParamTable:         stmt.Table,
+		customRuleParamColumns:       columns,
+		customRuleParamTableExists:   tableExists,
+		customRuleParamRowCount:      float64(rowCount),
+		customRuleParamHasWhere:      stmt.HasWhere,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to evaluate custom rule expression %q for statement %q", r.payload.Expression, stmt.Text)
+	}
+	matched, ok := result.(bool)
+	if !ok {
+		return nil, errors.Errorf("custom rule expression %q should return a boolean, but got %v", r.payload.Expression, result)
+	}
+	if !matched {
+		return nil, nil
+	}
+
+	content := fmt.Sprintf("\"%s\" violates the custom rule \"%s\"", stmt.Text, r.payload.Title)
+	if r.payload.Message != "" {
+		content = strings.NewReplacer(
+			"{{statement}}", stmt.Text,
+			"{{schema}}", stmt.Schema,
+			"{{table}}", stmt.Table,
+		).Replace(r.payload.Message)
+	}
+	return &Advice{
+		Status:  level,
+		Code:    Code(r.payload.Code),
+		Title:   r.payload.Title,
+		Content: content,
+		Line:    stmt.Line,
+	}, nil
+}
+
+// LeadingKeyword returns the first keyword of the statement in upper case, skipping the leading blanks and comments.
+func LeadingKeyword(statement string) string {
+	s := statement
+	for {
+		s = strings.TrimLeftFunc(s, unicode.IsSpace)
+		switch {
+		case strings.HasPrefix(s, "--"), strings.HasPrefix(s, "#"):
+			if i := strings.IndexByte(s, '\n'); i >= 0 {
+				s = s[i+1:]
+			} else {
+				s = ""
+			}
+		case strings.HasPrefix(s, "/*"):
+			if i := strings.Index(s, "*/"); i >= 0 {
+				s = s[i+2:]
+			} else {
+				s = ""
+			}
+		default:
+			end := strings.IndexFunc(s, func(r rune) bool {
+				return !unicode.IsLetter(r) && r != '_'
+			})
+			if end < 0 {
+				end = len(s)
+			}
+			return strings.ToUpper(s[:end])
+		}
+	}
+}
+
+func isCustomRuleParam(name string) bool {
+	for _, param := range customRuleParamList {
+		if param == name {
+			return true
+		}
+	}
+	return false
+}
+
+func customRuleStringArgument(function string, args []interface{}) (string, error) {
+	if len(args) != 1 {
+		return "", errors.Errorf("%s() expects 1 argument, but got %d", function, len(args))
+	}
+	s, ok := args[0].(string)
+	if !ok {
+		return "", errors.Errorf("%s() expects a string argument, but got %v", function, args[0])
+	}
+	return s, nil
+}
@@ -0,0 +1,110 @@
+package advisor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/advisor/catalog"
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+)
+
+func TestNewCustomRule(t *testing.T) {
+	tests := []struct {
+		payload string
+		err     bool
+	}{
+		{
+			payload: `{"title": "no-truncate", "code": 10001, "expression": "statement_type == 'TRUNCATE'"}`,
+			err:     false,
+		},
+		{
+			payload: `{"title": "", "code": 10001, "expression": "statement_type == 'TRUNCATE'"}`,
+			err:     true,
+		},
+		{
+			payload: `{"title": "no-truncate", "code": 202, "expression": "statement_type == 'TRUNCATE'"}`,
+			err:     true,
+		},
+		{
+			payload: `{"title": "no-truncate", "code": 10001, "expression": ""}`,
+			err:     true,
+		},
+		{
+			payload: `{"title": "no-truncate", "code": 10001, "expression": "statement_type == "}`,
+			err:     true,
+		},
+		{
+			payload: `{"title": "no-truncate", "code": 10001, "expression": "stmt_type == 'TRUNCATE'"}`,
+			err:     true,
+		},
+		{
+			payload: `{"title": "no-truncate", "code": 10001, "expression": "trim(statement) == ''"}`,
+			err:     true,
+		},
+	}
+
+	for _, test := range tests {
+		_, err := NewCustomRule(test.payload)
+		if test.err {
+			require.Error(t, err, test.payload)
+		} else {
+			require.NoError(t, err, test.payload)
+		}
+	}
+}
+
+func TestCustomRuleCheck(t *testing.T) {
+	rule, err := NewCustomRule(`{
+		"title": "billing.audited-at",
+		"code": 10002,
+		"expression": "lower(schema) == 'billing' && !('audited_at' IN columns) && table_exists && row_count > 100",
+		"message": "Table {{schema}}.{{table}} requires the audited_at column"
+	}`)
+	require.NoError(t, err)
+
+	finder := catalog.NewFinder(MockPostgreSQLDatabase, &catalog.FinderContext{CheckIntegrity: true, EngineType: db.Postgres})
+	stmt := &CustomRuleStatement{
+		Type:      CustomRuleStatementAlterTable,
+		Text:      "ALTER TABLE tech_book ADD COLUMN a int;",
+		Line:      3,
+		Schema:    "BILLING",
+		Table:     MockTableName,
+		TableFind: &catalog.TableFind{SchemaName: "public", TableName: MockTableName},
+	}
+	advice, err := rule.Check(db.Postgres, finder, Warn, stmt)
+	require.NoError(t, err)
+	require.Equal(t, &Advice{
+		Status:  Warn,
+		Code:    10002,
+		Title:   "billing.audited-at",
+		Content: "Table BILLING.tech_book requires the audited_at column",
+		Line:    3,
+	}, advice)
+
+	stmt.Schema = "public"
+	advice, err = rule.Check(db.Postgres, finder, Warn, stmt)
+	require.NoError(t, err)
+	require.Nil(t, advice)
+
+	rule, err = NewCustomRule(`{"title": "not-boolean", "code": 10003, "expression": "row_count + 1"}`)
+	require.NoError(t, err)
+	_, err = rule.Check(db.Postgres, finder, Warn, stmt)
+	require.Error(t, err)
+}
+
+func TestLeadingKeyword(t *testing.T) {
+	tests := []struct {
+		statement string
+		want      string
+	}{
+		{statement: "truncate table t;", want: "TRUNCATE"},
+		{statement: "  -- comment\n/* block */ GRANT SELECT ON t TO u;", want: "GRANT"},
+		{statement: "# comment", want: ""},
+		{statement: "", want: ""},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.want, LeadingKeyword(test.statement), test.statement)
+	}
+}
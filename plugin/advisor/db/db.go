@@ -18,6 +18,14 @@ const (
 	Postgres Type = "POSTGRES"
 	// TiDB is the database type for TiDB.
 	TiDB Type = "TIDB"
+	// MariaDB is the database type for MariaDB.
+	MariaDB Type = "MARIADB"
+	// OceanBase is the database type for OceanBase.
+	OceanBase Type = "OCEANBASE"
+	// Snowflake is the database type for Snowflake.
+	Snowflake Type = "SNOWFLAKE"
+	// ClickHouse is the database type for ClickHouse.
+	ClickHouse Type = "CLICKHOUSE"
 )
 
 // ConvertToAdvisorDBType will convert db type into advisor db type.
@@ -29,6 +37,14 @@ func ConvertToAdvisorDBType(dbType string) (Type, error) {
 		return Postgres, nil
 	case string(TiDB):
 		return TiDB, nil
+	case string(MariaDB):
+		return MariaDB, nil
+	case string(OceanBase):
+		return OceanBase, nil
+	case string(Snowflake):
+		return Snowflake, nil
+	case string(ClickHouse):
+		return ClickHouse, nil
 	}
 
 	return "", errors.Errorf("unsupported db type %s for advisor", dbType)
@@ -14,6 +14,8 @@ func init() {
 	advisor.Register(db.MySQL, advisor.Fake, &Advisor{})
 	advisor.Register(db.Postgres, advisor.Fake, &Advisor{})
 	advisor.Register(db.TiDB, advisor.Fake, &Advisor{})
+	advisor.Register(db.MariaDB, advisor.Fake, &Advisor{})
+	advisor.Register(db.OceanBase, advisor.Fake, &Advisor{})
 }
 
 // Advisor is the fake sql advisor.
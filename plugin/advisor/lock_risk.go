@@ -0,0 +1,60 @@
+package advisor
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bytebase/bytebase/plugin/advisor/catalog"
+)
+
+// The throughput used to estimate how long a blocking DDL holds its lock.
+// Real numbers depend on the hardware and the workload, the estimation only needs to tell
+// a lock held for seconds from a lock held for hours.
+const (
+	tableScanBytesPerSecond    = 200 * 1024 * 1024
+	tableRewriteBytesPerSecond = 50 * 1024 * 1024
+)
+
+// LockRiskOperation is the work a DDL does on the table while holding the lock.
+type LockRiskOperation string
+
+const (
+	// LockRiskOperationScan means the DDL reads the whole table, e.g. validating a constraint or building an index.
+	LockRiskOperationScan LockRiskOperation = "scans"
+	// LockRiskOperationRewrite means the DDL rewrites the whole table, e.g. changing the column type.
+	LockRiskOperationRewrite LockRiskOperation = "rewrites"
+)
+
+// IsLockRiskTable returns true if the table exists before the change and holds at least minRowCount rows.
+// Tables created in the same change are empty, so locking them is harmless.
+func IsLockRiskTable(table *catalog.TableState, minRowCount int) bool {
+	return table != nil && table.RowCount() >= int64(minRowCount)
+}
+
+// EstimateLockDuration estimates how long the DDL holds the lock on a table with dataSize bytes.
+func EstimateLockDuration(dataSize int64, operation LockRiskOperation) time.Duration {
+	bytesPerSecond := int64(tableScanBytesPerSecond)
+	if operation == LockRiskOperationRewrite {
+		bytesPerSecond = tableRewriteBytesPerSecond
+	}
+	seconds := dataSize / bytesPerSecond
+	if seconds < 1 {
+		seconds = 1
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// FormatLockRisk formats the advice content for the lock risk rule.
+func FormatLockRisk(statement string, lock string, tableName string, table *catalog.TableState, operation LockRiskOperation, suggestion string) string {
+	return fmt.Sprintf(
+		"\"%s\" takes %s lock on table %s and %s it (about %d rows, %d MB), the lock is expected to be held for at least %s. %s",
+		statement,
+		lock,
+		tableName,
+		operation,
+		table.RowCount(),
+		table.DataSize()/1024/1024,
+		EstimateLockDuration(table.DataSize(), operation),
+		suggestion,
+	)
+}
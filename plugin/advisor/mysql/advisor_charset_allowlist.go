@@ -20,6 +20,8 @@ var (
 func init() {
 	advisor.Register(db.MySQL, advisor.MySQLCharsetAllowlist, &CharsetAllowlistAdvisor{})
 	advisor.Register(db.TiDB, advisor.MySQLCharsetAllowlist, &CharsetAllowlistAdvisor{})
+	advisor.Register(db.MariaDB, advisor.MySQLCharsetAllowlist, &CharsetAllowlistAdvisor{})
+	advisor.Register(db.OceanBase, advisor.MySQLCharsetAllowlist, &CharsetAllowlistAdvisor{})
 }
 
 // CharsetAllowlistAdvisor is the advisor checking for charset allowlist.
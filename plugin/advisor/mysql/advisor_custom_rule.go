@@ -0,0 +1,175 @@
+package mysql
+
+import (
+	"github.com/pingcap/tidb/parser/ast"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/advisor/catalog"
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+)
+
+var (
+	_ advisor.Advisor = (*CustomRuleAdvisor)(nil)
+	_ ast.Visitor     = (*tableNameCollector)(nil)
+)
+
+func init() {
+	advisor.Register(db.MySQL, advisor.MySQLCustomRule, &CustomRuleAdvisor{engine: db.MySQL})
+	advisor.Register(db.TiDB, advisor.MySQLCustomRule, &CustomRuleAdvisor{engine: db.TiDB})
+	advisor.Register(db.MariaDB, advisor.MySQLCustomRule, &CustomRuleAdvisor{engine: db.MariaDB})
+	advisor.Register(db.OceanBase, advisor.MySQLCustomRule, &CustomRuleAdvisor{engine: db.OceanBase})
+}
+
+// CustomRuleAdvisor is the advisor checking for the user-defined rule.
+type CustomRuleAdvisor struct {
+	engine db.Type
+}
+
+// Check checks for the user-defined rule.
+func (a *CustomRuleAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmtList, errAdvice := parseStatement(statement, ctx.Charset, ctx.Collation)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+	rule, err := advisor.NewCustomRule(ctx.Rule.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var adviceList []advisor.Advice
+	for _, stmt := range stmtList {
+		for _, customStmt := range convertToCustomRuleStatementList(stmt) {
+			advice, err := rule.Check(a.engine, ctx.Catalog, level, customStmt)
+			if err != nil {
+				return nil, err
+			}
+			if advice != nil {
+				adviceList = append(adviceList, *advice)
+			}
+		}
+	}
+
+	if len(adviceList) == 0 {
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return adviceList, nil
+}
+
+// convertToCustomRuleStatementList converts the statement to the custom rule statements, one for each table it touches.
+func convertToCustomRuleStatementList(stmt ast.StmtNode) []*advisor.CustomRuleStatement {
+	tp := advisor.LeadingKeyword(stmt.Text())
+	var tableList []*ast.TableName
+	var columnList []string
+	hasWhere := false
+	switch node := stmt.(type) {
+	case *ast.CreateTableStmt:
+		tp = advisor.CustomRuleStatementCreateTable
+		tableList = append(tableList, node.Table)
+		for _, column := range node.Cols {
+			columnList = append(columnList, column.Name.Name.O)
+		}
+	case *ast.AlterTableStmt:
+		tp = advisor.CustomRuleStatementAlterTable
+		tableList = append(tableList, node.Table)
+	case *ast.DropTableStmt:
+		if !node.IsView {
+			tp = advisor.CustomRuleStatementDropTable
+			tableList = append(tableList, node.Tables...)
+		}
+	case *ast.RenameTableStmt:
+		tp = advisor.CustomRuleStatementRenameTable
+		for _, tableToTable := range node.TableToTables {
+			tableList = append(tableList, tableToTable.OldTable)
+		}
+	case *ast.TruncateTableStmt:
+		tp = advisor.CustomRuleStatementTruncate
+		tableList = append(tableList, node.Table)
+	case *ast.CreateIndexStmt:
+		tp = advisor.CustomRuleStatementCreateIndex
+		tableList = append(tableList, node.Table)
+	case *ast.DropIndexStmt:
+		tp = advisor.CustomRuleStatementDropIndex
+		tableList = append(tableList, node.Table)
+	case *ast.InsertStmt:
+		if !node.IsReplace {
+			tp = advisor.CustomRuleStatementInsert
+		}
+		tableList = collectTableName(node.Table)
+	case *ast.UpdateStmt:
+		tp = advisor.CustomRuleStatementUpdate
+		tableList = collectTableName(node.TableRefs)
+		hasWhere = node.Where != nil
+	case *ast.DeleteStmt:
+		tp = advisor.CustomRuleStatementDelete
+		tableList = collectTableName(node.TableRefs)
+		hasWhere = node.Where != nil
+	case *ast.SelectStmt, *ast.SetOprStmt:
+		tp = advisor.CustomRuleStatementSelect
+	}
+
+	if len(tableList) == 0 {
+		return []*advisor.CustomRuleStatement{
+			{
+				Type:     tp,
+				Text:     stmt.Text(),
+				Line:     stmt.OriginTextPosition(),
+				HasWhere: hasWhere,
+			},
+		}
+	}
+	var res []*advisor.CustomRuleStatement
+	for _, table := range tableList {
+		res = append(res, &advisor.CustomRuleStatement{
+			Type:   tp,
+			Text:   stmt.Text(),
+			Line:   stmt.OriginTextPosition(),
+			Schema: table.Schema.O,
+			Table:  table.Name.O,
+			// The MySQL catalog doesn't support the cross database reference, the schema is always empty.
+			TableFind:  &catalog.TableFind{TableName: table.Name.O},
+			ColumnList: columnList,
+			HasWhere:   hasWhere,
+		})
+	}
+	return res
+}
+
+// collectTableName collects the table names in the table references, the subqueries are skipped.
+func collectTableName(node *ast.TableRefsClause) []*ast.TableName {
+	if node == nil {
+		return nil
+	}
+	collector := &tableNameCollector{}
+	node.Accept(collector)
+	return collector.tableList
+}
+
+type tableNameCollector struct {
+	tableList []*ast.TableName
+}
+
+// Enter implements the ast.Visitor interface.
+func (c *tableNameCollector) Enter(in ast.Node) (ast.Node, bool) {
+	switch node := in.(type) {
+	case *ast.TableName:
+		c.tableList = append(c.tableList, node)
+	case *ast.SelectStmt, *ast.SetOprStmt:
+		return in, true
+	}
+	return in, false
+}
+
+// Leave implements the ast.Visitor interface.
+func (*tableNameCollector) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
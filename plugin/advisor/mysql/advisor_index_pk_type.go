@@ -23,6 +23,8 @@ var (
 func init() {
 	advisor.Register(db.MySQL, advisor.MySQLIndexPKType, &IndexPkTypeAdvisor{})
 	advisor.Register(db.TiDB, advisor.MySQLIndexPKType, &IndexPkTypeAdvisor{})
+	advisor.Register(db.MariaDB, advisor.MySQLIndexPKType, &IndexPkTypeAdvisor{})
+	advisor.Register(db.OceanBase, advisor.MySQLIndexPKType, &IndexPkTypeAdvisor{})
 }
 
 // IndexPkTypeAdvisor is the advisor checking for correct type of PK.
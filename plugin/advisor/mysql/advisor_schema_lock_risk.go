@@ -0,0 +1,174 @@
+package mysql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/tidb/parser/ast"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/advisor/catalog"
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+)
+
+var (
+	_ advisor.Advisor = (*SchemaLockRiskAdvisor)(nil)
+	_ ast.Visitor     = (*schemaLockRiskChecker)(nil)
+)
+
+func init() {
+	advisor.Register(db.MySQL, advisor.MySQLLockRisk, &SchemaLockRiskAdvisor{})
+	advisor.Register(db.MariaDB, advisor.MySQLLockRisk, &SchemaLockRiskAdvisor{})
+}
+
+// SchemaLockRiskAdvisor is the advisor checking for DDL taking blocking locks on large tables.
+type SchemaLockRiskAdvisor struct {
+}
+
+// Check checks for DDL taking blocking locks on large tables.
+func (*SchemaLockRiskAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmtList, errAdvice := parseStatement(statement, ctx.Charset, ctx.Collation)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := advisor.UnmarshalNumberTypeRulePayload(ctx.Rule.Payload)
+	if err != nil {
+		return nil, err
+	}
+	checker := &schemaLockRiskChecker{
+		level:       level,
+		title:       string(ctx.Rule.Type),
+		minRowCount: payload.Number,
+		catalog:     ctx.Catalog,
+	}
+
+	for _, stmt := range stmtList {
+		checker.text = stmt.Text()
+		checker.line = stmt.OriginTextPosition()
+		(stmt).Accept(checker)
+	}
+
+	if len(checker.adviceList) == 0 {
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return checker.adviceList, nil
+}
+
+type schemaLockRiskChecker struct {
+	adviceList  []advisor.Advice
+	level       advisor.Status
+	title       string
+	text        string
+	line        int
+	minRowCount int
+	catalog     *catalog.Finder
+}
+
+// Enter implements the ast.Visitor interface.
+func (checker *schemaLockRiskChecker) Enter(in ast.Node) (ast.Node, bool) {
+	switch node := in.(type) {
+	case *ast.CreateIndexStmt:
+		if node.LockAlg != nil && node.LockAlg.AlgorithmTp == ast.AlgorithmTypeCopy {
+			checker.addTableCopyAdvice(node.Table.Name.O, []string{"ALGORITHM=COPY is specified"})
+		} else if node.KeyType == ast.IndexKeyTypeFullText || node.KeyType == ast.IndexKeyTypeSpatial {
+			checker.addFullTextIndexAdvice(node.Table.Name.O)
+		}
+	case *ast.AlterTableStmt:
+		tableName := node.Table.Name.O
+		var reasonList []string
+		addPrimaryKey := false
+		dropPrimaryKey := false
+		addFullTextIndex := false
+		for _, spec := range node.Specs {
+			switch spec.Tp {
+			case ast.AlterTableAlgorithm:
+				if spec.Algorithm == ast.AlgorithmTypeCopy {
+					reasonList = append(reasonList, "ALGORITHM=COPY is specified")
+				}
+			case ast.AlterTableChangeColumn:
+				if checker.changeColumnType(tableName, spec.OldColumnName.Name.O, spec.NewColumns[0].Tp.String()) {
+					reasonList = append(reasonList, fmt.Sprintf("the type of column `%s` is changed", spec.OldColumnName.Name.O))
+				}
+			case ast.AlterTableModifyColumn:
+				if checker.changeColumnType(tableName, spec.NewColumns[0].Name.Name.O, spec.NewColumns[0].Tp.String()) {
+					reasonList = append(reasonList, fmt.Sprintf("the type of column `%s` is changed", spec.NewColumns[0].Name.Name.O))
+				}
+			case ast.AlterTableDropPrimaryKey:
+				dropPrimaryKey = true
+			case ast.AlterTableAddConstraint:
+				switch spec.Constraint.Tp {
+				case ast.ConstraintPrimaryKey:
+					addPrimaryKey = true
+				case ast.ConstraintFulltext:
+					addFullTextIndex = true
+				}
+			case ast.AlterTableOption:
+				for _, option := range spec.Options {
+					if option.Tp == ast.TableOptionCharset && option.UintValue == ast.TableOptionCharsetWithConvertTo {
+						reasonList = append(reasonList, "the character set is converted")
+					}
+				}
+			}
+		}
+		if dropPrimaryKey && !addPrimaryKey {
+			reasonList = append(reasonList, "the primary key is dropped without adding a new one")
+		}
+		if len(reasonList) > 0 {
+			checker.addTableCopyAdvice(tableName, reasonList)
+		} else if addFullTextIndex {
+			checker.addFullTextIndexAdvice(tableName)
+		}
+	}
+
+	return in, false
+}
+
+// Leave implements the ast.Visitor interface.
+func (*schemaLockRiskChecker) Leave(in ast.Node) (ast.Node, bool) {
+	return in, true
+}
+
+func (checker *schemaLockRiskChecker) addTableCopyAdvice(tableName string, reasonList []string) {
+	checker.addAdvice(tableName, advisor.LockRiskTableCopy, advisor.LockRiskOperationRewrite,
+		fmt.Sprintf("It copies the table because %s, use the online migration (gh-ost) mode for the schema change issue instead.", strings.Join(reasonList, ", ")))
+}
+
+func (checker *schemaLockRiskChecker) addFullTextIndexAdvice(tableName string) {
+	checker.addAdvice(tableName, advisor.LockRiskCreateIndex, advisor.LockRiskOperationScan,
+		"Building FULLTEXT and SPATIAL indexes blocks writes, use the online migration (gh-ost) mode for the schema change issue instead.")
+}
+
+func (checker *schemaLockRiskChecker) addAdvice(tableName string, code advisor.Code, operation advisor.LockRiskOperation, suggestion string) {
+	table := checker.catalog.Origin.FindTable(&catalog.TableFind{TableName: tableName})
+	if !advisor.IsLockRiskTable(table, checker.minRowCount) {
+		return
+	}
+	checker.adviceList = append(checker.adviceList, advisor.Advice{
+		Status:  checker.level,
+		Code:    code,
+		Title:   checker.title,
+		Content: advisor.FormatLockRisk(checker.text, "SHARED", fmt.Sprintf("`%s`", tableName), table, operation, suggestion),
+		Line:    checker.line,
+	})
+}
+
+func (checker *schemaLockRiskChecker) changeColumnType(tableName string, columnName string, newType string) bool {
+	column := checker.catalog.Origin.FindColumn(&catalog.ColumnFind{
+		TableName:  tableName,
+		ColumnName: columnName,
+	})
+	if column == nil {
+		return false
+	}
+	return normalizeColumnType(column.Type()) != normalizeColumnType(newType)
+}
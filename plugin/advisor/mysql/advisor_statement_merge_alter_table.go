@@ -20,6 +20,8 @@ var (
 func init() {
 	advisor.Register(db.MySQL, advisor.MySQLMergeAlterTable, &StatementMergeAlterTableAdvisor{})
 	advisor.Register(db.TiDB, advisor.MySQLMergeAlterTable, &StatementMergeAlterTableAdvisor{})
+	advisor.Register(db.MariaDB, advisor.MySQLMergeAlterTable, &StatementMergeAlterTableAdvisor{})
+	advisor.Register(db.OceanBase, advisor.MySQLMergeAlterTable, &StatementMergeAlterTableAdvisor{})
 }
 
 // StatementMergeAlterTableAdvisor is the advisor checking for merging ALTER TABLE statements.
@@ -17,6 +17,8 @@ var (
 func init() {
 	advisor.Register(db.MySQL, advisor.MySQLNoSelectAll, &NoSelectAllAdvisor{})
 	advisor.Register(db.TiDB, advisor.MySQLNoSelectAll, &NoSelectAllAdvisor{})
+	advisor.Register(db.MariaDB, advisor.MySQLNoSelectAll, &NoSelectAllAdvisor{})
+	advisor.Register(db.OceanBase, advisor.MySQLNoSelectAll, &NoSelectAllAdvisor{})
 }
 
 // NoSelectAllAdvisor is the advisor checking for no "select *".
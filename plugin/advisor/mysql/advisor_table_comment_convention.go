@@ -19,6 +19,8 @@ var (
 func init() {
 	advisor.Register(db.MySQL, advisor.MySQLTableCommentConvention, &TableCommentConventionAdvisor{})
 	advisor.Register(db.TiDB, advisor.MySQLTableCommentConvention, &TableCommentConventionAdvisor{})
+	advisor.Register(db.MariaDB, advisor.MySQLTableCommentConvention, &TableCommentConventionAdvisor{})
+	advisor.Register(db.OceanBase, advisor.MySQLTableCommentConvention, &TableCommentConventionAdvisor{})
 }
 
 // TableCommentConventionAdvisor is the advisor checking for table comment convention.
@@ -17,6 +17,8 @@ var (
 func init() {
 	advisor.Register(db.MySQL, advisor.MySQLTableNoFK, &TableNoFKAdvisor{})
 	advisor.Register(db.TiDB, advisor.MySQLTableNoFK, &TableNoFKAdvisor{})
+	advisor.Register(db.MariaDB, advisor.MySQLTableNoFK, &TableNoFKAdvisor{})
+	advisor.Register(db.OceanBase, advisor.MySQLTableNoFK, &TableNoFKAdvisor{})
 }
 
 // TableNoFKAdvisor is the advisor checking table disallow foreign key.
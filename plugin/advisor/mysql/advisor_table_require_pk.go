@@ -23,6 +23,8 @@ var (
 func init() {
 	advisor.Register(db.MySQL, advisor.MySQLTableRequirePK, &TableRequirePKAdvisor{})
 	advisor.Register(db.TiDB, advisor.MySQLTableRequirePK, &TableRequirePKAdvisor{})
+	advisor.Register(db.MariaDB, advisor.MySQLTableRequirePK, &TableRequirePKAdvisor{})
+	advisor.Register(db.OceanBase, advisor.MySQLTableRequirePK, &TableRequirePKAdvisor{})
 }
 
 // TableRequirePKAdvisor is the advisor checking table requires PK.
@@ -95,6 +95,8 @@ func TestMySQLRules(t *testing.T) {
 
 		// advisor.SchemaRuleSchemaBackwardCompatibility enforce the MySQL and TiDB support check whether the schema change is backward compatible.
 		advisor.SchemaRuleSchemaBackwardCompatibility,
+		// advisor.SchemaRuleSchemaLockRisk warns the DDL taking locks that block reads or writes on large tables for a long time.
+		advisor.SchemaRuleSchemaLockRisk,
 
 		// advisor.SchemaRuleDropEmptyDatabase enforce the MySQL and TiDB support check if the database is empty before users drop it.
 		advisor.SchemaRuleDropEmptyDatabase,
@@ -115,6 +117,9 @@ func TestMySQLRules(t *testing.T) {
 
 		// advisor.SchemaRuleCollationAllowlist enforce the collation allowlist.
 		advisor.SchemaRuleCollationAllowlist,
+
+		// advisor.SchemaRuleCustom enforce the user-defined rule.
+		advisor.SchemaRuleCustom,
 	}
 
 	for _, rule := range mysqlRules {
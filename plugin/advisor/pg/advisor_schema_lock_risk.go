@@ -0,0 +1,247 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/advisor/catalog"
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+	"github.com/bytebase/bytebase/plugin/parser"
+	"github.com/bytebase/bytebase/plugin/parser/ast"
+)
+
+var (
+	_ advisor.Advisor = (*SchemaLockRiskAdvisor)(nil)
+	_ ast.Visitor     = (*schemaLockRiskChecker)(nil)
+
+	// volatileFunctionList is the list of commonly used volatile functions.
+	// A column default calling them has to be evaluated for each existing row, so adding such a column rewrites the table.
+	volatileFunctionList = []string{
+		"random",
+		"clock_timestamp",
+		"timeofday",
+		"nextval",
+		"gen_random_uuid",
+		"uuid_generate_v1",
+		"uuid_generate_v1mc",
+		"uuid_generate_v4",
+	}
+	varcharLengthRegexp = regexp.MustCompile(`^character varying\((\d+)\)$`)
+)
+
+const (
+	// PostgreSQL 11 stores the non-volatile default of the new column in the catalog instead of rewriting the table.
+	fastDefaultServerVersionNum = 110000
+)
+
+func init() {
+	advisor.Register(db.Postgres, advisor.PostgreSQLLockRisk, &SchemaLockRiskAdvisor{})
+}
+
+// SchemaLockRiskAdvisor is the advisor checking for DDL taking blocking locks on large tables.
+type SchemaLockRiskAdvisor struct {
+}
+
+// Check checks for DDL taking blocking locks on large tables.
+func (*SchemaLockRiskAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmtList, errAdvice := parseStatement(statement)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := advisor.UnmarshalNumberTypeRulePayload(ctx.Rule.Payload)
+	if err != nil {
+		return nil, err
+	}
+	checker := &schemaLockRiskChecker{
+		level:       level,
+		title:       string(ctx.Rule.Type),
+		minRowCount: payload.Number,
+		catalog:     ctx.Catalog,
+		fastDefault: supportFastDefault(ctx.Context, ctx.Driver),
+	}
+
+	for _, stmt := range stmtList {
+		checker.text = stmt.Text()
+		checker.line = stmt.LastLine()
+		ast.Walk(checker, stmt)
+	}
+
+	if len(checker.adviceList) == 0 {
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+
+	return checker.adviceList, nil
+}
+
+type schemaLockRiskChecker struct {
+	adviceList  []advisor.Advice
+	level       advisor.Status
+	title       string
+	text        string
+	line        int
+	minRowCount int
+	catalog     *catalog.Finder
+	// fastDefault is true if adding a column with a non-volatile default doesn't rewrite the table.
+	fastDefault bool
+}
+
+// Visit implements the ast.Visitor interface.
+func (checker *schemaLockRiskChecker) Visit(node ast.Node) ast.Visitor {
+	switch node := node.(type) {
+	case *ast.CreateIndexStmt:
+		if node.Concurrently {
+			break
+		}
+		checker.addAdvice(node.Index.Table, advisor.LockRiskCreateIndex, "SHARE", advisor.LockRiskOperationScan,
+			"Use \"CREATE INDEX CONCURRENTLY\" to build the index without blocking writes, it cannot run inside a transaction block.")
+	case *ast.AlterTableStmt:
+		if node.Table.Type == ast.TableTypeView {
+			break
+		}
+		for _, item := range node.AlterItemList {
+			checker.checkAlterItem(node.Table, item)
+		}
+	}
+	return checker
+}
+
+func (checker *schemaLockRiskChecker) checkAlterItem(table *ast.TableDef, item ast.Node) {
+	switch item := item.(type) {
+	case *ast.AddColumnListStmt:
+		for _, column := range item.ColumnList {
+			if checker.rewriteForNewColumn(column) {
+				checker.addAdvice(table, advisor.LockRiskAddColumnWithDefault, "ACCESS EXCLUSIVE", advisor.LockRiskOperationRewrite,
+					fmt.Sprintf("Add column \"%s\" without the default, then set the default and backfill the existing rows in batches.", column.ColumnName))
+			}
+		}
+	case *ast.AddConstraintStmt:
+		switch item.Constraint.Type {
+		case ast.ConstraintTypeForeign:
+			if !item.Constraint.SkipValidation {
+				checker.addAdvice(table, advisor.LockRiskValidateConstraint, "SHARE ROW EXCLUSIVE", advisor.LockRiskOperationScan,
+					"Add the foreign key with \"NOT VALID\", then run \"VALIDATE CONSTRAINT\" in a separate statement, which doesn't block writes.")
+			}
+		case ast.ConstraintTypeCheck:
+			if !item.Constraint.SkipValidation {
+				checker.addAdvice(table, advisor.LockRiskValidateConstraint, "ACCESS EXCLUSIVE", advisor.LockRiskOperationScan,
+					"Add the check constraint with \"NOT VALID\", then run \"VALIDATE CONSTRAINT\" in a separate statement, which doesn't block writes.")
+			}
+		case ast.ConstraintTypePrimary, ast.ConstraintTypeUnique:
+			checker.addAdvice(table, advisor.LockRiskCreateIndex, "ACCESS EXCLUSIVE", advisor.LockRiskOperationScan,
+				"Build a unique index with \"CREATE UNIQUE INDEX CONCURRENTLY\" first, then add the constraint with \"USING INDEX\".")
+		}
+	case *ast.SetNotNullStmt:
+		checker.addAdvice(table, advisor.LockRiskSetNotNull, "ACCESS EXCLUSIVE", advisor.LockRiskOperationScan,
+			fmt.Sprintf("Add and validate a \"CHECK (%s IS NOT NULL) NOT VALID\" constraint first, PostgreSQL 12 and later skip the scan with it.", item.ColumnName))
+	case *ast.AlterColumnTypeStmt:
+		if checker.rewriteForNewType(table, item) {
+			checker.addAdvice(table, advisor.LockRiskChangeColumnType, "ACCESS EXCLUSIVE", advisor.LockRiskOperationRewrite,
+				fmt.Sprintf("Add a new column with the new type, backfill it in batches and switch the application over instead of changing column \"%s\" in place.", item.ColumnName))
+		}
+	}
+}
+
+func (checker *schemaLockRiskChecker) addAdvice(tableDef *ast.TableDef, code advisor.Code, lock string, operation advisor.LockRiskOperation, suggestion string) {
+	if tableDef == nil {
+		return
+	}
+	schemaName := normalizeSchemaName(tableDef.Schema)
+	table := checker.catalog.Origin.FindTable(&catalog.TableFind{
+		SchemaName: schemaName,
+		TableName:  tableDef.Name,
+	})
+	if !advisor.IsLockRiskTable(table, checker.minRowCount) {
+		return
+	}
+	checker.adviceList = append(checker.adviceList, advisor.Advice{
+		Status:  checker.level,
+		Code:    code,
+		Title:   checker.title,
+		Content: advisor.FormatLockRisk(checker.text, lock, fmt.Sprintf(`"%s"."%s"`, schemaName, tableDef.Name), table, operation, suggestion),
+		Line:    checker.line,
+	})
+}
+
+// rewriteForNewColumn returns true if adding the column has to rewrite the table to fill in the default for the existing rows.
+func (checker *schemaLockRiskChecker) rewriteForNewColumn(column *ast.ColumnDef) bool {
+	if _, ok := column.Type.(*ast.Serial); ok {
+		return true
+	}
+	for _, constraint := range column.ConstraintList {
+		if constraint.Type != ast.ConstraintTypeDefault || constraint.Expression == nil {
+			continue
+		}
+		if !checker.fastDefault {
+			return true
+		}
+		expression := strings.ToLower(constraint.Expression.Text())
+		for _, function := range volatileFunctionList {
+			if strings.Contains(expression, function+"(") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// rewriteForNewType returns true if changing the column type has to rewrite the table.
+// Increasing the length of varchar or dropping the length limit is binary coercible and doesn't rewrite the table.
+func (checker *schemaLockRiskChecker) rewriteForNewType(table *ast.TableDef, item *ast.AlterColumnTypeStmt) bool {
+	column := checker.catalog.Origin.FindColumn(&catalog.ColumnFind{
+		SchemaName: normalizeSchemaName(table.Schema),
+		TableName:  table.Name,
+		ColumnName: item.ColumnName,
+	})
+	if column == nil {
+		return true
+	}
+	newType, err := parser.Deparse(parser.Postgres, parser.DeparseContext{}, item.Type)
+	if err != nil {
+		return true
+	}
+	oldMatch := varcharLengthRegexp.FindStringSubmatch(column.Type())
+	if oldMatch == nil {
+		return column.Type() != newType
+	}
+	if newType == "text" || newType == "character varying" {
+		return false
+	}
+	newMatch := varcharLengthRegexp.FindStringSubmatch(newType)
+	if newMatch == nil {
+		return true
+	}
+	oldLength, _ := strconv.Atoi(oldMatch[1])
+	newLength, _ := strconv.Atoi(newMatch[1])
+	return newLength < oldLength
+}
+
+// supportFastDefault returns false only if we know the server is older than PostgreSQL 11.
+func supportFastDefault(ctx context.Context, driver *sql.DB) bool {
+	if driver == nil {
+		return true
+	}
+	var version string
+	if err := driver.QueryRowContext(ctx, "SHOW server_version_num").Scan(&version); err != nil {
+		return true
+	}
+	versionNum, err := strconv.Atoi(version)
+	if err != nil {
+		return true
+	}
+	return versionNum >= fastDefaultServerVersionNum
+}
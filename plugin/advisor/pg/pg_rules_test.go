@@ -30,6 +30,8 @@ func TestPostgreSQLRules(t *testing.T) {
 		advisor.SchemaRuleTableNoFK,
 		advisor.SchemaRuleTableRequirePK,
 		advisor.SchemaRuleColumnDisallowChangeType,
+		advisor.SchemaRuleSchemaLockRisk,
+		advisor.SchemaRuleCustom,
 	}
 
 	for _, rule := range pgRules {
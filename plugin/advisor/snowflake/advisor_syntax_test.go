@@ -0,0 +1,59 @@
+package snowflake
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/advisor/standard"
+
+	_ "github.com/bytebase/bytebase/plugin/parser/engine/standard"
+)
+
+func TestSnowflakeSyntax(t *testing.T) {
+	tests := []advisor.TestCase{
+		{
+			Statement: "CREATE TABLE book(id NUMBER, name VARCHAR) COMMENT = 'book';",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Success,
+					Code:    advisor.Ok,
+					Title:   "Syntax OK",
+					Content: "OK",
+				},
+			},
+		},
+		{
+			Statement: "CREATE TABLE book(id NUMBER;\nSELECT 1;",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Error,
+					Code:    advisor.StatementSyntaxError,
+					Title:   "Syntax error",
+					Content: "line 1: syntax error at end of input",
+				},
+			},
+		},
+		{
+			Statement: "SELECT 1;\nSELEC 2;",
+			Want: []advisor.Advice{
+				{
+					Status:  advisor.Error,
+					Code:    advisor.StatementSyntaxError,
+					Title:   "Syntax error",
+					Content: "line 2: syntax error at or near \"SELEC\"",
+				},
+			},
+		},
+	}
+
+	adv := &standard.SyntaxAdvisor{Dialect: dialect}
+
+	for _, tc := range tests {
+		adviceList, err := adv.Check(advisor.Context{}, tc.Statement)
+		require.NoError(t, err)
+		assert.Equal(t, tc.Want, adviceList)
+	}
+}
@@ -0,0 +1,54 @@
+// Package snowflake implements the SQL advisor rules for Snowflake.
+package snowflake
+
+import (
+	"strings"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/advisor/catalog"
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+	"github.com/bytebase/bytebase/plugin/advisor/standard"
+	"github.com/bytebase/bytebase/plugin/parser"
+	"github.com/bytebase/bytebase/plugin/parser/ast"
+)
+
+const (
+	// publicSchema is the default schema of the Snowflake database.
+	publicSchema = "PUBLIC"
+)
+
+// dialect is the Snowflake dialect of the advisors shared with the other engines.
+var dialect = standard.Dialect{
+	EngineType:      parser.Snowflake,
+	DBType:          db.Snowflake,
+	IdentifierQuote: `"`,
+	TableFind: func(table *ast.TableDef) (string, *catalog.TableFind) {
+		schemaName, quotedSchema := table.Schema, table.QuotedSchema
+		if schemaName == "" {
+			schemaName, quotedSchema = publicSchema, false
+		}
+		return schemaName, &catalog.TableFind{
+			SchemaName: foldIdentifier(schemaName, quotedSchema),
+			TableName:  foldIdentifier(table.Name, table.QuotedName),
+		}
+	},
+}
+
+func init() {
+	advisor.Register(db.Snowflake, advisor.SnowflakeSyntax, &standard.SyntaxAdvisor{Dialect: dialect})
+	advisor.Register(db.Snowflake, advisor.SnowflakeNamingTableConvention, &standard.NamingTableConventionAdvisor{Dialect: dialect})
+	advisor.Register(db.Snowflake, advisor.SnowflakeNamingColumnConvention, &standard.NamingColumnConventionAdvisor{Dialect: dialect})
+	advisor.Register(db.Snowflake, advisor.SnowflakeWhereRequirement, &standard.WhereRequirementAdvisor{Dialect: dialect})
+	advisor.Register(db.Snowflake, advisor.SnowflakeNoSelectAll, &standard.NoSelectAllAdvisor{Dialect: dialect})
+	advisor.Register(db.Snowflake, advisor.SnowflakeTableCommentConvention, &standard.TableCommentConventionAdvisor{Dialect: dialect})
+	advisor.Register(db.Snowflake, advisor.SnowflakeColumnCommentConvention, &standard.ColumnCommentConventionAdvisor{Dialect: dialect})
+	advisor.Register(db.Snowflake, advisor.SnowflakeCustomRule, &standard.CustomRuleAdvisor{Dialect: dialect})
+}
+
+// foldIdentifier returns the identifier stored by Snowflake, which is in upper case unless it's quoted.
+func foldIdentifier(identifier string, quoted bool) string {
+	if quoted {
+		return identifier
+	}
+	return strings.ToUpper(identifier)
+}
@@ -0,0 +1,63 @@
+package snowflake
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/advisor/catalog"
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+	"github.com/bytebase/bytebase/plugin/parser/ast"
+)
+
+func TestSnowflakeRules(t *testing.T) {
+	snowflakeRules := []advisor.SQLReviewRuleType{
+		advisor.SchemaRuleTableNaming,
+		advisor.SchemaRuleColumnNaming,
+		advisor.SchemaRuleStatementRequireWhere,
+		advisor.SchemaRuleStatementNoSelectAll,
+		advisor.SchemaRuleTableCommentConvention,
+		advisor.SchemaRuleColumnCommentConvention,
+		advisor.SchemaRuleCustom,
+	}
+
+	for _, rule := range snowflakeRules {
+		advisor.RunSQLReviewRuleTest(t, rule, db.Snowflake, false /* record */)
+	}
+}
+
+func TestTableFind(t *testing.T) {
+	tests := []struct {
+		statement string
+		schema    string
+		want      *catalog.TableFind
+	}{
+		{
+			statement: "DROP TABLE book",
+			schema:    "PUBLIC",
+			want:      &catalog.TableFind{SchemaName: "PUBLIC", TableName: "BOOK"},
+		},
+		{
+			statement: `DROP TABLE "mixedCase"`,
+			schema:    "PUBLIC",
+			want:      &catalog.TableFind{SchemaName: "PUBLIC", TableName: "mixedCase"},
+		},
+		{
+			statement: `DROP TABLE "mySchema".mixedCase`,
+			schema:    "mySchema",
+			want:      &catalog.TableFind{SchemaName: "mySchema", TableName: "MIXEDCASE"},
+		},
+	}
+
+	for _, test := range tests {
+		nodes, errAdvice := dialect.ParseStatement(test.statement)
+		require.Nil(t, errAdvice, test.statement)
+		require.Len(t, nodes, 1)
+		drop, ok := nodes[0].(*ast.DropTableStmt)
+		require.True(t, ok, test.statement)
+		schema, tableFind := dialect.TableFind(drop.TableList[0])
+		require.Equal(t, test.schema, schema, test.statement)
+		require.Equal(t, test.want, tableFind, test.statement)
+	}
+}
@@ -35,6 +35,12 @@ const (
 
 	// SchemaRuleMySQLEngine require InnoDB as the storage engine.
 	SchemaRuleMySQLEngine SQLReviewRuleType = "engine.mysql.use-innodb"
+	// SchemaRuleClickHouseRequireOrderBy require the ORDER BY key for ClickHouse MergeTree family tables.
+	SchemaRuleClickHouseRequireOrderBy SQLReviewRuleType = "engine.clickhouse.require-order-by"
+	// SchemaRuleClickHouseOnClusterConsistency require all DDL statements to use the same ON CLUSTER clause.
+	SchemaRuleClickHouseOnClusterConsistency SQLReviewRuleType = "engine.clickhouse.on-cluster-consistency"
+	// SchemaRuleClickHouseDisallowMutation disallow the ClickHouse mutation, i.e. ALTER TABLE UPDATE and ALTER TABLE DELETE.
+	SchemaRuleClickHouseDisallowMutation SQLReviewRuleType = "engine.clickhouse.disallow-mutation"
 
 	// SchemaRuleTableNaming enforce the table name format.
 	SchemaRuleTableNaming SQLReviewRuleType = "naming.table"
@@ -120,6 +126,8 @@ const (
 
 	// SchemaRuleSchemaBackwardCompatibility enforce the MySQL and TiDB support check whether the schema change is backward compatible.
 	SchemaRuleSchemaBackwardCompatibility SQLReviewRuleType = "schema.backward-compatibility"
+	// SchemaRuleSchemaLockRisk warns the DDL taking locks that block reads or writes on large tables for a long time.
+	SchemaRuleSchemaLockRisk SQLReviewRuleType = "schema.lock-risk"
 
 	// SchemaRuleDropEmptyDatabase enforce the MySQL and TiDB support check if the database is empty before users drop it.
 	SchemaRuleDropEmptyDatabase SQLReviewRuleType = "database.drop-empty-database"
@@ -144,6 +152,10 @@ const (
 	// SchemaRuleCommentLength limit comment length.
 	SchemaRuleCommentLength SQLReviewRuleType = "comment.length"
 
+	// SchemaRuleCustom is the user-defined rule matching the statements with the expression in CustomRulePayload.
+	// A policy can have multiple custom rules.
+	SchemaRuleCustom SQLReviewRuleType = "custom"
+
 	// TableNameTemplateToken is the token for table name.
 	TableNameTemplateToken = "{{table}}"
 	// ColumnListTemplateToken is the token for column name list.
@@ -237,7 +249,8 @@ func (rule *SQLReviewRule) Validate() error {
 			return err
 		}
 	case SchemaRuleIndexKeyNumberLimit, SchemaRuleStatementInsertRowLimit, SchemaRuleIndexTotalNumberLimit,
-		SchemaRuleColumnMaximumCharacterLength, SchemaRuleColumnAutoIncrementInitialValue, SchemaRuleStatementAffectedRowLimit:
+		SchemaRuleColumnMaximumCharacterLength, SchemaRuleColumnAutoIncrementInitialValue, SchemaRuleStatementAffectedRowLimit,
+		SchemaRuleSchemaLockRisk:
 		if _, err := UnmarshalNumberTypeRulePayload(rule.Payload); err != nil {
 			return err
 		}
@@ -245,6 +258,10 @@ func (rule *SQLReviewRule) Validate() error {
 		if _, err := UnmarshalStringArrayTypeRulePayload(rule.Payload); err != nil {
 			return err
 		}
+	case SchemaRuleCustom:
+		if _, err := NewCustomRule(rule.Payload); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -420,12 +437,13 @@ func SQLReviewCheck(statements string, ruleList []*SQLReviewRule, checkContext S
 
 	finder := checkContext.Catalog.GetFinder()
 	switch checkContext.DbType {
-	case db.TiDB, db.MySQL:
+	case db.TiDB, db.MySQL, db.MariaDB, db.OceanBase, db.Postgres:
 		if err := finder.WalkThrough(statements); err != nil {
 			return convertWalkThroughErrorToAdvice(err)
 		}
 	}
 
+	suppressor := newSuppressor(checkContext.DbType, statements)
 	for _, rule := range ruleList {
 		if rule.Level == SchemaRuleLevelDisabled {
 			continue
@@ -454,6 +472,9 @@ func SQLReviewCheck(statements string, ruleList []*SQLReviewRule, checkContext S
 			return nil, errors.Wrap(err, "failed to check statement")
 		}
 
+		for i := range adviceList {
+			suppressor.suppress(rule, &adviceList[i])
+		}
 		result = append(result, adviceList...)
 	}
 
@@ -461,6 +482,7 @@ func SQLReviewCheck(statements string, ruleList []*SQLReviewRule, checkContext S
 	if len(result) > 0 && result[0].Title == SyntaxErrorTitle {
 		return result[:1], nil
 	}
+	result = append(result, suppressor.invalidAdviceList...)
 	if len(result) == 0 {
 		result = append(result, Advice{
 			Status:  Success,
@@ -615,6 +637,30 @@ func convertWalkThroughErrorToAdvice(err error) ([]Advice, error) {
 			Content: walkThroughError.Content,
 			Line:    walkThroughError.Line,
 		})
+	case catalog.ErrorTypeSchemaNotExists:
+		res = append(res, Advice{
+			Status:  Error,
+			Code:    SchemaNotExists,
+			Title:   "Schema does not exist",
+			Content: walkThroughError.Content,
+			Line:    walkThroughError.Line,
+		})
+	case catalog.ErrorTypeSchemaExists:
+		res = append(res, Advice{
+			Status:  Error,
+			Code:    SchemaExists,
+			Title:   "Schema already exists",
+			Content: walkThroughError.Content,
+			Line:    walkThroughError.Line,
+		})
+	case catalog.ErrorTypeSequenceExists:
+		res = append(res, Advice{
+			Status:  Error,
+			Code:    SequenceExists,
+			Title:   "Sequence already exists",
+			Content: walkThroughError.Content,
+			Line:    walkThroughError.Line,
+		})
 	}
 
 	return res, nil
@@ -624,42 +670,61 @@ func getAdvisorTypeByRule(ruleType SQLReviewRuleType, engine db.Type) (Type, err
 	switch ruleType {
 	case SchemaRuleStatementRequireWhere:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLWhereRequirement, nil
 		case db.Postgres:
 			return PostgreSQLWhereRequirement, nil
+		case db.Snowflake:
+			return SnowflakeWhereRequirement, nil
+		case db.ClickHouse:
+			return ClickHouseWhereRequirement, nil
 		}
 	case SchemaRuleStatementNoLeadingWildcardLike:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLNoLeadingWildcardLike, nil
 		case db.Postgres:
 			return PostgreSQLNoLeadingWildcardLike, nil
 		}
 	case SchemaRuleStatementNoSelectAll:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLNoSelectAll, nil
 		case db.Postgres:
 			return PostgreSQLNoSelectAll, nil
+		case db.Snowflake:
+			return SnowflakeNoSelectAll, nil
+		case db.ClickHouse:
+			return ClickHouseNoSelectAll, nil
 		}
 	case SchemaRuleSchemaBackwardCompatibility:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLMigrationCompatibility, nil
 		case db.Postgres:
 			return PostgreSQLMigrationCompatibility, nil
 		}
+	case SchemaRuleSchemaLockRisk:
+		switch engine {
+		case db.MySQL, db.MariaDB:
+			return MySQLLockRisk, nil
+		case db.Postgres:
+			return PostgreSQLLockRisk, nil
+		}
 	case SchemaRuleTableNaming:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLNamingTableConvention, nil
 		case db.Postgres:
 			return PostgreSQLNamingTableConvention, nil
+		case db.Snowflake:
+			return SnowflakeNamingTableConvention, nil
+		case db.ClickHouse:
+			return ClickHouseNamingTableConvention, nil
 		}
 	case SchemaRuleIDXNaming:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLNamingIndexConvention, nil
 		case db.Postgres:
 			return PostgreSQLNamingIndexConvention, nil
@@ -670,195 +735,220 @@ func getAdvisorTypeByRule(ruleType SQLReviewRuleType, engine db.Type) (Type, err
 		}
 	case SchemaRuleUKNaming:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLNamingUKConvention, nil
 		case db.Postgres:
 			return PostgreSQLNamingUKConvention, nil
 		}
 	case SchemaRuleFKNaming:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLNamingFKConvention, nil
 		case db.Postgres:
 			return PostgreSQLNamingFKConvention, nil
 		}
 	case SchemaRuleColumnNaming:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLNamingColumnConvention, nil
 		case db.Postgres:
 			return PostgreSQLNamingColumnConvention, nil
+		case db.Snowflake:
+			return SnowflakeNamingColumnConvention, nil
+		case db.ClickHouse:
+			return ClickHouseNamingColumnConvention, nil
 		}
 	case SchemaRuleAutoIncrementColumnNaming:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLNamingAutoIncrementColumnConvention, nil
 		}
 	case SchemaRuleRequiredColumn:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLColumnRequirement, nil
 		case db.Postgres:
 			return PostgreSQLColumnRequirement, nil
 		}
 	case SchemaRuleColumnNotNull:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLColumnNoNull, nil
 		case db.Postgres:
 			return PostgreSQLColumnNoNull, nil
 		}
 	case SchemaRuleColumnDisallowChangeType:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLColumnDisallowChangingType, nil
 		case db.Postgres:
 			return PostgreSQLColumnDisallowChangingType, nil
 		}
 	case SchemaRuleColumnSetDefaultForNotNull:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLColumnSetDefaultForNotNull, nil
 		}
 	case SchemaRuleColumnDisallowChange:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLColumnDisallowChanging, nil
 		}
 	case SchemaRuleColumnDisallowChangingOrder:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLColumnDisallowChangingOrder, nil
 		}
 	case SchemaRuleColumnCommentConvention:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLColumnCommentConvention, nil
+		case db.Snowflake:
+			return SnowflakeColumnCommentConvention, nil
+		case db.ClickHouse:
+			return ClickHouseColumnCommentConvention, nil
 		}
 	case SchemaRuleColumnAutoIncrementMustInteger:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLAutoIncrementColumnMustInteger, nil
 		}
 	case SchemaRuleColumnTypeDisallowList:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLColumnTypeRestriction, nil
 		case db.Postgres:
 			return PostgreSQLColumnTypeDisallowList, nil
 		}
 	case SchemaRuleColumnDisallowSetCharset:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLDisallowSetColumnCharset, nil
 		}
 	case SchemaRuleColumnMaximumCharacterLength:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLColumnMaximumCharacterLength, nil
 		}
 	case SchemaRuleColumnAutoIncrementInitialValue:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLAutoIncrementColumnInitialValue, nil
 		}
 	case SchemaRuleColumnAutoIncrementMustUnsigned:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLAutoIncrementColumnMustUnsigned, nil
 		}
 	case SchemaRuleCurrentTimeColumnCountLimit:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLCurrentTimeColumnCountLimit, nil
 		}
 	case SchemaRuleColumnRequireDefault:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLRequireColumnDefault, nil
 		}
 	case SchemaRuleTableRequirePK:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLTableRequirePK, nil
 		case db.Postgres:
 			return PostgreSQLTableRequirePK, nil
 		}
 	case SchemaRuleTableNoFK:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLTableNoFK, nil
 		case db.Postgres:
 			return PostgreSQLTableNoFK, nil
 		}
 	case SchemaRuleTableDropNamingConvention:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLTableDropNamingConvention, nil
 		}
 	case SchemaRuleTableCommentConvention:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLTableCommentConvention, nil
+		case db.Snowflake:
+			return SnowflakeTableCommentConvention, nil
+		case db.ClickHouse:
+			return ClickHouseTableCommentConvention, nil
 		}
 	case SchemaRuleTableDisallowPartition:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLTableDisallowPartition, nil
 		}
 	case SchemaRuleMySQLEngine:
-		if engine == db.MySQL {
+		// OceanBase has its own storage engine and ignores the ENGINE table option.
+		if engine == db.MySQL || engine == db.MariaDB {
 			return MySQLUseInnoDB, nil
 		}
+	case SchemaRuleClickHouseRequireOrderBy:
+		if engine == db.ClickHouse {
+			return ClickHouseRequireOrderBy, nil
+		}
+	case SchemaRuleClickHouseOnClusterConsistency:
+		if engine == db.ClickHouse {
+			return ClickHouseOnClusterConsistency, nil
+		}
+	case SchemaRuleClickHouseDisallowMutation:
+		if engine == db.ClickHouse {
+			return ClickHouseDisallowMutation, nil
+		}
 	case SchemaRuleDropEmptyDatabase:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLDatabaseAllowDropIfEmpty, nil
 		}
 	case SchemaRuleIndexNoDuplicateColumn:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLIndexNoDuplicateColumn, nil
 		case db.Postgres:
 			return PostgreSQLIndexNoDuplicateColumn, nil
 		}
 	case SchemaRuleIndexKeyNumberLimit:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLIndexKeyNumberLimit, nil
 		case db.Postgres:
 			return PostgreSQLIndexKeyNumberLimit, nil
 		}
 	case SchemaRuleIndexTotalNumberLimit:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLIndexTotalNumberLimit, nil
 		}
 	case SchemaRuleStatementDisallowCommit:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLStatementDisallowCommit, nil
 		}
 	case SchemaRuleCharsetAllowlist:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLCharsetAllowlist, nil
 		case db.Postgres:
 			return PostgreSQLEncodingAllowlist, nil
 		}
 	case SchemaRuleCollationAllowlist:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLCollationAllowlist, nil
 		}
 	case SchemaRuleIndexPKTypeLimit:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLIndexPKType, nil
 		}
 	case SchemaRuleIndexTypeNoBlob:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLIndexTypeNoBlob, nil
 		}
 	case SchemaRuleStatementInsertRowLimit:
@@ -870,27 +960,27 @@ func getAdvisorTypeByRule(ruleType SQLReviewRuleType, engine db.Type) (Type, err
 		}
 	case SchemaRuleStatementInsertMustSpecifyColumn:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLInsertMustSpecifyColumn, nil
 		}
 	case SchemaRuleStatementInsertDisallowOrderByRand:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLInsertDisallowOrderByRand, nil
 		}
 	case SchemaRuleStatementDisallowLimit:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLDisallowLimit, nil
 		}
 	case SchemaRuleStatementDisallowOrderBy:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLDisallowOrderBy, nil
 		}
 	case SchemaRuleStatementMergeAlterTable:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLMergeAlterTable, nil
 		}
 	case SchemaRuleStatementAffectedRowLimit:
@@ -899,13 +989,24 @@ func getAdvisorTypeByRule(ruleType SQLReviewRuleType, engine db.Type) (Type, err
 		}
 	case SchemaRuleStatementDMLDryRun:
 		switch engine {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			return MySQLStatementDMLDryRun, nil
 		}
 	case SchemaRuleCommentLength:
 		if engine == db.Postgres {
 			return PostgreSQLCommentConvention, nil
 		}
+	case SchemaRuleCustom:
+		switch engine {
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
+			return MySQLCustomRule, nil
+		case db.Postgres:
+			return PostgreSQLCustomRule, nil
+		case db.Snowflake:
+			return SnowflakeCustomRule, nil
+		case db.ClickHouse:
+			return ClickHouseCustomRule, nil
+		}
 	}
 	return Fake, errors.Errorf("unknown SQL review rule type %v for %v", ruleType, engine)
 }
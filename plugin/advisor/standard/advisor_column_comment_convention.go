@@ -0,0 +1,105 @@
+package standard
+
+import (
+	"fmt"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/parser/ast"
+)
+
+var (
+	_ advisor.Advisor = (*ColumnCommentConventionAdvisor)(nil)
+	_ ast.Visitor     = (*columnCommentConventionChecker)(nil)
+)
+
+// ColumnCommentConventionAdvisor is the advisor checking for column comment convention.
+type ColumnCommentConventionAdvisor struct {
+	Dialect Dialect
+}
+
+// Check checks for column comment convention.
+func (a *ColumnCommentConventionAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmts, errAdvice := a.Dialect.ParseStatement(statement)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := advisor.UnmarshalCommentConventionRulePayload(ctx.Rule.Payload)
+	if err != nil {
+		return nil, err
+	}
+	checker := &columnCommentConventionChecker{
+		dialect:   a.Dialect,
+		level:     level,
+		title:     string(ctx.Rule.Type),
+		required:  payload.Required,
+		maxLength: payload.MaxLength,
+	}
+
+	for _, stmt := range stmts {
+		ast.Walk(checker, stmt)
+	}
+
+	if len(checker.adviceList) == 0 {
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return checker.adviceList, nil
+}
+
+type columnCommentConventionChecker struct {
+	adviceList []advisor.Advice
+	dialect    Dialect
+	level      advisor.Status
+	title      string
+	required   bool
+	maxLength  int
+}
+
+// Visit implements the ast.Visitor interface.
+func (checker *columnCommentConventionChecker) Visit(node ast.Node) ast.Visitor {
+	var tableName string
+	var columnList []*ast.ColumnDef
+
+	switch n := node.(type) {
+	// CREATE TABLE
+	case *ast.CreateTableStmt:
+		tableName = n.Name.Name
+		columnList = n.ColumnList
+	// ALTER TABLE ADD COLUMN
+	case *ast.AddColumnListStmt:
+		tableName = n.Table.Name
+		columnList = n.ColumnList
+	}
+
+	for _, column := range columnList {
+		if checker.required && column.Comment == "" {
+			checker.adviceList = append(checker.adviceList, advisor.Advice{
+				Status:  checker.level,
+				Code:    advisor.NoColumnComment,
+				Title:   checker.title,
+				Content: fmt.Sprintf("Column %s requires comments", checker.dialect.quoteColumn(tableName, column.ColumnName)),
+				Line:    column.LastLine(),
+			})
+		}
+		if checker.maxLength >= 0 && len(column.Comment) > checker.maxLength {
+			checker.adviceList = append(checker.adviceList, advisor.Advice{
+				Status:  checker.level,
+				Code:    advisor.ColumnCommentTooLong,
+				Title:   checker.title,
+				Content: fmt.Sprintf("The length of column %s comment should be within %d characters", checker.dialect.quoteColumn(tableName, column.ColumnName), checker.maxLength),
+				Line:    column.LastLine(),
+			})
+		}
+	}
+
+	return checker
+}
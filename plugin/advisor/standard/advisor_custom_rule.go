@@ -0,0 +1,125 @@
+package standard
+
+import (
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/parser/ast"
+)
+
+var (
+	_ advisor.Advisor = (*CustomRuleAdvisor)(nil)
+)
+
+// CustomRuleAdvisor is the advisor checking for the user-defined rule.
+type CustomRuleAdvisor struct {
+	Dialect Dialect
+}
+
+// Check checks for the user-defined rule.
+func (a *CustomRuleAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmtList, errAdvice := a.Dialect.ParseStatement(statement)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+	rule, err := advisor.NewCustomRule(ctx.Rule.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var adviceList []advisor.Advice
+	for _, stmt := range stmtList {
+		for _, customStmt := range a.Dialect.convertToCustomRuleStatementList(stmt) {
+			advice, err := rule.Check(a.Dialect.DBType, ctx.Catalog, level, customStmt)
+			if err != nil {
+				return nil, err
+			}
+			if advice != nil {
+				adviceList = append(adviceList, *advice)
+			}
+		}
+	}
+
+	if len(adviceList) == 0 {
+		adviceList = append(adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return adviceList, nil
+}
+
+// convertToCustomRuleStatementList converts the statement to the custom rule statements, one for each table it touches.
+func (d Dialect) convertToCustomRuleStatementList(stmt ast.Node) []*advisor.CustomRuleStatement {
+	tp := advisor.LeadingKeyword(stmt.Text())
+	var tableList []*ast.TableDef
+	var columnList []string
+	hasWhere := false
+	switch node := stmt.(type) {
+	case *ast.CreateTableStmt:
+		tp = advisor.CustomRuleStatementCreateTable
+		tableList = append(tableList, node.Name)
+		for _, column := range node.ColumnList {
+			columnList = append(columnList, column.ColumnName)
+		}
+	case *ast.AlterTableStmt:
+		tp = advisor.CustomRuleStatementAlterTable
+		tableList = append(tableList, node.Table)
+	case *ast.DropTableStmt:
+		tp = advisor.CustomRuleStatementDropTable
+		tableList = append(tableList, node.TableList...)
+	case *ast.InsertStmt:
+		tp = advisor.CustomRuleStatementInsert
+		tableList = append(tableList, node.Table)
+	case *ast.UpdateStmt:
+		tp = advisor.CustomRuleStatementUpdate
+		tableList = append(tableList, node.Table)
+		hasWhere = node.WhereClause != nil
+	case *ast.DeleteStmt:
+		tp = advisor.CustomRuleStatementDelete
+		tableList = append(tableList, node.Table)
+		hasWhere = node.WhereClause != nil
+	case *ast.MutationStmt:
+		// The mutation is the ClickHouse UPDATE or DELETE running in the background, the WHERE clause is required by the syntax.
+		tp = advisor.CustomRuleStatementUpdate
+		if node.Type == ast.MutationTypeDelete {
+			tp = advisor.CustomRuleStatementDelete
+		}
+		tableList = append(tableList, node.Table)
+		hasWhere = node.WhereClause != nil
+	case *ast.SelectStmt:
+		tp = advisor.CustomRuleStatementSelect
+	}
+
+	var res []*advisor.CustomRuleStatement
+	for _, table := range tableList {
+		if table == nil {
+			continue
+		}
+		schemaName, tableFind := d.TableFind(table)
+		res = append(res, &advisor.CustomRuleStatement{
+			Type:       tp,
+			Text:       stmt.Text(),
+			Line:       stmt.LastLine(),
+			Schema:     schemaName,
+			Table:      table.Name,
+			TableFind:  tableFind,
+			ColumnList: columnList,
+			HasWhere:   hasWhere,
+		})
+	}
+	if len(res) == 0 {
+		res = append(res, &advisor.CustomRuleStatement{
+			Type:     tp,
+			Text:     stmt.Text(),
+			Line:     stmt.LastLine(),
+			HasWhere: hasWhere,
+		})
+	}
+	return res
+}
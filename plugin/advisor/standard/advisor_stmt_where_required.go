@@ -0,0 +1,87 @@
+package standard
+
+import (
+	"fmt"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/parser/ast"
+)
+
+var (
+	_ advisor.Advisor = (*WhereRequirementAdvisor)(nil)
+	_ ast.Visitor     = (*whereRequirementChecker)(nil)
+)
+
+// WhereRequirementAdvisor is the advisor checking for the WHERE clause requirement in DELETE and UPDATE.
+type WhereRequirementAdvisor struct {
+	Dialect Dialect
+}
+
+// Check checks for the WHERE clause requirement.
+func (a *WhereRequirementAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmts, errAdvice := a.Dialect.ParseStatement(statement)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+	checker := &whereRequirementChecker{
+		level: level,
+		title: string(ctx.Rule.Type),
+	}
+
+	for _, stmt := range stmts {
+		checker.text = stmt.Text()
+		checker.line = stmt.LastLine()
+		ast.Walk(checker, stmt)
+	}
+
+	if len(checker.adviceList) == 0 {
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return checker.adviceList, nil
+}
+
+type whereRequirementChecker struct {
+	adviceList []advisor.Advice
+	level      advisor.Status
+	title      string
+	text       string
+	line       int
+}
+
+// Visit implements the ast.Visitor interface.
+func (checker *whereRequirementChecker) Visit(node ast.Node) ast.Visitor {
+	code := advisor.Ok
+	switch n := node.(type) {
+	// DELETE
+	case *ast.DeleteStmt:
+		if n.WhereClause == nil {
+			code = advisor.StatementNoWhere
+		}
+	// UPDATE
+	case *ast.UpdateStmt:
+		if n.WhereClause == nil {
+			code = advisor.StatementNoWhere
+		}
+	}
+
+	if code != advisor.Ok {
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  checker.level,
+			Code:    code,
+			Title:   checker.title,
+			Content: fmt.Sprintf("\"%s\" requires WHERE clause", checker.text),
+			Line:    checker.line,
+		})
+	}
+	return checker
+}
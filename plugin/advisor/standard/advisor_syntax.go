@@ -0,0 +1,30 @@
+package standard
+
+import (
+	"github.com/bytebase/bytebase/plugin/advisor"
+)
+
+var (
+	_ advisor.Advisor = (*SyntaxAdvisor)(nil)
+)
+
+// SyntaxAdvisor is the advisor for checking syntax.
+type SyntaxAdvisor struct {
+	Dialect Dialect
+}
+
+// Check parses the given statement and checks for errors.
+func (a *SyntaxAdvisor) Check(_ advisor.Context, statement string) ([]advisor.Advice, error) {
+	if _, errAdvice := a.Dialect.ParseStatement(statement); errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	return []advisor.Advice{
+		{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "Syntax OK",
+			Content: "OK",
+		},
+	}, nil
+}
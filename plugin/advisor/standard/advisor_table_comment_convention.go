@@ -0,0 +1,91 @@
+package standard
+
+import (
+	"fmt"
+
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/parser/ast"
+)
+
+var (
+	_ advisor.Advisor = (*TableCommentConventionAdvisor)(nil)
+	_ ast.Visitor     = (*tableCommentConventionChecker)(nil)
+)
+
+// TableCommentConventionAdvisor is the advisor checking for table comment convention.
+type TableCommentConventionAdvisor struct {
+	Dialect Dialect
+}
+
+// Check checks for table comment convention.
+func (a *TableCommentConventionAdvisor) Check(ctx advisor.Context, statement string) ([]advisor.Advice, error) {
+	stmts, errAdvice := a.Dialect.ParseStatement(statement)
+	if errAdvice != nil {
+		return errAdvice, nil
+	}
+
+	level, err := advisor.NewStatusBySQLReviewRuleLevel(ctx.Rule.Level)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := advisor.UnmarshalCommentConventionRulePayload(ctx.Rule.Payload)
+	if err != nil {
+		return nil, err
+	}
+	checker := &tableCommentConventionChecker{
+		dialect:   a.Dialect,
+		level:     level,
+		title:     string(ctx.Rule.Type),
+		required:  payload.Required,
+		maxLength: payload.MaxLength,
+	}
+
+	for _, stmt := range stmts {
+		ast.Walk(checker, stmt)
+	}
+
+	if len(checker.adviceList) == 0 {
+		checker.adviceList = append(checker.adviceList, advisor.Advice{
+			Status:  advisor.Success,
+			Code:    advisor.Ok,
+			Title:   "OK",
+			Content: "",
+		})
+	}
+	return checker.adviceList, nil
+}
+
+type tableCommentConventionChecker struct {
+	adviceList []advisor.Advice
+	dialect    Dialect
+	level      advisor.Status
+	title      string
+	required   bool
+	maxLength  int
+}
+
+// Visit implements the ast.Visitor interface.
+func (checker *tableCommentConventionChecker) Visit(node ast.Node) ast.Visitor {
+	if n, ok := node.(*ast.CreateTableStmt); ok {
+		if checker.required && n.Comment == "" {
+			checker.adviceList = append(checker.adviceList, advisor.Advice{
+				Status:  checker.level,
+				Code:    advisor.NoTableComment,
+				Title:   checker.title,
+				Content: fmt.Sprintf("Table %s requires comments", checker.dialect.quote(n.Name.Name)),
+				Line:    n.LastLine(),
+			})
+		}
+		if checker.maxLength >= 0 && len(n.Comment) > checker.maxLength {
+			checker.adviceList = append(checker.adviceList, advisor.Advice{
+				Status:  checker.level,
+				Code:    advisor.TableCommentTooLong,
+				Title:   checker.title,
+				Content: fmt.Sprintf("The length of table %s comment should be within %d characters", checker.dialect.quote(n.Name.Name), checker.maxLength),
+				Line:    n.LastLine(),
+			})
+		}
+	}
+
+	return checker
+}
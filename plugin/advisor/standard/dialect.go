@@ -0,0 +1,48 @@
+// Package standard implements the SQL advisor rules shared by the engines parsed by the standard parser engine,
+// e.g. Snowflake and ClickHouse. The engine packages register the advisors with their dialects.
+package standard
+
+import (
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/advisor/catalog"
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+	"github.com/bytebase/bytebase/plugin/parser"
+	"github.com/bytebase/bytebase/plugin/parser/ast"
+)
+
+// Dialect is the SQL dialect of the statements checked by the advisors.
+type Dialect struct {
+	// EngineType is the parser engine type of the dialect.
+	EngineType parser.EngineType
+	// DBType is the database type checked by the custom rules.
+	DBType db.Type
+	// IdentifierQuote is the quote of the identifiers in the advice content, e.g. `"` for Snowflake.
+	IdentifierQuote string
+	// TableFind returns the schema of the table in the custom rule statement and the condition to find the table in the catalog.
+	// The engines differ in the default schema and how they store the identifiers.
+	TableFind func(table *ast.TableDef) (string, *catalog.TableFind)
+}
+
+// ParseStatement parses the statement in the dialect, returns the syntax error advice if it fails.
+func (d Dialect) ParseStatement(statement string) ([]ast.Node, []advisor.Advice) {
+	nodes, err := parser.Parse(d.EngineType, parser.ParseContext{}, statement)
+	if err != nil {
+		return nil, []advisor.Advice{
+			{
+				Status:  advisor.Error,
+				Code:    advisor.StatementSyntaxError,
+				Title:   advisor.SyntaxErrorTitle,
+				Content: err.Error(),
+			},
+		}
+	}
+	return nodes, nil
+}
+
+func (d Dialect) quote(identifier string) string {
+	return d.IdentifierQuote + identifier + d.IdentifierQuote
+}
+
+func (d Dialect) quoteColumn(tableName string, columnName string) string {
+	return d.quote(tableName) + "." + d.quote(columnName)
+}
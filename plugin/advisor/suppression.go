@@ -0,0 +1,110 @@
+package advisor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bytebase/bytebase/plugin/advisor/db"
+	"github.com/bytebase/bytebase/plugin/parser"
+)
+
+// suppressor suppresses the advices by the suppression comments in the statements, such as
+//
+//	-- bb-ignore: table.require-pk reason: the log table has no primary key
+//	CREATE TABLE log(content text);
+//	CREATE TABLE event(content text); -- bb-ignore: table.require-pk, custom.10001 reason: the event table is append-only
+//
+// The custom rules are suppressed by "custom.<code>", or all together by "custom".
+// The suppressed advices keep in the result with the Suppressed status for auditing.
+type suppressor struct {
+	stmtList []parser.SingleSQL
+	// stmtSuppressionList is the valid statement scope suppressions for each statement in stmtList.
+	stmtSuppressionList [][]*parser.Suppression
+	fileSuppressionList []*parser.Suppression
+	// invalidAdviceList is the advices for the invalid suppression comments, which are ignored.
+	invalidAdviceList []Advice
+}
+
+// newSuppressor collects the suppression comments in the statements.
+// The statements that fail to split have no suppression, the syntax error is reported by the advisors.
+func newSuppressor(dbType db.Type, statements string) *suppressor {
+	s := &suppressor{}
+	var engineType parser.EngineType
+	switch dbType {
+	case db.MySQL, db.MariaDB, db.OceanBase:
+		engineType = parser.MySQL
+	case db.TiDB:
+		engineType = parser.TiDB
+	case db.Postgres:
+		engineType = parser.Postgres
+	case db.Snowflake:
+		engineType = parser.Snowflake
+	case db.ClickHouse:
+		engineType = parser.ClickHouse
+	default:
+		return s
+	}
+	stmtList, err := parser.SplitMultiSQL(engineType, statements)
+	if err != nil {
+		return s
+	}
+
+	s.stmtList = stmtList
+	for _, stmt := range stmtList {
+		var suppressionList []*parser.Suppression
+		for _, suppression := range stmt.SuppressionList {
+			if len(suppression.RuleList) == 0 || suppression.Reason == "" {
+				s.invalidAdviceList = append(s.invalidAdviceList, Advice{
+					Status:  Warn,
+					Code:    SuppressionInvalid,
+					Title:   "Invalid suppression comment",
+					Content: fmt.Sprintf("The suppression comment at line %d is ignored, it requires the rules and the reason, e.g. \"-- bb-ignore: table.require-pk reason: the log table has no primary key\"", suppression.Line),
+					Line:    suppression.Line,
+				})
+				continue
+			}
+			if suppression.Scope == parser.SuppressionScopeFile {
+				s.fileSuppressionList = append(s.fileSuppressionList, suppression)
+				continue
+			}
+			suppressionList = append(suppressionList, suppression)
+		}
+		s.stmtSuppressionList = append(s.stmtSuppressionList, suppressionList)
+	}
+	return s
+}
+
+// suppress sets the advice status to Suppressed if the rule is suppressed for the statement reported by the advice.
+// The rule is matched by the rule type, or by the rule type with the advice code for the custom rules.
+func (s *suppressor) suppress(rule *SQLReviewRule, advice *Advice) {
+	if advice.Status == Success || advice.Title == SyntaxErrorTitle {
+		return
+	}
+
+	// Copy the file scope suppressions to avoid appending to the shared slice.
+	suppressionList := append([]*parser.Suppression{}, s.fileSuppressionList...)
+	// The advice without the line number can only be suppressed by the file scope suppression.
+	if advice.Line > 0 {
+		for i, stmt := range s.stmtList {
+			if stmt.LastLine >= advice.Line {
+				suppressionList = append(suppressionList, s.stmtSuppressionList[i]...)
+				break
+			}
+		}
+	}
+
+	// The custom rules share the rule type, and are told apart by the code in the custom rule payload.
+	customRuleName := ""
+	if rule.Type == SchemaRuleCustom {
+		customRuleName = fmt.Sprintf("%s.%d", SchemaRuleCustom, advice.Code.Int())
+	}
+	for _, suppression := range suppressionList {
+		for _, name := range suppression.RuleList {
+			if strings.EqualFold(name, string(rule.Type)) || (customRuleName != "" && strings.EqualFold(name, customRuleName)) {
+				advice.Status = Suppressed
+				advice.Content = fmt.Sprintf("%s (suppressed by the comment at line %d: %s)", advice.Content, suppression.Line, suppression.Reason)
+				return
+			}
+		}
+	}
+}
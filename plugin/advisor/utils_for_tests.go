@@ -51,6 +51,9 @@ var (
 				Tables: []*storepb.TableMetadata{
 					{
 						Name: MockTableName,
+						// 1 million rows in 1 GB.
+						RowCount: 1000000,
+						DataSize: 1073741824,
 						Columns: []*storepb.ColumnMetadata{
 							{
 								Name: "id",
@@ -92,6 +95,9 @@ var (
 				Tables: []*storepb.TableMetadata{
 					{
 						Name: MockTableName,
+						// 1 million rows in 1 GB.
+						RowCount: 1000000,
+						DataSize: 1073741824,
 						Columns: []*storepb.ColumnMetadata{
 							{Name: "id"},
 							{Name: "name"},
@@ -354,6 +360,9 @@ func SetDefaultSQLReviewRulePayload(ruleTp SQLReviewRuleType) (string, error) {
 	var err error
 	switch ruleTp {
 	case SchemaRuleMySQLEngine,
+		SchemaRuleClickHouseRequireOrderBy,
+		SchemaRuleClickHouseOnClusterConsistency,
+		SchemaRuleClickHouseDisallowMutation,
 		SchemaRuleStatementNoSelectAll,
 		SchemaRuleStatementRequireWhere,
 		SchemaRuleStatementNoLeadingWildcardLike,
@@ -422,6 +431,10 @@ func SetDefaultSQLReviewRulePayload(ruleTp SQLReviewRuleType) (string, error) {
 		payload, err = json.Marshal(NumberTypeRulePayload{
 			Number: 5,
 		})
+	case SchemaRuleSchemaLockRisk:
+		payload, err = json.Marshal(NumberTypeRulePayload{
+			Number: 10000,
+		})
 	case SchemaRuleTableCommentConvention, SchemaRuleColumnCommentConvention:
 		payload, err = json.Marshal(CommentConventionRulePayload{
 			Required:  true,
@@ -466,6 +479,15 @@ func SetDefaultSQLReviewRulePayload(ruleTp SQLReviewRuleType) (string, error) {
 			Required:  true,
 			MaxLength: 20,
 		})
+	case SchemaRuleCustom:
+		payload, err = json.Marshal(CustomRulePayload{
+			Title: "organization.audit",
+			Code:  10001,
+			Expression: "statement_type == 'TRUNCATE' || " +
+				"(statement_type == 'CREATE_TABLE' && !('audited_at' IN columns)) || " +
+				"(statement_type IN ('UPDATE', 'DELETE') && !has_where && row_count > 1000)",
+			Message: "\"{{statement}}\" violates the organization audit rule",
+		})
 	default:
 		return "", errors.Errorf("unknown SQL review type for default payload: %s", ruleTp)
 	}
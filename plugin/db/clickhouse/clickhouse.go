@@ -10,6 +10,7 @@ import (
 	"time"
 
 	clickhouse "github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
@@ -26,7 +27,9 @@ var (
 		"INFORMATION_SCHEMA": true,
 	}
 
-	_ db.Driver = (*Driver)(nil)
+	_ db.Driver        = (*Driver)(nil)
+	_ db.StreamQuerier = (*Driver)(nil)
+	_ db.QueryCanceler = (*Driver)(nil)
 )
 
 func init() {
@@ -164,3 +167,28 @@ func (driver *Driver) Execute(ctx context.Context, statement string, _ bool) (in
 func (driver *Driver) Query(ctx context.Context, statement string, queryContext *db.QueryContext) ([]interface{}, error) {
 	return util.Query(ctx, driver.dbType, driver.db, statement, queryContext)
 }
+
+// QueryStream queries a SQL statement and passes the rows to the handler as they arrive.
+// ClickHouse kills the query by the query ID rather than the session, so the query ID is used as the session ID.
+func (driver *Driver) QueryStream(ctx context.Context, statement string, queryContext *db.QueryContext, handler db.RowHandler) error {
+	if queryContext.SessionHandler != nil {
+		queryID := uuid.NewString()
+		ctx = clickhouse.Context(ctx, clickhouse.WithQueryID(queryID))
+		release := queryContext.SessionHandler(queryID)
+		defer release()
+	}
+	return util.QueryStream(ctx, driver.dbType, driver.db, statement, queryContext, handler)
+}
+
+// CancelQuery kills the query with KILL QUERY, and the session ID is the query ID.
+func (driver *Driver) CancelQuery(ctx context.Context, sessionID string) error {
+	queryID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return errors.Wrapf(err, "invalid query ID %q", sessionID)
+	}
+	statement := fmt.Sprintf("KILL QUERY WHERE query_id = '%s'", queryID.String())
+	if _, err := driver.db.ExecContext(ctx, statement); err != nil {
+		return util.FormatErrorWithQuery(err, statement)
+	}
+	return nil
+}
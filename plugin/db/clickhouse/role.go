@@ -2,33 +2,288 @@ package clickhouse
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db/util"
 	v1pb "github.com/bytebase/bytebase/proto/generated-go/v1"
 )
 
-// CreateRole creates the role.
-func (*Driver) CreateRole(_ context.Context, _ *v1pb.DatabaseRoleUpsert) (*v1pb.DatabaseRole, error) {
-	return nil, errors.Errorf("create role for ClickHouse is not implemented yet")
+var (
+	validUntilRegexp = regexp.MustCompile(`VALID UNTIL '([^']+)'`)
+)
+
+type roleFind struct {
+	Name *string
+}
+
+// CreateRole creates the role, which is a ClickHouse user.
+// The role without the login attribute is created with HOST NONE, so nobody can log in as it.
+func (driver *Driver) CreateRole(ctx context.Context, upsert *v1pb.DatabaseRoleUpsert) (*v1pb.DatabaseRole, error) {
+	if err := validateRoleUpsert(upsert); err != nil {
+		return nil, err
+	}
+	canLogin := upsert.Attribute != nil && upsert.Attribute.CanLogin
+	if canLogin && upsert.Password == nil {
+		return nil, common.Errorf(common.Invalid, "password is required to create the user %q which can log in", upsert.Name)
+	}
+
+	statement := fmt.Sprintf("CREATE USER %s", quoteIdentifier(upsert.Name))
+	if v := upsert.Password; v != nil {
+		statement += fmt.Sprintf(" IDENTIFIED WITH sha256_password BY '%s'", escapeString(*v))
+	}
+	if canLogin {
+		statement += " HOST ANY"
+	} else {
+		statement += " HOST NONE"
+	}
+	if v := upsert.ValidUntil; v != nil {
+		statement += fmt.Sprintf(" VALID UNTIL '%s'", escapeString(*v))
+	}
+	statements := []string{statement}
+	if v := upsert.Attribute; v != nil {
+		statements = append(statements, getGrantStatements(upsert.Name, &v1pb.DatabaseRoleAttribute{}, v)...)
+	}
+	if err := driver.execRoleStatements(ctx, statements); err != nil {
+		return nil, err
+	}
+	return driver.FindRole(ctx, upsert.Name)
 }
 
 // UpdateRole updates the role.
-func (*Driver) UpdateRole(_ context.Context, _ string, _ *v1pb.DatabaseRoleUpsert) (*v1pb.DatabaseRole, error) {
-	return nil, errors.Errorf("update role for ClickHouse is not implemented yet")
+func (driver *Driver) UpdateRole(ctx context.Context, roleName string, upsert *v1pb.DatabaseRoleUpsert) (*v1pb.DatabaseRole, error) {
+	if err := validateRoleUpsert(upsert); err != nil {
+		return nil, err
+	}
+	role, err := driver.FindRole(ctx, roleName)
+	if err != nil {
+		return nil, err
+	}
+
+	var statements []string
+	user := quoteIdentifier(upsert.Name)
+	if roleName != upsert.Name {
+		statements = append(statements, fmt.Sprintf("ALTER USER %s RENAME TO %s", quoteIdentifier(roleName), user))
+	}
+	if v := upsert.Password; v != nil {
+		statements = append(statements, fmt.Sprintf("ALTER USER %s IDENTIFIED WITH sha256_password BY '%s'", user, escapeString(*v)))
+	}
+	if v := upsert.ValidUntil; v != nil {
+		statements = append(statements, fmt.Sprintf("ALTER USER %s VALID UNTIL '%s'", user, escapeString(*v)))
+	}
+	if v := upsert.Attribute; v != nil {
+		if v.CanLogin {
+			statements = append(statements, fmt.Sprintf("ALTER USER %s HOST ANY", user))
+		} else {
+			statements = append(statements, fmt.Sprintf("ALTER USER %s HOST NONE", user))
+		}
+		statements = append(statements, getGrantStatements(upsert.Name, role.Attribute, v)...)
+	}
+	if err := driver.execRoleStatements(ctx, statements); err != nil {
+		return nil, err
+	}
+	return driver.FindRole(ctx, upsert.Name)
 }
 
 // FindRole finds the role by name.
-func (*Driver) FindRole(_ context.Context, _ string) (*v1pb.DatabaseRole, error) {
-	return nil, errors.Errorf("find role for ClickHouse is not implemented yet")
+func (driver *Driver) FindRole(ctx context.Context, roleName string) (*v1pb.DatabaseRole, error) {
+	roles, err := driver.findRoleImpl(ctx, &roleFind{Name: &roleName})
+	if err != nil {
+		return nil, err
+	}
+	if len(roles) == 0 {
+		return nil, common.Errorf(common.NotFound, "cannot find the role %s", roleName)
+	}
+	return roles[0], nil
 }
 
 // ListRole lists the role.
-func (*Driver) ListRole(_ context.Context) ([]*v1pb.DatabaseRole, error) {
-	return nil, errors.Errorf("list role for ClickHouse is not implemented yet")
+func (driver *Driver) ListRole(ctx context.Context) ([]*v1pb.DatabaseRole, error) {
+	return driver.findRoleImpl(ctx, &roleFind{})
 }
 
 // DeleteRole deletes the role by name.
-func (*Driver) DeleteRole(_ context.Context, _ string) error {
-	return errors.Errorf("delete role for ClickHouse is not implemented yet")
+func (driver *Driver) DeleteRole(ctx context.Context, roleName string) error {
+	return driver.execRoleStatements(ctx, []string{fmt.Sprintf("DROP USER IF EXISTS %s", quoteIdentifier(roleName))})
+}
+
+// validateRoleUpsert validates the upsert because ClickHouse users don't support every role attribute.
+func validateRoleUpsert(upsert *v1pb.DatabaseRoleUpsert) error {
+	if upsert.ConnectionLimit != nil && *upsert.ConnectionLimit != -1 {
+		return common.Errorf(common.Invalid, "connection limit is not supported for ClickHouse")
+	}
+	if v := upsert.Attribute; v != nil && (v.NoInherit || v.Replication || v.BypassRls) {
+		return common.Errorf(common.Invalid, "only superuser, create role, create database and login attributes are supported for ClickHouse")
+	}
+	return nil
+}
+
+func (driver *Driver) execRoleStatements(ctx context.Context, statements []string) error {
+	for _, statement := range statements {
+		if _, err := driver.db.ExecContext(ctx, statement); err != nil {
+			return util.FormatErrorWithQuery(err, statement)
+		}
+	}
+	return nil
+}
+
+// getGrantStatements returns the statements granting or revoking the global privileges which the role attributes map onto.
+// The superuser has all privileges with the grant option, which include the privileges of the other attributes.
+func getGrantStatements(roleName string, current, desired *v1pb.DatabaseRoleAttribute) []string {
+	var statements []string
+	user := quoteIdentifier(roleName)
+	createDB, createRole := current.CreateDb, current.CreateRole
+	if desired.SuperUser != current.SuperUser {
+		if desired.SuperUser {
+			return append(statements, fmt.Sprintf("GRANT ALL ON *.* TO %s WITH GRANT OPTION", user))
+		}
+		statements = append(statements, fmt.Sprintf("REVOKE ALL ON *.* FROM %s", user))
+		createDB, createRole = false, false
+	} else if desired.SuperUser {
+		return nil
+	}
+	for _, privilege := range []struct {
+		privilege string
+		current   bool
+		desired   bool
+	}{
+		{privilege: "CREATE DATABASE", current: createDB, desired: desired.CreateDb},
+		{privilege: "CREATE USER, CREATE ROLE", current: createRole, desired: desired.CreateRole},
+	} {
+		switch {
+		case privilege.desired && !privilege.current:
+			statements = append(statements, fmt.Sprintf("GRANT %s ON *.* TO %s", privilege.privilege, user))
+		case !privilege.desired && privilege.current:
+			statements = append(statements, fmt.Sprintf("REVOKE %s ON *.* FROM %s", privilege.privilege, user))
+		}
+	}
+	return statements
+}
+
+func (driver *Driver) findRoleImpl(ctx context.Context, find *roleFind) ([]*v1pb.DatabaseRole, error) {
+	where, args := "", []interface{}{}
+	if v := find.Name; v != nil {
+		where, args = "WHERE name = ?", append(args, *v)
+	}
+	// The user created with HOST NONE has no allowed hosts.
+	statement := fmt.Sprintf(`
+		SELECT
+			name,
+			toUInt8(length(host_ip) + length(host_names) + length(host_names_regexp) + length(host_names_like) > 0)
+		FROM system.users
+		%s
+		ORDER BY name
+	`, where)
+	rows, err := driver.db.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, statement)
+	}
+	defer rows.Close()
+
+	var roleList []*v1pb.DatabaseRole
+	for rows.Next() {
+		var canLogin uint8
+		role := &v1pb.DatabaseRole{
+			ConnectionLimit: -1,
+			Attribute:       &v1pb.DatabaseRoleAttribute{},
+		}
+		if err := rows.Scan(&role.Name, &canLogin); err != nil {
+			return nil, util.FormatErrorWithQuery(err, statement)
+		}
+		role.Attribute.CanLogin = canLogin == 1
+		roleList = append(roleList, role)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to scan users")
+	}
+
+	for _, role := range roleList {
+		if err := driver.setRoleGrants(ctx, role); err != nil {
+			return nil, err
+		}
+		validUntil, err := driver.getValidUntil(ctx, role.Name)
+		if err != nil {
+			return nil, err
+		}
+		role.ValidUntil = validUntil
+	}
+	return roleList, nil
+}
+
+// setRoleGrants sets the role attributes from the global privileges of the user.
+func (driver *Driver) setRoleGrants(ctx context.Context, role *v1pb.DatabaseRole) error {
+	statement := `
+		SELECT
+			access_type,
+			grant_option
+		FROM system.grants
+		WHERE user_name = ? AND database IS NULL AND is_partial_revoke = 0
+	`
+	rows, err := driver.db.QueryContext(ctx, statement, role.Name)
+	if err != nil {
+		return util.FormatErrorWithQuery(err, statement)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var accessType string
+		var grantOption uint8
+		if err := rows.Scan(&accessType, &grantOption); err != nil {
+			return util.FormatErrorWithQuery(err, statement)
+		}
+		switch accessType {
+		case "ALL":
+			role.Attribute.SuperUser = grantOption == 1
+		case "CREATE DATABASE":
+			role.Attribute.CreateDb = true
+		case "CREATE ROLE", "ACCESS MANAGEMENT":
+			role.Attribute.CreateRole = true
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return errors.Wrapf(err, "failed to scan grants")
+	}
+	return nil
+}
+
+// getValidUntil gets the valid until timestamp from the CREATE USER statement, because system.users doesn't have it before ClickHouse 23.
+func (driver *Driver) getValidUntil(ctx context.Context, name string) (*string, error) {
+	statement := fmt.Sprintf("SHOW CREATE USER %s", quoteIdentifier(name))
+	var createStatement string
+	if err := driver.db.QueryRowContext(ctx, statement).Scan(&createStatement); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, common.FormatDBErrorEmptyRowWithQuery(statement)
+		}
+		return nil, util.FormatErrorWithQuery(err, statement)
+	}
+	return parseValidUntil(createStatement), nil
+}
+
+// parseValidUntil parses the VALID UNTIL clause of the CREATE USER statement, and returns the RFC 3339 timestamp if possible.
+func parseValidUntil(createStatement string) *string {
+	match := validUntilRegexp.FindStringSubmatch(createStatement)
+	if match == nil {
+		return nil
+	}
+	validUntil := match[1]
+	if t, err := time.Parse("2006-01-02 15:04:05", validUntil); err == nil {
+		validUntil = t.Format(time.RFC3339)
+	}
+	return &validUntil
+}
+
+func quoteIdentifier(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return fmt.Sprintf("`%s`", strings.ReplaceAll(s, "`", "\\`"))
+}
+
+func escapeString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return strings.ReplaceAll(s, "'", `\'`)
 }
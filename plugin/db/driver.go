@@ -10,6 +10,7 @@ import (
 	"regexp"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -39,6 +40,16 @@ const (
 	MongoDB Type = "MONGODB"
 	// Spanner is the database type for Spanner.
 	Spanner Type = "SPANNER"
+	// MSSQL is the database type for MSSQL.
+	MSSQL Type = "MSSQL"
+	// Oracle is the database type for Oracle.
+	Oracle Type = "ORACLE"
+	// Redis is the database type for Redis.
+	Redis Type = "REDIS"
+	// MariaDB is the database type for MariaDB.
+	MariaDB Type = "MARIADB"
+	// OceanBase is the database type for OceanBase.
+	OceanBase Type = "OCEANBASE"
 
 	// BytebaseDatabase is the database installed in the controlled database server.
 	BytebaseDatabase = "bytebase"
@@ -83,7 +94,7 @@ type DriverConfig struct {
 	DbBinDir string
 
 	// NOTE, introducing db specific fields is the last resort.
-	// MySQL specific
+	// The directory contains the MySQL binlog files, or the PostgreSQL WAL archive and base backups of the instance.
 	BinlogDir string
 }
 
@@ -364,6 +375,10 @@ type ConnectionConfig struct {
 	SRV bool
 	// AuthenticationDatabase is only supported for MongoDB now.
 	AuthenticationDatabase string
+	// ServiceName is only supported for Oracle now.
+	ServiceName string
+	// SSHConfig is the SSH tunnel to connect the database through, and it's not used if the host is empty.
+	SSHConfig SSHConfig
 }
 
 // ConnectionContext is the context for connection.
@@ -380,9 +395,19 @@ type QueryContext struct {
 	ReadOnly              bool
 	SensitiveDataMaskType SensitiveDataMaskType
 	SensitiveSchemaInfo   *SensitiveSchemaInfo
+	// MaskingSalt is the workspace salt for the HASH mask.
+	MaskingSalt string
 
 	// CurrentDatabase is for MySQL
 	CurrentDatabase string
+
+	// Timeout is the maximum execution time of the query. No timeout enforced if timeout <= 0.
+	Timeout time.Duration
+	// SessionHandler is called with the ID of the session running the query before the query starts.
+	// The session ID can be passed to QueryCanceler.CancelQuery to kill the query.
+	// The returned function is called after the query and before the session is released to the connection pool,
+	// it blocks until the running kill finishes, so the kill never hits another query reusing the session.
+	SessionHandler func(sessionID string) (release func())
 }
 
 // Driver is the interface for database driver.
@@ -441,6 +466,26 @@ type Driver interface {
 	Restore(ctx context.Context, src io.Reader) error
 }
 
+// RowHandler handles the query results streamed by StreamQuerier.
+type RowHandler interface {
+	// HandleColumns is called once with the column names and types before any row.
+	HandleColumns(names []string, types []string) error
+	// HandleRow is called for each row. The handler owns the row, and returning an error stops the query.
+	HandleRow(row []interface{}) error
+}
+
+// StreamQuerier is the driver which can stream the query results instead of holding all rows in memory.
+type StreamQuerier interface {
+	// QueryStream executes the readonly SELECT statement like Query, but passes the results to the handler row by row.
+	QueryStream(ctx context.Context, statement string, queryContext *QueryContext, handler RowHandler) error
+}
+
+// QueryCanceler is the driver which can kill the query running in another session.
+type QueryCanceler interface {
+	// CancelQuery kills the query running in the session with the engine-native command, and the session is kept.
+	CancelQuery(ctx context.Context, sessionID string) error
+}
+
 // Register makes a database driver available by the provided type.
 // If Register is called twice with the same name or if driver is nil,
 // it panics.
@@ -465,6 +510,18 @@ func Open(ctx context.Context, dbType Type, driverConfig DriverConfig, connectio
 		return nil, errors.Errorf("db: unknown driver %v", dbType)
 	}
 
+	if connectionConfig.SSHConfig.Host != "" {
+		if connectionConfig.SRV {
+			return nil, errors.New("SRV connection is not supported through the SSH tunnel")
+		}
+		// The drivers connect to the local end of the tunnel transparently.
+		host, port, err := openSSHTunnel(connectionConfig.SSHConfig, connectionConfig.Host, connectionConfig.Port)
+		if err != nil {
+			return nil, err
+		}
+		connectionConfig.Host, connectionConfig.Port = host, port
+	}
+
 	driver, err := f(driverConfig).Open(ctx, dbType, connectionConfig, connCtx)
 	if err != nil {
 		return nil, err
@@ -508,8 +565,32 @@ const (
 	// SensitiveDataMaskTypeDefault is the sensitive data type to hide data with a default method.
 	// The default method is subject to change.
 	SensitiveDataMaskTypeDefault SensitiveDataMaskType = "DEFAULT"
+	// SensitiveDataMaskTypeFull is the sensitive data type to hide the whole value.
+	SensitiveDataMaskTypeFull SensitiveDataMaskType = "FULL"
+	// SensitiveDataMaskTypePartial is the sensitive data type to hide the value except the first and the last characters.
+	SensitiveDataMaskTypePartial SensitiveDataMaskType = "PARTIAL"
+	// SensitiveDataMaskTypeHash is the sensitive data type to replace the value with its salted SHA-256 hash.
+	SensitiveDataMaskTypeHash SensitiveDataMaskType = "HASH"
+	// SensitiveDataMaskTypeRange is the sensitive data type to replace the number or the date with the range containing it.
+	SensitiveDataMaskTypeRange SensitiveDataMaskType = "RANGE"
+	// SensitiveDataMaskTypeEmail is the sensitive data type to hide the local part of the email address except the first character.
+	SensitiveDataMaskTypeEmail SensitiveDataMaskType = "EMAIL"
+	// SensitiveDataMaskTypePhone is the sensitive data type to hide the digits of the phone number except the last four.
+	SensitiveDataMaskTypePhone SensitiveDataMaskType = "PHONE"
 )
 
+// MaskAlgorithm is the algorithm to mask the sensitive data.
+type MaskAlgorithm struct {
+	Type SensitiveDataMaskType
+	// PrefixLength and SuffixLength are the numbers of the characters kept by the PARTIAL mask.
+	PrefixLength int
+	SuffixLength int
+	// RangeWidth is the width of the number ranges by the RANGE mask, such as 42 is masked as "[40, 50)" with the width 10.
+	RangeWidth float64
+	// RangeUnit is the unit of the date ranges by the RANGE mask, which is YEAR, MONTH or DAY.
+	RangeUnit string
+}
+
 // SensitiveSchemaInfo is the schema info using to extract sensitive fields.
 type SensitiveSchemaInfo struct {
 	DatabaseList []DatabaseSchema
@@ -519,6 +600,14 @@ type SensitiveSchemaInfo struct {
 type DatabaseSchema struct {
 	Name      string
 	TableList []TableSchema
+	// SchemaList is only used for Postgres, whose tables are in the schemas.
+	SchemaList []SchemaSchema
+}
+
+// SchemaSchema is the schema using to extract sensitive fields.
+type SchemaSchema struct {
+	Name      string
+	TableList []TableSchema
 }
 
 // TableSchema is the table schema using to extract sensitive fields.
@@ -531,10 +620,14 @@ type TableSchema struct {
 type ColumnInfo struct {
 	Name      string
 	Sensitive bool
+	// MaskAlgorithm is the algorithm to mask the sensitive column, nil means QueryContext.SensitiveDataMaskType.
+	MaskAlgorithm *MaskAlgorithm
 }
 
 // SensitiveField is the struct about SELECT fields.
 type SensitiveField struct {
 	Name      string
 	Sensitive bool
+	// MaskAlgorithm is the algorithm to mask the sensitive field, nil means QueryContext.SensitiveDataMaskType.
+	MaskAlgorithm *MaskAlgorithm
 }
@@ -0,0 +1,146 @@
+package mongodb
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/bytebase/bytebase/resources/mongoutil"
+)
+
+const (
+	// archiveMagicNumber is the little-endian magic number at the beginning of the mongodump archive.
+	archiveMagicNumber uint32 = 0x8199e26d
+	// archiveTerminator terminates the prelude and the namespace blocks of the archive.
+	archiveTerminator uint32 = 0xffffffff
+	// maxBSONDocumentSize is the maximum BSON document size allowed by MongoDB.
+	maxBSONDocumentSize = 16 * 1024 * 1024
+)
+
+// Dump dumps the database in the mongodump archive format.
+// MongoDB has no schema dump because the collections are schemaless, so it writes nothing if schemaOnly is true.
+func (driver *Driver) Dump(ctx context.Context, database string, out io.Writer, schemaOnly bool) (string, error) {
+	if schemaOnly {
+		return "", nil
+	}
+	connCfg := driver.connCfg
+	connCfg.Database = ""
+	args := []string{
+		"--uri",
+		getMongoDBConnectionURI(connCfg),
+		// Write the archive to stdout.
+		"--archive",
+	}
+	if database != "" {
+		args = append(args, "--db", database)
+	}
+	cmd := exec.CommandContext(ctx, mongoutil.GetMongodumpPath(driver.dbBinDir), args...)
+	var errContent bytes.Buffer
+	cmd.Stdout = out
+	cmd.Stderr = &errContent
+	if err := cmd.Run(); err != nil {
+		return "", errors.Wrapf(err, "failed to run mongodump: %s", errContent.String())
+	}
+	return "", nil
+}
+
+// Restore restores the mongodump archive read from src.
+// If the driver connects to a database, the archive of one database is restored into it even if the names are different.
+// The collections in the archive are dropped before restoring, so that the restore is repeatable.
+func (driver *Driver) Restore(ctx context.Context, src io.Reader) error {
+	prelude, databases, err := readArchivePrelude(src)
+	if err != nil {
+		return err
+	}
+	connCfg := driver.connCfg
+	connCfg.Database = ""
+	args := []string{
+		"--uri",
+		getMongoDBConnectionURI(connCfg),
+		// Read the archive from stdin.
+		"--archive",
+		"--drop",
+	}
+	if target := driver.connCfg.Database; target != "" {
+		if len(databases) > 1 {
+			return errors.Errorf("cannot restore the archive of %d databases into database %q", len(databases), target)
+		}
+		if len(databases) == 1 && databases[0] != target {
+			args = append(args,
+				"--nsFrom", escapeNamespace(databases[0])+".$collection$",
+				"--nsTo", escapeNamespace(target)+".$collection$",
+			)
+		}
+	}
+	cmd := exec.CommandContext(ctx, mongoutil.GetMongorestorePath(driver.dbBinDir), args...)
+	var errContent bytes.Buffer
+	cmd.Stdin = io.MultiReader(bytes.NewReader(prelude), src)
+	cmd.Stderr = &errContent
+	if err := cmd.Run(); err != nil {
+		return errors.Wrapf(err, "failed to run mongorestore: %s", errContent.String())
+	}
+	return nil
+}
+
+// readArchivePrelude reads the prelude of the mongodump archive, which is the magic number, the header document, the collection metadata documents and the terminator.
+// It returns the bytes read, so the caller can pass the whole archive to mongorestore, and the databases in the archive.
+func readArchivePrelude(r io.Reader) ([]byte, []string, error) {
+	var buf bytes.Buffer
+	tee := io.TeeReader(r, &buf)
+
+	var magic [4]byte
+	if _, err := io.ReadFull(tee, magic[:]); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to read the archive magic number")
+	}
+	if binary.LittleEndian.Uint32(magic[:]) != archiveMagicNumber {
+		return nil, nil, errors.New("the backup is not a mongodump archive")
+	}
+
+	var databases []string
+	seen := make(map[string]bool)
+	for isHeader := true; ; isHeader = false {
+		var sizeBytes [4]byte
+		if _, err := io.ReadFull(tee, sizeBytes[:]); err != nil {
+			return nil, nil, errors.Wrap(err, "failed to read the archive prelude")
+		}
+		size := binary.LittleEndian.Uint32(sizeBytes[:])
+		if size == archiveTerminator {
+			break
+		}
+		if size < 5 || size > maxBSONDocumentSize {
+			return nil, nil, errors.Errorf("invalid document size %d in the archive prelude", size)
+		}
+		doc := make([]byte, size)
+		copy(doc, sizeBytes[:])
+		if _, err := io.ReadFull(tee, doc[4:]); err != nil {
+			return nil, nil, errors.Wrap(err, "failed to read the archive prelude")
+		}
+		// The first document is the archive header with the tool and server versions.
+		if isHeader {
+			continue
+		}
+		var metadata struct {
+			Database string `bson:"db"`
+		}
+		if err := bson.Unmarshal(doc, &metadata); err != nil {
+			return nil, nil, errors.Wrap(err, "failed to unmarshal the collection metadata in the archive prelude")
+		}
+		if !seen[metadata.Database] {
+			seen[metadata.Database] = true
+			databases = append(databases, metadata.Database)
+		}
+	}
+	return buf.Bytes(), databases, nil
+}
+
+// escapeNamespace escapes the special characters of the namespace patterns used by --nsFrom and --nsTo.
+func escapeNamespace(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return strings.ReplaceAll(s, "$", `\$`)
+}
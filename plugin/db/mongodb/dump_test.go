@@ -0,0 +1,55 @@
+package mongodb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestReadArchivePrelude(t *testing.T) {
+	a := require.New(t)
+
+	var archive bytes.Buffer
+	writeUint32 := func(v uint32) {
+		var b [4]byte
+		binary.LittleEndian.PutUint32(b[:], v)
+		archive.Write(b[:])
+	}
+	writeDocument := func(doc bson.M) {
+		data, err := bson.Marshal(doc)
+		a.NoError(err)
+		archive.Write(data)
+	}
+	writeUint32(archiveMagicNumber)
+	writeDocument(bson.M{"concurrent_collections": int32(4), "version": "0.1", "server_version": "6.0.3", "tool_version": "100.6.1"})
+	writeDocument(bson.M{"db": "shop", "collection": "orders", "metadata": "{}", "size": int32(0), "type": "collection"})
+	writeDocument(bson.M{"db": "shop", "collection": "users", "metadata": "{}", "size": int32(0), "type": "collection"})
+	writeUint32(archiveTerminator)
+	body := []byte("namespace blocks")
+	archive.Write(body)
+	want := archive.Bytes()
+
+	r := bytes.NewReader(want)
+	prelude, databases, err := readArchivePrelude(r)
+	a.NoError(err)
+	a.Equal([]string{"shop"}, databases)
+	// The prelude and the rest of the reader make up the whole archive.
+	got, err := io.ReadAll(io.MultiReader(bytes.NewReader(prelude), r))
+	a.NoError(err)
+	a.Equal(want, got)
+
+	_, _, err = readArchivePrelude(bytes.NewReader([]byte("-- MySQL dump")))
+	a.ErrorContains(err, "not a mongodump archive")
+	_, _, err = readArchivePrelude(bytes.NewReader(want[:20]))
+	a.Error(err)
+}
+
+func TestEscapeNamespace(t *testing.T) {
+	a := require.New(t)
+	a.Equal("shop", escapeNamespace("shop"))
+	a.Equal(`a\$b\\c`, escapeNamespace(`a$b\c`))
+}
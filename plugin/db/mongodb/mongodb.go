@@ -6,7 +6,6 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"io"
 	"os"
 	"os/exec"
 	"strings"
@@ -125,7 +124,27 @@ func (driver *Driver) Execute(_ context.Context, statement string, _ bool) (int6
 }
 
 // Query queries a statement.
-func (driver *Driver) Query(ctx context.Context, statement string, _ *db.QueryContext) ([]interface{}, error) {
+// The find, aggregate and countDocuments statements are run through the Go driver, and the other statements are executed in mongosh unless the query is read-only.
+func (driver *Driver) Query(ctx context.Context, statement string, queryContext *db.QueryContext) ([]interface{}, error) {
+	if queryContext != nil && queryContext.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, queryContext.Timeout)
+		defer cancel()
+	}
+	query, err := parseMongoQuery(statement)
+	if err == nil && (query.method == findMethod || query.method == aggregateMethod || query.method == countDocumentsMethod) {
+		return driver.queryNative(ctx, query, queryContext)
+	}
+	if queryContext != nil && queryContext.ReadOnly {
+		if err == nil && writeMethods[query.method] {
+			return nil, errors.Errorf("write operation %q is not allowed in the read-only query", query.method)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "only db.<collection>.find(), aggregate() and countDocuments() are supported in the read-only query")
+		}
+		return nil, errors.Errorf("method %q is not supported in the read-only query, only find(), aggregate() and countDocuments() are supported", query.method)
+	}
+
 	connectionURI := getMongoDBConnectionURI(driver.connCfg)
 	// For MongoDB query, we execute the statement in mongosh with flag --eval for the following reasons:
 	// 1. Query always short, so it's safe to execute in the command line.
@@ -152,16 +171,6 @@ func (driver *Driver) Query(ctx context.Context, statement string, _ *db.QueryCo
 	return []interface{}{field, types, rows}, nil
 }
 
-// Dump dumps the database.
-func (*Driver) Dump(_ context.Context, _ string, _ io.Writer, _ bool) (string, error) {
-	return "", nil
-}
-
-// Restore restores the backup read from src.
-func (*Driver) Restore(_ context.Context, _ io.Reader) error {
-	panic("not implemented")
-}
-
 // getMongoDBConnectionURI returns the MongoDB connection URI.
 // https://www.mongodb.com/docs/manual/reference/connection-string/
 func getMongoDBConnectionURI(connConfig db.ConnectionConfig) string {
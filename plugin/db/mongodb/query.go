@@ -0,0 +1,831 @@
+package mongodb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/db/util"
+)
+
+const (
+	findMethod           = "find"
+	aggregateMethod      = "aggregate"
+	countDocumentsMethod = "countDocuments"
+)
+
+// mongoQuery is the query parsed from the mongosh statement "db.<collection>.<method>(<arguments>)".
+type mongoQuery struct {
+	collection string
+	method     string
+	arguments  bson.A
+
+	// The cursor methods chained after find().
+	limit int64
+	skip  int64
+	sort  interface{}
+}
+
+// writeMethods are the collection methods modifying the data or the schema.
+var writeMethods = map[string]bool{
+	"insert":            true,
+	"insertOne":         true,
+	"insertMany":        true,
+	"update":            true,
+	"updateOne":         true,
+	"updateMany":        true,
+	"replaceOne":        true,
+	"remove":            true,
+	"deleteOne":         true,
+	"deleteMany":        true,
+	"findAndModify":     true,
+	"findOneAndDelete":  true,
+	"findOneAndReplace": true,
+	"findOneAndUpdate":  true,
+	"bulkWrite":         true,
+	"drop":              true,
+	"renameCollection":  true,
+	"createIndex":       true,
+	"createIndexes":     true,
+	"dropIndex":         true,
+	"dropIndexes":       true,
+}
+
+// parseMongoQuery parses the find, aggregate and countDocuments statements of mongosh.
+// The arguments are shell object literals, which are converted to the extended JSON before unmarshalling.
+func parseMongoQuery(statement string) (*mongoQuery, error) {
+	s := &shellScanner{s: strings.TrimSpace(statement)}
+	if s.identifier() != "db" || !s.consume('.') {
+		return nil, errors.Errorf("statement must start with \"db.\"")
+	}
+	query := &mongoQuery{}
+	name := s.identifier()
+	if name == "getCollection" {
+		if !s.consume('(') {
+			return nil, errors.Errorf("expect \"(\" after getCollection")
+		}
+		s.skipSpaces()
+		collection, err := s.stringLiteral()
+		if err != nil {
+			return nil, err
+		}
+		if !s.consume(')') {
+			return nil, errors.Errorf("expect \")\" after the collection name")
+		}
+		query.collection = collection
+	} else {
+		query.collection = name
+	}
+	if query.collection == "" || !s.consume('.') {
+		return nil, errors.Errorf("statement must be \"db.<collection>.<method>(...)\"")
+	}
+
+	method, arguments, err := s.methodCall()
+	if err != nil {
+		return nil, err
+	}
+	query.method, query.arguments = method, arguments
+	for s.consume('.') {
+		cursorMethod, cursorArguments, err := s.methodCall()
+		if err != nil {
+			return nil, err
+		}
+		if query.method != findMethod {
+			return nil, errors.Errorf("cursor method %q is only supported after find()", cursorMethod)
+		}
+		switch cursorMethod {
+		case "limit":
+			if query.limit, err = getInt64Argument(cursorMethod, cursorArguments); err != nil {
+				return nil, err
+			}
+		case "skip":
+			if query.skip, err = getInt64Argument(cursorMethod, cursorArguments); err != nil {
+				return nil, err
+			}
+		case "sort":
+			if len(cursorArguments) != 1 {
+				return nil, errors.Errorf("sort() expects one argument")
+			}
+			query.sort = cursorArguments[0]
+		case "pretty", "toArray":
+		default:
+			return nil, errors.Errorf("unsupported cursor method %q", cursorMethod)
+		}
+	}
+	s.consume(';')
+	s.skipSpaces()
+	if !s.eof() {
+		return nil, errors.Errorf("unexpected %q at position %d, only one statement is supported", s.s[s.pos:], s.pos)
+	}
+	return query, nil
+}
+
+func getInt64Argument(method string, arguments bson.A) (int64, error) {
+	if len(arguments) == 1 {
+		switch v := arguments[0].(type) {
+		case int32:
+			return int64(v), nil
+		case int64:
+			return v, nil
+		}
+	}
+	return 0, errors.Errorf("%s() expects one integer argument", method)
+}
+
+// isReadOnlyPipeline returns false if the aggregation pipeline writes the result to a collection.
+func isReadOnlyPipeline(pipeline bson.A) bool {
+	for _, stage := range pipeline {
+		if d, ok := stage.(bson.D); ok {
+			for _, e := range d {
+				if e.Key == "$out" || e.Key == "$merge" {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// maskPreservingStageMap is the aggregation stages which never output the field values under the other field names.
+// The other stages, such as $project with expressions, $addFields, $group and $lookup, may rename, compute or join the fields,
+// so the sensitive values can't be masked by the field names.
+var maskPreservingStageMap = map[string]bool{
+	"$match":  true,
+	"$sort":   true,
+	"$limit":  true,
+	"$skip":   true,
+	"$sample": true,
+	"$count":  true,
+	"$unwind": true,
+	"$unset":  true,
+	// $project is allowed with the inclusion and exclusion only.
+	"$project": true,
+}
+
+// ValidateReadOnlyQuery returns an error if the statement isn't a read-only find, aggregate or countDocuments query supported by the SQL editor.
+func ValidateReadOnlyQuery(statement string) error {
+	query, err := parseMongoQuery(statement)
+	if err != nil {
+		return err
+	}
+	switch query.method {
+	case findMethod, countDocumentsMethod:
+		return nil
+	case aggregateMethod:
+		if len(query.arguments) > 0 {
+			if pipeline, ok := query.arguments[0].(bson.A); ok && !isReadOnlyPipeline(pipeline) {
+				return errors.Errorf("$out and $merge stages are not allowed in the read-only query")
+			}
+		}
+		return nil
+	default:
+		return errors.Errorf("method %q is not supported in the read-only query, only find(), aggregate() and countDocuments() are supported", query.method)
+	}
+}
+
+// checkSensitiveQuery returns an error if the query may output the values derived from the fields under the other field names.
+// The sensitive fields are masked by the output field names, so such queries are rejected on the database with the sensitive data,
+// including the joins from the other collections.
+func checkSensitiveQuery(query *mongoQuery) error {
+	switch query.method {
+	case findMethod:
+		if len(query.arguments) > 1 {
+			return checkSensitiveProjection(query.arguments[1])
+		}
+	case aggregateMethod:
+		if len(query.arguments) == 0 {
+			return errors.Errorf("aggregate() expects the pipeline argument")
+		}
+		pipeline, ok := query.arguments[0].(bson.A)
+		if !ok {
+			return errors.Errorf("the pipeline of aggregate() must be an array")
+		}
+		for _, stage := range pipeline {
+			d, ok := stage.(bson.D)
+			if !ok || len(d) != 1 {
+				return errors.Errorf("each stage of the pipeline must be a document with one field")
+			}
+			name := d[0].Key
+			if !maskPreservingStageMap[name] {
+				return errors.Errorf("stage %q is not allowed because the database has sensitive data, which may be output under the other field names", name)
+			}
+			if name == "$project" {
+				if err := checkSensitiveProjection(d[0].Value); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// checkSensitiveProjection returns an error if the projection has the expressions other than the inclusion and exclusion.
+func checkSensitiveProjection(projection interface{}) error {
+	d, ok := projection.(bson.D)
+	if !ok {
+		return errors.Errorf("the projection must be a document")
+	}
+	for _, e := range d {
+		switch e.Value.(type) {
+		case int32, int64, float64, bool:
+		default:
+			return errors.Errorf("the projection of field %q only supports the inclusion and exclusion because the database has sensitive data", e.Key)
+		}
+	}
+	return nil
+}
+
+// hasSensitiveData returns true if any collection of the database has the sensitive fields.
+func hasSensitiveData(schemaInfo *db.SensitiveSchemaInfo, databaseName string) bool {
+	if schemaInfo == nil {
+		return false
+	}
+	for _, database := range schemaInfo.DatabaseList {
+		if database.Name != databaseName {
+			continue
+		}
+		for _, table := range database.TableList {
+			for _, column := range table.ColumnList {
+				if column.Sensitive {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// queryNative runs the parsed query through the Go driver and returns the documents as tabular rows.
+func (driver *Driver) queryNative(ctx context.Context, query *mongoQuery, queryContext *db.QueryContext) ([]interface{}, error) {
+	databaseName := driver.connCfg.Database
+	if databaseName == "" {
+		return nil, errors.Errorf("database must be specified for the MongoDB query")
+	}
+	if queryContext != nil && hasSensitiveData(queryContext.SensitiveSchemaInfo, databaseName) {
+		if err := checkSensitiveQuery(query); err != nil {
+			return nil, err
+		}
+	}
+	collection := driver.client.Database(databaseName).Collection(query.collection)
+
+	var limit int64
+	if queryContext != nil && queryContext.Limit > 0 {
+		limit = int64(queryContext.Limit)
+	}
+	// The server stops the operation exceeding maxTimeMS, the context deadline only stops waiting on the client side.
+	var maxTime time.Duration
+	if queryContext != nil && queryContext.Timeout > 0 {
+		maxTime = queryContext.Timeout
+	}
+	var docs []bson.D
+	switch query.method {
+	case findMethod:
+		if len(query.arguments) > 2 {
+			return nil, errors.Errorf("find() expects at most two arguments")
+		}
+		filter, err := getDocumentArgument(query.arguments, 0)
+		if err != nil {
+			return nil, err
+		}
+		opts := options.Find()
+		if maxTime > 0 {
+			opts.SetMaxTime(maxTime)
+		}
+		if len(query.arguments) > 1 {
+			opts.SetProjection(query.arguments[1])
+		}
+		if query.sort != nil {
+			opts.SetSort(query.sort)
+		}
+		if query.skip > 0 {
+			opts.SetSkip(query.skip)
+		}
+		if query.limit > 0 && (limit <= 0 || query.limit < limit) {
+			limit = query.limit
+		}
+		if limit > 0 {
+			opts.SetLimit(limit)
+		}
+		cursor, err := collection.Find(ctx, filter, opts)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to find documents in collection %q", query.collection)
+		}
+		if err := cursor.All(ctx, &docs); err != nil {
+			return nil, errors.Wrapf(err, "failed to read documents in collection %q", query.collection)
+		}
+	case aggregateMethod:
+		if len(query.arguments) == 0 {
+			return nil, errors.Errorf("aggregate() expects the pipeline argument")
+		}
+		pipeline, ok := query.arguments[0].(bson.A)
+		if !ok {
+			return nil, errors.Errorf("the pipeline of aggregate() must be an array")
+		}
+		if queryContext != nil && queryContext.ReadOnly && !isReadOnlyPipeline(pipeline) {
+			return nil, errors.Errorf("$out and $merge stages are not allowed in the read-only query")
+		}
+		if limit > 0 {
+			pipeline = append(pipeline, bson.D{{Key: "$limit", Value: limit}})
+		}
+		opts := options.Aggregate()
+		if maxTime > 0 {
+			opts.SetMaxTime(maxTime)
+		}
+		cursor, err := collection.Aggregate(ctx, pipeline, opts)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to aggregate collection %q", query.collection)
+		}
+		if err := cursor.All(ctx, &docs); err != nil {
+			return nil, errors.Wrapf(err, "failed to read aggregation result of collection %q", query.collection)
+		}
+	case countDocumentsMethod:
+		filter, err := getDocumentArgument(query.arguments, 0)
+		if err != nil {
+			return nil, err
+		}
+		opts := options.Count()
+		if maxTime > 0 {
+			opts.SetMaxTime(maxTime)
+		}
+		count, err := collection.CountDocuments(ctx, filter, opts)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to count documents in collection %q", query.collection)
+		}
+		return []interface{}{[]string{"count"}, []string{"INT64"}, [][]interface{}{{count}}}, nil
+	default:
+		return nil, errors.Errorf("unsupported method %q", query.method)
+	}
+
+	var sensitiveFields map[string]*db.MaskAlgorithm
+	masker := util.NewSensitiveDataMasker(&db.QueryContext{})
+	if queryContext != nil {
+		sensitiveFields = getSensitiveFields(queryContext.SensitiveSchemaInfo, databaseName, query.collection)
+		masker = util.NewSensitiveDataMasker(queryContext)
+	}
+	fields, types, rows := convertDocumentsToRowSet(docs, sensitiveFields, masker)
+	return []interface{}{fields, types, rows}, nil
+}
+
+func getDocumentArgument(arguments bson.A, i int) (interface{}, error) {
+	if len(arguments) <= i {
+		return bson.D{}, nil
+	}
+	if _, ok := arguments[i].(bson.D); !ok {
+		return nil, errors.Errorf("argument %d must be a document", i+1)
+	}
+	return arguments[i], nil
+}
+
+// getSensitiveFields returns the sensitive top-level fields of the collection and their mask algorithms, nil means the default one.
+// A sensitive nested field such as "address.city" masks its top-level field "address" with the default algorithm,
+// because the algorithm for the nested field doesn't apply to the whole document.
+func getSensitiveFields(schemaInfo *db.SensitiveSchemaInfo, databaseName, collection string) map[string]*db.MaskAlgorithm {
+	if schemaInfo == nil {
+		return nil
+	}
+	fields := make(map[string]*db.MaskAlgorithm)
+	for _, database := range schemaInfo.DatabaseList {
+		if database.Name != databaseName {
+			continue
+		}
+		for _, table := range database.TableList {
+			if table.Name != collection {
+				continue
+			}
+			for _, column := range table.ColumnList {
+				if !column.Sensitive {
+					continue
+				}
+				field := strings.Split(column.Name, ".")[0]
+				algorithm := column.MaskAlgorithm
+				if field != column.Name {
+					algorithm = nil
+				}
+				if current, ok := fields[field]; ok && (current == nil || algorithm == nil || *current != *algorithm) {
+					algorithm = nil
+				}
+				fields[field] = algorithm
+			}
+		}
+	}
+	return fields
+}
+
+// convertDocumentsToRowSet converts the documents to rows, the columns are the top-level fields in the order of appearance.
+// The column type is inferred from the first non-null value, and the sensitive fields are masked by the masker.
+func convertDocumentsToRowSet(docs []bson.D, sensitiveFields map[string]*db.MaskAlgorithm, masker *util.SensitiveDataMasker) ([]string, []string, [][]interface{}) {
+	fields := []string{}
+	types := []string{}
+	fieldIndex := make(map[string]int)
+	for _, doc := range docs {
+		for _, e := range doc {
+			if _, ok := fieldIndex[e.Key]; !ok {
+				fieldIndex[e.Key] = len(fields)
+				fields = append(fields, e.Key)
+				types = append(types, "NULL")
+			}
+		}
+	}
+
+	rows := [][]interface{}{}
+	for _, doc := range docs {
+		row := make([]interface{}, len(fields))
+		for _, e := range doc {
+			i := fieldIndex[e.Key]
+			value, valueType := convertValue(e.Value)
+			if types[i] == "NULL" {
+				types[i] = valueType
+			}
+			row[i] = value
+		}
+		for i, field := range fields {
+			if algorithm, ok := sensitiveFields[field]; ok {
+				row[i] = masker.Mask(row[i], algorithm)
+			}
+		}
+		rows = append(rows, row)
+	}
+	return fields, types, rows
+}
+
+// convertValue converts the BSON value to the JSON value of the result, the documents and the arrays are converted to relaxed extended JSON.
+func convertValue(v interface{}) (interface{}, string) {
+	switch v := v.(type) {
+	case nil:
+		return nil, "NULL"
+	case string:
+		return v, "STRING"
+	case bool:
+		return v, "BOOL"
+	case int32:
+		return v, "INT32"
+	case int64:
+		return v, "INT64"
+	case float64:
+		return v, "DOUBLE"
+	case primitive.ObjectID:
+		return v.Hex(), "OBJECTID"
+	case primitive.DateTime:
+		return v.Time().UTC().Format(time.RFC3339Nano), "DATE"
+	case primitive.Decimal128:
+		return v.String(), "DECIMAL128"
+	case bson.D:
+		return marshalExtJSONValue(v), "DOCUMENT"
+	case bson.A:
+		return marshalExtJSONValue(v), "ARRAY"
+	default:
+		return marshalExtJSONValue(v), "OTHER"
+	}
+}
+
+// marshalExtJSONValue marshals the value in a wrapping document, because only the documents can be marshaled to extended JSON.
+func marshalExtJSONValue(v interface{}) string {
+	data, err := bson.MarshalExtJSON(bson.D{{Key: "v", Value: v}}, false /* canonical */, false /* escapeHTML */)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	s := strings.TrimSpace(string(data))
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(s, `{"v":`), "}"))
+}
+
+// shellScanner scans the mongosh statement.
+type shellScanner struct {
+	s   string
+	pos int
+}
+
+func (s *shellScanner) eof() bool {
+	return s.pos >= len(s.s)
+}
+
+func (s *shellScanner) skipSpaces() {
+	for !s.eof() {
+		switch s.s[s.pos] {
+		case ' ', '\t', '\n', '\r':
+			s.pos++
+		default:
+			return
+		}
+	}
+}
+
+// consume consumes the character after the spaces if it matches.
+func (s *shellScanner) consume(c byte) bool {
+	s.skipSpaces()
+	if !s.eof() && s.s[s.pos] == c {
+		s.pos++
+		return true
+	}
+	return false
+}
+
+func (s *shellScanner) peek() byte {
+	s.skipSpaces()
+	if s.eof() {
+		return 0
+	}
+	return s.s[s.pos]
+}
+
+func isIdentifierStart(c byte) bool {
+	return c == '_' || c == '$' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func isIdentifierPart(c byte) bool {
+	return isIdentifierStart(c) || ('0' <= c && c <= '9')
+}
+
+func (s *shellScanner) identifier() string {
+	s.skipSpaces()
+	start := s.pos
+	if s.eof() || !isIdentifierStart(s.s[s.pos]) {
+		return ""
+	}
+	for !s.eof() && isIdentifierPart(s.s[s.pos]) {
+		s.pos++
+	}
+	return s.s[start:s.pos]
+}
+
+// methodCall scans the method call "<method>(<arguments>)" and unmarshals the arguments.
+func (s *shellScanner) methodCall() (string, bson.A, error) {
+	method := s.identifier()
+	if method == "" {
+		return "", nil, errors.Errorf("expect the method name at position %d", s.pos)
+	}
+	if !s.consume('(') {
+		return "", nil, errors.Errorf("expect \"(\" after %q", method)
+	}
+	arguments, err := s.convert(')')
+	if err != nil {
+		return "", nil, err
+	}
+	var result struct {
+		Arguments bson.A `bson:"arguments"`
+	}
+	if err := bson.UnmarshalExtJSON([]byte(fmt.Sprintf(`{"arguments":[%s]}`, arguments)), false /* canonical */, &result); err != nil {
+		return "", nil, errors.Wrapf(err, "failed to parse the arguments of %s()", method)
+	}
+	return method, result.Arguments, nil
+}
+
+// stringLiteral scans the single-quoted or double-quoted string literal.
+func (s *shellScanner) stringLiteral() (string, error) {
+	if s.eof() || (s.s[s.pos] != '"' && s.s[s.pos] != '\'') {
+		return "", errors.Errorf("expect a string at position %d", s.pos)
+	}
+	quote := s.s[s.pos]
+	s.pos++
+	var sb strings.Builder
+	for !s.eof() {
+		c := s.s[s.pos]
+		switch {
+		case c == quote:
+			s.pos++
+			return sb.String(), nil
+		case c == '\\' && s.pos+1 < len(s.s):
+			s.pos++
+			switch e := s.s[s.pos]; e {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			case 'r':
+				sb.WriteByte('\r')
+			case 'u':
+				if s.pos+4 >= len(s.s) {
+					return "", errors.Errorf("invalid unicode escape at position %d", s.pos)
+				}
+				r, err := strconv.ParseUint(s.s[s.pos+1:s.pos+5], 16, 32)
+				if err != nil {
+					return "", errors.Errorf("invalid unicode escape at position %d", s.pos)
+				}
+				sb.WriteRune(rune(r))
+				s.pos += 4
+			default:
+				sb.WriteByte(e)
+			}
+			s.pos++
+		default:
+			sb.WriteByte(c)
+			s.pos++
+		}
+	}
+	return "", errors.Errorf("unterminated string")
+}
+
+// convert converts the shell object literals to the extended JSON until the closing character at the top level.
+// The keys may be unquoted, the strings may be single-quoted, and the trailing commas are allowed.
+func (s *shellScanner) convert(closing byte) (string, error) {
+	var sb strings.Builder
+	depth := 0
+	trimTrailingComma := func() {
+		if str := sb.String(); strings.HasSuffix(str, ",") {
+			sb.Reset()
+			sb.WriteString(str[:len(str)-1])
+		}
+	}
+	for {
+		c := s.peek()
+		switch {
+		case s.eof():
+			return "", errors.Errorf("expect %q before the end of the statement", closing)
+		case c == closing && depth == 0:
+			s.pos++
+			trimTrailingComma()
+			return sb.String(), nil
+		case c == '{' || c == '[':
+			depth++
+			sb.WriteByte(c)
+			s.pos++
+		case c == '}' || c == ']':
+			depth--
+			if depth < 0 {
+				return "", errors.Errorf("unexpected %q at position %d", c, s.pos)
+			}
+			trimTrailingComma()
+			sb.WriteByte(c)
+			s.pos++
+		case c == ':' || c == ',':
+			sb.WriteByte(c)
+			s.pos++
+		case c == '"' || c == '\'':
+			str, err := s.stringLiteral()
+			if err != nil {
+				return "", err
+			}
+			writeJSONString(&sb, str)
+		case c == '/':
+			if err := s.convertRegex(&sb); err != nil {
+				return "", err
+			}
+		case c == '-' || c == '+' || c == '.' || ('0' <= c && c <= '9'):
+			number, err := s.number()
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(number)
+		case isIdentifierStart(c):
+			name := s.identifier()
+			switch {
+			case s.peek() == ':':
+				writeJSONString(&sb, name)
+			case name == "true" || name == "false" || name == "null":
+				sb.WriteString(name)
+			case name == "undefined":
+				sb.WriteString("null")
+			case name == "new":
+			default:
+				if err := s.convertConstructor(&sb, name); err != nil {
+					return "", err
+				}
+			}
+		default:
+			r, _ := utf8.DecodeRuneInString(s.s[s.pos:])
+			return "", errors.Errorf("unexpected %q at position %d", r, s.pos)
+		}
+	}
+}
+
+func (s *shellScanner) number() (string, error) {
+	start := s.pos
+	for !s.eof() && strings.IndexByte("+-.0123456789eE", s.s[s.pos]) >= 0 {
+		s.pos++
+	}
+	number := s.s[start:s.pos]
+	if _, err := strconv.ParseFloat(number, 64); err != nil {
+		return "", errors.Errorf("invalid number %q at position %d", number, start)
+	}
+	number = strings.TrimPrefix(number, "+")
+	if strings.HasPrefix(number, "-.") {
+		number = "-0" + number[1:]
+	} else if strings.HasPrefix(number, ".") {
+		number = "0" + number
+	}
+	return number, nil
+}
+
+// convertRegex converts the regular expression literal "/pattern/flags".
+func (s *shellScanner) convertRegex(sb *strings.Builder) error {
+	start := s.pos
+	s.pos++
+	var pattern strings.Builder
+	for {
+		if s.eof() {
+			return errors.Errorf("unterminated regular expression at position %d", start)
+		}
+		c := s.s[s.pos]
+		if c == '/' {
+			s.pos++
+			break
+		}
+		if c == '\\' && s.pos+1 < len(s.s) {
+			pattern.WriteByte(c)
+			s.pos++
+			c = s.s[s.pos]
+		}
+		pattern.WriteByte(c)
+		s.pos++
+	}
+	flagStart := s.pos
+	for !s.eof() && isIdentifierPart(s.s[s.pos]) {
+		s.pos++
+	}
+	flags := []byte(s.s[flagStart:s.pos])
+	sort.Slice(flags, func(i, j int) bool { return flags[i] < flags[j] })
+
+	sb.WriteString(`{"$regularExpression":{"pattern":`)
+	writeJSONString(sb, pattern.String())
+	sb.WriteString(`,"options":`)
+	writeJSONString(sb, string(flags))
+	sb.WriteString("}}")
+	return nil
+}
+
+// convertConstructor converts the shell constructors such as ObjectId("...") and ISODate("...") to the extended JSON.
+func (s *shellScanner) convertConstructor(sb *strings.Builder, name string) error {
+	if !s.consume('(') {
+		return errors.Errorf("unsupported identifier %q", name)
+	}
+	var argument string
+	hasArgument := false
+	switch c := s.peek(); {
+	case c == ')':
+	case c == '"' || c == '\'':
+		str, err := s.stringLiteral()
+		if err != nil {
+			return err
+		}
+		argument, hasArgument = str, true
+	default:
+		number, err := s.number()
+		if err != nil {
+			return err
+		}
+		argument, hasArgument = number, true
+	}
+	if !s.consume(')') {
+		return errors.Errorf("expect \")\" after the argument of %s", name)
+	}
+
+	switch name {
+	case "ObjectId":
+		if !hasArgument {
+			argument = primitive.NewObjectID().Hex()
+		}
+		if _, err := primitive.ObjectIDFromHex(argument); err != nil {
+			return errors.Errorf("invalid ObjectId %q", argument)
+		}
+		sb.WriteString(`{"$oid":`)
+	case "ISODate", "Date":
+		t := time.Now()
+		if hasArgument {
+			var err error
+			if t, err = parseDate(argument); err != nil {
+				return err
+			}
+		}
+		sb.WriteString(fmt.Sprintf(`{"$date":{"$numberLong":"%d"}}`, t.UnixMilli()))
+		return nil
+	case "NumberLong", "Long":
+		sb.WriteString(`{"$numberLong":`)
+	case "NumberInt", "Int32":
+		sb.WriteString(`{"$numberInt":`)
+	case "NumberDecimal", "Decimal128":
+		sb.WriteString(`{"$numberDecimal":`)
+	default:
+		return errors.Errorf("unsupported function %q", name)
+	}
+	if !hasArgument {
+		return errors.Errorf("%s expects one argument", name)
+	}
+	writeJSONString(sb, argument)
+	sb.WriteString("}")
+	return nil
+}
+
+func parseDate(s string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", "2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.Errorf("invalid date %q", s)
+}
+
+func writeJSONString(sb *strings.Builder, s string) {
+	// Marshaling a string never fails.
+	data, _ := json.Marshal(s)
+	sb.Write(data)
+}
@@ -0,0 +1,226 @@
+package mongodb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/db/util"
+)
+
+func TestParseMongoQuery(t *testing.T) {
+	objectID, err := primitive.ObjectIDFromHex("63b5a5e1d2f1a2b3c4d5e6f7")
+	require.NoError(t, err)
+	tests := []struct {
+		statement string
+		want      *mongoQuery
+	}{
+		{
+			statement: "db.users.find()",
+			want:      &mongoQuery{collection: "users", method: findMethod},
+		},
+		{
+			statement: `db.users.find({ name: 'alice', age: { $gte: 18, }, "tags": ["a", "b"] }, { _id: 0 });`,
+			want: &mongoQuery{
+				collection: "users",
+				method:     findMethod,
+				arguments: bson.A{
+					bson.D{
+						{Key: "name", Value: "alice"},
+						{Key: "age", Value: bson.D{{Key: "$gte", Value: int32(18)}}},
+						{Key: "tags", Value: bson.A{"a", "b"}},
+					},
+					bson.D{{Key: "_id", Value: int32(0)}},
+				},
+			},
+		},
+		{
+			statement: `db.getCollection("order.items").find({ _id: ObjectId("63b5a5e1d2f1a2b3c4d5e6f7"), price: -.5 }).sort({ price: -1 }).skip(10).limit(5)`,
+			want: &mongoQuery{
+				collection: "order.items",
+				method:     findMethod,
+				arguments: bson.A{
+					bson.D{
+						{Key: "_id", Value: objectID},
+						{Key: "price", Value: -0.5},
+					},
+				},
+				limit: 5,
+				skip:  10,
+				sort:  bson.D{{Key: "price", Value: int32(-1)}},
+			},
+		},
+		{
+			statement: `db.events.countDocuments({ at: { $lt: ISODate("2023-01-02T00:00:00Z") }, name: /^sign\/in/i, count: NumberLong("9007199254740993") })`,
+			want: &mongoQuery{
+				collection: "events",
+				method:     countDocumentsMethod,
+				arguments: bson.A{
+					bson.D{
+						{Key: "at", Value: bson.D{{Key: "$lt", Value: primitive.NewDateTimeFromTime(time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC))}}},
+						{Key: "name", Value: primitive.Regex{Pattern: `^sign\/in`, Options: "i"}},
+						{Key: "count", Value: int64(9007199254740993)},
+					},
+				},
+			},
+		},
+		{
+			statement: `db.orders.aggregate([{ $match: { status: "A" } }, { $group: { _id: "$cust_id", total: { $sum: "$amount" } } }])`,
+			want: &mongoQuery{
+				collection: "orders",
+				method:     aggregateMethod,
+				arguments: bson.A{
+					bson.A{
+						bson.D{{Key: "$match", Value: bson.D{{Key: "status", Value: "A"}}}},
+						bson.D{{Key: "$group", Value: bson.D{
+							{Key: "_id", Value: "$cust_id"},
+							{Key: "total", Value: bson.D{{Key: "$sum", Value: "$amount"}}},
+						}}},
+					},
+				},
+			},
+		},
+		{
+			statement: `db.users.deleteMany({})`,
+			want: &mongoQuery{
+				collection: "users",
+				method:     "deleteMany",
+				arguments:  bson.A{bson.D{}},
+			},
+		},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		got, err := parseMongoQuery(test.statement)
+		a.NoError(err, test.statement)
+		if test.want.arguments == nil {
+			test.want.arguments = bson.A{}
+		}
+		a.Equal(test.want, got, test.statement)
+	}
+
+	for _, statement := range []string{
+		"show dbs",
+		"db.users.find({})\ndb.users.drop()",
+		"db.users.find({ name: 'alice' ",
+		"db.users.find({ $where: function() { return true } })",
+		"db.users.aggregate([]).limit(1)",
+		"db.users.find().forEach(printjson)",
+	} {
+		_, err := parseMongoQuery(statement)
+		a.Error(err, statement)
+	}
+}
+
+func TestIsReadOnlyPipeline(t *testing.T) {
+	a := require.New(t)
+	query, err := parseMongoQuery(`db.orders.aggregate([{ $match: {} }, { $out: "archive" }])`)
+	a.NoError(err)
+	a.False(isReadOnlyPipeline(query.arguments[0].(bson.A)))
+	query, err = parseMongoQuery(`db.orders.aggregate([{ $match: { $out: 1 } }])`)
+	a.NoError(err)
+	a.True(isReadOnlyPipeline(query.arguments[0].(bson.A)))
+}
+
+func TestCheckSensitiveQuery(t *testing.T) {
+	a := require.New(t)
+	schemaInfo := &db.SensitiveSchemaInfo{
+		DatabaseList: []db.DatabaseSchema{
+			{
+				Name: "shop",
+				TableList: []db.TableSchema{
+					{Name: "users", ColumnList: []db.ColumnInfo{{Name: "ssn", Sensitive: true}}},
+				},
+			},
+		},
+	}
+	a.True(hasSensitiveData(schemaInfo, "shop"))
+	a.False(hasSensitiveData(schemaInfo, "blog"))
+	a.False(hasSensitiveData(nil, "shop"))
+
+	for _, statement := range []string{
+		`db.users.find({ ssn: "1" }, { name: 1, ssn: 1, _id: 0 })`,
+		`db.users.find().limit(1)`,
+		`db.users.aggregate([{ $match: { age: { $gt: 18 } } }, { $project: { ssn: 1, "address.city": true } }, { $sort: { age: -1 } }, { $unwind: "$tags" }, { $count: "total" }])`,
+		`db.users.countDocuments({ ssn: "1" })`,
+	} {
+		query, err := parseMongoQuery(statement)
+		a.NoError(err, statement)
+		a.NoError(checkSensitiveQuery(query), statement)
+	}
+
+	for _, statement := range []string{
+		// The sensitive field is renamed, computed or joined under the other field names.
+		`db.users.find({}, { x: "$ssn" })`,
+		`db.users.find({}, { x: { $concat: ["$ssn", ""] } })`,
+		`db.users.aggregate([{ $project: { x: "$ssn" } }])`,
+		`db.users.aggregate([{ $addFields: { x: "$ssn" } }])`,
+		`db.users.aggregate([{ $set: { x: "$ssn" } }])`,
+		`db.users.aggregate([{ $group: { _id: "$ssn" } }])`,
+		`db.users.aggregate([{ $replaceRoot: { newRoot: { x: "$ssn" } } }])`,
+		`db.orders.aggregate([{ $lookup: { from: "users", localField: "user", foreignField: "_id", as: "buyer" } }])`,
+		`db.orders.aggregate([{ $unionWith: "users" }])`,
+		`db.users.aggregate([{ $facet: { x: [{ $project: { ssn: 1 } }] } }])`,
+	} {
+		query, err := parseMongoQuery(statement)
+		a.NoError(err, statement)
+		a.Error(checkSensitiveQuery(query), statement)
+	}
+}
+
+func TestValidateReadOnlyQuery(t *testing.T) {
+	a := require.New(t)
+	a.NoError(ValidateReadOnlyQuery(`db.users.find({ name: "alice" })`))
+	a.NoError(ValidateReadOnlyQuery(`db.users.aggregate([{ $match: {} }])`))
+	a.NoError(ValidateReadOnlyQuery(`db.users.countDocuments()`))
+	a.Error(ValidateReadOnlyQuery(`db.users.aggregate([{ $out: "archive" }])`))
+	a.Error(ValidateReadOnlyQuery(`db.users.deleteMany({})`))
+	a.Error(ValidateReadOnlyQuery(`SELECT * FROM users`))
+}
+
+func TestConvertDocumentsToRowSet(t *testing.T) {
+	a := require.New(t)
+	objectID, err := primitive.ObjectIDFromHex("63b5a5e1d2f1a2b3c4d5e6f7")
+	a.NoError(err)
+	docs := []bson.D{
+		{
+			{Key: "_id", Value: objectID},
+			{Key: "name", Value: "alice"},
+			{Key: "phone", Value: nil},
+		},
+		{
+			{Key: "_id", Value: int32(2)},
+			{Key: "phone", Value: "123"},
+			{Key: "address", Value: bson.D{{Key: "city", Value: "Paris"}}},
+			{Key: "tags", Value: bson.A{"a", int32(1)}},
+		},
+	}
+	sensitiveFields := getSensitiveFields(&db.SensitiveSchemaInfo{
+		DatabaseList: []db.DatabaseSchema{
+			{
+				Name: "shop",
+				TableList: []db.TableSchema{
+					{Name: "users", ColumnList: []db.ColumnInfo{
+						{Name: "address.city", Sensitive: true, MaskAlgorithm: &db.MaskAlgorithm{Type: db.SensitiveDataMaskTypePartial, PrefixLength: 1}},
+						{Name: "phone", Sensitive: true, MaskAlgorithm: &db.MaskAlgorithm{Type: db.SensitiveDataMaskTypePhone}},
+					}},
+					{Name: "orders", ColumnList: []db.ColumnInfo{{Name: "name", Sensitive: true}}},
+				},
+			},
+		},
+	}, "shop", "users")
+	a.Equal(map[string]*db.MaskAlgorithm{"address": nil, "phone": {Type: db.SensitiveDataMaskTypePhone}}, sensitiveFields)
+
+	fields, types, rows := convertDocumentsToRowSet(docs, sensitiveFields, util.NewSensitiveDataMasker(&db.QueryContext{SensitiveDataMaskType: db.SensitiveDataMaskTypeDefault}))
+	a.Equal([]string{"_id", "name", "phone", "address", "tags"}, fields)
+	a.Equal([]string{"OBJECTID", "STRING", "STRING", "DOCUMENT", "ARRAY"}, types)
+	a.Equal([][]interface{}{
+		{"63b5a5e1d2f1a2b3c4d5e6f7", "alice", nil, util.MaskedValue, nil},
+		{int32(2), nil, "***", util.MaskedValue, `["a",1]`},
+	}, rows)
+}
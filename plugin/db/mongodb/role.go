@@ -2,33 +2,217 @@ package mongodb
 
 import (
 	"context"
+	"sort"
 
 	"github.com/pkg/errors"
+	"go.mongodb.org/mongo-driver/bson"
 
+	"github.com/bytebase/bytebase/common"
 	v1pb "github.com/bytebase/bytebase/proto/generated-go/v1"
 )
 
-// CreateRole creates the role.
-func (*Driver) CreateRole(_ context.Context, _ *v1pb.DatabaseRoleUpsert) (*v1pb.DatabaseRole, error) {
-	return nil, errors.Errorf("create role for MongoDB is not implemented yet")
+const (
+	// userDatabase is the database where the users are created, so they can access all databases.
+	userDatabase = "admin"
+
+	superUserRole  = "root"
+	createDBRole   = "dbAdminAnyDatabase"
+	createRoleRole = "userAdminAnyDatabase"
+)
+
+// CreateRole creates the role, which is a MongoDB user in the admin database.
+// The role attributes map onto the built-in roles, e.g. the superuser has the root role.
+func (driver *Driver) CreateRole(ctx context.Context, upsert *v1pb.DatabaseRoleUpsert) (*v1pb.DatabaseRole, error) {
+	if err := validateRoleUpsert(upsert); err != nil {
+		return nil, err
+	}
+	if upsert.Password == nil {
+		return nil, common.Errorf(common.Invalid, "password is required to create the MongoDB user %q", upsert.Name)
+	}
+
+	grants, _ := getRoleChanges(&v1pb.DatabaseRoleAttribute{}, upsert.Attribute)
+	command := bson.D{
+		{Key: "createUser", Value: upsert.Name},
+		{Key: "pwd", Value: *upsert.Password},
+		{Key: "roles", Value: convertToUserRoles(grants)},
+	}
+	if err := driver.runUserCommand(ctx, command); err != nil {
+		return nil, errors.Wrapf(err, "failed to create user %q", upsert.Name)
+	}
+	return driver.FindRole(ctx, upsert.Name)
 }
 
 // UpdateRole updates the role.
-func (*Driver) UpdateRole(_ context.Context, _ string, _ *v1pb.DatabaseRoleUpsert) (*v1pb.DatabaseRole, error) {
-	return nil, errors.Errorf("update role for MongoDB is not implemented yet")
+func (driver *Driver) UpdateRole(ctx context.Context, roleName string, upsert *v1pb.DatabaseRoleUpsert) (*v1pb.DatabaseRole, error) {
+	if err := validateRoleUpsert(upsert); err != nil {
+		return nil, err
+	}
+	if roleName != upsert.Name {
+		return nil, common.Errorf(common.Invalid, "renaming the user is not supported for MongoDB")
+	}
+	role, err := driver.FindRole(ctx, roleName)
+	if err != nil {
+		return nil, err
+	}
+
+	if v := upsert.Password; v != nil {
+		command := bson.D{
+			{Key: "updateUser", Value: roleName},
+			{Key: "pwd", Value: *v},
+		}
+		if err := driver.runUserCommand(ctx, command); err != nil {
+			return nil, errors.Wrapf(err, "failed to update the password of user %q", roleName)
+		}
+	}
+	if v := upsert.Attribute; v != nil {
+		grants, revokes := getRoleChanges(role.Attribute, v)
+		if len(grants) > 0 {
+			command := bson.D{
+				{Key: "grantRolesToUser", Value: roleName},
+				{Key: "roles", Value: convertToUserRoles(grants)},
+			}
+			if err := driver.runUserCommand(ctx, command); err != nil {
+				return nil, errors.Wrapf(err, "failed to grant roles to user %q", roleName)
+			}
+		}
+		if len(revokes) > 0 {
+			command := bson.D{
+				{Key: "revokeRolesFromUser", Value: roleName},
+				{Key: "roles", Value: convertToUserRoles(revokes)},
+			}
+			if err := driver.runUserCommand(ctx, command); err != nil {
+				return nil, errors.Wrapf(err, "failed to revoke roles from user %q", roleName)
+			}
+		}
+	}
+	return driver.FindRole(ctx, roleName)
 }
 
 // FindRole finds the role by name.
-func (*Driver) FindRole(_ context.Context, _ string) (*v1pb.DatabaseRole, error) {
-	return nil, errors.Errorf("find role for MongoDB is not implemented yet")
+func (driver *Driver) FindRole(ctx context.Context, roleName string) (*v1pb.DatabaseRole, error) {
+	roles, err := driver.findRoleImpl(ctx, roleName)
+	if err != nil {
+		return nil, err
+	}
+	if len(roles) == 0 {
+		return nil, common.Errorf(common.NotFound, "cannot find the role %s", roleName)
+	}
+	return roles[0], nil
 }
 
 // ListRole lists the role.
-func (*Driver) ListRole(_ context.Context) ([]*v1pb.DatabaseRole, error) {
-	return nil, errors.Errorf("list role for MongoDB is not implemented yet")
+func (driver *Driver) ListRole(ctx context.Context) ([]*v1pb.DatabaseRole, error) {
+	return driver.findRoleImpl(ctx, "")
 }
 
 // DeleteRole deletes the role by name.
-func (*Driver) DeleteRole(_ context.Context, _ string) error {
-	return errors.Errorf("delete role for MongoDB is not implemented yet")
+func (driver *Driver) DeleteRole(ctx context.Context, roleName string) error {
+	if err := driver.runUserCommand(ctx, bson.D{{Key: "dropUser", Value: roleName}}); err != nil {
+		return errors.Wrapf(err, "failed to drop user %q", roleName)
+	}
+	return nil
+}
+
+// validateRoleUpsert validates the upsert because MongoDB users don't support every role attribute.
+func validateRoleUpsert(upsert *v1pb.DatabaseRoleUpsert) error {
+	if upsert.ConnectionLimit != nil && *upsert.ConnectionLimit != -1 {
+		return common.Errorf(common.Invalid, "connection limit is not supported for MongoDB")
+	}
+	if upsert.ValidUntil != nil {
+		return common.Errorf(common.Invalid, "valid until is not supported for MongoDB")
+	}
+	if v := upsert.Attribute; v != nil {
+		if !v.CanLogin {
+			return common.Errorf(common.Invalid, "MongoDB users can always log in")
+		}
+		if v.NoInherit || v.Replication || v.BypassRls {
+			return common.Errorf(common.Invalid, "only superuser, create role, create database and login attributes are supported for MongoDB")
+		}
+	}
+	return nil
+}
+
+func (driver *Driver) runUserCommand(ctx context.Context, command bson.D) error {
+	return driver.client.Database(userDatabase).RunCommand(ctx, command).Err()
+}
+
+// getRoleChanges returns the built-in roles to grant and to revoke, which the changed role attributes map onto.
+// The roles not managed by the attributes are kept.
+func getRoleChanges(current, desired *v1pb.DatabaseRoleAttribute) ([]string, []string) {
+	if desired == nil {
+		return nil, nil
+	}
+	var grants, revokes []string
+	for _, role := range []struct {
+		role    string
+		current bool
+		desired bool
+	}{
+		{role: superUserRole, current: current.SuperUser, desired: desired.SuperUser},
+		{role: createDBRole, current: current.CreateDb, desired: desired.CreateDb},
+		{role: createRoleRole, current: current.CreateRole, desired: desired.CreateRole},
+	} {
+		switch {
+		case role.desired && !role.current:
+			grants = append(grants, role.role)
+		case !role.desired && role.current:
+			revokes = append(revokes, role.role)
+		}
+	}
+	return grants, revokes
+}
+
+func convertToUserRoles(roles []string) []Role {
+	userRoles := []Role{}
+	for _, role := range roles {
+		userRoles = append(userRoles, Role{RoleName: role, DB: userDatabase})
+	}
+	return userRoles
+}
+
+// findRoleImpl finds the users in the admin database, or all of them if the name is empty.
+func (driver *Driver) findRoleImpl(ctx context.Context, name string) ([]*v1pb.DatabaseRole, error) {
+	var command bson.D
+	if name == "" {
+		command = bson.D{{Key: "usersInfo", Value: 1}}
+	} else {
+		command = bson.D{{Key: "usersInfo", Value: name}}
+	}
+	var result UsersInfo
+	if err := driver.client.Database(userDatabase).RunCommand(ctx, command).Decode(&result); err != nil {
+		return nil, errors.Wrap(err, "failed to get users info")
+	}
+
+	var roleList []*v1pb.DatabaseRole
+	for _, user := range result.Users {
+		roleList = append(roleList, convertToDatabaseRole(user))
+	}
+	sort.Slice(roleList, func(i, j int) bool {
+		return roleList[i].Name < roleList[j].Name
+	})
+	return roleList, nil
+}
+
+func convertToDatabaseRole(user User) *v1pb.DatabaseRole {
+	role := &v1pb.DatabaseRole{
+		Name:            user.UserName,
+		ConnectionLimit: -1,
+		Attribute: &v1pb.DatabaseRoleAttribute{
+			CanLogin: true,
+		},
+	}
+	for _, userRole := range user.Roles {
+		if userRole.DB != userDatabase {
+			continue
+		}
+		switch userRole.RoleName {
+		case superUserRole:
+			role.Attribute.SuperUser = true
+		case createDBRole:
+			role.Attribute.CreateDb = true
+		case createRoleRole:
+			role.Attribute.CreateRole = true
+		}
+	}
+	return role
 }
@@ -0,0 +1,60 @@
+package mongodb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	v1pb "github.com/bytebase/bytebase/proto/generated-go/v1"
+)
+
+func TestGetRoleChanges(t *testing.T) {
+	tests := []struct {
+		current *v1pb.DatabaseRoleAttribute
+		desired *v1pb.DatabaseRoleAttribute
+		grants  []string
+		revokes []string
+	}{
+		{
+			current: &v1pb.DatabaseRoleAttribute{},
+			desired: nil,
+		},
+		{
+			current: &v1pb.DatabaseRoleAttribute{},
+			desired: &v1pb.DatabaseRoleAttribute{SuperUser: true, CanLogin: true},
+			grants:  []string{superUserRole},
+		},
+		{
+			current: &v1pb.DatabaseRoleAttribute{SuperUser: true, CreateDb: true},
+			desired: &v1pb.DatabaseRoleAttribute{CreateDb: true, CreateRole: true},
+			grants:  []string{createRoleRole},
+			revokes: []string{superUserRole},
+		},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		grants, revokes := getRoleChanges(test.current, test.desired)
+		a.Equal(test.grants, grants)
+		a.Equal(test.revokes, revokes)
+	}
+}
+
+func TestConvertToDatabaseRole(t *testing.T) {
+	a := require.New(t)
+	role := convertToDatabaseRole(User{
+		UserName: "bob",
+		DB:       userDatabase,
+		Roles: []Role{
+			{RoleName: createDBRole, DB: userDatabase},
+			{RoleName: superUserRole, DB: "shop"},
+			{RoleName: "readWrite", DB: "shop"},
+		},
+	})
+	a.Equal("bob", role.Name)
+	a.Equal(int32(-1), role.ConnectionLimit)
+	a.True(role.Attribute.CanLogin)
+	a.True(role.Attribute.CreateDb)
+	a.False(role.Attribute.SuperUser)
+	a.False(role.Attribute.CreateRole)
+}
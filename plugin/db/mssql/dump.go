@@ -0,0 +1,284 @@
+package mssql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	mssqldb "github.com/microsoft/go-mssqldb"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/plugin/db/util"
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+// Dump and restore.
+const (
+	databaseHeaderFmt = "" +
+		"--\n" +
+		"-- MSSQL database structure for %s\n" +
+		"--\n"
+	tableDataHeaderFmt = "" +
+		"--\n" +
+		"-- Table data for %s\n" +
+		"--\n"
+	batchSeparator = "GO\n"
+	defaultSchema  = "dbo"
+)
+
+// Dump dumps the database.
+// The schema is generated from the synced metadata, and batches are separated by "GO".
+func (driver *Driver) Dump(ctx context.Context, database string, out io.Writer, schemaOnly bool) (string, error) {
+	var dumpableDbNames []string
+	if database != "" {
+		dumpableDbNames = []string{database}
+	} else {
+		databases, err := driver.getDatabases(ctx)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to get databases")
+		}
+		for _, database := range databases {
+			if excludedDatabaseList[database.Name] {
+				continue
+			}
+			dumpableDbNames = append(dumpableDbNames, database.Name)
+		}
+	}
+
+	for _, dbName := range dumpableDbNames {
+		// The CREATE DATABASE and USE statements should be excluded if dumping a single database.
+		if len(dumpableDbNames) > 1 {
+			header := fmt.Sprintf(databaseHeaderFmt, dbName)
+			if _, err := io.WriteString(out, header); err != nil {
+				return "", err
+			}
+			if _, err := io.WriteString(out, fmt.Sprintf("CREATE DATABASE %s;\n%sUSE %s;\n%s\n", quoteIdentifier(dbName), batchSeparator, quoteIdentifier(dbName), batchSeparator)); err != nil {
+				return "", err
+			}
+		}
+		if err := driver.dumpOneDatabase(ctx, dbName, out, schemaOnly); err != nil {
+			return "", err
+		}
+	}
+
+	return "", nil
+}
+
+func (driver *Driver) dumpOneDatabase(ctx context.Context, database string, out io.Writer, schemaOnly bool) error {
+	databaseMetadata, err := driver.SyncDBSchema(ctx, database)
+	if err != nil {
+		return err
+	}
+	if err := writeSchema(out, databaseMetadata); err != nil {
+		return err
+	}
+	if schemaOnly {
+		return nil
+	}
+
+	txn, err := driver.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer txn.Rollback()
+	for _, schema := range databaseMetadata.Schemas {
+		for _, table := range schema.Tables {
+			if err := exportTableData(ctx, txn, database, schema.Name, table.Name, out); err != nil {
+				return err
+			}
+		}
+	}
+	return txn.Commit()
+}
+
+// writeSchema writes the DDL of the database metadata.
+// Foreign keys are created after all tables so that the referenced tables exist.
+func writeSchema(out io.Writer, databaseMetadata *storepb.DatabaseMetadata) error {
+	var sb strings.Builder
+	for _, schema := range databaseMetadata.Schemas {
+		if schema.Name != defaultSchema {
+			fmt.Fprintf(&sb, "CREATE SCHEMA %s;\n%s\n", quoteIdentifier(schema.Name), batchSeparator)
+		}
+		for _, table := range schema.Tables {
+			writeTable(&sb, schema.Name, table)
+		}
+	}
+	for _, schema := range databaseMetadata.Schemas {
+		for _, table := range schema.Tables {
+			for _, fk := range table.ForeignKeys {
+				writeForeignKey(&sb, schema.Name, table.Name, fk)
+			}
+		}
+	}
+	for _, schema := range databaseMetadata.Schemas {
+		for _, view := range schema.Views {
+			fmt.Fprintf(&sb, "%s;\n%s\n", strings.TrimRight(strings.TrimSpace(view.Definition), ";"), batchSeparator)
+		}
+	}
+	_, err := io.WriteString(out, sb.String())
+	return err
+}
+
+func writeTable(sb *strings.Builder, schemaName string, table *storepb.TableMetadata) {
+	var lines []string
+	for _, column := range table.Columns {
+		line := fmt.Sprintf("    %s %s", quoteIdentifier(column.Name), column.Type)
+		if column.Collation != "" {
+			line += fmt.Sprintf(" COLLATE %s", column.Collation)
+		}
+		if column.Nullable {
+			line += " NULL"
+		} else {
+			line += " NOT NULL"
+		}
+		if column.Default != nil {
+			line += fmt.Sprintf(" DEFAULT %s", column.Default.Value)
+		}
+		lines = append(lines, line)
+	}
+	for _, index := range table.Indexes {
+		if !index.Primary {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("    CONSTRAINT %s PRIMARY KEY %s (%s)", quoteIdentifier(index.Name), index.Type, joinIdentifiers(index.Expressions)))
+	}
+	fmt.Fprintf(sb, "CREATE TABLE %s.%s (\n%s\n);\n%s\n", quoteIdentifier(schemaName), quoteIdentifier(table.Name), strings.Join(lines, ",\n"), batchSeparator)
+
+	for _, index := range table.Indexes {
+		if index.Primary {
+			continue
+		}
+		unique := ""
+		if index.Unique {
+			unique = "UNIQUE "
+		}
+		fmt.Fprintf(sb, "CREATE %s%s INDEX %s ON %s.%s (%s);\n%s\n", unique, index.Type, quoteIdentifier(index.Name), quoteIdentifier(schemaName), quoteIdentifier(table.Name), joinIdentifiers(index.Expressions), batchSeparator)
+	}
+}
+
+func writeForeignKey(sb *strings.Builder, schemaName, tableName string, fk *storepb.ForeignKeyMetadata) {
+	fmt.Fprintf(sb, "ALTER TABLE %s.%s ADD CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s.%s (%s) ON DELETE %s ON UPDATE %s;\n%s\n",
+		quoteIdentifier(schemaName),
+		quoteIdentifier(tableName),
+		quoteIdentifier(fk.Name),
+		joinIdentifiers(fk.Columns),
+		quoteIdentifier(fk.ReferencedSchema),
+		quoteIdentifier(fk.ReferencedTable),
+		joinIdentifiers(fk.ReferencedColumns),
+		fk.OnDelete,
+		fk.OnUpdate,
+		batchSeparator,
+	)
+}
+
+func joinIdentifiers(identifiers []string) string {
+	var quoted []string
+	for _, identifier := range identifiers {
+		quoted = append(quoted, quoteIdentifier(identifier))
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// exportTableData writes the table data as INSERT statements.
+func exportTableData(ctx context.Context, txn *sql.Tx, database, schemaName, tableName string, out io.Writer) error {
+	query := fmt.Sprintf("SELECT * FROM %s.%s.%s", quoteIdentifier(database), quoteIdentifier(schemaName), quoteIdentifier(tableName))
+	rows, err := txn.QueryContext(ctx, query)
+	if err != nil {
+		return util.FormatErrorWithQuery(err, query)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return err
+	}
+	fullTableName := fmt.Sprintf("%s.%s", quoteIdentifier(schemaName), quoteIdentifier(tableName))
+	if _, err := io.WriteString(out, fmt.Sprintf(tableDataHeaderFmt, fullTableName)); err != nil {
+		return err
+	}
+	values := make([]interface{}, len(columns))
+	refs := make([]interface{}, len(columns))
+	for i := range values {
+		refs[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(refs...); err != nil {
+			return err
+		}
+		var tokens []string
+		for i, v := range values {
+			token, err := formatValue(columnTypes[i].DatabaseTypeName(), v)
+			if err != nil {
+				return err
+			}
+			tokens = append(tokens, token)
+		}
+		stmt := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s);\n", fullTableName, joinIdentifiers(columns), strings.Join(tokens, ", "))
+		if _, err := io.WriteString(out, stmt); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return util.FormatErrorWithQuery(err, query)
+	}
+	_, err = io.WriteString(out, batchSeparator+"\n")
+	return err
+}
+
+// formatValue formats the scanned value as a T-SQL literal.
+func formatValue(typeName string, v interface{}) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "NULL", nil
+	case []byte:
+		switch typeName {
+		case "DECIMAL", "MONEY", "SMALLMONEY":
+			// The driver returns the textual representation for exact numerics.
+			return string(v), nil
+		case "UNIQUEIDENTIFIER":
+			var uuid mssqldb.UniqueIdentifier
+			if err := uuid.Scan(v); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("'%s'", uuid.String()), nil
+		}
+		return "0x" + hex.EncodeToString(v), nil
+	case string:
+		return fmt.Sprintf("N'%s'", escapeString(v)), nil
+	case bool:
+		if v {
+			return "1", nil
+		}
+		return "0", nil
+	case time.Time:
+		return fmt.Sprintf("'%s'", v.Format("2006-01-02T15:04:05.9999999")), nil
+	default:
+		return fmt.Sprintf("%v", v), nil
+	}
+}
+
+// Restore restores a database.
+func (driver *Driver) Restore(ctx context.Context, sc io.Reader) error {
+	buf, err := io.ReadAll(sc)
+	if err != nil {
+		return err
+	}
+	batches, err := splitBatch(string(buf))
+	if err != nil {
+		return err
+	}
+	for _, batch := range batches {
+		if _, err := driver.db.ExecContext(ctx, batch); err != nil {
+			return util.FormatErrorWithQuery(err, batch)
+		}
+	}
+	return nil
+}
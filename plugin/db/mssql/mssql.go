@@ -0,0 +1,293 @@
+// Package mssql is the plugin for MSSQL driver.
+package mssql
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+
+	mssqldb "github.com/microsoft/go-mssqldb"
+	"github.com/microsoft/go-mssqldb/msdsn"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/db/util"
+)
+
+var (
+	excludedDatabaseList = map[string]bool{
+		// Skip our internal "bytebase" database
+		"bytebase": true,
+		// Skip the system databases.
+		"master": true,
+		"model":  true,
+		"msdb":   true,
+		"tempdb": true,
+		// Skip internal databases from cloud service providers
+		// aws
+		"rdsadmin": true,
+	}
+
+	_ db.Driver        = (*Driver)(nil)
+	_ db.StreamQuerier = (*Driver)(nil)
+)
+
+func init() {
+	db.Register(db.MSSQL, newDriver)
+}
+
+// Driver is the MSSQL driver.
+type Driver struct {
+	connectionCtx db.ConnectionContext
+	config        db.ConnectionConfig
+
+	db           *sql.DB
+	databaseName string
+}
+
+func newDriver(db.DriverConfig) db.Driver {
+	return &Driver{}
+}
+
+// Open opens a MSSQL driver.
+func (driver *Driver) Open(_ context.Context, _ db.Type, config db.ConnectionConfig, connCtx db.ConnectionContext) (db.Driver, error) {
+	driver.config = config
+	driver.connectionCtx = connCtx
+	if err := driver.switchDatabase(config.Database); err != nil {
+		return nil, err
+	}
+	return driver, nil
+}
+
+// Close closes the driver.
+func (driver *Driver) Close(context.Context) error {
+	return driver.db.Close()
+}
+
+// Ping pings the database.
+func (driver *Driver) Ping(ctx context.Context) error {
+	return driver.db.PingContext(ctx)
+}
+
+// GetType returns the database type.
+func (*Driver) GetType() db.Type {
+	return db.MSSQL
+}
+
+// GetDBConnection gets a database connection.
+func (driver *Driver) GetDBConnection(_ context.Context, database string) (*sql.DB, error) {
+	if database != driver.databaseName {
+		if err := driver.switchDatabase(database); err != nil {
+			return nil, err
+		}
+	}
+	return driver.db, nil
+}
+
+// switchDatabase reopens the connection pool with the given database as the initial catalog.
+func (driver *Driver) switchDatabase(database string) error {
+	connector, err := newConnector(driver.config, database)
+	if err != nil {
+		return err
+	}
+	if driver.db != nil {
+		if err := driver.db.Close(); err != nil {
+			return err
+		}
+	}
+	driver.db = sql.OpenDB(connector)
+	driver.databaseName = database
+	return nil
+}
+
+func newConnector(config db.ConnectionConfig, database string) (*mssqldb.Connector, error) {
+	query := url.Values{}
+	if database != "" {
+		query.Add("database", database)
+	}
+	if config.ReadOnly {
+		query.Add("ApplicationIntent", "ReadOnly")
+	}
+	host := config.Host
+	if config.Port != "" {
+		host = fmt.Sprintf("%s:%s", config.Host, config.Port)
+	}
+	u := &url.URL{
+		Scheme:   "sqlserver",
+		User:     url.UserPassword(config.Username, config.Password),
+		Host:     host,
+		RawQuery: query.Encode(),
+	}
+	dsnConfig, _, err := msdsn.Parse(u.String())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse the connection string")
+	}
+
+	tlsConfig, err := config.TLSConfig.GetSslConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "sql: tls config error")
+	}
+	if tlsConfig != nil {
+		dsnConfig.TLSConfig = tlsConfig
+		dsnConfig.Encryption = msdsn.EncryptionRequired
+	}
+	return mssqldb.NewConnectorConfig(dsnConfig), nil
+}
+
+// getVersion gets the version.
+func (driver *Driver) getVersion(ctx context.Context) (string, error) {
+	query := "SELECT CONVERT(NVARCHAR(128), SERVERPROPERTY('ProductVersion'))"
+	var version string
+	if err := driver.db.QueryRowContext(ctx, query).Scan(&version); err != nil {
+		if err == sql.ErrNoRows {
+			return "", common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return "", util.FormatErrorWithQuery(err, query)
+	}
+	return version, nil
+}
+
+// getDatabases gets all databases of an instance.
+func (driver *Driver) getDatabases(ctx context.Context) ([]*db.DatabaseMeta, error) {
+	query := "SELECT name, collation_name FROM sys.databases"
+	rows, err := driver.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	defer rows.Close()
+
+	var databases []*db.DatabaseMeta
+	for rows.Next() {
+		var name string
+		var collation sql.NullString
+		if err := rows.Scan(&name, &collation); err != nil {
+			return nil, err
+		}
+		databases = append(databases, &db.DatabaseMeta{
+			Name:      name,
+			Collation: collation.String,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	return databases, nil
+}
+
+// Execute executes a SQL statement.
+// The statement is split into batches by the "GO" batch separator, and each batch is sent to the server as a whole.
+// CREATE DATABASE is not allowed in a multi-statement transaction, so batches are executed without a transaction if createDatabase is set.
+func (driver *Driver) Execute(ctx context.Context, statement string, createDatabase bool) (int64, error) {
+	batches, err := splitBatch(statement)
+	if err != nil {
+		return 0, err
+	}
+
+	if createDatabase {
+		// Use a single connection so that the USE statement applies to the following batches.
+		conn, err := driver.db.Conn(ctx)
+		if err != nil {
+			return 0, err
+		}
+		defer conn.Close()
+
+		var totalRowsAffected int64
+		for _, batch := range batches {
+			sqlResult, err := conn.ExecContext(ctx, batch)
+			if err != nil {
+				return 0, util.FormatErrorWithQuery(err, batch)
+			}
+			totalRowsAffected += getRowsAffected(sqlResult)
+		}
+		return totalRowsAffected, nil
+	}
+
+	tx, err := driver.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var totalRowsAffected int64
+	for _, batch := range batches {
+		sqlResult, err := tx.ExecContext(ctx, batch)
+		if err != nil {
+			return 0, util.FormatErrorWithQuery(err, batch)
+		}
+		totalRowsAffected += getRowsAffected(sqlResult)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return totalRowsAffected, nil
+}
+
+func getRowsAffected(sqlResult sql.Result) int64 {
+	rowsAffected, err := sqlResult.RowsAffected()
+	if err != nil {
+		// Since we cannot differentiate DDL and DML yet, we have to ignore the error.
+		log.Debug("rowsAffected returns error", zap.Error(err))
+		return 0
+	}
+	return rowsAffected
+}
+
+// Query queries a SQL statement.
+func (driver *Driver) Query(ctx context.Context, statement string, queryContext *db.QueryContext) ([]interface{}, error) {
+	if queryContext.ReadOnly {
+		if err := checkSelectStatement(statement); err != nil {
+			return nil, err
+		}
+	}
+	return util.Query(ctx, db.MSSQL, driver.db, statement, queryContext)
+}
+
+// QueryStream queries a SQL statement and passes the rows to the handler as they arrive.
+func (driver *Driver) QueryStream(ctx context.Context, statement string, queryContext *db.QueryContext, handler db.RowHandler) error {
+	if queryContext.ReadOnly {
+		if err := checkSelectStatement(statement); err != nil {
+			return err
+		}
+	}
+	return util.QueryStream(ctx, db.MSSQL, driver.db, statement, queryContext, handler)
+}
+
+// splitBatch splits the statement into batches by the "GO" batch separator.
+// The batch separator must be on a line by itself, and it's case-insensitive.
+func splitBatch(statement string) ([]string, error) {
+	var batches []string
+	var sb strings.Builder
+	scanner := bufio.NewScanner(strings.NewReader(statement))
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), len(statement)+1)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.EqualFold(strings.TrimSpace(line), "GO") {
+			if s := strings.TrimSpace(sb.String()); s != "" {
+				batches = append(batches, s)
+			}
+			sb.Reset()
+			continue
+		}
+		_, _ = sb.WriteString(line)
+		_, _ = sb.WriteString("\n")
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to split the statement into batches")
+	}
+	if s := strings.TrimSpace(sb.String()); s != "" {
+		batches = append(batches, s)
+	}
+	return batches, nil
+}
+
+// quoteIdentifier quotes the identifier with brackets.
+func quoteIdentifier(s string) string {
+	return fmt.Sprintf("[%s]", strings.ReplaceAll(s, "]", "]]"))
+}
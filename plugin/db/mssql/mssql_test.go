@@ -0,0 +1,161 @@
+package mssql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+func TestSplitBatch(t *testing.T) {
+	tests := []struct {
+		statement string
+		want      []string
+	}{
+		{
+			statement: "SELECT 1;",
+			want:      []string{"SELECT 1;"},
+		},
+		{
+			statement: "CREATE DATABASE hello;\nGO\nCREATE TABLE hello.dbo.t (id INT);\ngo\n",
+			want:      []string{"CREATE DATABASE hello;", "CREATE TABLE hello.dbo.t (id INT);"},
+		},
+		{
+			statement: "CREATE VIEW v AS SELECT 1 AS good;\n  GO  \nGO\nSELECT * FROM v;",
+			want:      []string{"CREATE VIEW v AS SELECT 1 AS good;", "SELECT * FROM v;"},
+		},
+		{
+			statement: "\nGO\n",
+			want:      nil,
+		},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		got, err := splitBatch(test.statement)
+		a.NoError(err)
+		a.Equal(test.want, got)
+	}
+}
+
+func TestWriteSchema(t *testing.T) {
+	databaseMetadata := &storepb.DatabaseMetadata{
+		Name: "hello",
+		Schemas: []*storepb.SchemaMetadata{
+			{
+				Name: "dbo",
+				Tables: []*storepb.TableMetadata{
+					{
+						Name: "author",
+						Columns: []*storepb.ColumnMetadata{
+							{Name: "id", Type: "int"},
+							{Name: "name", Type: "nvarchar(255)", Nullable: true, Default: &wrapperspb.StringValue{Value: "(N'')"}},
+						},
+						Indexes: []*storepb.IndexMetadata{
+							{Name: "PK_author", Type: "CLUSTERED", Primary: true, Unique: true, Expressions: []string{"id"}},
+							{Name: "idx_name", Type: "NONCLUSTERED", Unique: true, Expressions: []string{"name"}},
+						},
+					},
+				},
+			},
+			{
+				Name: "sales",
+				Tables: []*storepb.TableMetadata{
+					{
+						Name: "book",
+						Columns: []*storepb.ColumnMetadata{
+							{Name: "author_id", Type: "int"},
+						},
+						ForeignKeys: []*storepb.ForeignKeyMetadata{
+							{
+								Name:              "fk_author",
+								Columns:           []string{"author_id"},
+								ReferencedSchema:  "dbo",
+								ReferencedTable:   "author",
+								ReferencedColumns: []string{"id"},
+								OnDelete:          "CASCADE",
+								OnUpdate:          "NO ACTION",
+							},
+						},
+					},
+				},
+				Views: []*storepb.ViewMetadata{
+					{Name: "v", Definition: "CREATE VIEW sales.v AS SELECT 1 AS one;"},
+				},
+			},
+		},
+	}
+	want := strings.Join([]string{
+		"CREATE TABLE [dbo].[author] (",
+		"    [id] int NOT NULL,",
+		"    [name] nvarchar(255) NULL DEFAULT (N''),",
+		"    CONSTRAINT [PK_author] PRIMARY KEY CLUSTERED ([id])",
+		");",
+		"GO",
+		"",
+		"CREATE UNIQUE NONCLUSTERED INDEX [idx_name] ON [dbo].[author] ([name]);",
+		"GO",
+		"",
+		"CREATE SCHEMA [sales];",
+		"GO",
+		"",
+		"CREATE TABLE [sales].[book] (",
+		"    [author_id] int NOT NULL",
+		");",
+		"GO",
+		"",
+		"ALTER TABLE [sales].[book] ADD CONSTRAINT [fk_author] FOREIGN KEY ([author_id]) REFERENCES [dbo].[author] ([id]) ON DELETE CASCADE ON UPDATE NO ACTION;",
+		"GO",
+		"",
+		"CREATE VIEW sales.v AS SELECT 1 AS one;",
+		"GO",
+		"",
+		"",
+	}, "\n")
+
+	var sb strings.Builder
+	a := require.New(t)
+	a.NoError(writeSchema(&sb, databaseMetadata))
+	a.Equal(want, sb.String())
+}
+
+func TestCheckSelectStatement(t *testing.T) {
+	tests := []struct {
+		statement string
+		valid     bool
+	}{
+		{statement: "SELECT * FROM t;", valid: true},
+		{statement: "SELECT COUNT(*) FROM t", valid: true},
+		{statement: "SELECT 1", valid: true},
+		{statement: "SELECT a FROM t ORDER BY a", valid: true},
+		{statement: "WITH a(x) AS (SELECT 1), b AS (SELECT x FROM a) SELECT * FROM b ORDER BY x", valid: true},
+		{statement: "SELECT a FROM t UNION ALL SELECT a FROM t2", valid: true},
+		{statement: "SELECT a FROM t WITH (NOLOCK) WHERE b IN (SELECT b FROM t2)", valid: true},
+		{statement: "SELECT 'DELETE FROM t; SELECT 1' AS [update], \"set\" FROM t -- DROP TABLE t", valid: true},
+		{statement: "SELECT 1 /* outer /* nested */ DELETE */", valid: true},
+		{statement: "SELECT 1 a) SELECT * FROM result DELETE FROM t COMMIT --", valid: false},
+		{statement: "SELECT 1 DELETE FROM t", valid: false},
+		{statement: "SELECT 1 SELECT 2", valid: false},
+		{statement: "SELECT 1; SELECT 2", valid: false},
+		{statement: "SELECT (1) SELECT 2", valid: false},
+		{statement: "WITH a AS (SELECT 1) SELECT (1) SELECT 2", valid: false},
+		{statement: "SELECT * INTO t2 FROM t", valid: false},
+		{statement: "EXEC sp_who", valid: false},
+		{statement: "WITH a AS (SELECT 1) DELETE FROM t", valid: false},
+		{statement: "SELECT 'unterminated", valid: false},
+		{statement: "SELECT 1 /* unterminated", valid: false},
+		{statement: "", valid: false},
+	}
+
+	for _, test := range tests {
+		err := checkSelectStatement(test.statement)
+		if test.valid {
+			require.NoError(t, err, test.statement)
+		} else {
+			require.Error(t, err, test.statement)
+		}
+	}
+}
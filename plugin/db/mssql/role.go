@@ -0,0 +1,262 @@
+package mssql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db/util"
+	v1pb "github.com/bytebase/bytebase/proto/generated-go/v1"
+)
+
+// The server-level roles that the role attributes map onto.
+const (
+	sysAdminRole      = "sysadmin"
+	dbCreatorRole     = "dbcreator"
+	securityAdminRole = "securityadmin"
+)
+
+type roleFind struct {
+	Name *string
+}
+
+// CreateRole creates the role, which is a SQL Server login.
+func (driver *Driver) CreateRole(ctx context.Context, upsert *v1pb.DatabaseRoleUpsert) (*v1pb.DatabaseRole, error) {
+	if err := validateRoleUpsert(upsert); err != nil {
+		return nil, err
+	}
+	if upsert.Password == nil {
+		return nil, common.Errorf(common.Invalid, "password is required to create the login %q", upsert.Name)
+	}
+
+	statement := fmt.Sprintf("CREATE LOGIN %s WITH PASSWORD = N'%s'", quoteIdentifier(upsert.Name), escapeString(*upsert.Password))
+	if _, err := driver.db.ExecContext(ctx, statement); err != nil {
+		return nil, util.FormatErrorWithQuery(err, statement)
+	}
+
+	txn, err := driver.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer txn.Rollback()
+
+	if err := alterRoleAttributeImpl(ctx, txn, upsert); err != nil {
+		return nil, err
+	}
+	roles, err := findRoleImpl(ctx, txn, &roleFind{Name: &upsert.Name})
+	if err != nil {
+		return nil, err
+	}
+	if len(roles) == 0 {
+		return nil, common.Errorf(common.NotFound, "cannot find the role %s", upsert.Name)
+	}
+
+	if err := txn.Commit(); err != nil {
+		return nil, err
+	}
+	return roles[0], nil
+}
+
+// UpdateRole updates the role.
+func (driver *Driver) UpdateRole(ctx context.Context, roleName string, upsert *v1pb.DatabaseRoleUpsert) (*v1pb.DatabaseRole, error) {
+	if err := validateRoleUpsert(upsert); err != nil {
+		return nil, err
+	}
+
+	txn, err := driver.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer txn.Rollback()
+
+	if roleName != upsert.Name {
+		statement := fmt.Sprintf("ALTER LOGIN %s WITH NAME = %s", quoteIdentifier(roleName), quoteIdentifier(upsert.Name))
+		if _, err := txn.ExecContext(ctx, statement); err != nil {
+			return nil, util.FormatErrorWithQuery(err, statement)
+		}
+	}
+	if v := upsert.Password; v != nil {
+		statement := fmt.Sprintf("ALTER LOGIN %s WITH PASSWORD = N'%s'", quoteIdentifier(upsert.Name), escapeString(*v))
+		if _, err := txn.ExecContext(ctx, statement); err != nil {
+			return nil, util.FormatErrorWithQuery(err, statement)
+		}
+	}
+	if err := alterRoleAttributeImpl(ctx, txn, upsert); err != nil {
+		return nil, err
+	}
+
+	roles, err := findRoleImpl(ctx, txn, &roleFind{Name: &upsert.Name})
+	if err != nil {
+		return nil, err
+	}
+	if len(roles) == 0 {
+		return nil, common.Errorf(common.NotFound, "cannot find the role %s", upsert.Name)
+	}
+
+	if err := txn.Commit(); err != nil {
+		return nil, err
+	}
+	return roles[0], nil
+}
+
+// FindRole finds the role by name.
+func (driver *Driver) FindRole(ctx context.Context, roleName string) (*v1pb.DatabaseRole, error) {
+	txn, err := driver.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer txn.Rollback()
+
+	roles, err := findRoleImpl(ctx, txn, &roleFind{Name: &roleName})
+	if err != nil {
+		return nil, err
+	}
+	if len(roles) == 0 {
+		return nil, common.Errorf(common.NotFound, "cannot find the role %s", roleName)
+	}
+
+	if err := txn.Commit(); err != nil {
+		return nil, err
+	}
+	return roles[0], nil
+}
+
+// ListRole lists the role.
+func (driver *Driver) ListRole(ctx context.Context) ([]*v1pb.DatabaseRole, error) {
+	txn, err := driver.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer txn.Rollback()
+
+	roles, err := findRoleImpl(ctx, txn, &roleFind{})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := txn.Commit(); err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// DeleteRole deletes the role by name.
+func (driver *Driver) DeleteRole(ctx context.Context, roleName string) error {
+	statement := fmt.Sprintf("DROP LOGIN %s", quoteIdentifier(roleName))
+	if _, err := driver.db.ExecContext(ctx, statement); err != nil {
+		return util.FormatErrorWithQuery(err, statement)
+	}
+	return nil
+}
+
+// validateRoleUpsert validates the upsert because SQL Server logins don't support every role attribute.
+func validateRoleUpsert(upsert *v1pb.DatabaseRoleUpsert) error {
+	if upsert.ConnectionLimit != nil && *upsert.ConnectionLimit != -1 {
+		return common.Errorf(common.Invalid, "connection limit is not supported for MSSQL")
+	}
+	if upsert.ValidUntil != nil {
+		return common.Errorf(common.Invalid, "valid until is not supported for MSSQL")
+	}
+	if v := upsert.Attribute; v != nil {
+		if v.NoInherit || v.Replication || v.BypassRls {
+			return common.Errorf(common.Invalid, "only superuser, create role, create database and login attributes are supported for MSSQL")
+		}
+	}
+	return nil
+}
+
+// alterRoleAttributeImpl maps the role attributes onto the login status and the server role memberships.
+func alterRoleAttributeImpl(ctx context.Context, txn *sql.Tx, upsert *v1pb.DatabaseRoleUpsert) error {
+	if upsert.Attribute == nil {
+		return nil
+	}
+	var statements []string
+	if upsert.Attribute.CanLogin {
+		statements = append(statements, fmt.Sprintf("ALTER LOGIN %s ENABLE", quoteIdentifier(upsert.Name)))
+	} else {
+		statements = append(statements, fmt.Sprintf("ALTER LOGIN %s DISABLE", quoteIdentifier(upsert.Name)))
+	}
+	for _, membership := range []struct {
+		role   string
+		member bool
+	}{
+		{role: sysAdminRole, member: upsert.Attribute.SuperUser},
+		{role: dbCreatorRole, member: upsert.Attribute.CreateDb},
+		{role: securityAdminRole, member: upsert.Attribute.CreateRole},
+	} {
+		role := membership.role
+		if membership.member {
+			statements = append(statements, fmt.Sprintf("ALTER SERVER ROLE %s ADD MEMBER %s", quoteIdentifier(role), quoteIdentifier(upsert.Name)))
+		} else {
+			statements = append(statements, fmt.Sprintf("IF IS_SRVROLEMEMBER(N'%s', N'%s') = 1 ALTER SERVER ROLE %s DROP MEMBER %s", role, escapeString(upsert.Name), quoteIdentifier(role), quoteIdentifier(upsert.Name)))
+		}
+	}
+	for _, statement := range statements {
+		if _, err := txn.ExecContext(ctx, statement); err != nil {
+			return util.FormatErrorWithQuery(err, statement)
+		}
+	}
+	return nil
+}
+
+func findRoleImpl(ctx context.Context, txn *sql.Tx, find *roleFind) ([]*v1pb.DatabaseRole, error) {
+	where := []string{"p.type IN ('S', 'U', 'G')", "p.name NOT LIKE '##%'"}
+	var args []interface{}
+	if v := find.Name; v != nil {
+		where = append(where, "p.name = @p1")
+		args = append(args, *v)
+	}
+	statement := fmt.Sprintf(`
+		SELECT
+			p.name,
+			p.is_disabled,
+			IS_SRVROLEMEMBER('%s', p.name),
+			IS_SRVROLEMEMBER('%s', p.name),
+			IS_SRVROLEMEMBER('%s', p.name)
+		FROM sys.server_principals p
+		WHERE %s
+		ORDER BY p.name
+	`, sysAdminRole, dbCreatorRole, securityAdminRole, strings.Join(where, " AND "))
+
+	rows, err := txn.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, statement)
+	}
+	defer rows.Close()
+
+	var roleList []*v1pb.DatabaseRole
+	for rows.Next() {
+		var disabled bool
+		var superUser, createDB, createRole sql.NullInt32
+		role := &v1pb.DatabaseRole{
+			ConnectionLimit: -1,
+			Attribute:       &v1pb.DatabaseRoleAttribute{},
+		}
+		if err := rows.Scan(
+			&role.Name,
+			&disabled,
+			&superUser,
+			&createDB,
+			&createRole,
+		); err != nil {
+			return nil, util.FormatErrorWithQuery(err, statement)
+		}
+		role.Attribute.CanLogin = !disabled
+		role.Attribute.SuperUser = superUser.Int32 == 1
+		role.Attribute.CreateDb = createDB.Int32 == 1
+		role.Attribute.CreateRole = createRole.Int32 == 1
+		roleList = append(roleList, role)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to scan roles")
+	}
+	return roleList, nil
+}
+
+func escapeString(s string) string {
+	return strings.ReplaceAll(s, "'", "''")
+}
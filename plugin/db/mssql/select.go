@@ -0,0 +1,177 @@
+package mssql
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// forbiddenSelectKeywordMap is the keywords that cannot appear in the read-only SELECT statement.
+// T-SQL doesn't require the statement terminator, so a batch like "SELECT 1 DELETE FROM t" runs both statements,
+// and we reject the keywords starting or modifying statements anywhere in the query.
+var forbiddenSelectKeywordMap = map[string]bool{
+	"ALTER": true, "BACKUP": true, "BEGIN": true, "BULK": true, "COMMIT": true, "CREATE": true,
+	"DBCC": true, "DECLARE": true, "DELETE": true, "DENY": true, "DROP": true, "EXEC": true,
+	"EXECUTE": true, "GO": true, "GOTO": true, "GRANT": true, "IF": true, "INSERT": true,
+	"INTO": true, "KILL": true, "MERGE": true, "OPENDATASOURCE": true, "OPENQUERY": true, "OPENROWSET": true,
+	"PRINT": true, "RAISERROR": true, "RECONFIGURE": true, "RESTORE": true, "RETURN": true, "REVERT": true,
+	"REVOKE": true, "ROLLBACK": true, "SAVE": true, "SET": true, "SHUTDOWN": true, "THROW": true,
+	"TRUNCATE": true, "UPDATE": true, "USE": true, "WAITFOR": true, "WHILE": true,
+}
+
+type selectToken struct {
+	text   string
+	isWord bool
+	depth  int
+}
+
+// checkSelectStatement returns an error if the statement isn't a single SELECT statement.
+// MSSQL doesn't support the read-only transaction, so the SQL editor relies on this check to reject the writes.
+func checkSelectStatement(statement string) error {
+	tokenList, err := tokenizeSelectStatement(statement)
+	if err != nil {
+		return err
+	}
+	if len(tokenList) == 0 {
+		return errors.Errorf("the statement is empty")
+	}
+	if first := tokenList[0]; !first.isWord || (first.text != "SELECT" && first.text != "WITH") {
+		return errors.Errorf("only the SELECT statement is allowed")
+	}
+
+	hasTopLevelSelect := false
+	for i, token := range tokenList {
+		if !token.isWord {
+			continue
+		}
+		if forbiddenSelectKeywordMap[token.text] {
+			return errors.Errorf("only the SELECT statement is allowed, but found %q", token.text)
+		}
+		if token.text != "SELECT" || token.depth != 0 {
+			continue
+		}
+		// The top-level SELECT starts the statement, follows the common table expressions, or follows the set operators.
+		// Otherwise, it starts another statement in the batch.
+		switch {
+		case i == 0:
+		case !hasTopLevelSelect && tokenList[0].text == "WITH" && tokenList[i-1].text == ")":
+		case tokenList[i-1].isWord && isSetOperator(tokenList[i-1].text):
+		default:
+			return errors.Errorf("only one SELECT statement is allowed")
+		}
+		hasTopLevelSelect = true
+	}
+	if !hasTopLevelSelect {
+		return errors.Errorf("only the SELECT statement is allowed")
+	}
+	return nil
+}
+
+func isSetOperator(word string) bool {
+	switch word {
+	case "UNION", "ALL", "EXCEPT", "INTERSECT":
+		return true
+	}
+	return false
+}
+
+// tokenizeSelectStatement splits the statement into the words and symbols, skipping the comments and literals.
+// The strings and quoted identifiers are returned as the non-word tokens so they never match the keywords.
+func tokenizeSelectStatement(statement string) ([]selectToken, error) {
+	s := []rune(statement)
+	var res []selectToken
+	depth := 0
+	terminated := false
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+			continue
+		case c == '-' && i+1 < len(s) && s[i+1] == '-':
+			for i < len(s) && s[i] != '\n' {
+				i++
+			}
+			continue
+		case c == '/' && i+1 < len(s) && s[i+1] == '*':
+			// T-SQL supports the nested block comments.
+			commentDepth := 0
+			for {
+				if i+1 >= len(s) {
+					return nil, errors.Errorf("invalid comment: not found */")
+				}
+				if s[i] == '/' && s[i+1] == '*' {
+					commentDepth++
+					i += 2
+				} else if s[i] == '*' && s[i+1] == '/' {
+					commentDepth--
+					i += 2
+					if commentDepth == 0 {
+						break
+					}
+				} else {
+					i++
+				}
+			}
+			continue
+		}
+
+		if terminated {
+			return nil, errors.Errorf("only one SELECT statement is allowed")
+		}
+		switch {
+		case c == '\'' || c == '"' || c == '[':
+			end := c
+			if c == '[' {
+				end = ']'
+			}
+			j := i + 1
+			for ; ; j++ {
+				if j >= len(s) {
+					return nil, errors.Errorf("invalid statement: not found the closing %q", end)
+				}
+				if s[j] == end {
+					// The doubled closing character is the escape.
+					if j+1 < len(s) && s[j+1] == end {
+						j++
+						continue
+					}
+					break
+				}
+			}
+			res = append(res, selectToken{text: string(s[i : j+1]), depth: depth})
+			i = j + 1
+		case isSelectWordRune(c):
+			j := i
+			for j < len(s) && isSelectWordRune(s[j]) {
+				j++
+			}
+			res = append(res, selectToken{text: strings.ToUpper(string(s[i:j])), isWord: true, depth: depth})
+			i = j
+		case c == ';':
+			terminated = true
+			i++
+		default:
+			if c == ')' {
+				depth--
+				if depth < 0 {
+					return nil, errors.Errorf("invalid statement: unbalanced parentheses")
+				}
+			}
+			res = append(res, selectToken{text: string(c), depth: depth})
+			if c == '(' {
+				depth++
+			}
+			i++
+		}
+	}
+	if depth != 0 {
+		return nil, errors.Errorf("invalid statement: unbalanced parentheses")
+	}
+	return res, nil
+}
+
+func isSelectWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '@' || r == '#' || r == '$'
+}
@@ -0,0 +1,499 @@
+package mssql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/db/util"
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+var (
+	systemSchemas = map[string]bool{
+		"sys":                true,
+		"information_schema": true,
+		"guest":              true,
+		"db_owner":           true,
+		"db_accessadmin":     true,
+		"db_securityadmin":   true,
+		"db_ddladmin":        true,
+		"db_backupoperator":  true,
+		"db_datareader":      true,
+		"db_datawriter":      true,
+		"db_denydatareader":  true,
+		"db_denydatawriter":  true,
+	}
+)
+
+// SyncInstance syncs the instance.
+func (driver *Driver) SyncInstance(ctx context.Context) (*db.InstanceMeta, error) {
+	version, err := driver.getVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userList, err := driver.getUserList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	databases, err := driver.getDatabases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var databaseList []db.DatabaseMeta
+	for _, database := range databases {
+		if excludedDatabaseList[database.Name] {
+			continue
+		}
+		databaseList = append(databaseList, *database)
+	}
+
+	return &db.InstanceMeta{
+		Version:      version,
+		UserList:     userList,
+		DatabaseList: databaseList,
+	}, nil
+}
+
+// SyncDBSchema syncs a single database schema.
+func (driver *Driver) SyncDBSchema(ctx context.Context, databaseName string) (*storepb.DatabaseMetadata, error) {
+	databases, err := driver.getDatabases(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get databases")
+	}
+
+	databaseMetadata := &storepb.DatabaseMetadata{
+		Name: databaseName,
+	}
+	found := false
+	for _, database := range databases {
+		if database.Name == databaseName {
+			found = true
+			databaseMetadata.Collation = database.Collation
+			break
+		}
+	}
+	if !found {
+		return nil, common.Errorf(common.NotFound, "database %q not found", databaseName)
+	}
+
+	txn, err := driver.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer txn.Rollback()
+
+	tableMap, err := getTables(ctx, txn, databaseName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get tables from database %q", databaseName)
+	}
+	viewMap, err := getViews(ctx, txn, databaseName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get views from database %q", databaseName)
+	}
+
+	if err := txn.Commit(); err != nil {
+		return nil, err
+	}
+
+	schemaNameMap := make(map[string]bool)
+	for schemaName := range tableMap {
+		schemaNameMap[schemaName] = true
+	}
+	for schemaName := range viewMap {
+		schemaNameMap[schemaName] = true
+	}
+	var schemaNames []string
+	for schemaName := range schemaNameMap {
+		schemaNames = append(schemaNames, schemaName)
+	}
+	sort.Strings(schemaNames)
+	for _, schemaName := range schemaNames {
+		databaseMetadata.Schemas = append(databaseMetadata.Schemas, &storepb.SchemaMetadata{
+			Name:   schemaName,
+			Tables: tableMap[schemaName],
+			Views:  viewMap[schemaName],
+		})
+	}
+
+	return databaseMetadata, nil
+}
+
+func (driver *Driver) getUserList(ctx context.Context) ([]db.User, error) {
+	query := `
+		SELECT
+			p.name,
+			ISNULL(STRING_AGG(r.name, ', '), '')
+		FROM sys.server_principals p
+		LEFT JOIN sys.server_role_members m ON p.principal_id = m.member_principal_id
+		LEFT JOIN sys.server_principals r ON m.role_principal_id = r.principal_id
+		WHERE p.type IN ('S', 'U', 'G') AND p.name NOT LIKE '##%'
+		GROUP BY p.name
+		ORDER BY p.name`
+	rows, err := driver.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	defer rows.Close()
+
+	var userList []db.User
+	for rows.Next() {
+		var user db.User
+		if err := rows.Scan(
+			&user.Name,
+			&user.Grant,
+		); err != nil {
+			return nil, err
+		}
+		userList = append(userList, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	return userList, nil
+}
+
+func getExcludedSchemaWhere(column string) string {
+	var excludedSchemaList []string
+	for k := range systemSchemas {
+		excludedSchemaList = append(excludedSchemaList, fmt.Sprintf("'%s'", k))
+	}
+	sort.Strings(excludedSchemaList)
+	return fmt.Sprintf("LOWER(%s) NOT IN (%s)", column, strings.Join(excludedSchemaList, ", "))
+}
+
+// getTables gets all tables of a database.
+func getTables(ctx context.Context, txn *sql.Tx, database string) (map[string][]*storepb.TableMetadata, error) {
+	columnMap, err := getColumns(ctx, txn, database)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get columns")
+	}
+	indexMap, err := getIndexes(ctx, txn, database)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get indexes")
+	}
+	foreignKeysMap, err := getForeignKeys(ctx, txn, database)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get foreign keys")
+	}
+
+	tableMap := make(map[string][]*storepb.TableMetadata)
+	query := fmt.Sprintf(`
+		SELECT
+			s.name,
+			t.name,
+			ISNULL((SELECT SUM(p.rows) FROM %[1]s.sys.partitions p WHERE p.object_id = t.object_id AND p.index_id IN (0, 1)), 0),
+			ISNULL((SELECT SUM(a.data_pages) FROM %[1]s.sys.partitions p JOIN %[1]s.sys.allocation_units a ON p.partition_id = a.container_id WHERE p.object_id = t.object_id AND p.index_id IN (0, 1)), 0) * 8192,
+			ISNULL((SELECT SUM(a.used_pages) FROM %[1]s.sys.partitions p JOIN %[1]s.sys.allocation_units a ON p.partition_id = a.container_id WHERE p.object_id = t.object_id AND p.index_id > 1), 0) * 8192,
+			ISNULL(CONVERT(NVARCHAR(MAX), ep.value), '')
+		FROM %[1]s.sys.tables t
+		JOIN %[1]s.sys.schemas s ON t.schema_id = s.schema_id
+		LEFT JOIN %[1]s.sys.extended_properties ep ON ep.major_id = t.object_id AND ep.minor_id = 0 AND ep.class = 1 AND ep.name = 'MS_Description'
+		WHERE t.is_ms_shipped = 0 AND %[2]s
+		ORDER BY s.name, t.name`, quoteIdentifier(database), getExcludedSchemaWhere("s.name"))
+	rows, err := txn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var schemaName string
+		table := &storepb.TableMetadata{}
+		if err := rows.Scan(
+			&schemaName,
+			&table.Name,
+			&table.RowCount,
+			&table.DataSize,
+			&table.IndexSize,
+			&table.Comment,
+		); err != nil {
+			return nil, err
+		}
+		key := db.TableKey{Schema: schemaName, Table: table.Name}
+		table.Columns = columnMap[key]
+		table.Indexes = indexMap[key]
+		table.ForeignKeys = foreignKeysMap[key]
+
+		tableMap[schemaName] = append(tableMap[schemaName], table)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	return tableMap, nil
+}
+
+// getColumns gets all columns of a database.
+func getColumns(ctx context.Context, txn *sql.Tx, database string) (map[db.TableKey][]*storepb.ColumnMetadata, error) {
+	columnMap := make(map[db.TableKey][]*storepb.ColumnMetadata)
+	query := fmt.Sprintf(`
+		SELECT
+			c.TABLE_SCHEMA,
+			c.TABLE_NAME,
+			c.COLUMN_NAME,
+			c.ORDINAL_POSITION,
+			c.COLUMN_DEFAULT,
+			c.IS_NULLABLE,
+			c.DATA_TYPE,
+			c.CHARACTER_MAXIMUM_LENGTH,
+			c.NUMERIC_PRECISION,
+			c.NUMERIC_SCALE,
+			ISNULL(c.CHARACTER_SET_NAME, ''),
+			ISNULL(c.COLLATION_NAME, ''),
+			ISNULL(CONVERT(NVARCHAR(MAX), ep.value), '')
+		FROM %[1]s.INFORMATION_SCHEMA.COLUMNS c
+		LEFT JOIN %[1]s.sys.extended_properties ep
+			ON ep.major_id = OBJECT_ID(QUOTENAME(c.TABLE_CATALOG) + '.' + QUOTENAME(c.TABLE_SCHEMA) + '.' + QUOTENAME(c.TABLE_NAME))
+			AND ep.minor_id = COLUMNPROPERTY(OBJECT_ID(QUOTENAME(c.TABLE_CATALOG) + '.' + QUOTENAME(c.TABLE_SCHEMA) + '.' + QUOTENAME(c.TABLE_NAME)), c.COLUMN_NAME, 'ColumnId')
+			AND ep.class = 1 AND ep.name = 'MS_Description'
+		WHERE %[2]s
+		ORDER BY c.TABLE_SCHEMA, c.TABLE_NAME, c.ORDINAL_POSITION`, quoteIdentifier(database), getExcludedSchemaWhere("c.TABLE_SCHEMA"))
+	rows, err := txn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var schemaName, tableName, nullable, dataType string
+		var defaultStr sql.NullString
+		var maxLength, precision, scale sql.NullInt64
+		column := &storepb.ColumnMetadata{}
+		if err := rows.Scan(
+			&schemaName,
+			&tableName,
+			&column.Name,
+			&column.Position,
+			&defaultStr,
+			&nullable,
+			&dataType,
+			&maxLength,
+			&precision,
+			&scale,
+			&column.CharacterSet,
+			&column.Collation,
+			&column.Comment,
+		); err != nil {
+			return nil, err
+		}
+		if defaultStr.Valid {
+			column.Default = &wrapperspb.StringValue{Value: defaultStr.String}
+		}
+		isNullBool, err := util.ConvertYesNo(nullable)
+		if err != nil {
+			return nil, err
+		}
+		column.Nullable = isNullBool
+		column.Type = getColumnType(dataType, maxLength, precision, scale)
+
+		key := db.TableKey{Schema: schemaName, Table: tableName}
+		columnMap[key] = append(columnMap[key], column)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	return columnMap, nil
+}
+
+// getColumnType returns the full column type, e.g. nvarchar(255), decimal(10,2).
+func getColumnType(dataType string, maxLength, precision, scale sql.NullInt64) string {
+	switch strings.ToLower(dataType) {
+	case "char", "varchar", "nchar", "nvarchar", "binary", "varbinary":
+		if !maxLength.Valid {
+			return dataType
+		}
+		if maxLength.Int64 == -1 {
+			return fmt.Sprintf("%s(max)", dataType)
+		}
+		return fmt.Sprintf("%s(%d)", dataType, maxLength.Int64)
+	case "decimal", "numeric":
+		if precision.Valid && scale.Valid {
+			return fmt.Sprintf("%s(%d,%d)", dataType, precision.Int64, scale.Int64)
+		}
+	}
+	return dataType
+}
+
+// getIndexes gets all indexes of a database.
+func getIndexes(ctx context.Context, txn *sql.Tx, database string) (map[db.TableKey][]*storepb.IndexMetadata, error) {
+	indexMap := make(map[db.TableKey][]*storepb.IndexMetadata)
+	query := fmt.Sprintf(`
+		SELECT
+			s.name,
+			t.name,
+			i.name,
+			i.type_desc,
+			i.is_unique,
+			i.is_primary_key,
+			i.is_disabled,
+			c.name
+		FROM %[1]s.sys.indexes i
+		JOIN %[1]s.sys.tables t ON i.object_id = t.object_id
+		JOIN %[1]s.sys.schemas s ON t.schema_id = s.schema_id
+		JOIN %[1]s.sys.index_columns ic ON i.object_id = ic.object_id AND i.index_id = ic.index_id
+		JOIN %[1]s.sys.columns c ON ic.object_id = c.object_id AND ic.column_id = c.column_id
+		WHERE i.type > 0 AND ic.is_included_column = 0 AND t.is_ms_shipped = 0 AND %[2]s
+		ORDER BY s.name, t.name, i.name, ic.key_ordinal`, quoteIdentifier(database), getExcludedSchemaWhere("s.name"))
+	rows, err := txn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	defer rows.Close()
+
+	indexes := make(map[db.TableKey]map[string]*storepb.IndexMetadata)
+	for rows.Next() {
+		var schemaName, tableName, indexName, indexType, columnName string
+		var unique, primary, disabled bool
+		if err := rows.Scan(
+			&schemaName,
+			&tableName,
+			&indexName,
+			&indexType,
+			&unique,
+			&primary,
+			&disabled,
+			&columnName,
+		); err != nil {
+			return nil, err
+		}
+		key := db.TableKey{Schema: schemaName, Table: tableName}
+		if _, ok := indexes[key]; !ok {
+			indexes[key] = make(map[string]*storepb.IndexMetadata)
+		}
+		index, ok := indexes[key][indexName]
+		if !ok {
+			index = &storepb.IndexMetadata{
+				Name:    indexName,
+				Type:    indexType,
+				Unique:  unique,
+				Primary: primary,
+				Visible: !disabled,
+			}
+			indexes[key][indexName] = index
+			indexMap[key] = append(indexMap[key], index)
+		}
+		index.Expressions = append(index.Expressions, columnName)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	return indexMap, nil
+}
+
+// getForeignKeys gets all foreign keys of a database.
+func getForeignKeys(ctx context.Context, txn *sql.Tx, database string) (map[db.TableKey][]*storepb.ForeignKeyMetadata, error) {
+	foreignKeysMap := make(map[db.TableKey][]*storepb.ForeignKeyMetadata)
+	query := fmt.Sprintf(`
+		SELECT
+			s.name,
+			t.name,
+			fk.name,
+			c.name,
+			rs.name,
+			rt.name,
+			rc.name,
+			fk.delete_referential_action_desc,
+			fk.update_referential_action_desc
+		FROM %[1]s.sys.foreign_keys fk
+		JOIN %[1]s.sys.foreign_key_columns fkc ON fk.object_id = fkc.constraint_object_id
+		JOIN %[1]s.sys.tables t ON fk.parent_object_id = t.object_id
+		JOIN %[1]s.sys.schemas s ON t.schema_id = s.schema_id
+		JOIN %[1]s.sys.columns c ON fkc.parent_object_id = c.object_id AND fkc.parent_column_id = c.column_id
+		JOIN %[1]s.sys.tables rt ON fk.referenced_object_id = rt.object_id
+		JOIN %[1]s.sys.schemas rs ON rt.schema_id = rs.schema_id
+		JOIN %[1]s.sys.columns rc ON fkc.referenced_object_id = rc.object_id AND fkc.referenced_column_id = rc.column_id
+		WHERE %[2]s
+		ORDER BY s.name, t.name, fk.name, fkc.constraint_column_id`, quoteIdentifier(database), getExcludedSchemaWhere("s.name"))
+	rows, err := txn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	defer rows.Close()
+
+	foreignKeys := make(map[db.TableKey]map[string]*storepb.ForeignKeyMetadata)
+	for rows.Next() {
+		var schemaName, tableName, fkName, columnName, referencedSchema, referencedTable, referencedColumn, onDelete, onUpdate string
+		if err := rows.Scan(
+			&schemaName,
+			&tableName,
+			&fkName,
+			&columnName,
+			&referencedSchema,
+			&referencedTable,
+			&referencedColumn,
+			&onDelete,
+			&onUpdate,
+		); err != nil {
+			return nil, err
+		}
+		key := db.TableKey{Schema: schemaName, Table: tableName}
+		if _, ok := foreignKeys[key]; !ok {
+			foreignKeys[key] = make(map[string]*storepb.ForeignKeyMetadata)
+		}
+		fk, ok := foreignKeys[key][fkName]
+		if !ok {
+			fk = &storepb.ForeignKeyMetadata{
+				Name:             fkName,
+				ReferencedSchema: referencedSchema,
+				ReferencedTable:  referencedTable,
+				OnDelete:         strings.ReplaceAll(onDelete, "_", " "),
+				OnUpdate:         strings.ReplaceAll(onUpdate, "_", " "),
+			}
+			foreignKeys[key][fkName] = fk
+			foreignKeysMap[key] = append(foreignKeysMap[key], fk)
+		}
+		fk.Columns = append(fk.Columns, columnName)
+		fk.ReferencedColumns = append(fk.ReferencedColumns, referencedColumn)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	return foreignKeysMap, nil
+}
+
+// getViews gets all views of a database.
+func getViews(ctx context.Context, txn *sql.Tx, database string) (map[string][]*storepb.ViewMetadata, error) {
+	viewMap := make(map[string][]*storepb.ViewMetadata)
+	query := fmt.Sprintf(`
+		SELECT
+			s.name,
+			v.name,
+			ISNULL(m.definition, ''),
+			ISNULL(CONVERT(NVARCHAR(MAX), ep.value), '')
+		FROM %[1]s.sys.views v
+		JOIN %[1]s.sys.schemas s ON v.schema_id = s.schema_id
+		LEFT JOIN %[1]s.sys.sql_modules m ON v.object_id = m.object_id
+		LEFT JOIN %[1]s.sys.extended_properties ep ON ep.major_id = v.object_id AND ep.minor_id = 0 AND ep.class = 1 AND ep.name = 'MS_Description'
+		WHERE v.is_ms_shipped = 0 AND %[2]s
+		ORDER BY s.name, v.name`, quoteIdentifier(database), getExcludedSchemaWhere("s.name"))
+	rows, err := txn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var schemaName string
+		view := &storepb.ViewMetadata{}
+		if err := rows.Scan(
+			&schemaName,
+			&view.Name,
+			&view.Definition,
+			&view.Comment,
+		); err != nil {
+			return nil, err
+		}
+		viewMap[schemaName] = append(viewMap[schemaName], view)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	return viewMap, nil
+}
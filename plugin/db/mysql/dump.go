@@ -19,6 +19,7 @@ import (
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
 	"github.com/bytebase/bytebase/plugin/db/util"
 	"github.com/bytebase/bytebase/resources/mysqlutil"
 )
@@ -122,8 +123,8 @@ func (driver *Driver) Dump(ctx context.Context, database string, out io.Writer,
 	}
 
 	options := sql.TxOptions{}
-	// TiDB does not support readonly, so we only set for MySQL.
-	if driver.dbType == "MYSQL" {
+	// TiDB does not support readonly, so we only set for MySQL, MariaDB and OceanBase.
+	if driver.dbType != db.TiDB {
 		options.ReadOnly = true
 	}
 	// If `schemaOnly` is false, now we are still holding the tables' exclusive locks.
@@ -136,7 +137,7 @@ func (driver *Driver) Dump(ctx context.Context, database string, out io.Writer,
 	defer txn.Rollback()
 
 	log.Debug("begin to dump database", zap.String("database", database), zap.Bool("schemaOnly", schemaOnly))
-	if err := dumpTxn(ctx, txn, database, out, schemaOnly); err != nil {
+	if err := dumpTxn(ctx, txn, driver.dbType, database, out, schemaOnly); err != nil {
 		return "", err
 	}
 
@@ -183,7 +184,7 @@ func FlushTablesWithReadLock(ctx context.Context, conn *sql.Conn, database strin
 	return txn.Commit()
 }
 
-func dumpTxn(ctx context.Context, txn *sql.Tx, database string, out io.Writer, schemaOnly bool) error {
+func dumpTxn(ctx context.Context, txn *sql.Tx, dbType db.Type, database string, out io.Writer, schemaOnly bool) error {
 	// Find all dumpable databases
 	dbNames, err := getDatabases(ctx, txn)
 	if err != nil {
@@ -205,7 +206,7 @@ func dumpTxn(ctx context.Context, txn *sql.Tx, database string, out io.Writer, s
 		dumpableDbNames = []string{database}
 	} else {
 		for _, dbName := range dbNames {
-			if systemDatabases[dbName] {
+			if isSystemDatabase(dbType, dbName) {
 				continue
 			}
 			dumpableDbNames = append(dumpableDbNames, dbName)
@@ -6,6 +6,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/go-sql-driver/mysql"
@@ -24,12 +25,16 @@ var (
 	baseTableType = "BASE TABLE"
 	viewTableType = "VIEW"
 
-	_ db.Driver = (*Driver)(nil)
+	_ db.Driver        = (*Driver)(nil)
+	_ db.StreamQuerier = (*Driver)(nil)
+	_ db.QueryCanceler = (*Driver)(nil)
 )
 
 func init() {
 	db.Register(db.MySQL, newDriver)
 	db.Register(db.TiDB, newDriver)
+	db.Register(db.MariaDB, newDriver)
+	db.Register(db.OceanBase, newDriver)
 }
 
 // Driver is the MySQL driver.
@@ -68,8 +73,11 @@ func (driver *Driver) Open(ctx context.Context, dbType db.Type, connCfg db.Conne
 	port := connCfg.Port
 	if port == "" {
 		port = "3306"
-		if dbType == db.TiDB {
+		switch dbType {
+		case db.TiDB:
 			port = "4000"
+		case db.OceanBase:
+			port = "2881"
 		}
 	}
 
@@ -161,6 +169,10 @@ func getDatabases(ctx context.Context, txn *sql.Tx) ([]string, error) {
 // getVersion gets the version.
 func (driver *Driver) getVersion(ctx context.Context) (string, error) {
 	query := "SELECT VERSION()"
+	// VERSION() returns the compatible MySQL version for OceanBase, such as 5.7.25-OceanBase_CE-v4.1.0.0.
+	if driver.dbType == db.OceanBase {
+		query = "SELECT OB_VERSION()"
+	}
 	var version string
 	if err := driver.db.QueryRowContext(ctx, query).Scan(&version); err != nil {
 		if err == sql.ErrNoRows {
@@ -168,9 +180,22 @@ func (driver *Driver) getVersion(ctx context.Context) (string, error) {
 		}
 		return "", util.FormatErrorWithQuery(err, query)
 	}
+	if driver.dbType == db.MariaDB {
+		version = parseMariaDBVersion(version)
+	}
 	return version, nil
 }
 
+// parseMariaDBVersion parses the version returned by MariaDB, such as 10.6.12-MariaDB-1:10.6.12+maria~ubu2004.
+// The replication protocol of MariaDB prepends the fake 5.5.5- prefix to the version, so that the old MySQL clients can connect to it.
+func parseMariaDBVersion(version string) string {
+	version = strings.TrimPrefix(version, "5.5.5-")
+	if i := strings.Index(version, "-"); i >= 0 {
+		version = version[:i]
+	}
+	return version
+}
+
 // Execute executes a SQL statement.
 func (driver *Driver) Execute(ctx context.Context, statement string, _ bool) (int64, error) {
 	trunks, err := splitAndTransformDelimiter(statement)
@@ -216,26 +241,57 @@ func (driver *Driver) GetMigrationConnID(ctx context.Context) (string, error) {
 
 // Query queries a SQL statement.
 func (driver *Driver) Query(ctx context.Context, statement string, queryContext *db.QueryContext) ([]interface{}, error) {
+	collector := &util.RowCollector{}
+	if err := driver.QueryStream(ctx, statement, queryContext, collector); err != nil {
+		return nil, err
+	}
+	return collector.Result(), nil
+}
+
+// QueryStream queries a SQL statement and passes the rows to the handler as they arrive.
+func (driver *Driver) QueryStream(ctx context.Context, statement string, queryContext *db.QueryContext, handler db.RowHandler) error {
 	singleSQLs, err := bbparser.SplitMultiSQL(bbparser.MySQL, statement)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	if len(singleSQLs) == 0 {
-		return nil, nil
+		return nil
 	}
 	// https://dev.mysql.com/doc/c-api/8.0/en/mysql-affected-rows.html
 	// If the statement is an INSERT, UPDATE, or DELETE statement, we will call execute instead of query and return the number of rows affected.
 	if len(singleSQLs) == 1 && util.IsAffectedRowsStatement(singleSQLs[0].Text) {
+		if queryContext.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, queryContext.Timeout)
+			defer cancel()
+		}
 		affectedRows, err := driver.Execute(ctx, singleSQLs[0].Text, false)
 		if err != nil {
-			return nil, err
+			return err
+		}
+		if err := handler.HandleColumns([]string{"Affected Rows"}, []string{"INT"}); err != nil {
+			return err
 		}
-		field := []string{"Affected Rows"}
-		types := []string{"INT"}
-		rows := [][]interface{}{{affectedRows}}
-		return []interface{}{field, types, rows}, nil
+		return handler.HandleRow([]interface{}{affectedRows})
+	}
+	return util.QueryStream(ctx, driver.dbType, driver.db, statement, queryContext, handler)
+}
+
+// CancelQuery kills the query running in the session with KILL QUERY, and the connection is kept.
+func (driver *Driver) CancelQuery(ctx context.Context, sessionID string) error {
+	connectionID, err := strconv.ParseUint(sessionID, 10, 64)
+	if err != nil {
+		return errors.Wrapf(err, "invalid connection ID %q", sessionID)
+	}
+	statement := fmt.Sprintf("KILL QUERY %d", connectionID)
+	if driver.dbType == db.TiDB {
+		// https://docs.pingcap.com/tidb/stable/sql-statement-kill
+		statement = fmt.Sprintf("KILL TIDB QUERY %d", connectionID)
+	}
+	if _, err := driver.db.ExecContext(ctx, statement); err != nil {
+		return util.FormatErrorWithQuery(err, statement)
 	}
-	return util.Query(ctx, driver.dbType, driver.db, statement, queryContext)
+	return nil
 }
 
 const querySize = 2 * 1024 * 1024 // 2M.
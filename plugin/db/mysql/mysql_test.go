@@ -2,6 +2,7 @@ package mysql
 
 import (
 	"bytes"
+	"database/sql"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -105,3 +106,65 @@ func TestTransformDelimiter_Truncate(t *testing.T) {
 	// Make sure all trunks add up.
 	require.Equal(t, total, len(statements))
 }
+
+func TestParseMariaDBVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{
+			version: "10.6.12-MariaDB-1:10.6.12+maria~ubu2004",
+			want:    "10.6.12",
+		},
+		{
+			version: "5.5.5-10.3.38-MariaDB-0ubuntu0.20.04.1",
+			want:    "10.3.38",
+		},
+		{
+			version: "10.11.2-MariaDB",
+			want:    "10.11.2",
+		},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		a.Equal(test.want, parseMariaDBVersion(test.version))
+	}
+}
+
+func TestNormalizeMariaDBColumnDefault(t *testing.T) {
+	tests := []struct {
+		defaultStr sql.NullString
+		want       sql.NullString
+	}{
+		{
+			defaultStr: sql.NullString{},
+			want:       sql.NullString{},
+		},
+		{
+			defaultStr: sql.NullString{String: "NULL", Valid: true},
+			want:       sql.NullString{},
+		},
+		{
+			defaultStr: sql.NullString{String: "'it''s'", Valid: true},
+			want:       sql.NullString{String: "it's", Valid: true},
+		},
+		{
+			defaultStr: sql.NullString{String: "''", Valid: true},
+			want:       sql.NullString{String: "", Valid: true},
+		},
+		{
+			defaultStr: sql.NullString{String: "current_timestamp()", Valid: true},
+			want:       sql.NullString{String: "current_timestamp()", Valid: true},
+		},
+		{
+			defaultStr: sql.NullString{String: "0", Valid: true},
+			want:       sql.NullString{String: "0", Valid: true},
+		},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		a.Equal(test.want, normalizeMariaDBColumnDefault(test.defaultStr))
+	}
+}
@@ -2,33 +2,322 @@ package mysql
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/db/util"
 	v1pb "github.com/bytebase/bytebase/proto/generated-go/v1"
 )
 
-// CreateRole creates the role.
-func (*Driver) CreateRole(_ context.Context, _ *v1pb.DatabaseRoleUpsert) (*v1pb.DatabaseRole, error) {
-	return nil, errors.Errorf("create role for MySQL is not implemented yet")
+type roleFind struct {
+	Name *string
+}
+
+// CreateRole creates the role, which is a MySQL account.
+// The role name is "user@host", and the host can be omitted if it's "%".
+// The role without the login attribute is created with ACCOUNT LOCK like the MySQL roles.
+func (driver *Driver) CreateRole(ctx context.Context, upsert *v1pb.DatabaseRoleUpsert) (*v1pb.DatabaseRole, error) {
+	if err := driver.validateRoleUpsert(upsert); err != nil {
+		return nil, err
+	}
+
+	account := quoteAccount(upsert.Name)
+	statement := fmt.Sprintf("CREATE USER %s", account)
+	if v := upsert.Password; v != nil {
+		statement += fmt.Sprintf(" IDENTIFIED BY '%s'", escapeString(*v))
+	}
+	if v := upsert.ConnectionLimit; v != nil {
+		statement += fmt.Sprintf(" WITH MAX_USER_CONNECTIONS %d", convertToMaxUserConnections(*v))
+	}
+	if v := upsert.ValidUntil; v != nil {
+		passwordExpire, err := convertToPasswordExpire(*v, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		statement += " " + passwordExpire
+	}
+	if upsert.Attribute != nil && !upsert.Attribute.CanLogin {
+		statement += " ACCOUNT LOCK"
+	}
+	statements := []string{statement}
+	if v := upsert.Attribute; v != nil {
+		statements = append(statements, getGrantStatements(account, &v1pb.DatabaseRoleAttribute{}, v)...)
+	}
+	if err := driver.execRoleStatements(ctx, statements); err != nil {
+		return nil, err
+	}
+
+	return driver.FindRole(ctx, upsert.Name)
 }
 
 // UpdateRole updates the role.
-func (*Driver) UpdateRole(_ context.Context, _ string, _ *v1pb.DatabaseRoleUpsert) (*v1pb.DatabaseRole, error) {
-	return nil, errors.Errorf("update role for MySQL is not implemented yet")
+func (driver *Driver) UpdateRole(ctx context.Context, roleName string, upsert *v1pb.DatabaseRoleUpsert) (*v1pb.DatabaseRole, error) {
+	if err := driver.validateRoleUpsert(upsert); err != nil {
+		return nil, err
+	}
+	role, err := driver.FindRole(ctx, roleName)
+	if err != nil {
+		return nil, err
+	}
+
+	var statements []string
+	account := quoteAccount(upsert.Name)
+	if roleName != upsert.Name {
+		statements = append(statements, fmt.Sprintf("RENAME USER %s TO %s", quoteAccount(roleName), account))
+	}
+	if v := upsert.Password; v != nil {
+		statements = append(statements, fmt.Sprintf("ALTER USER %s IDENTIFIED BY '%s'", account, escapeString(*v)))
+	}
+	if v := upsert.ConnectionLimit; v != nil {
+		statements = append(statements, fmt.Sprintf("ALTER USER %s WITH MAX_USER_CONNECTIONS %d", account, convertToMaxUserConnections(*v)))
+	}
+	if v := upsert.ValidUntil; v != nil {
+		passwordExpire, err := convertToPasswordExpire(*v, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, fmt.Sprintf("ALTER USER %s %s", account, passwordExpire))
+	}
+	if v := upsert.Attribute; v != nil {
+		if v.CanLogin {
+			statements = append(statements, fmt.Sprintf("ALTER USER %s ACCOUNT UNLOCK", account))
+		} else {
+			statements = append(statements, fmt.Sprintf("ALTER USER %s ACCOUNT LOCK", account))
+		}
+		statements = append(statements, getGrantStatements(account, role.Attribute, v)...)
+	}
+	if err := driver.execRoleStatements(ctx, statements); err != nil {
+		return nil, err
+	}
+
+	return driver.FindRole(ctx, upsert.Name)
 }
 
 // FindRole finds the role by name.
-func (*Driver) FindRole(_ context.Context, _ string) (*v1pb.DatabaseRole, error) {
-	return nil, errors.Errorf("find role for MySQL is not implemented yet")
+func (driver *Driver) FindRole(ctx context.Context, roleName string) (*v1pb.DatabaseRole, error) {
+	if err := driver.checkRoleSupport(); err != nil {
+		return nil, err
+	}
+	roles, err := driver.findRoleImpl(ctx, &roleFind{Name: &roleName})
+	if err != nil {
+		return nil, err
+	}
+	if len(roles) == 0 {
+		return nil, common.Errorf(common.NotFound, "cannot find the role %s", roleName)
+	}
+	return roles[0], nil
 }
 
 // ListRole lists the role.
-func (*Driver) ListRole(_ context.Context) ([]*v1pb.DatabaseRole, error) {
-	return nil, errors.Errorf("list role for MySQL is not implemented yet")
+func (driver *Driver) ListRole(ctx context.Context) ([]*v1pb.DatabaseRole, error) {
+	if err := driver.checkRoleSupport(); err != nil {
+		return nil, err
+	}
+	return driver.findRoleImpl(ctx, &roleFind{})
 }
 
 // DeleteRole deletes the role by name.
-func (*Driver) DeleteRole(_ context.Context, _ string) error {
-	return errors.Errorf("delete role for MySQL is not implemented yet")
+func (driver *Driver) DeleteRole(ctx context.Context, roleName string) error {
+	if err := driver.checkRoleSupport(); err != nil {
+		return err
+	}
+	return driver.execRoleStatements(ctx, []string{fmt.Sprintf("DROP USER IF EXISTS %s", quoteAccount(roleName))})
+}
+
+// checkRoleSupport checks the engine because the account attributes are read from the mysql.user table of MySQL 5.7+ and MariaDB 10.4+.
+func (driver *Driver) checkRoleSupport() error {
+	if driver.dbType != db.MySQL && driver.dbType != db.MariaDB {
+		return common.Errorf(common.NotImplemented, "role management is not supported for %s", driver.dbType)
+	}
+	return nil
+}
+
+// validateRoleUpsert validates the upsert because MySQL accounts don't support every role attribute.
+func (driver *Driver) validateRoleUpsert(upsert *v1pb.DatabaseRoleUpsert) error {
+	if err := driver.checkRoleSupport(); err != nil {
+		return err
+	}
+	if v := upsert.Attribute; v != nil && (v.NoInherit || v.BypassRls) {
+		return common.Errorf(common.Invalid, "no inherit and bypass RLS attributes are not supported for %s", driver.dbType)
+	}
+	return nil
+}
+
+// execRoleStatements executes the account statements one by one, because MySQL commits the account management statements implicitly.
+func (driver *Driver) execRoleStatements(ctx context.Context, statements []string) error {
+	for _, statement := range statements {
+		if _, err := driver.db.ExecContext(ctx, statement); err != nil {
+			return util.FormatErrorWithQuery(err, statement)
+		}
+	}
+	return nil
+}
+
+// getGrantStatements returns the statements granting or revoking the global privileges which the role attributes map onto.
+// The superuser has all privileges with the grant option, which include the privileges of the other attributes.
+func getGrantStatements(account string, current, desired *v1pb.DatabaseRoleAttribute) []string {
+	var statements []string
+	createDB, createRole, replication := current.CreateDb, current.CreateRole, current.Replication
+	if desired.SuperUser != current.SuperUser {
+		if desired.SuperUser {
+			return append(statements, fmt.Sprintf("GRANT ALL PRIVILEGES ON *.* TO %s WITH GRANT OPTION", account))
+		}
+		statements = append(statements, fmt.Sprintf("REVOKE ALL PRIVILEGES, GRANT OPTION FROM %s", account))
+		createDB, createRole, replication = false, false, false
+	} else if desired.SuperUser {
+		return nil
+	}
+	for _, privilege := range []struct {
+		privilege string
+		current   bool
+		desired   bool
+	}{
+		{privilege: "CREATE", current: createDB, desired: desired.CreateDb},
+		{privilege: "CREATE USER", current: createRole, desired: desired.CreateRole},
+		{privilege: "REPLICATION SLAVE, REPLICATION CLIENT", current: replication, desired: desired.Replication},
+	} {
+		switch {
+		case privilege.desired && !privilege.current:
+			statements = append(statements, fmt.Sprintf("GRANT %s ON *.* TO %s", privilege.privilege, account))
+		case !privilege.desired && privilege.current:
+			statements = append(statements, fmt.Sprintf("REVOKE %s ON *.* FROM %s", privilege.privilege, account))
+		}
+	}
+	return statements
+}
+
+func (driver *Driver) findRoleImpl(ctx context.Context, find *roleFind) ([]*v1pb.DatabaseRole, error) {
+	where := []string{"User != ''", "User NOT LIKE 'mysql.%'"}
+	var args []interface{}
+	if v := find.Name; v != nil {
+		user, host := parseRoleName(*v)
+		where = append(where, "User = ?", "Host = ?")
+		args = append(args, user, host)
+	}
+	statement := fmt.Sprintf(`
+		SELECT
+			User,
+			Host,
+			max_user_connections,
+			UNIX_TIMESTAMP(password_last_changed),
+			password_lifetime,
+			account_locked,
+			Super_priv,
+			Grant_priv,
+			Create_priv,
+			Create_user_priv,
+			Repl_slave_priv
+		FROM mysql.user
+		WHERE %s
+		ORDER BY User, Host
+	`, strings.Join(where, " AND "))
+
+	rows, err := driver.db.QueryContext(ctx, statement, args...)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, statement)
+	}
+	defer rows.Close()
+
+	var roleList []*v1pb.DatabaseRole
+	for rows.Next() {
+		var user, host, accountLocked, superPriv, grantPriv, createPriv, createUserPriv, replSlavePriv string
+		var maxUserConnections int32
+		var passwordLastChanged sql.NullInt64
+		var passwordLifetime sql.NullInt32
+		if err := rows.Scan(
+			&user,
+			&host,
+			&maxUserConnections,
+			&passwordLastChanged,
+			&passwordLifetime,
+			&accountLocked,
+			&superPriv,
+			&grantPriv,
+			&createPriv,
+			&createUserPriv,
+			&replSlavePriv,
+		); err != nil {
+			return nil, util.FormatErrorWithQuery(err, statement)
+		}
+		role := &v1pb.DatabaseRole{
+			Name:            formatRoleName(user, host),
+			ConnectionLimit: -1,
+			Attribute: &v1pb.DatabaseRoleAttribute{
+				SuperUser:   superPriv == "Y" && grantPriv == "Y",
+				CreateRole:  createUserPriv == "Y",
+				CreateDb:    createPriv == "Y",
+				CanLogin:    accountLocked != "Y",
+				Replication: replSlavePriv == "Y",
+			},
+		}
+		if maxUserConnections > 0 {
+			role.ConnectionLimit = maxUserConnections
+		}
+		if passwordLastChanged.Valid && passwordLifetime.Valid && passwordLifetime.Int32 > 0 {
+			validUntil := time.Unix(passwordLastChanged.Int64, 0).AddDate(0, 0, int(passwordLifetime.Int32)).UTC().Format(time.RFC3339)
+			role.ValidUntil = &validUntil
+		}
+		roleList = append(roleList, role)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to scan roles")
+	}
+	return roleList, nil
+}
+
+// convertToMaxUserConnections converts the connection limit to MAX_USER_CONNECTIONS, where 0 means no limit.
+func convertToMaxUserConnections(connectionLimit int32) int32 {
+	if connectionLimit < 0 {
+		return 0
+	}
+	return connectionLimit
+}
+
+// convertToPasswordExpire converts the valid until timestamp to the password expiration clause.
+// MySQL only supports the password lifetime in days since the password is changed, so the timestamp is rounded up to days from now.
+func convertToPasswordExpire(validUntil string, now time.Time) (string, error) {
+	t, err := time.Parse(time.RFC3339, validUntil)
+	if err != nil {
+		return "", common.Wrapf(err, common.Invalid, "invalid valid until %q", validUntil)
+	}
+	days := math.Ceil(t.Sub(now).Hours() / 24)
+	if days < 1 {
+		return "PASSWORD EXPIRE", nil
+	}
+	if days > math.MaxUint16 {
+		return "PASSWORD EXPIRE NEVER", nil
+	}
+	return fmt.Sprintf("PASSWORD EXPIRE INTERVAL %d DAY", int(days)), nil
+}
+
+// parseRoleName parses the role name "user@host" into the user and the host.
+func parseRoleName(name string) (string, string) {
+	if i := strings.LastIndex(name, "@"); i > 0 {
+		return name[:i], name[i+1:]
+	}
+	return name, "%"
+}
+
+func formatRoleName(user, host string) string {
+	if host == "%" {
+		return user
+	}
+	return fmt.Sprintf("%s@%s", user, host)
+}
+
+func quoteAccount(name string) string {
+	user, host := parseRoleName(name)
+	return fmt.Sprintf("'%s'@'%s'", escapeString(user), escapeString(host))
+}
+
+func escapeString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return strings.ReplaceAll(s, "'", "''")
 }
@@ -0,0 +1,95 @@
+package mysql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	v1pb "github.com/bytebase/bytebase/proto/generated-go/v1"
+)
+
+func TestParseRoleName(t *testing.T) {
+	tests := []struct {
+		name string
+		user string
+		host string
+	}{
+		{name: "bob", user: "bob", host: "%"},
+		{name: "bob@localhost", user: "bob", host: "localhost"},
+		{name: "bob@example.com@10.0.0.%", user: "bob@example.com", host: "10.0.0.%"},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		user, host := parseRoleName(test.name)
+		a.Equal(test.user, user)
+		a.Equal(test.host, host)
+		a.Equal(test.name, formatRoleName(user, host))
+	}
+	a.Equal(`'o''neil'@'%'`, quoteAccount("o'neil"))
+}
+
+func TestConvertToPasswordExpire(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		validUntil string
+		want       string
+	}{
+		{validUntil: "2022-12-31T00:00:00Z", want: "PASSWORD EXPIRE"},
+		{validUntil: "2023-01-01T00:00:00Z", want: "PASSWORD EXPIRE"},
+		{validUntil: "2023-01-01T12:00:00Z", want: "PASSWORD EXPIRE INTERVAL 1 DAY"},
+		{validUntil: "2023-01-31T08:00:00+08:00", want: "PASSWORD EXPIRE INTERVAL 30 DAY"},
+		{validUntil: "2300-01-01T00:00:00Z", want: "PASSWORD EXPIRE NEVER"},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		got, err := convertToPasswordExpire(test.validUntil, now)
+		a.NoError(err)
+		a.Equal(test.want, got, test.validUntil)
+	}
+	_, err := convertToPasswordExpire("2023-01-01", now)
+	a.Error(err)
+}
+
+func TestGetGrantStatements(t *testing.T) {
+	account := quoteAccount("bob")
+	tests := []struct {
+		current *v1pb.DatabaseRoleAttribute
+		desired *v1pb.DatabaseRoleAttribute
+		want    []string
+	}{
+		{
+			current: &v1pb.DatabaseRoleAttribute{},
+			desired: &v1pb.DatabaseRoleAttribute{CreateDb: true, Replication: true},
+			want: []string{
+				"GRANT CREATE ON *.* TO 'bob'@'%'",
+				"GRANT REPLICATION SLAVE, REPLICATION CLIENT ON *.* TO 'bob'@'%'",
+			},
+		},
+		{
+			current: &v1pb.DatabaseRoleAttribute{CreateDb: true},
+			desired: &v1pb.DatabaseRoleAttribute{SuperUser: true},
+			want:    []string{"GRANT ALL PRIVILEGES ON *.* TO 'bob'@'%' WITH GRANT OPTION"},
+		},
+		{
+			current: &v1pb.DatabaseRoleAttribute{SuperUser: true, CreateDb: true, CreateRole: true},
+			desired: &v1pb.DatabaseRoleAttribute{CreateRole: true},
+			want: []string{
+				"REVOKE ALL PRIVILEGES, GRANT OPTION FROM 'bob'@'%'",
+				"GRANT CREATE USER ON *.* TO 'bob'@'%'",
+			},
+		},
+		{
+			current: &v1pb.DatabaseRoleAttribute{CreateRole: true},
+			desired: &v1pb.DatabaseRoleAttribute{CreateRole: true},
+			want:    nil,
+		},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		a.Equal(test.want, getGrantStatements(account, test.current, test.desired))
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"regexp"
 	"sort"
 	"strings"
 
@@ -18,14 +19,37 @@ import (
 var (
 	systemDatabases = map[string]bool{
 		"information_schema": true,
-		// TiDB only
-		"metrics_schema":     true,
 		"mysql":              true,
 		"performance_schema": true,
 		"sys":                true,
 	}
+	// engineSystemDatabases is the system databases only existing in the specific engine.
+	engineSystemDatabases = map[db.Type]map[string]bool{
+		db.TiDB: {
+			"metrics_schema": true,
+		},
+		db.OceanBase: {
+			"oceanbase":    true,
+			"__public":     true,
+			"__recyclebin": true,
+		},
+	}
+
+	// MariaDB reports the system-versioned tables with their own table type.
+	systemVersionedTableType = "SYSTEM VERSIONED"
+	// MariaDB reports the sequences in information_schema.TABLES, but they are not tables.
+	sequenceTableType = "SEQUENCE"
+
+	// mariaDBJSONCheckRegexp matches the check constraint created for the JSON column in MariaDB, such as json_valid(`data`).
+	mariaDBJSONCheckRegexp = regexp.MustCompile("^json_valid\\(`(.+)`\\)$")
 )
 
+// isSystemDatabase returns true if the database is a system database of the engine.
+func isSystemDatabase(dbType db.Type, database string) bool {
+	database = strings.ToLower(database)
+	return systemDatabases[database] || engineSystemDatabases[dbType][database]
+}
+
 // SyncInstance syncs the instance.
 func (driver *Driver) SyncInstance(ctx context.Context) (*db.InstanceMeta, error) {
 	version, err := driver.getVersion(ctx)
@@ -47,6 +71,9 @@ func (driver *Driver) SyncInstance(ctx context.Context) (*db.InstanceMeta, error
 	for k := range systemDatabases {
 		excludedDatabaseList = append(excludedDatabaseList, fmt.Sprintf("'%s'", k))
 	}
+	for k := range engineSystemDatabases[driver.dbType] {
+		excludedDatabaseList = append(excludedDatabaseList, fmt.Sprintf("'%s'", k))
+	}
 
 	// Query db info
 	where := fmt.Sprintf("LOWER(SCHEMA_NAME) NOT IN (%s)", strings.Join(excludedDatabaseList, ", "))
@@ -219,6 +246,9 @@ func (driver *Driver) SyncDBSchema(ctx context.Context, databaseName string) (*s
 		); err != nil {
 			return nil, err
 		}
+		if driver.dbType == db.MariaDB {
+			defaultStr = normalizeMariaDBColumnDefault(defaultStr)
+		}
 		if defaultStr.Valid {
 			column.Default = &wrapperspb.StringValue{Value: defaultStr.String}
 		}
@@ -235,6 +265,21 @@ func (driver *Driver) SyncDBSchema(ctx context.Context, databaseName string) (*s
 		return nil, util.FormatErrorWithQuery(err, columnQuery)
 	}
 
+	// JSON is an alias for LONGTEXT with a json_valid() check constraint in MariaDB.
+	if driver.dbType == db.MariaDB {
+		jsonColumnMap, err := driver.getMariaDBJSONColumnMap(ctx, databaseName)
+		if err != nil {
+			return nil, err
+		}
+		for key, columns := range columnMap {
+			for _, column := range columns {
+				if jsonColumnMap[key][column.Name] && strings.EqualFold(column.Type, "longtext") {
+					column.Type = "json"
+				}
+			}
+		}
+	}
+
 	// Query view info.
 	viewMap := make(map[db.TableKey]*storepb.ViewMetadata)
 	viewQuery := `
@@ -312,7 +357,7 @@ func (driver *Driver) SyncDBSchema(ctx context.Context, databaseName string) (*s
 
 		key := db.TableKey{Schema: "", Table: tableName}
 		switch tableType {
-		case baseTableType:
+		case baseTableType, systemVersionedTableType:
 			tableMetadata := &storepb.TableMetadata{
 				Name:          tableName,
 				Columns:       columnMap[key],
@@ -346,6 +391,8 @@ func (driver *Driver) SyncDBSchema(ctx context.Context, databaseName string) (*s
 				view.Comment = comment
 				schemaMetadata.Views = append(schemaMetadata.Views, view)
 			}
+		case sequenceTableType:
+			// We don't sync the sequences yet.
 		}
 	}
 	if err := tableRows.Err(); err != nil {
@@ -376,6 +423,57 @@ func (driver *Driver) SyncDBSchema(ctx context.Context, databaseName string) (*s
 	return databaseMetadata, err
 }
 
+// getMariaDBJSONColumnMap gets the JSON columns, which are the columns with the json_valid() check constraint.
+func (driver *Driver) getMariaDBJSONColumnMap(ctx context.Context, databaseName string) (map[db.TableKey]map[string]bool, error) {
+	checkQuery := `
+		SELECT
+			TABLE_NAME,
+			CHECK_CLAUSE
+		FROM information_schema.CHECK_CONSTRAINTS
+		WHERE CONSTRAINT_SCHEMA = ?`
+	checkRows, err := driver.db.QueryContext(ctx, checkQuery, databaseName)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, checkQuery)
+	}
+	defer checkRows.Close()
+	jsonColumnMap := make(map[db.TableKey]map[string]bool)
+	for checkRows.Next() {
+		var tableName, checkClause string
+		if err := checkRows.Scan(&tableName, &checkClause); err != nil {
+			return nil, err
+		}
+		matches := mariaDBJSONCheckRegexp.FindStringSubmatch(checkClause)
+		if len(matches) != 2 {
+			continue
+		}
+		key := db.TableKey{Schema: "", Table: tableName}
+		if _, ok := jsonColumnMap[key]; !ok {
+			jsonColumnMap[key] = make(map[string]bool)
+		}
+		jsonColumnMap[key][matches[1]] = true
+	}
+	if err := checkRows.Err(); err != nil {
+		return nil, util.FormatErrorWithQuery(err, checkQuery)
+	}
+	return jsonColumnMap, nil
+}
+
+// normalizeMariaDBColumnDefault converts the column default of MariaDB to the MySQL style.
+// Since MariaDB 10.2.7, the literal defaults are quoted, and the NULL default is the string "NULL".
+func normalizeMariaDBColumnDefault(defaultStr sql.NullString) sql.NullString {
+	if !defaultStr.Valid {
+		return defaultStr
+	}
+	value := defaultStr.String
+	if value == "NULL" {
+		return sql.NullString{}
+	}
+	if len(value) >= 2 && strings.HasPrefix(value, "'") && strings.HasSuffix(value, "'") {
+		value = strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+	}
+	return sql.NullString{String: value, Valid: true}
+}
+
 func (driver *Driver) getForeignKeyList(ctx context.Context, databaseName string) (map[db.TableKey][]*storepb.ForeignKeyMetadata, error) {
 	fkQuery := `
 		SELECT
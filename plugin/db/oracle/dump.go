@@ -0,0 +1,191 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+// Dump and restore.
+const (
+	databaseHeaderFmt = "" +
+		"--\n" +
+		"-- Oracle schema structure for %s\n" +
+		"--\n"
+)
+
+// plainIdentifierRegexp matches the index expressions that are column names rather than function-based expressions.
+var plainIdentifierRegexp = regexp.MustCompile(`^[^"()\s]+$`)
+
+// Dump dumps the database.
+// Only the schema is supported, and it's generated from the synced metadata.
+func (driver *Driver) Dump(ctx context.Context, database string, out io.Writer, schemaOnly bool) (string, error) {
+	if !schemaOnly {
+		return "", errors.New("Oracle only supports dumping the schema")
+	}
+
+	var dumpableDbNames []string
+	if database != "" {
+		dumpableDbNames = []string{database}
+	} else {
+		databases, err := driver.getDatabases(ctx)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to get databases")
+		}
+		for _, database := range databases {
+			if excludedDatabaseList[database.Name] {
+				continue
+			}
+			dumpableDbNames = append(dumpableDbNames, database.Name)
+		}
+	}
+
+	for _, dbName := range dumpableDbNames {
+		// The schema headers should be excluded if dumping a single database.
+		if len(dumpableDbNames) > 1 {
+			if _, err := io.WriteString(out, fmt.Sprintf(databaseHeaderFmt, dbName)); err != nil {
+				return "", err
+			}
+		}
+		databaseMetadata, err := driver.SyncDBSchema(ctx, dbName)
+		if err != nil {
+			return "", err
+		}
+		if err := writeSchema(out, databaseMetadata); err != nil {
+			return "", err
+		}
+	}
+
+	return "", nil
+}
+
+// writeSchema writes the DDL of the database metadata.
+// The objects aren't qualified with the schema name so that the DDL applies to the current schema.
+// Foreign keys are created after all tables so that the referenced tables exist.
+func writeSchema(out io.Writer, databaseMetadata *storepb.DatabaseMetadata) error {
+	var sb strings.Builder
+	for _, schema := range databaseMetadata.Schemas {
+		for _, table := range schema.Tables {
+			writeTable(&sb, table)
+		}
+	}
+	for _, schema := range databaseMetadata.Schemas {
+		for _, table := range schema.Tables {
+			for _, fk := range table.ForeignKeys {
+				writeForeignKey(&sb, schema.Name, table.Name, fk)
+			}
+		}
+	}
+	for _, schema := range databaseMetadata.Schemas {
+		for _, view := range schema.Views {
+			fmt.Fprintf(&sb, "CREATE VIEW %s AS %s;\n\n", quoteIdentifier(view.Name), strings.TrimRight(view.Definition, "; \n\t"))
+			if view.Comment != "" {
+				fmt.Fprintf(&sb, "COMMENT ON TABLE %s IS '%s';\n\n", quoteIdentifier(view.Name), escapeString(view.Comment))
+			}
+		}
+	}
+	_, err := io.WriteString(out, sb.String())
+	return err
+}
+
+func writeTable(sb *strings.Builder, table *storepb.TableMetadata) {
+	var lines []string
+	for _, column := range table.Columns {
+		line := fmt.Sprintf("    %s %s", quoteIdentifier(column.Name), column.Type)
+		if column.Default != nil {
+			line += fmt.Sprintf(" DEFAULT %s", column.Default.Value)
+		}
+		if !column.Nullable {
+			line += " NOT NULL"
+		}
+		lines = append(lines, line)
+	}
+	for _, index := range table.Indexes {
+		if !index.Primary {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("    CONSTRAINT %s PRIMARY KEY (%s)", quoteIdentifier(index.Name), joinExpressions(index.Expressions)))
+	}
+	fmt.Fprintf(sb, "CREATE TABLE %s (\n%s\n);\n\n", quoteIdentifier(table.Name), strings.Join(lines, ",\n"))
+
+	for _, index := range table.Indexes {
+		if index.Primary {
+			continue
+		}
+		kind := ""
+		switch {
+		case index.Unique:
+			kind = "UNIQUE "
+		case strings.HasPrefix(index.Type, "BITMAP"):
+			kind = "BITMAP "
+		}
+		fmt.Fprintf(sb, "CREATE %sINDEX %s ON %s (%s)", kind, quoteIdentifier(index.Name), quoteIdentifier(table.Name), joinExpressions(index.Expressions))
+		if !index.Visible {
+			_, _ = sb.WriteString(" INVISIBLE")
+		}
+		_, _ = sb.WriteString(";\n\n")
+	}
+
+	if table.Comment != "" {
+		fmt.Fprintf(sb, "COMMENT ON TABLE %s IS '%s';\n\n", quoteIdentifier(table.Name), escapeString(table.Comment))
+	}
+	for _, column := range table.Columns {
+		if column.Comment != "" {
+			fmt.Fprintf(sb, "COMMENT ON COLUMN %s.%s IS '%s';\n\n", quoteIdentifier(table.Name), quoteIdentifier(column.Name), escapeString(column.Comment))
+		}
+	}
+}
+
+func writeForeignKey(sb *strings.Builder, schemaName, tableName string, fk *storepb.ForeignKeyMetadata) {
+	referencedTable := quoteIdentifier(fk.ReferencedTable)
+	// The referenced table is qualified only if it's in another schema.
+	if fk.ReferencedSchema != schemaName {
+		referencedTable = fmt.Sprintf("%s.%s", quoteIdentifier(fk.ReferencedSchema), referencedTable)
+	}
+	fmt.Fprintf(sb, "ALTER TABLE %s ADD CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s)",
+		quoteIdentifier(tableName),
+		quoteIdentifier(fk.Name),
+		joinExpressions(fk.Columns),
+		referencedTable,
+		joinExpressions(fk.ReferencedColumns),
+	)
+	// NO ACTION is the default, and Oracle doesn't accept it in the ON DELETE clause.
+	if fk.OnDelete != "" && fk.OnDelete != "NO ACTION" {
+		fmt.Fprintf(sb, " ON DELETE %s", fk.OnDelete)
+	}
+	_, _ = sb.WriteString(";\n\n")
+}
+
+// joinExpressions quotes the column names and keeps the function-based expressions as they are.
+func joinExpressions(expressions []string) string {
+	var quoted []string
+	for _, expression := range expressions {
+		if plainIdentifierRegexp.MatchString(expression) {
+			expression = quoteIdentifier(expression)
+		}
+		quoted = append(quoted, expression)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func escapeString(s string) string {
+	return strings.ReplaceAll(s, "'", "''")
+}
+
+// Restore restores a database.
+func (driver *Driver) Restore(ctx context.Context, sc io.Reader) error {
+	buf, err := io.ReadAll(sc)
+	if err != nil {
+		return err
+	}
+	if _, err := driver.Execute(ctx, string(buf), false /* createDatabase */); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,339 @@
+// Package oracle is the plugin for Oracle driver.
+package oracle
+
+import (
+	"context"
+	"database/sql"
+	sqldriver "database/sql/driver"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	goora "github.com/sijms/go-ora/v2"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/db/util"
+)
+
+const defaultPort = 1521
+
+var (
+	// In Oracle, a Bytebase database maps to a user (schema).
+	excludedDatabaseList = map[string]bool{
+		// Skip our internal "bytebase" user.
+		"BYTEBASE": true,
+	}
+
+	// plsqlBlockRegexp matches the statements that are terminated by a "/" line instead of a semicolon.
+	plsqlBlockRegexp = regexp.MustCompile(`(?is)^(DECLARE|BEGIN|CREATE\s+(OR\s+REPLACE\s+)?((NON)?EDITIONABLE\s+)?(PROCEDURE|FUNCTION|PACKAGE|TRIGGER|TYPE|LIBRARY))\b`)
+
+	_ db.Driver        = (*Driver)(nil)
+	_ db.StreamQuerier = (*Driver)(nil)
+)
+
+func init() {
+	db.Register(db.Oracle, newDriver)
+}
+
+// Driver is the Oracle driver.
+type Driver struct {
+	connectionCtx db.ConnectionContext
+	config        db.ConnectionConfig
+
+	db           *sql.DB
+	databaseName string
+}
+
+func newDriver(db.DriverConfig) db.Driver {
+	return &Driver{}
+}
+
+// Open opens an Oracle driver.
+// The service name comes from the data source options, and the database is the schema used for the session.
+func (driver *Driver) Open(_ context.Context, _ db.Type, config db.ConnectionConfig, connCtx db.ConnectionContext) (db.Driver, error) {
+	if config.ServiceName == "" {
+		return nil, errors.New("service name is required for Oracle")
+	}
+	tlsConfig, err := config.TLSConfig.GetSslConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "sql: tls config error")
+	}
+	if tlsConfig != nil {
+		return nil, errors.New("TLS/SSL is not supported for Oracle yet")
+	}
+	driver.config = config
+	driver.connectionCtx = connCtx
+	if err := driver.switchDatabase(config.Database); err != nil {
+		return nil, err
+	}
+	return driver, nil
+}
+
+// Close closes the driver.
+func (driver *Driver) Close(context.Context) error {
+	return driver.db.Close()
+}
+
+// Ping pings the database.
+func (driver *Driver) Ping(ctx context.Context) error {
+	return driver.db.PingContext(ctx)
+}
+
+// GetType returns the database type.
+func (*Driver) GetType() db.Type {
+	return db.Oracle
+}
+
+// GetDBConnection gets a database connection.
+// The migration history table is always referenced with the owner, so we keep the current schema for the bytebase database.
+func (driver *Driver) GetDBConnection(_ context.Context, database string) (*sql.DB, error) {
+	if database != driver.databaseName && database != db.BytebaseDatabase {
+		if err := driver.switchDatabase(database); err != nil {
+			return nil, err
+		}
+	}
+	return driver.db, nil
+}
+
+// switchDatabase reopens the connection pool with the given database as the current schema.
+func (driver *Driver) switchDatabase(database string) error {
+	port := defaultPort
+	if driver.config.Port != "" {
+		p, err := strconv.Atoi(driver.config.Port)
+		if err != nil {
+			return errors.Wrapf(err, "invalid port %q", driver.config.Port)
+		}
+		port = p
+	}
+	dsn := goora.BuildUrl(driver.config.Host, port, driver.config.ServiceName, driver.config.Username, driver.config.Password, nil)
+	connector, err := (&goora.OracleDriver{}).OpenConnector(dsn)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse the connection string")
+	}
+	if driver.db != nil {
+		if err := driver.db.Close(); err != nil {
+			return err
+		}
+	}
+	driver.db = sql.OpenDB(&sessionConnector{Connector: connector, schema: database})
+	driver.databaseName = database
+	return nil
+}
+
+// sessionConnector sets the current schema on every new connection so that unqualified names resolve in the database.
+type sessionConnector struct {
+	sqldriver.Connector
+	schema string
+}
+
+// Connect implements the driver.Connector interface.
+func (c *sessionConnector) Connect(ctx context.Context) (sqldriver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if c.schema == "" {
+		return conn, nil
+	}
+	execer, ok := conn.(sqldriver.ExecerContext)
+	if !ok {
+		conn.Close()
+		return nil, errors.New("the connection doesn't support executing statements")
+	}
+	statement := fmt.Sprintf("ALTER SESSION SET CURRENT_SCHEMA = %s", quoteIdentifier(c.schema))
+	if _, err := execer.ExecContext(ctx, statement, nil); err != nil {
+		conn.Close()
+		return nil, util.FormatErrorWithQuery(err, statement)
+	}
+	return conn, nil
+}
+
+// getVersion gets the version.
+func (driver *Driver) getVersion(ctx context.Context) (string, error) {
+	query := "SELECT VERSION FROM PRODUCT_COMPONENT_VERSION WHERE PRODUCT LIKE 'Oracle%'"
+	var version string
+	if err := driver.db.QueryRowContext(ctx, query).Scan(&version); err != nil {
+		if err == sql.ErrNoRows {
+			return "", common.FormatDBErrorEmptyRowWithQuery(query)
+		}
+		return "", util.FormatErrorWithQuery(err, query)
+	}
+	return version, nil
+}
+
+// getDatabases gets all databases of an instance, which are the users not maintained by Oracle.
+func (driver *Driver) getDatabases(ctx context.Context) ([]*db.DatabaseMeta, error) {
+	query := "SELECT USERNAME FROM ALL_USERS WHERE ORACLE_MAINTAINED = 'N' ORDER BY USERNAME"
+	rows, err := driver.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	defer rows.Close()
+
+	var databases []*db.DatabaseMeta
+	for rows.Next() {
+		database := &db.DatabaseMeta{}
+		if err := rows.Scan(&database.Name); err != nil {
+			return nil, err
+		}
+		databases = append(databases, database)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	return databases, nil
+}
+
+// Execute executes a SQL statement.
+// Oracle doesn't support multiple statements in a single call, so the statement is split and executed one by one.
+// Note that Oracle commits DDL statements implicitly.
+func (driver *Driver) Execute(ctx context.Context, statement string, createDatabase bool) (int64, error) {
+	statements := splitStatement(statement)
+
+	if createDatabase {
+		var totalRowsAffected int64
+		for _, stmt := range statements {
+			sqlResult, err := driver.db.ExecContext(ctx, stmt)
+			if err != nil {
+				return 0, util.FormatErrorWithQuery(err, stmt)
+			}
+			totalRowsAffected += getRowsAffected(sqlResult)
+		}
+		return totalRowsAffected, nil
+	}
+
+	tx, err := driver.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var totalRowsAffected int64
+	for _, stmt := range statements {
+		sqlResult, err := tx.ExecContext(ctx, stmt)
+		if err != nil {
+			return 0, util.FormatErrorWithQuery(err, stmt)
+		}
+		totalRowsAffected += getRowsAffected(sqlResult)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return totalRowsAffected, nil
+}
+
+func getRowsAffected(sqlResult sql.Result) int64 {
+	rowsAffected, err := sqlResult.RowsAffected()
+	if err != nil {
+		// Since we cannot differentiate DDL and DML yet, we have to ignore the error.
+		log.Debug("rowsAffected returns error", zap.Error(err))
+		return 0
+	}
+	return rowsAffected
+}
+
+// Query queries a SQL statement.
+func (driver *Driver) Query(ctx context.Context, statement string, queryContext *db.QueryContext) ([]interface{}, error) {
+	return util.Query(ctx, db.Oracle, driver.db, statement, queryContext)
+}
+
+// QueryStream queries a SQL statement and passes the rows to the handler as they arrive.
+func (driver *Driver) QueryStream(ctx context.Context, statement string, queryContext *db.QueryContext, handler db.RowHandler) error {
+	return util.QueryStream(ctx, db.Oracle, driver.db, statement, queryContext, handler)
+}
+
+// splitStatement splits the statement following the SQL*Plus conventions.
+// SQL statements are terminated by a semicolon, which is removed because Oracle rejects it.
+// PL/SQL blocks keep their semicolons and are terminated by a line containing only "/".
+func splitStatement(statement string) []string {
+	var statements []string
+	var sb strings.Builder
+	flush := func() {
+		s := strings.TrimSpace(sb.String())
+		if trimLeadingComments(s) != "" {
+			statements = append(statements, s)
+		}
+		sb.Reset()
+	}
+
+	var quote byte
+	inBlockComment := false
+	for _, line := range strings.SplitAfter(statement, "\n") {
+		if quote == 0 && !inBlockComment && strings.TrimSpace(line) == "/" {
+			flush()
+			continue
+		}
+		for i := 0; i < len(line); i++ {
+			c := line[i]
+			var next byte
+			if i+1 < len(line) {
+				next = line[i+1]
+			}
+			switch {
+			case inBlockComment:
+				if c == '*' && next == '/' {
+					inBlockComment = false
+					_, _ = sb.WriteString("*/")
+					i++
+					continue
+				}
+			case quote != 0:
+				// The escaped quote ('') closes and reopens the quote, so it needs no special handling.
+				if c == quote {
+					quote = 0
+				}
+			case c == '-' && next == '-':
+				_, _ = sb.WriteString(line[i:])
+				i = len(line)
+				continue
+			case c == '/' && next == '*':
+				inBlockComment = true
+				_, _ = sb.WriteString("/*")
+				i++
+				continue
+			case c == '\'' || c == '"':
+				quote = c
+			case c == ';' && !plsqlBlockRegexp.MatchString(trimLeadingComments(sb.String())):
+				flush()
+				continue
+			}
+			_ = sb.WriteByte(c)
+		}
+	}
+	flush()
+	return statements
+}
+
+// trimLeadingComments trims the leading whitespaces and comments of the statement.
+func trimLeadingComments(s string) string {
+	for {
+		s = strings.TrimSpace(s)
+		switch {
+		case strings.HasPrefix(s, "--"):
+			idx := strings.Index(s, "\n")
+			if idx < 0 {
+				return ""
+			}
+			s = s[idx+1:]
+		case strings.HasPrefix(s, "/*"):
+			idx := strings.Index(s, "*/")
+			if idx < 0 {
+				return ""
+			}
+			s = s[idx+2:]
+		default:
+			return s
+		}
+	}
+}
+
+// quoteIdentifier quotes the identifier with double quotes.
+func quoteIdentifier(s string) string {
+	return fmt.Sprintf(`"%s"`, strings.ReplaceAll(s, `"`, `""`))
+}
@@ -0,0 +1,132 @@
+package oracle
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+func TestSplitStatement(t *testing.T) {
+	tests := []struct {
+		statement string
+		want      []string
+	}{
+		{
+			statement: "SELECT 1 FROM DUAL;",
+			want:      []string{"SELECT 1 FROM DUAL"},
+		},
+		{
+			statement: "CREATE TABLE t (id INT);\nINSERT INTO t VALUES (1); INSERT INTO t VALUES (2)",
+			want:      []string{"CREATE TABLE t (id INT)", "INSERT INTO t VALUES (1)", "INSERT INTO t VALUES (2)"},
+		},
+		{
+			statement: "INSERT INTO t VALUES ('a;b', 'it''s');\nCREATE TABLE \"a;b\" (id INT);",
+			want:      []string{"INSERT INTO t VALUES ('a;b', 'it''s')", "CREATE TABLE \"a;b\" (id INT)"},
+		},
+		{
+			statement: "-- comment;\nSELECT 1 FROM DUAL; /* comment; */\n-- trailing comment",
+			want:      []string{"-- comment;\nSELECT 1 FROM DUAL"},
+		},
+		{
+			statement: "CREATE OR REPLACE PROCEDURE p AS\nBEGIN\n  NULL;\nEND;\n/\nBEGIN\n  p;\nEND;\n/\nSELECT 1 FROM DUAL;",
+			want:      []string{"CREATE OR REPLACE PROCEDURE p AS\nBEGIN\n  NULL;\nEND;", "BEGIN\n  p;\nEND;", "SELECT 1 FROM DUAL"},
+		},
+		{
+			statement: "SELECT 1 FROM DUAL\n/\n",
+			want:      []string{"SELECT 1 FROM DUAL"},
+		},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		a.Equal(test.want, splitStatement(test.statement), test.statement)
+	}
+}
+
+func TestWriteSchema(t *testing.T) {
+	databaseMetadata := &storepb.DatabaseMetadata{
+		Name: "HELLO",
+		Schemas: []*storepb.SchemaMetadata{
+			{
+				Name: "HELLO",
+				Tables: []*storepb.TableMetadata{
+					{
+						Name:    "AUTHOR",
+						Comment: "The author's table",
+						Columns: []*storepb.ColumnMetadata{
+							{Name: "ID", Type: "NUMBER(10)"},
+							{Name: "NAME", Type: "VARCHAR2(255 BYTE)", Nullable: true, Default: &wrapperspb.StringValue{Value: "'unknown'"}, Comment: "Pen name"},
+						},
+						Indexes: []*storepb.IndexMetadata{
+							{Name: "PK_AUTHOR", Type: "NORMAL", Primary: true, Unique: true, Visible: true, Expressions: []string{"ID"}},
+							{Name: "IDX_NAME", Type: "FUNCTION-BASED NORMAL", Visible: false, Expressions: []string{`UPPER("NAME")`}},
+						},
+					},
+					{
+						Name: "BOOK",
+						Columns: []*storepb.ColumnMetadata{
+							{Name: "AUTHOR_ID", Type: "NUMBER(10)"},
+							{Name: "EDITOR_ID", Type: "NUMBER(10)"},
+						},
+						ForeignKeys: []*storepb.ForeignKeyMetadata{
+							{
+								Name:              "FK_AUTHOR",
+								Columns:           []string{"AUTHOR_ID"},
+								ReferencedSchema:  "HELLO",
+								ReferencedTable:   "AUTHOR",
+								ReferencedColumns: []string{"ID"},
+								OnDelete:          "CASCADE",
+							},
+							{
+								Name:              "FK_EDITOR",
+								Columns:           []string{"EDITOR_ID"},
+								ReferencedSchema:  "WORLD",
+								ReferencedTable:   "EDITOR",
+								ReferencedColumns: []string{"ID"},
+								OnDelete:          "NO ACTION",
+							},
+						},
+					},
+				},
+				Views: []*storepb.ViewMetadata{
+					{Name: "V", Definition: "SELECT 1 AS ONE FROM DUAL"},
+				},
+			},
+		},
+	}
+	want := strings.Join([]string{
+		`CREATE TABLE "AUTHOR" (`,
+		`    "ID" NUMBER(10) NOT NULL,`,
+		`    "NAME" VARCHAR2(255 BYTE) DEFAULT 'unknown',`,
+		`    CONSTRAINT "PK_AUTHOR" PRIMARY KEY ("ID")`,
+		`);`,
+		``,
+		`CREATE INDEX "IDX_NAME" ON "AUTHOR" (UPPER("NAME")) INVISIBLE;`,
+		``,
+		`COMMENT ON TABLE "AUTHOR" IS 'The author''s table';`,
+		``,
+		`COMMENT ON COLUMN "AUTHOR"."NAME" IS 'Pen name';`,
+		``,
+		`CREATE TABLE "BOOK" (`,
+		`    "AUTHOR_ID" NUMBER(10) NOT NULL,`,
+		`    "EDITOR_ID" NUMBER(10) NOT NULL`,
+		`);`,
+		``,
+		`ALTER TABLE "BOOK" ADD CONSTRAINT "FK_AUTHOR" FOREIGN KEY ("AUTHOR_ID") REFERENCES "AUTHOR" ("ID") ON DELETE CASCADE;`,
+		``,
+		`ALTER TABLE "BOOK" ADD CONSTRAINT "FK_EDITOR" FOREIGN KEY ("EDITOR_ID") REFERENCES "WORLD"."EDITOR" ("ID");`,
+		``,
+		`CREATE VIEW "V" AS SELECT 1 AS ONE FROM DUAL;`,
+		``,
+		``,
+	}, "\n")
+
+	var sb strings.Builder
+	a := require.New(t)
+	a.NoError(writeSchema(&sb, databaseMetadata))
+	a.Equal(want, sb.String())
+}
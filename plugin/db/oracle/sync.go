@@ -0,0 +1,418 @@
+package oracle
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/db/util"
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+// SyncInstance syncs the instance.
+func (driver *Driver) SyncInstance(ctx context.Context) (*db.InstanceMeta, error) {
+	version, err := driver.getVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	databases, err := driver.getDatabases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var userList []db.User
+	var databaseList []db.DatabaseMeta
+	for _, database := range databases {
+		if excludedDatabaseList[database.Name] {
+			continue
+		}
+		// The users that aren't maintained by Oracle are both the users and the databases.
+		userList = append(userList, db.User{Name: database.Name})
+		databaseList = append(databaseList, *database)
+	}
+
+	return &db.InstanceMeta{
+		Version:      version,
+		UserList:     userList,
+		DatabaseList: databaseList,
+	}, nil
+}
+
+// SyncDBSchema syncs a single database schema.
+// The database is an Oracle user, and its objects are synced into a schema with the same name.
+func (driver *Driver) SyncDBSchema(ctx context.Context, databaseName string) (*storepb.DatabaseMetadata, error) {
+	databases, err := driver.getDatabases(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get databases")
+	}
+	found := false
+	for _, database := range databases {
+		if database.Name == databaseName {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, common.Errorf(common.NotFound, "database %q not found", databaseName)
+	}
+
+	txn, err := driver.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer txn.Rollback()
+
+	tables, err := getTables(ctx, txn, databaseName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get tables from database %q", databaseName)
+	}
+	views, err := getViews(ctx, txn, databaseName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get views from database %q", databaseName)
+	}
+
+	if err := txn.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &storepb.DatabaseMetadata{
+		Name: databaseName,
+		Schemas: []*storepb.SchemaMetadata{
+			{
+				Name:   databaseName,
+				Tables: tables,
+				Views:  views,
+			},
+		},
+	}, nil
+}
+
+// getTables gets all tables of a schema.
+func getTables(ctx context.Context, txn *sql.Tx, schemaName string) ([]*storepb.TableMetadata, error) {
+	columnMap, err := getColumns(ctx, txn, schemaName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get columns")
+	}
+	indexMap, err := getIndexes(ctx, txn, schemaName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get indexes")
+	}
+	foreignKeysMap, err := getForeignKeys(ctx, txn, schemaName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get foreign keys")
+	}
+
+	var tables []*storepb.TableMetadata
+	// Skip the dropped tables in the recycle bin, the nested tables and the secondary objects created by domain indexes.
+	query := `
+		SELECT
+			t.TABLE_NAME,
+			NVL(t.NUM_ROWS, 0),
+			c.COMMENTS
+		FROM ALL_TABLES t
+		LEFT JOIN ALL_TAB_COMMENTS c ON t.OWNER = c.OWNER AND t.TABLE_NAME = c.TABLE_NAME
+		WHERE t.OWNER = :1 AND t.DROPPED = 'NO' AND t.NESTED = 'NO' AND t.SECONDARY = 'N'
+		ORDER BY t.TABLE_NAME`
+	rows, err := txn.QueryContext(ctx, query, schemaName)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		table := &storepb.TableMetadata{}
+		var comment sql.NullString
+		if err := rows.Scan(
+			&table.Name,
+			&table.RowCount,
+			&comment,
+		); err != nil {
+			return nil, err
+		}
+		table.Comment = comment.String
+		table.Columns = columnMap[table.Name]
+		table.Indexes = indexMap[table.Name]
+		table.ForeignKeys = foreignKeysMap[table.Name]
+
+		tables = append(tables, table)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	return tables, nil
+}
+
+// getColumns gets all columns of a schema.
+func getColumns(ctx context.Context, txn *sql.Tx, schemaName string) (map[string][]*storepb.ColumnMetadata, error) {
+	columnMap := make(map[string][]*storepb.ColumnMetadata)
+	query := `
+		SELECT
+			c.TABLE_NAME,
+			c.COLUMN_NAME,
+			c.COLUMN_ID,
+			c.DATA_DEFAULT,
+			c.NULLABLE,
+			c.DATA_TYPE,
+			c.DATA_LENGTH,
+			c.DATA_PRECISION,
+			c.DATA_SCALE,
+			c.CHAR_LENGTH,
+			c.CHAR_USED,
+			cc.COMMENTS
+		FROM ALL_TAB_COLUMNS c
+		JOIN ALL_TABLES t ON c.OWNER = t.OWNER AND c.TABLE_NAME = t.TABLE_NAME
+		LEFT JOIN ALL_COL_COMMENTS cc ON c.OWNER = cc.OWNER AND c.TABLE_NAME = cc.TABLE_NAME AND c.COLUMN_NAME = cc.COLUMN_NAME
+		WHERE c.OWNER = :1
+		ORDER BY c.TABLE_NAME, c.COLUMN_ID`
+	rows, err := txn.QueryContext(ctx, query, schemaName)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var tableName, nullable, dataType string
+		var defaultStr, charUsed, comment sql.NullString
+		var dataLength, precision, scale, charLength sql.NullInt64
+		column := &storepb.ColumnMetadata{}
+		if err := rows.Scan(
+			&tableName,
+			&column.Name,
+			&column.Position,
+			&defaultStr,
+			&nullable,
+			&dataType,
+			&dataLength,
+			&precision,
+			&scale,
+			&charLength,
+			&charUsed,
+			&comment,
+		); err != nil {
+			return nil, err
+		}
+		// The default expression is stored as it was typed, including the trailing whitespaces.
+		if defaultStr.Valid {
+			if v := strings.TrimSpace(defaultStr.String); v != "" {
+				column.Default = &wrapperspb.StringValue{Value: v}
+			}
+		}
+		column.Nullable = nullable == "Y"
+		column.Type = getColumnType(dataType, dataLength, precision, scale, charLength, charUsed.String)
+		column.Comment = comment.String
+
+		columnMap[tableName] = append(columnMap[tableName], column)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	return columnMap, nil
+}
+
+// getColumnType returns the full column type, e.g. VARCHAR2(255 BYTE), NUMBER(10,2).
+func getColumnType(dataType string, dataLength, precision, scale, charLength sql.NullInt64, charUsed string) string {
+	switch dataType {
+	case "VARCHAR2", "CHAR":
+		semantics := "BYTE"
+		if charUsed == "C" {
+			semantics = "CHAR"
+		}
+		return fmt.Sprintf("%s(%d %s)", dataType, charLength.Int64, semantics)
+	case "NVARCHAR2", "NCHAR":
+		return fmt.Sprintf("%s(%d)", dataType, charLength.Int64)
+	case "RAW":
+		return fmt.Sprintf("%s(%d)", dataType, dataLength.Int64)
+	case "NUMBER":
+		switch {
+		case !precision.Valid && scale.Valid && scale.Int64 == 0:
+			return "INTEGER"
+		case !precision.Valid:
+			return dataType
+		case !scale.Valid || scale.Int64 == 0:
+			return fmt.Sprintf("%s(%d)", dataType, precision.Int64)
+		default:
+			return fmt.Sprintf("%s(%d,%d)", dataType, precision.Int64, scale.Int64)
+		}
+	case "FLOAT":
+		if precision.Valid {
+			return fmt.Sprintf("%s(%d)", dataType, precision.Int64)
+		}
+	}
+	// Types such as TIMESTAMP(6) and INTERVAL DAY(2) TO SECOND(6) already contain the precision.
+	return dataType
+}
+
+// getIndexes gets all indexes of a schema.
+// The indexes backing the primary key and unique constraints are included, and the LOB indexes are skipped.
+func getIndexes(ctx context.Context, txn *sql.Tx, schemaName string) (map[string][]*storepb.IndexMetadata, error) {
+	indexMap := make(map[string][]*storepb.IndexMetadata)
+	query := `
+		SELECT
+			i.TABLE_NAME,
+			i.INDEX_NAME,
+			i.INDEX_TYPE,
+			i.UNIQUENESS,
+			NVL2(c.CONSTRAINT_NAME, 1, 0),
+			i.VISIBILITY,
+			ic.COLUMN_NAME,
+			ie.COLUMN_EXPRESSION
+		FROM ALL_INDEXES i
+		JOIN ALL_IND_COLUMNS ic ON i.OWNER = ic.INDEX_OWNER AND i.INDEX_NAME = ic.INDEX_NAME
+		LEFT JOIN ALL_IND_EXPRESSIONS ie ON ic.INDEX_OWNER = ie.INDEX_OWNER AND ic.INDEX_NAME = ie.INDEX_NAME AND ic.COLUMN_POSITION = ie.COLUMN_POSITION
+		LEFT JOIN ALL_CONSTRAINTS c ON i.TABLE_OWNER = c.OWNER AND i.TABLE_NAME = c.TABLE_NAME AND i.INDEX_NAME = c.INDEX_NAME AND c.CONSTRAINT_TYPE = 'P'
+		WHERE i.TABLE_OWNER = :1 AND i.INDEX_TYPE != 'LOB'
+		ORDER BY i.TABLE_NAME, i.INDEX_NAME, ic.COLUMN_POSITION`
+	rows, err := txn.QueryContext(ctx, query, schemaName)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	defer rows.Close()
+
+	indexes := make(map[string]map[string]*storepb.IndexMetadata)
+	for rows.Next() {
+		var tableName, indexName, indexType, uniqueness, visibility, columnName string
+		var primary int
+		var expression sql.NullString
+		if err := rows.Scan(
+			&tableName,
+			&indexName,
+			&indexType,
+			&uniqueness,
+			&primary,
+			&visibility,
+			&columnName,
+			&expression,
+		); err != nil {
+			return nil, err
+		}
+		if _, ok := indexes[tableName]; !ok {
+			indexes[tableName] = make(map[string]*storepb.IndexMetadata)
+		}
+		index, ok := indexes[tableName][indexName]
+		if !ok {
+			index = &storepb.IndexMetadata{
+				Name:    indexName,
+				Type:    indexType,
+				Unique:  uniqueness == "UNIQUE",
+				Primary: primary == 1,
+				Visible: visibility == "VISIBLE",
+			}
+			indexes[tableName][indexName] = index
+			indexMap[tableName] = append(indexMap[tableName], index)
+		}
+		// The function-based indexes reference hidden virtual columns, so we use the expression instead.
+		if expression.Valid {
+			index.Expressions = append(index.Expressions, expression.String)
+		} else {
+			index.Expressions = append(index.Expressions, columnName)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	return indexMap, nil
+}
+
+// getForeignKeys gets all foreign keys of a schema.
+func getForeignKeys(ctx context.Context, txn *sql.Tx, schemaName string) (map[string][]*storepb.ForeignKeyMetadata, error) {
+	foreignKeysMap := make(map[string][]*storepb.ForeignKeyMetadata)
+	query := `
+		SELECT
+			c.TABLE_NAME,
+			c.CONSTRAINT_NAME,
+			cc.COLUMN_NAME,
+			rc.OWNER,
+			rc.TABLE_NAME,
+			rcc.COLUMN_NAME,
+			c.DELETE_RULE
+		FROM ALL_CONSTRAINTS c
+		JOIN ALL_CONS_COLUMNS cc ON c.OWNER = cc.OWNER AND c.CONSTRAINT_NAME = cc.CONSTRAINT_NAME
+		JOIN ALL_CONSTRAINTS rc ON c.R_OWNER = rc.OWNER AND c.R_CONSTRAINT_NAME = rc.CONSTRAINT_NAME
+		JOIN ALL_CONS_COLUMNS rcc ON rc.OWNER = rcc.OWNER AND rc.CONSTRAINT_NAME = rcc.CONSTRAINT_NAME AND cc.POSITION = rcc.POSITION
+		WHERE c.OWNER = :1 AND c.CONSTRAINT_TYPE = 'R'
+		ORDER BY c.TABLE_NAME, c.CONSTRAINT_NAME, cc.POSITION`
+	rows, err := txn.QueryContext(ctx, query, schemaName)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	defer rows.Close()
+
+	foreignKeys := make(map[string]map[string]*storepb.ForeignKeyMetadata)
+	for rows.Next() {
+		var tableName, fkName, columnName, referencedSchema, referencedTable, referencedColumn, onDelete string
+		if err := rows.Scan(
+			&tableName,
+			&fkName,
+			&columnName,
+			&referencedSchema,
+			&referencedTable,
+			&referencedColumn,
+			&onDelete,
+		); err != nil {
+			return nil, err
+		}
+		if _, ok := foreignKeys[tableName]; !ok {
+			foreignKeys[tableName] = make(map[string]*storepb.ForeignKeyMetadata)
+		}
+		fk, ok := foreignKeys[tableName][fkName]
+		if !ok {
+			// Oracle doesn't support ON UPDATE actions.
+			fk = &storepb.ForeignKeyMetadata{
+				Name:             fkName,
+				ReferencedSchema: referencedSchema,
+				ReferencedTable:  referencedTable,
+				OnDelete:         onDelete,
+			}
+			foreignKeys[tableName][fkName] = fk
+			foreignKeysMap[tableName] = append(foreignKeysMap[tableName], fk)
+		}
+		fk.Columns = append(fk.Columns, columnName)
+		fk.ReferencedColumns = append(fk.ReferencedColumns, referencedColumn)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	return foreignKeysMap, nil
+}
+
+// getViews gets all views of a schema.
+func getViews(ctx context.Context, txn *sql.Tx, schemaName string) ([]*storepb.ViewMetadata, error) {
+	var views []*storepb.ViewMetadata
+	query := `
+		SELECT
+			v.VIEW_NAME,
+			v.TEXT,
+			c.COMMENTS
+		FROM ALL_VIEWS v
+		LEFT JOIN ALL_TAB_COMMENTS c ON v.OWNER = c.OWNER AND v.VIEW_NAME = c.TABLE_NAME
+		WHERE v.OWNER = :1
+		ORDER BY v.VIEW_NAME`
+	rows, err := txn.QueryContext(ctx, query, schemaName)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		view := &storepb.ViewMetadata{}
+		var definition, comment sql.NullString
+		if err := rows.Scan(
+			&view.Name,
+			&definition,
+			&comment,
+		); err != nil {
+			return nil, err
+		}
+		view.Definition = strings.TrimSpace(definition.String)
+		view.Comment = comment.String
+		views = append(views, view)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, util.FormatErrorWithQuery(err, query)
+	}
+	return views, nil
+}
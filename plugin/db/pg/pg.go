@@ -5,6 +5,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strconv"
 	"strings"
 
 	// Import pg driver.
@@ -41,7 +42,9 @@ var (
 	// driverName is the driver name that our driver dependence register, now is "pgx".
 	driverName = "pgx"
 
-	_ db.Driver = (*Driver)(nil)
+	_ db.Driver        = (*Driver)(nil)
+	_ db.StreamQuerier = (*Driver)(nil)
+	_ db.QueryCanceler = (*Driver)(nil)
 )
 
 func init() {
@@ -51,6 +54,7 @@ func init() {
 // Driver is the Postgres driver.
 type Driver struct {
 	dbBinDir      string
+	archiveDir    string
 	connectionCtx db.ConnectionContext
 	config        db.ConnectionConfig
 
@@ -60,11 +64,15 @@ type Driver struct {
 
 	// strictDatabase should be used only if the user gives only a database instead of a whole instance to access.
 	strictDatabase string
+
+	// rollbackGenerator is set by EnableRollbackSQLGeneration to generate the rollback statement for the DMLs.
+	rollbackGenerator *rollbackGenerator
 }
 
 func newDriver(config db.DriverConfig) db.Driver {
 	return &Driver{
-		dbBinDir: config.DbBinDir,
+		dbBinDir:   config.DbBinDir,
+		archiveDir: config.BinlogDir,
 	}
 }
 
@@ -336,6 +344,22 @@ func (driver *Driver) Execute(ctx context.Context, statement string, createDatab
 		return 0, err
 	}
 
+	if driver.rollbackGenerator != nil {
+		// Execute the statements one by one to capture the before-images of the affected rows for each statement.
+		driver.rollbackGenerator = &rollbackGenerator{}
+		for _, stmt := range remainingStmts {
+			rowsAffected, err := driver.rollbackGenerator.execute(ctx, tx, stmt)
+			if err != nil {
+				return 0, err
+			}
+			totalRowsAffected += rowsAffected
+		}
+		if err := tx.Commit(); err != nil {
+			return 0, err
+		}
+		return totalRowsAffected, nil
+	}
+
 	sqlResult, err := tx.ExecContext(ctx, strings.Join(remainingStmts, "\n"))
 	if err != nil {
 		return 0, err
@@ -415,27 +439,54 @@ func (driver *Driver) GetCurrentDatabaseOwner() (string, error) {
 
 // Query queries a SQL statement.
 func (driver *Driver) Query(ctx context.Context, statement string, queryContext *db.QueryContext) ([]interface{}, error) {
+	collector := &util.RowCollector{}
+	if err := driver.QueryStream(ctx, statement, queryContext, collector); err != nil {
+		return nil, err
+	}
+	return collector.Result(), nil
+}
+
+// QueryStream queries a SQL statement and passes the rows to the handler as they arrive.
+func (driver *Driver) QueryStream(ctx context.Context, statement string, queryContext *db.QueryContext, handler db.RowHandler) error {
 	singleSQLs, err := parser.SplitMultiSQL(parser.Postgres, statement)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	if len(singleSQLs) == 0 {
-		return nil, nil
+		return nil
 	}
-
 	// If the statement is an INSERT, UPDATE, or DELETE statement, we will call execute instead of query and return the number of rows affected.
 	// https://github.com/postgres/postgres/blob/master/src/bin/psql/common.c#L969
 	if len(singleSQLs) == 1 && util.IsAffectedRowsStatement(singleSQLs[0].Text) {
+		if queryContext.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, queryContext.Timeout)
+			defer cancel()
+		}
 		affectedRows, err := driver.Execute(ctx, singleSQLs[0].Text, false)
 		if err != nil {
-			return nil, err
+			return err
+		}
+		if err := handler.HandleColumns([]string{"Affected Rows"}, []string{"INT"}); err != nil {
+			return err
 		}
-		field := []string{"Affected Rows"}
-		types := []string{"INT"}
-		rows := [][]interface{}{{affectedRows}}
-		return []interface{}{field, types, rows}, nil
+		return handler.HandleRow([]interface{}{affectedRows})
 	}
-	return util.Query(ctx, db.Postgres, driver.db, statement, queryContext)
+	return util.QueryStream(ctx, db.Postgres, driver.db, statement, queryContext, handler)
+}
+
+// CancelQuery cancels the query running in the backend with pg_cancel_backend, and the backend is kept.
+func (driver *Driver) CancelQuery(ctx context.Context, sessionID string) error {
+	pid, err := strconv.Atoi(sessionID)
+	if err != nil {
+		return errors.Wrapf(err, "invalid backend pid %q", sessionID)
+	}
+	query := "SELECT pg_cancel_backend($1)"
+	// The result is false if the backend has gone, and there is nothing to cancel.
+	if _, err := driver.db.ExecContext(ctx, query, pid); err != nil {
+		return util.FormatErrorWithQuery(err, query)
+	}
+	return nil
 }
 
 func (driver *Driver) switchDatabase(dbName string) error {
@@ -0,0 +1,792 @@
+package pg
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db/util"
+	bbs3 "github.com/bytebase/bytebase/plugin/storage/s3"
+	"github.com/bytebase/bytebase/resources/utils"
+)
+
+// The PITR of PostgreSQL is based on the base backups and the WAL archive of the instance.
+// The WAL files are streamed from the server by pg_receivewal with a physical replication slot, so that the server
+// retains the WAL files until they are archived. The base backups are taken by pg_basebackup in the tar format.
+// To recover to a point in time, we start a temporary server on the latest base backup before the target time,
+// which replays the archived WAL files up to the target time.
+
+const (
+	// pitrReplicationSlotName is the physical replication slot used to archive the WAL files.
+	pitrReplicationSlotName = "bytebase_pitr"
+	walDirName              = "wal"
+	baseBackupDirName       = "basebackup"
+	baseBackupFileName      = "base.tar.gz"
+	baseBackupWALFileName   = "pg_wal.tar.gz"
+	backupLabelFileName     = "backup_label"
+	partialWALFileSuffix    = ".partial"
+	// replicationSlotFileName is the marker file in the WAL archive, which exists while the replication slot is created by us.
+	// It tells whether to drop the replication slot after the PITR is disabled without connecting to the instance.
+	replicationSlotFileName = "replication_slot"
+	// archiveWALTimeout is the maximum duration of streaming the WAL files in a single run.
+	archiveWALTimeout = 10 * time.Minute
+	// maxReplicationSlotLagBytes is the size of the WAL retained by the replication slot to warn about.
+	// The server retains the WAL files until they are archived, which can run out of the disk if the archiving keeps failing.
+	maxReplicationSlotLagBytes = 16 * 1024 * 1024 * 1024
+	// maxRecoveryConnectionFailure is the maximum number of consecutive connection failures when waiting for the recovery.
+	maxRecoveryConnectionFailure = 60
+	// maxDeleteObjectCount is the maximum number of objects deleted in a single request to the cloud storage.
+	maxDeleteObjectCount = 1000
+)
+
+var (
+	walFileNameRegexp    = regexp.MustCompile(`^[0-9A-F]{24}$`)
+	walHistoryFileRegexp = regexp.MustCompile(`^[0-9A-F]{8}\.history$`)
+	backupLabelRegexp    = regexp.MustCompile(`START WAL LOCATION: \S+ \(file ([0-9A-F]{24})\)`)
+	binaryVersionRegexp  = regexp.MustCompile(`\(PostgreSQL\) (\d+)`)
+)
+
+// BaseBackup is a base backup of the PostgreSQL instance taken by pg_basebackup.
+type BaseBackup struct {
+	// Name is the directory name of the base backup in the format of "<EndTs>-<StartWALFile>".
+	Name string
+	// StartWALFile is the WAL file where the base backup starts.
+	// The WAL files before it are not needed to recover from the base backup.
+	StartWALFile string
+	// EndTs is the time when the base backup is done, in UNIX timestamp in seconds.
+	// The base backup can only be used to recover to a point in time after it.
+	EndTs int64
+}
+
+func newBaseBackup(name string) (*BaseBackup, error) {
+	parts := strings.SplitN(name, "-", 2)
+	if len(parts) != 2 || !walFileNameRegexp.MatchString(parts[1]) {
+		return nil, errors.Errorf("invalid base backup name %q", name)
+	}
+	endTs, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid base backup name %q", name)
+	}
+	return &BaseBackup{
+		Name:         name,
+		StartWALFile: parts[1],
+		EndTs:        endTs,
+	}, nil
+}
+
+// CheckPITR checks whether the instance meets the requirements of PITR with the WAL archive.
+func (driver *Driver) CheckPITR(ctx context.Context) error {
+	var walLevel string
+	if err := driver.db.QueryRowContext(ctx, "SHOW wal_level").Scan(&walLevel); err != nil {
+		return util.FormatErrorWithQuery(err, "SHOW wal_level")
+	}
+	if walLevel != "replica" && walLevel != "logical" {
+		return errors.Errorf("wal_level must be replica or logical, but got %q", walLevel)
+	}
+
+	var maxWALSenders int
+	if err := driver.db.QueryRowContext(ctx, "SHOW max_wal_senders").Scan(&maxWALSenders); err != nil {
+		return util.FormatErrorWithQuery(err, "SHOW max_wal_senders")
+	}
+	if maxWALSenders == 0 {
+		return errors.Errorf("max_wal_senders must be greater than 0")
+	}
+
+	canReplicate := false
+	query := "SELECT rolsuper OR rolreplication FROM pg_roles WHERE rolname = current_user"
+	if err := driver.db.QueryRowContext(ctx, query).Scan(&canReplicate); err != nil {
+		return util.FormatErrorWithQuery(err, query)
+	}
+	if !canReplicate {
+		return errors.Errorf("user %q must be a superuser or have the REPLICATION attribute", driver.config.Username)
+	}
+
+	var tablespaceCount int
+	query = "SELECT COUNT(1) FROM pg_tablespace WHERE spcname NOT IN ('pg_default', 'pg_global')"
+	if err := driver.db.QueryRowContext(ctx, query).Scan(&tablespaceCount); err != nil {
+		return util.FormatErrorWithQuery(err, query)
+	}
+	if tablespaceCount > 0 {
+		return errors.Errorf("PITR is not supported for the instance with user-defined tablespaces")
+	}
+
+	// The recovery runs on the bundled PostgreSQL binaries, which can only read the data files of the same major version.
+	var serverVersionNum int
+	if err := driver.db.QueryRowContext(ctx, "SHOW server_version_num").Scan(&serverVersionNum); err != nil {
+		return util.FormatErrorWithQuery(err, "SHOW server_version_num")
+	}
+	binaryMajorVersion, err := driver.getBinaryMajorVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if serverVersionNum/10000 != binaryMajorVersion {
+		return errors.Errorf("PITR requires PostgreSQL %d, but the instance is PostgreSQL %d", binaryMajorVersion, serverVersionNum/10000)
+	}
+	return nil
+}
+
+func (driver *Driver) getBinaryMajorVersion(ctx context.Context) (int, error) {
+	var out bytes.Buffer
+	cmd := exec.CommandContext(ctx, filepath.Join(driver.dbBinDir, "postgres"), "--version")
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		return 0, errors.Wrapf(err, "failed to get the version of %q", cmd.String())
+	}
+	matches := binaryVersionRegexp.FindStringSubmatch(out.String())
+	if len(matches) != 2 {
+		return 0, errors.Errorf("failed to parse the PostgreSQL version from %q", out.String())
+	}
+	return strconv.Atoi(matches[1])
+}
+
+// WriteCommitRecord commits a transaction to write a commit record with the current time into the WAL.
+// The recovery to a point in time stops at the first commit record after it, so there must be one before the recovery.
+func (driver *Driver) WriteCommitRecord(ctx context.Context) error {
+	// txid_current() assigns a transaction ID, so the implicit transaction writes a commit record.
+	if _, err := driver.db.ExecContext(ctx, "SELECT txid_current()"); err != nil {
+		return errors.Wrap(err, "failed to write a commit record")
+	}
+	return nil
+}
+
+// ArchiveWAL streams the WAL files generated so far from the server to the local WAL archive.
+// If client is not nil, the completed WAL files are uploaded to the cloud storage, and only the latest one is kept locally
+// for pg_receivewal to resume from.
+func (driver *Driver) ArchiveWAL(ctx context.Context, client *bbs3.Client) error {
+	walDir := driver.getWALDir()
+	if err := os.MkdirAll(walDir, os.ModePerm); err != nil {
+		return errors.Wrapf(err, "failed to create WAL directory %q", walDir)
+	}
+	if err := driver.createReplicationSlot(ctx); err != nil {
+		return err
+	}
+	if client != nil {
+		if err := driver.downloadLatestWALFile(ctx, client); err != nil {
+			return err
+		}
+	}
+
+	var hasNewWAL bool
+	var endLSN string
+	var lagBytes int64
+	query := "SELECT pg_current_wal_lsn() > COALESCE(restart_lsn, '0/0'), pg_current_wal_lsn()::text, COALESCE(pg_wal_lsn_diff(pg_current_wal_lsn(), restart_lsn), 0)::bigint FROM pg_replication_slots WHERE slot_name = $1"
+	if err := driver.db.QueryRowContext(ctx, query, pitrReplicationSlotName).Scan(&hasNewWAL, &endLSN, &lagBytes); err != nil {
+		return util.FormatErrorWithQuery(err, query)
+	}
+	if lagBytes > maxReplicationSlotLagBytes {
+		log.Warn("The replication slot for PITR retains too much WAL on the server, the archiving may keep failing",
+			zap.String("slot", pitrReplicationSlotName),
+			zap.Int64("lagBytes", lagBytes),
+		)
+	}
+	// pg_receivewal keeps waiting for new WAL if it has received all, so we skip it if there is no new WAL.
+	if hasNewWAL {
+		// pg_receivewal stops only after receiving the WAL beyond the end position.
+		endPos, err := getPreviousLSN(endLSN)
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithTimeout(ctx, archiveWALTimeout)
+		defer cancel()
+		args := append(driver.getConnectionArgs(),
+			fmt.Sprintf("--directory=%s", walDir),
+			fmt.Sprintf("--slot=%s", pitrReplicationSlotName),
+			fmt.Sprintf("--endpos=%s", endPos),
+			"--no-loop",
+		)
+		if err := driver.runCommand(ctx, "pg_receivewal", args...); err != nil {
+			return errors.Wrap(err, "failed to archive WAL files")
+		}
+	}
+
+	if client != nil {
+		if err := driver.uploadWALFiles(ctx, client); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getPreviousLSN returns the LSN right before the LSN in the format of "XXX/XXX".
+func getPreviousLSN(lsn string) (string, error) {
+	var high, low uint64
+	if _, err := fmt.Sscanf(lsn, "%X/%X", &high, &low); err != nil {
+		return "", errors.Wrapf(err, "invalid LSN %q", lsn)
+	}
+	pos := high<<32 | low
+	if pos == 0 {
+		return "", errors.Errorf("invalid LSN %q", lsn)
+	}
+	pos--
+	return fmt.Sprintf("%X/%X", pos>>32, pos&0xFFFFFFFF), nil
+}
+
+func (driver *Driver) createReplicationSlot(ctx context.Context) error {
+	query := "SELECT pg_create_physical_replication_slot($1, true) WHERE NOT EXISTS (SELECT 1 FROM pg_replication_slots WHERE slot_name = $1)"
+	if _, err := driver.db.ExecContext(ctx, query, pitrReplicationSlotName); err != nil {
+		return errors.Wrapf(err, "failed to create replication slot %q", pitrReplicationSlotName)
+	}
+	markerPath := filepath.Join(driver.getWALDir(), replicationSlotFileName)
+	if err := os.WriteFile(markerPath, []byte(pitrReplicationSlotName), 0600); err != nil {
+		return errors.Wrapf(err, "failed to write the replication slot marker %q", markerPath)
+	}
+	return nil
+}
+
+// HasReplicationSlot returns true if the replication slot for PITR has been created for the instance
+// whose WAL archive is under the binlog directory, and hasn't been dropped.
+func HasReplicationSlot(binlogDir string) bool {
+	_, err := os.Stat(filepath.Join(binlogDir, walDirName, replicationSlotFileName))
+	return err == nil
+}
+
+// DropReplicationSlot drops the replication slot for PITR, so that the server stops retaining the WAL files for archiving.
+// It should be called after the PITR is disabled for the instance, the WAL archive is kept for recovering.
+func (driver *Driver) DropReplicationSlot(ctx context.Context) error {
+	// The slot of a terminated pg_receivewal may be still active for a while, and the drop fails until it's released.
+	query := "SELECT pg_drop_replication_slot(slot_name) FROM pg_replication_slots WHERE slot_name = $1"
+	if _, err := driver.db.ExecContext(ctx, query, pitrReplicationSlotName); err != nil {
+		return errors.Wrapf(err, "failed to drop replication slot %q", pitrReplicationSlotName)
+	}
+	markerPath := filepath.Join(driver.getWALDir(), replicationSlotFileName)
+	if err := os.Remove(markerPath); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "failed to remove the replication slot marker %q", markerPath)
+	}
+	return nil
+}
+
+// downloadLatestWALFile downloads the latest WAL file from the cloud storage if there is no WAL file locally.
+// pg_receivewal streams from the current position of the server if there is no WAL file in the directory,
+// which would leave a gap in the WAL archive.
+func (driver *Driver) downloadLatestWALFile(ctx context.Context, client *bbs3.Client) error {
+	localFileList, err := driver.getLocalWALFileList()
+	if err != nil {
+		return err
+	}
+	if len(localFileList) > 0 {
+		return nil
+	}
+	cloudFileList, err := listFileNamesOnCloud(ctx, client, driver.getWALRelativeDir())
+	if err != nil {
+		return errors.Wrap(err, "failed to list WAL files on the cloud storage")
+	}
+	latest := ""
+	for _, name := range cloudFileList {
+		if walFileNameRegexp.MatchString(name) && name > latest {
+			latest = name
+		}
+	}
+	if latest == "" {
+		return nil
+	}
+	return client.DownloadFileFromCloud(ctx, filepath.Join(driver.getWALDir(), latest), path.Join(driver.getWALRelativeDir(), latest))
+}
+
+func (driver *Driver) uploadWALFiles(ctx context.Context, client *bbs3.Client) error {
+	cloudFileList, err := listFileNamesOnCloud(ctx, client, driver.getWALRelativeDir())
+	if err != nil {
+		return errors.Wrap(err, "failed to list WAL files on the cloud storage")
+	}
+	uploaded := make(map[string]bool)
+	for _, name := range cloudFileList {
+		uploaded[name] = true
+	}
+	localFileList, err := driver.getLocalWALFileList()
+	if err != nil {
+		return err
+	}
+	latest := ""
+	for _, name := range localFileList {
+		if walFileNameRegexp.MatchString(name) && name > latest {
+			latest = name
+		}
+	}
+	for _, name := range localFileList {
+		localPath := filepath.Join(driver.getWALDir(), name)
+		if !uploaded[name] {
+			if err := uploadFileToCloud(ctx, client, localPath, path.Join(driver.getWALRelativeDir(), name)); err != nil {
+				return err
+			}
+		}
+		if walFileNameRegexp.MatchString(name) && name != latest {
+			if err := os.Remove(localPath); err != nil {
+				return errors.Wrapf(err, "failed to remove the uploaded WAL file %q", localPath)
+			}
+		}
+	}
+	return nil
+}
+
+// getLocalWALFileList returns the completed WAL files and the timeline history files in the local WAL archive.
+func (driver *Driver) getLocalWALFileList() ([]string, error) {
+	entryList, err := os.ReadDir(driver.getWALDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to read WAL directory %q", driver.getWALDir())
+	}
+	var fileList []string
+	for _, entry := range entryList {
+		if isArchiveWALFile(entry.Name()) {
+			fileList = append(fileList, entry.Name())
+		}
+	}
+	return fileList, nil
+}
+
+// TakeBaseBackup takes a base backup of the instance.
+// If client is not nil, the base backup is uploaded to the cloud storage instead of being kept locally.
+func (driver *Driver) TakeBaseBackup(ctx context.Context, client *bbs3.Client) (*BaseBackup, error) {
+	baseBackupDir := driver.getBaseBackupDir()
+	if err := os.MkdirAll(baseBackupDir, os.ModePerm); err != nil {
+		return nil, errors.Wrapf(err, "failed to create base backup directory %q", baseBackupDir)
+	}
+	tmpDir := filepath.Join(baseBackupDir, fmt.Sprintf("tmp-%d", time.Now().UnixNano()))
+	defer os.RemoveAll(tmpDir)
+
+	args := append(driver.getConnectionArgs(),
+		fmt.Sprintf("--pgdata=%s", tmpDir),
+		"--format=tar",
+		"--gzip",
+		"--wal-method=stream",
+		"--checkpoint=fast",
+	)
+	if err := driver.runCommand(ctx, "pg_basebackup", args...); err != nil {
+		return nil, errors.Wrap(err, "failed to take base backup")
+	}
+	endTs := time.Now().Unix()
+	startWALFile, err := getBaseBackupStartWALFile(filepath.Join(tmpDir, baseBackupFileName))
+	if err != nil {
+		return nil, err
+	}
+	backup := &BaseBackup{
+		Name:         fmt.Sprintf("%d-%s", endTs, startWALFile),
+		StartWALFile: startWALFile,
+		EndTs:        endTs,
+	}
+	backupDir := filepath.Join(baseBackupDir, backup.Name)
+	if err := os.Rename(tmpDir, backupDir); err != nil {
+		return nil, errors.Wrapf(err, "failed to rename %q to %q", tmpDir, backupDir)
+	}
+
+	if client != nil {
+		defer os.RemoveAll(backupDir)
+		// The WAL of the base backup is uploaded last, whose existence marks the base backup complete on the cloud storage.
+		for _, name := range []string{baseBackupFileName, baseBackupWALFileName} {
+			if err := uploadFileToCloud(ctx, client, filepath.Join(backupDir, name), path.Join(driver.getBaseBackupRelativeDir(), backup.Name, name)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	log.Debug("Successfully took base backup", zap.String("backup", backup.Name))
+	return backup, nil
+}
+
+func getBaseBackupStartWALFile(baseBackupPath string) (string, error) {
+	f, err := os.Open(baseBackupPath)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to open base backup %q", baseBackupPath)
+	}
+	defer f.Close()
+	gzipReader, err := gzip.NewReader(f)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read base backup %q", baseBackupPath)
+	}
+	defer gzipReader.Close()
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return "", errors.Errorf("%s not found in base backup %q", backupLabelFileName, baseBackupPath)
+		}
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to read base backup %q", baseBackupPath)
+		}
+		if header.Name != backupLabelFileName {
+			continue
+		}
+		content, err := io.ReadAll(tarReader)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to read %s in base backup %q", backupLabelFileName, baseBackupPath)
+		}
+		return parseBackupLabel(string(content))
+	}
+}
+
+// parseBackupLabel returns the start WAL file in the backup_label file of a base backup.
+func parseBackupLabel(content string) (string, error) {
+	matches := backupLabelRegexp.FindStringSubmatch(content)
+	if len(matches) != 2 {
+		return "", errors.Errorf("failed to find the start WAL file in backup label %q", content)
+	}
+	return matches[1], nil
+}
+
+// ListBaseBackups returns the complete base backups of the instance sorted by EndTs in ascending order.
+// If client is not nil, the base backups are listed on the cloud storage.
+func (driver *Driver) ListBaseBackups(ctx context.Context, client *bbs3.Client) ([]*BaseBackup, error) {
+	var nameList []string
+	if client == nil {
+		entryList, err := os.ReadDir(driver.getBaseBackupDir())
+		if err != nil && !os.IsNotExist(err) {
+			return nil, errors.Wrapf(err, "failed to read base backup directory %q", driver.getBaseBackupDir())
+		}
+		for _, entry := range entryList {
+			if !entry.IsDir() {
+				continue
+			}
+			if _, err := os.Stat(filepath.Join(driver.getBaseBackupDir(), entry.Name(), baseBackupWALFileName)); err != nil {
+				continue
+			}
+			nameList = append(nameList, entry.Name())
+		}
+	} else {
+		prefix := driver.getBaseBackupRelativeDir() + "/"
+		objectList, err := client.ListObjects(ctx, prefix)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list base backups on the cloud storage")
+		}
+		for _, object := range objectList {
+			name, file := path.Split(strings.TrimPrefix(*object.Key, prefix))
+			if file == baseBackupWALFileName {
+				nameList = append(nameList, strings.TrimSuffix(name, "/"))
+			}
+		}
+	}
+
+	var backupList []*BaseBackup
+	for _, name := range nameList {
+		backup, err := newBaseBackup(name)
+		if err != nil {
+			// Skip the temporary directories of the base backups in progress.
+			continue
+		}
+		backupList = append(backupList, backup)
+	}
+	sort.Slice(backupList, func(i, j int) bool {
+		return backupList[i].EndTs < backupList[j].EndTs
+	})
+	return backupList, nil
+}
+
+// PurgeArchive purges the base backups and the WAL files which are no longer needed to recover to a point in time
+// within the retention period.
+func (driver *Driver) PurgeArchive(ctx context.Context, client *bbs3.Client, retentionPeriodTs int) error {
+	backupList, err := driver.ListBaseBackups(ctx, client)
+	if err != nil {
+		return err
+	}
+	if len(backupList) == 0 {
+		return nil
+	}
+	// Keep the latest base backup before the retention period, which is needed to recover to the start of the retention period.
+	expireTs := time.Now().Unix() - int64(retentionPeriodTs)
+	keep := 0
+	for i, backup := range backupList {
+		if backup.EndTs <= expireTs {
+			keep = i
+		}
+	}
+	for _, backup := range backupList[:keep] {
+		if err := os.RemoveAll(filepath.Join(driver.getBaseBackupDir(), backup.Name)); err != nil {
+			return errors.Wrapf(err, "failed to remove base backup %q", backup.Name)
+		}
+		if client != nil {
+			var pathList []string
+			for _, name := range []string{baseBackupFileName, baseBackupWALFileName} {
+				pathList = append(pathList, path.Join(driver.getBaseBackupRelativeDir(), backup.Name, name))
+			}
+			if err := deleteObjectsOnCloud(ctx, client, pathList); err != nil {
+				return errors.Wrapf(err, "failed to delete base backup %q on the cloud storage", backup.Name)
+			}
+		}
+		log.Debug("Purged expired base backup", zap.String("backup", backup.Name))
+	}
+
+	startWALFile := backupList[keep].StartWALFile
+	localFileList, err := driver.getLocalWALFileList()
+	if err != nil {
+		return err
+	}
+	for _, name := range localFileList {
+		if isWALFileBefore(name, startWALFile) {
+			if err := os.Remove(filepath.Join(driver.getWALDir(), name)); err != nil {
+				return errors.Wrapf(err, "failed to remove expired WAL file %q", name)
+			}
+		}
+	}
+	if client != nil {
+		cloudFileList, err := listFileNamesOnCloud(ctx, client, driver.getWALRelativeDir())
+		if err != nil {
+			return errors.Wrap(err, "failed to list WAL files on the cloud storage")
+		}
+		var pathList []string
+		for _, name := range cloudFileList {
+			if isWALFileBefore(name, startWALFile) {
+				pathList = append(pathList, path.Join(driver.getWALRelativeDir(), name))
+			}
+		}
+		if err := deleteObjectsOnCloud(ctx, client, pathList); err != nil {
+			return errors.Wrap(err, "failed to delete expired WAL files on the cloud storage")
+		}
+	}
+	return nil
+}
+
+// PrepareRecovery prepares the data directory to recover the instance from the base backup to the targetTs by replaying
+// the archived WAL files. The base backup and the WAL files are downloaded first if client is not nil.
+func (driver *Driver) PrepareRecovery(ctx context.Context, client *bbs3.Client, backup *BaseBackup, dataDir string, targetTs int64) error {
+	backupDir := filepath.Join(driver.getBaseBackupDir(), backup.Name)
+	if client != nil {
+		if err := os.MkdirAll(backupDir, os.ModePerm); err != nil {
+			return errors.Wrapf(err, "failed to create base backup directory %q", backupDir)
+		}
+		defer os.RemoveAll(backupDir)
+		for _, name := range []string{baseBackupFileName, baseBackupWALFileName} {
+			if err := client.DownloadFileFromCloud(ctx, filepath.Join(backupDir, name), path.Join(driver.getBaseBackupRelativeDir(), backup.Name, name)); err != nil {
+				return errors.Wrapf(err, "failed to download base backup %q", backup.Name)
+			}
+		}
+		if err := driver.downloadWALFiles(ctx, client, backup.StartWALFile); err != nil {
+			return err
+		}
+	}
+
+	for name, dir := range map[string]string{
+		baseBackupFileName:    dataDir,
+		baseBackupWALFileName: filepath.Join(dataDir, "pg_wal"),
+	} {
+		if err := extractTarGzFile(filepath.Join(backupDir, name), dir); err != nil {
+			return err
+		}
+	}
+
+	// recovery.signal starts the server in the targeted recovery mode.
+	if err := os.Remove(filepath.Join(dataDir, "standby.signal")); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "failed to remove standby.signal")
+	}
+	files := map[string]string{
+		"recovery.signal": "",
+		// Only the local connections from the Bytebase server are allowed.
+		"pg_hba.conf":   "local all all trust\n",
+		"pg_ident.conf": "",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dataDir, name), []byte(content), 0600); err != nil {
+			return errors.Wrapf(err, "failed to write %s", name)
+		}
+	}
+	autoConfPath := filepath.Join(dataDir, "postgresql.auto.conf")
+	autoConf, err := os.OpenFile(autoConfPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open %q", autoConfPath)
+	}
+	defer autoConf.Close()
+	if _, err := autoConf.WriteString(getRecoveryConfig(driver.getWALDir(), dataDir, targetTs)); err != nil {
+		return errors.Wrapf(err, "failed to write %q", autoConfPath)
+	}
+	if err := os.Chmod(dataDir, 0700); err != nil {
+		return errors.Wrapf(err, "failed to chmod data directory %q to 0700", dataDir)
+	}
+	return nil
+}
+
+// getRecoveryConfig returns the configurations to recover to the targetTs, which override the ones of the base backup.
+func getRecoveryConfig(walDir, dataDir string, targetTs int64) string {
+	walPath := filepath.Join(walDir, "%f")
+	lines := []string{
+		"",
+		"# Added by Bytebase for PITR.",
+		// The latest WAL file is only partially received.
+		fmt.Sprintf(`restore_command = 'cp "%s" "%%p" 2>/dev/null || cp "%s%s" "%%p"'`, walPath, walPath, partialWALFileSuffix),
+		fmt.Sprintf("recovery_target_time = '%s UTC'", time.Unix(targetTs, 0).UTC().Format("2006-01-02 15:04:05")),
+		"recovery_target_action = 'promote'",
+		fmt.Sprintf("hba_file = '%s'", filepath.Join(dataDir, "pg_hba.conf")),
+		fmt.Sprintf("ident_file = '%s'", filepath.Join(dataDir, "pg_ident.conf")),
+		"hot_standby = on",
+		"archive_mode = off",
+		"ssl = off",
+		"shared_preload_libraries = ''",
+		"shared_buffers = '128MB'",
+		"huge_pages = off",
+		"",
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (driver *Driver) downloadWALFiles(ctx context.Context, client *bbs3.Client, startWALFile string) error {
+	cloudFileList, err := listFileNamesOnCloud(ctx, client, driver.getWALRelativeDir())
+	if err != nil {
+		return errors.Wrap(err, "failed to list WAL files on the cloud storage")
+	}
+	for _, name := range cloudFileList {
+		if !isArchiveWALFile(name) || isWALFileBefore(name, startWALFile) {
+			continue
+		}
+		localPath := filepath.Join(driver.getWALDir(), name)
+		if _, err := os.Stat(localPath); err == nil {
+			continue
+		}
+		if err := client.DownloadFileFromCloud(ctx, localPath, path.Join(driver.getWALRelativeDir(), name)); err != nil {
+			return errors.Wrapf(err, "failed to download WAL file %q", name)
+		}
+	}
+	return nil
+}
+
+// WaitRecoveryDone waits until the server recovered from the WAL archive finishes the recovery.
+func (driver *Driver) WaitRecoveryDone(ctx context.Context) error {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	failureCount := 0
+	for {
+		var inRecovery bool
+		err := driver.db.QueryRowContext(ctx, "SELECT pg_is_in_recovery()").Scan(&inRecovery)
+		if err == nil && !inRecovery {
+			return nil
+		}
+		// The server may be unavailable before reaching a consistent state.
+		if err != nil {
+			failureCount++
+			if failureCount > maxRecoveryConnectionFailure {
+				return errors.Wrap(err, "failed to check the recovery status")
+			}
+		} else {
+			failureCount = 0
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (driver *Driver) getConnectionArgs() []string {
+	args := []string{
+		fmt.Sprintf("--username=%s", driver.config.Username),
+		fmt.Sprintf("--host=%s", driver.config.Host),
+		fmt.Sprintf("--port=%s", driver.config.Port),
+	}
+	if driver.config.Password == "" {
+		args = append(args, "--no-password")
+	}
+	return args
+}
+
+func (driver *Driver) runCommand(ctx context.Context, name string, args ...string) error {
+	cmd := exec.CommandContext(ctx, filepath.Join(driver.dbBinDir, name), args...)
+	// Keep the inherited environment such as PATH and HOME, which the PostgreSQL tools may depend on.
+	cmd.Env = os.Environ()
+	if driver.config.Password != "" {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("PGPASSWORD=%s", driver.config.Password))
+	}
+	cmd.Env = append(cmd.Env, "OPENSSL_CONF=/etc/ssl/")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return errors.Wrapf(err, "failed to run %q: %s", name, stderr.String())
+	}
+	return nil
+}
+
+// getWALDir returns the directory of the WAL archive.
+func (driver *Driver) getWALDir() string {
+	return filepath.Join(driver.archiveDir, walDirName)
+}
+
+func (driver *Driver) getWALRelativeDir() string {
+	return path.Join(common.GetBinlogRelativeDir(driver.archiveDir), walDirName)
+}
+
+// getBaseBackupDir returns the directory of the base backups.
+func (driver *Driver) getBaseBackupDir() string {
+	return filepath.Join(driver.archiveDir, baseBackupDirName)
+}
+
+func (driver *Driver) getBaseBackupRelativeDir() string {
+	return path.Join(common.GetBinlogRelativeDir(driver.archiveDir), baseBackupDirName)
+}
+
+// isArchiveWALFile returns true if the file is a completed WAL file or a timeline history file.
+func isArchiveWALFile(name string) bool {
+	return walFileNameRegexp.MatchString(name) || walHistoryFileRegexp.MatchString(name)
+}
+
+// isWALFileBefore returns true if the name is a WAL file whose position is before the WAL file target.
+// The first 8 characters of the WAL file name is the timeline, and the rest is the position.
+func isWALFileBefore(name, target string) bool {
+	if !walFileNameRegexp.MatchString(name) {
+		return false
+	}
+	return name[8:] < target[8:]
+}
+
+func extractTarGzFile(filePath, dir string) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open %q", filePath)
+	}
+	defer f.Close()
+	if err := utils.ExtractTarGz(f, dir); err != nil {
+		return errors.Wrapf(err, "failed to extract %q to %q", filePath, dir)
+	}
+	return nil
+}
+
+func uploadFileToCloud(ctx context.Context, client *bbs3.Client, filePathLocal, filePathOnCloud string) error {
+	f, err := os.Open(filePathLocal)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open %q for uploading", filePathLocal)
+	}
+	defer f.Close()
+	if _, err := client.UploadObject(ctx, filePathOnCloud, f); err != nil {
+		return errors.Wrapf(err, "failed to upload %q to the cloud storage", filePathLocal)
+	}
+	return nil
+}
+
+// listFileNamesOnCloud returns the names of the files in the directory on the cloud storage.
+func listFileNamesOnCloud(ctx context.Context, client *bbs3.Client, dir string) ([]string, error) {
+	objectList, err := client.ListObjects(ctx, dir+"/")
+	if err != nil {
+		return nil, err
+	}
+	var nameList []string
+	for _, object := range objectList {
+		nameList = append(nameList, path.Base(*object.Key))
+	}
+	return nameList, nil
+}
+
+func deleteObjectsOnCloud(ctx context.Context, client *bbs3.Client, pathList []string) error {
+	for len(pathList) > 0 {
+		n := len(pathList)
+		if n > maxDeleteObjectCount {
+			n = maxDeleteObjectCount
+		}
+		if _, err := client.DeleteObjects(ctx, pathList[:n]...); err != nil {
+			return err
+		}
+		pathList = pathList[n:]
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package pg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBackupLabel(t *testing.T) {
+	a := require.New(t)
+	content := `START WAL LOCATION: 0/2000028 (file 000000010000000000000002)
+CHECKPOINT LOCATION: 0/2000060
+BACKUP METHOD: streamed
+BACKUP FROM: primary
+START TIME: 2023-01-10 08:00:00 UTC
+LABEL: pg_basebackup base backup
+START TIMELINE: 1
+`
+	startWALFile, err := parseBackupLabel(content)
+	a.NoError(err)
+	a.Equal("000000010000000000000002", startWALFile)
+
+	_, err = parseBackupLabel("CHECKPOINT LOCATION: 0/2000060")
+	a.Error(err)
+}
+
+func TestNewBaseBackup(t *testing.T) {
+	a := require.New(t)
+	backup, err := newBaseBackup("1673337600-000000010000000000000002")
+	a.NoError(err)
+	a.Equal(&BaseBackup{
+		Name:         "1673337600-000000010000000000000002",
+		StartWALFile: "000000010000000000000002",
+		EndTs:        1673337600,
+	}, backup)
+
+	for _, name := range []string{"tmp-1673337600", "1673337600", "abc-000000010000000000000002", "1673337600-00000001"} {
+		_, err := newBaseBackup(name)
+		a.Error(err, name)
+	}
+}
+
+func TestGetPreviousLSN(t *testing.T) {
+	a := require.New(t)
+	tests := []struct {
+		lsn  string
+		want string
+	}{
+		{lsn: "0/2000028", want: "0/2000027"},
+		{lsn: "1/0", want: "0/FFFFFFFF"},
+		{lsn: "16/B374D848", want: "16/B374D847"},
+	}
+	for _, test := range tests {
+		got, err := getPreviousLSN(test.lsn)
+		a.NoError(err)
+		a.Equal(test.want, got)
+	}
+	_, err := getPreviousLSN("0/0")
+	a.Error(err)
+}
+
+func TestIsWALFileBefore(t *testing.T) {
+	a := require.New(t)
+	a.True(isWALFileBefore("000000010000000000000001", "000000010000000000000002"))
+	a.True(isWALFileBefore("000000010000000000000001", "000000020000000000000002"))
+	a.False(isWALFileBefore("000000010000000000000002", "000000010000000000000002"))
+	a.False(isWALFileBefore("000000010000000100000000", "0000000100000000000000FF"))
+	a.False(isWALFileBefore("00000002.history", "000000020000000000000002"))
+}
+
+func TestGetRecoveryConfig(t *testing.T) {
+	a := require.New(t)
+	config := getRecoveryConfig("/var/opt/bytebase/backup/instance/1/wal", "/var/opt/bytebase/tmp/pitr-1/pgdata", 1673337600)
+	a.Contains(config, `restore_command = 'cp "/var/opt/bytebase/backup/instance/1/wal/%f" "%p" 2>/dev/null || cp "/var/opt/bytebase/backup/instance/1/wal/%f.partial" "%p"'`)
+	a.Contains(config, "recovery_target_time = '2023-01-10 08:00:00 UTC'")
+	a.Contains(config, "recovery_target_action = 'promote'")
+	a.Contains(config, "hba_file = '/var/opt/bytebase/tmp/pitr-1/pgdata/pg_hba.conf'")
+}
+
+func TestHasReplicationSlot(t *testing.T) {
+	a := require.New(t)
+	binlogDir := t.TempDir()
+	a.False(HasReplicationSlot(binlogDir))
+
+	a.NoError(os.MkdirAll(filepath.Join(binlogDir, walDirName), os.ModePerm))
+	a.NoError(os.WriteFile(filepath.Join(binlogDir, walDirName, replicationSlotFileName), []byte(pitrReplicationSlotName), 0600))
+	a.True(HasReplicationSlot(binlogDir))
+	// The marker is never archived or restored as the WAL file.
+	a.False(isArchiveWALFile(replicationSlotFileName))
+}
@@ -0,0 +1,462 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	pgquery "github.com/pganalyze/pg_query_go/v2"
+	"github.com/pkg/errors"
+)
+
+const (
+	// maxRollbackRowCount is the maximum number of rows whose before-images can be captured for a migration.
+	// The rollback statement is stored in the task payload, so we don't generate it for large data changes.
+	maxRollbackRowCount = 10000
+	// rollbackSavepoint is the savepoint protecting the migration transaction from the failures of capturing the before-images.
+	rollbackSavepoint = "bytebase_rollback"
+)
+
+// rollbackGenerator generates the rollback statements of the DMLs with the before-images of the affected rows.
+// The before-images are captured in the migration transaction right before executing each statement,
+// so the rollback statements reflect the changes made by the previous statements in the same migration.
+type rollbackGenerator struct {
+	// statementList is the list of rollback statements for each executed statement in the execution order.
+	statementList []string
+	rowCount      int
+	err           error
+}
+
+// rollbackTable is the table changed by a DML.
+type rollbackTable struct {
+	schema string
+	name   string
+	// columnList is the list of the non-generated columns.
+	columnList []string
+	// primaryKey is the list of the primary key columns.
+	primaryKey []string
+	// identityAlwaysColumn is the identity column with GENERATED ALWAYS, it's empty if the table has no such column.
+	identityAlwaysColumn string
+}
+
+// EnableRollbackSQLGeneration makes the driver capture the before-images of the affected rows and generate the rollback statement
+// when executing the DMLs. The result can be obtained by GetRollbackSQL after the execution.
+func (driver *Driver) EnableRollbackSQLGeneration() {
+	driver.rollbackGenerator = &rollbackGenerator{}
+}
+
+// GetRollbackSQL returns the rollback statement generated in the last execution,
+// or the error explaining why the rollback statement cannot be generated.
+func (driver *Driver) GetRollbackSQL() (string, error) {
+	if driver.rollbackGenerator == nil {
+		return "", errors.Errorf("rollback SQL generation isn't enabled")
+	}
+	return driver.rollbackGenerator.rollbackStatement()
+}
+
+func (g *rollbackGenerator) rollbackStatement() (string, error) {
+	if g.err != nil {
+		return "", g.err
+	}
+	var buf strings.Builder
+	// The changes are rolled back in the reverse order.
+	for i := len(g.statementList) - 1; i >= 0; i-- {
+		buf.WriteString(g.statementList[i])
+	}
+	return buf.String(), nil
+}
+
+// execute executes the statement in the transaction and generates its rollback statement.
+// Failing to generate the rollback statement doesn't fail the execution, and the error is reported by rollbackStatement.
+func (g *rollbackGenerator) execute(ctx context.Context, tx *sql.Tx, stmt string) (int64, error) {
+	if g.err != nil {
+		return execStatement(ctx, tx, stmt)
+	}
+	res, err := pgquery.Parse(stmt)
+	if err != nil || len(res.Stmts) != 1 {
+		g.err = errors.Errorf("rollback SQL isn't supported for statement %q", stmt)
+		return execStatement(ctx, tx, stmt)
+	}
+
+	switch node := res.Stmts[0].Stmt.Node.(type) {
+	case *pgquery.Node_InsertStmt:
+		return g.executeInsert(ctx, tx, stmt, node.InsertStmt)
+	case *pgquery.Node_UpdateStmt:
+		return g.executeUpdate(ctx, tx, stmt, node.UpdateStmt)
+	case *pgquery.Node_DeleteStmt:
+		return g.executeDelete(ctx, tx, stmt, node.DeleteStmt)
+	case *pgquery.Node_SelectStmt:
+		if node.SelectStmt.IntoClause == nil {
+			// SELECT doesn't change data, and nothing needs to be rolled back.
+			return execStatement(ctx, tx, stmt)
+		}
+	case *pgquery.Node_VariableSetStmt:
+		return execStatement(ctx, tx, stmt)
+	}
+	g.err = errors.Errorf("rollback SQL isn't supported for statement %q", stmt)
+	return execStatement(ctx, tx, stmt)
+}
+
+func (g *rollbackGenerator) executeInsert(ctx context.Context, tx *sql.Tx, stmt string, insert *pgquery.InsertStmt) (int64, error) {
+	if insert.WithClause != nil || len(insert.ReturningList) > 0 ||
+		(insert.OnConflictClause != nil && insert.OnConflictClause.Action != pgquery.OnConflictAction_ONCONFLICT_NOTHING) {
+		g.err = errors.Errorf("rollback SQL isn't supported for INSERT with WITH, RETURNING or ON CONFLICT DO UPDATE clause: %q", stmt)
+		return execStatement(ctx, tx, stmt)
+	}
+	table, err := g.getTable(ctx, tx, insert.Relation)
+	if err != nil {
+		g.err = err
+		return execStatement(ctx, tx, stmt)
+	}
+	if len(table.primaryKey) == 0 {
+		g.err = errors.Errorf("rollback SQL isn't supported for INSERT into table %q without primary key", table.name)
+		return execStatement(ctx, tx, stmt)
+	}
+
+	// Execute the INSERT returning the primary keys of the inserted rows.
+	insert.ReturningList = quoteNullableTargetList(table.primaryKey)
+	query, err := deparseStatement(&pgquery.Node{Node: &pgquery.Node_InsertStmt{InsertStmt: insert}})
+	if err != nil {
+		g.err = errors.Wrapf(err, "failed to deparse statement %q", stmt)
+		return execStatement(ctx, tx, stmt)
+	}
+	rows, rowCount, err := queryRows(ctx, tx, query, len(table.primaryKey), g.remainingRowCount())
+	if err != nil {
+		return 0, err
+	}
+	if err := g.addRowCount(rowCount); err == nil {
+		g.statementList = append(g.statementList, generateDeleteStatement(table, rows))
+	}
+	return int64(rowCount), nil
+}
+
+func (g *rollbackGenerator) executeUpdate(ctx context.Context, tx *sql.Tx, stmt string, update *pgquery.UpdateStmt) (int64, error) {
+	if update.WithClause != nil || len(update.FromClause) > 0 {
+		g.err = errors.Errorf("rollback SQL isn't supported for UPDATE with WITH or FROM clause: %q", stmt)
+		return execStatement(ctx, tx, stmt)
+	}
+	table, err := g.getTable(ctx, tx, update.Relation)
+	if err != nil {
+		g.err = err
+		return execStatement(ctx, tx, stmt)
+	}
+	if len(table.primaryKey) == 0 {
+		g.err = errors.Errorf("rollback SQL isn't supported for UPDATE on table %q without primary key", table.name)
+		return execStatement(ctx, tx, stmt)
+	}
+	for _, target := range update.TargetList {
+		resTarget, ok := target.Node.(*pgquery.Node_ResTarget)
+		if !ok {
+			continue
+		}
+		for _, key := range table.primaryKey {
+			if resTarget.ResTarget.Name == key {
+				g.err = errors.Errorf("rollback SQL isn't supported for UPDATE changing the primary key of table %q", table.name)
+				return execStatement(ctx, tx, stmt)
+			}
+		}
+	}
+
+	rows, err := g.captureBeforeImage(ctx, tx, table, update.Relation, update.WhereClause)
+	if err != nil {
+		g.err = err
+		return execStatement(ctx, tx, stmt)
+	}
+	affectedRows, err := execStatement(ctx, tx, stmt)
+	if err != nil {
+		return 0, err
+	}
+	g.statementList = append(g.statementList, generateUpdateStatement(table, rows))
+	return affectedRows, nil
+}
+
+func (g *rollbackGenerator) executeDelete(ctx context.Context, tx *sql.Tx, stmt string, deleteStmt *pgquery.DeleteStmt) (int64, error) {
+	if deleteStmt.WithClause != nil || len(deleteStmt.UsingClause) > 0 {
+		g.err = errors.Errorf("rollback SQL isn't supported for DELETE with WITH or USING clause: %q", stmt)
+		return execStatement(ctx, tx, stmt)
+	}
+	table, err := g.getTable(ctx, tx, deleteStmt.Relation)
+	if err != nil {
+		g.err = err
+		return execStatement(ctx, tx, stmt)
+	}
+
+	rows, err := g.captureBeforeImage(ctx, tx, table, deleteStmt.Relation, deleteStmt.WhereClause)
+	if err != nil {
+		g.err = err
+		return execStatement(ctx, tx, stmt)
+	}
+	affectedRows, err := execStatement(ctx, tx, stmt)
+	if err != nil {
+		return 0, err
+	}
+	g.statementList = append(g.statementList, generateInsertStatement(table, rows))
+	return affectedRows, nil
+}
+
+// remainingRowCount returns the number of rows to capture, which is one more than the remaining quota,
+// so that exceeding maxRollbackRowCount can be told without loading all affected rows.
+func (g *rollbackGenerator) remainingRowCount() int {
+	return maxRollbackRowCount - g.rowCount + 1
+}
+
+func (g *rollbackGenerator) addRowCount(count int) error {
+	g.rowCount += count
+	if g.rowCount > maxRollbackRowCount {
+		g.err = errors.Errorf("rollback SQL isn't supported for changing more than %d rows", maxRollbackRowCount)
+		return g.err
+	}
+	return nil
+}
+
+// captureBeforeImage selects and locks the rows matching the WHERE clause, and returns the quoted literals of all columns.
+// The rows beyond the remaining quota of maxRollbackRowCount are not selected.
+func (g *rollbackGenerator) captureBeforeImage(ctx context.Context, tx *sql.Tx, table *rollbackTable, relation *pgquery.RangeVar, whereClause *pgquery.Node) ([][]string, error) {
+	limit := g.remainingRowCount()
+	query, err := getBeforeImageQuery(table, relation, whereClause, limit)
+	if err != nil {
+		return nil, err
+	}
+	var rows [][]string
+	if err := withSavepoint(ctx, tx, func() error {
+		var err error
+		rows, _, err = queryRows(ctx, tx, query, len(table.columnList), limit)
+		return err
+	}); err != nil {
+		return nil, errors.Wrapf(err, "failed to capture the before-images with query %q", query)
+	}
+	if err := g.addRowCount(len(rows)); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// getTable gets the columns and primary key of the table.
+func (*rollbackGenerator) getTable(ctx context.Context, tx *sql.Tx, relation *pgquery.RangeVar) (*rollbackTable, error) {
+	name := quoteIdentifier(relation.Relname)
+	if relation.Schemaname != "" {
+		name = fmt.Sprintf("%s.%s", quoteIdentifier(relation.Schemaname), name)
+	}
+	table := &rollbackTable{name: relation.Relname}
+	if err := withSavepoint(ctx, tx, func() error {
+		if err := tx.QueryRowContext(ctx, `
+			SELECT n.nspname FROM pg_catalog.pg_class c
+			JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+			WHERE c.oid = $1::regclass AND c.relkind IN ('r', 'p')`, name).Scan(&table.schema); err != nil {
+			return err
+		}
+
+		rows, err := tx.QueryContext(ctx, `
+			SELECT column_name, COALESCE(identity_generation, '') FROM information_schema.columns
+			WHERE table_schema = $1 AND table_name = $2 AND is_generated = 'NEVER'
+			ORDER BY ordinal_position`, table.schema, table.name)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var column, identityGeneration string
+			if err := rows.Scan(&column, &identityGeneration); err != nil {
+				return err
+			}
+			table.columnList = append(table.columnList, column)
+			if identityGeneration == "ALWAYS" {
+				table.identityAlwaysColumn = column
+			}
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		keyRows, err := tx.QueryContext(ctx, `
+			SELECT kcu.column_name FROM information_schema.table_constraints tc
+			JOIN information_schema.key_column_usage kcu
+				ON tc.constraint_schema = kcu.constraint_schema AND tc.constraint_name = kcu.constraint_name
+			WHERE tc.constraint_type = 'PRIMARY KEY' AND tc.table_schema = $1 AND tc.table_name = $2
+			ORDER BY kcu.ordinal_position`, table.schema, table.name)
+		if err != nil {
+			return err
+		}
+		defer keyRows.Close()
+		for keyRows.Next() {
+			var column string
+			if err := keyRows.Scan(&column); err != nil {
+				return err
+			}
+			table.primaryKey = append(table.primaryKey, column)
+		}
+		return keyRows.Err()
+	}); err != nil {
+		return nil, errors.Wrapf(err, "failed to get the columns of table %s", name)
+	}
+	if len(table.columnList) == 0 {
+		return nil, errors.Errorf("rollback SQL isn't supported for table %s without columns", name)
+	}
+	return table, nil
+}
+
+// getBeforeImageQuery returns the query like "SELECT quote_nullable(a), quote_nullable(b) FROM t WHERE ... LIMIT 10001 FOR UPDATE".
+func getBeforeImageQuery(table *rollbackTable, relation *pgquery.RangeVar, whereClause *pgquery.Node, limit int) (string, error) {
+	selectStmt := &pgquery.SelectStmt{
+		TargetList:  quoteNullableTargetList(table.columnList),
+		FromClause:  []*pgquery.Node{{Node: &pgquery.Node_RangeVar{RangeVar: relation}}},
+		WhereClause: whereClause,
+		LockingClause: []*pgquery.Node{{Node: &pgquery.Node_LockingClause{LockingClause: &pgquery.LockingClause{
+			Strength:   pgquery.LockClauseStrength_LCS_FORUPDATE,
+			WaitPolicy: pgquery.LockWaitPolicy_LockWaitBlock,
+		}}}},
+		LimitCount:  pgquery.MakeAConstIntNode(int64(limit), 0),
+		LimitOption: pgquery.LimitOption_LIMIT_OPTION_COUNT,
+		Op:          pgquery.SetOperation_SETOP_NONE,
+	}
+	return deparseStatement(&pgquery.Node{Node: &pgquery.Node_SelectStmt{SelectStmt: selectStmt}})
+}
+
+// quoteNullableTargetList returns the target list quoting the column values as SQL literals.
+func quoteNullableTargetList(columnList []string) []*pgquery.Node {
+	var targetList []*pgquery.Node
+	for _, column := range columnList {
+		targetList = append(targetList, pgquery.MakeResTargetNodeWithVal(
+			pgquery.MakeFuncCallNode(
+				[]*pgquery.Node{pgquery.MakeStrNode("quote_nullable")},
+				[]*pgquery.Node{pgquery.MakeColumnRefNode([]*pgquery.Node{pgquery.MakeStrNode(column)}, 0)},
+				0,
+			),
+			0,
+		))
+	}
+	return targetList
+}
+
+func deparseStatement(node *pgquery.Node) (string, error) {
+	return pgquery.Deparse(&pgquery.ParseResult{Stmts: []*pgquery.RawStmt{{Stmt: node}}})
+}
+
+// generateInsertStatement generates the statements inserting the deleted rows back.
+func generateInsertStatement(table *rollbackTable, rows [][]string) string {
+	var columnList []string
+	for _, column := range table.columnList {
+		columnList = append(columnList, quoteIdentifier(column))
+	}
+	overriding := ""
+	if table.identityAlwaysColumn != "" {
+		overriding = " OVERRIDING SYSTEM VALUE"
+	}
+	var buf strings.Builder
+	for _, row := range rows {
+		fmt.Fprintf(&buf, "INSERT INTO %s (%s)%s VALUES (%s);\n", table.fullName(), strings.Join(columnList, ", "), overriding, strings.Join(row, ", "))
+	}
+	return buf.String()
+}
+
+// generateUpdateStatement generates the statements updating the rows back to the before-images.
+// The identity column with GENERATED ALWAYS cannot be updated, and neither can it be changed by the UPDATE being rolled back.
+func generateUpdateStatement(table *rollbackTable, rows [][]string) string {
+	var buf strings.Builder
+	for _, row := range rows {
+		var setList, whereList []string
+		for i, column := range table.columnList {
+			if table.isPrimaryKey(column) {
+				whereList = append(whereList, fmt.Sprintf("%s = %s", quoteIdentifier(column), row[i]))
+			} else if column != table.identityAlwaysColumn {
+				setList = append(setList, fmt.Sprintf("%s = %s", quoteIdentifier(column), row[i]))
+			}
+		}
+		if len(setList) == 0 {
+			// All columns are in the primary key or generated always, and nothing can be changed.
+			continue
+		}
+		fmt.Fprintf(&buf, "UPDATE %s SET %s WHERE %s;\n", table.fullName(), strings.Join(setList, ", "), strings.Join(whereList, " AND "))
+	}
+	return buf.String()
+}
+
+// generateDeleteStatement generates the statements deleting the inserted rows with the primary keys.
+func generateDeleteStatement(table *rollbackTable, rows [][]string) string {
+	var buf strings.Builder
+	for _, row := range rows {
+		var whereList []string
+		for i, column := range table.primaryKey {
+			whereList = append(whereList, fmt.Sprintf("%s = %s", quoteIdentifier(column), row[i]))
+		}
+		fmt.Fprintf(&buf, "DELETE FROM %s WHERE %s;\n", table.fullName(), strings.Join(whereList, " AND "))
+	}
+	return buf.String()
+}
+
+func (t *rollbackTable) fullName() string {
+	return fmt.Sprintf("%s.%s", quoteIdentifier(t.schema), quoteIdentifier(t.name))
+}
+
+func (t *rollbackTable) isPrimaryKey(column string) bool {
+	for _, key := range t.primaryKey {
+		if key == column {
+			return true
+		}
+	}
+	return false
+}
+
+func quoteIdentifier(identifier string) string {
+	return fmt.Sprintf(`"%s"`, strings.ReplaceAll(identifier, `"`, `""`))
+}
+
+// withSavepoint runs f in a savepoint, so that its failure doesn't abort the transaction.
+func withSavepoint(ctx context.Context, tx *sql.Tx, f func() error) error {
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("SAVEPOINT %s", rollbackSavepoint)); err != nil {
+		return err
+	}
+	if err := f(); err != nil {
+		if _, rollbackErr := tx.ExecContext(ctx, fmt.Sprintf("ROLLBACK TO SAVEPOINT %s", rollbackSavepoint)); rollbackErr != nil {
+			return errors.Wrapf(rollbackErr, "failed to roll back to savepoint after error %v", err)
+		}
+		return err
+	}
+	_, err := tx.ExecContext(ctx, fmt.Sprintf("RELEASE SAVEPOINT %s", rollbackSavepoint))
+	return err
+}
+
+func execStatement(ctx context.Context, tx *sql.Tx, stmt string) (int64, error) {
+	sqlResult, err := tx.ExecContext(ctx, stmt)
+	if err != nil {
+		return 0, err
+	}
+	rowsAffected, err := sqlResult.RowsAffected()
+	if err != nil {
+		// Since we cannot differentiate DDL and DML yet, we have to ignore the error.
+		return 0, nil
+	}
+	return rowsAffected, nil
+}
+
+// queryRows queries the rows with the given number of text columns.
+// It keeps at most limit rows in memory, and returns the count of all rows.
+func queryRows(ctx context.Context, tx *sql.Tx, query string, columnCount int, limit int) ([][]string, int, error) {
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+	var result [][]string
+	count := 0
+	for rows.Next() {
+		count++
+		if len(result) >= limit {
+			continue
+		}
+		row := make([]string, columnCount)
+		dest := make([]interface{}, columnCount)
+		for i := range row {
+			dest[i] = &row[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, 0, err
+		}
+		result = append(result, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return result, count, nil
+}
@@ -0,0 +1,87 @@
+package pg
+
+import (
+	"testing"
+
+	pgquery "github.com/pganalyze/pg_query_go/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBeforeImageQuery(t *testing.T) {
+	tests := []struct {
+		stmt string
+		want string
+	}{
+		{
+			stmt: `UPDATE "public"."user" SET name = 'a' WHERE id > 1 AND name LIKE 'b%'`,
+			want: `SELECT quote_nullable(id), quote_nullable(name) FROM public."user" WHERE id > 1 AND name LIKE 'b%' LIMIT 10001 FOR UPDATE`,
+		},
+		{
+			stmt: `DELETE FROM t AS x WHERE x.id IN (SELECT id FROM s)`,
+			want: `SELECT quote_nullable(id), quote_nullable(name) FROM t x WHERE x.id IN (SELECT id FROM s) LIMIT 10001 FOR UPDATE`,
+		},
+		{
+			stmt: `DELETE FROM t`,
+			want: `SELECT quote_nullable(id), quote_nullable(name) FROM t LIMIT 10001 FOR UPDATE`,
+		},
+	}
+
+	table := &rollbackTable{columnList: []string{"id", "name"}}
+	for _, test := range tests {
+		res, err := pgquery.Parse(test.stmt)
+		require.NoError(t, err)
+		var relation *pgquery.RangeVar
+		var whereClause *pgquery.Node
+		switch node := res.Stmts[0].Stmt.Node.(type) {
+		case *pgquery.Node_UpdateStmt:
+			relation, whereClause = node.UpdateStmt.Relation, node.UpdateStmt.WhereClause
+		case *pgquery.Node_DeleteStmt:
+			relation, whereClause = node.DeleteStmt.Relation, node.DeleteStmt.WhereClause
+		}
+		query, err := getBeforeImageQuery(table, relation, whereClause, maxRollbackRowCount+1)
+		require.NoError(t, err)
+		require.Equal(t, test.want, query, test.stmt)
+	}
+}
+
+func TestGenerateRollbackStatement(t *testing.T) {
+	table := &rollbackTable{
+		schema:     "public",
+		name:       "user",
+		columnList: []string{"id", "name", "note"},
+		primaryKey: []string{"id"},
+	}
+	rows := [][]string{
+		{"'1'", "'O''Brien'", "NULL"},
+		{"'2'", "'b'", "'c'"},
+	}
+
+	require.Equal(t,
+		`INSERT INTO "public"."user" ("id", "name", "note") VALUES ('1', 'O''Brien', NULL);`+"\n"+
+			`INSERT INTO "public"."user" ("id", "name", "note") VALUES ('2', 'b', 'c');`+"\n",
+		generateInsertStatement(table, rows))
+	require.Equal(t,
+		`UPDATE "public"."user" SET "name" = 'O''Brien', "note" = NULL WHERE "id" = '1';`+"\n"+
+			`UPDATE "public"."user" SET "name" = 'b', "note" = 'c' WHERE "id" = '2';`+"\n",
+		generateUpdateStatement(table, rows))
+	require.Equal(t,
+		`DELETE FROM "public"."user" WHERE "id" = '1';`+"\n"+
+			`DELETE FROM "public"."user" WHERE "id" = '2';`+"\n",
+		generateDeleteStatement(table, [][]string{{"'1'"}, {"'2'"}}))
+
+	table.identityAlwaysColumn = "id"
+	require.Equal(t,
+		`INSERT INTO "public"."user" ("id", "name", "note") OVERRIDING SYSTEM VALUE VALUES ('2', 'b', 'c');`+"\n",
+		generateInsertStatement(table, rows[1:]))
+
+	// The identity column with GENERATED ALWAYS isn't in the SET list if it's not the primary key.
+	table.identityAlwaysColumn = "note"
+	require.Equal(t,
+		`UPDATE "public"."user" SET "name" = 'b' WHERE "id" = '2';`+"\n",
+		generateUpdateStatement(table, rows[1:]))
+
+	generator := &rollbackGenerator{statementList: []string{"DELETE 1;\n", "UPDATE 2;\n"}}
+	statement, err := generator.rollbackStatement()
+	require.NoError(t, err)
+	require.Equal(t, "UPDATE 2;\nDELETE 1;\n", statement)
+}
@@ -0,0 +1,35 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// Redis doesn't record the migration history because there is no place to store it without polluting the keyspace.
+
+// NeedsSetupMigration returns whether it needs to setup migration.
+func (*Driver) NeedsSetupMigration(_ context.Context) (bool, error) {
+	return false, nil
+}
+
+// SetupMigrationIfNeeded sets up migration if needed.
+func (*Driver) SetupMigrationIfNeeded(_ context.Context) error {
+	return nil
+}
+
+// ExecuteMigration executes the migration without recording the history.
+func (driver *Driver) ExecuteMigration(ctx context.Context, m *db.MigrationInfo, statement string) (int64, string, error) {
+	if m.Type == db.Baseline || statement == "" {
+		return 0, "", nil
+	}
+	if _, err := driver.Execute(ctx, statement, false /* createDatabase */); err != nil {
+		return 0, "", err
+	}
+	return 0, "", nil
+}
+
+// FindMigrationHistoryList finds the migration history.
+func (*Driver) FindMigrationHistoryList(_ context.Context, _ *db.MigrationHistoryFind) ([]*db.MigrationHistory, error) {
+	return nil, nil
+}
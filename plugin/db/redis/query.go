@@ -0,0 +1,201 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/common"
+)
+
+// scanCount is the COUNT hint for each SCAN family call.
+const scanCount = 100
+
+// unboundedCommandMap is the read-only commands whose reply size has no bound and no cursor-based equivalent.
+var unboundedCommandMap = map[string]string{
+	"SUNION": "SSCAN",
+	"SINTER": "SSCAN",
+	"SDIFF":  "SSCAN",
+	"ZUNION": "ZSCAN",
+	"ZINTER": "ZSCAN",
+	"ZDIFF":  "ZSCAN",
+}
+
+// boundedCommand is the command rewritten to return at most limit elements.
+type boundedCommand struct {
+	// command is the command to run. For the SCAN family, it's the command before the cursor, e.g. ["HSCAN", key].
+	command []interface{}
+	// scanOptionList is the options after the cursor for the SCAN family, nil if the command isn't a scan.
+	scanOptionList []interface{}
+	scan           bool
+	// stride and offset select the elements from the scan reply, e.g. HSCAN returns field and value pairs,
+	// and HKEYS keeps the fields only with stride 2 and offset 0.
+	stride int
+	offset int
+	// lengthCommand is the command getting the length, e.g. LLEN, to resolve the negative indexes of the range.
+	lengthCommand string
+}
+
+// boundCommand rewrites the command whose reply can be unbounded, so that Redis never sends more than limit elements.
+// KEYS, HGETALL, HKEYS, HVALS and SMEMBERS are replaced by the SCAN family, and the ranges are capped by limit.
+func boundCommand(command []interface{}, limit int) (*boundedCommand, error) {
+	name := strings.ToUpper(fmt.Sprintf("%v", command[0]))
+	args := command[1:]
+	if alternative, ok := unboundedCommandMap[name]; ok {
+		return nil, common.Errorf(common.Invalid, "command %q is not allowed because its reply is unbounded, use %s instead", name, alternative)
+	}
+
+	switch name {
+	case "KEYS":
+		if len(args) == 1 {
+			return &boundedCommand{
+				command:        []interface{}{"SCAN"},
+				scanOptionList: []interface{}{"MATCH", args[0], "COUNT", scanCount},
+				scan:           true,
+				stride:         1,
+			}, nil
+		}
+	case "HGETALL", "HKEYS", "HVALS", "SMEMBERS":
+		if len(args) == 1 {
+			bc := &boundedCommand{
+				command:        []interface{}{"HSCAN", args[0]},
+				scanOptionList: []interface{}{"COUNT", scanCount},
+				scan:           true,
+				stride:         1,
+			}
+			switch name {
+			case "HKEYS":
+				bc.stride = 2
+			case "HVALS":
+				bc.stride, bc.offset = 2, 1
+			case "SMEMBERS":
+				bc.command[0] = "SSCAN"
+			}
+			return bc, nil
+		}
+	case "LRANGE", "ZRANGE", "ZREVRANGE":
+		if len(args) < 3 {
+			break
+		}
+		if name == "ZRANGE" && (hasOption(args[3:], "BYSCORE") || hasOption(args[3:], "BYLEX")) {
+			if !hasOption(args[3:], "LIMIT") {
+				return &boundedCommand{command: appendArgs(command, "LIMIT", 0, limit)}, nil
+			}
+			break
+		}
+		start, err1 := strconv.Atoi(fmt.Sprintf("%v", args[1]))
+		stop, err2 := strconv.Atoi(fmt.Sprintf("%v", args[2]))
+		if err1 != nil || err2 != nil {
+			// Let the server report the invalid index.
+			break
+		}
+		if start < 0 || stop < 0 {
+			lengthCommand := "ZCARD"
+			if name == "LRANGE" {
+				lengthCommand = "LLEN"
+			}
+			return &boundedCommand{command: command, lengthCommand: lengthCommand}, nil
+		}
+		return &boundedCommand{command: capRange(command, start, stop, limit)}, nil
+	case "ZRANGEBYSCORE", "ZREVRANGEBYSCORE", "ZRANGEBYLEX", "ZREVRANGEBYLEX":
+		if len(args) >= 3 && !hasOption(args[3:], "LIMIT") {
+			return &boundedCommand{command: appendArgs(command, "LIMIT", 0, limit)}, nil
+		}
+	case "XRANGE", "XREVRANGE":
+		if len(args) >= 3 && !hasOption(args[3:], "COUNT") {
+			return &boundedCommand{command: appendArgs(command, "COUNT", limit)}, nil
+		}
+	}
+	return &boundedCommand{command: command}, nil
+}
+
+// runBoundedCommand runs the command returning at most limit elements, the reply is in the format of Cmd.Val().
+func runBoundedCommand(ctx context.Context, conn *goredis.Conn, command []interface{}, limit int) (interface{}, error) {
+	bc, err := boundCommand(command, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if bc.scan {
+		var values []interface{}
+		cursor := "0"
+		for {
+			cmd := goredis.NewCmd(ctx, append(appendArgs(bc.command, cursor), bc.scanOptionList...)...)
+			if err := conn.Process(ctx, cmd); err != nil {
+				return nil, errors.Wrapf(err, "failed to execute command %q", formatCommand(command))
+			}
+			reply, ok := cmd.Val().([]interface{})
+			if !ok || len(reply) != 2 {
+				return nil, errors.Errorf("unexpected reply %v of command %q", cmd.Val(), formatCommand(cmd.Args()))
+			}
+			elementList, _ := reply[1].([]interface{})
+			for i := bc.offset; i < len(elementList); i += bc.stride {
+				values = append(values, elementList[i])
+			}
+			cursor = fmt.Sprintf("%v", reply[0])
+			if cursor == "0" || len(values) >= limit {
+				break
+			}
+		}
+		if len(values) > limit {
+			values = values[:limit]
+		}
+		return values, nil
+	}
+
+	if bc.lengthCommand != "" {
+		cmd := goredis.NewIntCmd(ctx, bc.lengthCommand, bc.command[1])
+		if err := conn.Process(ctx, cmd); err != nil {
+			return nil, errors.Wrapf(err, "failed to execute command %q", formatCommand(cmd.Args()))
+		}
+		length := int(cmd.Val())
+		start, _ := strconv.Atoi(fmt.Sprintf("%v", bc.command[2]))
+		stop, _ := strconv.Atoi(fmt.Sprintf("%v", bc.command[3]))
+		if start < 0 {
+			start += length
+		}
+		if stop < 0 {
+			stop += length
+		}
+		if start < 0 {
+			start = 0
+		}
+		bc.command = capRange(bc.command, start, stop, limit)
+	}
+
+	cmd := goredis.NewCmd(ctx, bc.command...)
+	if err := conn.Process(ctx, cmd); err != nil && err != goredis.Nil {
+		return nil, errors.Wrapf(err, "failed to execute command %q", formatCommand(command))
+	}
+	return cmd.Val(), nil
+}
+
+// capRange returns the range command with the non-negative start and stop, covering at most limit elements.
+func capRange(command []interface{}, start, stop, limit int) []interface{} {
+	if stop-start+1 > limit {
+		stop = start + limit - 1
+	}
+	res := appendArgs(command[:2])
+	res = append(res, start, stop)
+	return append(res, command[4:]...)
+}
+
+func hasOption(args []interface{}, option string) bool {
+	for _, arg := range args {
+		if strings.EqualFold(fmt.Sprintf("%v", arg), option) {
+			return true
+		}
+	}
+	return false
+}
+
+// appendArgs returns a new slice, so the command is never modified.
+func appendArgs(command []interface{}, args ...interface{}) []interface{} {
+	res := make([]interface{}, 0, len(command)+len(args))
+	res = append(res, command...)
+	return append(res, args...)
+}
@@ -0,0 +1,343 @@
+// Package redis is the plugin for Redis driver.
+package redis
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+const (
+	defaultPort = "6379"
+	// defaultDatabaseCount is the number of logical databases if we cannot get it from the server config.
+	defaultDatabaseCount = 16
+)
+
+var (
+	_ db.Driver = (*Driver)(nil)
+)
+
+func init() {
+	db.Register(db.Redis, newDriver)
+}
+
+// Driver is the Redis driver.
+type Driver struct {
+	connectionCtx db.ConnectionContext
+	config        db.ConnectionConfig
+
+	rdb *goredis.Client
+}
+
+func newDriver(db.DriverConfig) db.Driver {
+	return &Driver{}
+}
+
+// Open opens a Redis driver.
+// The database is the index of the logical database, and it's 0 if not specified.
+func (driver *Driver) Open(_ context.Context, _ db.Type, config db.ConnectionConfig, connCtx db.ConnectionContext) (db.Driver, error) {
+	database := 0
+	if config.Database != "" {
+		d, err := strconv.Atoi(config.Database)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid Redis database %q, it must be the index of the logical database", config.Database)
+		}
+		database = d
+	}
+	tlsConfig, err := config.TLSConfig.GetSslConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "redis: tls config error")
+	}
+	port := config.Port
+	if port == "" {
+		port = defaultPort
+	}
+
+	driver.rdb = goredis.NewClient(&goredis.Options{
+		Addr:      net.JoinHostPort(config.Host, port),
+		Username:  config.Username,
+		Password:  config.Password,
+		DB:        database,
+		TLSConfig: tlsConfig,
+	})
+	driver.config = config
+	driver.connectionCtx = connCtx
+	return driver, nil
+}
+
+// Close closes the driver.
+func (driver *Driver) Close(context.Context) error {
+	return driver.rdb.Close()
+}
+
+// Ping pings the database.
+func (driver *Driver) Ping(ctx context.Context) error {
+	if err := driver.rdb.Ping(ctx).Err(); err != nil {
+		return errors.Wrap(err, "failed to ping Redis")
+	}
+	return nil
+}
+
+// GetType returns the database type.
+func (*Driver) GetType() db.Type {
+	return db.Redis
+}
+
+// GetDBConnection returns a database connection.
+// It always returns an error because Redis doesn't implement the SQL interface.
+func (*Driver) GetDBConnection(_ context.Context, _ string) (*sql.DB, error) {
+	return nil, errors.New("redis doesn't support GetDBConnection")
+}
+
+// Execute executes the command script, always returns 0 as the number of rows affected.
+// The commands run on a single connection, so that SELECT and MULTI/EXEC apply to the following commands.
+func (driver *Driver) Execute(ctx context.Context, statement string, _ bool) (int64, error) {
+	commands, err := parseCommands(statement)
+	if err != nil {
+		return 0, err
+	}
+
+	conn := driver.rdb.Conn(ctx)
+	defer conn.Close()
+	for _, command := range commands {
+		cmd := goredis.NewCmd(ctx, command...)
+		if err := conn.Process(ctx, cmd); err != nil && err != goredis.Nil {
+			return 0, errors.Wrapf(err, "failed to execute command %q", formatCommand(command))
+		}
+	}
+	return 0, nil
+}
+
+// Query runs the commands and returns one row per result element.
+// If the query is read-only, the commands which aren't flagged as readonly by the server are rejected.
+func (driver *Driver) Query(ctx context.Context, statement string, queryContext *db.QueryContext) ([]interface{}, error) {
+	if queryContext.Timeout > 0 {
+		// The client stops waiting for the reply after the context deadline.
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, queryContext.Timeout)
+		defer cancel()
+	}
+	commands, err := parseCommands(statement)
+	if err != nil {
+		return nil, err
+	}
+	if queryContext.ReadOnly {
+		commandInfos, err := driver.rdb.Command(ctx).Result()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get command info")
+		}
+		for _, command := range commands {
+			name := strings.ToLower(fmt.Sprintf("%v", command[0]))
+			info, ok := commandInfos[name]
+			if !ok {
+				return nil, common.Errorf(common.Invalid, "unknown command %q", name)
+			}
+			if !info.ReadOnly {
+				return nil, common.Errorf(common.Invalid, "command %q is not allowed because only read-only commands are supported", name)
+			}
+		}
+	}
+
+	conn := driver.rdb.Conn(ctx)
+	defer conn.Close()
+	var rows [][]interface{}
+	for _, command := range commands {
+		var result interface{}
+		if queryContext.Limit > 0 {
+			// Bound the reply on the server side, the limit only applies after the whole reply is read.
+			r, err := runBoundedCommand(ctx, conn, command, queryContext.Limit)
+			if err != nil {
+				return nil, err
+			}
+			result = r
+		} else {
+			cmd := goredis.NewCmd(ctx, command...)
+			if err := conn.Process(ctx, cmd); err != nil && err != goredis.Nil {
+				return nil, errors.Wrapf(err, "failed to execute command %q", formatCommand(command))
+			}
+			result = cmd.Val()
+		}
+		values, err := convertResult(result)
+		if err != nil {
+			return nil, err
+		}
+		for _, value := range values {
+			if queryContext.Limit > 0 && len(rows) >= queryContext.Limit {
+				break
+			}
+			rows = append(rows, []interface{}{formatCommand(command), value})
+		}
+	}
+
+	field := []string{"command", "result"}
+	types := []string{"TEXT", "TEXT"}
+	return []interface{}{field, types, rows}, nil
+}
+
+// convertResult flattens the reply into the row values.
+// An array reply has a row per element, and the nested arrays are encoded as JSON.
+func convertResult(result interface{}) ([]interface{}, error) {
+	list, ok := result.([]interface{})
+	if !ok {
+		return []interface{}{result}, nil
+	}
+	var values []interface{}
+	for _, element := range list {
+		if _, ok := element.([]interface{}); ok {
+			bytes, err := json.Marshal(element)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to marshal the nested reply")
+			}
+			values = append(values, string(bytes))
+			continue
+		}
+		values = append(values, element)
+	}
+	return values, nil
+}
+
+// parseCommands parses the command script, which has a command per line in the redis-cli syntax.
+// The empty lines and the lines starting with "#" are skipped.
+func parseCommands(statement string) ([][]interface{}, error) {
+	var commands [][]interface{}
+	for i, line := range strings.Split(statement, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		args, err := splitArgs(line)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse line %d", i+1)
+		}
+		command := make([]interface{}, 0, len(args))
+		for _, arg := range args {
+			command = append(command, arg)
+		}
+		commands = append(commands, command)
+	}
+	return commands, nil
+}
+
+// splitArgs splits the line into arguments in the same way as redis-cli.
+// The double-quoted arguments support escape sequences such as \n and \x41, while the single-quoted arguments only support \'.
+func splitArgs(line string) ([]string, error) {
+	var args []string
+	i := 0
+	for {
+		for i < len(line) && (line[i] == ' ' || line[i] == '\t') {
+			i++
+		}
+		if i >= len(line) {
+			return args, nil
+		}
+		var sb strings.Builder
+		switch line[i] {
+		case '"':
+			i++
+			closed := false
+			for i < len(line) && !closed {
+				c := line[i]
+				switch {
+				case c == '\\' && i+3 < len(line) && line[i+1] == 'x' && isHexDigit(line[i+2]) && isHexDigit(line[i+3]):
+					v, _ := strconv.ParseUint(line[i+2:i+4], 16, 8)
+					_ = sb.WriteByte(byte(v))
+					i += 4
+				case c == '\\' && i+1 < len(line):
+					switch line[i+1] {
+					case 'n':
+						_ = sb.WriteByte('\n')
+					case 'r':
+						_ = sb.WriteByte('\r')
+					case 't':
+						_ = sb.WriteByte('\t')
+					case 'b':
+						_ = sb.WriteByte('\b')
+					case 'a':
+						_ = sb.WriteByte('\a')
+					default:
+						_ = sb.WriteByte(line[i+1])
+					}
+					i += 2
+				case c == '"':
+					closed = true
+					i++
+				default:
+					_ = sb.WriteByte(c)
+					i++
+				}
+			}
+			if !closed {
+				return nil, errors.New("unbalanced double quotes")
+			}
+		case '\'':
+			i++
+			closed := false
+			for i < len(line) && !closed {
+				c := line[i]
+				switch {
+				case c == '\\' && i+1 < len(line) && line[i+1] == '\'':
+					_ = sb.WriteByte('\'')
+					i += 2
+				case c == '\'':
+					closed = true
+					i++
+				default:
+					_ = sb.WriteByte(c)
+					i++
+				}
+			}
+			if !closed {
+				return nil, errors.New("unbalanced single quotes")
+			}
+		default:
+			for i < len(line) && line[i] != ' ' && line[i] != '\t' {
+				_ = sb.WriteByte(line[i])
+				i++
+			}
+		}
+		// The closing quote must be followed by a space or nothing.
+		if i < len(line) && line[i] != ' ' && line[i] != '\t' {
+			return nil, errors.New("closing quote must be followed by a space or nothing at all")
+		}
+		args = append(args, sb.String())
+	}
+}
+
+func isHexDigit(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+// formatCommand formats the command for the error messages and the query results.
+func formatCommand(command []interface{}) string {
+	var args []string
+	for _, arg := range command {
+		s := fmt.Sprintf("%v", arg)
+		if s == "" || strings.ContainsAny(s, " \t\n\"'") {
+			s = strconv.Quote(s)
+		}
+		args = append(args, s)
+	}
+	return strings.Join(args, " ")
+}
+
+// Dump dumps the database.
+// Redis is schemaless, so there is nothing to dump.
+func (*Driver) Dump(_ context.Context, _ string, _ io.Writer, _ bool) (string, error) {
+	return "", nil
+}
+
+// Restore restores the backup read from src.
+func (*Driver) Restore(_ context.Context, _ io.Reader) error {
+	return errors.New("restore is not supported for Redis")
+}
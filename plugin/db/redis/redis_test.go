@@ -0,0 +1,158 @@
+package redis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	v1pb "github.com/bytebase/bytebase/proto/generated-go/v1"
+)
+
+func TestParseCommands(t *testing.T) {
+	tests := []struct {
+		statement string
+		want      [][]interface{}
+	}{
+		{
+			statement: "GET foo",
+			want:      [][]interface{}{{"GET", "foo"}},
+		},
+		{
+			statement: "# comment\nSET foo bar\n\n  HGETALL   user:1  \n",
+			want:      [][]interface{}{{"SET", "foo", "bar"}, {"HGETALL", "user:1"}},
+		},
+		{
+			statement: `SET greeting "hello world\n" EX 10`,
+			want:      [][]interface{}{{"SET", "greeting", "hello world\n", "EX", "10"}},
+		},
+		{
+			statement: `SET key "\x41\"B\"\\"`,
+			want:      [][]interface{}{{"SET", "key", `A"B"\`}},
+		},
+		{
+			statement: `SET key 'it\'s \n raw' ""`,
+			want:      [][]interface{}{{"SET", "key", `it's \n raw`, ""}},
+		},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		commands, err := parseCommands(test.statement)
+		a.NoError(err, test.statement)
+		a.Equal(test.want, commands, test.statement)
+	}
+
+	for _, statement := range []string{`GET "foo`, `GET 'foo`, `GET "foo"bar`} {
+		_, err := parseCommands(statement)
+		a.Error(err, statement)
+	}
+}
+
+func TestParseACLUser(t *testing.T) {
+	tests := []struct {
+		line string
+		want *v1pb.DatabaseRole
+	}{
+		{
+			line: "user default on nopass ~* &* +@all",
+			want: &v1pb.DatabaseRole{
+				Name:            "default",
+				ConnectionLimit: -1,
+				Attribute:       &v1pb.DatabaseRoleAttribute{CanLogin: true, SuperUser: true},
+			},
+		},
+		{
+			line: "user reader off #5e884898da28047151d0e56f8dc6292773603d0d6aabbdd62a11ef721d1542d8 ~cache:* resetchannels -@all +get",
+			want: &v1pb.DatabaseRole{
+				Name:            "reader",
+				ConnectionLimit: -1,
+				Attribute:       &v1pb.DatabaseRoleAttribute{},
+			},
+		},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		role, err := parseACLUser(test.line)
+		a.NoError(err)
+		a.Equal(test.want.Name, role.Name)
+		a.Equal(test.want.ConnectionLimit, role.ConnectionLimit)
+		a.Equal(test.want.Attribute.CanLogin, role.Attribute.CanLogin)
+		a.Equal(test.want.Attribute.SuperUser, role.Attribute.SuperUser)
+	}
+
+	_, err := parseACLUser("invalid")
+	a.Error(err)
+}
+
+func TestBoundCommand(t *testing.T) {
+	tests := []struct {
+		command []interface{}
+		want    *boundedCommand
+		err     bool
+	}{
+		{
+			command: []interface{}{"GET", "foo"},
+			want:    &boundedCommand{command: []interface{}{"GET", "foo"}},
+		},
+		{
+			command: []interface{}{"keys", "user:*"},
+			want: &boundedCommand{
+				command:        []interface{}{"SCAN"},
+				scanOptionList: []interface{}{"MATCH", "user:*", "COUNT", scanCount},
+				scan:           true,
+				stride:         1,
+			},
+		},
+		{
+			command: []interface{}{"HVALS", "user:1"},
+			want: &boundedCommand{
+				command:        []interface{}{"HSCAN", "user:1"},
+				scanOptionList: []interface{}{"COUNT", scanCount},
+				scan:           true,
+				stride:         2,
+				offset:         1,
+			},
+		},
+		{
+			command: []interface{}{"SMEMBERS", "tags"},
+			want: &boundedCommand{
+				command:        []interface{}{"SSCAN", "tags"},
+				scanOptionList: []interface{}{"COUNT", scanCount},
+				scan:           true,
+				stride:         1,
+			},
+		},
+		{
+			command: []interface{}{"LRANGE", "list", "5", "100000"},
+			want:    &boundedCommand{command: []interface{}{"LRANGE", "list", 5, 14}},
+		},
+		{
+			command: []interface{}{"ZRANGE", "z", "0", "-1", "WITHSCORES"},
+			want:    &boundedCommand{command: []interface{}{"ZRANGE", "z", "0", "-1", "WITHSCORES"}, lengthCommand: "ZCARD"},
+		},
+		{
+			command: []interface{}{"ZRANGE", "z", "-inf", "+inf", "BYSCORE"},
+			want:    &boundedCommand{command: []interface{}{"ZRANGE", "z", "-inf", "+inf", "BYSCORE", "LIMIT", 0, 10}},
+		},
+		{
+			command: []interface{}{"XRANGE", "s", "-", "+"},
+			want:    &boundedCommand{command: []interface{}{"XRANGE", "s", "-", "+", "COUNT", 10}},
+		},
+		{
+			command: []interface{}{"SUNION", "a", "b"},
+			err:     true,
+		},
+	}
+
+	a := require.New(t)
+	for _, test := range tests {
+		got, err := boundCommand(test.command, 10)
+		if test.err {
+			a.Error(err, test.command)
+			continue
+		}
+		a.NoError(err, test.command)
+		a.Equal(test.want, got, test.command)
+	}
+}
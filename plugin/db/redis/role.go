@@ -0,0 +1,163 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/common"
+	v1pb "github.com/bytebase/bytebase/proto/generated-go/v1"
+)
+
+// In Redis, a role maps to an ACL user.
+// The superuser attribute grants all keys, channels and commands, and the login attribute enables the user.
+
+// CreateRole creates the ACL user.
+func (driver *Driver) CreateRole(ctx context.Context, upsert *v1pb.DatabaseRoleUpsert) (*v1pb.DatabaseRole, error) {
+	if err := validateRoleUpsert(upsert); err != nil {
+		return nil, err
+	}
+	if _, err := driver.FindRole(ctx, upsert.Name); err == nil {
+		return nil, common.Errorf(common.Conflict, "the role %s already exists", upsert.Name)
+	} else if common.ErrorCode(err) != common.NotFound {
+		return nil, err
+	}
+
+	args := []interface{}{"ACL", "SETUSER", upsert.Name}
+	args = append(args, convertToRules(upsert)...)
+	if err := driver.rdb.Do(ctx, args...).Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to create the role %s", upsert.Name)
+	}
+	return driver.FindRole(ctx, upsert.Name)
+}
+
+// UpdateRole updates the ACL user.
+func (driver *Driver) UpdateRole(ctx context.Context, roleName string, upsert *v1pb.DatabaseRoleUpsert) (*v1pb.DatabaseRole, error) {
+	if roleName != upsert.Name {
+		return nil, common.Errorf(common.Invalid, "renaming the role isn't supported for Redis")
+	}
+	if err := validateRoleUpsert(upsert); err != nil {
+		return nil, err
+	}
+	if _, err := driver.FindRole(ctx, roleName); err != nil {
+		return nil, err
+	}
+
+	rules := convertToRules(upsert)
+	if len(rules) > 0 {
+		args := []interface{}{"ACL", "SETUSER", roleName}
+		args = append(args, rules...)
+		if err := driver.rdb.Do(ctx, args...).Err(); err != nil {
+			return nil, errors.Wrapf(err, "failed to update the role %s", roleName)
+		}
+	}
+	return driver.FindRole(ctx, roleName)
+}
+
+// FindRole finds the ACL user by name.
+func (driver *Driver) FindRole(ctx context.Context, roleName string) (*v1pb.DatabaseRole, error) {
+	roles, err := driver.ListRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range roles {
+		if role.Name == roleName {
+			return role, nil
+		}
+	}
+	return nil, common.Errorf(common.NotFound, "cannot find the role %s", roleName)
+}
+
+// ListRole lists the ACL users.
+func (driver *Driver) ListRole(ctx context.Context) ([]*v1pb.DatabaseRole, error) {
+	lines, err := driver.rdb.Do(ctx, "ACL", "LIST").StringSlice()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list ACL users")
+	}
+	var roles []*v1pb.DatabaseRole
+	for _, line := range lines {
+		role, err := parseACLUser(line)
+		if err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+	return roles, nil
+}
+
+// DeleteRole deletes the ACL user by name.
+func (driver *Driver) DeleteRole(ctx context.Context, roleName string) error {
+	deleted, err := driver.rdb.Do(ctx, "ACL", "DELUSER", roleName).Int()
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete the role %s", roleName)
+	}
+	if deleted == 0 {
+		return common.Errorf(common.NotFound, "cannot find the role %s", roleName)
+	}
+	return nil
+}
+
+// parseACLUser parses a line of the ACL LIST result, such as "user default on nopass ~* &* +@all".
+func parseACLUser(line string) (*v1pb.DatabaseRole, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 || fields[0] != "user" {
+		return nil, errors.Errorf("invalid ACL user %q", line)
+	}
+	role := &v1pb.DatabaseRole{
+		Name:            fields[1],
+		ConnectionLimit: -1,
+		Attribute:       &v1pb.DatabaseRoleAttribute{},
+	}
+	allKeys, allCommands := false, false
+	for _, rule := range fields[2:] {
+		switch rule {
+		case "on":
+			role.Attribute.CanLogin = true
+		case "~*", "allkeys":
+			allKeys = true
+		case "+@all", "allcommands":
+			allCommands = true
+		}
+	}
+	role.Attribute.SuperUser = allKeys && allCommands
+	return role, nil
+}
+
+// convertToRules converts the upsert to the ACL SETUSER rules.
+func convertToRules(upsert *v1pb.DatabaseRoleUpsert) []interface{} {
+	var rules []interface{}
+	if v := upsert.Attribute; v != nil {
+		if v.CanLogin {
+			rules = append(rules, "on")
+		} else {
+			rules = append(rules, "off")
+		}
+		if v.SuperUser {
+			rules = append(rules, "~*", "&*", "+@all")
+		} else {
+			rules = append(rules, "resetkeys", "resetchannels", "-@all")
+		}
+	}
+	if v := upsert.Password; v != nil {
+		rules = append(rules, "resetpass", fmt.Sprintf(">%s", *v))
+	}
+	return rules
+}
+
+// validateRoleUpsert rejects the attributes that have no equivalent in Redis ACL.
+func validateRoleUpsert(upsert *v1pb.DatabaseRoleUpsert) error {
+	if v := upsert.ConnectionLimit; v != nil && *v != -1 {
+		return common.Errorf(common.Invalid, "connection limit isn't supported for Redis")
+	}
+	if upsert.ValidUntil != nil {
+		return common.Errorf(common.Invalid, "valid until isn't supported for Redis")
+	}
+	if v := upsert.Attribute; v != nil {
+		if v.NoInherit || v.CreateRole || v.CreateDb || v.Replication || v.BypassRls {
+			return common.Errorf(common.Invalid, "only the superuser and login attributes are supported for Redis")
+		}
+	}
+	return nil
+}
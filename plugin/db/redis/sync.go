@@ -0,0 +1,100 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+)
+
+// SyncInstance syncs the instance meta.
+// The databases are the logical databases from 0 to the configured "databases" minus one.
+func (driver *Driver) SyncInstance(ctx context.Context) (*db.InstanceMeta, error) {
+	version, err := driver.getVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	databaseCount := driver.getDatabaseCount(ctx)
+	var databaseMetaList []db.DatabaseMeta
+	for i := 0; i < databaseCount; i++ {
+		databaseMetaList = append(databaseMetaList, db.DatabaseMeta{
+			Name: strconv.Itoa(i),
+		})
+	}
+
+	return &db.InstanceMeta{
+		Version:      version,
+		UserList:     driver.getUserList(ctx),
+		DatabaseList: databaseMetaList,
+	}, nil
+}
+
+// SyncDBSchema syncs the database schema.
+// Redis is schemaless, so only the database name is returned.
+func (driver *Driver) SyncDBSchema(ctx context.Context, databaseName string) (*storepb.DatabaseMetadata, error) {
+	index, err := strconv.Atoi(databaseName)
+	if err != nil || index < 0 || index >= driver.getDatabaseCount(ctx) {
+		return nil, errors.Errorf("database %s does not exist", databaseName)
+	}
+	return &storepb.DatabaseMetadata{
+		Name: databaseName,
+	}, nil
+}
+
+// getVersion gets the version from the server section of INFO.
+func (driver *Driver) getVersion(ctx context.Context) (string, error) {
+	info, err := driver.rdb.Info(ctx, "server").Result()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get the server info")
+	}
+	for _, line := range strings.Split(info, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "redis_version:") {
+			return strings.TrimPrefix(line, "redis_version:"), nil
+		}
+	}
+	return "", errors.New("cannot find redis_version in the server info")
+}
+
+// getDatabaseCount gets the number of logical databases.
+// CONFIG GET is usually disabled on managed services, so we fall back to the default.
+func (driver *Driver) getDatabaseCount(ctx context.Context) int {
+	values, err := driver.rdb.ConfigGet(ctx, "databases").Result()
+	if err != nil || len(values) != 2 {
+		log.Debug("failed to get the number of databases, use the default", zap.Error(err))
+		return defaultDatabaseCount
+	}
+	count, err := strconv.Atoi(values[1].(string))
+	if err != nil {
+		return defaultDatabaseCount
+	}
+	return count
+}
+
+// getUserList gets the ACL users.
+// ACL is introduced in Redis 6, so the user list is empty for the older versions.
+func (driver *Driver) getUserList(ctx context.Context) []db.User {
+	lines, err := driver.rdb.Do(ctx, "ACL", "LIST").StringSlice()
+	if err != nil {
+		log.Debug("failed to list ACL users", zap.Error(err))
+		return nil
+	}
+	var userList []db.User
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		userList = append(userList, db.User{
+			Name:  fields[1],
+			Grant: strings.Join(fields[2:], " "),
+		})
+	}
+	return userList
+}
@@ -2,33 +2,417 @@ package snowflake
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db/util"
 	v1pb "github.com/bytebase/bytebase/proto/generated-go/v1"
 )
 
+var (
+	// systemRoleList is the system-defined roles, which cannot be managed.
+	systemRoleList = map[string]bool{
+		"ACCOUNTADMIN":  true,
+		"ORGADMIN":      true,
+		"PUBLIC":        true,
+		"SECURITYADMIN": true,
+		"SYSADMIN":      true,
+		"USERADMIN":     true,
+	}
+)
+
+// roleWithUser is the Snowflake role and the login user of the same name.
+// Snowflake roles cannot log in, so the password, the login and the valid until attributes are set on the user, whose default role is the role.
+type roleWithUser struct {
+	role    *v1pb.DatabaseRole
+	hasUser bool
+}
+
+type roleFind struct {
+	Name *string
+}
+
 // CreateRole creates the role.
-func (*Driver) CreateRole(_ context.Context, _ *v1pb.DatabaseRoleUpsert) (*v1pb.DatabaseRole, error) {
-	return nil, errors.Errorf("create role for Snowflake is not implemented yet")
+func (driver *Driver) CreateRole(ctx context.Context, upsert *v1pb.DatabaseRoleUpsert) (*v1pb.DatabaseRole, error) {
+	if err := validateRoleUpsert(upsert); err != nil {
+		return nil, err
+	}
+	statements := []string{fmt.Sprintf("CREATE ROLE %s", quoteIdentifier(upsert.Name))}
+	userStatements, err := getUserStatements(upsert, false /* hasUser */, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	statements = append(statements, userStatements...)
+	if v := upsert.Attribute; v != nil {
+		statements = append(statements, getGrantStatements(upsert.Name, &v1pb.DatabaseRoleAttribute{}, v)...)
+	}
+
+	conn, err := driver.getAccountAdminConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if err := execRoleStatements(ctx, conn, statements); err != nil {
+		return nil, err
+	}
+	return findRole(ctx, conn, upsert.Name)
 }
 
 // UpdateRole updates the role.
-func (*Driver) UpdateRole(_ context.Context, _ string, _ *v1pb.DatabaseRoleUpsert) (*v1pb.DatabaseRole, error) {
-	return nil, errors.Errorf("update role for Snowflake is not implemented yet")
+func (driver *Driver) UpdateRole(ctx context.Context, roleName string, upsert *v1pb.DatabaseRoleUpsert) (*v1pb.DatabaseRole, error) {
+	// The system role cannot be renamed or altered, and the new name is checked by validateRoleUpsert.
+	if systemRoleList[roleName] {
+		return nil, common.Errorf(common.Invalid, "cannot change the system role %s", roleName)
+	}
+	if err := validateRoleUpsert(upsert); err != nil {
+		return nil, err
+	}
+	conn, err := driver.getAccountAdminConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	roles, err := findRoleImpl(ctx, conn, &roleFind{Name: &roleName})
+	if err != nil {
+		return nil, err
+	}
+	if len(roles) == 0 {
+		return nil, common.Errorf(common.NotFound, "cannot find the role %s", roleName)
+	}
+	current := roles[0]
+
+	var statements []string
+	if roleName != upsert.Name {
+		statements = append(statements, fmt.Sprintf("ALTER ROLE %s RENAME TO %s", quoteIdentifier(roleName), quoteIdentifier(upsert.Name)))
+		if current.hasUser {
+			// The default role is stored by name, so it's reset to the renamed role.
+			statements = append(statements,
+				fmt.Sprintf("ALTER USER %s RENAME TO %s", quoteIdentifier(roleName), quoteIdentifier(upsert.Name)),
+				fmt.Sprintf("ALTER USER %s SET DEFAULT_ROLE = %s", quoteIdentifier(upsert.Name), quoteIdentifier(upsert.Name)),
+			)
+		}
+	}
+	userStatements, err := getUserStatements(upsert, current.hasUser, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	statements = append(statements, userStatements...)
+	if v := upsert.Attribute; v != nil {
+		statements = append(statements, getGrantStatements(upsert.Name, current.role.Attribute, v)...)
+	}
+	if err := execRoleStatements(ctx, conn, statements); err != nil {
+		return nil, err
+	}
+	return findRole(ctx, conn, upsert.Name)
 }
 
 // FindRole finds the role by name.
-func (*Driver) FindRole(_ context.Context, _ string) (*v1pb.DatabaseRole, error) {
-	return nil, errors.Errorf("find role for Snowflake is not implemented yet")
+func (driver *Driver) FindRole(ctx context.Context, roleName string) (*v1pb.DatabaseRole, error) {
+	conn, err := driver.getAccountAdminConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return findRole(ctx, conn, roleName)
 }
 
 // ListRole lists the role.
-func (*Driver) ListRole(_ context.Context) ([]*v1pb.DatabaseRole, error) {
-	return nil, errors.Errorf("list role for Snowflake is not implemented yet")
+func (driver *Driver) ListRole(ctx context.Context) ([]*v1pb.DatabaseRole, error) {
+	conn, err := driver.getAccountAdminConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	roles, err := findRoleImpl(ctx, conn, &roleFind{})
+	if err != nil {
+		return nil, err
+	}
+	var roleList []*v1pb.DatabaseRole
+	for _, role := range roles {
+		roleList = append(roleList, role.role)
+	}
+	return roleList, nil
+}
+
+// DeleteRole deletes the role and its login user by name.
+func (driver *Driver) DeleteRole(ctx context.Context, roleName string) error {
+	if systemRoleList[roleName] {
+		return common.Errorf(common.Invalid, "cannot delete the system role %s", roleName)
+	}
+	conn, err := driver.getAccountAdminConn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	roles, err := findRoleImpl(ctx, conn, &roleFind{Name: &roleName})
+	if err != nil {
+		return err
+	}
+	var statements []string
+	if len(roles) > 0 && roles[0].hasUser {
+		statements = append(statements, fmt.Sprintf("DROP USER IF EXISTS %s", quoteIdentifier(roleName)))
+	}
+	statements = append(statements, fmt.Sprintf("DROP ROLE IF EXISTS %s", quoteIdentifier(roleName)))
+	return execRoleStatements(ctx, conn, statements)
+}
+
+// getAccountAdminConn returns a connection using the ACCOUNTADMIN role, which can manage the roles, the users and the account privileges.
+// The role is set on a dedicated connection, so the other connections of the pool are not affected.
+func (driver *Driver) getAccountAdminConn(ctx context.Context) (*sql.Conn, error) {
+	conn, err := driver.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	statement := fmt.Sprintf("USE ROLE %s", accountAdminRole)
+	if _, err := conn.ExecContext(ctx, statement); err != nil {
+		conn.Close()
+		return nil, util.FormatErrorWithQuery(err, statement)
+	}
+	return conn, nil
+}
+
+// validateRoleUpsert validates the upsert because Snowflake roles don't support every role attribute.
+func validateRoleUpsert(upsert *v1pb.DatabaseRoleUpsert) error {
+	if systemRoleList[upsert.Name] {
+		return common.Errorf(common.Invalid, "cannot change the system role %s", upsert.Name)
+	}
+	if upsert.ConnectionLimit != nil && *upsert.ConnectionLimit != -1 {
+		return common.Errorf(common.Invalid, "connection limit is not supported for Snowflake")
+	}
+	if v := upsert.Attribute; v != nil && (v.NoInherit || v.Replication || v.BypassRls) {
+		return common.Errorf(common.Invalid, "only superuser, create role, create database and login attributes are supported for Snowflake")
+	}
+	return nil
+}
+
+// getUserStatements returns the statements creating or altering the login user of the role.
+// The user is created only if the role can log in, has a password or has a valid until timestamp.
+func getUserStatements(upsert *v1pb.DatabaseRoleUpsert, hasUser bool, now time.Time) ([]string, error) {
+	var properties []string
+	if v := upsert.Password; v != nil {
+		properties = append(properties, fmt.Sprintf("PASSWORD = '%s'", escapeString(*v)))
+	}
+	if v := upsert.ValidUntil; v != nil {
+		daysToExpiry, err := convertToDaysToExpiry(*v, now)
+		if err != nil {
+			return nil, err
+		}
+		properties = append(properties, fmt.Sprintf("DAYS_TO_EXPIRY = %d", daysToExpiry))
+	}
+	if v := upsert.Attribute; v != nil {
+		properties = append(properties, fmt.Sprintf("DISABLED = %t", !v.CanLogin))
+	}
+
+	user := quoteIdentifier(upsert.Name)
+	if hasUser {
+		if len(properties) == 0 {
+			return nil, nil
+		}
+		return []string{fmt.Sprintf("ALTER USER %s SET %s", user, strings.Join(properties, " "))}, nil
+	}
+	canLogin := upsert.Attribute != nil && upsert.Attribute.CanLogin
+	if !canLogin && upsert.Password == nil && upsert.ValidUntil == nil {
+		return nil, nil
+	}
+	if upsert.Attribute == nil {
+		// The user is disabled unless the role is given the login attribute explicitly.
+		properties = append(properties, "DISABLED = true")
+	}
+	properties = append(properties, fmt.Sprintf("DEFAULT_ROLE = %s", user))
+	return []string{
+		fmt.Sprintf("CREATE USER %s %s", user, strings.Join(properties, " ")),
+		fmt.Sprintf("GRANT ROLE %s TO USER %s", user, user),
+	}, nil
+}
+
+// getGrantStatements returns the statements granting or revoking the account privileges which the role attributes map onto.
+// The superuser is granted the ACCOUNTADMIN role.
+func getGrantStatements(roleName string, current, desired *v1pb.DatabaseRoleAttribute) []string {
+	var statements []string
+	role := quoteIdentifier(roleName)
+	for _, grant := range []struct {
+		grant   string
+		revoke  string
+		current bool
+		desired bool
+	}{
+		{
+			grant:   fmt.Sprintf("GRANT ROLE %s TO ROLE %s", accountAdminRole, role),
+			revoke:  fmt.Sprintf("REVOKE ROLE %s FROM ROLE %s", accountAdminRole, role),
+			current: current.SuperUser,
+			desired: desired.SuperUser,
+		},
+		{
+			grant:   fmt.Sprintf("GRANT CREATE DATABASE ON ACCOUNT TO ROLE %s", role),
+			revoke:  fmt.Sprintf("REVOKE CREATE DATABASE ON ACCOUNT FROM ROLE %s", role),
+			current: current.CreateDb,
+			desired: desired.CreateDb,
+		},
+		{
+			grant:   fmt.Sprintf("GRANT CREATE ROLE, CREATE USER ON ACCOUNT TO ROLE %s", role),
+			revoke:  fmt.Sprintf("REVOKE CREATE ROLE, CREATE USER ON ACCOUNT FROM ROLE %s", role),
+			current: current.CreateRole,
+			desired: desired.CreateRole,
+		},
+	} {
+		switch {
+		case grant.desired && !grant.current:
+			statements = append(statements, grant.grant)
+		case !grant.desired && grant.current:
+			statements = append(statements, grant.revoke)
+		}
+	}
+	return statements
+}
+
+func execRoleStatements(ctx context.Context, conn *sql.Conn, statements []string) error {
+	for _, statement := range statements {
+		if _, err := conn.ExecContext(ctx, statement); err != nil {
+			return util.FormatErrorWithQuery(err, statement)
+		}
+	}
+	return nil
+}
+
+func findRole(ctx context.Context, conn *sql.Conn, roleName string) (*v1pb.DatabaseRole, error) {
+	roles, err := findRoleImpl(ctx, conn, &roleFind{Name: &roleName})
+	if err != nil {
+		return nil, err
+	}
+	if len(roles) == 0 {
+		return nil, common.Errorf(common.NotFound, "cannot find the role %s", roleName)
+	}
+	return roles[0].role, nil
+}
+
+func findRoleImpl(ctx context.Context, conn *sql.Conn, find *roleFind) ([]*roleWithUser, error) {
+	roleStatement, userStatement := "SHOW ROLES", "SHOW USERS"
+	if v := find.Name; v != nil {
+		// LIKE is case-insensitive and matches the wildcards, so the names are compared exactly below.
+		roleStatement = fmt.Sprintf("SHOW ROLES LIKE '%s'", escapeString(*v))
+		userStatement = fmt.Sprintf("SHOW USERS LIKE '%s'", escapeString(*v))
+	}
+	roleRows, err := queryShowStatement(ctx, conn, roleStatement)
+	if err != nil {
+		return nil, err
+	}
+	userRows, err := queryShowStatement(ctx, conn, userStatement)
+	if err != nil {
+		return nil, err
+	}
+	users := make(map[string]map[string]string)
+	for _, user := range userRows {
+		users[user["name"]] = user
+	}
+
+	var roles []*roleWithUser
+	for _, roleRow := range roleRows {
+		name := roleRow["name"]
+		if find.Name != nil && name != *find.Name {
+			continue
+		}
+		if find.Name == nil && systemRoleList[name] {
+			continue
+		}
+		r := &roleWithUser{
+			role: &v1pb.DatabaseRole{
+				Name:            name,
+				ConnectionLimit: -1,
+				Attribute:       &v1pb.DatabaseRoleAttribute{},
+			},
+		}
+		if user, ok := users[name]; ok {
+			r.hasUser = true
+			r.role.Attribute.CanLogin = user["disabled"] != "true"
+			if v := user["expires_at_time"]; v != "" {
+				validUntil := v
+				if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+					validUntil = t.UTC().Format(time.RFC3339)
+				}
+				r.role.ValidUntil = &validUntil
+			}
+		}
+
+		grantRows, err := queryShowStatement(ctx, conn, fmt.Sprintf("SHOW GRANTS TO ROLE %s", quoteIdentifier(name)))
+		if err != nil {
+			return nil, err
+		}
+		for _, grant := range grantRows {
+			switch {
+			case grant["privilege"] == "USAGE" && grant["granted_on"] == "ROLE" && grant["name"] == accountAdminRole:
+				r.role.Attribute.SuperUser = true
+			case grant["privilege"] == "CREATE DATABASE" && grant["granted_on"] == "ACCOUNT":
+				r.role.Attribute.CreateDb = true
+			case grant["privilege"] == "CREATE ROLE" && grant["granted_on"] == "ACCOUNT":
+				r.role.Attribute.CreateRole = true
+			}
+		}
+		roles = append(roles, r)
+	}
+	return roles, nil
+}
+
+// queryShowStatement returns the rows of the SHOW statement as the maps from the column names to the values,
+// because the columns of the SHOW statements vary in the Snowflake versions.
+func queryShowStatement(ctx context.Context, conn *sql.Conn, statement string) ([]map[string]string, error) {
+	rows, err := conn.QueryContext(ctx, statement)
+	if err != nil {
+		return nil, util.FormatErrorWithQuery(err, statement)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	values := make([]sql.NullString, len(columns))
+	refs := make([]interface{}, len(columns))
+	for i := range values {
+		refs[i] = &values[i]
+	}
+	var result []map[string]string
+	for rows.Next() {
+		if err := rows.Scan(refs...); err != nil {
+			return nil, util.FormatErrorWithQuery(err, statement)
+		}
+		row := make(map[string]string)
+		for i, column := range columns {
+			row[column] = values[i].String
+		}
+		result = append(result, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to scan the result of %q", statement)
+	}
+	return result, nil
+}
+
+// convertToDaysToExpiry converts the valid until timestamp to DAYS_TO_EXPIRY of the user, which is rounded up to days from now.
+func convertToDaysToExpiry(validUntil string, now time.Time) (int, error) {
+	t, err := time.Parse(time.RFC3339, validUntil)
+	if err != nil {
+		return 0, common.Wrapf(err, common.Invalid, "invalid valid until %q", validUntil)
+	}
+	if !t.After(now) {
+		return 0, common.Errorf(common.Invalid, "valid until %q must be in the future", validUntil)
+	}
+	return int(math.Ceil(t.Sub(now).Hours() / 24)), nil
+}
+
+func quoteIdentifier(s string) string {
+	return fmt.Sprintf(`"%s"`, strings.ReplaceAll(s, `"`, `""`))
 }
 
-// DeleteRole deletes the role by name.
-func (*Driver) DeleteRole(_ context.Context, _ string) error {
-	return errors.Errorf("delete role for Snowflake is not implemented yet")
+func escapeString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return strings.ReplaceAll(s, "'", `\'`)
 }
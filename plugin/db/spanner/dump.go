@@ -2,15 +2,48 @@ package spanner
 
 import (
 	"context"
+	"fmt"
 	"io"
+
+	"cloud.google.com/go/spanner/admin/database/apiv1/databasepb"
+	"github.com/pkg/errors"
 )
 
-// Dump dumps the database.
-func (*Driver) Dump(_ context.Context, _ string, _ io.Writer, _ bool) (string, error) {
-	panic("not implemented")
+// Dump dumps the schema of the database as Spanner DDL statements.
+// Dumping the data isn't supported, and the migration history table is excluded from the schema.
+func (d *Driver) Dump(ctx context.Context, database string, out io.Writer, schemaOnly bool) (string, error) {
+	if !schemaOnly {
+		return "", errors.New("only the schema-only dump is supported for Spanner")
+	}
+	resp, err := d.dbClient.GetDatabaseDdl(ctx, &databasepb.GetDatabaseDdlRequest{
+		Database: getDSN(d.config.Host, database),
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get the schema of database %q", database)
+	}
+	for _, statement := range resp.Statements {
+		if isMigrationHistoryStatement(statement) {
+			continue
+		}
+		if _, err := fmt.Fprintf(out, "%s;\n\n", statement); err != nil {
+			return "", err
+		}
+	}
+	return "", nil
 }
 
-// Restore restores a database.
-func (*Driver) Restore(_ context.Context, _ io.Reader) error {
-	panic("not implemented")
+// Restore restores the schema dump into the connected database.
+func (d *Driver) Restore(ctx context.Context, src io.Reader) error {
+	if d.config.Database == "" {
+		return errors.New("the driver must connect to a database to restore")
+	}
+	dump, err := io.ReadAll(src)
+	if err != nil {
+		return err
+	}
+	statements := splitStatement(string(dump))
+	if len(statements) == 0 {
+		return nil
+	}
+	return d.updateDDL(ctx, d.config.Database, statements)
 }
@@ -2,64 +2,396 @@ package spanner
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
 
+	spanner "cloud.google.com/go/spanner"
 	"cloud.google.com/go/spanner/admin/database/apiv1/databasepb"
 	"go.uber.org/zap"
+	"google.golang.org/api/iterator"
+
+	"github.com/pkg/errors"
 
 	"github.com/bytebase/bytebase/common/log"
 	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/db/util"
+)
+
+const (
+	// migrationHistoryTable is the migration history table in each database.
+	// Spanner doesn't support cross-database queries, so the migration history is kept in the migrated database instead of the "bytebase" database.
+	migrationHistoryTable = "bytebase_migration_history"
+)
+
+var (
+	migrationHistoryTableRegexp = regexp.MustCompile("(?is)^CREATE\\s+TABLE\\s+`?" + migrationHistoryTable + "`?\\s*\\(")
+	migrationHistoryIndexRegexp = regexp.MustCompile("(?is)^CREATE\\s+(UNIQUE\\s+)?(NULL_FILTERED\\s+)?INDEX\\s+\\S+\\s+ON\\s+`?" + migrationHistoryTable + "`?\\s*\\(")
 
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	_ util.MigrationExecutor = (*Driver)(nil)
 )
 
 // NeedsSetupMigration checks if it needs to set up migration.
-func (d *Driver) NeedsSetupMigration(ctx context.Context) (bool, error) {
-	dsn := getDSN(d.config.Host, db.BytebaseDatabase)
-	_, err := d.dbClient.GetDatabase(ctx, &databasepb.GetDatabaseRequest{Name: dsn})
-	if status.Code(err) == codes.NotFound {
-		return true, nil
-	}
-	if err != nil {
-		return false, err
-	}
+// The migration history table is created in the database by its first migration, so the instance never needs the setup.
+func (*Driver) NeedsSetupMigration(_ context.Context) (bool, error) {
 	return false, nil
 }
 
-// SetupMigrationIfNeeded sets up migration if needed.
+// SetupMigrationIfNeeded creates the migration history table in the connected database if needed.
 func (d *Driver) SetupMigrationIfNeeded(ctx context.Context) error {
-	setup, err := d.NeedsSetupMigration(ctx)
+	if d.config.Database == "" {
+		return nil
+	}
+	exist, err := d.hasMigrationHistoryTable(ctx, d.config.Database)
 	if err != nil {
 		return err
 	}
-	if !setup {
+	if exist {
 		return nil
 	}
 	log.Info("Bytebase migration schema not found, creating schema...",
 		zap.String("environment", d.connCtx.EnvironmentName),
 		zap.String("instance", d.connCtx.InstanceName),
+		zap.String("database", d.config.Database),
 	)
-	statements := splitStatement(migrationSchema)
-	op, err := d.dbClient.CreateDatabase(ctx, &databasepb.CreateDatabaseRequest{
-		Parent:          d.config.Host,
-		CreateStatement: createBytebaseDatabaseStatement,
-		ExtraStatements: statements,
+	return d.updateDDL(ctx, d.config.Database, splitStatement(migrationSchema))
+}
+
+func (d *Driver) hasMigrationHistoryTable(ctx context.Context, database string) (bool, error) {
+	resp, err := d.dbClient.GetDatabaseDdl(ctx, &databasepb.GetDatabaseDdlRequest{
+		Database: getDSN(d.config.Host, database),
 	})
 	if err != nil {
-		return err
+		return false, errors.Wrapf(err, "failed to get the schema of database %q", database)
 	}
-	if _, err := op.Wait(ctx); err != nil {
-		return err
+	for _, statement := range resp.Statements {
+		if migrationHistoryTableRegexp.MatchString(statement) {
+			return true, nil
+		}
 	}
-	return nil
+	return false, nil
+}
+
+// isMigrationHistoryStatement returns true if the DDL statement creates the migration history table or its indexes.
+func isMigrationHistoryStatement(statement string) bool {
+	return migrationHistoryTableRegexp.MatchString(statement) || migrationHistoryIndexRegexp.MatchString(statement)
 }
 
 // ExecuteMigration executes a migration.
-func (*Driver) ExecuteMigration(_ context.Context, _ *db.MigrationInfo, _ string) (int64, string, error) {
-	panic("not implemented")
+// The driver must connect to the migrated database because the migration history is recorded in it.
+func (d *Driver) ExecuteMigration(ctx context.Context, m *db.MigrationInfo, statement string) (int64, string, error) {
+	if m.CreateDatabase {
+		return -1, "", errors.New("creating database is not supported for Spanner")
+	}
+	if d.config.Database != m.Database || d.client == nil {
+		return -1, "", errors.Errorf("the driver must connect to database %q to migrate it", m.Database)
+	}
+	if err := d.SetupMigrationIfNeeded(ctx); err != nil {
+		return -1, "", err
+	}
+	return util.ExecuteMigration(ctx, d, m, statement, m.Database)
 }
 
 // FindMigrationHistoryList finds the migration history list.
-func (*Driver) FindMigrationHistoryList(_ context.Context, _ *db.MigrationHistoryFind) ([]*db.MigrationHistory, error) {
-	panic("not implemented")
+// The migration history of all databases is searched if neither the driver nor the find specifies the database.
+func (d *Driver) FindMigrationHistoryList(ctx context.Context, find *db.MigrationHistoryFind) ([]*db.MigrationHistory, error) {
+	var databases []string
+	switch {
+	case find.Database != nil:
+		databases = []string{*find.Database}
+	case d.config.Database != "":
+		databases = []string{d.config.Database}
+	default:
+		instance, err := d.SyncInstance(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, database := range instance.DatabaseList {
+			databases = append(databases, database.Name)
+		}
+	}
+
+	var migrationHistoryList []*db.MigrationHistory
+	for _, database := range databases {
+		exist, err := d.hasMigrationHistoryTable(ctx, database)
+		if err != nil {
+			return nil, err
+		}
+		// The database is never migrated by Bytebase.
+		if !exist {
+			continue
+		}
+		list, err := d.findDatabaseMigrationHistoryList(ctx, database, find)
+		if err != nil {
+			return nil, err
+		}
+		migrationHistoryList = append(migrationHistoryList, list...)
+	}
+	if len(databases) > 1 {
+		sort.SliceStable(migrationHistoryList, func(i, j int) bool {
+			return migrationHistoryList[i].CreatedTs > migrationHistoryList[j].CreatedTs
+		})
+		if v := find.Limit; v != nil && len(migrationHistoryList) > *v {
+			migrationHistoryList = migrationHistoryList[:*v]
+		}
+	}
+	return migrationHistoryList, nil
+}
+
+func (d *Driver) findDatabaseMigrationHistoryList(ctx context.Context, database string, find *db.MigrationHistoryFind) ([]*db.MigrationHistory, error) {
+	client := d.client
+	if database != d.config.Database || client == nil {
+		var err error
+		client, err = spanner.NewClient(ctx, getDSN(d.config.Host, database), d.clientOptions...)
+		if err != nil {
+			return nil, err
+		}
+		defer client.Close()
+	}
+
+	query := `
+		SELECT
+			id,
+			created_by,
+			created_ts,
+			updated_by,
+			updated_ts,
+			release_version,
+			namespace,
+			sequence,
+			source,
+			type,
+			status,
+			version,
+			description,
+			statement,
+			schema,
+			schema_prev,
+			execution_duration_ns,
+			issue_id,
+			payload
+		FROM ` + migrationHistoryTable
+	var where []string
+	params := make(map[string]interface{})
+	if v := find.ID; v != nil {
+		where, params["id"] = append(where, "id = @id"), int64(*v)
+	}
+	if v := find.Database; v != nil {
+		where, params["namespace"] = append(where, "namespace = @namespace"), *v
+	}
+	if v := find.Version; v != nil {
+		// TODO(d): support semantic versioning.
+		storedVersion, err := util.ToStoredVersion(false, *v, "")
+		if err != nil {
+			return nil, err
+		}
+		where, params["version"] = append(where, "version = @version"), storedVersion
+	}
+	if v := find.Source; v != nil {
+		where, params["source"] = append(where, "source = @source"), string(*v)
+	}
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY id DESC"
+	if v := find.Limit; v != nil {
+		query += fmt.Sprintf(" LIMIT %d", *v)
+	}
+
+	iter := client.Single().Query(ctx, spanner.Statement{SQL: query, Params: params})
+	defer iter.Stop()
+	var migrationHistoryList []*db.MigrationHistory
+	for {
+		row, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to find the migration history of database %q", database)
+		}
+		var id, createdTs, updatedTs, sequence, executionDurationNs int64
+		var creator, updater, releaseVersion, namespace, source, migrationType, status, storedVersion, description, statement, schema, schemaPrev, issueID, payload string
+		if err := row.Columns(
+			&id,
+			&creator,
+			&createdTs,
+			&updater,
+			&updatedTs,
+			&releaseVersion,
+			&namespace,
+			&sequence,
+			&source,
+			&migrationType,
+			&status,
+			&storedVersion,
+			&description,
+			&statement,
+			&schema,
+			&schemaPrev,
+			&executionDurationNs,
+			&issueID,
+			&payload,
+		); err != nil {
+			return nil, err
+		}
+		useSemanticVersion, version, semanticVersionSuffix, err := util.FromStoredVersion(storedVersion)
+		if err != nil {
+			return nil, err
+		}
+		migrationHistoryList = append(migrationHistoryList, &db.MigrationHistory{
+			ID:                    int(id),
+			Creator:               creator,
+			CreatedTs:             createdTs,
+			Updater:               updater,
+			UpdatedTs:             updatedTs,
+			ReleaseVersion:        releaseVersion,
+			Namespace:             namespace,
+			Sequence:              int(sequence),
+			Source:                db.MigrationSource(source),
+			Type:                  db.MigrationType(migrationType),
+			Status:                db.MigrationStatus(status),
+			Version:               version,
+			Description:           description,
+			Statement:             statement,
+			Schema:                schema,
+			SchemaPrev:            schemaPrev,
+			ExecutionDurationNs:   executionDurationNs,
+			IssueID:               issueID,
+			Payload:               payload,
+			UseSemanticVersion:    useSemanticVersion,
+			SemanticVersionSuffix: semanticVersionSuffix,
+		})
+	}
+	return migrationHistoryList, nil
+}
+
+// FindLargestVersionSinceBaseline will find the largest version since last baseline or branch.
+func (d *Driver) FindLargestVersionSinceBaseline(ctx context.Context, tx *sql.Tx, namespace string) (*string, error) {
+	largestBaselineSequence, err := d.FindLargestSequence(ctx, tx, namespace, true /* baseline */)
+	if err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf("SELECT MAX(version) FROM %s WHERE namespace = @namespace AND sequence >= @sequence", migrationHistoryTable)
+	var version spanner.NullString
+	if err := d.queryOne(ctx, spanner.Statement{
+		SQL:    query,
+		Params: map[string]interface{}{"namespace": namespace, "sequence": int64(largestBaselineSequence)},
+	}, &version); err != nil {
+		return nil, err
+	}
+	if version.Valid {
+		return &version.StringVal, nil
+	}
+	return nil, nil
+}
+
+// FindLargestSequence will return the largest sequence number.
+func (d *Driver) FindLargestSequence(ctx context.Context, _ *sql.Tx, namespace string, baseline bool) (int, error) {
+	query := fmt.Sprintf("SELECT MAX(sequence) FROM %s WHERE namespace = @namespace", migrationHistoryTable)
+	if baseline {
+		query = fmt.Sprintf("%s AND (type = '%s' OR type = '%s')", query, db.Baseline, db.Branch)
+	}
+	var sequence spanner.NullInt64
+	if err := d.queryOne(ctx, spanner.Statement{
+		SQL:    query,
+		Params: map[string]interface{}{"namespace": namespace},
+	}, &sequence); err != nil {
+		return -1, err
+	}
+	if sequence.Valid {
+		return int(sequence.Int64), nil
+	}
+	// Returns 0 if we haven't applied any migration for this namespace.
+	return 0, nil
+}
+
+// queryOne reads the single column of the single row returned by the statement.
+func (d *Driver) queryOne(ctx context.Context, statement spanner.Statement, ptr interface{}) error {
+	iter := d.client.Single().Query(ctx, statement)
+	defer iter.Stop()
+	row, err := iter.Next()
+	if err != nil {
+		return errors.Wrapf(err, "failed to query %q", statement.SQL)
+	}
+	return row.Column(0, ptr)
+}
+
+// InsertPendingHistory will insert the migration record with pending status and return the inserted ID.
+// Spanner has no auto-increment column, so the ID is the largest ID plus one, read and inserted in the same read-write transaction.
+func (d *Driver) InsertPendingHistory(ctx context.Context, _ *sql.Tx, sequence int, prevSchema string, m *db.MigrationInfo, storedVersion, statement string) (int64, error) {
+	var insertedID int64
+	if _, err := d.client.ReadWriteTransaction(ctx, func(ctx context.Context, tx *spanner.ReadWriteTransaction) error {
+		iter := tx.Query(ctx, spanner.NewStatement(fmt.Sprintf("SELECT MAX(id) FROM %s", migrationHistoryTable)))
+		defer iter.Stop()
+		row, err := iter.Next()
+		if err != nil {
+			return err
+		}
+		var id spanner.NullInt64
+		if err := row.Column(0, &id); err != nil {
+			return err
+		}
+		insertedID = 1
+		if id.Valid {
+			insertedID = id.Int64 + 1
+		}
+
+		now := time.Now().Unix()
+		return tx.BufferWrite([]*spanner.Mutation{
+			spanner.InsertMap(migrationHistoryTable, map[string]interface{}{
+				"id":                    insertedID,
+				"created_by":            m.Creator,
+				"created_ts":            now,
+				"updated_by":            m.Creator,
+				"updated_ts":            now,
+				"release_version":       m.ReleaseVersion,
+				"namespace":             m.Namespace,
+				"sequence":              int64(sequence),
+				"source":                string(m.Source),
+				"type":                  string(m.Type),
+				"status":                string(db.Pending),
+				"version":               storedVersion,
+				"description":           m.Description,
+				"statement":             statement,
+				"schema":                prevSchema,
+				"schema_prev":           prevSchema,
+				"execution_duration_ns": int64(0),
+				"issue_id":              m.IssueID,
+				"payload":               m.Payload,
+			}),
+		})
+	}); err != nil {
+		return 0, errors.Wrap(err, "failed to insert a pending migration history record")
+	}
+	return insertedID, nil
+}
+
+// UpdateHistoryAsDone will update the migration record as done.
+func (d *Driver) UpdateHistoryAsDone(ctx context.Context, _ *sql.Tx, migrationDurationNs int64, updatedSchema string, insertedID int64) error {
+	return d.updateHistory(ctx, insertedID, map[string]interface{}{
+		"status":                string(db.Done),
+		"execution_duration_ns": migrationDurationNs,
+		"schema":                updatedSchema,
+	})
+}
+
+// UpdateHistoryAsFailed will update the migration record as failed.
+func (d *Driver) UpdateHistoryAsFailed(ctx context.Context, _ *sql.Tx, migrationDurationNs int64, insertedID int64) error {
+	return d.updateHistory(ctx, insertedID, map[string]interface{}{
+		"status":                string(db.Failed),
+		"execution_duration_ns": migrationDurationNs,
+	})
+}
+
+func (d *Driver) updateHistory(ctx context.Context, id int64, columns map[string]interface{}) error {
+	columns["id"] = id
+	columns["updated_ts"] = time.Now().Unix()
+	// The update mutation fails with NotFound if the record doesn't exist.
+	if _, err := d.client.Apply(ctx, []*spanner.Mutation{spanner.UpdateMap(migrationHistoryTable, columns)}); err != nil {
+		return errors.Wrapf(err, "failed to update the migration history record %d", id)
+	}
+	return nil
 }
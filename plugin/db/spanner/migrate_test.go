@@ -0,0 +1,233 @@
+package spanner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	spanner "cloud.google.com/go/spanner"
+	spannerdb "cloud.google.com/go/spanner/admin/database/apiv1"
+	"cloud.google.com/go/spanner/admin/database/apiv1/databasepb"
+	spannerinstance "cloud.google.com/go/spanner/admin/instance/apiv1"
+	"cloud.google.com/go/spanner/admin/instance/apiv1/instancepb"
+	"cloud.google.com/go/spanner/spannertest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+const (
+	testProject  = "projects/test-project"
+	testInstance = testProject + "/instances/test-instance"
+)
+
+// newTestDriver returns the driver connecting to a new database in the Spanner emulator if SPANNER_EMULATOR_HOST is set,
+// otherwise to the in-memory fake Spanner.
+func newTestDriver(t *testing.T) *Driver {
+	a := require.New(t)
+	ctx := context.Background()
+	database := fmt.Sprintf("test_%d", time.Now().UnixNano()%1000000)
+	var options []option.ClientOption
+	if os.Getenv("SPANNER_EMULATOR_HOST") != "" {
+		createEmulatorDatabase(t, database)
+	} else {
+		server, err := spannertest.NewServer("localhost:0")
+		a.NoError(err)
+		server.SetLogger(t.Logf)
+		t.Cleanup(server.Close)
+		options = []option.ClientOption{
+			option.WithEndpoint(server.Addr),
+			option.WithoutAuthentication(),
+			option.WithGRPCDialOption(grpc.WithInsecure()),
+		}
+	}
+
+	client, err := spanner.NewClient(ctx, getDSN(testInstance, database), options...)
+	a.NoError(err)
+	dbClient, err := spannerdb.NewDatabaseAdminClient(ctx, options...)
+	a.NoError(err)
+	driver := &Driver{
+		config:        db.ConnectionConfig{Host: testInstance, Database: database},
+		client:        client,
+		dbClient:      dbClient,
+		clientOptions: options,
+	}
+	t.Cleanup(func() {
+		_ = driver.Close(ctx)
+	})
+	return driver
+}
+
+func createEmulatorDatabase(t *testing.T, database string) {
+	a := require.New(t)
+	ctx := context.Background()
+	instanceClient, err := spannerinstance.NewInstanceAdminClient(ctx)
+	a.NoError(err)
+	defer instanceClient.Close()
+	instanceOp, err := instanceClient.CreateInstance(ctx, &instancepb.CreateInstanceRequest{
+		Parent:     testProject,
+		InstanceId: "test-instance",
+		Instance: &instancepb.Instance{
+			Config:      testProject + "/instanceConfigs/emulator-config",
+			DisplayName: "test-instance",
+			NodeCount:   1,
+		},
+	})
+	if status.Code(err) != codes.AlreadyExists {
+		a.NoError(err)
+		_, err = instanceOp.Wait(ctx)
+		a.NoError(err)
+	}
+
+	dbClient, err := spannerdb.NewDatabaseAdminClient(ctx)
+	a.NoError(err)
+	defer dbClient.Close()
+	databaseOp, err := dbClient.CreateDatabase(ctx, &databasepb.CreateDatabaseRequest{
+		Parent:          testInstance,
+		CreateStatement: fmt.Sprintf("CREATE DATABASE %s", database),
+	})
+	a.NoError(err)
+	_, err = databaseOp.Wait(ctx)
+	a.NoError(err)
+}
+
+func TestMigration(t *testing.T) {
+	a := require.New(t)
+	ctx := context.Background()
+	driver := newTestDriver(t)
+	database := driver.config.Database
+
+	setup, err := driver.NeedsSetupMigration(ctx)
+	a.NoError(err)
+	a.False(setup)
+	// The database never migrated has no migration history.
+	list, err := driver.FindMigrationHistoryList(ctx, &db.MigrationHistoryFind{Database: &database})
+	a.NoError(err)
+	a.Empty(list)
+
+	statement := `
+		CREATE TABLE author (
+			id INT64 NOT NULL,
+			name STRING(100) NOT NULL,
+		) PRIMARY KEY (id);
+		CREATE TABLE book (
+			id INT64 NOT NULL,
+			title STRING(100) NOT NULL,
+		) PRIMARY KEY (id);
+	`
+	id, schema, err := driver.ExecuteMigration(ctx, &db.MigrationInfo{
+		Version:     "0001",
+		Namespace:   database,
+		Database:    database,
+		Source:      db.UI,
+		Type:        db.Migrate,
+		Description: "Create tables",
+		Creator:     "alice",
+		IssueID:     "101",
+	}, statement)
+	a.NoError(err)
+	a.Equal(int64(1), id)
+	a.Contains(schema, "CREATE TABLE author")
+	a.Contains(schema, "CREATE TABLE book")
+	a.NotContains(schema, migrationHistoryTable)
+
+	statement = "ALTER TABLE book ADD COLUMN author_id INT64"
+	id, schema, err = driver.ExecuteMigration(ctx, &db.MigrationInfo{
+		Version:     "0002",
+		Namespace:   database,
+		Database:    database,
+		Source:      db.UI,
+		Type:        db.Migrate,
+		Description: "Add author",
+		Creator:     "alice",
+		IssueID:     "102",
+	}, statement)
+	a.NoError(err)
+	a.Equal(int64(2), id)
+	a.Contains(schema, "author_id")
+
+	list, err = driver.FindMigrationHistoryList(ctx, &db.MigrationHistoryFind{Database: &database})
+	a.NoError(err)
+	a.Len(list, 2)
+	a.Equal(2, list[0].ID)
+	a.Equal(2, list[0].Sequence)
+	a.Equal("0002", list[0].Version)
+	a.Equal(db.Done, list[0].Status)
+	a.Equal(statement, list[0].Statement)
+	a.Equal(schema, list[0].Schema)
+	a.Contains(list[0].SchemaPrev, "CREATE TABLE book")
+	a.NotContains(list[0].SchemaPrev, "author_id")
+	a.Equal("alice", list[1].Creator)
+	a.Equal("102", list[0].IssueID)
+
+	// The applied version is skipped.
+	id, _, err = driver.ExecuteMigration(ctx, &db.MigrationInfo{
+		Version:   "0002",
+		Namespace: database,
+		Database:  database,
+		Source:    db.UI,
+		Type:      db.Migrate,
+		IssueID:   "102",
+	}, statement)
+	a.NoError(err)
+	a.Equal(int64(2), id)
+
+	// The failed migration is recorded.
+	_, _, err = driver.ExecuteMigration(ctx, &db.MigrationInfo{
+		Version:   "0003",
+		Namespace: database,
+		Database:  database,
+		Source:    db.UI,
+		Type:      db.Migrate,
+		IssueID:   "104",
+	}, "DROP TABLE not_found")
+	a.Error(err)
+	version := "0003"
+	list, err = driver.FindMigrationHistoryList(ctx, &db.MigrationHistoryFind{Database: &database, Version: &version})
+	a.NoError(err)
+	a.Len(list, 1)
+	a.Equal(db.Failed, list[0].Status)
+
+	limit := 1
+	list, err = driver.FindMigrationHistoryList(ctx, &db.MigrationHistoryFind{Limit: &limit})
+	a.NoError(err)
+	a.Len(list, 1)
+	a.Equal("0003", list[0].Version)
+}
+
+func TestDumpAndRestore(t *testing.T) {
+	a := require.New(t)
+	ctx := context.Background()
+	driver := newTestDriver(t)
+
+	schema := "CREATE TABLE singer (\n  id INT64 NOT NULL,\n  name STRING(MAX),\n) PRIMARY KEY(id);\n\n"
+	a.NoError(driver.Restore(ctx, strings.NewReader(schema)))
+	a.NoError(driver.SetupMigrationIfNeeded(ctx))
+
+	var buf bytes.Buffer
+	_, err := driver.Dump(ctx, driver.config.Database, &buf, true /* schemaOnly */)
+	a.NoError(err)
+	dump := buf.String()
+	a.Contains(dump, "CREATE TABLE singer")
+	a.NotContains(dump, migrationHistoryTable)
+	a.Equal(1, strings.Count(dump, ";"))
+
+	_, err = driver.Dump(ctx, driver.config.Database, &buf, false /* schemaOnly */)
+	a.ErrorContains(err, "schema-only")
+
+	// Restoring the dump recreates the schema.
+	a.NoError(driver.updateDDL(ctx, driver.config.Database, []string{"DROP TABLE singer"}))
+	a.NoError(driver.Restore(ctx, strings.NewReader(dump)))
+	buf.Reset()
+	_, err = driver.Dump(ctx, driver.config.Database, &buf, true /* schemaOnly */)
+	a.NoError(err)
+	a.Equal(dump, buf.String())
+}
@@ -3,30 +3,31 @@ package spanner
 import (
 	"context"
 
+	"github.com/bytebase/bytebase/common"
 	v1pb "github.com/bytebase/bytebase/proto/generated-go/v1"
 )
 
 // CreateRole creates a role.
 func (*Driver) CreateRole(_ context.Context, _ *v1pb.DatabaseRoleUpsert) (*v1pb.DatabaseRole, error) {
-	panic("not implemented")
+	return nil, common.Errorf(common.NotImplemented, "role management is not supported for Spanner")
 }
 
 // UpdateRole updates a role.
 func (*Driver) UpdateRole(_ context.Context, _ string, _ *v1pb.DatabaseRoleUpsert) (*v1pb.DatabaseRole, error) {
-	panic("not implemented")
+	return nil, common.Errorf(common.NotImplemented, "role management is not supported for Spanner")
 }
 
 // FindRole finds the role.
 func (*Driver) FindRole(_ context.Context, _ string) (*v1pb.DatabaseRole, error) {
-	panic("not implemented")
+	return nil, common.Errorf(common.NotImplemented, "role management is not supported for Spanner")
 }
 
 // ListRole lists the roles.
 func (*Driver) ListRole(_ context.Context) ([]*v1pb.DatabaseRole, error) {
-	panic("not implemented")
+	return nil, common.Errorf(common.NotImplemented, "role management is not supported for Spanner")
 }
 
 // DeleteRole deletes the role.
 func (*Driver) DeleteRole(_ context.Context, _ string) error {
-	panic("not implemented")
+	return common.Errorf(common.NotImplemented, "role management is not supported for Spanner")
 }
@@ -11,6 +11,7 @@ import (
 
 	spanner "cloud.google.com/go/spanner"
 	spannerdb "cloud.google.com/go/spanner/admin/database/apiv1"
+	"cloud.google.com/go/spanner/admin/database/apiv1/databasepb"
 	"go.uber.org/zap"
 
 	"github.com/pkg/errors"
@@ -32,8 +33,6 @@ var (
 		"bytebase": true,
 	}
 
-	createBytebaseDatabaseStatement = `CREATE DATABASE bytebase`
-
 	dsnRegExp = regexp.MustCompile("projects/(?P<PROJECTGROUP>([a-z]|[-.:]|[0-9])+)/instances/(?P<INSTANCEGROUP>([a-z]|[-]|[0-9])+)/databases/(?P<DATABASEGROUP>([a-z]|[-]|[_]|[0-9])+)")
 
 	_ db.Driver = (*Driver)(nil)
@@ -49,6 +48,8 @@ type Driver struct {
 	connCtx  db.ConnectionContext
 	client   *spanner.Client
 	dbClient *spannerdb.DatabaseAdminClient
+	// clientOptions is used to connect to the other databases of the instance.
+	clientOptions []option.ClientOption
 }
 
 func newDriver(_ db.DriverConfig) db.Driver {
@@ -64,10 +65,11 @@ func (d *Driver) Open(ctx context.Context, _ db.Type, config db.ConnectionConfig
 	}
 	d.config = config
 	d.connCtx = connCtx
+	d.clientOptions = []option.ClientOption{option.WithCredentialsJSON([]byte(config.Password))}
 	if config.Database == "" {
 		// try to connect to bytebase
 		dsn := getDSN(d.config.Host, db.BytebaseDatabase)
-		client, err := spanner.NewClient(ctx, dsn, option.WithCredentialsJSON([]byte(config.Password)))
+		client, err := spanner.NewClient(ctx, dsn, d.clientOptions...)
 		if status.Code(err) == codes.NotFound {
 			log.Debug(`spanner driver: no database provided, try connecting to "bytebase" database which is not found`, zap.Error(err))
 		} else if err != nil {
@@ -77,14 +79,14 @@ func (d *Driver) Open(ctx context.Context, _ db.Type, config db.ConnectionConfig
 		}
 	} else {
 		dsn := getDSN(d.config.Host, d.config.Database)
-		client, err := spanner.NewClient(ctx, dsn, option.WithCredentialsJSON([]byte(config.Password)))
+		client, err := spanner.NewClient(ctx, dsn, d.clientOptions...)
 		if err != nil {
 			return nil, err
 		}
 		d.client = client
 	}
 
-	dbClient, err := spannerdb.NewDatabaseAdminClient(ctx, option.WithCredentialsJSON([]byte(config.Password)))
+	dbClient, err := spannerdb.NewDatabaseAdminClient(ctx, d.clientOptions...)
 	if err != nil {
 		return nil, err
 	}
@@ -133,9 +135,65 @@ func (*Driver) GetDBConnection(_ context.Context, _ string) (*sql.DB, error) {
 	panic("not implemented")
 }
 
-// Execute executes a SQL statement.
-func (*Driver) Execute(_ context.Context, _ string, _ bool) (int64, error) {
-	panic("not implemented")
+// Execute executes the SQL statements in the connected database.
+// The consecutive DDL statements are applied in one UpdateDatabaseDdl batch because each schema update is a long-running operation,
+// and the consecutive DML statements are executed in one read-write transaction.
+func (d *Driver) Execute(ctx context.Context, statement string, createDatabase bool) (int64, error) {
+	if createDatabase {
+		return 0, errors.New("creating database is not supported for Spanner")
+	}
+	if d.config.Database == "" || d.client == nil {
+		return 0, errors.New("the driver must connect to a database to execute statements")
+	}
+	var totalRowsAffected int64
+	for _, batch := range batchStatements(splitStatement(statement)) {
+		if batch.ddl {
+			if err := d.updateDDL(ctx, d.config.Database, batch.statements); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		rowsAffected, err := d.executeDML(ctx, batch.statements)
+		if err != nil {
+			return 0, err
+		}
+		totalRowsAffected += rowsAffected
+	}
+	return totalRowsAffected, nil
+}
+
+// updateDDL applies the DDL statements to the database and waits for the schema update to complete.
+func (d *Driver) updateDDL(ctx context.Context, database string, statements []string) error {
+	op, err := d.dbClient.UpdateDatabaseDdl(ctx, &databasepb.UpdateDatabaseDdlRequest{
+		Database:   getDSN(d.config.Host, database),
+		Statements: statements,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to update the schema of database %q", database)
+	}
+	if err := op.Wait(ctx); err != nil {
+		return errors.Wrapf(err, "failed to update the schema of database %q", database)
+	}
+	return nil
+}
+
+func (d *Driver) executeDML(ctx context.Context, statements []string) (int64, error) {
+	var totalRowsAffected int64
+	if _, err := d.client.ReadWriteTransaction(ctx, func(ctx context.Context, tx *spanner.ReadWriteTransaction) error {
+		// The transaction function may be retried, so the count is reset for each attempt.
+		totalRowsAffected = 0
+		for _, statement := range statements {
+			rowsAffected, err := tx.Update(ctx, spanner.NewStatement(statement))
+			if err != nil {
+				return errors.Wrapf(err, "failed to execute %q", statement)
+			}
+			totalRowsAffected += rowsAffected
+		}
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+	return totalRowsAffected, nil
 }
 
 // Query queries a SQL statement.
@@ -162,6 +220,43 @@ func getDatabaseFromDSN(dsn string) (string, error) {
 	return matches["DATABASEGROUP"], nil
 }
 
+// statementBatch is the consecutive statements of the same kind.
+type statementBatch struct {
+	ddl        bool
+	statements []string
+}
+
+// batchStatements groups the consecutive DDL statements and the consecutive DML statements.
+func batchStatements(statements []string) []statementBatch {
+	var batches []statementBatch
+	for _, statement := range statements {
+		ddl := isDDL(statement)
+		if len(batches) == 0 || batches[len(batches)-1].ddl != ddl {
+			batches = append(batches, statementBatch{ddl: ddl})
+		}
+		batches[len(batches)-1].statements = append(batches[len(batches)-1].statements, statement)
+	}
+	return batches
+}
+
+// isDDL returns true if the statement is a schema update statement such as CREATE TABLE or GRANT.
+func isDDL(statement string) bool {
+	for _, line := range strings.Split(statement, "\n") {
+		fields := strings.Fields(line)
+		// Skip the leading comments.
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "--") || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		switch strings.ToUpper(fields[0]) {
+		case "CREATE", "ALTER", "DROP", "ANALYZE", "GRANT", "REVOKE":
+			return true
+		default:
+			return false
+		}
+	}
+	return false
+}
+
 func splitStatement(statement string) []string {
 	var res []string
 	for _, s := range strings.Split(statement, ";") {
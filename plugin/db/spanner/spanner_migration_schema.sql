@@ -1,5 +1,5 @@
-CREATE TABLE migration_history (
-    id STRING(MAX) NOT NULL,
+CREATE TABLE bytebase_migration_history (
+    id INT64 NOT NULL,
     created_by STRING(MAX) NOT NULL,
     created_ts INT64 NOT NULL,
     updated_by STRING(MAX) NOT NULL,
@@ -7,7 +7,6 @@ CREATE TABLE migration_history (
     release_version STRING(MAX) NOT NULL,
     namespace STRING(MAX) NOT NULL,
     sequence INT64 NOT NULL,
-    CONSTRAINT sequence_is_non_negative CHECK (sequence >= 0),
     source STRING(MAX) NOT NULL,
     type STRING(MAX) NOT NULL,
     status STRING(MAX) NOT NULL,
@@ -21,10 +20,10 @@ CREATE TABLE migration_history (
     payload STRING(MAX) NOT NULL
 ) PRIMARY KEY(id);
 
-CREATE UNIQUE INDEX bytebase_idx_unique_migration_history_namespace_sequence ON migration_history (namespace, sequence);
+CREATE UNIQUE INDEX bytebase_idx_unique_migration_history_namespace_sequence ON bytebase_migration_history (namespace, sequence);
 
-CREATE UNIQUE INDEX bytebase_idx_unique_migration_history_namespace_version ON migration_history (namespace, version);
+CREATE UNIQUE INDEX bytebase_idx_unique_migration_history_namespace_version ON bytebase_migration_history (namespace, version);
 
-CREATE INDEX bytebase_idx_migration_history_namespace_source_type ON migration_history(namespace, source, type);
+CREATE INDEX bytebase_idx_migration_history_namespace_source_type ON bytebase_migration_history (namespace, source, type);
 
-CREATE INDEX bytebase_idx_migration_history_namespace_created ON migration_history(namespace, created_ts);
\ No newline at end of file
+CREATE INDEX bytebase_idx_migration_history_namespace_created ON bytebase_migration_history (namespace, created_ts);
@@ -35,3 +35,26 @@ func TestGetDatabaseFromDSN(t *testing.T) {
 		}
 	}
 }
+
+func TestBatchStatements(t *testing.T) {
+	a := require.New(t)
+	statements := splitStatement(`
+		CREATE TABLE t1 (id INT64) PRIMARY KEY (id);
+		-- Add a column.
+		ALTER TABLE t1 ADD COLUMN name STRING(MAX);
+		INSERT INTO t1 (id) VALUES (1);
+		update t1 SET name = 'a' WHERE id = 1;
+		DROP TABLE t2;
+	`)
+	a.Equal([]statementBatch{
+		{ddl: true, statements: []string{
+			"CREATE TABLE t1 (id INT64) PRIMARY KEY (id)",
+			"-- Add a column.\n\t\tALTER TABLE t1 ADD COLUMN name STRING(MAX)",
+		}},
+		{ddl: false, statements: []string{
+			"INSERT INTO t1 (id) VALUES (1)",
+			"update t1 SET name = 'a' WHERE id = 1",
+		}},
+		{ddl: true, statements: []string{"DROP TABLE t2"}},
+	}, batchStatements(statements))
+}
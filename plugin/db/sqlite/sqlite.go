@@ -22,7 +22,8 @@ import (
 var (
 	bytebaseDatabase = "bytebase"
 
-	_ db.Driver = (*Driver)(nil)
+	_ db.Driver        = (*Driver)(nil)
+	_ db.StreamQuerier = (*Driver)(nil)
 )
 
 func init() {
@@ -189,3 +190,8 @@ func (driver *Driver) Execute(ctx context.Context, statement string, _ bool) (in
 func (driver *Driver) Query(ctx context.Context, statement string, queryContext *db.QueryContext) ([]interface{}, error) {
 	return util.Query(ctx, db.SQLite, driver.db, statement, queryContext)
 }
+
+// QueryStream queries a SQL statement and passes the rows to the handler as they arrive.
+func (driver *Driver) QueryStream(ctx context.Context, statement string, queryContext *db.QueryContext, handler db.RowHandler) error {
+	return util.QueryStream(ctx, db.SQLite, driver.db, statement, queryContext, handler)
+}
@@ -0,0 +1,346 @@
+package db
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/bytebase/bytebase/common/log"
+)
+
+const (
+	defaultSSHPort = "22"
+	// sshTunnelIdleTimeout is the duration after which a tunnel without any connections is closed.
+	sshTunnelIdleTimeout = 10 * time.Minute
+	sshDialTimeout       = 10 * time.Second
+)
+
+var (
+	sshTunnelsMu sync.Mutex
+	sshTunnels   = map[sshTunnelKey]*sshTunnel{}
+	// sshTunnelReaperOnce starts the goroutine closing the idle tunnels.
+	sshTunnelReaperOnce sync.Once
+)
+
+// SSHConfig is the configuration for connecting the database through an SSH tunnel, such as a bastion host.
+type SSHConfig struct {
+	Host string
+	Port string
+	User string
+	// HostKey is the public key of the SSH server in the authorized_keys or known_hosts format, such as the output of ssh-keyscan.
+	// It's required to verify the SSH server, multiple keys are separated by the new lines.
+	HostKey string
+	// Password is used if the private key is empty.
+	Password string
+	// PrivateKey is the PEM encoded private key.
+	PrivateKey string
+}
+
+// sshTunnelKey identifies the tunnel shared by the connections to the same target through the same SSH server.
+type sshTunnelKey struct {
+	config SSHConfig
+	target string
+}
+
+// sshTunnel forwards the connections accepted by the local listener to the target through the SSH server.
+type sshTunnel struct {
+	key          sshTunnelKey
+	clientConfig *ssh.ClientConfig
+	listener     net.Listener
+
+	mu     sync.Mutex
+	client *ssh.Client
+	// refs is the number of the callers opening the tunnel and the connections forwarded through the tunnel.
+	// The tunnel is only closed without any reference.
+	refs     int
+	lastUsed time.Time
+}
+
+// openSSHTunnel returns the local address forwarding to the target host and port through the SSH server.
+// The tunnels are shared and kept open until idle, so the drivers don't need to close them.
+func openSSHTunnel(config SSHConfig, host, port string) (string, string, error) {
+	if port == "" {
+		return "", "", errors.New("the database port is required for connecting through the SSH tunnel")
+	}
+	key := sshTunnelKey{config: config, target: net.JoinHostPort(host, port)}
+
+	sshTunnelsMu.Lock()
+	sshTunnelReaperOnce.Do(func() {
+		go reapIdleSSHTunnels()
+	})
+	tunnel, ok := sshTunnels[key]
+	if !ok {
+		clientConfig, err := getSSHClientConfig(config)
+		if err != nil {
+			sshTunnelsMu.Unlock()
+			return "", "", err
+		}
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			sshTunnelsMu.Unlock()
+			return "", "", errors.Wrap(err, "failed to listen for the SSH tunnel")
+		}
+		tunnel = &sshTunnel{
+			key:          key,
+			clientConfig: clientConfig,
+			listener:     listener,
+			lastUsed:     time.Now(),
+		}
+		go tunnel.serve()
+		sshTunnels[key] = tunnel
+	}
+	// Hold the reference while connecting, so that the tunnel isn't closed by the reaper or the other callers.
+	tunnel.acquire()
+	sshTunnelsMu.Unlock()
+
+	// Connect to the SSH server eagerly, so that the SSH errors are reported instead of the broken database connections.
+	// The SSH server is dialed without the global lock, so that a slow SSH server doesn't block the other tunnels.
+	_, err := tunnel.connect()
+	// Releasing the reference also refreshes the last used time, so that the tunnel isn't closed before the driver connects.
+	tunnel.release()
+	if err != nil {
+		closeUnusedSSHTunnel(tunnel)
+		return "", "", err
+	}
+
+	localHost, localPort, err := net.SplitHostPort(tunnel.listener.Addr().String())
+	if err != nil {
+		return "", "", err
+	}
+	return localHost, localPort, nil
+}
+
+// closeUnusedSSHTunnel closes the tunnel if no one else is using it.
+func closeUnusedSSHTunnel(tunnel *sshTunnel) {
+	sshTunnelsMu.Lock()
+	defer sshTunnelsMu.Unlock()
+	if sshTunnels[tunnel.key] != tunnel || !tunnel.isUnused() {
+		return
+	}
+	tunnel.close()
+	delete(sshTunnels, tunnel.key)
+}
+
+func getSSHClientConfig(config SSHConfig) (*ssh.ClientConfig, error) {
+	hostKeyList, err := parseSSHHostKeys(config.HostKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(hostKeyList) == 0 {
+		return nil, errors.New("the SSH host key is required to verify the SSH server")
+	}
+	var hostKeyAlgorithmList []string
+	for _, hostKey := range hostKeyList {
+		// The RSA host key is used with the SHA-2 signature algorithms by the modern SSH servers.
+		if hostKey.Type() == ssh.KeyAlgoRSA {
+			hostKeyAlgorithmList = append(hostKeyAlgorithmList, ssh.KeyAlgoRSASHA512, ssh.KeyAlgoRSASHA256)
+		}
+		hostKeyAlgorithmList = append(hostKeyAlgorithmList, hostKey.Type())
+	}
+
+	var auth ssh.AuthMethod
+	if config.PrivateKey != "" {
+		signer, err := ssh.ParsePrivateKey([]byte(config.PrivateKey))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse the SSH private key")
+		}
+		auth = ssh.PublicKeys(signer)
+	} else {
+		auth = ssh.Password(config.Password)
+	}
+	return &ssh.ClientConfig{
+		User: config.User,
+		Auth: []ssh.AuthMethod{auth},
+		HostKeyCallback: func(hostname string, _ net.Addr, key ssh.PublicKey) error {
+			for _, hostKey := range hostKeyList {
+				if bytes.Equal(hostKey.Marshal(), key.Marshal()) {
+					return nil
+				}
+			}
+			return errors.Errorf("the host key %s of SSH server %q doesn't match the configured host key", ssh.FingerprintSHA256(key), hostname)
+		},
+		HostKeyAlgorithms: hostKeyAlgorithmList,
+		Timeout:           sshDialTimeout,
+	}, nil
+}
+
+// parseSSHHostKeys parses the public keys in the authorized_keys format, e.g. "ssh-ed25519 AAAA...",
+// or the known_hosts format, e.g. "bastion.example.com ssh-ed25519 AAAA...". The empty and comment lines are skipped.
+func parseSSHHostKeys(text string) ([]ssh.PublicKey, error) {
+	var res []ssh.PublicKey
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, _, _, _, err := ssh.ParseAuthorizedKey([]byte(line))
+		if err != nil {
+			_, _, key, _, _, err = ssh.ParseKnownHosts([]byte(line))
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to parse the SSH host key %q", line)
+			}
+		}
+		res = append(res, key)
+	}
+	return res, nil
+}
+
+// connect connects to the SSH server if not connected yet, and returns the client.
+// The SSH server is dialed without holding the tunnel lock, so that it never blocks the reaper.
+func (t *sshTunnel) connect() (*ssh.Client, error) {
+	t.mu.Lock()
+	client := t.client
+	t.mu.Unlock()
+	if client != nil {
+		return client, nil
+	}
+
+	port := t.key.config.Port
+	if port == "" {
+		port = defaultSSHPort
+	}
+	addr := net.JoinHostPort(t.key.config.Host, port)
+	client, err := ssh.Dial("tcp", addr, t.clientConfig)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to SSH server %q", addr)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	// Another connection has connected to the SSH server concurrently.
+	if t.client != nil {
+		client.Close()
+		return t.client, nil
+	}
+	t.client = client
+	return client, nil
+}
+
+// disconnect closes the broken client, unless it's replaced already.
+func (t *sshTunnel) disconnect(client *ssh.Client) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.client == client {
+		t.client = nil
+	}
+	client.Close()
+}
+
+// dial dials the target through the SSH server, and reconnects once if the SSH connection is broken.
+func (t *sshTunnel) dial() (net.Conn, error) {
+	client, err := t.connect()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := client.Dial("tcp", t.key.target)
+	if err == nil {
+		return conn, nil
+	}
+	t.disconnect(client)
+	client, err = t.connect()
+	if err != nil {
+		return nil, err
+	}
+	conn, err = client.Dial("tcp", t.key.target)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to dial %q through the SSH tunnel", t.key.target)
+	}
+	return conn, nil
+}
+
+func (t *sshTunnel) serve() {
+	for {
+		conn, err := t.listener.Accept()
+		if err != nil {
+			// The listener is closed.
+			return
+		}
+		go t.forward(conn)
+	}
+}
+
+func (t *sshTunnel) forward(local net.Conn) {
+	defer local.Close()
+	remote, err := t.dial()
+	if err != nil {
+		log.Warn("Failed to forward the connection through the SSH tunnel",
+			zap.String("ssh_host", t.key.config.Host),
+			zap.String("target", t.key.target),
+			zap.Error(err))
+		return
+	}
+	defer remote.Close()
+
+	t.acquire()
+	defer t.release()
+
+	done := make(chan struct{}, 2)
+	go func() {
+		_, _ = io.Copy(remote, local)
+		done <- struct{}{}
+	}()
+	go func() {
+		_, _ = io.Copy(local, remote)
+		done <- struct{}{}
+	}()
+	// Closing both connections after either direction finishes stops the other one.
+	<-done
+}
+
+func (t *sshTunnel) acquire() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.refs++
+	t.lastUsed = time.Now()
+}
+
+func (t *sshTunnel) release() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.refs--
+	t.lastUsed = time.Now()
+}
+
+func (t *sshTunnel) isUnused() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.refs == 0
+}
+
+func (t *sshTunnel) isIdle(now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.refs == 0 && now.Sub(t.lastUsed) > sshTunnelIdleTimeout
+}
+
+func (t *sshTunnel) close() {
+	t.listener.Close()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.client != nil {
+		t.client.Close()
+		t.client = nil
+	}
+}
+
+func reapIdleSSHTunnels() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		sshTunnelsMu.Lock()
+		for key, tunnel := range sshTunnels {
+			if tunnel.isIdle(now) {
+				tunnel.close()
+				delete(sshTunnels, key)
+			}
+		}
+		sshTunnelsMu.Unlock()
+	}
+}
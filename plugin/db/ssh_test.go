@@ -0,0 +1,142 @@
+package db
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestSSHTunnel(t *testing.T) {
+	a := require.New(t)
+
+	// The echo server is the database only reachable through the SSH server.
+	echoListener, err := net.Listen("tcp", "127.0.0.1:0")
+	a.NoError(err)
+	defer echoListener.Close()
+	go func() {
+		for {
+			conn, err := echoListener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	sshListener, err := net.Listen("tcp", "127.0.0.1:0")
+	a.NoError(err)
+	defer sshListener.Close()
+	hostKey := serveSSH(t, sshListener, "bastion", "secret")
+	sshHost, sshPort, err := net.SplitHostPort(sshListener.Addr().String())
+	a.NoError(err)
+	dbHost, dbPort, err := net.SplitHostPort(echoListener.Addr().String())
+	a.NoError(err)
+
+	_, _, err = openSSHTunnel(SSHConfig{Host: sshHost, Port: sshPort, User: "bastion", HostKey: hostKey, Password: "wrong"}, dbHost, dbPort)
+	a.Error(err)
+	// The SSH server is verified by the host key.
+	_, _, err = openSSHTunnel(SSHConfig{Host: sshHost, Port: sshPort, User: "bastion", Password: "secret"}, dbHost, dbPort)
+	a.ErrorContains(err, "host key is required")
+	_, otherPrivateKey, err := ed25519.GenerateKey(rand.Reader)
+	a.NoError(err)
+	otherSigner, err := ssh.NewSignerFromKey(otherPrivateKey)
+	a.NoError(err)
+	otherHostKey := fmt.Sprintf("%s %s", sshHost, ssh.MarshalAuthorizedKey(otherSigner.PublicKey()))
+	_, _, err = openSSHTunnel(SSHConfig{Host: sshHost, Port: sshPort, User: "bastion", HostKey: otherHostKey, Password: "secret"}, dbHost, dbPort)
+	a.ErrorContains(err, "doesn't match the configured host key")
+
+	// The known_hosts format is supported as well.
+	config := SSHConfig{Host: sshHost, Port: sshPort, User: "bastion", HostKey: otherHostKey + sshHost + " " + hostKey, Password: "secret"}
+	host, port, err := openSSHTunnel(config, dbHost, dbPort)
+	a.NoError(err)
+	a.Equal("127.0.0.1", host)
+	// The tunnel is shared.
+	_, port2, err := openSSHTunnel(config, dbHost, dbPort)
+	a.NoError(err)
+	a.Equal(port, port2)
+
+	conn, err := net.Dial("tcp", net.JoinHostPort(host, port))
+	a.NoError(err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("hello"))
+	a.NoError(err)
+	buf := make([]byte, 5)
+	_, err = io.ReadFull(conn, buf)
+	a.NoError(err)
+	a.Equal("hello", string(buf))
+}
+
+// serveSSH serves an SSH server with the password authentication, which only supports the direct-tcpip channel.
+// It returns the host key of the SSH server in the authorized_keys format.
+func serveSSH(t *testing.T, listener net.Listener, user, password string) string {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(privateKey)
+	require.NoError(t, err)
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(conn ssh.ConnMetadata, p []byte) (*ssh.Permissions, error) {
+			if conn.User() == user && string(p) == password {
+				return nil, nil
+			}
+			return nil, errors.New("invalid password")
+		},
+	}
+	config.AddHostKey(signer)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_, chans, reqs, err := ssh.NewServerConn(conn, config)
+				if err != nil {
+					return
+				}
+				go ssh.DiscardRequests(reqs)
+				for newChannel := range chans {
+					if newChannel.ChannelType() != "direct-tcpip" {
+						_ = newChannel.Reject(ssh.UnknownChannelType, "unsupported channel type")
+						continue
+					}
+					// The payload is the target host, port, originator host and port defined in RFC 4254 7.2.
+					data := newChannel.ExtraData()
+					hostLen := binary.BigEndian.Uint32(data)
+					target := net.JoinHostPort(string(data[4:4+hostLen]), fmt.Sprintf("%d", binary.BigEndian.Uint32(data[4+hostLen:])))
+					remote, err := net.Dial("tcp", target)
+					if err != nil {
+						_ = newChannel.Reject(ssh.ConnectionFailed, err.Error())
+						continue
+					}
+					channel, requests, err := newChannel.Accept()
+					if err != nil {
+						remote.Close()
+						continue
+					}
+					go ssh.DiscardRequests(requests)
+					go func() {
+						defer channel.Close()
+						defer remote.Close()
+						go func() {
+							_, _ = io.Copy(remote, channel)
+						}()
+						_, _ = io.Copy(channel, remote)
+					}()
+				}
+			}()
+		}
+	}()
+	return string(ssh.MarshalAuthorizedKey(signer.PublicKey()))
+}
@@ -6,6 +6,7 @@ import (
 	"bytes"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"io"
 	"regexp"
@@ -196,8 +197,8 @@ func ExecuteMigration(ctx context.Context, executor MigrationExecutor, m *db.Mig
 	}
 	if doMigrate {
 		// Switch to the target database only if we're NOT creating this target database.
-		// We should not call GetDBConnection() if the instance is MongoDB because it doesn't support.
-		if !m.CreateDatabase && executor.GetType() != db.MongoDB {
+		// We should not call GetDBConnection() if the instance is MongoDB or Spanner because they don't support.
+		if !m.CreateDatabase && supportsSQLConnection(executor.GetType()) {
 			if _, err := executor.GetDBConnection(ctx, m.Database); err != nil {
 				return -1, "", err
 			}
@@ -266,7 +267,8 @@ func BeginMigration(ctx context.Context, executor MigrationExecutor, m *db.Migra
 	// 1. MongoDB support transaction only in replica set mode or shard cluster mode. But we need to
 	// support standalone mode as well. https://stackoverflow.com/a/51462024/19075342
 	// 2. We use mongodb driver, it has not implemented the SQL interface. So we can't use the same code.
-	if executor.GetType() != db.MongoDB {
+	// Spanner doesn't implement the SQL interface either, and its driver uses read-write transactions by itself.
+	if supportsSQLConnection(executor.GetType()) {
 		// We use transaction here for the RDBMS.
 		sqldb, err := executor.GetDBConnection(ctx, databaseName)
 		if err != nil {
@@ -302,7 +304,7 @@ func BeginMigration(ctx context.Context, executor MigrationExecutor, m *db.Migra
 		return -1, err
 	}
 
-	if executor.GetType() != db.MongoDB {
+	if supportsSQLConnection(executor.GetType()) {
 		if err := tx.Commit(); err != nil {
 			return -1, err
 		}
@@ -320,7 +322,8 @@ func EndMigration(ctx context.Context, executor MigrationExecutor, startedNs int
 	// 1. MongoDB support transaction only in replica set mode or shard cluster mode. But we need to
 	// support standalone mode as well. https://stackoverflow.com/a/51462024/19075342
 	// 2. We use mongodb driver, it had not implment the sql interface. So we can't use the same code.
-	if executor.GetType() != db.MongoDB {
+	// Spanner doesn't implement the SQL interface either, and its driver uses read-write transactions by itself.
+	if supportsSQLConnection(executor.GetType()) {
 		sqldb, err := executor.GetDBConnection(ctx, databaseName)
 		if err != nil {
 			return err
@@ -343,24 +346,50 @@ func EndMigration(ctx context.Context, executor MigrationExecutor, startedNs int
 	if err != nil {
 		return err
 	}
-	if executor.GetType() != db.MongoDB {
+	if supportsSQLConnection(executor.GetType()) {
 		return tx.Commit()
 	}
 	return nil
 }
 
+// supportsSQLConnection returns true if the driver of the database type implements GetDBConnection.
+func supportsSQLConnection(dbType db.Type) bool {
+	return dbType != db.MongoDB && dbType != db.Spanner
+}
+
 // Query will execute a readonly / SELECT query.
 func Query(ctx context.Context, dbType db.Type, sqldb *sql.DB, statement string, queryContext *db.QueryContext) ([]interface{}, error) {
+	collector := &RowCollector{}
+	if err := QueryStream(ctx, dbType, sqldb, statement, queryContext, collector); err != nil {
+		return nil, err
+	}
+	return collector.Result(), nil
+}
+
+// QueryStream will execute a readonly / SELECT query and pass the rows to the handler as they arrive.
+func QueryStream(ctx context.Context, dbType db.Type, sqldb *sql.DB, statement string, queryContext *db.QueryContext, handler db.RowHandler) error {
+	if queryContext.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, queryContext.Timeout)
+		defer cancel()
+	}
 	readOnly := queryContext.ReadOnly
 	limit := queryContext.Limit
 	if !readOnly {
-		return queryAdmin(ctx, sqldb, statement, limit)
+		return queryAdmin(ctx, dbType, sqldb, statement, queryContext, handler)
 	}
 	// Limit SQL query result size.
-	if dbType == db.MySQL {
-		// MySQL 5.7 doesn't support WITH clause.
+	switch dbType {
+	case db.MySQL, db.MariaDB, db.OceanBase:
+		// MySQL 5.7 and MariaDB 10.1 don't support WITH clause.
 		statement = getMySQLStatementWithResultLimit(statement, limit)
-	} else {
+	case db.MSSQL:
+		// MSSQL doesn't support LIMIT clause, and wrapping the query in TOP breaks the valid queries with ORDER BY,
+		// unnamed columns or common table expressions, so the rows are capped by readRows instead.
+	case db.Oracle:
+		// Oracle doesn't support LIMIT clause, and it rejects the trailing semicolon.
+		statement = getOracleStatementWithResultLimit(statement, limit)
+	default:
 		statement = getStatementWithResultLimit(statement, limit)
 	}
 
@@ -369,127 +398,137 @@ func Query(ctx context.Context, dbType db.Type, sqldb *sql.DB, statement string,
 	// Clickhouse doesn't support READ ONLY transactions (Error: sql: driver does not support read-only transactions).
 	// Snowflake doesn't support READ ONLY transactions.
 	// https://github.com/snowflakedb/gosnowflake/blob/0450f0b16a4679b216baecd3fd6cdce739dbb683/connection.go#L166
-	if dbType == db.TiDB || dbType == db.ClickHouse || dbType == db.Snowflake {
+	// MSSQL doesn't support READ ONLY transactions (Error: read-only transactions are not supported).
+	// Oracle doesn't support READ ONLY transactions (Error: readonly transaction is not supported).
+	if dbType == db.TiDB || dbType == db.ClickHouse || dbType == db.Snowflake || dbType == db.MSSQL || dbType == db.Oracle {
 		readOnly = false
 	}
 	tx, err := sqldb.BeginTx(ctx, &sql.TxOptions{ReadOnly: readOnly})
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer tx.Rollback()
 
+	release, err := handleSession(ctx, dbType, tx, queryContext)
+	if err != nil {
+		return err
+	}
+	// The session is released before the transaction is rolled back and the connection returns to the pool.
+	defer release()
+
+	var searchPath []string
+	if dbType == db.Postgres && queryContext.SensitiveSchemaInfo != nil {
+		if searchPath, err = getPGSearchPath(ctx, tx); err != nil {
+			return err
+		}
+	}
+
 	rows, err := tx.QueryContext(ctx, statement)
 	if err != nil {
-		return nil, FormatErrorWithQuery(err, statement)
+		return FormatErrorWithQuery(err, statement)
 	}
 	defer rows.Close()
 
 	columnNames, err := rows.Columns()
 	if err != nil {
-		return nil, FormatError(err)
+		return FormatError(err)
 	}
 
-	fieldList, err := extractSensitiveField(dbType, statement, queryContext.CurrentDatabase, queryContext.SensitiveSchemaInfo)
+	fieldList, err := extractSensitiveField(dbType, statement, queryContext.CurrentDatabase, searchPath, queryContext.SensitiveSchemaInfo)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	if len(fieldList) != 0 && len(fieldList) != len(columnNames) {
-		return nil, errors.Errorf("failed to extract sensitive fields: %q", statement)
+		return errors.Errorf("failed to extract sensitive fields: %q", statement)
 	}
 
-	columnTypes, err := rows.ColumnTypes()
-	if err != nil {
-		return nil, FormatError(err)
+	rowLimit := 0
+	if dbType == db.MSSQL {
+		rowLimit = limit
 	}
+	return readRows(rows, fieldList, NewSensitiveDataMasker(queryContext), handler, rowLimit)
+}
 
-	colCount := len(columnTypes)
+// query will execute a query.
+func queryAdmin(ctx context.Context, dbType db.Type, sqldb *sql.DB, statement string, queryContext *db.QueryContext, handler db.RowHandler) error {
+	// Use a dedicated connection so that the session is the one running the query.
+	conn, err := sqldb.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
 
-	var columnTypeNames []string
-	for _, v := range columnTypes {
-		// DatabaseTypeName returns the database system name of the column type.
-		// refer: https://pkg.go.dev/database/sql#ColumnType.DatabaseTypeName
-		columnTypeNames = append(columnTypeNames, strings.ToUpper(v.DatabaseTypeName()))
+	release, err := handleSession(ctx, dbType, conn, queryContext)
+	if err != nil {
+		return err
 	}
+	// The session is released before the connection returns to the pool.
+	defer release()
 
-	data := []interface{}{}
-	for rows.Next() {
-		scanArgs := make([]interface{}, colCount)
-		for i, v := range columnTypeNames {
-			// TODO(steven need help): Consult a common list of data types from database driver documentation. e.g. MySQL,PostgreSQL.
-			switch v {
-			case "VARCHAR", "TEXT", "UUID", "TIMESTAMP":
-				scanArgs[i] = new(sql.NullString)
-			case "BOOL":
-				scanArgs[i] = new(sql.NullBool)
-			case "INT", "INTEGER":
-				scanArgs[i] = new(sql.NullInt64)
-			case "FLOAT":
-				scanArgs[i] = new(sql.NullFloat64)
-			default:
-				scanArgs[i] = new(sql.NullString)
-			}
-		}
+	rows, err := conn.QueryContext(ctx, statement)
+	if err != nil {
+		return FormatErrorWithQuery(err, statement)
+	}
+	defer rows.Close()
 
-		if err := rows.Scan(scanArgs...); err != nil {
-			return nil, FormatError(err)
-		}
+	return readRows(rows, nil, nil, handler, 0)
+}
 
-		rowData := []interface{}{}
-		for i := range columnTypes {
-			if len(fieldList) > 0 && fieldList[i].Sensitive {
-				rowData = append(rowData, "******")
-				continue
-			}
-			if v, ok := (scanArgs[i]).(*sql.NullBool); ok && v.Valid {
-				rowData = append(rowData, v.Bool)
-				continue
-			}
-			if v, ok := (scanArgs[i]).(*sql.NullString); ok && v.Valid {
-				rowData = append(rowData, v.String)
-				continue
-			}
-			if v, ok := (scanArgs[i]).(*sql.NullInt64); ok && v.Valid {
-				rowData = append(rowData, v.Int64)
-				continue
-			}
-			if v, ok := (scanArgs[i]).(*sql.NullInt32); ok && v.Valid {
-				rowData = append(rowData, v.Int32)
-				continue
-			}
-			if v, ok := (scanArgs[i]).(*sql.NullFloat64); ok && v.Valid {
-				rowData = append(rowData, v.Float64)
-				continue
-			}
-			// If none of them match, set nil to its value.
-			rowData = append(rowData, nil)
-		}
+// rowQuerier is the interface shared by *sql.Tx and *sql.Conn to query a row.
+type rowQuerier interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
 
-		data = append(data, rowData)
+// handleSession passes the ID of the session running the query to the session handler, and returns the function releasing the session.
+// It's a no-op if there is no session handler or the engine cannot get the session ID by SQL.
+func handleSession(ctx context.Context, dbType db.Type, querier rowQuerier, queryContext *db.QueryContext) (func(), error) {
+	noop := func() {}
+	if queryContext.SessionHandler == nil {
+		return noop, nil
+	}
+	var query string
+	switch dbType {
+	case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
+		query = "SELECT CONNECTION_ID()"
+	case db.Postgres:
+		query = "SELECT pg_backend_pid()"
+	default:
+		return noop, nil
 	}
-	if err := rows.Err(); err != nil {
-		return nil, err
+	var sessionID string
+	if err := querier.QueryRowContext(ctx, query).Scan(&sessionID); err != nil {
+		return nil, FormatErrorWithQuery(err, query)
 	}
-
-	return []interface{}{columnNames, columnTypeNames, data}, nil
+	return queryContext.SessionHandler(sessionID), nil
 }
 
-// query will execute a query.
-func queryAdmin(ctx context.Context, sqldb *sql.DB, statement string, _ int) ([]interface{}, error) {
-	rows, err := sqldb.QueryContext(ctx, statement)
-	if err != nil {
-		return nil, FormatErrorWithQuery(err, statement)
+// getPGSearchPath returns the existing schemas in the search path of the session, which resolve the unqualified table names.
+// The implicit pg_catalog and temporary schemas are excluded, they don't have the sensitive tables.
+func getPGSearchPath(ctx context.Context, querier rowQuerier) ([]string, error) {
+	query := "SELECT array_to_json(current_schemas(false))::text"
+	var value string
+	if err := querier.QueryRowContext(ctx, query).Scan(&value); err != nil {
+		return nil, FormatErrorWithQuery(err, query)
 	}
-	defer rows.Close()
+	searchPath := []string{}
+	if err := json.Unmarshal([]byte(value), &searchPath); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the search path %q", value)
+	}
+	return searchPath, nil
+}
 
+// readRows reads the rows and passes them to the handler, the sensitive fields are masked by the masker.
+// It stops after reading limit rows if limit is positive.
+func readRows(rows *sql.Rows, fieldList []db.SensitiveField, masker *SensitiveDataMasker, handler db.RowHandler, limit int) error {
 	columnNames, err := rows.Columns()
 	if err != nil {
-		return nil, FormatError(err)
+		return FormatError(err)
 	}
 
 	columnTypes, err := rows.ColumnTypes()
 	if err != nil {
-		return nil, FormatError(err)
+		return FormatError(err)
 	}
 
 	colCount := len(columnTypes)
@@ -500,10 +539,17 @@ func queryAdmin(ctx context.Context, sqldb *sql.DB, statement string, _ int) ([]
 		// refer: https://pkg.go.dev/database/sql#ColumnType.DatabaseTypeName
 		columnTypeNames = append(columnTypeNames, strings.ToUpper(v.DatabaseTypeName()))
 	}
+	if err := handler.HandleColumns(columnNames, columnTypeNames); err != nil {
+		return err
+	}
 
-	// TODO(d): share the common code.
-	data := []interface{}{}
+	rowCount := 0
 	for rows.Next() {
+		if limit > 0 && rowCount >= limit {
+			// Closing the rows cancels the rest of the query.
+			break
+		}
+		rowCount++
 		scanArgs := make([]interface{}, colCount)
 		for i, v := range columnTypeNames {
 			// TODO(steven need help): Consult a common list of data types from database driver documentation. e.g. MySQL,PostgreSQL.
@@ -522,42 +568,71 @@ func queryAdmin(ctx context.Context, sqldb *sql.DB, statement string, _ int) ([]
 		}
 
 		if err := rows.Scan(scanArgs...); err != nil {
-			return nil, FormatError(err)
+			return FormatError(err)
 		}
 
 		rowData := []interface{}{}
 		for i := range columnTypes {
-			if v, ok := (scanArgs[i]).(*sql.NullBool); ok && v.Valid {
-				rowData = append(rowData, v.Bool)
-				continue
-			}
-			if v, ok := (scanArgs[i]).(*sql.NullString); ok && v.Valid {
-				rowData = append(rowData, v.String)
-				continue
-			}
-			if v, ok := (scanArgs[i]).(*sql.NullInt64); ok && v.Valid {
-				rowData = append(rowData, v.Int64)
-				continue
-			}
-			if v, ok := (scanArgs[i]).(*sql.NullInt32); ok && v.Valid {
-				rowData = append(rowData, v.Int32)
-				continue
-			}
-			if v, ok := (scanArgs[i]).(*sql.NullFloat64); ok && v.Valid {
-				rowData = append(rowData, v.Float64)
-				continue
+			value := getScannedValue(scanArgs[i])
+			if len(fieldList) > 0 && fieldList[i].Sensitive {
+				value = masker.Mask(value, fieldList[i].MaskAlgorithm)
 			}
-			// If none of them match, set nil to its value.
-			rowData = append(rowData, nil)
+			rowData = append(rowData, value)
 		}
 
-		data = append(data, rowData)
+		if err := handler.HandleRow(rowData); err != nil {
+			return err
+		}
 	}
-	if err := rows.Err(); err != nil {
-		return nil, err
+	return rows.Err()
+}
+
+func getScannedValue(scanArg interface{}) interface{} {
+	if v, ok := scanArg.(*sql.NullBool); ok && v.Valid {
+		return v.Bool
 	}
+	if v, ok := scanArg.(*sql.NullString); ok && v.Valid {
+		return v.String
+	}
+	if v, ok := scanArg.(*sql.NullInt64); ok && v.Valid {
+		return v.Int64
+	}
+	if v, ok := scanArg.(*sql.NullInt32); ok && v.Valid {
+		return v.Int32
+	}
+	if v, ok := scanArg.(*sql.NullFloat64); ok && v.Valid {
+		return v.Float64
+	}
+	// If none of them match, set nil to its value.
+	return nil
+}
+
+// RowCollector is the row handler collecting all rows in memory.
+type RowCollector struct {
+	columnNames     []string
+	columnTypeNames []string
+	data            []interface{}
+}
+
+// HandleColumns implements the db.RowHandler interface.
+func (c *RowCollector) HandleColumns(names []string, types []string) error {
+	c.columnNames, c.columnTypeNames = names, types
+	c.data = []interface{}{}
+	return nil
+}
+
+// HandleRow implements the db.RowHandler interface.
+func (c *RowCollector) HandleRow(row []interface{}) error {
+	c.data = append(c.data, row)
+	return nil
+}
 
-	return []interface{}{columnNames, columnTypeNames, data}, nil
+// Result returns the collected result in the format of Driver.Query, which is nil if no columns are handled.
+func (c *RowCollector) Result() []interface{} {
+	if c.data == nil {
+		return nil
+	}
+	return []interface{}{c.columnNames, c.columnTypeNames, c.data}
 }
 
 func getStatementWithResultLimit(stmt string, limit int) string {
@@ -584,6 +659,14 @@ func getMySQLStatementWithResultLimit(stmt string, limit int) string {
 	return stmt
 }
 
+func getOracleStatementWithResultLimit(stmt string, limit int) string {
+	stmt = strings.TrimRight(stmt, " \n\t;")
+	if limit > 0 {
+		return fmt.Sprintf("SELECT * FROM (%s) WHERE ROWNUM <= %d", stmt, limit)
+	}
+	return stmt
+}
+
 // FindMigrationHistoryList will find the list of migration history.
 func FindMigrationHistoryList(ctx context.Context, findMigrationHistoryListQuery string, queryParams []interface{}, driver db.Driver, database string) ([]*db.MigrationHistory, error) {
 	// To support `pg` option, the util layer will not know which database where `migration_history` table is,
@@ -180,6 +180,37 @@ func TestGetMySQLStatementWithResultLimit(t *testing.T) {
 	}
 }
 
+func TestGetOracleStatementWithResultLimit(t *testing.T) {
+	tests := []struct {
+		sqlStatement string
+		limit        int
+		want         string
+	}{
+		{
+			sqlStatement: "  seLeCT * FROM test;",
+			limit:        123,
+			want:         "SELECT * FROM (  seLeCT * FROM test) WHERE ROWNUM <= 123",
+		},
+		{
+			sqlStatement: "  seLeCT * FROM test;",
+			limit:        0,
+			want:         "  seLeCT * FROM test",
+		},
+		{
+			sqlStatement: "SELECT\n*\nFROM\n\"test;\"  ;;;\n",
+			limit:        100,
+			want:         "SELECT * FROM (SELECT\n*\nFROM\n\"test;\") WHERE ROWNUM <= 100",
+		},
+	}
+
+	for _, test := range tests {
+		got := getOracleStatementWithResultLimit(test.sqlStatement, test.limit)
+		if got != test.want {
+			t.Errorf("trimSQLStatement %q: got result %v, want %v.", test.sqlStatement, got, test.want)
+		}
+	}
+}
+
 func TestApplyMultiStatements(t *testing.T) {
 	type testData struct {
 		statement string
@@ -708,8 +739,329 @@ func TestExtractSensitiveField(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		res, err := extractSensitiveField(db.MySQL, test.statement, defaultDatabase, test.schemaInfo)
+		res, err := extractSensitiveField(db.MySQL, test.statement, defaultDatabase, nil, test.schemaInfo)
 		require.NoError(t, err)
 		require.Equal(t, test.fieldList, res, test.statement)
 	}
 }
+
+func TestExtractPostgreSQLSensitiveField(t *testing.T) {
+	const (
+		defaultDatabase = "db"
+	)
+	var (
+		defaultSearchPath     = []string{"public"}
+		defaultDatabaseSchema = &db.SensitiveSchemaInfo{
+			DatabaseList: []db.DatabaseSchema{
+				{
+					Name: defaultDatabase,
+					SchemaList: []db.SchemaSchema{
+						{
+							Name: "public",
+							TableList: []db.TableSchema{
+								{
+									Name: "t",
+									ColumnList: []db.ColumnInfo{
+										{Name: "a", Sensitive: true},
+										{Name: "b", Sensitive: false},
+										{Name: "c", Sensitive: false},
+										{Name: "d", Sensitive: true},
+									},
+								},
+								{
+									Name: "t2",
+									ColumnList: []db.ColumnInfo{
+										{Name: "a", Sensitive: false},
+										{Name: "e", Sensitive: false},
+									},
+								},
+							},
+						},
+						{
+							Name: "s",
+							TableList: []db.TableSchema{
+								{
+									Name: "t",
+									ColumnList: []db.ColumnInfo{
+										{Name: "a", Sensitive: false},
+										{Name: "b", Sensitive: true},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	)
+	tests := []struct {
+		statement string
+		fieldList []db.SensitiveField
+	}{
+		{
+			// The statement rewritten with the result limit.
+			statement: `WITH result AS (SELECT * FROM t) SELECT * FROM result LIMIT 10000;`,
+			fieldList: []db.SensitiveField{
+				{Name: "a", Sensitive: true},
+				{Name: "b", Sensitive: false},
+				{Name: "c", Sensitive: false},
+				{Name: "d", Sensitive: true},
+			},
+		},
+		{
+			// Test for schema-qualified names.
+			statement: `SELECT s.t.a, t.b, x.* FROM s.t, s.t AS x`,
+			fieldList: []db.SensitiveField{
+				{Name: "a", Sensitive: false},
+				{Name: "b", Sensitive: true},
+				{Name: "a", Sensitive: false},
+				{Name: "b", Sensitive: true},
+			},
+		},
+		{
+			// Test for expressions, functions and aliases.
+			statement: `SELECT a + 1 AS x, max(b), c::text, lower(d || c), CASE WHEN b > 0 THEN a ELSE c END, 1 FROM public.t GROUP BY a, b, c, d`,
+			fieldList: []db.SensitiveField{
+				{Name: "x", Sensitive: true},
+				{Name: "max", Sensitive: false},
+				{Name: "c", Sensitive: false},
+				{Name: "lower", Sensitive: true},
+				{Name: "case", Sensitive: true},
+				{Name: "?column?", Sensitive: false},
+			},
+		},
+		{
+			// Test for the subquery in the FROM clause with the column aliases.
+			statement: `SELECT * FROM (SELECT a, b FROM t) AS sub(x, y) WHERE x > 0`,
+			fieldList: []db.SensitiveField{
+				{Name: "x", Sensitive: true},
+				{Name: "y", Sensitive: false},
+			},
+		},
+		{
+			// Test for the correlated subquery in the target list.
+			statement: `SELECT e, (SELECT d FROM t WHERE t.b = t2.e LIMIT 1), EXISTS (SELECT 1 FROM t WHERE t.a = t2.a) FROM t2`,
+			fieldList: []db.SensitiveField{
+				{Name: "e", Sensitive: false},
+				{Name: "d", Sensitive: true},
+				{Name: "exists", Sensitive: false},
+			},
+		},
+		{
+			// Test for JOIN USING, the merged column comes first and the qualified wildcard keeps the original columns.
+			statement: `SELECT *, t2.* FROM t2 JOIN t USING (a)`,
+			fieldList: []db.SensitiveField{
+				{Name: "a", Sensitive: true},
+				{Name: "e", Sensitive: false},
+				{Name: "b", Sensitive: false},
+				{Name: "c", Sensitive: false},
+				{Name: "d", Sensitive: true},
+				{Name: "a", Sensitive: false},
+				{Name: "e", Sensitive: false},
+			},
+		},
+		{
+			// Test for NATURAL JOIN and the JOIN alias.
+			statement: `SELECT j.* FROM (t NATURAL JOIN t2) AS j`,
+			fieldList: []db.SensitiveField{
+				{Name: "a", Sensitive: true},
+				{Name: "b", Sensitive: false},
+				{Name: "c", Sensitive: false},
+				{Name: "d", Sensitive: true},
+				{Name: "e", Sensitive: false},
+			},
+		},
+		{
+			// Test for the LATERAL subquery.
+			statement: `SELECT x.* FROM t2, LATERAL (SELECT t.d FROM t WHERE t.b = t2.e) AS x`,
+			fieldList: []db.SensitiveField{
+				{Name: "d", Sensitive: true},
+			},
+		},
+		{
+			// Test for set operations.
+			statement: `SELECT b, e FROM t, t2 UNION SELECT a, c FROM t EXCEPT SELECT 1, 2`,
+			fieldList: []db.SensitiveField{
+				{Name: "b", Sensitive: true},
+				{Name: "e", Sensitive: false},
+			},
+		},
+		{
+			// Test for CTE with the column aliases, which hides the table with the same name.
+			statement: `WITH t2(x, y) AS (SELECT d, b FROM t), t AS (SELECT y FROM t2) SELECT * FROM t, t2`,
+			fieldList: []db.SensitiveField{
+				{Name: "y", Sensitive: false},
+				{Name: "x", Sensitive: true},
+				{Name: "y", Sensitive: false},
+			},
+		},
+		{
+			// Test for recursive CTE, the sensitive field flows into the other column in the recursive part.
+			statement: `WITH RECURSIVE r(x, y, z) AS (SELECT 1, b, c FROM t UNION ALL SELECT x + 1, z, a FROM r, t WHERE x < 3) SELECT * FROM r`,
+			fieldList: []db.SensitiveField{
+				{Name: "x", Sensitive: false},
+				{Name: "y", Sensitive: true},
+				{Name: "z", Sensitive: true},
+			},
+		},
+		{
+			// Test for the whole-row reference and VALUES.
+			statement: `SELECT t, v.* FROM t, (VALUES (1, 'a')) AS v`,
+			fieldList: []db.SensitiveField{
+				{Name: "t", Sensitive: true},
+				{Name: "column1", Sensitive: false},
+				{Name: "column2", Sensitive: false},
+			},
+		},
+		{
+			// Test for the query without sensitive tables.
+			statement: `SELECT * FROM pg_catalog.pg_tables`,
+			fieldList: nil,
+		},
+		{
+			// Test for the query without sensitive tables.
+			statement: `SELECT * FROM t2`,
+			fieldList: nil,
+		},
+		{
+			statement: `EXPLAIN SELECT * FROM t`,
+			fieldList: nil,
+		},
+	}
+
+	for _, test := range tests {
+		res, err := extractSensitiveField(db.Postgres, test.statement, defaultDatabase, defaultSearchPath, defaultDatabaseSchema)
+		require.NoError(t, err, test.statement)
+		require.Equal(t, test.fieldList, res, test.statement)
+	}
+
+	for _, statement := range []string{
+		// The unknown table may contain sensitive data.
+		`SELECT * FROM t, unknown_table`,
+		`SELECT a FROM t UNION SELECT a, b FROM t`,
+		`INSERT INTO t SELECT * FROM t`,
+	} {
+		_, err := extractSensitiveField(db.Postgres, statement, defaultDatabase, defaultSearchPath, defaultDatabaseSchema)
+		require.Error(t, err, statement)
+	}
+
+	searchPathTests := []struct {
+		searchPath []string
+		statement  string
+		fieldList  []db.SensitiveField
+	}{
+		{
+			// The unqualified table is resolved by the search path.
+			searchPath: []string{"s", "public"},
+			statement:  `SELECT * FROM t`,
+			fieldList: []db.SensitiveField{
+				{Name: "a", Sensitive: false},
+				{Name: "b", Sensitive: true},
+			},
+		},
+		{
+			// The column is sensitive in any schema if the search path is unknown.
+			searchPath: nil,
+			statement:  `SELECT * FROM t`,
+			fieldList: []db.SensitiveField{
+				{Name: "a", Sensitive: true},
+				{Name: "b", Sensitive: true},
+				{Name: "c", Sensitive: false},
+				{Name: "d", Sensitive: true},
+			},
+		},
+		{
+			// The schema-qualified column matches the table whose schema is unknown.
+			searchPath: nil,
+			statement:  `SELECT public.t.b, s.t.c FROM t`,
+			fieldList: []db.SensitiveField{
+				{Name: "b", Sensitive: true},
+				{Name: "c", Sensitive: false},
+			},
+		},
+	}
+	for _, test := range searchPathTests {
+		res, err := extractSensitiveField(db.Postgres, test.statement, defaultDatabase, test.searchPath, defaultDatabaseSchema)
+		require.NoError(t, err, test.statement)
+		require.Equal(t, test.fieldList, res, test.statement)
+	}
+}
+
+func TestExtractMaskAlgorithm(t *testing.T) {
+	const (
+		defaultDatabase = "db"
+	)
+	var (
+		partial = &db.MaskAlgorithm{Type: db.SensitiveDataMaskTypePartial, PrefixLength: 1}
+		email   = &db.MaskAlgorithm{Type: db.SensitiveDataMaskTypeEmail}
+		columns = []db.ColumnInfo{
+			{Name: "name", Sensitive: true, MaskAlgorithm: partial},
+			{Name: "email", Sensitive: true, MaskAlgorithm: email},
+			{Name: "note", Sensitive: true},
+			{Name: "id", Sensitive: false},
+		}
+		schemaInfo = &db.SensitiveSchemaInfo{
+			DatabaseList: []db.DatabaseSchema{
+				{
+					Name:       defaultDatabase,
+					TableList:  []db.TableSchema{{Name: "t", ColumnList: columns}},
+					SchemaList: []db.SchemaSchema{{Name: "public", TableList: []db.TableSchema{{Name: "t", ColumnList: columns}}}},
+				},
+			},
+		}
+	)
+	tests := []struct {
+		dbType    db.Type
+		statement string
+		fieldList []db.SensitiveField
+	}{
+		{
+			dbType:    db.MySQL,
+			statement: `SELECT x.name, x.email AS mail, CONCAT(name, email), id FROM (SELECT * FROM t) x`,
+			fieldList: []db.SensitiveField{
+				{Name: "name", Sensitive: true, MaskAlgorithm: partial},
+				{Name: "mail", Sensitive: true, MaskAlgorithm: email},
+				{Name: "CONCAT(name, email)", Sensitive: true},
+				{Name: "id", Sensitive: false},
+			},
+		},
+		{
+			// The field keeps the algorithm only if all the sensitive fields flowing into it share the algorithm.
+			dbType:    db.MySQL,
+			statement: `SELECT name, email, id FROM t UNION SELECT name, name, email FROM t`,
+			fieldList: []db.SensitiveField{
+				{Name: "name", Sensitive: true, MaskAlgorithm: partial},
+				{Name: "email", Sensitive: true},
+				{Name: "id", Sensitive: true, MaskAlgorithm: email},
+			},
+		},
+		{
+			dbType:    db.Postgres,
+			statement: `WITH result AS (SELECT t.name, email, lower(email), note FROM t) SELECT * FROM result LIMIT 10000;`,
+			fieldList: []db.SensitiveField{
+				{Name: "name", Sensitive: true, MaskAlgorithm: partial},
+				{Name: "email", Sensitive: true, MaskAlgorithm: email},
+				{Name: "lower", Sensitive: true},
+				{Name: "note", Sensitive: true},
+			},
+		},
+		{
+			dbType:    db.Postgres,
+			statement: `SELECT * FROM t AS a JOIN t AS b USING (name, email) UNION ALL SELECT name, note, note, name, id, id FROM t`,
+			fieldList: []db.SensitiveField{
+				{Name: "name", Sensitive: true, MaskAlgorithm: partial},
+				{Name: "email", Sensitive: true},
+				{Name: "note", Sensitive: true},
+				{Name: "id", Sensitive: true, MaskAlgorithm: partial},
+				{Name: "note", Sensitive: true},
+				{Name: "id", Sensitive: false},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		res, err := extractSensitiveField(test.dbType, test.statement, defaultDatabase, nil, schemaInfo)
+		require.NoError(t, err, test.statement)
+		require.Equal(t, test.fieldList, res, test.statement)
+	}
+}
@@ -13,8 +13,10 @@ import (
 )
 
 type sensitiveFieldExtractor struct {
-	currentDatabase    string
-	schemaInfo         *db.SensitiveSchemaInfo
+	currentDatabase string
+	schemaInfo      *db.SensitiveSchemaInfo
+	// searchPath is the schemas resolving the unqualified table names for Postgres, it's nil if unknown.
+	searchPath         []string
 	outerSchemaInfo    []fieldInfo
 	cteOuterSchemaInfo []db.TableSchema
 
@@ -22,18 +24,25 @@ type sensitiveFieldExtractor struct {
 	fromFieldList []fieldInfo
 }
 
-func extractSensitiveField(dbType db.Type, statement string, currentDatabase string, schemaInfo *db.SensitiveSchemaInfo) ([]db.SensitiveField, error) {
+func extractSensitiveField(dbType db.Type, statement string, currentDatabase string, searchPath []string, schemaInfo *db.SensitiveSchemaInfo) ([]db.SensitiveField, error) {
 	if schemaInfo == nil {
 		return nil, nil
 	}
 
 	switch dbType {
-	case db.MySQL, db.TiDB:
+	case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 		extractor := &sensitiveFieldExtractor{
 			currentDatabase: currentDatabase,
 			schemaInfo:      schemaInfo,
 		}
 		return extractor.extractMySQLSensitiveField(statement)
+	case db.Postgres:
+		extractor := &sensitiveFieldExtractor{
+			currentDatabase: currentDatabase,
+			schemaInfo:      schemaInfo,
+			searchPath:      searchPath,
+		}
+		return extractor.extractPostgreSQLSensitiveField(statement)
 	default:
 		return nil, nil
 	}
@@ -68,18 +77,62 @@ func (extractor *sensitiveFieldExtractor) extractMySQLSensitiveField(statement s
 	result := []db.SensitiveField{}
 	for _, field := range fieldList {
 		result = append(result, db.SensitiveField{
-			Name:      field.name,
-			Sensitive: field.sensitive,
+			Name:          field.name,
+			Sensitive:     field.sensitive,
+			MaskAlgorithm: field.maskAlgorithm,
 		})
 	}
 	return result, nil
 }
 
 type fieldInfo struct {
-	name      string
-	table     string
-	database  string
+	name     string
+	table    string
+	database string
+	// schema is only used for Postgres.
+	schema    string
 	sensitive bool
+	// hidden is only used for Postgres, the hidden field is the column merged by the JOIN USING clause.
+	// It can be referenced with the table name, but it's not in the unqualified wildcard.
+	hidden bool
+	// maskAlgorithm is the mask algorithm of the sensitive field, nil means the default one.
+	// Only the field passing the column through keeps its algorithm, the field computed from the columns uses the default one,
+	// because the algorithm for the column may reveal the data in the expression, such as the PARTIAL mask for "CONCAT(a, b)".
+	maskAlgorithm *db.MaskAlgorithm
+}
+
+// mergeSensitive merges the sensitive state of the other field flowing into the same field, such as the fields in the same position of UNION.
+// The field keeps the mask algorithm only if all of its sensitive sources share the algorithm.
+func (field *fieldInfo) mergeSensitive(other fieldInfo) {
+	if !other.sensitive {
+		return
+	}
+	if !field.sensitive {
+		field.sensitive = true
+		field.maskAlgorithm = other.maskAlgorithm
+		return
+	}
+	if !equalMaskAlgorithm(field.maskAlgorithm, other.maskAlgorithm) {
+		field.maskAlgorithm = nil
+	}
+}
+
+func equalMaskAlgorithm(a *db.MaskAlgorithm, b *db.MaskAlgorithm) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// mergeSensitiveColumn merges the sensitive state of the field into the CTE column, and returns true if the column is changed.
+func mergeSensitiveColumn(column *db.ColumnInfo, field fieldInfo) bool {
+	merged := fieldInfo{sensitive: column.Sensitive, maskAlgorithm: column.MaskAlgorithm}
+	merged.mergeSensitive(field)
+	if merged.sensitive == column.Sensitive && equalMaskAlgorithm(merged.maskAlgorithm, column.MaskAlgorithm) {
+		return false
+	}
+	column.Sensitive, column.MaskAlgorithm = merged.sensitive, merged.maskAlgorithm
+	return true
 }
 
 func (extractor *sensitiveFieldExtractor) extractNode(in tidbast.Node) ([]fieldInfo, error) {
@@ -131,9 +184,7 @@ func (extractor *sensitiveFieldExtractor) extractSetOpr(node *tidbast.SetOprStmt
 				return nil, errors.Errorf("The used SELECT statements have a different number of columns")
 			}
 			for index := 0; index < len(result); index++ {
-				if fieldList[index].sensitive {
-					result[index].sensitive = true
-				}
+				result[index].mergeSensitive(fieldList[index])
 			}
 		}
 	}
@@ -209,8 +260,9 @@ func (extractor *sensitiveFieldExtractor) extractRecursiveCTE(node *tidbast.Comm
 		}
 		for _, field := range initialField {
 			cteInfo.ColumnList = append(cteInfo.ColumnList, db.ColumnInfo{
-				Name:      field.name,
-				Sensitive: field.sensitive,
+				Name:          field.name,
+				Sensitive:     field.sensitive,
+				MaskAlgorithm: field.maskAlgorithm,
 			})
 		}
 
@@ -244,9 +296,8 @@ func (extractor *sensitiveFieldExtractor) extractRecursiveCTE(node *tidbast.Comm
 
 			changed := false
 			for i, field := range fieldList {
-				if field.sensitive && !cteInfo.ColumnList[i].Sensitive {
+				if mergeSensitiveColumn(&cteInfo.ColumnList[i], field) {
 					changed = true
-					cteInfo.ColumnList[i].Sensitive = true
 				}
 			}
 
@@ -281,8 +332,9 @@ func (extractor *sensitiveFieldExtractor) extractNonRecursiveCTE(node *tidbast.C
 	}
 	for _, field := range fieldList {
 		result.ColumnList = append(result.ColumnList, db.ColumnInfo{
-			Name:      field.name,
-			Sensitive: field.sensitive,
+			Name:          field.name,
+			Sensitive:     field.sensitive,
+			MaskAlgorithm: field.maskAlgorithm,
 		})
 	}
 	return result, nil
@@ -345,11 +397,18 @@ func (extractor *sensitiveFieldExtractor) extractSelect(node *tidbast.SelectStmt
 					return nil, err
 				}
 				fieldName := extractFieldName(field)
+				var maskAlgorithm *db.MaskAlgorithm
+				if columnName, ok := field.Expr.(*tidbast.ColumnNameExpr); ok {
+					if columnField, ok := extractor.findField(columnName.Name.Schema.O, columnName.Name.Table.O, columnName.Name.Name.O); ok {
+						maskAlgorithm = columnField.maskAlgorithm
+					}
+				}
 				result = append(result, fieldInfo{
-					database:  "",
-					table:     "",
-					name:      fieldName,
-					sensitive: sensitive,
+					database:      "",
+					table:         "",
+					name:          fieldName,
+					sensitive:     sensitive,
+					maskAlgorithm: maskAlgorithm,
 				})
 			}
 		}
@@ -373,6 +432,11 @@ func extractFieldName(in *tidbast.SelectField) string {
 }
 
 func (extractor *sensitiveFieldExtractor) checkFieldSensitive(databaseName string, tableName string, fieldName string) bool {
+	field, _ := extractor.findField(databaseName, tableName, fieldName)
+	return field.sensitive
+}
+
+func (extractor *sensitiveFieldExtractor) findField(databaseName string, tableName string, fieldName string) (fieldInfo, bool) {
 	// One sub-query may have multi-outer schemas and the multi-outer schemas can use the same name, such as:
 	//
 	//  select (
@@ -392,7 +456,7 @@ func (extractor *sensitiveFieldExtractor) checkFieldSensitive(databaseName strin
 		sameTable := (tableName == field.table || tableName == "")
 		sameField := (fieldName == field.name)
 		if sameDatabase && sameTable && sameField {
-			return field.sensitive
+			return field, true
 		}
 	}
 
@@ -401,11 +465,11 @@ func (extractor *sensitiveFieldExtractor) checkFieldSensitive(databaseName strin
 		sameTable := (tableName == field.table || tableName == "")
 		sameField := (fieldName == field.name)
 		if sameDatabase && sameTable && sameField {
-			return field.sensitive
+			return field, true
 		}
 	}
 
-	return false
+	return fieldInfo{}, false
 }
 
 func (extractor *sensitiveFieldExtractor) extractColumnFromExprNode(in tidbast.ExprNode) (sensitive bool, err error) {
@@ -523,10 +587,11 @@ func (extractor *sensitiveFieldExtractor) extractTableSource(node *tidbast.Table
 	if node.AsName.O != "" {
 		for _, field := range fieldList {
 			res = append(res, fieldInfo{
-				name:      field.name,
-				table:     node.AsName.O,
-				database:  field.database,
-				sensitive: field.sensitive,
+				name:          field.name,
+				table:         node.AsName.O,
+				database:      field.database,
+				sensitive:     field.sensitive,
+				maskAlgorithm: field.maskAlgorithm,
 			})
 		}
 	} else {
@@ -577,10 +642,11 @@ func (extractor *sensitiveFieldExtractor) extractTableName(node *tidbast.TableNa
 	var res []fieldInfo
 	for _, column := range tableSchema.ColumnList {
 		res = append(res, fieldInfo{
-			name:      column.Name,
-			table:     tableSchema.Name,
-			database:  databaseName,
-			sensitive: column.Sensitive,
+			name:          column.Name,
+			table:         tableSchema.Name,
+			database:      databaseName,
+			sensitive:     column.Sensitive,
+			maskAlgorithm: column.MaskAlgorithm,
 		})
 	}
 	return res, nil
@@ -618,8 +684,8 @@ func mergeJoinField(node *tidbast.Join, leftField []fieldInfo, rightField []fiel
 		// Natural Join will merge the same column name field.
 		for _, field := range leftField {
 			// Merge the sensitive attribute for the same column name field.
-			if rField, exists := rightFieldMap[strings.ToLower(field.name)]; exists && rField.sensitive {
-				field.sensitive = true
+			if rField, exists := rightFieldMap[strings.ToLower(field.name)]; exists {
+				field.mergeSensitive(rField)
 			}
 			result = append(result, field)
 		}
@@ -642,8 +708,8 @@ func mergeJoinField(node *tidbast.Join, leftField []fieldInfo, rightField []fiel
 				_, existsInUsingMap := usingMap[strings.ToLower(field.name)]
 				rField, existsInRightField := rightFieldMap[strings.ToLower(field.name)]
 				// Merge the sensitive attribute for the column name field in USING.
-				if existsInUsingMap && existsInRightField && rField.sensitive {
-					field.sensitive = true
+				if existsInUsingMap && existsInRightField {
+					field.mergeSensitive(rField)
 				}
 				result = append(result, field)
 			}
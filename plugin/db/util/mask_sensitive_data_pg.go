@@ -0,0 +1,799 @@
+package util
+
+import (
+	"fmt"
+
+	pgquery "github.com/pganalyze/pg_query_go/v2"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+const (
+	// pgUnknownFieldName is the name of the field that Postgres cannot infer a name for.
+	pgUnknownFieldName = "?column?"
+)
+
+func (extractor *sensitiveFieldExtractor) extractPostgreSQLSensitiveField(statement string) ([]db.SensitiveField, error) {
+	res, err := pgquery.Parse(statement)
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Stmts) != 1 {
+		return nil, errors.Errorf("expect one statement but found %d", len(res.Stmts))
+	}
+	node := res.Stmts[0].Stmt
+
+	switch node.Node.(type) {
+	case *pgquery.Node_SelectStmt:
+	case *pgquery.Node_ExplainStmt:
+		// The result of EXPLAIN is the query plan rather than the data.
+		return nil, nil
+	default:
+		return nil, errors.Errorf("expect a query statement but found %T", node.Node)
+	}
+
+	// Skip the extraction if the query doesn't access any sensitive table.
+	// Such as the query on the system catalogs, whose tables are not in the schema info.
+	if !extractor.accessPGSensitiveTable(node) {
+		return nil, nil
+	}
+
+	fieldList, err := extractor.extractPGSelect(node.GetSelectStmt())
+	if err != nil {
+		return nil, err
+	}
+	result := []db.SensitiveField{}
+	for _, field := range fieldList {
+		result = append(result, db.SensitiveField{
+			Name:          field.name,
+			Sensitive:     field.sensitive,
+			MaskAlgorithm: field.maskAlgorithm,
+		})
+	}
+	return result, nil
+}
+
+// accessPGSensitiveTable returns true if the statement refers to any table with sensitive columns.
+// The CTE with the same name as a sensitive table is treated as the table, the extraction will tell them apart.
+func (extractor *sensitiveFieldExtractor) accessPGSensitiveTable(node *pgquery.Node) bool {
+	sensitive := false
+	walkPGNode(node, func(n *pgquery.Node) {
+		rangeVar := n.GetRangeVar()
+		if sensitive || rangeVar == nil {
+			return
+		}
+		_, table, ok := extractor.findPGTable(rangeVar.Schemaname, rangeVar.Relname)
+		if !ok {
+			return
+		}
+		for _, column := range table.ColumnList {
+			if column.Sensitive {
+				sensitive = true
+				return
+			}
+		}
+	})
+	return sensitive
+}
+
+func (extractor *sensitiveFieldExtractor) newPGSubqueryExtractor(fromFieldList []fieldInfo) *sensitiveFieldExtractor {
+	// The subquery can access the outer schema, including the FROM items on the left of the LATERAL subquery.
+	var outerSchemaInfo []fieldInfo
+	outerSchemaInfo = append(outerSchemaInfo, extractor.outerSchemaInfo...)
+	outerSchemaInfo = append(outerSchemaInfo, extractor.fromFieldList...)
+	outerSchemaInfo = append(outerSchemaInfo, fromFieldList...)
+	return &sensitiveFieldExtractor{
+		currentDatabase:    extractor.currentDatabase,
+		schemaInfo:         extractor.schemaInfo,
+		searchPath:         extractor.searchPath,
+		outerSchemaInfo:    outerSchemaInfo,
+		cteOuterSchemaInfo: extractor.cteOuterSchemaInfo,
+	}
+}
+
+func (extractor *sensitiveFieldExtractor) extractPGSelect(node *pgquery.SelectStmt) ([]fieldInfo, error) {
+	if node == nil {
+		return nil, errors.Errorf("expect a SELECT statement")
+	}
+	if node.WithClause != nil {
+		cteOuterLength := len(extractor.cteOuterSchemaInfo)
+		defer func() {
+			extractor.cteOuterSchemaInfo = extractor.cteOuterSchemaInfo[:cteOuterLength]
+		}()
+		for _, cte := range node.WithClause.Ctes {
+			cteTable, err := extractor.extractPGCTE(cte.GetCommonTableExpr(), node.WithClause.Recursive)
+			if err != nil {
+				return nil, err
+			}
+			extractor.cteOuterSchemaInfo = append(extractor.cteOuterSchemaInfo, cteTable)
+		}
+	}
+
+	switch node.Op {
+	case pgquery.SetOperation_SETOP_UNION, pgquery.SetOperation_SETOP_INTERSECT, pgquery.SetOperation_SETOP_EXCEPT:
+		return extractor.extractPGSetOperation(node)
+	}
+
+	if len(node.ValuesLists) > 0 {
+		return extractor.extractPGValues(node.ValuesLists)
+	}
+
+	var fromFieldList []fieldInfo
+	for _, item := range node.FromClause {
+		fieldList, err := extractor.extractPGFromItem(item, fromFieldList)
+		if err != nil {
+			return nil, err
+		}
+		fromFieldList = append(fromFieldList, fieldList...)
+	}
+	previousFromFieldList := extractor.fromFieldList
+	extractor.fromFieldList = fromFieldList
+	defer func() {
+		extractor.fromFieldList = previousFromFieldList
+	}()
+
+	var result []fieldInfo
+	for _, target := range node.TargetList {
+		resTarget := target.GetResTarget()
+		if resTarget == nil {
+			continue
+		}
+		var maskAlgorithm *db.MaskAlgorithm
+		if columnRef := resTarget.Val.GetColumnRef(); columnRef != nil {
+			schemaName, tableName, columnName := splitPGColumnRef(columnRef)
+			if columnName == "*" {
+				result = append(result, extractor.expandPGWildcard(schemaName, tableName)...)
+				continue
+			}
+			if field, ok := extractor.findPGField(schemaName, tableName, columnName); ok {
+				maskAlgorithm = field.maskAlgorithm
+			}
+		}
+		sensitive, err := extractor.extractPGExpr(resTarget.Val)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, fieldInfo{
+			name:          getPGTargetName(resTarget),
+			sensitive:     sensitive,
+			maskAlgorithm: maskAlgorithm,
+		})
+	}
+	return result, nil
+}
+
+func (extractor *sensitiveFieldExtractor) extractPGSetOperation(node *pgquery.SelectStmt) ([]fieldInfo, error) {
+	leftField, err := extractor.extractPGSelect(node.Larg)
+	if err != nil {
+		return nil, err
+	}
+	rightField, err := extractor.extractPGSelect(node.Rarg)
+	if err != nil {
+		return nil, err
+	}
+	if len(leftField) != len(rightField) {
+		// The error content comes from Postgres.
+		return nil, errors.Errorf("each %s query must have the same number of columns", getPGSetOperationName(node.Op))
+	}
+	for i, field := range rightField {
+		leftField[i].mergeSensitive(field)
+	}
+	return leftField, nil
+}
+
+func getPGSetOperationName(op pgquery.SetOperation) string {
+	switch op {
+	case pgquery.SetOperation_SETOP_INTERSECT:
+		return "INTERSECT"
+	case pgquery.SetOperation_SETOP_EXCEPT:
+		return "EXCEPT"
+	default:
+		return "UNION"
+	}
+}
+
+func (extractor *sensitiveFieldExtractor) extractPGValues(valuesLists []*pgquery.Node) ([]fieldInfo, error) {
+	var result []fieldInfo
+	for _, values := range valuesLists {
+		list := values.GetList()
+		if list == nil {
+			continue
+		}
+		if result == nil {
+			for i := range list.Items {
+				// Postgres names the VALUES columns column1, column2 and so on.
+				result = append(result, fieldInfo{name: fmt.Sprintf("column%d", i+1)})
+			}
+		}
+		if len(list.Items) != len(result) {
+			// The error content comes from Postgres.
+			return nil, errors.Errorf("VALUES lists must all be the same length")
+		}
+		for i, item := range list.Items {
+			sensitive, err := extractor.extractPGExpr(item)
+			if err != nil {
+				return nil, err
+			}
+			if sensitive {
+				result[i].sensitive = true
+			}
+		}
+	}
+	return result, nil
+}
+
+func (extractor *sensitiveFieldExtractor) extractPGCTE(node *pgquery.CommonTableExpr, recursive bool) (db.TableSchema, error) {
+	if node == nil {
+		return db.TableSchema{}, errors.Errorf("expect a common table expression")
+	}
+	query := node.Ctequery.GetSelectStmt()
+	if query == nil {
+		return db.TableSchema{}, errors.Errorf("expect a SELECT statement in the common table expression %q", node.Ctename)
+	}
+
+	if !recursive || query.Op != pgquery.SetOperation_SETOP_UNION {
+		fieldList, err := extractor.extractPGSelect(query)
+		if err != nil {
+			return db.TableSchema{}, err
+		}
+		return convertToPGCTETable(node, fieldList)
+	}
+
+	// The recursive CTE is "initial_part UNION [ALL] recursive_part", and only the recursive part can refer to the CTE itself.
+	initialField, err := extractor.extractPGSelect(query.Larg)
+	if err != nil {
+		return db.TableSchema{}, err
+	}
+	cteInfo, err := convertToPGCTETable(node, initialField)
+	if err != nil {
+		return db.TableSchema{}, err
+	}
+
+	// Compute the dependent closures by simulating the recursive process like the MySQL one,
+	// until the sensitive state of the fields doesn't change.
+	extractor.cteOuterSchemaInfo = append(extractor.cteOuterSchemaInfo, cteInfo)
+	defer func() {
+		extractor.cteOuterSchemaInfo = extractor.cteOuterSchemaInfo[:len(extractor.cteOuterSchemaInfo)-1]
+	}()
+	for {
+		fieldList, err := extractor.extractPGSelect(query.Rarg)
+		if err != nil {
+			return db.TableSchema{}, err
+		}
+		if len(fieldList) != len(cteInfo.ColumnList) {
+			// The error content comes from Postgres.
+			return db.TableSchema{}, errors.Errorf("each UNION query must have the same number of columns")
+		}
+
+		changed := false
+		for i, field := range fieldList {
+			if mergeSensitiveColumn(&cteInfo.ColumnList[i], field) {
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+		extractor.cteOuterSchemaInfo[len(extractor.cteOuterSchemaInfo)-1] = cteInfo
+	}
+	return cteInfo, nil
+}
+
+func convertToPGCTETable(node *pgquery.CommonTableExpr, fieldList []fieldInfo) (db.TableSchema, error) {
+	if len(node.Aliascolnames) > len(fieldList) {
+		// The error content comes from Postgres.
+		return db.TableSchema{}, errors.Errorf("WITH query %q has %d columns available but %d columns specified", node.Ctename, len(fieldList), len(node.Aliascolnames))
+	}
+	result := db.TableSchema{
+		Name:       node.Ctename,
+		ColumnList: []db.ColumnInfo{},
+	}
+	for i, field := range fieldList {
+		name := field.name
+		if i < len(node.Aliascolnames) {
+			name = getPGString(node.Aliascolnames[i])
+		}
+		result.ColumnList = append(result.ColumnList, db.ColumnInfo{
+			Name:          name,
+			Sensitive:     field.sensitive,
+			MaskAlgorithm: field.maskAlgorithm,
+		})
+	}
+	return result, nil
+}
+
+// extractPGFromItem extracts the fields of the FROM item, the fromFieldList is the fields of the FROM items on its left.
+func (extractor *sensitiveFieldExtractor) extractPGFromItem(node *pgquery.Node, fromFieldList []fieldInfo) ([]fieldInfo, error) {
+	switch n := node.Node.(type) {
+	case *pgquery.Node_RangeVar:
+		return extractor.extractPGRangeVar(n.RangeVar)
+	case *pgquery.Node_RangeSubselect:
+		fieldList, err := extractor.newPGSubqueryExtractor(fromFieldList).extractPGSelect(n.RangeSubselect.Subquery.GetSelectStmt())
+		if err != nil {
+			return nil, err
+		}
+		return applyPGAlias(fieldList, n.RangeSubselect.Alias), nil
+	case *pgquery.Node_JoinExpr:
+		return extractor.extractPGJoin(n.JoinExpr, fromFieldList)
+	case *pgquery.Node_RangeFunction:
+		return extractor.extractPGRangeFunction(n.RangeFunction, fromFieldList)
+	default:
+		return nil, errors.Errorf("unsupported FROM item %T", n)
+	}
+}
+
+func (extractor *sensitiveFieldExtractor) extractPGRangeVar(node *pgquery.RangeVar) ([]fieldInfo, error) {
+	schemaName, tableSchema, err := extractor.findPGTableSchema(node.Schemaname, node.Relname)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []fieldInfo
+	for _, column := range tableSchema.ColumnList {
+		res = append(res, fieldInfo{
+			name:          column.Name,
+			table:         tableSchema.Name,
+			schema:        schemaName,
+			database:      extractor.currentDatabase,
+			sensitive:     column.Sensitive,
+			maskAlgorithm: column.MaskAlgorithm,
+		})
+	}
+	return applyPGAlias(res, node.Alias), nil
+}
+
+// applyPGAlias renames the table and the columns of the fields, the hidden fields are invisible outside the alias.
+func applyPGAlias(fieldList []fieldInfo, alias *pgquery.Alias) []fieldInfo {
+	if alias == nil {
+		return fieldList
+	}
+	var res []fieldInfo
+	for _, field := range fieldList {
+		if field.hidden {
+			continue
+		}
+		name := field.name
+		if i := len(res); i < len(alias.Colnames) {
+			name = getPGString(alias.Colnames[i])
+		}
+		res = append(res, fieldInfo{
+			name:          name,
+			table:         alias.Aliasname,
+			sensitive:     field.sensitive,
+			maskAlgorithm: field.maskAlgorithm,
+		})
+	}
+	return res
+}
+
+func (extractor *sensitiveFieldExtractor) extractPGJoin(node *pgquery.JoinExpr, fromFieldList []fieldInfo) ([]fieldInfo, error) {
+	leftField, err := extractor.extractPGFromItem(node.Larg, fromFieldList)
+	if err != nil {
+		return nil, err
+	}
+	var lateralFieldList []fieldInfo
+	lateralFieldList = append(lateralFieldList, fromFieldList...)
+	lateralFieldList = append(lateralFieldList, leftField...)
+	rightField, err := extractor.extractPGFromItem(node.Rarg, lateralFieldList)
+	if err != nil {
+		return nil, err
+	}
+
+	var usingList []string
+	if node.IsNatural {
+		// NATURAL JOIN is the JOIN USING the common columns in the order of the left side.
+		rightFieldMap := make(map[string]bool)
+		for _, field := range rightField {
+			if !field.hidden {
+				rightFieldMap[field.name] = true
+			}
+		}
+		for _, field := range leftField {
+			if !field.hidden && rightFieldMap[field.name] {
+				usingList = append(usingList, field.name)
+			}
+		}
+	} else {
+		for _, column := range node.UsingClause {
+			usingList = append(usingList, getPGString(column))
+		}
+	}
+	return applyPGAlias(mergePGJoinField(leftField, rightField, usingList), node.Alias), nil
+}
+
+// mergePGJoinField merges the fields of the JOIN.
+// The columns in USING are merged into one column, which comes first in the unqualified wildcard and is sensitive if either side is sensitive.
+// The original columns are kept as hidden, so that they can be referenced with the table name.
+func mergePGJoinField(leftField []fieldInfo, rightField []fieldInfo, usingList []string) []fieldInfo {
+	var result []fieldInfo
+	usingMap := make(map[string]bool)
+	for _, name := range usingList {
+		usingMap[name] = true
+		merged := fieldInfo{name: name}
+		for _, fieldList := range [][]fieldInfo{leftField, rightField} {
+			for _, field := range fieldList {
+				if !field.hidden && field.name == name {
+					merged.mergeSensitive(field)
+					break
+				}
+			}
+		}
+		result = append(result, merged)
+	}
+	for _, fieldList := range [][]fieldInfo{leftField, rightField} {
+		for _, field := range fieldList {
+			if usingMap[field.name] {
+				field.hidden = true
+			}
+			result = append(result, field)
+		}
+	}
+	return result
+}
+
+func (extractor *sensitiveFieldExtractor) extractPGRangeFunction(node *pgquery.RangeFunction, fromFieldList []fieldInfo) ([]fieldInfo, error) {
+	subqueryExtractor := extractor.newPGSubqueryExtractor(fromFieldList)
+	var res []fieldInfo
+	for _, function := range node.Functions {
+		// Each function is a list of the function call and its column definitions.
+		list := function.GetList()
+		if list == nil || len(list.Items) == 0 {
+			continue
+		}
+		sensitive, err := subqueryExtractor.extractPGExpr(list.Items[0])
+		if err != nil {
+			return nil, err
+		}
+		var columnDefList []*pgquery.Node
+		if len(list.Items) > 1 && list.Items[1].GetList() != nil {
+			columnDefList = list.Items[1].GetList().Items
+		}
+		if len(columnDefList) == 0 {
+			columnDefList = node.Coldeflist
+		}
+		if len(columnDefList) == 0 {
+			name := getPGExprName(list.Items[0])
+			if node.Alias != nil && len(node.Functions) == 1 {
+				// The column of the function returning a scalar is named after the alias.
+				name = node.Alias.Aliasname
+			}
+			res = append(res, fieldInfo{name: name, sensitive: sensitive})
+			continue
+		}
+		for _, columnDef := range columnDefList {
+			res = append(res, fieldInfo{name: columnDef.GetColumnDef().GetColname(), sensitive: sensitive})
+		}
+	}
+	if node.Ordinality {
+		res = append(res, fieldInfo{name: "ordinality"})
+	}
+	if node.Alias == nil {
+		return res, nil
+	}
+	return applyPGAlias(res, node.Alias), nil
+}
+
+func (extractor *sensitiveFieldExtractor) findPGTableSchema(schemaName string, tableName string) (string, db.TableSchema, error) {
+	// The closer CTE hides the outer CTE and the table with the same name.
+	if schemaName == "" {
+		for i := len(extractor.cteOuterSchemaInfo) - 1; i >= 0; i-- {
+			table := extractor.cteOuterSchemaInfo[i]
+			if table.Name == tableName {
+				return "", table, nil
+			}
+		}
+	}
+
+	if schema, table, ok := extractor.findPGTable(schemaName, tableName); ok {
+		return schema, table, nil
+	}
+	return "", db.TableSchema{}, errors.Errorf("Table %q.%q not found", schemaName, tableName)
+}
+
+// findPGTable finds the table in the current database, the unqualified table name is resolved by the search path.
+// If the search path is unknown, the tables of the same name in all schemas are merged into one with the empty schema name,
+// whose column is sensitive if it's sensitive in any of them.
+func (extractor *sensitiveFieldExtractor) findPGTable(schemaName string, tableName string) (string, db.TableSchema, bool) {
+	var schemaList []db.SchemaSchema
+	for _, database := range extractor.schemaInfo.DatabaseList {
+		if database.Name == extractor.currentDatabase {
+			schemaList = append(schemaList, database.SchemaList...)
+		}
+	}
+	find := func(schemaName string) (db.TableSchema, bool) {
+		for _, schema := range schemaList {
+			if schema.Name != schemaName {
+				continue
+			}
+			for _, table := range schema.TableList {
+				if table.Name == tableName {
+					return table, true
+				}
+			}
+		}
+		return db.TableSchema{}, false
+	}
+
+	if schemaName != "" {
+		table, ok := find(schemaName)
+		return schemaName, table, ok
+	}
+	if extractor.searchPath != nil {
+		for _, name := range extractor.searchPath {
+			if table, ok := find(name); ok {
+				return name, table, true
+			}
+		}
+		return "", db.TableSchema{}, false
+	}
+
+	var tableList []db.TableSchema
+	var tableSchemaName string
+	for _, schema := range schemaList {
+		if table, ok := find(schema.Name); ok {
+			tableList = append(tableList, table)
+			tableSchemaName = schema.Name
+		}
+	}
+	switch len(tableList) {
+	case 0:
+		return "", db.TableSchema{}, false
+	case 1:
+		return tableSchemaName, tableList[0], true
+	default:
+		return "", mergePGTable(tableName, tableList), true
+	}
+}
+
+// mergePGTable merges the tables of the same name in different schemas, the column is sensitive if it's sensitive in any of them.
+func mergePGTable(tableName string, tableList []db.TableSchema) db.TableSchema {
+	result := db.TableSchema{
+		Name:       tableName,
+		ColumnList: []db.ColumnInfo{},
+	}
+	columnIndex := make(map[string]int)
+	for _, table := range tableList {
+		for _, column := range table.ColumnList {
+			i, ok := columnIndex[column.Name]
+			if !ok {
+				columnIndex[column.Name] = len(result.ColumnList)
+				result.ColumnList = append(result.ColumnList, db.ColumnInfo{Name: column.Name})
+				i = len(result.ColumnList) - 1
+			}
+			if column.Sensitive && !result.ColumnList[i].Sensitive {
+				result.ColumnList[i].Sensitive = true
+				result.ColumnList[i].MaskAlgorithm = column.MaskAlgorithm
+			}
+		}
+	}
+	return result
+}
+
+// expandPGWildcard expands "*" and "table.*".
+func (extractor *sensitiveFieldExtractor) expandPGWildcard(schemaName string, tableName string) []fieldInfo {
+	var result []fieldInfo
+	for _, field := range extractor.fromFieldList {
+		if tableName == "" {
+			if !field.hidden {
+				result = append(result, field)
+			}
+			continue
+		}
+		if field.table == tableName && matchPGSchema(field, schemaName) {
+			result = append(result, field)
+		}
+	}
+	return result
+}
+
+// matchPGSchema returns true if the field can be qualified by the schema name.
+// The field of the table whose schema is unknown matches any schema name, so the sensitive field isn't skipped.
+func matchPGSchema(field fieldInfo, schemaName string) bool {
+	return schemaName == "" || field.schema == "" || field.schema == schemaName
+}
+
+// splitPGColumnRef splits the column reference "[[[database.]schema.]table.]column", and the column is "*" for the wildcard.
+func splitPGColumnRef(node *pgquery.ColumnRef) (string, string, string) {
+	var names []string
+	for _, field := range node.Fields {
+		switch f := field.Node.(type) {
+		case *pgquery.Node_String_:
+			names = append(names, f.String_.Str)
+		case *pgquery.Node_AStar:
+			names = append(names, "*")
+		}
+	}
+	switch len(names) {
+	case 0:
+		return "", "", ""
+	case 1:
+		return "", "", names[0]
+	case 2:
+		return "", names[0], names[1]
+	default:
+		return names[len(names)-3], names[len(names)-2], names[len(names)-1]
+	}
+}
+
+func (extractor *sensitiveFieldExtractor) checkPGColumnRef(node *pgquery.ColumnRef) bool {
+	schemaName, tableName, columnName := splitPGColumnRef(node)
+	if columnName == "*" {
+		for _, field := range extractor.expandPGWildcard(schemaName, tableName) {
+			if field.sensitive {
+				return true
+			}
+		}
+		return false
+	}
+	if field, ok := extractor.findPGField(schemaName, tableName, columnName); ok {
+		return field.sensitive
+	}
+	if tableName == "" {
+		// The column reference may be the whole-row reference of the table, such as "SELECT t FROM t".
+		for _, field := range extractor.expandPGWildcard("", columnName) {
+			if field.sensitive {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// findPGField finds the field referenced by the column reference.
+// The fields in the FROM clause hide the ones in the outer query, and the closer outer query hides the further one.
+func (extractor *sensitiveFieldExtractor) findPGField(schemaName string, tableName string, fieldName string) (fieldInfo, bool) {
+	match := func(field fieldInfo) bool {
+		if tableName == "" {
+			return !field.hidden && field.name == fieldName
+		}
+		return field.table == tableName && matchPGSchema(field, schemaName) && field.name == fieldName
+	}
+	for _, field := range extractor.fromFieldList {
+		if match(field) {
+			return field, true
+		}
+	}
+	for i := len(extractor.outerSchemaInfo) - 1; i >= 0; i-- {
+		if field := extractor.outerSchemaInfo[i]; match(field) {
+			return field, true
+		}
+	}
+	return fieldInfo{}, false
+}
+
+// extractPGExpr returns true if the expression refers to any sensitive field.
+func (extractor *sensitiveFieldExtractor) extractPGExpr(node *pgquery.Node) (bool, error) {
+	if node == nil {
+		return false, nil
+	}
+
+	switch n := node.Node.(type) {
+	case *pgquery.Node_ColumnRef:
+		return extractor.checkPGColumnRef(n.ColumnRef), nil
+	case *pgquery.Node_SubLink:
+		sensitive, err := extractor.extractPGExpr(n.SubLink.Testexpr)
+		if err != nil || sensitive {
+			return sensitive, err
+		}
+		return extractor.extractPGSubquery(n.SubLink.Subselect)
+	case *pgquery.Node_SelectStmt:
+		return extractor.extractPGSubquery(node)
+	}
+
+	for _, child := range getPGChildNodes(node) {
+		sensitive, err := extractor.extractPGExpr(child)
+		if err != nil || sensitive {
+			return sensitive, err
+		}
+	}
+	return false, nil
+}
+
+func (extractor *sensitiveFieldExtractor) extractPGSubquery(node *pgquery.Node) (bool, error) {
+	fieldList, err := extractor.newPGSubqueryExtractor(nil).extractPGSelect(node.GetSelectStmt())
+	if err != nil {
+		return false, err
+	}
+	for _, field := range fieldList {
+		if field.sensitive {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func getPGTargetName(node *pgquery.ResTarget) string {
+	if node.Name != "" {
+		return node.Name
+	}
+	return getPGExprName(node.Val)
+}
+
+// getPGExprName returns the field name of the expression inferred by Postgres.
+func getPGExprName(node *pgquery.Node) string {
+	switch n := node.Node.(type) {
+	case *pgquery.Node_ColumnRef:
+		if _, _, columnName := splitPGColumnRef(n.ColumnRef); columnName != "" {
+			return columnName
+		}
+	case *pgquery.Node_FuncCall:
+		if len(n.FuncCall.Funcname) > 0 {
+			return getPGString(n.FuncCall.Funcname[len(n.FuncCall.Funcname)-1])
+		}
+	case *pgquery.Node_TypeCast:
+		if name := getPGExprName(n.TypeCast.Arg); name != pgUnknownFieldName {
+			return name
+		}
+		if typeName := n.TypeCast.TypeName; typeName != nil && len(typeName.Names) > 0 {
+			return getPGString(typeName.Names[len(typeName.Names)-1])
+		}
+	case *pgquery.Node_CaseExpr:
+		return "case"
+	case *pgquery.Node_CoalesceExpr:
+		return "coalesce"
+	case *pgquery.Node_AArrayExpr:
+		return "array"
+	case *pgquery.Node_SubLink:
+		switch n.SubLink.SubLinkType {
+		case pgquery.SubLinkType_EXISTS_SUBLINK:
+			return "exists"
+		case pgquery.SubLinkType_ARRAY_SUBLINK:
+			return "array"
+		case pgquery.SubLinkType_EXPR_SUBLINK:
+			if query := n.SubLink.Subselect.GetSelectStmt(); query != nil && len(query.TargetList) > 0 {
+				if resTarget := query.TargetList[0].GetResTarget(); resTarget != nil {
+					return getPGTargetName(resTarget)
+				}
+			}
+		}
+	}
+	return pgUnknownFieldName
+}
+
+func getPGString(node *pgquery.Node) string {
+	if s := node.GetString_(); s != nil {
+		return s.Str
+	}
+	return ""
+}
+
+// getPGChildNodes returns the child nodes of the node, including the ones in the nested messages which are not nodes, such as the window definition.
+func getPGChildNodes(node *pgquery.Node) []*pgquery.Node {
+	var children []*pgquery.Node
+	var collect func(m protoreflect.Message)
+	visit := func(m protoreflect.Message) {
+		if child, ok := m.Interface().(*pgquery.Node); ok {
+			children = append(children, child)
+			return
+		}
+		collect(m)
+	}
+	collect = func(m protoreflect.Message) {
+		m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+			if fd.Kind() != protoreflect.MessageKind || fd.IsMap() {
+				return true
+			}
+			if fd.IsList() {
+				list := v.List()
+				for i := 0; i < list.Len(); i++ {
+					visit(list.Get(i).Message())
+				}
+				return true
+			}
+			visit(v.Message())
+			return true
+		})
+	}
+	collect(node.ProtoReflect())
+	return children
+}
+
+// walkPGNode calls the function on the node and all its descendants.
+func walkPGNode(node *pgquery.Node, fn func(*pgquery.Node)) {
+	fn(node)
+	for _, child := range getPGChildNodes(node) {
+		walkPGNode(child, fn)
+	}
+}
@@ -0,0 +1,204 @@
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+const (
+	// MaskedValue is the value replacing the whole sensitive value.
+	MaskedValue = "******"
+
+	maskCharacter = '*'
+	// phoneKeptDigits is the number of the trailing digits kept by the PHONE mask.
+	phoneKeptDigits = 4
+	// phoneMinDigits is the minimum number of the digits in the phone number to keep the trailing digits.
+	phoneMinDigits = 7
+)
+
+var (
+	// dateLayouts are the layouts of the dates masked by the RANGE mask, the drivers return dates as strings in these layouts.
+	dateLayouts = []string{
+		time.RFC3339Nano,
+		"2006-01-02T15:04:05",
+		"2006-01-02 15:04:05.999999999Z07:00",
+		"2006-01-02 15:04:05.999999999",
+		"2006-01-02",
+	}
+)
+
+// SensitiveDataMasker masks the sensitive values with their mask algorithms.
+type SensitiveDataMasker struct {
+	defaultType db.SensitiveDataMaskType
+	salt        string
+}
+
+// NewSensitiveDataMasker creates a masker with the default mask type and the salt of the query context.
+func NewSensitiveDataMasker(queryContext *db.QueryContext) *SensitiveDataMasker {
+	return &SensitiveDataMasker{
+		defaultType: queryContext.SensitiveDataMaskType,
+		salt:        queryContext.MaskingSalt,
+	}
+}
+
+// Mask masks the value with the algorithm, and the nil algorithm means the default mask type.
+// The value is masked as a whole if the algorithm doesn't apply to it, such as the RANGE mask on the string which is not a date.
+// The NULL value is kept by all but the FULL and DEFAULT masks, which don't reveal anything of the value.
+func (m *SensitiveDataMasker) Mask(value interface{}, algorithm *db.MaskAlgorithm) interface{} {
+	if algorithm == nil {
+		algorithm = &db.MaskAlgorithm{Type: m.defaultType}
+	}
+	if value == nil {
+		return maskNil(algorithm.Type)
+	}
+
+	switch algorithm.Type {
+	case db.SensitiveDataMaskTypePartial:
+		return maskPartial(toMaskString(value), algorithm.PrefixLength, algorithm.SuffixLength)
+	case db.SensitiveDataMaskTypeHash:
+		sum := sha256.Sum256([]byte(m.salt + toMaskString(value)))
+		return hex.EncodeToString(sum[:])
+	case db.SensitiveDataMaskTypeRange:
+		if masked, ok := maskRange(value, algorithm.RangeWidth, algorithm.RangeUnit); ok {
+			return masked
+		}
+	case db.SensitiveDataMaskTypeEmail:
+		if masked, ok := maskEmail(toMaskString(value)); ok {
+			return masked
+		}
+	case db.SensitiveDataMaskTypePhone:
+		return maskPhone(toMaskString(value))
+	}
+	return MaskedValue
+}
+
+func maskNil(maskType db.SensitiveDataMaskType) interface{} {
+	switch maskType {
+	case db.SensitiveDataMaskTypePartial, db.SensitiveDataMaskTypeHash, db.SensitiveDataMaskTypeRange, db.SensitiveDataMaskTypeEmail, db.SensitiveDataMaskTypePhone:
+		return nil
+	default:
+		return MaskedValue
+	}
+}
+
+func toMaskString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// maskPartial keeps the first prefixLength and the last suffixLength characters, and masks each character between them.
+// The whole value is masked if it's not longer than the kept characters.
+func maskPartial(s string, prefixLength int, suffixLength int) string {
+	runes := []rune(s)
+	if len(runes) <= prefixLength+suffixLength {
+		return strings.Repeat(string(maskCharacter), len(runes))
+	}
+	for i := prefixLength; i < len(runes)-suffixLength; i++ {
+		runes[i] = maskCharacter
+	}
+	return string(runes)
+}
+
+// maskRange masks the number with the range of the width containing it, such as "[40, 50)",
+// and masks the date with the year, the month or the day containing it, such as "2023-01".
+func maskRange(value interface{}, width float64, unit string) (string, bool) {
+	var number float64
+	isNumber := true
+	switch v := value.(type) {
+	case int:
+		number = float64(v)
+	case int32:
+		number = float64(v)
+	case int64:
+		number = float64(v)
+	case float32:
+		number = float64(v)
+	case float64:
+		number = v
+	case time.Time:
+		return maskDate(v, unit), true
+	default:
+		s := toMaskString(value)
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			number = f
+			break
+		}
+		isNumber = false
+		for _, layout := range dateLayouts {
+			if t, err := time.Parse(layout, s); err == nil {
+				return maskDate(t, unit), true
+			}
+		}
+	}
+	if !isNumber || width <= 0 || math.IsNaN(number) || math.IsInf(number, 0) {
+		return "", false
+	}
+	lower := math.Floor(number/width) * width
+	return fmt.Sprintf("[%s, %s)", formatFloat(lower), formatFloat(lower+width)), true
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func maskDate(t time.Time, unit string) string {
+	switch strings.ToUpper(unit) {
+	case "DAY":
+		return t.Format("2006-01-02")
+	case "MONTH":
+		return t.Format("2006-01")
+	default:
+		return t.Format("2006")
+	}
+}
+
+// maskEmail keeps the first character of the local part and the domain, such as "a****@example.com".
+func maskEmail(s string) (string, bool) {
+	at := strings.LastIndex(s, "@")
+	if at <= 0 || at == len(s)-1 {
+		return "", false
+	}
+	return maskPartial(s[:at], 1, 0) + s[at:], true
+}
+
+// maskPhone masks the digits except the last four and keeps the formatting, such as "+* (***) ***-4567".
+// All digits are masked if the phone number is too short, because the last four digits would be most of it.
+func maskPhone(s string) string {
+	runes := []rune(s)
+	digitCount := 0
+	for _, r := range runes {
+		if unicode.IsDigit(r) {
+			digitCount++
+		}
+	}
+	maskedCount := digitCount - phoneKeptDigits
+	if digitCount < phoneMinDigits {
+		maskedCount = digitCount
+	}
+	for i, r := range runes {
+		if maskedCount == 0 {
+			break
+		}
+		if unicode.IsDigit(r) {
+			runes[i] = maskCharacter
+			maskedCount--
+		}
+	}
+	return string(runes)
+}
@@ -0,0 +1,51 @@
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestSensitiveDataMasker(t *testing.T) {
+	hash := sha256.Sum256([]byte("salt" + "alice"))
+	tests := []struct {
+		value     interface{}
+		algorithm *db.MaskAlgorithm
+		want      interface{}
+	}{
+		{"alice", nil, MaskedValue},
+		{nil, nil, MaskedValue},
+		{int64(42), &db.MaskAlgorithm{Type: db.SensitiveDataMaskTypeFull}, MaskedValue},
+		{"4111111111111111", &db.MaskAlgorithm{Type: db.SensitiveDataMaskTypePartial, PrefixLength: 4, SuffixLength: 4}, "4111********1111"},
+		{"张三丰", &db.MaskAlgorithm{Type: db.SensitiveDataMaskTypePartial, PrefixLength: 1}, "张**"},
+		{"abc", &db.MaskAlgorithm{Type: db.SensitiveDataMaskTypePartial, PrefixLength: 2, SuffixLength: 1}, "***"},
+		{nil, &db.MaskAlgorithm{Type: db.SensitiveDataMaskTypePartial, PrefixLength: 1}, nil},
+		{"alice", &db.MaskAlgorithm{Type: db.SensitiveDataMaskTypeHash}, hex.EncodeToString(hash[:])},
+		{int64(42), &db.MaskAlgorithm{Type: db.SensitiveDataMaskTypeRange, RangeWidth: 10}, "[40, 50)"},
+		{-2.5, &db.MaskAlgorithm{Type: db.SensitiveDataMaskTypeRange, RangeWidth: 2}, "[-4, -2)"},
+		{"12.5", &db.MaskAlgorithm{Type: db.SensitiveDataMaskTypeRange, RangeWidth: 0.5}, "[12.5, 13)"},
+		{"2023-01-07T12:30:00Z", &db.MaskAlgorithm{Type: db.SensitiveDataMaskTypeRange, RangeUnit: "MONTH"}, "2023-01"},
+		{"2023-01-07", &db.MaskAlgorithm{Type: db.SensitiveDataMaskTypeRange, RangeUnit: "DAY"}, "2023-01-07"},
+		{"2023-01-07 12:30:00", &db.MaskAlgorithm{Type: db.SensitiveDataMaskTypeRange, RangeWidth: 10}, "2023"},
+		{int64(42), &db.MaskAlgorithm{Type: db.SensitiveDataMaskTypeRange, RangeUnit: "YEAR"}, MaskedValue},
+		{"unknown", &db.MaskAlgorithm{Type: db.SensitiveDataMaskTypeRange, RangeWidth: 10}, MaskedValue},
+		{"alice@example.com", &db.MaskAlgorithm{Type: db.SensitiveDataMaskTypeEmail}, "a****@example.com"},
+		{"a@example.com", &db.MaskAlgorithm{Type: db.SensitiveDataMaskTypeEmail}, "*@example.com"},
+		{"alice", &db.MaskAlgorithm{Type: db.SensitiveDataMaskTypeEmail}, MaskedValue},
+		{"+1 (555) 123-4567", &db.MaskAlgorithm{Type: db.SensitiveDataMaskTypePhone}, "+* (***) ***-4567"},
+		{"13812345678", &db.MaskAlgorithm{Type: db.SensitiveDataMaskTypePhone}, "*******5678"},
+		{"110", &db.MaskAlgorithm{Type: db.SensitiveDataMaskTypePhone}, "***"},
+	}
+
+	masker := NewSensitiveDataMasker(&db.QueryContext{
+		SensitiveDataMaskType: db.SensitiveDataMaskTypeDefault,
+		MaskingSalt:           "salt",
+	})
+	for _, test := range tests {
+		require.Equal(t, test.want, masker.Mask(test.value, test.algorithm), test.value)
+	}
+}
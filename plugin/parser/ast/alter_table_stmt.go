@@ -6,4 +6,6 @@ type AlterTableStmt struct {
 
 	Table         *TableDef
 	AlterItemList []Node
+	// Cluster is a ClickHouse specific field for the ON CLUSTER clause.
+	Cluster string
 }
@@ -7,4 +7,6 @@ type ColumnDef struct {
 	ColumnName     string
 	Type           DataType
 	ConstraintList []*ConstraintDef
+	// Comment is the inline column comment for Snowflake and ClickHouse.
+	Comment string
 }
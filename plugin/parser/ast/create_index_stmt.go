@@ -4,7 +4,7 @@ package ast
 // TODO(rebelice): fully support CREATE INDEX statements.
 // Currently, only support:
 // ```
-// CREATE [ UNIQUE ] INDEX [ [ IF NOT EXISTS ] name ] ON table_name [ USING method ]
+// CREATE [ UNIQUE ] INDEX [ CONCURRENTLY ] [ [ IF NOT EXISTS ] name ] ON table_name [ USING method ]
 // ( { column_name | ( expression ) } [ ASC | DESC ] [ NULLS { FIRST | LAST } ] [, ...] )
 // ```.
 type CreateIndexStmt struct {
@@ -12,4 +12,6 @@ type CreateIndexStmt struct {
 
 	Index       *IndexDef
 	IfNotExists bool
+	// Concurrently builds the index without taking a lock that blocks writes on the table.
+	Concurrently bool
 }
@@ -8,4 +8,13 @@ type CreateTableStmt struct {
 	Name           *TableDef
 	ColumnList     []*ColumnDef
 	ConstraintList []*ConstraintDef
+	// Comment is the inline table comment for Snowflake and ClickHouse.
+	Comment string
+	// Cluster is a ClickHouse specific field for the ON CLUSTER clause.
+	Cluster string
+	// Engine is a ClickHouse specific field for the table engine name, e.g. ReplicatedMergeTree.
+	Engine string
+	// SortingKey is a ClickHouse specific field for the ORDER BY expression of the MergeTree family engines.
+	// If ORDER BY is absent, it's the PRIMARY KEY expression.
+	SortingKey string
 }
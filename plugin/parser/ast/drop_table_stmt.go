@@ -7,4 +7,6 @@ type DropTableStmt struct {
 	IfExists  bool
 	Behavior  DropBehavior
 	TableList []*TableDef
+	// Cluster is a ClickHouse specific field for the ON CLUSTER clause.
+	Cluster string
 }
@@ -0,0 +1,21 @@
+package ast
+
+// MutationType is the type for mutation.
+type MutationType int
+
+const (
+	// MutationTypeUpdate is the mutation type for ALTER TABLE UPDATE.
+	MutationTypeUpdate MutationType = iota
+	// MutationTypeDelete is the mutation type for ALTER TABLE DELETE.
+	MutationTypeDelete
+)
+
+// MutationStmt is the struct for ClickHouse mutation statement.
+// It's ALTER TABLE UPDATE and ALTER TABLE DELETE, which rewrite all data parts containing the affected rows in the background.
+type MutationStmt struct {
+	node
+
+	Table       *TableDef
+	Type        MutationType
+	WhereClause ExpressionNode
+}
@@ -26,4 +26,9 @@ type TableDef struct {
 	Schema string
 	// Name is the name of the table.
 	Name string
+	// QuotedSchema and QuotedName are true if the schema and the table name are the quoted identifiers,
+	// which keep the case in the engines folding the unquoted identifiers, e.g. Snowflake.
+	// They're only set by the standard parser engine.
+	QuotedSchema bool
+	QuotedName   bool
 }
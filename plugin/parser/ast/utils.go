@@ -133,6 +133,13 @@ func Walk(v Visitor, node Node) {
 		if n.Select != nil {
 			Walk(v, n.Select)
 		}
+	case *MutationStmt:
+		if n.Table != nil {
+			Walk(v, n.Table)
+		}
+		if n.WhereClause != nil {
+			Walk(v, n.WhereClause)
+		}
 	case *PatternLikeDef:
 		if n.Expression != nil {
 			Walk(v, n.Expression)
@@ -0,0 +1,10 @@
+package ast
+
+// VariableSetStmt is the struct for the statement setting the run-time parameter, e.g. SET search_path TO public.
+type VariableSetStmt struct {
+	node
+
+	Name string
+	// ValueList is empty if the parameter is reset to the default value, e.g. RESET search_path.
+	ValueList []string
+}
@@ -251,7 +251,7 @@ func convert(node *pgquery.Node, statement parser.SingleSQL) (res ast.Node, err
 			indexDef.KeyList = append(indexDef.KeyList, indexKey)
 		}
 
-		return &ast.CreateIndexStmt{Index: indexDef, IfNotExists: in.IndexStmt.IfNotExists}, nil
+		return &ast.CreateIndexStmt{Index: indexDef, IfNotExists: in.IndexStmt.IfNotExists, Concurrently: in.IndexStmt.Concurrent}, nil
 	case *pgquery.Node_DropStmt:
 		switch in.DropStmt.RemoveType {
 		case pgquery.ObjectType_OBJECT_INDEX:
@@ -610,6 +610,22 @@ func convert(node *pgquery.Node, statement parser.SingleSQL) (res ast.Node, err
 		}
 
 		return &copyStmt, nil
+	case *pgquery.Node_VariableSetStmt:
+		variableSetStmt := &ast.VariableSetStmt{
+			Name: in.VariableSetStmt.Name,
+		}
+		if in.VariableSetStmt.Kind == pgquery.VariableSetKind_VAR_SET_VALUE {
+			for _, arg := range in.VariableSetStmt.Args {
+				value, ok := convertVariableSetValue(arg)
+				if !ok {
+					// The value can be an expression for some parameters, e.g. SET TIME ZONE INTERVAL '+00:00' HOUR TO MINUTE.
+					return &ast.UnconvertedStmt{}, nil
+				}
+				variableSetStmt.ValueList = append(variableSetStmt.ValueList, value)
+			}
+		}
+
+		return variableSetStmt, nil
 	case *pgquery.Node_CommentStmt:
 		commentStmt := ast.CommentStmt{
 			Comment: in.CommentStmt.Comment,
@@ -754,6 +770,24 @@ func convert(node *pgquery.Node, statement parser.SingleSQL) (res ast.Node, err
 	return nil, nil
 }
 
+// convertVariableSetValue converts the value of SET statement, returns false if it isn't a string or number constant.
+func convertVariableSetValue(node *pgquery.Node) (string, bool) {
+	constant, ok := node.Node.(*pgquery.Node_AConst)
+	if !ok {
+		return "", false
+	}
+	switch value := constant.AConst.Val.Node.(type) {
+	case *pgquery.Node_String_:
+		return value.String_.Str, true
+	case *pgquery.Node_Integer:
+		return strconv.FormatInt(int64(value.Integer.Ival), 10), true
+	case *pgquery.Node_Float:
+		return value.Float.Str, true
+	default:
+		return "", false
+	}
+}
+
 func convertEnumLabelList(list []*pgquery.Node) ([]string, error) {
 	var result []string
 	for _, node := range list {
@@ -809,6 +809,33 @@ func TestPGCreateIndexStmt(t *testing.T) {
 				},
 			},
 		},
+		{
+			stmt: "CREATE INDEX CONCURRENTLY idx_id ON tech_book (id)",
+			want: []ast.Node{
+				&ast.CreateIndexStmt{
+					Concurrently: true,
+					Index: &ast.IndexDef{
+						Name:   "idx_id",
+						Table:  &ast.TableDef{Name: "tech_book"},
+						Unique: false,
+						KeyList: []*ast.IndexKeyDef{
+							{
+								Type:      ast.IndexKeyTypeColumn,
+								Key:       "id",
+								SortOrder: ast.SortOrderTypeDefault,
+								NullOrder: ast.NullOrderTypeDefault,
+							},
+						},
+					},
+				},
+			},
+			statementList: []parser.SingleSQL{
+				{
+					Text:     "CREATE INDEX CONCURRENTLY idx_id ON tech_book (id)",
+					LastLine: 1,
+				},
+			},
+		},
 	}
 
 	runTests(t, tests)
@@ -2131,6 +2158,48 @@ func TestUnconvertStmt(t *testing.T) {
 	runTests(t, tests)
 }
 
+func TestVariableSetStmt(t *testing.T) {
+	tests := []testData{
+		{
+			stmt: `SET search_path TO foo, "Bar", public`,
+			want: []ast.Node{&ast.VariableSetStmt{
+				Name:      "search_path",
+				ValueList: []string{"foo", "Bar", "public"},
+			}},
+			statementList: []parser.SingleSQL{
+				{
+					Text:     `SET search_path TO foo, "Bar", public`,
+					LastLine: 1,
+				},
+			},
+		},
+		{
+			stmt: "RESET search_path",
+			want: []ast.Node{&ast.VariableSetStmt{
+				Name: "search_path",
+			}},
+			statementList: []parser.SingleSQL{
+				{
+					Text:     "RESET search_path",
+					LastLine: 1,
+				},
+			},
+		},
+		{
+			stmt: "SET TIME ZONE INTERVAL '+00:00' HOUR TO MINUTE",
+			want: []ast.Node{&ast.UnconvertedStmt{}},
+			statementList: []parser.SingleSQL{
+				{
+					Text:     "SET TIME ZONE INTERVAL '+00:00' HOUR TO MINUTE",
+					LastLine: 1,
+				},
+			},
+		},
+	}
+
+	runTests(t, tests)
+}
+
 func TestCommentStmt(t *testing.T) {
 	tests := []testData{
 		{
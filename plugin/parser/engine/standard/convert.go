@@ -0,0 +1,724 @@
+package standard
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/plugin/parser/ast"
+)
+
+var (
+	// statementKeywordList is the list of keywords starting a statement in Snowflake or ClickHouse.
+	// A statement starting with other words is a syntax error.
+	statementKeywordList = map[string]bool{
+		"ALTER": true, "ATTACH": true, "BEGIN": true, "CALL": true, "CHECK": true, "COMMENT": true,
+		"COMMIT": true, "COPY": true, "CREATE": true, "DELETE": true, "DESC": true, "DESCRIBE": true,
+		"DETACH": true, "DROP": true, "EXCHANGE": true, "EXECUTE": true, "EXISTS": true, "EXPLAIN": true,
+		"GET": true, "GRANT": true, "INSERT": true, "KILL": true, "LIST": true, "MERGE": true,
+		"OPTIMIZE": true, "PUT": true, "REMOVE": true, "RENAME": true, "REVOKE": true, "ROLLBACK": true,
+		"SELECT": true, "SET": true, "SHOW": true, "START": true, "SYSTEM": true, "TRUNCATE": true,
+		"UNDROP": true, "UNSET": true, "UPDATE": true, "USE": true, "VALUES": true, "WATCH": true,
+		"WITH": true,
+	}
+	// columnOptionKeywordList is the list of keywords ending the data type in the column definition.
+	columnOptionKeywordList = map[string]bool{
+		"AFTER": true, "ALIAS": true, "AUTOINCREMENT": true, "CHECK": true, "CODEC": true, "COLLATE": true,
+		"COMMENT": true, "CONSTRAINT": true, "DEFAULT": true, "EPHEMERAL": true, "FIRST": true, "FOREIGN": true,
+		"IDENTITY": true, "MASKING": true, "MATERIALIZED": true, "NOT": true, "NULL": true, "PRIMARY": true,
+		"REFERENCES": true, "SETTINGS": true, "TAG": true, "TTL": true, "UNIQUE": true, "WITH": true,
+	}
+	// tableElementKeywordList is the list of keywords starting a table element other than the column.
+	tableElementKeywordList = map[string]bool{
+		"CHECK": true, "CONSTRAINT": true, "FOREIGN": true, "INDEX": true, "PRIMARY": true, "PROJECTION": true,
+		"UNIQUE": true,
+	}
+	// tableClauseKeywordList is the list of keywords starting a clause after the table elements.
+	tableClauseKeywordList = map[string]bool{
+		"AS": true, "CHANGE_TRACKING": true, "CLUSTER": true, "COMMENT": true, "COPY": true, "DATA_RETENTION_TIME_IN_DAYS": true,
+		"DEFAULT_DDL_COLLATION": true, "EMPTY": true, "ENGINE": true, "ORDER": true, "PARTITION": true, "PRIMARY": true,
+		"SAMPLE": true, "SETTINGS": true, "STAGE_COPY_OPTIONS": true, "STAGE_FILE_FORMAT": true, "TTL": true, "WITH": true,
+	}
+	// alterActionKeywordList is the list of keywords starting an ALTER TABLE action.
+	// Snowflake allows "ADD COLUMN a INT, b INT", so the item not starting with these keywords continues the previous action.
+	alterActionKeywordList = map[string]bool{
+		"ADD": true, "ALTER": true, "APPLY": true, "ATTACH": true, "CLEAR": true, "CLUSTER": true,
+		"COMMENT": true, "DELETE": true, "DETACH": true, "DROP": true, "FETCH": true, "FREEZE": true,
+		"MATERIALIZE": true, "MODIFY": true, "MOVE": true, "RECLUSTER": true, "REMOVE": true, "RENAME": true,
+		"REPLACE": true, "RESET": true, "RESUME": true, "SET": true, "SUSPEND": true, "SWAP": true,
+		"UNFREEZE": true, "UNSET": true, "UPDATE": true,
+	}
+	// selectClauseKeywordList is the list of keywords ending the field list or the WHERE clause of SELECT.
+	selectClauseKeywordList = []string{"FORMAT", "FROM", "GROUP", "HAVING", "INTO", "LIMIT", "ORDER", "QUALIFY", "SETTINGS", "WHERE", "WINDOW"}
+	setOperationKeywordList = []string{"EXCEPT", "INTERSECT", "MINUS", "UNION"}
+)
+
+// converter converts the tokens of one statement, without the ending semicolon, to the AST node.
+type converter struct {
+	dialect  dialect
+	buf      []rune
+	tokens   []*token
+	pos      int
+	lastLine int
+}
+
+func (c *converter) convert() (ast.Node, error) {
+	if err := c.checkParentheses(); err != nil {
+		return nil, err
+	}
+
+	first := c.tokens[0]
+	if first.isOperator("(") {
+		return c.convertSelect(0, len(c.tokens)), nil
+	}
+	keyword := strings.ToUpper(first.text)
+	if first.tp != tokenWord || !statementKeywordList[keyword] {
+		return nil, c.syntaxError()
+	}
+	switch keyword {
+	case "SELECT", "WITH":
+		return c.convertSelect(0, len(c.tokens)), nil
+	case "INSERT":
+		return c.convertInsert()
+	case "DELETE":
+		return c.convertDelete()
+	case "UPDATE":
+		return c.convertUpdate()
+	case "CREATE":
+		return c.convertCreate()
+	case "ALTER":
+		return c.convertAlter()
+	case "DROP":
+		return c.convertDrop()
+	}
+	return &ast.UnconvertedStmt{}, nil
+}
+
+func (c *converter) checkParentheses() error {
+	depth := 0
+	for i, tk := range c.tokens {
+		switch {
+		case tk.isOperator("("):
+			depth++
+		case tk.isOperator(")"):
+			if depth == 0 {
+				c.pos = i
+				return c.syntaxError()
+			}
+			depth--
+		}
+	}
+	if depth > 0 {
+		c.pos = len(c.tokens)
+		return c.syntaxError()
+	}
+	return nil
+}
+
+// convertSelect converts the tokens in [from, to) to the SELECT statement.
+func (c *converter) convertSelect(from, to int) *ast.SelectStmt {
+	stmt := &ast.SelectStmt{}
+	if from >= to {
+		return stmt
+	}
+	if c.tokens[from].isOperator("(") && c.matchParen(from) == to-1 {
+		return c.convertSelect(from+1, to-1)
+	}
+	if i := c.findLastTopLevel(from, to, setOperationKeywordList...); i > from {
+		switch strings.ToUpper(c.tokens[i].text) {
+		case "UNION":
+			stmt.SetOperation = ast.SetOperationTypeUnion
+		case "INTERSECT":
+			stmt.SetOperation = ast.SetOperationTypeIntersect
+		default:
+			stmt.SetOperation = ast.SetOperationTypeExcept
+		}
+		stmt.LQuery = c.convertSelect(from, i)
+		right := i + 1
+		for right < to && (c.tokens[right].isKeyword("ALL") || c.tokens[right].isKeyword("DISTINCT")) {
+			right++
+		}
+		stmt.RQuery = c.convertSelect(right, to)
+		return stmt
+	}
+
+	stmt.SubqueryList = c.collectSubqueryList(from, to)
+	selectIndex := c.findTopLevel(from, to, "SELECT")
+	if selectIndex < 0 {
+		return stmt
+	}
+	fieldStart := selectIndex + 1
+	for fieldStart < to && (c.tokens[fieldStart].isKeyword("DISTINCT") || c.tokens[fieldStart].isKeyword("ALL")) {
+		fieldStart++
+	}
+	// Snowflake SELECT TOP n.
+	if fieldStart+1 < to && c.tokens[fieldStart].isKeyword("TOP") && c.tokens[fieldStart+1].tp == tokenNumber {
+		fieldStart += 2
+	}
+	fieldEnd := c.findTopLevel(fieldStart, to, selectClauseKeywordList...)
+	if fieldEnd < 0 {
+		fieldEnd = to
+	}
+	for _, field := range c.splitTopLevel(fieldStart, fieldEnd, ",") {
+		stmt.FieldList = append(stmt.FieldList, c.convertField(field[0], field[1]))
+	}
+	stmt.WhereClause = c.convertWhereClause(fieldEnd, to)
+	return stmt
+}
+
+// convertField converts the tokens in [from, to) to the SELECT field.
+// The "*" and "t.*" are converted to the column name "*", including ClickHouse "* EXCEPT (a)" and Snowflake "* EXCLUDE a".
+func (c *converter) convertField(from, to int) ast.ExpressionNode {
+	if c.tokens[from].isOperator("*") {
+		return &ast.ColumnNameDef{ColumnName: "*"}
+	}
+	for i := from; i+2 < to; i += 2 {
+		if !c.tokens[i].isIdentifier() || !c.tokens[i+1].isOperator(".") {
+			break
+		}
+		if c.tokens[i+2].isOperator("*") {
+			return &ast.ColumnNameDef{
+				Table:      c.tableDef(from, i+1, ast.TableTypeUnknown),
+				ColumnName: "*",
+			}
+		}
+	}
+	return c.newUnconvertedExpression(from, to)
+}
+
+// convertWhereClause converts the top-level WHERE clause in [from, to).
+func (c *converter) convertWhereClause(from, to int) ast.ExpressionNode {
+	whereIndex := c.findTopLevel(from, to, "WHERE")
+	if whereIndex < 0 {
+		return nil
+	}
+	whereEnd := c.findTopLevel(whereIndex+1, to, selectClauseKeywordList...)
+	if whereEnd < 0 {
+		whereEnd = to
+	}
+	return c.newUnconvertedExpression(whereIndex+1, whereEnd)
+}
+
+// collectSubqueryList collects the parenthesized SELECT in [from, to) as subqueries.
+func (c *converter) collectSubqueryList(from, to int) []*ast.SubqueryDef {
+	var res []*ast.SubqueryDef
+	for i := from; i < to; i++ {
+		if !c.tokens[i].isOperator("(") || i+1 >= to {
+			continue
+		}
+		if next := c.tokens[i+1]; next.isKeyword("SELECT") || next.isKeyword("WITH") {
+			match := c.matchParen(i)
+			res = append(res, &ast.SubqueryDef{Select: c.convertSelect(i+1, match)})
+			i = match
+		}
+	}
+	return res
+}
+
+func (c *converter) convertInsert() (ast.Node, error) {
+	c.pos = 1
+	c.acceptKeyword("OVERWRITE")
+	if !c.acceptKeyword("INTO") {
+		// Snowflake multi-table INSERT.
+		return &ast.UnconvertedStmt{}, nil
+	}
+	c.acceptKeyword("TABLE")
+	table, err := c.tableName(ast.TableTypeBaseTable)
+	if err != nil {
+		return nil, err
+	}
+	stmt := &ast.InsertStmt{Table: table}
+	if i := c.findTopLevel(c.pos, len(c.tokens), "SELECT", "WITH"); i >= 0 {
+		stmt.Select = c.convertSelect(i, len(c.tokens))
+	}
+	return stmt, nil
+}
+
+func (c *converter) convertDelete() (ast.Node, error) {
+	c.pos = 1
+	c.acceptKeyword("FROM")
+	table, err := c.tableName(ast.TableTypeBaseTable)
+	if err != nil {
+		return nil, err
+	}
+	return &ast.DeleteStmt{
+		Table:        table,
+		WhereClause:  c.convertWhereClause(c.pos, len(c.tokens)),
+		SubqueryList: c.collectSubqueryList(c.pos, len(c.tokens)),
+	}, nil
+}
+
+func (c *converter) convertUpdate() (ast.Node, error) {
+	c.pos = 1
+	table, err := c.tableName(ast.TableTypeBaseTable)
+	if err != nil {
+		return nil, err
+	}
+	return &ast.UpdateStmt{
+		Table:        table,
+		WhereClause:  c.convertWhereClause(c.pos, len(c.tokens)),
+		SubqueryList: c.collectSubqueryList(c.pos, len(c.tokens)),
+	}, nil
+}
+
+func (c *converter) convertCreate() (ast.Node, error) {
+	c.pos = 1
+	c.acceptKeyword("OR", "REPLACE")
+	for _, keyword := range []string{"LOCAL", "GLOBAL", "TEMPORARY", "TEMP", "VOLATILE", "TRANSIENT"} {
+		c.acceptKeyword(keyword)
+	}
+	if !c.acceptKeyword("TABLE") {
+		return &ast.UnconvertedStmt{}, nil
+	}
+	stmt := &ast.CreateTableStmt{}
+	stmt.IfNotExists = c.acceptKeyword("IF", "NOT", "EXISTS")
+	table, err := c.tableName(ast.TableTypeBaseTable)
+	if err != nil {
+		return nil, err
+	}
+	stmt.Name = table
+	if stmt.Cluster, err = c.onCluster(); err != nil {
+		return nil, err
+	}
+
+	if tk := c.peek(0); tk != nil && tk.isOperator("(") {
+		match := c.matchParen(c.pos)
+		for _, element := range c.splitTopLevel(c.pos+1, match, ",") {
+			first := c.tokens[element[0]]
+			if first.tp == tokenWord && tableElementKeywordList[strings.ToUpper(first.text)] {
+				continue
+			}
+			column, err := c.convertColumnDef(element[0], element[1])
+			if err != nil {
+				return nil, err
+			}
+			stmt.ColumnList = append(stmt.ColumnList, column)
+		}
+		c.pos = match + 1
+	}
+
+	var primaryKey string
+	for c.peek(0) != nil {
+		switch {
+		case c.acceptKeyword("ENGINE"):
+			c.acceptOperator("=")
+			if tk := c.peek(0); tk != nil && tk.isIdentifier() {
+				stmt.Engine = tk.value
+			}
+			c.skipTableClause()
+		case c.acceptKeyword("ORDER", "BY"):
+			stmt.SortingKey = c.skipTableClause()
+		case c.acceptKeyword("PRIMARY", "KEY"):
+			primaryKey = c.skipTableClause()
+		case c.acceptKeyword("COMMENT"):
+			c.acceptOperator("=")
+			if tk := c.peek(0); tk != nil && tk.tp == tokenString {
+				stmt.Comment = tk.value
+			}
+			c.skipTableClause()
+		case c.peekKeyword("AS"):
+			// CREATE TABLE AS SELECT, the rest is the query.
+			c.pos = len(c.tokens)
+		default:
+			if c.skipTableClause() == "" {
+				c.pos++
+			}
+		}
+	}
+	if stmt.SortingKey == "" {
+		stmt.SortingKey = primaryKey
+	}
+	return stmt, nil
+}
+
+// skipTableClause skips to the next table clause and returns the text of the skipped tokens.
+func (c *converter) skipTableClause() string {
+	start := c.pos
+	for tk := c.peek(0); tk != nil; tk = c.peek(0) {
+		if tk.tp == tokenWord && tableClauseKeywordList[strings.ToUpper(tk.text)] {
+			break
+		}
+		if tk.isOperator("(") {
+			c.pos = c.matchParen(c.pos)
+		}
+		c.pos++
+	}
+	return c.text(start, c.pos)
+}
+
+// convertColumnDef converts the tokens in [from, to) to the column definition.
+func (c *converter) convertColumnDef(from, to int) (*ast.ColumnDef, error) {
+	c.pos = from
+	c.acceptKeyword("IF", "NOT", "EXISTS")
+	name, err := c.identifier()
+	if err != nil {
+		return nil, err
+	}
+	column := &ast.ColumnDef{ColumnName: name}
+	typeStart := c.pos
+	for c.pos < to {
+		tk := c.tokens[c.pos]
+		if tk.tp == tokenWord && columnOptionKeywordList[strings.ToUpper(tk.text)] {
+			break
+		}
+		if tk.isOperator("(") {
+			c.pos = c.matchParen(c.pos)
+		}
+		c.pos++
+	}
+	if c.pos > typeStart {
+		tp := &ast.UnconvertedDataType{Name: []string{c.tokens[typeStart].value}}
+		tp.SetText(c.text(typeStart, c.pos))
+		column.Type = tp
+	}
+	for i := c.pos; i+1 < to; i++ {
+		if !c.tokens[i].isKeyword("COMMENT") {
+			continue
+		}
+		value := c.tokens[i+1]
+		if value.isOperator("=") && i+2 < to {
+			value = c.tokens[i+2]
+		}
+		if value.tp == tokenString {
+			column.Comment = value.value
+		}
+		break
+	}
+	column.SetLastLine(c.tokens[to-1].line)
+	return column, nil
+}
+
+func (c *converter) convertAlter() (ast.Node, error) {
+	c.pos = 1
+	if !c.acceptKeyword("TABLE") {
+		return &ast.UnconvertedStmt{}, nil
+	}
+	c.acceptKeyword("IF", "EXISTS")
+	table, err := c.tableName(ast.TableTypeBaseTable)
+	if err != nil {
+		return nil, err
+	}
+	stmt := &ast.AlterTableStmt{Table: table}
+	if stmt.Cluster, err = c.onCluster(); err != nil {
+		return nil, err
+	}
+
+	// ClickHouse mutation "ALTER TABLE t UPDATE a = 1, b = 2 WHERE c = 3", the assignments are separated by commas.
+	if c.dialect == dialectClickHouse && (c.peekKeyword("UPDATE") || c.peekKeyword("DELETE")) {
+		mutation := &ast.MutationStmt{
+			Table:       table,
+			Type:        ast.MutationTypeUpdate,
+			WhereClause: c.convertWhereClause(c.pos, len(c.tokens)),
+		}
+		if c.peekKeyword("DELETE") {
+			mutation.Type = ast.MutationTypeDelete
+		}
+		mutation.SetLastLine(c.lastLine)
+		stmt.AlterItemList = append(stmt.AlterItemList, mutation)
+		return stmt, nil
+	}
+
+	var last ast.Node
+	for _, item := range c.splitTopLevel(c.pos, len(c.tokens), ",") {
+		c.pos = item[0]
+		first := c.tokens[item[0]]
+		var node ast.Node
+		switch {
+		case c.acceptKeyword("ADD"):
+			if !c.acceptKeyword("COLUMN") {
+				if tk := c.peek(0); tk == nil || (tk.tp == tokenWord && tableElementKeywordList[strings.ToUpper(tk.text)]) {
+					break
+				}
+			}
+			column, err := c.convertColumnDef(c.pos, item[1])
+			if err != nil {
+				return nil, err
+			}
+			node = &ast.AddColumnListStmt{Table: table, ColumnList: []*ast.ColumnDef{column}}
+		case c.acceptKeyword("DROP"):
+			if !c.acceptKeyword("COLUMN") {
+				break
+			}
+			c.acceptKeyword("IF", "EXISTS")
+			name, err := c.identifier()
+			if err != nil {
+				return nil, err
+			}
+			node = &ast.DropColumnStmt{Table: table, ColumnName: name}
+		case c.acceptKeyword("RENAME", "COLUMN"):
+			c.acceptKeyword("IF", "EXISTS")
+			name, err := c.identifier()
+			if err != nil {
+				return nil, err
+			}
+			if !c.acceptKeyword("TO") {
+				return nil, c.syntaxError()
+			}
+			newName, err := c.identifier()
+			if err != nil {
+				return nil, err
+			}
+			node = &ast.RenameColumnStmt{Table: table, ColumnName: name, NewName: newName}
+		case c.acceptKeyword("RENAME", "TO"):
+			newTable, err := c.tableName(ast.TableTypeBaseTable)
+			if err != nil {
+				return nil, err
+			}
+			node = &ast.RenameTableStmt{Table: table, NewName: newTable.Name}
+		case first.tp == tokenWord && alterActionKeywordList[strings.ToUpper(first.text)]:
+			// Other actions are not converted.
+		default:
+			// Continue the previous ADD COLUMN or DROP COLUMN.
+			switch previous := last.(type) {
+			case *ast.AddColumnListStmt:
+				column, err := c.convertColumnDef(item[0], item[1])
+				if err != nil {
+					return nil, err
+				}
+				previous.ColumnList = append(previous.ColumnList, column)
+			case *ast.DropColumnStmt:
+				name, err := c.identifier()
+				if err != nil {
+					return nil, err
+				}
+				node = &ast.DropColumnStmt{Table: table, ColumnName: name}
+			}
+			if node == nil {
+				continue
+			}
+		}
+		last = node
+		if node != nil {
+			node.SetLastLine(c.lastLine)
+			stmt.AlterItemList = append(stmt.AlterItemList, node)
+		}
+	}
+	return stmt, nil
+}
+
+func (c *converter) convertDrop() (ast.Node, error) {
+	c.pos = 1
+	c.acceptKeyword("TEMPORARY")
+	if !c.acceptKeyword("TABLE") {
+		return &ast.UnconvertedStmt{}, nil
+	}
+	stmt := &ast.DropTableStmt{}
+	stmt.IfExists = c.acceptKeyword("IF", "EXISTS")
+	for {
+		table, err := c.tableName(ast.TableTypeBaseTable)
+		if err != nil {
+			return nil, err
+		}
+		stmt.TableList = append(stmt.TableList, table)
+		if !c.acceptOperator(",") {
+			break
+		}
+	}
+	cluster, err := c.onCluster()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Cluster = cluster
+	switch {
+	case c.acceptKeyword("CASCADE"):
+		stmt.Behavior = ast.DropBehaviorCascade
+	case c.acceptKeyword("RESTRICT"):
+		stmt.Behavior = ast.DropBehaviorRestrict
+	}
+	return stmt, nil
+}
+
+// onCluster converts the ClickHouse ON CLUSTER clause and returns the cluster name.
+func (c *converter) onCluster() (string, error) {
+	if c.dialect != dialectClickHouse || !c.acceptKeyword("ON", "CLUSTER") {
+		return "", nil
+	}
+	tk := c.peek(0)
+	if tk == nil || (!tk.isIdentifier() && tk.tp != tokenString) {
+		return "", c.syntaxError()
+	}
+	c.pos++
+	return tk.value, nil
+}
+
+// tableName converts the table name at the current position.
+// It's "database.schema.table" for Snowflake and "database.table" for ClickHouse.
+func (c *converter) tableName(tableType ast.TableType) (*ast.TableDef, error) {
+	start := c.pos
+	for {
+		if _, err := c.identifier(); err != nil {
+			return nil, err
+		}
+		if !c.acceptOperator(".") {
+			break
+		}
+	}
+	maxLength := 3
+	if c.dialect == dialectClickHouse {
+		maxLength = 2
+	}
+	if len(c.identifierList(start, c.pos)) > maxLength {
+		c.pos = start
+		return nil, c.syntaxError()
+	}
+	return c.tableDef(start, c.pos, tableType), nil
+}
+
+// tableDef converts the table name in [from, to), which is the identifiers separated by dots.
+func (c *converter) tableDef(from, to int, tableType ast.TableType) *ast.TableDef {
+	nameList := c.identifierList(from, to)
+	// isQuoted returns whether the i-th identifier from the end is quoted.
+	isQuoted := func(i int) bool {
+		return c.tokens[from+2*(len(nameList)-1-i)].tp == tokenQuotedIdentifier
+	}
+	table := &ast.TableDef{Type: tableType, Name: nameList[len(nameList)-1], QuotedName: isQuoted(0)}
+	switch {
+	case len(nameList) == 3:
+		table.Database, table.Schema = nameList[0], nameList[1]
+		table.QuotedSchema = isQuoted(1)
+	case len(nameList) == 2 && c.dialect == dialectSnowflake:
+		table.Schema = nameList[0]
+		table.QuotedSchema = isQuoted(1)
+	case len(nameList) == 2:
+		table.Database = nameList[0]
+	}
+	return table
+}
+
+// identifierList returns the identifiers in [from, to) separated by dots.
+func (c *converter) identifierList(from, to int) []string {
+	var res []string
+	for i := from; i < to; i += 2 {
+		res = append(res, c.tokens[i].value)
+	}
+	return res
+}
+
+func (c *converter) identifier() (string, error) {
+	tk := c.peek(0)
+	if tk == nil || !tk.isIdentifier() {
+		return "", c.syntaxError()
+	}
+	c.pos++
+	return tk.value, nil
+}
+
+func (c *converter) peek(offset int) *token {
+	if c.pos+offset >= len(c.tokens) {
+		return nil
+	}
+	return c.tokens[c.pos+offset]
+}
+
+// peekKeyword returns true if the following tokens are the keyword sequence.
+func (c *converter) peekKeyword(keywordList ...string) bool {
+	for i, keyword := range keywordList {
+		if tk := c.peek(i); tk == nil || !tk.isKeyword(keyword) {
+			return false
+		}
+	}
+	return true
+}
+
+// acceptKeyword consumes the keyword sequence if the following tokens are the keyword sequence.
+func (c *converter) acceptKeyword(keywordList ...string) bool {
+	if !c.peekKeyword(keywordList...) {
+		return false
+	}
+	c.pos += len(keywordList)
+	return true
+}
+
+func (c *converter) acceptOperator(operator string) bool {
+	if tk := c.peek(0); tk == nil || !tk.isOperator(operator) {
+		return false
+	}
+	c.pos++
+	return true
+}
+
+// matchParen returns the index of the right parenthesis matching the left parenthesis at index i.
+// The parentheses are checked to be balanced before converting.
+func (c *converter) matchParen(i int) int {
+	depth := 0
+	for ; i < len(c.tokens); i++ {
+		switch {
+		case c.tokens[i].isOperator("("):
+			depth++
+		case c.tokens[i].isOperator(")"):
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(c.tokens) - 1
+}
+
+// findTopLevel returns the index of the first top-level keyword in [from, to), or -1 if not found.
+func (c *converter) findTopLevel(from, to int, keywordList ...string) int {
+	for i := from; i < to; i++ {
+		if c.tokens[i].isOperator("(") {
+			i = c.matchParen(i)
+			continue
+		}
+		for _, keyword := range keywordList {
+			if c.tokens[i].isKeyword(keyword) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// findLastTopLevel returns the index of the last top-level keyword in [from, to), or -1 if not found.
+func (c *converter) findLastTopLevel(from, to int, keywordList ...string) int {
+	res := -1
+	for i := from; i < to; i++ {
+		if i = c.findTopLevel(i, to, keywordList...); i < 0 {
+			break
+		}
+		res = i
+	}
+	return res
+}
+
+// splitTopLevel splits [from, to) by the top-level operator and returns the non-empty ranges.
+func (c *converter) splitTopLevel(from, to int, operator string) [][2]int {
+	var res [][2]int
+	start := from
+	for i := from; i <= to; i++ {
+		if i < to && c.tokens[i].isOperator("(") {
+			i = c.matchParen(i)
+			continue
+		}
+		if i == to || c.tokens[i].isOperator(operator) {
+			if i > start {
+				res = append(res, [2]int{start, i})
+			}
+			start = i + 1
+		}
+	}
+	return res
+}
+
+func (c *converter) newUnconvertedExpression(from, to int) *ast.UnconvertedExpressionDef {
+	expression := &ast.UnconvertedExpressionDef{}
+	expression.SetText(c.text(from, to))
+	return expression
+}
+
+// text returns the original text of the tokens in [from, to).
+func (c *converter) text(from, to int) string {
+	if from >= to {
+		return ""
+	}
+	return string(c.buf[c.tokens[from].start:c.tokens[to-1].end])
+}
+
+func (c *converter) syntaxError() error {
+	if tk := c.peek(0); tk != nil {
+		return errors.Errorf("line %d: syntax error at or near \"%s\"", tk.line, tk.text)
+	}
+	return errors.Errorf("line %d: syntax error at end of input", c.lastLine)
+}
@@ -0,0 +1,468 @@
+package standard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/parser"
+	"github.com/bytebase/bytebase/plugin/parser/ast"
+)
+
+type testData struct {
+	stmt          string
+	want          []ast.Node
+	statementList []parser.SingleSQL
+	columnLine    [][]int
+}
+
+func runTests(t *testing.T, engineType parser.EngineType, tests []testData) {
+	for _, test := range tests {
+		res, err := parser.Parse(engineType, parser.ParseContext{}, test.stmt)
+		require.NoError(t, err)
+		for i := range test.want {
+			test.want[i].SetText(test.statementList[i].Text)
+			test.want[i].SetLastLine(test.statementList[i].LastLine)
+
+			switch n := test.want[i].(type) {
+			case *ast.CreateTableStmt:
+				for j, col := range n.ColumnList {
+					col.SetLastLine(test.columnLine[i][j])
+				}
+			case *ast.AlterTableStmt:
+				for _, item := range n.AlterItemList {
+					item.SetLastLine(n.LastLine())
+					if addColumn, ok := item.(*ast.AddColumnListStmt); ok {
+						for _, col := range addColumn.ColumnList {
+							col.SetLastLine(n.LastLine())
+						}
+					}
+				}
+			}
+		}
+		require.Equal(t, test.want, res, test.stmt)
+	}
+}
+
+func newUnconvertedDataType(name string, text string) *ast.UnconvertedDataType {
+	tp := &ast.UnconvertedDataType{Name: []string{name}}
+	tp.SetText(text)
+	return tp
+}
+
+func newUnconvertedExpression(text string) *ast.UnconvertedExpressionDef {
+	expression := &ast.UnconvertedExpressionDef{}
+	expression.SetText(text)
+	return expression
+}
+
+func TestSnowflakeConvertCreateTableStmt(t *testing.T) {
+	tests := []testData{
+		{
+			stmt: `
+				CREATE OR REPLACE TRANSIENT TABLE db1.public."Book" (
+					id NUMBER(38, 0) NOT NULL COMMENT 'ID',
+					name VARCHAR,
+					PRIMARY KEY (id)
+				) COMMENT = 'book table';
+				CREATE TABLE IF NOT EXISTS author AS SELECT * FROM person;`,
+			want: []ast.Node{
+				&ast.CreateTableStmt{
+					Name: &ast.TableDef{
+						Type:       ast.TableTypeBaseTable,
+						Database:   "db1",
+						Schema:     "public",
+						Name:       "Book",
+						QuotedName: true,
+					},
+					ColumnList: []*ast.ColumnDef{
+						{
+							ColumnName: "id",
+							Type:       newUnconvertedDataType("NUMBER", "NUMBER(38, 0)"),
+							Comment:    "ID",
+						},
+						{
+							ColumnName: "name",
+							Type:       newUnconvertedDataType("VARCHAR", "VARCHAR"),
+						},
+					},
+					Comment: "book table",
+				},
+				&ast.CreateTableStmt{
+					IfNotExists: true,
+					Name: &ast.TableDef{
+						Type: ast.TableTypeBaseTable,
+						Name: "author",
+					},
+				},
+			},
+			statementList: []parser.SingleSQL{
+				{
+					Text: `CREATE OR REPLACE TRANSIENT TABLE db1.public."Book" (
+					id NUMBER(38, 0) NOT NULL COMMENT 'ID',
+					name VARCHAR,
+					PRIMARY KEY (id)
+				) COMMENT = 'book table';`,
+					LastLine: 6,
+				},
+				{
+					Text:     `CREATE TABLE IF NOT EXISTS author AS SELECT * FROM person;`,
+					LastLine: 7,
+				},
+			},
+			columnLine: [][]int{{3, 4}, {}},
+		},
+	}
+
+	runTests(t, parser.Snowflake, tests)
+}
+
+func TestClickHouseConvertCreateTableStmt(t *testing.T) {
+	tests := []testData{
+		{
+			stmt: "CREATE TABLE IF NOT EXISTS db1.`events` ON CLUSTER '{cluster}' (\n" +
+				"  `id` UInt64 COMMENT 'event id',\n" +
+				"  `name` LowCardinality(String) DEFAULT 'unknown',\n" +
+				"  INDEX idx_name name TYPE bloom_filter GRANULARITY 4\n" +
+				") ENGINE = ReplicatedMergeTree('/clickhouse/tables/{shard}/events', '{replica}')\n" +
+				"PARTITION BY toYYYYMM(ts)\n" +
+				"ORDER BY (id, name)\n" +
+				"COMMENT 'event table'",
+			want: []ast.Node{
+				&ast.CreateTableStmt{
+					IfNotExists: true,
+					Name: &ast.TableDef{
+						Type:       ast.TableTypeBaseTable,
+						Database:   "db1",
+						Name:       "events",
+						QuotedName: true,
+					},
+					ColumnList: []*ast.ColumnDef{
+						{
+							ColumnName: "id",
+							Type:       newUnconvertedDataType("UInt64", "UInt64"),
+							Comment:    "event id",
+						},
+						{
+							ColumnName: "name",
+							Type:       newUnconvertedDataType("LowCardinality", "LowCardinality(String)"),
+						},
+					},
+					Comment:    "event table",
+					Cluster:    "{cluster}",
+					Engine:     "ReplicatedMergeTree",
+					SortingKey: "(id, name)",
+				},
+			},
+			statementList: []parser.SingleSQL{
+				{
+					Text: "CREATE TABLE IF NOT EXISTS db1.`events` ON CLUSTER '{cluster}' (\n" +
+						"  `id` UInt64 COMMENT 'event id',\n" +
+						"  `name` LowCardinality(String) DEFAULT 'unknown',\n" +
+						"  INDEX idx_name name TYPE bloom_filter GRANULARITY 4\n" +
+						") ENGINE = ReplicatedMergeTree('/clickhouse/tables/{shard}/events', '{replica}')\n" +
+						"PARTITION BY toYYYYMM(ts)\n" +
+						"ORDER BY (id, name)\n" +
+						"COMMENT 'event table'",
+					LastLine: 8,
+				},
+			},
+			columnLine: [][]int{{2, 3}},
+		},
+		{
+			stmt: "CREATE TABLE t (a Int32) ENGINE = MergeTree PRIMARY KEY a",
+			want: []ast.Node{
+				&ast.CreateTableStmt{
+					Name: &ast.TableDef{
+						Type: ast.TableTypeBaseTable,
+						Name: "t",
+					},
+					ColumnList: []*ast.ColumnDef{
+						{
+							ColumnName: "a",
+							Type:       newUnconvertedDataType("Int32", "Int32"),
+						},
+					},
+					Engine:     "MergeTree",
+					SortingKey: "a",
+				},
+			},
+			statementList: []parser.SingleSQL{
+				{
+					Text:     "CREATE TABLE t (a Int32) ENGINE = MergeTree PRIMARY KEY a",
+					LastLine: 1,
+				},
+			},
+			columnLine: [][]int{{1}},
+		},
+	}
+
+	runTests(t, parser.ClickHouse, tests)
+}
+
+func TestSnowflakeConvertAlterTableStmt(t *testing.T) {
+	table := &ast.TableDef{Type: ast.TableTypeBaseTable, Name: "book"}
+	tests := []testData{
+		{
+			stmt: "ALTER TABLE book ADD COLUMN a INT COMMENT 'a', b TEXT",
+			want: []ast.Node{
+				&ast.AlterTableStmt{
+					Table: table,
+					AlterItemList: []ast.Node{
+						&ast.AddColumnListStmt{
+							Table: table,
+							ColumnList: []*ast.ColumnDef{
+								{
+									ColumnName: "a",
+									Type:       newUnconvertedDataType("INT", "INT"),
+									Comment:    "a",
+								},
+								{
+									ColumnName: "b",
+									Type:       newUnconvertedDataType("TEXT", "TEXT"),
+								},
+							},
+						},
+					},
+				},
+			},
+			statementList: []parser.SingleSQL{
+				{
+					Text:     "ALTER TABLE book ADD COLUMN a INT COMMENT 'a', b TEXT",
+					LastLine: 1,
+				},
+			},
+		},
+		{
+			stmt: "ALTER TABLE book RENAME COLUMN a TO b;\nALTER TABLE book RENAME TO book2",
+			want: []ast.Node{
+				&ast.AlterTableStmt{
+					Table: table,
+					AlterItemList: []ast.Node{
+						&ast.RenameColumnStmt{
+							Table:      table,
+							ColumnName: "a",
+							NewName:    "b",
+						},
+					},
+				},
+				&ast.AlterTableStmt{
+					Table: table,
+					AlterItemList: []ast.Node{
+						&ast.RenameTableStmt{
+							Table:   table,
+							NewName: "book2",
+						},
+					},
+				},
+			},
+			statementList: []parser.SingleSQL{
+				{
+					Text:     "ALTER TABLE book RENAME COLUMN a TO b;",
+					LastLine: 1,
+				},
+				{
+					Text:     "ALTER TABLE book RENAME TO book2",
+					LastLine: 2,
+				},
+			},
+		},
+	}
+
+	runTests(t, parser.Snowflake, tests)
+}
+
+func TestClickHouseConvertAlterTableStmt(t *testing.T) {
+	table := &ast.TableDef{Type: ast.TableTypeBaseTable, Name: "events"}
+	tests := []testData{
+		{
+			stmt: "ALTER TABLE events ON CLUSTER c1 DROP COLUMN IF EXISTS a, MODIFY COLUMN b String",
+			want: []ast.Node{
+				&ast.AlterTableStmt{
+					Table:   table,
+					Cluster: "c1",
+					AlterItemList: []ast.Node{
+						&ast.DropColumnStmt{
+							Table:      table,
+							ColumnName: "a",
+						},
+					},
+				},
+			},
+			statementList: []parser.SingleSQL{
+				{
+					Text:     "ALTER TABLE events ON CLUSTER c1 DROP COLUMN IF EXISTS a, MODIFY COLUMN b String",
+					LastLine: 1,
+				},
+			},
+		},
+		{
+			stmt: "ALTER TABLE events UPDATE a = 1, b = 2 WHERE id = 3;\nALTER TABLE events DELETE WHERE 1",
+			want: []ast.Node{
+				&ast.AlterTableStmt{
+					Table: table,
+					AlterItemList: []ast.Node{
+						&ast.MutationStmt{
+							Table:       table,
+							Type:        ast.MutationTypeUpdate,
+							WhereClause: newUnconvertedExpression("id = 3"),
+						},
+					},
+				},
+				&ast.AlterTableStmt{
+					Table: table,
+					AlterItemList: []ast.Node{
+						&ast.MutationStmt{
+							Table:       table,
+							Type:        ast.MutationTypeDelete,
+							WhereClause: newUnconvertedExpression("1"),
+						},
+					},
+				},
+			},
+			statementList: []parser.SingleSQL{
+				{
+					Text:     "ALTER TABLE events UPDATE a = 1, b = 2 WHERE id = 3;",
+					LastLine: 1,
+				},
+				{
+					Text:     "ALTER TABLE events DELETE WHERE 1",
+					LastLine: 2,
+				},
+			},
+		},
+	}
+
+	runTests(t, parser.ClickHouse, tests)
+}
+
+func TestClickHouseConvertDropTableStmt(t *testing.T) {
+	tests := []testData{
+		{
+			stmt: "DROP TABLE IF EXISTS db1.events ON CLUSTER c1 SYNC",
+			want: []ast.Node{
+				&ast.DropTableStmt{
+					IfExists: true,
+					TableList: []*ast.TableDef{
+						{
+							Type:     ast.TableTypeBaseTable,
+							Database: "db1",
+							Name:     "events",
+						},
+					},
+					Cluster: "c1",
+				},
+			},
+			statementList: []parser.SingleSQL{
+				{
+					Text:     "DROP TABLE IF EXISTS db1.events ON CLUSTER c1 SYNC",
+					LastLine: 1,
+				},
+			},
+		},
+	}
+
+	runTests(t, parser.ClickHouse, tests)
+}
+
+func TestSnowflakeConvertDMLStmt(t *testing.T) {
+	tests := []testData{
+		{
+			stmt: `
+				SELECT t.*, a FROM t WHERE a IN (SELECT * FROM s) ORDER BY a;
+				DELETE FROM t;
+				UPDATE t SET a = 1 WHERE b = 'x;y';
+				INSERT INTO t (a) SELECT a FROM s`,
+			want: []ast.Node{
+				&ast.SelectStmt{
+					FieldList: []ast.ExpressionNode{
+						&ast.ColumnNameDef{
+							Table:      &ast.TableDef{Name: "t"},
+							ColumnName: "*",
+						},
+						newUnconvertedExpression("a"),
+					},
+					WhereClause: newUnconvertedExpression("a IN (SELECT * FROM s)"),
+					SubqueryList: []*ast.SubqueryDef{
+						{
+							Select: &ast.SelectStmt{
+								FieldList: []ast.ExpressionNode{
+									&ast.ColumnNameDef{ColumnName: "*"},
+								},
+							},
+						},
+					},
+				},
+				&ast.DeleteStmt{
+					Table: &ast.TableDef{Type: ast.TableTypeBaseTable, Name: "t"},
+				},
+				&ast.UpdateStmt{
+					Table:       &ast.TableDef{Type: ast.TableTypeBaseTable, Name: "t"},
+					WhereClause: newUnconvertedExpression("b = 'x;y'"),
+				},
+				&ast.InsertStmt{
+					Table: &ast.TableDef{Type: ast.TableTypeBaseTable, Name: "t"},
+					Select: &ast.SelectStmt{
+						FieldList: []ast.ExpressionNode{
+							newUnconvertedExpression("a"),
+						},
+					},
+				},
+			},
+			statementList: []parser.SingleSQL{
+				{
+					Text:     "SELECT t.*, a FROM t WHERE a IN (SELECT * FROM s) ORDER BY a;",
+					LastLine: 2,
+				},
+				{
+					Text:     "DELETE FROM t;",
+					LastLine: 3,
+				},
+				{
+					Text:     "UPDATE t SET a = 1 WHERE b = 'x;y';",
+					LastLine: 4,
+				},
+				{
+					Text:     "INSERT INTO t (a) SELECT a FROM s",
+					LastLine: 5,
+				},
+			},
+		},
+	}
+
+	runTests(t, parser.Snowflake, tests)
+}
+
+func TestSyntaxError(t *testing.T) {
+	tests := []struct {
+		engineType parser.EngineType
+		stmt       string
+		err        string
+	}{
+		{
+			engineType: parser.Snowflake,
+			stmt:       "SELECT 1;\nSELEC 2",
+			err:        `line 2: syntax error at or near "SELEC"`,
+		},
+		{
+			engineType: parser.ClickHouse,
+			stmt:       "SELECT (1",
+			err:        "line 1: syntax error at end of input",
+		},
+		{
+			engineType: parser.ClickHouse,
+			stmt:       "SELECT 'abc",
+			err:        "line 1: syntax error, unterminated quoted string",
+		},
+		{
+			engineType: parser.Snowflake,
+			stmt:       "CREATE TABLE a.b.c.d (id INT)",
+			err:        `line 1: syntax error at or near "a"`,
+		},
+	}
+
+	for _, test := range tests {
+		_, err := parser.Parse(test.engineType, parser.ParseContext{}, test.stmt)
+		require.EqualError(t, err, test.err, test.stmt)
+	}
+}
@@ -0,0 +1,230 @@
+package standard
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+type tokenType int
+
+const (
+	tokenWord tokenType = iota
+	tokenQuotedIdentifier
+	tokenString
+	tokenNumber
+	tokenOperator
+	tokenSemicolon
+)
+
+type token struct {
+	tp tokenType
+	// text is the original text of the token.
+	text string
+	// value is the unquoted value for quoted identifiers and strings, otherwise the same as text.
+	value string
+	line  int
+	// start and end are the rune offsets of the token in the statement.
+	start int
+	end   int
+}
+
+// isKeyword returns true if the token is the unquoted word equal to the keyword, case insensitive.
+func (t *token) isKeyword(keyword string) bool {
+	return t.tp == tokenWord && strings.EqualFold(t.text, keyword)
+}
+
+func (t *token) isOperator(operator string) bool {
+	return t.tp == tokenOperator && t.text == operator
+}
+
+// isIdentifier returns true if the token can be used as an identifier.
+func (t *token) isIdentifier() bool {
+	return t.tp == tokenWord || t.tp == tokenQuotedIdentifier
+}
+
+type lexer struct {
+	dialect dialect
+	buf     []rune
+	cursor  int
+	line    int
+}
+
+func newLexer(d dialect, statement string) *lexer {
+	return &lexer{
+		dialect: d,
+		buf:     []rune(statement),
+		line:    1,
+	}
+}
+
+func (l *lexer) char(offset int) rune {
+	if l.cursor+offset >= len(l.buf) {
+		return 0
+	}
+	return l.buf[l.cursor+offset]
+}
+
+func (l *lexer) skip(n int) {
+	for i := 0; i < n && l.cursor < len(l.buf); i++ {
+		if l.buf[l.cursor] == '\n' {
+			l.line++
+		}
+		l.cursor++
+	}
+}
+
+func (l *lexer) atEnd() bool {
+	return l.cursor >= len(l.buf)
+}
+
+// tokenize splits the statement into tokens, skipping blanks and comments.
+func (l *lexer) tokenize() ([]*token, error) {
+	var tokens []*token
+	for {
+		if err := l.skipBlankAndComment(); err != nil {
+			return nil, err
+		}
+		if l.atEnd() {
+			return tokens, nil
+		}
+		tk, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, tk)
+	}
+}
+
+func (l *lexer) skipBlankAndComment() error {
+	for !l.atEnd() {
+		switch {
+		case unicode.IsSpace(l.char(0)):
+			l.skip(1)
+		case l.char(0) == '-' && l.char(1) == '-', l.char(0) == '/' && l.char(1) == '/' && l.dialect == dialectSnowflake, l.char(0) == '#' && l.dialect == dialectClickHouse:
+			for !l.atEnd() && l.char(0) != '\n' {
+				l.skip(1)
+			}
+		case l.char(0) == '/' && l.char(1) == '*':
+			line := l.line
+			l.skip(2)
+			for !(l.char(0) == '*' && l.char(1) == '/') {
+				if l.atEnd() {
+					return errors.Errorf("line %d: syntax error, unterminated comment", line)
+				}
+				l.skip(1)
+			}
+			l.skip(2)
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+func (l *lexer) next() (*token, error) {
+	tk := &token{line: l.line, start: l.cursor}
+	c := l.char(0)
+	switch {
+	case c == '\'':
+		value, err := l.scanQuoted('\'')
+		if err != nil {
+			return nil, err
+		}
+		tk.tp, tk.value = tokenString, value
+	case c == '"' || (c == '`' && l.dialect == dialectClickHouse):
+		value, err := l.scanQuoted(c)
+		if err != nil {
+			return nil, err
+		}
+		tk.tp, tk.value = tokenQuotedIdentifier, value
+	case c == '$' && l.char(1) == '$':
+		value, err := l.scanDoubleDollarQuoted()
+		if err != nil {
+			return nil, err
+		}
+		tk.tp, tk.value = tokenString, value
+	case unicode.IsDigit(c) || (c == '.' && unicode.IsDigit(l.char(1))):
+		for unicode.IsDigit(l.char(0)) || unicode.IsLetter(l.char(0)) || l.char(0) == '.' || l.char(0) == '_' {
+			// Exponents such as 1e-5.
+			if (l.char(0) == 'e' || l.char(0) == 'E') && (l.char(1) == '-' || l.char(1) == '+') {
+				l.skip(1)
+			}
+			l.skip(1)
+		}
+		tk.tp = tokenNumber
+	case isWordRune(c):
+		for isWordRune(l.char(0)) || unicode.IsDigit(l.char(0)) || l.char(0) == '$' {
+			l.skip(1)
+		}
+		tk.tp = tokenWord
+	case c == ';':
+		l.skip(1)
+		tk.tp = tokenSemicolon
+	default:
+		tk.tp = tokenOperator
+		for _, operator := range []string{"::", "<=", ">=", "<>", "!=", "||", "->", "=>", "=="} {
+			if string(c) == operator[:1] && l.char(1) == rune(operator[1]) {
+				l.skip(2)
+				break
+			}
+		}
+		if l.cursor == tk.start {
+			l.skip(1)
+		}
+	}
+	tk.end = l.cursor
+	tk.text = string(l.buf[tk.start:tk.end])
+	if tk.tp == tokenWord || tk.tp == tokenNumber || tk.tp == tokenOperator || tk.tp == tokenSemicolon {
+		tk.value = tk.text
+	}
+	return tk, nil
+}
+
+func isWordRune(c rune) bool {
+	return unicode.IsLetter(c) || c == '_'
+}
+
+// scanQuoted scans the string or quoted identifier, the quote is escaped by doubling it or by a backslash.
+func (l *lexer) scanQuoted(quote rune) (string, error) {
+	line := l.line
+	var value strings.Builder
+	l.skip(1)
+	for {
+		switch {
+		case l.atEnd():
+			if quote == '\'' {
+				return "", errors.Errorf("line %d: syntax error, unterminated quoted string", line)
+			}
+			return "", errors.Errorf("line %d: syntax error, unterminated quoted identifier", line)
+		case l.char(0) == '\\':
+			value.WriteRune(l.char(1))
+			l.skip(2)
+		case l.char(0) == quote && l.char(1) == quote:
+			value.WriteRune(quote)
+			l.skip(2)
+		case l.char(0) == quote:
+			l.skip(1)
+			return value.String(), nil
+		default:
+			value.WriteRune(l.char(0))
+			l.skip(1)
+		}
+	}
+}
+
+func (l *lexer) scanDoubleDollarQuoted() (string, error) {
+	line := l.line
+	l.skip(2)
+	start := l.cursor
+	for !(l.char(0) == '$' && l.char(1) == '$') {
+		if l.atEnd() {
+			return "", errors.Errorf("line %d: syntax error, unterminated dollar-quoted string", line)
+		}
+		l.skip(1)
+	}
+	value := string(l.buf[start:l.cursor])
+	l.skip(2)
+	return value, nil
+}
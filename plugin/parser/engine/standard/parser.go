@@ -0,0 +1,88 @@
+// Package standard implements a lightweight parser for the dialects without a full-fledged parser, currently Snowflake and ClickHouse.
+// It recognizes the statements and clauses checked by SQL review, and converts everything else to the unconverted nodes.
+package standard
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/plugin/parser"
+	"github.com/bytebase/bytebase/plugin/parser/ast"
+)
+
+var (
+	_ parser.Parser = (*DialectParser)(nil)
+)
+
+type dialect int
+
+const (
+	dialectSnowflake dialect = iota
+	dialectClickHouse
+)
+
+func init() {
+	parser.Register(parser.Snowflake, &DialectParser{dialect: dialectSnowflake})
+	parser.Register(parser.ClickHouse, &DialectParser{dialect: dialectClickHouse})
+}
+
+// DialectParser is the parser for Snowflake and ClickHouse dialects.
+type DialectParser struct {
+	dialect dialect
+}
+
+// Parse implements the parser.Parser interface.
+func (p *DialectParser) Parse(_ parser.ParseContext, statement string) ([]ast.Node, error) {
+	tokens, err := newLexer(p.dialect, statement).tokenize()
+	if err != nil {
+		return nil, err
+	}
+
+	buf := []rune(statement)
+	var nodeList []ast.Node
+	for _, stmtTokens := range splitTokens(tokens) {
+		last := stmtTokens[len(stmtTokens)-1]
+		c := &converter{
+			dialect:  p.dialect,
+			buf:      buf,
+			tokens:   stmtTokens,
+			lastLine: last.line,
+		}
+		if last.tp == tokenSemicolon {
+			c.tokens = stmtTokens[:len(stmtTokens)-1]
+		}
+		node, err := c.convert()
+		if err != nil {
+			return nil, err
+		}
+		node.SetText(string(buf[stmtTokens[0].start:last.end]))
+		node.SetLastLine(last.line)
+		nodeList = append(nodeList, node)
+	}
+	return nodeList, nil
+}
+
+// Deparse implements the parser.Deparse interface.
+func (*DialectParser) Deparse(_ parser.DeparseContext, _ ast.Node) (string, error) {
+	return "", errors.New("deparse is not supported for Snowflake and ClickHouse")
+}
+
+// splitTokens splits the tokens into statements by the semicolon, the semicolon is kept as the last token of the statement.
+func splitTokens(tokens []*token) [][]*token {
+	var res [][]*token
+	var current []*token
+	for _, tk := range tokens {
+		if tk.tp == tokenSemicolon && len(current) == 0 {
+			// Skip the empty statement.
+			continue
+		}
+		current = append(current, tk)
+		if tk.tp == tokenSemicolon {
+			res = append(res, current)
+			current = nil
+		}
+	}
+	if len(current) > 0 {
+		res = append(res, current)
+	}
+	return res
+}
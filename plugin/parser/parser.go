@@ -20,6 +20,10 @@ const (
 	Postgres EngineType = "POSTGRES"
 	// TiDB is the engine type for TiDB.
 	TiDB EngineType = "TIDB"
+	// Snowflake is the engine type for Snowflake.
+	Snowflake EngineType = "SNOWFLAKE"
+	// ClickHouse is the engine type for ClickHouse.
+	ClickHouse EngineType = "CLICKHOUSE"
 
 	// DeparseIndentString is the string for each indent level.
 	DeparseIndentString = "    "
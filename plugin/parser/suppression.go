@@ -0,0 +1,84 @@
+package parser
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SuppressionScope is the scope of the SQL review suppression comment.
+type SuppressionScope string
+
+const (
+	// SuppressionScopeStatement suppresses the rules for the statement following the comment,
+	// or the statement ending on the line where the comment starts.
+	SuppressionScopeStatement SuppressionScope = "STATEMENT"
+	// SuppressionScopeFile suppresses the rules for all statements.
+	SuppressionScopeFile SuppressionScope = "FILE"
+)
+
+var (
+	// suppressionDirectiveList is the directives starting the suppression comment.
+	// The "-file" suffix changes the scope from the next statement to the whole file.
+	suppressionDirectiveList = []string{"bb-ignore", "bytebase:disable"}
+	suppressionFileSuffix    = "-file"
+	suppressionReasonPrefix  = "reason:"
+)
+
+// Suppression is the comment suppressing the SQL review rules, such as
+//
+//	-- bb-ignore: table.require-pk, naming.table reason: the log table has no primary key
+//	/* bytebase:disable-file naming.table reason: legacy naming */
+//
+// The reason is required, the caller should report the suppression without the reason instead of applying it.
+type Suppression struct {
+	Scope    SuppressionScope
+	RuleList []string
+	Reason   string
+	// Line is the line of the comment.
+	Line int
+}
+
+// ParseSuppression parses the comment, returns nil if it isn't a suppression comment.
+func ParseSuppression(comment string) *Suppression {
+	text := strings.TrimSpace(comment)
+	switch {
+	case strings.HasPrefix(text, "--"):
+		text = text[2:]
+	case strings.HasPrefix(text, "#"):
+		text = text[1:]
+	case strings.HasPrefix(text, "/*"):
+		text = strings.TrimSuffix(text[2:], "*/")
+	default:
+		return nil
+	}
+	text = strings.TrimSpace(text)
+
+	for _, directive := range suppressionDirectiveList {
+		if len(text) < len(directive) || !strings.EqualFold(text[:len(directive)], directive) {
+			continue
+		}
+		rest := text[len(directive):]
+		scope := SuppressionScopeStatement
+		if len(rest) >= len(suppressionFileSuffix) && strings.EqualFold(rest[:len(suppressionFileSuffix)], suppressionFileSuffix) {
+			scope = SuppressionScopeFile
+			rest = rest[len(suppressionFileSuffix):]
+		}
+		// The directive should be a whole word, e.g. "bb-ignored" isn't a suppression comment.
+		if rest != "" && rest[0] != ':' && !unicode.IsSpace(rune(rest[0])) {
+			return nil
+		}
+		rest = strings.TrimPrefix(rest, ":")
+
+		suppression := &Suppression{Scope: scope}
+		ruleText := rest
+		if i := strings.Index(strings.ToLower(rest), suppressionReasonPrefix); i >= 0 {
+			ruleText = rest[:i]
+			suppression.Reason = strings.Join(strings.Fields(rest[i+len(suppressionReasonPrefix):]), " ")
+		}
+		suppression.RuleList = strings.FieldsFunc(ruleText, func(r rune) bool {
+			return r == ',' || unicode.IsSpace(r)
+		})
+		return suppression
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSuppression(t *testing.T) {
+	tests := []struct {
+		comment string
+		want    *Suppression
+	}{
+		{
+			comment: "-- bb-ignore: table.require-pk reason: the log table has no primary key",
+			want: &Suppression{
+				Scope:    SuppressionScopeStatement,
+				RuleList: []string{"table.require-pk"},
+				Reason:   "the log table has no primary key",
+			},
+		},
+		{
+			comment: "/* BB-IGNORE-FILE: naming.table, table.require-pk Reason: legacy\n tables */",
+			want: &Suppression{
+				Scope:    SuppressionScopeFile,
+				RuleList: []string{"naming.table", "table.require-pk"},
+				Reason:   "legacy tables",
+			},
+		},
+		{
+			comment: "# bytebase:disable naming.table",
+			want: &Suppression{
+				Scope:    SuppressionScopeStatement,
+				RuleList: []string{"naming.table"},
+			},
+		},
+		{
+			comment: "-- bytebase:disable-file reason: no rules",
+			want: &Suppression{
+				Scope:    SuppressionScopeFile,
+				RuleList: []string{},
+				Reason:   "no rules",
+			},
+		},
+		{
+			comment: "-- bb-ignored: naming.table reason: not a directive",
+			want:    nil,
+		},
+		{
+			comment: "-- create the log table",
+			want:    nil,
+		},
+		{
+			comment: "bb-ignore: naming.table reason: not a comment",
+			want:    nil,
+		},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.want, ParseSuppression(test.comment), test.comment)
+	}
+}
+
+func TestSplitMultiSQLSuppression(t *testing.T) {
+	statement := `-- bb-ignore-file: naming.table reason: legacy naming
+CREATE TABLE t1(a int);
+/* bb-ignore: table.require-pk reason: the log table */
+CREATE TABLE log(
+  -- a normal comment
+  content text
+);
+CREATE TABLE t2(a int);`
+
+	for _, engineType := range []EngineType{MySQL, Postgres} {
+		sqlList, err := SplitMultiSQL(engineType, statement)
+		require.NoError(t, err)
+		require.Len(t, sqlList, 3)
+		require.Equal(t, []*Suppression{
+			{
+				Scope:    SuppressionScopeFile,
+				RuleList: []string{"naming.table"},
+				Reason:   "legacy naming",
+				Line:     1,
+			},
+		}, sqlList[0].SuppressionList)
+		require.Equal(t, []*Suppression{
+			{
+				Scope:    SuppressionScopeStatement,
+				RuleList: []string{"table.require-pk"},
+				Reason:   "the log table",
+				Line:     3,
+			},
+		}, sqlList[1].SuppressionList)
+		require.Nil(t, sqlList[2].SuppressionList)
+	}
+}
+
+func TestSplitMultiSQLTrailingSuppression(t *testing.T) {
+	statement := `CREATE TABLE log(content text); -- bb-ignore: table.require-pk reason: the log table
+CREATE TABLE t1(a int);
+CREATE TABLE t2(a int); /* bb-ignore: naming.table reason: legacy naming */`
+
+	for _, engineType := range []EngineType{MySQL, Postgres} {
+		sqlList, err := SplitMultiSQL(engineType, statement)
+		require.NoError(t, err)
+		require.Len(t, sqlList, 4)
+		require.Equal(t, []*Suppression{
+			{
+				Scope:    SuppressionScopeStatement,
+				RuleList: []string{"table.require-pk"},
+				Reason:   "the log table",
+				Line:     1,
+			},
+		}, sqlList[0].SuppressionList)
+		require.Nil(t, sqlList[1].SuppressionList)
+		require.Equal(t, []*Suppression{
+			{
+				Scope:    SuppressionScopeStatement,
+				RuleList: []string{"naming.table"},
+				Reason:   "legacy naming",
+				Line:     3,
+			},
+		}, sqlList[2].SuppressionList)
+		require.Nil(t, sqlList[3].SuppressionList)
+	}
+}
@@ -27,6 +27,8 @@ type tokenizer struct {
 	cursor uint
 	len    uint
 	line   int
+	// suppressionList is the suppression comments found since the last split statement.
+	suppressionList []*Suppression
 
 	// steaming API specific field
 	reader  *bufio.Reader
@@ -389,7 +391,8 @@ func (t *tokenizer) splitMySQLMultiSQL() ([]SingleSQL, error) {
 						// but we want to get the line of last line of the SQL
 						// which means the line of ')'.
 						// So we need minus the aboveNonBlankLineDistance.
-						LastLine: t.line - t.aboveNonBlankLineDistance(),
+						LastLine:        t.line - t.aboveNonBlankLineDistance(),
+						SuppressionList: t.takeSuppressionList(),
 					})
 				}
 				if err := t.processStreaming(s); err != nil {
@@ -402,8 +405,9 @@ func (t *tokenizer) splitMySQLMultiSQL() ([]SingleSQL, error) {
 			text := t.getString(startPos, t.pos()-startPos)
 			if t.f == nil {
 				res = append(res, SingleSQL{
-					Text:     text,
-					LastLine: t.line,
+					Text:            text,
+					LastLine:        t.line,
+					SuppressionList: t.takeSuppressionList(),
 				})
 			}
 			t.skipBlank()
@@ -421,8 +425,9 @@ func (t *tokenizer) splitMySQLMultiSQL() ([]SingleSQL, error) {
 			text := t.getString(startPos, t.pos()-startPos)
 			if t.f == nil {
 				res = append(res, SingleSQL{
-					Text:     text,
-					LastLine: t.line,
+					Text:            text,
+					LastLine:        t.line,
+					SuppressionList: t.takeSuppressionList(),
 				})
 			}
 			t.skipBlank()
@@ -431,15 +436,15 @@ func (t *tokenizer) splitMySQLMultiSQL() ([]SingleSQL, error) {
 			}
 			startPos = t.pos()
 		case t.char(0) == '/' && t.char(1) == '*':
-			if err := t.scanComment(); err != nil {
+			if err := t.scanCommentAndSuppression(); err != nil {
 				return nil, err
 			}
 		case t.char(0) == '-' && t.char(1) == '-':
-			if err := t.scanComment(); err != nil {
+			if err := t.scanCommentAndSuppression(); err != nil {
 				return nil, err
 			}
 		case t.char(0) == '#':
-			if err := t.scanComment(); err != nil {
+			if err := t.scanCommentAndSuppression(); err != nil {
 				return nil, err
 			}
 		case t.char(0) == '\'' || t.char(0) == '"':
@@ -483,11 +488,11 @@ func (t *tokenizer) splitPostgreSQLMultiSQL() ([]SingleSQL, error) {
 	for {
 		switch {
 		case t.char(0) == '/' && t.char(1) == '*':
-			if err := t.scanComment(); err != nil {
+			if err := t.scanCommentAndSuppression(); err != nil {
 				return nil, err
 			}
 		case t.char(0) == '-' && t.char(1) == '-':
-			if err := t.scanComment(); err != nil {
+			if err := t.scanCommentAndSuppression(); err != nil {
 				return nil, err
 			}
 		case t.char(0) == '\'':
@@ -507,8 +512,9 @@ func (t *tokenizer) splitPostgreSQLMultiSQL() ([]SingleSQL, error) {
 			text := t.getString(startPos, t.pos()-startPos)
 			if t.f == nil {
 				res = append(res, SingleSQL{
-					Text:     text,
-					LastLine: t.line,
+					Text:            text,
+					LastLine:        t.line,
+					SuppressionList: t.takeSuppressionList(),
 				})
 			}
 			t.skipBlank()
@@ -534,7 +540,8 @@ func (t *tokenizer) splitPostgreSQLMultiSQL() ([]SingleSQL, error) {
 						// but we want to get the line of last line of the SQL
 						// which means the line of ')'.
 						// So we need minus the aboveNonBlankLineDistance.
-						LastLine: t.line - t.aboveNonBlankLineDistance(),
+						LastLine:        t.line - t.aboveNonBlankLineDistance(),
+						SuppressionList: t.takeSuppressionList(),
 					})
 				}
 				if err := t.processStreaming(s); err != nil {
@@ -654,6 +661,30 @@ func (t *tokenizer) scanComment() error {
 	return errors.Errorf("no comment found")
 }
 
+// scanCommentAndSuppression scans the comment and collects it if it's a suppression comment.
+// The suppression comments are only collected by the non-streaming API.
+func (t *tokenizer) scanCommentAndSuppression() error {
+	startPos, line := t.pos(), t.line
+	if err := t.scanComment(); err != nil {
+		return err
+	}
+	if t.f != nil {
+		return nil
+	}
+	if suppression := ParseSuppression(t.getString(startPos, t.pos()-startPos)); suppression != nil {
+		suppression.Line = line
+		t.suppressionList = append(t.suppressionList, suppression)
+	}
+	return nil
+}
+
+// takeSuppressionList returns the collected suppression comments and resets them for the next statement.
+func (t *tokenizer) takeSuppressionList() []*Suppression {
+	res := t.suppressionList
+	t.suppressionList = nil
+	return res
+}
+
 // scanTo scans to delimiter. Use KMP algorithm.
 func (t *tokenizer) scanTo(delimiter []rune) error {
 	if len(delimiter) == 0 {
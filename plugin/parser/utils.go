@@ -20,20 +20,50 @@ import (
 type SingleSQL struct {
 	Text     string
 	LastLine int
+	// SuppressionList is the SQL review suppression comments in or before the statement, see Suppression.
+	SuppressionList []*Suppression
 }
 
 // SplitMultiSQL splits statement into a slice of the single SQL.
 func SplitMultiSQL(engineType EngineType, statement string) ([]SingleSQL, error) {
+	var res []SingleSQL
+	var err error
 	switch engineType {
-	case Postgres:
+	// Snowflake shares the quoting and comment styles with PostgreSQL, and ClickHouse shares them with MySQL.
+	case Postgres, Snowflake:
 		t := newTokenizer(statement)
-		return t.splitPostgreSQLMultiSQL()
-	case MySQL, TiDB:
+		res, err = t.splitPostgreSQLMultiSQL()
+	case MySQL, TiDB, ClickHouse:
 		t := newTokenizer(statement)
-		return t.splitMySQLMultiSQL()
+		res, err = t.splitMySQLMultiSQL()
 	default:
 		return nil, errors.Errorf("engine type is not supported: %s", engineType)
 	}
+	if err != nil {
+		return nil, err
+	}
+	bindTrailingSuppression(res)
+	return res, nil
+}
+
+// bindTrailingSuppression binds the suppression comments starting on the last line of the statement to the statement, e.g.
+//
+//	CREATE TABLE log(content text); -- bb-ignore: table.require-pk reason: the log table has no primary key
+//
+// The tokenizer collects the comments after the delimiter for the next statement, so we move them back.
+func bindTrailingSuppression(sqlList []SingleSQL) {
+	for i := 1; i < len(sqlList); i++ {
+		prev := &sqlList[i-1]
+		var suppressionList []*Suppression
+		for _, suppression := range sqlList[i].SuppressionList {
+			if suppression.Line == prev.LastLine {
+				prev.SuppressionList = append(prev.SuppressionList, suppression)
+				continue
+			}
+			suppressionList = append(suppressionList, suppression)
+		}
+		sqlList[i].SuppressionList = suppressionList
+	}
 }
 
 // SplitMultiSQLStream splits statement stream into a slice of the single SQL.
@@ -5,6 +5,7 @@ import (
 	"context"
 	"io"
 	"os"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
@@ -103,6 +104,18 @@ func (c *Client) DeleteObjects(ctx context.Context, pathList ...string) (*s3.Del
 	})
 }
 
+// PresignGetObject returns the URL to download the object with path, which expires after the duration.
+func (c *Client) PresignGetObject(ctx context.Context, path string, expires time.Duration) (string, error) {
+	request, err := s3.NewPresignClient(c.c).PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: &c.bucket,
+		Key:    &path,
+	}, s3.WithPresignExpires(expires))
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to presign the download URL of %q", path)
+	}
+	return request.URL, nil
+}
+
 // GetBucket returns the bucket.
 func (c *Client) GetBucket() string {
 	return c.bucket
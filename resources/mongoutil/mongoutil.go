@@ -20,6 +20,16 @@ func GetMongoshPath(binDir string) string {
 	return path.Join(binDir, "mongosh")
 }
 
+// GetMongodumpPath returns the mongodump path.
+func GetMongodumpPath(binDir string) string {
+	return path.Join(binDir, "mongodump")
+}
+
+// GetMongorestorePath returns the mongorestore path.
+func GetMongorestorePath(binDir string) string {
+	return path.Join(binDir, "mongorestore")
+}
+
 // getTarnameAndVersion returns the mongoutil tarball name and version string.
 func getTarNameAndVersion() (tarname string, version string, err error) {
 	var tarName string
@@ -209,6 +209,27 @@ func InitDB(pgBinDir, pgDataDir, pgUser string) error {
 	return nil
 }
 
+// ChownDataDir changes the owner of the data directory and its files to the user running postgres.
+// It's for the data directory that is not created by InitDB, such as the one recovered from a base backup.
+func ChownDataDir(pgDataDir string) error {
+	uid, gid, sameUser, err := shouldSwitchUser()
+	if err != nil {
+		return err
+	}
+	if sameUser {
+		return nil
+	}
+	return filepath.Walk(pgDataDir, func(path string, _ os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := os.Lchown(path, uid, gid); err != nil {
+			return errors.Wrapf(err, "failed to change owner of %q to bytebase", path)
+		}
+		return nil
+	})
+}
+
 func shouldSwitchUser() (int, int, bool, error) {
 	sameUser := true
 	bytebaseUser, err := user.Current()
@@ -16,6 +16,7 @@ import (
 	enterpriseAPI "github.com/bytebase/bytebase/enterprise/api"
 	"github.com/bytebase/bytebase/plugin/db"
 	v1pb "github.com/bytebase/bytebase/proto/generated-go/v1"
+	"github.com/bytebase/bytebase/server/component/dbfactory"
 	"github.com/bytebase/bytebase/store"
 )
 
@@ -26,13 +27,15 @@ type InstanceService struct {
 	v1pb.UnimplementedInstanceServiceServer
 	store          *store.Store
 	licenseService enterpriseAPI.LicenseService
+	dbFactory      *dbfactory.DBFactory
 }
 
 // NewInstanceService creates a new InstanceService.
-func NewInstanceService(store *store.Store, licenseService enterpriseAPI.LicenseService) *InstanceService {
+func NewInstanceService(store *store.Store, licenseService enterpriseAPI.LicenseService, dbFactory *dbfactory.DBFactory) *InstanceService {
 	return &InstanceService{
 		store:          store,
 		licenseService: licenseService,
+		dbFactory:      dbFactory,
 	}
 }
 
@@ -168,6 +171,7 @@ func (s *InstanceService) UpdateInstance(ctx context.Context, request *v1pb.Upda
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, err.Error())
 	}
+	s.dbFactory.Invalidate(ins.UID)
 
 	// TODO(d): sync instance databases.
 
@@ -201,6 +205,7 @@ func (s *InstanceService) DeleteInstance(ctx context.Context, request *v1pb.Dele
 	}); err != nil {
 		return nil, status.Errorf(codes.Internal, err.Error())
 	}
+	s.dbFactory.Invalidate(instance.UID)
 
 	return &emptypb.Empty{}, nil
 }
@@ -291,6 +296,18 @@ func convertToInstance(instance *store.InstanceMessage) *v1pb.Instance {
 		engine = v1pb.Engine_TIDB
 	case db.MongoDB:
 		engine = v1pb.Engine_MONGODB
+	case db.Redis:
+		engine = v1pb.Engine_REDIS
+	case db.Oracle:
+		engine = v1pb.Engine_ORACLE
+	case db.Spanner:
+		engine = v1pb.Engine_SPANNER
+	case db.MSSQL:
+		engine = v1pb.Engine_MSSQL
+	case db.MariaDB:
+		engine = v1pb.Engine_MARIADB
+	case db.OceanBase:
+		engine = v1pb.Engine_OCEANBASE
 	}
 
 	dataSourceList := []*v1pb.DataSource{}
@@ -344,6 +361,18 @@ func convertToInstanceMessage(instanceID string, instance *v1pb.Instance) (*stor
 		engine = db.TiDB
 	case v1pb.Engine_MONGODB:
 		engine = db.MongoDB
+	case v1pb.Engine_REDIS:
+		engine = db.Redis
+	case v1pb.Engine_ORACLE:
+		engine = db.Oracle
+	case v1pb.Engine_SPANNER:
+		engine = db.Spanner
+	case v1pb.Engine_MSSQL:
+		engine = db.MSSQL
+	case v1pb.Engine_MARIADB:
+		engine = db.MariaDB
+	case v1pb.Engine_OCEANBASE:
+		engine = db.OceanBase
 	default:
 		return nil, errors.Errorf("invalid instance engine %v", instance.Engine)
 	}
@@ -0,0 +1,26 @@
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/db"
+	v1pb "github.com/bytebase/bytebase/proto/generated-go/v1"
+	"github.com/bytebase/bytebase/store"
+)
+
+func TestConvertInstanceEngine(t *testing.T) {
+	engineList := []db.Type{
+		db.ClickHouse, db.MySQL, db.Postgres, db.Snowflake, db.SQLite, db.TiDB, db.MongoDB,
+		db.Redis, db.Oracle, db.Spanner, db.MSSQL, db.MariaDB, db.OceanBase,
+	}
+	for _, engine := range engineList {
+		instance := convertToInstance(&store.InstanceMessage{Engine: engine})
+		require.NotEqual(t, v1pb.Engine_ENGINE_UNSPECIFIED, instance.Engine, engine)
+
+		message, err := convertToInstanceMessage("instance", instance)
+		require.NoError(t, err, engine)
+		require.Equal(t, engine, message.Engine)
+	}
+}
@@ -63,6 +63,12 @@ type Profile struct {
 	MetricConnectionKey string
 	// DisableMetric will disable the metric collector.
 	DisableMetric bool
+	// CredentialCommands is the map from the name to the external command printing the data source password.
+	CredentialCommands map[string]string
+	// SecretEnvPrefix is the prefix of the environment variables which can be referenced by the data source password secrets.
+	SecretEnvPrefix string
+	// SecretVaultMount is the Vault KV mount which can be referenced by the data source password secrets.
+	SecretVaultMount string
 }
 
 // UseEmbedDB returns whether to use embedDB.
@@ -0,0 +1,150 @@
+package dbfactory
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2/google"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+const (
+	// credentialCommandTimeout is the maximum time for the external command printing the password.
+	credentialCommandTimeout = 30 * time.Second
+	// rdsAuthTokenExpiration is the lifetime of the AWS RDS IAM authentication token, which is the maximum allowed by AWS.
+	rdsAuthTokenExpiration = 15 * time.Minute
+	// emptyPayloadHash is the hex encoded SHA-256 hash of the empty payload.
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	// cloudSQLLoginScope is the OAuth2 scope required by the Cloud SQL IAM database authentication.
+	cloudSQLLoginScope = "https://www.googleapis.com/auth/sqlservice.login"
+)
+
+// CredentialConfig is the server configuration restricting the external credentials referenced by the data sources.
+type CredentialConfig struct {
+	// CommandMap is the map from the name to the external command printing the password.
+	CommandMap map[string]string
+	// SecretEnvPrefix is the prefix of the environment variables referenced by "env:<NAME>", the env references are disabled if empty.
+	SecretEnvPrefix string
+	// SecretVaultMount is the Vault KV mount referenced by "vault:<mount>/<path>#<key>", the Vault references are disabled if empty.
+	SecretVaultMount string
+}
+
+// CredentialRequest is the connection to get the password for.
+type CredentialRequest struct {
+	Host     string
+	Port     string
+	Username string
+	// Password is the password stored in the data source.
+	Password string
+}
+
+// GetPassword gets the password for connecting to the database with the credential provider in the data source options.
+// The short-lived credentials are generated on each call, so the callers should get the password before each db.Open.
+func (d *DBFactory) GetPassword(ctx context.Context, options api.DataSourceOptions, request CredentialRequest) (string, error) {
+	switch options.CredentialProvider {
+	case "", api.CredentialProviderStatic:
+		if options.PasswordSecret != "" {
+			return d.getSecret(ctx, options.PasswordSecret)
+		}
+		return request.Password, nil
+	case api.CredentialProviderAWSRDSIAM:
+		return getRDSAuthToken(ctx, options.Region, request)
+	case api.CredentialProviderGCPCloudSQLIAM:
+		return getCloudSQLAccessToken(ctx)
+	case api.CredentialProviderCommand:
+		return d.getCommandPassword(ctx, options.CredentialCommandName)
+	default:
+		return "", common.Errorf(common.Invalid, "unsupported credential provider %q", options.CredentialProvider)
+	}
+}
+
+// getRDSAuthToken generates the AWS RDS IAM authentication token, which is the presigned URL of the rds-db:connect action without the scheme.
+// The AWS credentials and the default region are loaded from the environment, the shared config files or the instance role.
+func getRDSAuthToken(ctx context.Context, region string, request CredentialRequest) (string, error) {
+	if request.Host == "" || request.Port == "" {
+		return "", common.Errorf(common.Invalid, "host and port are required for the AWS RDS IAM authentication")
+	}
+	cfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to load AWS config")
+	}
+	if region == "" {
+		region = cfg.Region
+	}
+	if region == "" {
+		return "", common.Errorf(common.Invalid, "region is required for the AWS RDS IAM authentication")
+	}
+	credentials, err := cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to retrieve AWS credentials")
+	}
+
+	endpoint := net.JoinHostPort(request.Host, request.Port)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://"+endpoint+"/", nil)
+	if err != nil {
+		return "", err
+	}
+	values := url.Values{}
+	values.Set("Action", "connect")
+	values.Set("DBUser", request.Username)
+	values.Set("X-Amz-Expires", strconv.Itoa(int(rdsAuthTokenExpiration.Seconds())))
+	req.URL.RawQuery = values.Encode()
+
+	signedURL, _, err := v4.NewSigner().PresignHTTP(ctx, credentials, req, emptyPayloadHash, "rds-db", region, time.Now().UTC())
+	if err != nil {
+		return "", errors.Wrap(err, "failed to sign the AWS RDS IAM authentication token")
+	}
+	return strings.TrimPrefix(signedURL, "https://"), nil
+}
+
+// getCloudSQLAccessToken gets the OAuth2 access token of the GCP application default credentials.
+// The database user is the IAM user or the service account, such as "sa-name@project-id.iam".
+func getCloudSQLAccessToken(ctx context.Context) (string, error) {
+	tokenSource, err := google.DefaultTokenSource(ctx, cloudSQLLoginScope)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to find GCP default credentials")
+	}
+	token, err := tokenSource.Token()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get GCP access token")
+	}
+	return token.AccessToken, nil
+}
+
+// getCommandPassword runs the command configured by the server flag with the shell, and uses its stdout with the surrounding whitespaces trimmed as the password.
+// The stderr isn't returned, because the error is shown to the users who may not be allowed to see the command output.
+func (d *DBFactory) getCommandPassword(ctx context.Context, name string) (string, error) {
+	if name == "" {
+		return "", common.Errorf(common.Invalid, "credential command name is required for the command credential provider")
+	}
+	command, ok := d.credentialConfig.CommandMap[name]
+	if !ok {
+		return "", common.Errorf(common.Invalid, "credential command %q is not configured by --credential-command", name)
+	}
+	ctx, cancel := context.WithTimeout(ctx, credentialCommandTimeout)
+	defer cancel()
+
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		return "", errors.Wrapf(err, "failed to run credential command %q", name)
+	}
+	password := strings.TrimSpace(stdout.String())
+	if password == "" {
+		return "", errors.Errorf("credential command %q prints an empty password", name)
+	}
+	return password, nil
+}
@@ -0,0 +1,107 @@
+package dbfactory
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestGetPassword(t *testing.T) {
+	a := require.New(t)
+	ctx := context.Background()
+	request := CredentialRequest{
+		Host:     "db.example.com",
+		Port:     "5432",
+		Username: "bytebase",
+		Password: "static-password",
+	}
+
+	d := &DBFactory{}
+	password, err := d.GetPassword(ctx, api.DataSourceOptions{}, request)
+	a.NoError(err)
+	a.Equal("static-password", password)
+	password, err = d.GetPassword(ctx, api.DataSourceOptions{CredentialProvider: api.CredentialProviderStatic}, request)
+	a.NoError(err)
+	a.Equal("static-password", password)
+
+	_, err = d.GetPassword(ctx, api.DataSourceOptions{CredentialProvider: "UNKNOWN"}, request)
+	a.Error(err)
+}
+
+func TestGetCommandPassword(t *testing.T) {
+	a := require.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	script := filepath.Join(dir, "password.sh")
+	err := os.WriteFile(script, []byte("#!/bin/sh\necho \"  token-$1  \"\n"), 0700)
+	a.NoError(err)
+	failing := filepath.Join(dir, "failing.sh")
+	err = os.WriteFile(failing, []byte("#!/bin/sh\necho 'permission denied' >&2\nexit 1\n"), 0700)
+	a.NoError(err)
+	d := &DBFactory{
+		credentialConfig: CredentialConfig{
+			CommandMap: map[string]string{
+				"token":   script + " 1",
+				"failing": failing,
+				"empty":   "true",
+			},
+		},
+	}
+
+	password, err := d.GetPassword(ctx, api.DataSourceOptions{
+		CredentialProvider:    api.CredentialProviderCommand,
+		CredentialCommandName: "token",
+	}, CredentialRequest{})
+	a.NoError(err)
+	a.Equal("token-1", password)
+
+	// The stderr isn't returned to the users.
+	_, err = d.GetPassword(ctx, api.DataSourceOptions{
+		CredentialProvider:    api.CredentialProviderCommand,
+		CredentialCommandName: "failing",
+	}, CredentialRequest{})
+	a.ErrorContains(err, "failed to run credential command \"failing\"")
+	a.NotContains(err.Error(), "permission denied")
+
+	_, err = d.GetPassword(ctx, api.DataSourceOptions{
+		CredentialProvider:    api.CredentialProviderCommand,
+		CredentialCommandName: "empty",
+	}, CredentialRequest{})
+	a.ErrorContains(err, "empty password")
+
+	// Only the commands configured by the server flag can run.
+	_, err = d.GetPassword(ctx, api.DataSourceOptions{
+		CredentialProvider:    api.CredentialProviderCommand,
+		CredentialCommandName: "echo secret",
+	}, CredentialRequest{})
+	a.ErrorContains(err, "is not configured")
+}
+
+func TestGetRDSAuthToken(t *testing.T) {
+	a := require.New(t)
+	dir := t.TempDir()
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIAEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_REGION", "us-east-1")
+
+	d := &DBFactory{}
+	token, err := d.GetPassword(context.Background(), api.DataSourceOptions{
+		CredentialProvider: api.CredentialProviderAWSRDSIAM,
+		Region:             "us-west-2",
+	}, CredentialRequest{Host: "db.example.com", Port: "3306", Username: "bytebase"})
+	a.NoError(err)
+	a.True(strings.HasPrefix(token, "db.example.com:3306/?Action=connect&DBUser=bytebase&"), token)
+	a.Contains(token, "X-Amz-Credential=AKIAEXAMPLE%2F")
+	a.Contains(token, "%2Fus-west-2%2Frds-db%2Faws4_request")
+	a.Contains(token, "X-Amz-Expires=900")
+	a.Contains(token, "X-Amz-Signature=")
+}
@@ -3,6 +3,8 @@ package dbfactory
 
 import (
 	"context"
+	"sync"
+	"time"
 
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
@@ -11,20 +13,91 @@ import (
 
 // DBFactory is the factory for building database driver.
 type DBFactory struct {
-	mysqlBinDir string
-	pgBinDir    string
-	mongoBinDir string
-	dataDir     string
+	mysqlBinDir      string
+	pgBinDir         string
+	mongoBinDir      string
+	dataDir          string
+	credentialConfig CredentialConfig
+
+	pool *driverPool
 }
 
 // New creates a new database driver factory.
-func New(mysqlBinDir, mongoBinDir, pgBinDir, dataDir string) *DBFactory {
+func New(mysqlBinDir, mongoBinDir, pgBinDir, dataDir string, credentialConfig CredentialConfig) *DBFactory {
 	return &DBFactory{
-		mysqlBinDir: mysqlBinDir,
-		mongoBinDir: mongoBinDir,
-		pgBinDir:    pgBinDir,
-		dataDir:     dataDir,
+		mysqlBinDir:      mysqlBinDir,
+		mongoBinDir:      mongoBinDir,
+		pgBinDir:         pgBinDir,
+		dataDir:          dataDir,
+		credentialConfig: credentialConfig,
+		pool:             newDriverPool(),
+	}
+}
+
+// Run evicts the idle drivers in the pool periodically, and closes the idle drivers when the context is done.
+func (d *DBFactory) Run(ctx context.Context, wg *sync.WaitGroup) {
+	ticker := time.NewTicker(driverPoolEvictInterval)
+	defer ticker.Stop()
+	defer wg.Done()
+	for {
+		select {
+		case now := <-ticker.C:
+			d.pool.evict(now)
+		case <-ctx.Done():
+			d.pool.close()
+			return
+		}
+	}
+}
+
+// AcquireAdminDatabaseDriver gets the admin database driver from the pool, and opens a new one if there are no idle drivers.
+// It's for the frequent and short operations which don't change the session state, such as syncing schemas and running the read-only queries.
+// Upon successful return, caller must call the release function instead of driver.Close(), and must not use the driver after that.
+func (d *DBFactory) AcquireAdminDatabaseDriver(ctx context.Context, instance *api.Instance, databaseName string) (db.Driver, func(), error) {
+	adminDataSource := api.DataSourceFromInstanceWithType(instance, api.Admin)
+	if adminDataSource == nil {
+		return nil, nil, common.Errorf(common.Internal, "admin data source not found for instance %d", instance.ID)
+	}
+	if databaseName == "" {
+		databaseName = instance.Database
+	}
+	key := driverKey{
+		instanceID:   instance.ID,
+		dataSourceID: adminDataSource.ID,
+		databaseName: databaseName,
+	}
+	return d.pool.acquire(ctx, key, func() (db.Driver, error) {
+		return d.GetAdminDatabaseDriver(ctx, instance, databaseName)
+	})
+}
+
+// AcquireReadOnlyDatabaseDriver gets the read-only database driver from the pool, and opens a new one if there are no idle drivers.
+// Upon successful return, caller must call the release function instead of driver.Close(), and must not use the driver after that.
+func (d *DBFactory) AcquireReadOnlyDatabaseDriver(ctx context.Context, instance *api.Instance, databaseName string) (db.Driver, func(), error) {
+	dataSource := getReadOnlyDataSource(instance)
+	if dataSource == nil {
+		return nil, nil, common.Errorf(common.Internal, "data source not found for instance %d", instance.ID)
+	}
+	key := driverKey{
+		instanceID:   instance.ID,
+		dataSourceID: dataSource.ID,
+		databaseName: databaseName,
+		readOnly:     true,
 	}
+	return d.pool.acquire(ctx, key, func() (db.Driver, error) {
+		return d.GetReadOnlyDatabaseDriver(ctx, instance, databaseName)
+	})
+}
+
+// Invalidate closes the pooled drivers of the instance, it should be called after the instance or its data sources are changed.
+// The drivers in use are closed after released.
+func (d *DBFactory) Invalidate(instanceID int) {
+	d.pool.invalidate(instanceID)
+}
+
+// Stats returns the usage of the driver pool.
+func (d *DBFactory) Stats() PoolStats {
+	return d.pool.getStats()
 }
 
 // GetAdminDatabaseDriver gets the admin database driver using the instance's admin data source.
@@ -37,7 +110,7 @@ func (d *DBFactory) GetAdminDatabaseDriver(ctx context.Context, instance *api.In
 
 	dbBinDir := ""
 	switch instance.Engine {
-	case db.MySQL, db.TiDB:
+	case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 		dbBinDir = d.mysqlBinDir
 	case db.Postgres:
 		dbBinDir = d.pgBinDir
@@ -48,9 +121,10 @@ func (d *DBFactory) GetAdminDatabaseDriver(ctx context.Context, instance *api.In
 	if databaseName == "" {
 		databaseName = instance.Database
 	}
-	driver, err := getDatabaseDriver(
+	driver, err := d.getDatabaseDriver(
 		ctx,
 		instance.Engine,
+		adminDataSource.Options,
 		db.DriverConfig{
 			DbBinDir:  dbBinDir,
 			BinlogDir: common.GetBinlogAbsDir(d.dataDir, instance.ID),
@@ -68,6 +142,8 @@ func (d *DBFactory) GetAdminDatabaseDriver(ctx context.Context, instance *api.In
 			Database:               databaseName,
 			SRV:                    adminDataSource.Options.SRV,
 			AuthenticationDatabase: adminDataSource.Options.AuthenticationDatabase,
+			ServiceName:            adminDataSource.Options.ServiceName,
+			SSHConfig:              getSSHConfig(adminDataSource),
 		},
 		db.ConnectionContext{
 			EnvironmentName: instance.Environment.Name,
@@ -85,11 +161,7 @@ func (d *DBFactory) GetAdminDatabaseDriver(ctx context.Context, instance *api.In
 // If the read-only data source is not defined, we will fallback to admin data source.
 // Upon successful return, caller must call driver.Close(). Otherwise, it will leak the database connection.
 func (d *DBFactory) GetReadOnlyDatabaseDriver(ctx context.Context, instance *api.Instance, databaseName string) (db.Driver, error) {
-	dataSource := api.DataSourceFromInstanceWithType(instance, api.RO)
-	// If there are no read-only data source, fall back to admin data source.
-	if dataSource == nil {
-		dataSource = api.DataSourceFromInstanceWithType(instance, api.Admin)
-	}
+	dataSource := getReadOnlyDataSource(instance)
 	if dataSource == nil {
 		return nil, common.Errorf(common.Internal, "data source not found for instance %d", instance.ID)
 	}
@@ -101,15 +173,16 @@ func (d *DBFactory) GetReadOnlyDatabaseDriver(ctx context.Context, instance *api
 
 	dbBinDir := ""
 	switch instance.Engine {
-	case db.MySQL, db.TiDB:
+	case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 		dbBinDir = d.mysqlBinDir
 	case db.Postgres:
 		dbBinDir = d.pgBinDir
 	}
 
-	driver, err := getDatabaseDriver(
+	driver, err := d.getDatabaseDriver(
 		ctx,
 		instance.Engine,
+		dataSource.Options,
 		db.DriverConfig{
 			DbBinDir:  dbBinDir,
 			BinlogDir: common.GetBinlogAbsDir(d.dataDir, instance.ID),
@@ -125,7 +198,9 @@ func (d *DBFactory) GetReadOnlyDatabaseDriver(ctx context.Context, instance *api
 				SslCert: dataSource.SslCert,
 				SslKey:  dataSource.SslKey,
 			},
-			ReadOnly: true,
+			ReadOnly:    true,
+			ServiceName: dataSource.Options.ServiceName,
+			SSHConfig:   getSSHConfig(dataSource),
 		},
 		db.ConnectionContext{
 			EnvironmentName: instance.Environment.Name,
@@ -140,7 +215,19 @@ func (d *DBFactory) GetReadOnlyDatabaseDriver(ctx context.Context, instance *api
 }
 
 // Retrieve db.Driver connection with standard parameters for all type data source.
-func getDatabaseDriver(ctx context.Context, engine db.Type, driverConfig db.DriverConfig, connectionConfig db.ConnectionConfig, connCtx db.ConnectionContext) (db.Driver, error) {
+// The password is refreshed by the credential provider in the data source options before connecting.
+func (d *DBFactory) getDatabaseDriver(ctx context.Context, engine db.Type, options api.DataSourceOptions, driverConfig db.DriverConfig, connectionConfig db.ConnectionConfig, connCtx db.ConnectionContext) (db.Driver, error) {
+	password, err := d.GetPassword(ctx, options, CredentialRequest{
+		Host:     connectionConfig.Host,
+		Port:     connectionConfig.Port,
+		Username: connectionConfig.Username,
+		Password: connectionConfig.Password,
+	})
+	if err != nil {
+		return nil, common.Wrapf(err, common.DbConnectionFailure, "failed to get credential for user %q", connectionConfig.Username)
+	}
+	connectionConfig.Password = password
+
 	driver, err := db.Open(
 		ctx,
 		engine,
@@ -153,3 +240,23 @@ func getDatabaseDriver(ctx context.Context, engine db.Type, driverConfig db.Driv
 	}
 	return driver, nil
 }
+
+func getSSHConfig(dataSource *api.DataSource) db.SSHConfig {
+	return db.SSHConfig{
+		Host:       dataSource.SSHHost,
+		Port:       dataSource.SSHPort,
+		User:       dataSource.SSHUser,
+		HostKey:    dataSource.SSHHostKey,
+		Password:   dataSource.SSHPassword,
+		PrivateKey: dataSource.SSHPrivateKey,
+	}
+}
+
+func getReadOnlyDataSource(instance *api.Instance) *api.DataSource {
+	dataSource := api.DataSourceFromInstanceWithType(instance, api.RO)
+	// If there are no read-only data source, fall back to admin data source.
+	if dataSource == nil {
+		dataSource = api.DataSourceFromInstanceWithType(instance, api.Admin)
+	}
+	return dataSource
+}
@@ -0,0 +1,363 @@
+package dbfactory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+const (
+	// maxIdleDriversPerKey is the maximum number of idle drivers kept for each instance data source and database.
+	// The drivers beyond the limit are closed after use.
+	maxIdleDriversPerKey = 4
+	// maxOpenDriversPerInstance is the maximum number of drivers in use and idle for each instance.
+	// The callers wait for a driver to be released beyond the limit, so that the instance isn't flooded with the connections.
+	maxOpenDriversPerInstance = 16
+	// driverAcquireTimeout is the maximum duration waiting for a driver to be released.
+	driverAcquireTimeout = 30 * time.Second
+	// driverIdleTimeout is the duration after which an idle driver is closed.
+	driverIdleTimeout = 5 * time.Minute
+	// driverMaxLifetime is the maximum duration a driver is reused.
+	// It's shorter than the lifetime of the AWS RDS IAM authentication token, so the drivers never open connections with an expired credential.
+	driverMaxLifetime = 10 * time.Minute
+	// driverHealthCheckInterval is the idle duration after which the driver is pinged before reuse.
+	driverHealthCheckInterval = 30 * time.Second
+	driverPoolEvictInterval   = time.Minute
+)
+
+// PoolStats is the usage of the driver pool.
+type PoolStats struct {
+	// InUse is the number of the drivers acquired and not released yet.
+	InUse int `json:"inUse"`
+	// Idle is the number of the drivers kept for reuse.
+	Idle int `json:"idle"`
+	// Hits is the number of the acquisitions reusing an idle driver.
+	Hits int64 `json:"hits"`
+	// Misses is the number of the acquisitions opening a new driver.
+	Misses int64 `json:"misses"`
+	// Waits is the number of the acquisitions waiting for a driver to be released because of the open limit.
+	Waits int64 `json:"waits"`
+	// OpenFailures is the number of the failures opening a new driver.
+	OpenFailures int64 `json:"openFailures"`
+	// HealthCheckFailures is the number of the idle drivers closed because the ping fails.
+	HealthCheckFailures int64 `json:"healthCheckFailures"`
+	// Evictions is the number of the drivers closed because they're idle, expired, invalidated or beyond the idle limit.
+	Evictions int64 `json:"evictions"`
+}
+
+// driverKey identifies the drivers sharing the same connection config.
+type driverKey struct {
+	instanceID   int
+	dataSourceID int
+	databaseName string
+	readOnly     bool
+}
+
+type pooledDriver struct {
+	key    driverKey
+	driver db.Driver
+	// generation is the generation of the instance when the driver is opened.
+	generation int
+	createdTs  time.Time
+	lastUsedTs time.Time
+}
+
+// driverPool keeps the drivers for reuse, so that the frequent callers such as the schema syncer and the SQL editor don't open new connections every time.
+// Each driver is used by one caller at a time.
+type driverPool struct {
+	mu   sync.Mutex
+	idle map[driverKey][]*pooledDriver
+	// open is the number of the drivers in use and idle for each instance.
+	open map[int]int
+	// waiters is closed to wake up the callers waiting for the instance when a driver of the instance is closed.
+	waiters map[int]chan struct{}
+	// generations is increased when the instance is invalidated, the drivers opened with an older generation are closed instead of reused.
+	generations map[int]int
+	stats       PoolStats
+	closed      bool
+}
+
+func newDriverPool() *driverPool {
+	return &driverPool{
+		idle:        make(map[driverKey][]*pooledDriver),
+		open:        make(map[int]int),
+		waiters:     make(map[int]chan struct{}),
+		generations: make(map[int]int),
+	}
+}
+
+// acquire returns an idle driver for the key, or opens a new one.
+// If the instance has reached the open limit, it closes an idle driver of the other keys, or waits for a driver to be released until the context is done or the acquire timeout.
+// The returned release function must be called exactly once instead of closing the driver.
+func (p *driverPool) acquire(ctx context.Context, key driverKey, open func() (db.Driver, error)) (db.Driver, func(), error) {
+	ctx, cancel := context.WithTimeout(ctx, driverAcquireTimeout)
+	defer cancel()
+	for {
+		p.mu.Lock()
+		entry := p.popIdleLocked(key)
+		if entry == nil {
+			if p.open[key.instanceID] < maxOpenDriversPerInstance {
+				break
+			}
+			if victim := p.popInstanceIdleLocked(key.instanceID); victim != nil {
+				p.stats.Evictions++
+				p.closeOpenLocked(victim.key.instanceID)
+				go closeDriver(victim.driver)
+				break
+			}
+			wait := p.waitLocked(key.instanceID)
+			p.stats.Waits++
+			p.mu.Unlock()
+			select {
+			case <-wait:
+				continue
+			case <-ctx.Done():
+				return nil, nil, errors.Wrapf(ctx.Err(), "timeout waiting for a driver of instance %d, which has reached the limit of %d open drivers", key.instanceID, maxOpenDriversPerInstance)
+			}
+		}
+		p.mu.Unlock()
+
+		if time.Since(entry.lastUsedTs) > driverHealthCheckInterval {
+			if err := entry.driver.Ping(ctx); err != nil {
+				log.Debug("Closing the pooled driver failing the health check",
+					zap.Int("instance_id", key.instanceID),
+					zap.String("database", key.databaseName),
+					zap.Error(err))
+				p.mu.Lock()
+				p.stats.HealthCheckFailures++
+				p.closeOpenLocked(key.instanceID)
+				p.mu.Unlock()
+				closeDriver(entry.driver)
+				if ctx.Err() != nil {
+					return nil, nil, ctx.Err()
+				}
+				continue
+			}
+		}
+
+		p.mu.Lock()
+		p.stats.Hits++
+		p.stats.InUse++
+		p.mu.Unlock()
+		return entry.driver, p.releaseFunc(entry), nil
+	}
+	generation := p.generations[key.instanceID]
+	p.stats.Misses++
+	p.open[key.instanceID]++
+	p.mu.Unlock()
+
+	driver, err := open()
+	if err != nil {
+		p.mu.Lock()
+		p.stats.OpenFailures++
+		p.closeOpenLocked(key.instanceID)
+		p.mu.Unlock()
+		return nil, nil, err
+	}
+	now := time.Now()
+	entry := &pooledDriver{
+		key:        key,
+		driver:     driver,
+		generation: generation,
+		createdTs:  now,
+		lastUsedTs: now,
+	}
+	p.mu.Lock()
+	p.stats.InUse++
+	p.mu.Unlock()
+	return driver, p.releaseFunc(entry), nil
+}
+
+// popIdleLocked pops the most recently used driver which is still reusable, and closes the expired ones on the way.
+func (p *driverPool) popIdleLocked(key driverKey) *pooledDriver {
+	for {
+		entries := p.idle[key]
+		if len(entries) == 0 {
+			delete(p.idle, key)
+			return nil
+		}
+		entry := entries[len(entries)-1]
+		p.idle[key] = entries[:len(entries)-1]
+		p.stats.Idle--
+		if p.isReusableLocked(entry, time.Now()) {
+			return entry
+		}
+		p.stats.Evictions++
+		p.closeOpenLocked(key.instanceID)
+		go closeDriver(entry.driver)
+	}
+}
+
+// popInstanceIdleLocked pops the least recently used idle driver of the instance, it returns nil if there are no idle drivers.
+func (p *driverPool) popInstanceIdleLocked(instanceID int) *pooledDriver {
+	var res *pooledDriver
+	for key, entries := range p.idle {
+		if key.instanceID != instanceID || len(entries) == 0 {
+			continue
+		}
+		if res == nil || entries[0].lastUsedTs.Before(res.lastUsedTs) {
+			res = entries[0]
+		}
+	}
+	if res == nil {
+		return nil
+	}
+	entries := p.idle[res.key][1:]
+	if len(entries) == 0 {
+		delete(p.idle, res.key)
+	} else {
+		p.idle[res.key] = entries
+	}
+	p.stats.Idle--
+	return res
+}
+
+// closeOpenLocked decreases the open drivers of the instance after a driver is closed, and wakes up the waiting callers.
+func (p *driverPool) closeOpenLocked(instanceID int) {
+	p.open[instanceID]--
+	if p.open[instanceID] <= 0 {
+		delete(p.open, instanceID)
+	}
+	p.signalLocked(instanceID)
+}
+
+// signalLocked wakes up the callers waiting for the instance.
+func (p *driverPool) signalLocked(instanceID int) {
+	if wait, ok := p.waiters[instanceID]; ok {
+		close(wait)
+		delete(p.waiters, instanceID)
+	}
+}
+
+// waitLocked returns the channel closed when a driver of the instance is released or closed.
+func (p *driverPool) waitLocked(instanceID int) <-chan struct{} {
+	wait, ok := p.waiters[instanceID]
+	if !ok {
+		wait = make(chan struct{})
+		p.waiters[instanceID] = wait
+	}
+	return wait
+}
+
+func (p *driverPool) releaseFunc(entry *pooledDriver) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			p.release(entry)
+		})
+	}
+}
+
+func (p *driverPool) release(entry *pooledDriver) {
+	p.mu.Lock()
+	p.stats.InUse--
+	now := time.Now()
+	if p.closed || !p.isReusableLocked(entry, now) || len(p.idle[entry.key]) >= maxIdleDriversPerKey {
+		p.stats.Evictions++
+		p.closeOpenLocked(entry.key.instanceID)
+		p.mu.Unlock()
+		closeDriver(entry.driver)
+		return
+	}
+	entry.lastUsedTs = now
+	p.idle[entry.key] = append(p.idle[entry.key], entry)
+	p.stats.Idle++
+	// The waiting callers reuse the idle driver for the same key, or close it to open a new one.
+	p.signalLocked(entry.key.instanceID)
+	p.mu.Unlock()
+}
+
+func (p *driverPool) isReusableLocked(entry *pooledDriver, now time.Time) bool {
+	return entry.generation == p.generations[entry.key.instanceID] && now.Sub(entry.createdTs) < driverMaxLifetime
+}
+
+// invalidate closes the idle drivers of the instance, and makes the drivers in use closed after released.
+func (p *driverPool) invalidate(instanceID int) {
+	p.mu.Lock()
+	p.generations[instanceID]++
+	var drivers []db.Driver
+	for key, entries := range p.idle {
+		if key.instanceID != instanceID {
+			continue
+		}
+		for _, entry := range entries {
+			drivers = append(drivers, entry.driver)
+			p.closeOpenLocked(key.instanceID)
+		}
+		p.stats.Idle -= len(entries)
+		p.stats.Evictions += int64(len(entries))
+		delete(p.idle, key)
+	}
+	p.mu.Unlock()
+
+	for _, driver := range drivers {
+		closeDriver(driver)
+	}
+}
+
+// evict closes the drivers idle for too long or expired.
+func (p *driverPool) evict(now time.Time) {
+	p.mu.Lock()
+	var drivers []db.Driver
+	for key, entries := range p.idle {
+		var kept []*pooledDriver
+		for _, entry := range entries {
+			if now.Sub(entry.lastUsedTs) > driverIdleTimeout || !p.isReusableLocked(entry, now) {
+				drivers = append(drivers, entry.driver)
+				p.closeOpenLocked(key.instanceID)
+				continue
+			}
+			kept = append(kept, entry)
+		}
+		p.stats.Idle -= len(entries) - len(kept)
+		p.stats.Evictions += int64(len(entries) - len(kept))
+		if len(kept) == 0 {
+			delete(p.idle, key)
+		} else {
+			p.idle[key] = kept
+		}
+	}
+	p.mu.Unlock()
+
+	for _, driver := range drivers {
+		closeDriver(driver)
+	}
+}
+
+// close closes all the idle drivers, and the drivers in use are closed after released.
+func (p *driverPool) close() {
+	p.mu.Lock()
+	p.closed = true
+	var drivers []db.Driver
+	for key, entries := range p.idle {
+		for _, entry := range entries {
+			drivers = append(drivers, entry.driver)
+			p.closeOpenLocked(key.instanceID)
+		}
+	}
+	p.idle = make(map[driverKey][]*pooledDriver)
+	p.stats.Idle = 0
+	p.mu.Unlock()
+
+	for _, driver := range drivers {
+		closeDriver(driver)
+	}
+}
+
+func (p *driverPool) getStats() PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.stats
+}
+
+func closeDriver(driver db.Driver) {
+	if err := driver.Close(context.Background()); err != nil {
+		log.Debug("Failed to close the pooled driver", zap.Error(err))
+	}
+}
@@ -0,0 +1,181 @@
+package dbfactory
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// fakeDriver implements Ping and Close only, the other methods panic.
+type fakeDriver struct {
+	db.Driver
+
+	mu      sync.Mutex
+	pingErr error
+	closed  bool
+}
+
+func (d *fakeDriver) Ping(_ context.Context) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.pingErr
+}
+
+func (d *fakeDriver) Close(_ context.Context) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.closed = true
+	return nil
+}
+
+func (d *fakeDriver) isClosed() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.closed
+}
+
+func TestDriverPool(t *testing.T) {
+	a := require.New(t)
+	ctx := context.Background()
+	pool := newDriverPool()
+	var opened []*fakeDriver
+	open := func() (db.Driver, error) {
+		driver := &fakeDriver{}
+		opened = append(opened, driver)
+		return driver, nil
+	}
+	key := driverKey{instanceID: 1, dataSourceID: 1, databaseName: "db"}
+
+	driver1, release1, err := pool.acquire(ctx, key, open)
+	a.NoError(err)
+	// The driver in use isn't shared.
+	driver2, release2, err := pool.acquire(ctx, key, open)
+	a.NoError(err)
+	a.NotSame(driver1, driver2)
+	a.Equal(PoolStats{InUse: 2, Misses: 2}, pool.getStats())
+
+	release1()
+	// Releasing twice is a no-op.
+	release1()
+	release2()
+	a.Equal(PoolStats{Idle: 2, Misses: 2}, pool.getStats())
+
+	// The most recently released driver is reused.
+	driver, release, err := pool.acquire(ctx, key, open)
+	a.NoError(err)
+	a.Same(driver2, driver)
+	release()
+	a.Len(opened, 2)
+
+	// The idle driver failing the health check is closed and replaced.
+	for _, entry := range pool.idle[key] {
+		entry.lastUsedTs = time.Now().Add(-2 * driverHealthCheckInterval)
+	}
+	opened[1].pingErr = errors.New("broken pipe")
+	driver, release, err = pool.acquire(ctx, key, open)
+	a.NoError(err)
+	a.Same(driver1, driver)
+	a.True(opened[1].isClosed())
+	a.Equal(int64(1), pool.getStats().HealthCheckFailures)
+
+	// The driver in use is closed after released if the instance is invalidated.
+	pool.invalidate(key.instanceID)
+	a.False(opened[0].isClosed())
+	release()
+	a.True(opened[0].isClosed())
+	a.Equal(0, pool.getStats().Idle)
+	a.Equal(0, pool.getStats().InUse)
+
+	// The drivers beyond the idle limit are closed.
+	var releases []func()
+	for i := 0; i < maxIdleDriversPerKey+1; i++ {
+		_, release, err := pool.acquire(ctx, key, open)
+		a.NoError(err)
+		releases = append(releases, release)
+	}
+	for _, release := range releases {
+		release()
+	}
+	a.Equal(maxIdleDriversPerKey, pool.getStats().Idle)
+	a.True(opened[len(opened)-1].isClosed())
+
+	// The idle drivers are evicted after the idle timeout.
+	pool.evict(time.Now().Add(driverIdleTimeout + time.Second))
+	a.Equal(0, pool.getStats().Idle)
+	for _, driver := range opened {
+		a.True(driver.isClosed())
+	}
+
+	_, _, err = pool.acquire(ctx, key, func() (db.Driver, error) {
+		return nil, errors.New("connection refused")
+	})
+	a.Error(err)
+	a.Equal(int64(1), pool.getStats().OpenFailures)
+	a.Equal(0, pool.getStats().InUse)
+}
+
+func TestDriverPoolOpenLimit(t *testing.T) {
+	a := require.New(t)
+	ctx := context.Background()
+	pool := newDriverPool()
+	open := func() (db.Driver, error) {
+		return &fakeDriver{}, nil
+	}
+	key := driverKey{instanceID: 1, dataSourceID: 1, databaseName: "db"}
+
+	var drivers []db.Driver
+	var releases []func()
+	for i := 0; i < maxOpenDriversPerInstance; i++ {
+		driver, release, err := pool.acquire(ctx, key, open)
+		a.NoError(err)
+		drivers = append(drivers, driver)
+		releases = append(releases, release)
+	}
+
+	// The other instances are not limited.
+	_, release, err := pool.acquire(ctx, driverKey{instanceID: 2, dataSourceID: 2, databaseName: "db"}, open)
+	a.NoError(err)
+	release()
+
+	// The caller waits until the context is done.
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, _, err = pool.acquire(timeoutCtx, key, open)
+	a.ErrorIs(err, context.DeadlineExceeded)
+
+	// The caller waiting reuses the released driver.
+	acquired := make(chan db.Driver)
+	go func() {
+		driver, release, err := pool.acquire(ctx, key, open)
+		if err != nil {
+			acquired <- nil
+			return
+		}
+		release()
+		acquired <- driver
+	}()
+	time.Sleep(10 * time.Millisecond)
+	releases[0]()
+	a.Same(drivers[0], <-acquired)
+	a.Equal(int64(2), pool.getStats().Waits)
+
+	// The idle drivers of the other keys are closed to open the new driver.
+	for _, release := range releases[1:] {
+		release()
+	}
+	a.Len(pool.idle[key], maxIdleDriversPerKey)
+	otherKey := driverKey{instanceID: 1, dataSourceID: 1, databaseName: "other"}
+	for i := 0; i < maxOpenDriversPerInstance; i++ {
+		_, release, err := pool.acquire(ctx, otherKey, open)
+		a.NoError(err)
+		defer release()
+	}
+	a.Empty(pool.idle[key])
+	a.Equal(maxOpenDriversPerInstance, pool.getStats().InUse)
+}
@@ -0,0 +1,119 @@
+package dbfactory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/common"
+)
+
+const (
+	envSecretPrefix   = "env:"
+	vaultSecretPrefix = "vault:"
+	vaultTimeout      = 10 * time.Second
+)
+
+// getSecret resolves the secret reference in the data source options, so the secret is read from the external secret manager on each connection and the rotation takes effect without editing the instance.
+// The supported references are:
+//   - "env:<NAME>" reads the environment variable of the Bytebase server, whose name starts with the configured prefix.
+//   - "vault:<mount>/<path>#<key>" reads the key of the HashiCorp Vault KV version 2 secret in the configured mount, with the server address and the token in VAULT_ADDR and VAULT_TOKEN.
+//
+// The references are limited by the server configuration, so that the other server environment variables and Vault secrets can't be read.
+func (d *DBFactory) getSecret(ctx context.Context, reference string) (string, error) {
+	switch {
+	case strings.HasPrefix(reference, envSecretPrefix):
+		name := strings.TrimPrefix(reference, envSecretPrefix)
+		prefix := d.credentialConfig.SecretEnvPrefix
+		if prefix == "" {
+			return "", common.Errorf(common.Invalid, "environment variable secret reference is disabled, configure --secret-env-prefix to enable it")
+		}
+		if !strings.HasPrefix(name, prefix) || name == prefix {
+			return "", common.Errorf(common.Invalid, "environment variable %q should start with %q", name, prefix)
+		}
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return "", common.Errorf(common.NotFound, "environment variable %q not found", name)
+		}
+		return value, nil
+	case strings.HasPrefix(reference, vaultSecretPrefix):
+		return d.getVaultSecret(ctx, strings.TrimPrefix(reference, vaultSecretPrefix))
+	default:
+		return "", common.Errorf(common.Invalid, "unsupported secret reference %q, it should start with %q or %q", reference, envSecretPrefix, vaultSecretPrefix)
+	}
+}
+
+// getVaultSecret reads the secret with the KV version 2 API, such as "secret/bytebase/mysql#password".
+func (d *DBFactory) getVaultSecret(ctx context.Context, path string) (string, error) {
+	path, key, ok := strings.Cut(path, "#")
+	if !ok || key == "" {
+		return "", common.Errorf(common.Invalid, "missing the key in Vault secret reference %q", path)
+	}
+	mount := strings.Trim(d.credentialConfig.SecretVaultMount, "/")
+	if mount == "" {
+		return "", common.Errorf(common.Invalid, "Vault secret reference is disabled, configure --secret-vault-mount to enable it")
+	}
+	// The mount may contain slashes, e.g. "kv/bytebase".
+	secretPath := strings.Trim(path, "/")
+	if !strings.HasPrefix(secretPath, mount+"/") {
+		return "", common.Errorf(common.Invalid, "Vault secret reference %q should be in the format of %s/<path>#<key>", path, mount)
+	}
+	secretPath = strings.TrimPrefix(secretPath, mount+"/")
+	// The relative segments could escape the mount after the path is cleaned by the Vault server.
+	for _, segment := range strings.Split(secretPath, "/") {
+		if segment == "" || segment == "." || segment == ".." || strings.ContainsAny(segment, "?%") {
+			return "", common.Errorf(common.Invalid, "invalid Vault secret path %q", path)
+		}
+	}
+	address := os.Getenv("VAULT_ADDR")
+	if address == "" {
+		return "", common.Errorf(common.Invalid, "VAULT_ADDR is required for reading the Vault secret")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, vaultTimeout)
+	defer cancel()
+	url := fmt.Sprintf("%s/v1/%s/data/%s", strings.TrimRight(address, "/"), mount, secretPath)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", os.Getenv("VAULT_TOKEN"))
+	if namespace := os.Getenv("VAULT_NAMESPACE"); namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read Vault secret %q", path)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return "", common.Errorf(common.NotFound, "Vault secret %q not found", path)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("failed to read Vault secret %q, status code: %d", path, resp.StatusCode)
+	}
+
+	var secret struct {
+		Data struct {
+			Data map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&secret); err != nil {
+		return "", errors.Wrapf(err, "failed to decode Vault secret %q", path)
+	}
+	value, ok := secret.Data.Data[key]
+	if !ok {
+		return "", common.Errorf(common.NotFound, "key %q not found in Vault secret %q", key, path)
+	}
+	str, ok := value.(string)
+	if !ok {
+		return "", errors.Errorf("key %q in Vault secret %q is not a string", key, path)
+	}
+	return str, nil
+}
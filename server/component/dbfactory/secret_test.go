@@ -0,0 +1,114 @@
+package dbfactory
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+// fakeVault serves the KV version 2 secrets in memory.
+type fakeVault struct {
+	token string
+
+	mu      sync.Mutex
+	secrets map[string]map[string]interface{}
+}
+
+func (v *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-Vault-Token") != v.token {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	data, ok := v.secrets[r.URL.Path]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"data": map[string]interface{}{
+			"data": data,
+		},
+	})
+}
+
+func (v *fakeVault) set(path string, data map[string]interface{}) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.secrets[path] = data
+}
+
+func TestGetSecret(t *testing.T) {
+	a := require.New(t)
+	ctx := context.Background()
+
+	vault := &fakeVault{token: "root", secrets: map[string]map[string]interface{}{}}
+	vault.set("/v1/secret/data/bytebase/mysql", map[string]interface{}{"password": "v1"})
+	server := httptest.NewServer(vault)
+	defer server.Close()
+	t.Setenv("VAULT_ADDR", server.URL)
+	t.Setenv("VAULT_TOKEN", "root")
+	t.Setenv("BB_TEST_MYSQL_PASSWORD", "from-env")
+	t.Setenv("BB_TEST_SERVER_TOKEN", "server-token")
+
+	options := api.DataSourceOptions{PasswordSecret: "vault:secret/bytebase/mysql#password"}
+	d := &DBFactory{
+		credentialConfig: CredentialConfig{
+			SecretEnvPrefix:  "BB_TEST_MYSQL_",
+			SecretVaultMount: "secret",
+		},
+	}
+	password, err := d.GetPassword(ctx, options, CredentialRequest{Password: "stored"})
+	a.NoError(err)
+	a.Equal("v1", password)
+	// The rotated secret is used by the next connection.
+	vault.set("/v1/secret/data/bytebase/mysql", map[string]interface{}{"password": "v2"})
+	password, err = d.GetPassword(ctx, options, CredentialRequest{Password: "stored"})
+	a.NoError(err)
+	a.Equal("v2", password)
+
+	password, err = d.getSecret(ctx, "env:BB_TEST_MYSQL_PASSWORD")
+	a.NoError(err)
+	a.Equal("from-env", password)
+
+	tests := []struct {
+		reference string
+		errorText string
+	}{
+		{reference: "env:BB_TEST_MYSQL_MISSING", errorText: "not found"},
+		// Only the configured env prefix and Vault mount can be referenced.
+		{reference: "env:BB_TEST_SERVER_TOKEN", errorText: "should start with \"BB_TEST_MYSQL_\""},
+		{reference: "env:BB_TEST_MYSQL_", errorText: "should start with"},
+		{reference: "vault:sys/bytebase/mysql#password", errorText: "secret/<path>#<key>"},
+		{reference: "vault:secretive/bytebase/mysql#password", errorText: "secret/<path>#<key>"},
+		{reference: "vault:secret/../../sys/mounts#password", errorText: "invalid Vault secret path"},
+		{reference: "vault:secret/bytebase/mysql#user", errorText: "key \"user\" not found"},
+		{reference: "vault:secret/bytebase/pg#password", errorText: "not found"},
+		{reference: "vault:secret/bytebase/mysql", errorText: "missing the key"},
+		{reference: "vault:secret#password", errorText: "secret/<path>#<key>"},
+		{reference: "file:/tmp/password", errorText: "unsupported secret reference"},
+	}
+	for _, test := range tests {
+		_, err := d.getSecret(ctx, test.reference)
+		a.ErrorContains(err, test.errorText, test.reference)
+	}
+
+	t.Setenv("VAULT_TOKEN", "wrong")
+	_, err = d.getSecret(ctx, "vault:secret/bytebase/mysql#password")
+	a.ErrorContains(err, "status code: 403")
+
+	// The references are disabled without the configuration.
+	d = &DBFactory{}
+	_, err = d.getSecret(ctx, "env:BB_TEST_MYSQL_PASSWORD")
+	a.ErrorContains(err, "--secret-env-prefix")
+	_, err = d.getSecret(ctx, "vault:secret/bytebase/mysql#password")
+	a.ErrorContains(err, "--secret-vault-mount")
+}
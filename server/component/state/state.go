@@ -31,6 +31,8 @@ type State struct {
 	RunningTasks sync.Map // map[taskID]bool
 	// RunningTasksCancel is the cancel's of running tasks.
 	RunningTasksCancel sync.Map // map[taskID]context.CancelFunc
+	// RunningSQLExecutions is the set of running SQL editor executions which can be canceled.
+	RunningSQLExecutions sync.Map // map[executionID]*server.sqlExecution
 	// InstanceOutstandingConnections is the maximum number of connections per instance.
 	InstanceOutstandingConnections map[int]int
 
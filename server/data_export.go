@@ -0,0 +1,113 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/server/component/activity"
+)
+
+// maxDataExportDownloadURLExpiration is the maximum expiration of the presigned URL to download the exported file from the cloud storage.
+const maxDataExportDownloadURLExpiration = 5 * time.Minute
+
+func (s *Server) registerDataExportRoutes(g *echo.Group) {
+	g.GET("/pipeline/:pipelineID/task/:taskID/data-export", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		taskID, err := strconv.Atoi(c.Param("taskID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task ID is not a number: %s", c.Param("taskID"))).SetInternal(err)
+		}
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		role := c.Get(getRoleContextKey()).(api.Role)
+
+		task, err := s.store.GetTaskByID(ctx, taskID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch task ID: %d", taskID)).SetInternal(err)
+		}
+		if task == nil || task.Type != api.TaskDatabaseDataExport {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Data export task not found with ID %d", taskID))
+		}
+		issue, err := s.store.GetIssueByPipelineID(ctx, task.PipelineID)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch issue with pipeline ID: %d", task.PipelineID)).SetInternal(err)
+		}
+		if issue == nil {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Issue not found with pipeline ID: %d", task.PipelineID))
+		}
+		// Only the requester, workspace owner and DBA can download the exported data.
+		if issue.CreatorID != principalID && role != api.Owner && role != api.DBA {
+			return echo.NewHTTPError(http.StatusForbidden, "Only the issue creator, workspace owner and DBA can download the exported data")
+		}
+		if task.Status != api.TaskDone {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Data export task %d has status %s, must be %s", taskID, task.Status, api.TaskDone))
+		}
+
+		payload := &api.TaskDatabaseDataExportPayload{}
+		if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to unmarshal the task payload with ID %d", taskID)).SetInternal(err)
+		}
+		if payload.Path == "" {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Data export task %d has no exported file", taskID))
+		}
+		// The expired file is removed by the backup runner.
+		remaining := time.Until(time.Unix(payload.ExpireTs, 0))
+		if payload.Purged || remaining <= 0 {
+			return echo.NewHTTPError(http.StatusGone, fmt.Sprintf("The exported file of task %d has expired", taskID))
+		}
+
+		s.createDataExportDownloadActivity(ctx, principalID, task, issue, payload)
+
+		filename := fmt.Sprintf("%s-%d.%s", task.Database.Name, task.ID, payload.Format.Extension())
+		switch payload.StorageBackend {
+		case api.BackupStorageBackendLocal:
+			return c.Attachment(filepath.Join(s.profile.DataDir, payload.Path), filename)
+		case api.BackupStorageBackendS3:
+			if remaining > maxDataExportDownloadURLExpiration {
+				remaining = maxDataExportDownloadURLExpiration
+			}
+			url, err := s.s3Client.PresignGetObject(ctx, payload.Path, remaining)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create the download URL of the exported file").SetInternal(err)
+			}
+			return c.Redirect(http.StatusTemporaryRedirect, url)
+		default:
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Unsupported storage backend %s", payload.StorageBackend))
+		}
+	})
+}
+
+func (s *Server) createDataExportDownloadActivity(ctx context.Context, principalID int, task *api.Task, issue *api.Issue, payload *api.TaskDatabaseDataExportPayload) {
+	activityPayload, err := json.Marshal(api.ActivityDatabaseDataExportPayload{
+		IssueID:      issue.ID,
+		TaskID:       task.ID,
+		DatabaseID:   task.Database.ID,
+		DatabaseName: task.Database.Name,
+		Statement:    payload.Statement,
+		Format:       payload.Format,
+		RowCount:     payload.RowCount,
+	})
+	if err != nil {
+		log.Error("failed to marshal data export download activity", zap.Error(err))
+		return
+	}
+	if _, err := s.ActivityManager.CreateActivity(ctx, &api.ActivityCreate{
+		CreatorID:   principalID,
+		ContainerID: issue.ProjectID,
+		Type:        api.ActivityDatabaseDataExportDownload,
+		Level:       api.ActivityInfo,
+		Payload:     string(activityPayload),
+		Comment:     fmt.Sprintf("Downloaded the data exported from database %q.", task.Database.Name),
+	}, &activity.Metadata{Issue: issue}); err != nil {
+		log.Error("cannot create a data export download activity", zap.Error(err))
+	}
+}
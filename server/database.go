@@ -15,7 +15,6 @@ import (
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/common/log"
-	"github.com/bytebase/bytebase/plugin/db"
 	"github.com/bytebase/bytebase/plugin/parser"
 	"github.com/bytebase/bytebase/plugin/parser/edit"
 	"github.com/bytebase/bytebase/server/component/activity"
@@ -312,10 +311,6 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database not found with ID %d", id))
 		}
 
-		if database.Instance.Engine == db.MongoDB {
-			return echo.NewHTTPError(http.StatusBadRequest, "Backup is not supported for MongoDB")
-		}
-
 		storeBackupList, err := s.store.FindBackup(ctx, &api.BackupFind{
 			DatabaseID: &id,
 			Name:       &backupCreate.Name,
@@ -508,6 +503,10 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 			}
 		}
 
+		if err := checkDataSourceCredentialPermission(c.Get(getRoleContextKey()).(api.Role), &dataSourceCreate.Options); err != nil {
+			return err
+		}
+
 		dataSourceCreate.CreatorID = c.Get(getPrincipalIDContextKey()).(int)
 		dataSourceCreate.DatabaseID = databaseID
 
@@ -515,6 +514,7 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create data source").SetInternal(err)
 		}
+		s.dbFactory.Invalidate(database.InstanceID)
 
 		// Refetch the instance to get the updated data source.
 		updatedInstance, err := s.store.GetInstanceByID(ctx, database.InstanceID)
@@ -580,6 +580,11 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 			}
 		}
 
+		// The data source using the external credential can't be changed either, e.g. changing the host sends the credential to the new host.
+		if err := checkDataSourceCredentialPermission(c.Get(getRoleContextKey()).(api.Role), &dataSourceOld.Options, dataSourcePatch.Options); err != nil {
+			return err
+		}
+
 		dataSourcePatch.ID = dataSourceID
 		dataSourcePatch.UpdaterID = c.Get(getPrincipalIDContextKey()).(int)
 
@@ -591,6 +596,7 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to update data source with ID %d", dataSourceID)).SetInternal(err)
 		}
+		s.dbFactory.Invalidate(database.InstanceID)
 
 		// Refetch the instance to get the updated data source.
 		updatedInstance, err := s.store.GetInstanceByID(ctx, database.InstanceID)
@@ -654,6 +660,7 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 		}); err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to delete data source").SetInternal(err)
 		}
+		s.dbFactory.Invalidate(database.InstanceID)
 
 		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
 		c.Response().WriteHeader(http.StatusOK)
@@ -711,3 +718,28 @@ func (s *Server) registerDatabaseRoutes(g *echo.Group) {
 		return nil
 	})
 }
+
+// usesExternalCredential returns true if the password of the data source is resolved by the server from an external source,
+// which can't be changed by the roles other than the workspace owner and DBA.
+func usesExternalCredential(options *api.DataSourceOptions) bool {
+	return options != nil && (options.CredentialProvider == api.CredentialProviderCommand || usesCloudIAMCredential(options.CredentialProvider) || options.PasswordSecret != "")
+}
+
+// usesCloudIAMCredential returns true if the password is the token signed by the cloud credentials of the server,
+// which is sent to the host of the data source as is.
+func usesCloudIAMCredential(provider api.CredentialProviderType) bool {
+	return provider == api.CredentialProviderAWSRDSIAM || provider == api.CredentialProviderGCPCloudSQLIAM
+}
+
+// checkDataSourceCredentialPermission returns an error if the role isn't allowed to create or update the data source with the options.
+func checkDataSourceCredentialPermission(role api.Role, optionsList ...*api.DataSourceOptions) error {
+	if role == api.Owner || role == api.DBA {
+		return nil
+	}
+	for _, options := range optionsList {
+		if usesExternalCredential(options) {
+			return echo.NewHTTPError(http.StatusForbidden, "Only the workspace owner and DBA can manage the data source using the credential command, the cloud IAM authentication or the password secret")
+		}
+	}
+	return nil
+}
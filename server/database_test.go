@@ -0,0 +1,62 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+)
+
+func TestCheckDataSourceCredentialPermission(t *testing.T) {
+	tests := []struct {
+		role    api.Role
+		options *api.DataSourceOptions
+		wantErr bool
+	}{
+		{
+			role:    api.Developer,
+			options: &api.DataSourceOptions{CredentialProvider: api.CredentialProviderStatic},
+			wantErr: false,
+		},
+		{
+			role:    api.Developer,
+			options: &api.DataSourceOptions{CredentialProvider: api.CredentialProviderCommand},
+			wantErr: true,
+		},
+		{
+			role:    api.Developer,
+			options: &api.DataSourceOptions{CredentialProvider: api.CredentialProviderAWSRDSIAM},
+			wantErr: true,
+		},
+		{
+			role:    api.Developer,
+			options: &api.DataSourceOptions{CredentialProvider: api.CredentialProviderGCPCloudSQLIAM},
+			wantErr: true,
+		},
+		{
+			role:    api.Developer,
+			options: &api.DataSourceOptions{PasswordSecret: "env:BYTEBASE_SECRET_MYSQL"},
+			wantErr: true,
+		},
+		{
+			role:    api.DBA,
+			options: &api.DataSourceOptions{CredentialProvider: api.CredentialProviderGCPCloudSQLIAM},
+			wantErr: false,
+		},
+		{
+			role:    api.Owner,
+			options: &api.DataSourceOptions{CredentialProvider: api.CredentialProviderAWSRDSIAM},
+			wantErr: false,
+		},
+	}
+
+	for _, test := range tests {
+		err := checkDataSourceCredentialPermission(test.role, test.options)
+		if test.wantErr {
+			require.Error(t, err, test.options)
+		} else {
+			require.NoError(t, err, test.options)
+		}
+	}
+}
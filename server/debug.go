@@ -31,6 +31,15 @@ func (s *Server) registerDebugRoutes(g *echo.Group) {
 		return currentDebugState(c)
 	})
 
+	g.GET("/debug/driver-pool", func(c echo.Context) error {
+		// Only Owner and DBA can see the driver pool usage.
+		role := c.Get(getRoleContextKey()).(api.Role)
+		if role != api.Owner && role != api.DBA {
+			return echo.NewHTTPError(http.StatusForbidden, "Not allowed to fetch driver pool stats")
+		}
+		return c.JSON(http.StatusOK, s.dbFactory.Stats())
+	})
+
 	g.GET("/debug/log", func(c echo.Context) error {
 		var errorRecordList []*api.DebugLog
 		// incrementID is used as primary key in jsonapi.
@@ -55,8 +55,19 @@ func (s *Server) registerInstanceRoutes(g *echo.Group) {
 					Options: api.DataSourceOptions{
 						SRV:                    instanceCreate.SRV,
 						AuthenticationDatabase: instanceCreate.AuthenticationDatabase,
+						ServiceName:            instanceCreate.ServiceName,
+						CredentialProvider:     instanceCreate.CredentialProvider,
+						Region:                 instanceCreate.Region,
+						CredentialCommandName:  instanceCreate.CredentialCommandName,
+						PasswordSecret:         instanceCreate.PasswordSecret,
 					},
-					Database: instanceCreate.Database,
+					Database:      instanceCreate.Database,
+					SSHHost:       instanceCreate.SSHHost,
+					SSHPort:       instanceCreate.SSHPort,
+					SSHUser:       instanceCreate.SSHUser,
+					SSHHostKey:    instanceCreate.SSHHostKey,
+					SSHPassword:   instanceCreate.SSHPassword,
+					SSHPrivateKey: instanceCreate.SSHPrivateKey,
 				},
 			},
 			Name:         instanceCreate.Name,
@@ -572,6 +583,8 @@ func (s *Server) updateInstance(ctx context.Context, patch *store.InstancePatch)
 			}
 			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to patch instance ID: %v", patch.ID)).SetInternal(err)
 		}
+		// Close the pooled drivers using the outdated connection info or the archived instance.
+		s.dbFactory.Invalidate(patch.ID)
 	}
 
 	// Try immediately setup the migration schema, sync the engine version and schema after updating any connection related info.
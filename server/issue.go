@@ -513,6 +513,8 @@ func (s *Server) getPipelineCreate(ctx context.Context, issueCreate *api.IssueCr
 		return s.getPipelineCreateForDatabaseSchemaAndDataUpdate(ctx, issueCreate)
 	case api.IssueDatabaseRollback:
 		return s.getPipelineCreateForDatabaseRollback(ctx, issueCreate)
+	case api.IssueDatabaseDataExport:
+		return s.getPipelineCreateForDatabaseDataExport(ctx, issueCreate)
 	default:
 		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid issue type %q", issueCreate.Type))
 	}
@@ -546,8 +548,8 @@ func (s *Server) getPipelineCreateForDatabaseRollback(ctx context.Context, issue
 	if task.Type != api.TaskDatabaseDataUpdate {
 		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task type must be %s, but got %s", api.TaskDatabaseDataUpdate, task.Type))
 	}
-	if task.Database.Instance.Engine != db.MySQL {
-		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Only support rollback for MySQL now, but got %s", task.Database.Instance.Engine))
+	if task.Database.Instance.Engine != db.MySQL && task.Database.Instance.Engine != db.Postgres {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Only support rollback for MySQL and PostgreSQL now, but got %s", task.Database.Instance.Engine))
 	}
 	if task.PipelineID != issue.PipelineID {
 		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Task %d is not in issue %d", taskID, issue.ID))
@@ -610,6 +612,77 @@ func (s *Server) getPipelineCreateForDatabaseRollback(ctx context.Context, issue
 	return pipelineCreate, nil
 }
 
+func (s *Server) getPipelineCreateForDatabaseDataExport(ctx context.Context, issueCreate *api.IssueCreate) (*api.PipelineCreate, error) {
+	c := api.DataExportContext{}
+	if err := json.Unmarshal([]byte(issueCreate.CreateContext), &c); err != nil {
+		return nil, err
+	}
+	switch c.Format {
+	case api.DataExportFormatCSV, api.DataExportFormatJSON, api.DataExportFormatSQL:
+	default:
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid data export format %q", c.Format))
+	}
+	if c.Limit < 0 || c.Limit > api.MaxDataExportLimit {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("The data export limit must be between 0 and %d", api.MaxDataExportLimit))
+	}
+
+	database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &c.DatabaseID})
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database ID: %v", c.DatabaseID)).SetInternal(err)
+	}
+	if database == nil {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database ID not found: %d", c.DatabaseID))
+	}
+	if database.ProjectID != issueCreate.ProjectID {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("The issue project %d must be the same as the database project %d.", issueCreate.ProjectID, database.ProjectID))
+	}
+	if database.Instance.Engine == db.Redis || database.Instance.Engine == db.MongoDB {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Data export is not supported for %s", database.Instance.Engine))
+	}
+	if !validateSQLSelectStatement(c.Statement) {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Only a single SELECT statement can be exported")
+	}
+	if err := utils.CheckCrossDatabaseQuery(database.Instance.Engine, c.Statement, database.Name); err != nil {
+		if common.ErrorCode(err) == common.Invalid {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to check the databases accessed by the statement").SetInternal(err)
+	}
+
+	payload := api.TaskDatabaseDataExportPayload{
+		Statement: c.Statement,
+		Format:    c.Format,
+		Limit:     c.Limit,
+		TableName: c.TableName,
+	}
+	bytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal database data export payload").SetInternal(err)
+	}
+
+	return &api.PipelineCreate{
+		Name:      fmt.Sprintf("Pipeline - Export data from database %s", database.Name),
+		CreatorID: issueCreate.CreatorID,
+		StageList: []api.StageCreate{
+			{
+				Name:          database.Instance.Environment.Name,
+				EnvironmentID: database.Instance.Environment.ID,
+				TaskList: []api.TaskCreate{
+					{
+						Name:       fmt.Sprintf("Export data from database %q", database.Name),
+						InstanceID: database.InstanceID,
+						DatabaseID: &database.ID,
+						Status:     api.TaskPendingApproval,
+						Type:       api.TaskDatabaseDataExport,
+						Statement:  c.Statement,
+						Payload:    string(bytes),
+					},
+				},
+			},
+		},
+	}, nil
+}
+
 func (s *Server) getPipelineCreateForDatabaseCreate(ctx context.Context, issueCreate *api.IssueCreate) (*api.PipelineCreate, error) {
 	c := api.CreateDatabaseContext{}
 	if err := json.Unmarshal([]byte(issueCreate.CreateContext), &c); err != nil {
@@ -1184,7 +1257,7 @@ func (s *Server) createPITRTaskList(ctx context.Context, originDatabase *api.Dat
 func getCreateDatabaseStatement(dbType db.Type, createDatabaseContext api.CreateDatabaseContext, databaseName, adminDatasourceUser string) (string, error) {
 	var stmt string
 	switch dbType {
-	case db.MySQL, db.TiDB:
+	case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 		return fmt.Sprintf("CREATE DATABASE `%s` CHARACTER SET %s COLLATE %s;", databaseName, createDatabaseContext.CharacterSet, createDatabaseContext.Collation), nil
 	case db.Postgres:
 		// On Cloud RDS, the data source role isn't the actual superuser with sudo privilege.
@@ -1218,6 +1291,11 @@ func getCreateDatabaseStatement(dbType db.Type, createDatabaseContext api.Create
 	case db.SQLite:
 		// This is a fake CREATE DATABASE and USE statement since a single SQLite file represents a database. Engine driver will recognize it and establish a connection to create the sqlite file representing the database.
 		return fmt.Sprintf("CREATE DATABASE '%s';", databaseName), nil
+	case db.MSSQL:
+		if createDatabaseContext.Collation == "" {
+			return fmt.Sprintf("CREATE DATABASE [%s];", databaseName), nil
+		}
+		return fmt.Sprintf("CREATE DATABASE [%s] COLLATE %s;", databaseName, createDatabaseContext.Collation), nil
 	case db.MongoDB:
 		// We just run createCollection in mongosh instead of execute `use <database>` first, because we execute the
 		// mongodb statement in mongosh with --file flag, and it doesn't support `use <database>` statement in the file.
@@ -1297,6 +1375,11 @@ func checkCharacterSetCollationOwner(dbType db.Type, characterSet, collation, ow
 		if owner == "" {
 			return errors.Errorf("database owner is required for PostgreSQL")
 		}
+	case db.MSSQL:
+		// MSSQL does not support character set at the database level, and the collation falls back to the server default.
+		if characterSet != "" {
+			return errors.Errorf("MSSQL does not support character set, but got %s", characterSet)
+		}
 	case db.SQLite, db.MongoDB:
 		// no-op.
 	default:
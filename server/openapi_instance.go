@@ -299,6 +299,9 @@ func (s *Server) createDatabaseRole(c echo.Context) error {
 		return role, nil
 	}()
 	if err != nil {
+		if common.ErrorCode(err) == common.Invalid {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create the role").SetInternal(err)
 	}
 
@@ -369,6 +372,9 @@ func (s *Server) updateDatabaseRole(c echo.Context) error {
 		if common.ErrorCode(err) == common.NotFound {
 			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Cannot found the role %s in instance %d", rawName, instance.ID))
 		}
+		if common.ErrorCode(err) == common.Invalid {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update the role").SetInternal(err)
 	}
 
@@ -401,7 +407,9 @@ func (s *Server) validateInstance(ctx context.Context, c echo.Context) (*api.Ins
 	if instance == nil {
 		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance ID not found: %d", instanceID))
 	}
-	if instance.Engine != db.Postgres {
+	switch instance.Engine {
+	case db.Postgres, db.MySQL, db.MariaDB, db.Snowflake, db.ClickHouse, db.MongoDB, db.MSSQL:
+	default:
 		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Role management for %v is not support", instance.Engine))
 	}
 
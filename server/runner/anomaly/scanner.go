@@ -157,7 +157,7 @@ func (s *Scanner) Run(ctx context.Context, wg *sync.WaitGroup) {
 }
 
 func (s *Scanner) checkInstanceAnomaly(ctx context.Context, instance *api.Instance) {
-	driver, err := s.dbFactory.GetAdminDatabaseDriver(ctx, instance, "" /* databaseName */)
+	driver, release, err := s.dbFactory.AcquireAdminDatabaseDriver(ctx, instance, "" /* databaseName */)
 
 	// Check connection
 	if err != nil {
@@ -186,7 +186,7 @@ func (s *Scanner) checkInstanceAnomaly(ctx context.Context, instance *api.Instan
 		return
 	}
 
-	defer driver.Close(ctx)
+	defer release()
 	err = s.store.ArchiveAnomaly(ctx, &api.AnomalyArchive{
 		InstanceID: &instance.ID,
 		Type:       api.AnomalyInstanceConnection,
@@ -235,7 +235,7 @@ func (s *Scanner) checkInstanceAnomaly(ctx context.Context, instance *api.Instan
 }
 
 func (s *Scanner) checkDatabaseAnomaly(ctx context.Context, instance *api.Instance, database *api.Database) {
-	driver, err := s.dbFactory.GetAdminDatabaseDriver(ctx, instance, database.Name)
+	driver, release, err := s.dbFactory.AcquireAdminDatabaseDriver(ctx, instance, database.Name)
 
 	// Check connection
 	if err != nil {
@@ -266,7 +266,7 @@ func (s *Scanner) checkDatabaseAnomaly(ctx context.Context, instance *api.Instan
 		}
 		return
 	}
-	defer driver.Close(ctx)
+	defer release()
 	err = s.store.ArchiveAnomaly(ctx, &api.AnomalyArchive{
 		DatabaseID: &database.ID,
 		Type:       api.AnomalyDatabaseConnection,
@@ -21,6 +21,7 @@ import (
 	"github.com/bytebase/bytebase/common/log"
 	"github.com/bytebase/bytebase/plugin/db"
 	"github.com/bytebase/bytebase/plugin/db/mysql"
+	"github.com/bytebase/bytebase/plugin/db/pg"
 	"github.com/bytebase/bytebase/plugin/storage/s3"
 	"github.com/bytebase/bytebase/server/component/config"
 	"github.com/bytebase/bytebase/server/component/dbfactory"
@@ -38,6 +39,7 @@ func NewRunner(store *store.Store, dbFactory *dbfactory.DBFactory, s3Client *s3.
 		stateCfg:                  stateCfg,
 		profile:                   profile,
 		downloadBinlogInstanceIDs: make(map[int]bool),
+		archiveWALInstanceIDs:     make(map[int]bool),
 	}
 }
 
@@ -52,6 +54,9 @@ type Runner struct {
 	backupWg                  sync.WaitGroup
 	downloadBinlogWg          sync.WaitGroup
 	downloadBinlogMu          sync.Mutex
+	archiveWALInstanceIDs     map[int]bool
+	archiveWALWg              sync.WaitGroup
+	archiveWALMu              sync.Mutex
 }
 
 // Run is the runner for backup runner.
@@ -75,16 +80,78 @@ func (r *Runner) Run(ctx context.Context, wg *sync.WaitGroup) {
 				}()
 				r.startAutoBackups(ctx)
 				r.downloadBinlogFiles(ctx)
+				r.archivePostgresWAL(ctx)
 				r.purgeExpiredBackupData(ctx)
+				r.purgeExpiredDataExportFiles(ctx)
 			}()
 		case <-ctx.Done(): // if cancel() execute
 			r.backupWg.Wait()
 			r.downloadBinlogWg.Wait()
+			r.archiveWALWg.Wait()
 			return
 		}
 	}
 }
 
+// purgeExpiredDataExportFiles removes the expired files exported by the data export tasks, whether they are downloaded or not.
+func (r *Runner) purgeExpiredDataExportFiles(ctx context.Context) {
+	taskList, err := r.store.FindTask(ctx, &api.TaskFind{
+		StatusList: &[]api.TaskStatus{api.TaskDone},
+		TypeList:   &[]api.TaskType{api.TaskDatabaseDataExport},
+		Payload:    fmt.Sprintf("payload->>'path' IS NOT NULL AND payload->>'purged' IS NULL AND (payload->>'expireTs')::BIGINT <= %d", time.Now().Unix()),
+	}, false /* returnOnErr */)
+	if err != nil {
+		log.Error("Failed to find the data export tasks with expired files.", zap.Error(err))
+		return
+	}
+	for _, task := range taskList {
+		payload := &api.TaskDatabaseDataExportPayload{}
+		if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+			log.Error("Failed to unmarshal the data export task payload.", zap.Int("task", task.ID), zap.Error(err))
+			continue
+		}
+		if err := r.removeDataExportFile(ctx, payload); err != nil {
+			log.Error("Failed to remove the expired data export file.", zap.Int("task", task.ID), zap.String("path", payload.Path), zap.Error(err))
+			continue
+		}
+		payload.Purged = true
+		payloadBytes, err := json.Marshal(payload)
+		if err != nil {
+			log.Error("Failed to marshal the data export task payload.", zap.Int("task", task.ID), zap.Error(err))
+			continue
+		}
+		payloadString := string(payloadBytes)
+		if _, err := r.store.PatchTask(ctx, &api.TaskPatch{
+			ID:        task.ID,
+			UpdaterID: api.SystemBotID,
+			Payload:   &payloadString,
+		}); err != nil {
+			log.Error("Failed to mark the data export file as purged.", zap.Int("task", task.ID), zap.Error(err))
+		}
+	}
+}
+
+func (r *Runner) removeDataExportFile(ctx context.Context, payload *api.TaskDatabaseDataExportPayload) error {
+	switch payload.StorageBackend {
+	case api.BackupStorageBackendLocal:
+		path := filepath.Join(r.profile.DataDir, payload.Path)
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "failed to remove %q", path)
+		}
+		return nil
+	case api.BackupStorageBackendS3:
+		if r.s3Client == nil {
+			return errors.Errorf("the cloud storage is not configured")
+		}
+		if _, err := r.s3Client.DeleteObjects(ctx, payload.Path); err != nil {
+			return errors.Wrapf(err, "failed to remove %q in the cloud storage", payload.Path)
+		}
+		return nil
+	default:
+		return errors.Errorf("unsupported storage backend %s", payload.StorageBackend)
+	}
+}
+
 // TODO(dragonly): Make best effort to assure that users could recover to at least RetentionPeriodTs ago.
 // This may require pending deleting expired backup files and binlog files.
 func (r *Runner) purgeExpiredBackupData(ctx context.Context) {
@@ -129,7 +196,7 @@ func (r *Runner) purgeExpiredBackupData(ctx context.Context) {
 		if instance.Engine != db.MySQL {
 			continue
 		}
-		maxRetentionPeriodTs, err := r.getMaxRetentionPeriodTsForMySQLInstance(ctx, instance)
+		maxRetentionPeriodTs, err := r.getMaxRetentionPeriodTsForInstance(ctx, instance)
 		if err != nil {
 			log.Error("Failed to get max retention period for MySQL instance", zap.String("instance", instance.Name), zap.Error(err))
 			continue
@@ -143,7 +210,7 @@ func (r *Runner) purgeExpiredBackupData(ctx context.Context) {
 	}
 }
 
-func (r *Runner) getMaxRetentionPeriodTsForMySQLInstance(ctx context.Context, instance *api.Instance) (int, error) {
+func (r *Runner) getMaxRetentionPeriodTsForInstance(ctx context.Context, instance *api.Instance) (int, error) {
 	backupSettingList, err := r.store.FindBackupSetting(ctx, api.BackupSettingFind{InstanceID: &instance.ID})
 	if err != nil {
 		log.Error("Failed to find backup settings for instance.", zap.String("instance", instance.Name), zap.Error(err))
@@ -298,6 +365,133 @@ func (r *Runner) downloadBinlogFilesForInstance(ctx context.Context, instance *a
 	}
 }
 
+// archivePostgresWAL archives the WAL files and takes the base backups for the PostgreSQL instances
+// whose environment requires PITR in the backup plan policy.
+// The replication slots are dropped for the instances no longer requiring PITR, including the archived ones.
+func (r *Runner) archivePostgresWAL(ctx context.Context) {
+	instanceList, err := r.store.FindInstance(ctx, &api.InstanceFind{})
+	if err != nil {
+		log.Error("Failed to find instances.", zap.Error(err))
+		return
+	}
+
+	r.archiveWALMu.Lock()
+	defer r.archiveWALMu.Unlock()
+	for _, instance := range instanceList {
+		if instance.Engine != db.Postgres {
+			continue
+		}
+		if _, ok := r.archiveWALInstanceIDs[instance.ID]; ok {
+			continue
+		}
+		policy, err := r.store.GetBackupPlanPolicyByEnvID(ctx, instance.EnvironmentID)
+		if err != nil {
+			log.Error("Failed to get backup plan policy", zap.String("instance", instance.Name), zap.Error(err))
+			continue
+		}
+		if !policy.PITR || instance.RowStatus == api.Archived {
+			// The replication slot makes the server retain the WAL files forever, so we drop it once the PITR is disabled.
+			if pg.HasReplicationSlot(common.GetBinlogAbsDir(r.profile.DataDir, instance.ID)) {
+				r.archiveWALInstanceIDs[instance.ID] = true
+				go r.dropPostgresReplicationSlot(ctx, instance)
+				r.archiveWALWg.Add(1)
+			}
+			continue
+		}
+		r.archiveWALInstanceIDs[instance.ID] = true
+		go r.archivePostgresWALForInstance(ctx, instance, policy)
+		r.archiveWALWg.Add(1)
+	}
+}
+
+func (r *Runner) dropPostgresReplicationSlot(ctx context.Context, instance *api.Instance) {
+	defer func() {
+		r.archiveWALMu.Lock()
+		delete(r.archiveWALInstanceIDs, instance.ID)
+		r.archiveWALMu.Unlock()
+		r.archiveWALWg.Done()
+	}()
+	driver, err := r.dbFactory.GetAdminDatabaseDriver(ctx, instance, "" /* databaseName */)
+	if err != nil {
+		if common.ErrorCode(err) == common.DbConnectionFailure {
+			log.Debug("Cannot connect to instance", zap.String("instance", instance.Name), zap.Error(err))
+			return
+		}
+		log.Error("Failed to get driver for PostgreSQL instance when dropping replication slot", zap.String("instance", instance.Name), zap.Error(err))
+		return
+	}
+	defer driver.Close(ctx)
+
+	pgDriver, ok := driver.(*pg.Driver)
+	if !ok {
+		log.Error("Failed to cast driver to pg.Driver", zap.String("instance", instance.Name))
+		return
+	}
+	if err := pgDriver.DropReplicationSlot(ctx); err != nil {
+		log.Error("Failed to drop replication slot for instance", zap.String("instance", instance.Name), zap.Error(err))
+	}
+}
+
+func (r *Runner) archivePostgresWALForInstance(ctx context.Context, instance *api.Instance, policy *api.BackupPlanPolicy) {
+	defer func() {
+		r.archiveWALMu.Lock()
+		delete(r.archiveWALInstanceIDs, instance.ID)
+		r.archiveWALMu.Unlock()
+		r.archiveWALWg.Done()
+	}()
+	driver, err := r.dbFactory.GetAdminDatabaseDriver(ctx, instance, "" /* databaseName */)
+	if err != nil {
+		if common.ErrorCode(err) == common.DbConnectionFailure {
+			log.Debug("Cannot connect to instance", zap.String("instance", instance.Name), zap.Error(err))
+			return
+		}
+		log.Error("Failed to get driver for PostgreSQL instance when archiving WAL", zap.String("instance", instance.Name), zap.Error(err))
+		return
+	}
+	defer driver.Close(ctx)
+
+	pgDriver, ok := driver.(*pg.Driver)
+	if !ok {
+		log.Error("Failed to cast driver to pg.Driver", zap.String("instance", instance.Name))
+		return
+	}
+	if err := pgDriver.ArchiveWAL(ctx, r.s3Client); err != nil {
+		log.Error("Failed to archive WAL files for instance", zap.String("instance", instance.Name), zap.Error(err))
+		return
+	}
+
+	baseBackupList, err := pgDriver.ListBaseBackups(ctx, r.s3Client)
+	if err != nil {
+		log.Error("Failed to list base backups for instance", zap.String("instance", instance.Name), zap.Error(err))
+		return
+	}
+	baseBackupInterval := 24 * time.Hour
+	if policy.Schedule == api.BackupPlanPolicyScheduleWeekly {
+		baseBackupInterval = 7 * 24 * time.Hour
+	}
+	if len(baseBackupList) == 0 || time.Since(time.Unix(baseBackupList[len(baseBackupList)-1].EndTs, 0)) >= baseBackupInterval {
+		if _, err := pgDriver.TakeBaseBackup(ctx, r.s3Client); err != nil {
+			log.Error("Failed to take base backup for instance", zap.String("instance", instance.Name), zap.Error(err))
+			return
+		}
+	}
+
+	retentionPeriodTs, err := r.getMaxRetentionPeriodTsForInstance(ctx, instance)
+	if err != nil {
+		log.Error("Failed to get max retention period for PostgreSQL instance", zap.String("instance", instance.Name), zap.Error(err))
+		return
+	}
+	if retentionPeriodTs == math.MaxInt && policy.RetentionPeriodTs > 0 {
+		retentionPeriodTs = policy.RetentionPeriodTs
+	}
+	if retentionPeriodTs == math.MaxInt {
+		return
+	}
+	if err := pgDriver.PurgeArchive(ctx, r.s3Client, retentionPeriodTs); err != nil {
+		log.Error("Failed to purge WAL archive for instance", zap.String("instance", instance.Name), zap.Int("retentionPeriodTs", retentionPeriodTs), zap.Error(err))
+	}
+}
+
 func (r *Runner) startAutoBackups(ctx context.Context) {
 	// Find all databases that need a backup in this hour.
 	t := time.Now().UTC().Truncate(time.Hour)
@@ -0,0 +1,272 @@
+package schemasync
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
+	storepb "github.com/bytebase/bytebase/proto/generated-go/store"
+	"github.com/bytebase/bytebase/store"
+)
+
+const (
+	// sampleQueryTimeout is the timeout of the query sampling the values of a column.
+	sampleQueryTimeout = 10 * time.Second
+)
+
+// classificationRule is the compiled api.SensitiveDataClassificationRule.
+type classificationRule struct {
+	rule          api.SensitiveDataClassificationRule
+	columnNameReg *regexp.Regexp
+	commentReg    *regexp.Regexp
+	typeReg       *regexp.Regexp
+	valueReg      *regexp.Regexp
+}
+
+// classificationColumn is the column to classify.
+type classificationColumn struct {
+	name        string
+	comment     string
+	columnType  string
+	sampleValue func() ([]string, error)
+}
+
+func compileClassificationRules(ruleList []api.SensitiveDataClassificationRule) ([]*classificationRule, error) {
+	compile := func(pattern string) (*regexp.Regexp, error) {
+		if pattern == "" {
+			return nil, nil
+		}
+		return regexp.Compile(pattern)
+	}
+
+	var compiledList []*classificationRule
+	for _, rule := range ruleList {
+		compiled := &classificationRule{rule: rule}
+		var err error
+		if compiled.columnNameReg, err = compile(rule.ColumnNamePattern); err != nil {
+			return nil, errors.Wrapf(err, "invalid column name pattern in classification rule %q", rule.Title)
+		}
+		if compiled.commentReg, err = compile(rule.CommentPattern); err != nil {
+			return nil, errors.Wrapf(err, "invalid comment pattern in classification rule %q", rule.Title)
+		}
+		if compiled.typeReg, err = compile(rule.TypePattern); err != nil {
+			return nil, errors.Wrapf(err, "invalid type pattern in classification rule %q", rule.Title)
+		}
+		if compiled.valueReg, err = compile(rule.ValuePattern); err != nil {
+			return nil, errors.Wrapf(err, "invalid value pattern in classification rule %q", rule.Title)
+		}
+		compiledList = append(compiledList, compiled)
+	}
+	return compiledList, nil
+}
+
+// classifyColumn returns the first rule matching the column and the reason, or nil if no rule matches.
+// The values are sampled at most once and only if the metadata of the column doesn't match any rule.
+func classifyColumn(ruleList []*classificationRule, column *classificationColumn) (*classificationRule, string, error) {
+	var candidateList []*classificationRule
+	for _, rule := range ruleList {
+		if rule.typeReg != nil && !rule.typeReg.MatchString(column.columnType) {
+			continue
+		}
+		if rule.columnNameReg != nil && rule.columnNameReg.MatchString(column.name) {
+			return rule, "column name", nil
+		}
+		if rule.commentReg != nil && column.comment != "" && rule.commentReg.MatchString(column.comment) {
+			return rule, "comment", nil
+		}
+		if rule.valueReg != nil {
+			candidateList = append(candidateList, rule)
+		}
+	}
+	if len(candidateList) == 0 || column.sampleValue == nil {
+		return nil, "", nil
+	}
+
+	valueList, err := column.sampleValue()
+	if err != nil {
+		return nil, "", err
+	}
+	// Nothing can be told from the empty column.
+	if len(valueList) == 0 {
+		return nil, "", nil
+	}
+	for _, rule := range candidateList {
+		matched := true
+		for _, value := range valueList {
+			if !rule.valueReg.MatchString(value) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return rule, "sampled values", nil
+		}
+	}
+	return nil, "", nil
+}
+
+// classifySensitiveData proposes the sensitive columns of the database with the classification rules.
+// The columns in the sensitive data policy or proposed before are skipped.
+func classifySensitiveData(ctx context.Context, stores *store.Store, database *api.Database, databaseMetadata *storepb.DatabaseMetadata, driver db.Driver) error {
+	settingName := api.SettingSensitiveDataClassification
+	setting, err := stores.GetSetting(ctx, &api.SettingFind{Name: &settingName})
+	if err != nil {
+		return errors.Wrapf(err, "failed to get setting %q", settingName)
+	}
+	if setting == nil || setting.Value == "" {
+		return nil
+	}
+	classification, err := api.UnmarshalSensitiveDataClassification(setting.Value)
+	if err != nil {
+		return err
+	}
+	if !classification.Enabled || len(classification.RuleList) == 0 {
+		return nil
+	}
+	ruleList, err := compileClassificationRules(classification.RuleList)
+	if err != nil {
+		return err
+	}
+
+	policy, err := stores.GetSensitiveDataPolicy(ctx, database.ID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the sensitive data policy for database %q", database.Name)
+	}
+	dbType := driver.GetType()
+	// The columns in the policy or proposed before, including the pending and rejected proposals, are not proposed again.
+	skippedColumns := make(map[api.SensitiveData]bool)
+	for _, data := range policy.SensitiveDataList {
+		skippedColumns[api.SensitiveData{
+			Schema: api.NormalizeSensitiveDataSchema(dbType, data.Schema),
+			Table:  data.Table,
+			Column: data.Column,
+		}] = true
+	}
+	proposalList, err := stores.FindSensitiveDataProposal(ctx, &api.SensitiveDataProposalFind{DatabaseID: &database.ID})
+	if err != nil {
+		return errors.Wrapf(err, "failed to find the sensitive data proposals for database %q", database.Name)
+	}
+	for _, proposal := range proposalList {
+		skippedColumns[api.SensitiveData{
+			Schema: api.NormalizeSensitiveDataSchema(dbType, proposal.Schema),
+			Table:  proposal.Table,
+			Column: proposal.Column,
+		}] = true
+	}
+
+	for _, schema := range databaseMetadata.Schemas {
+		for _, table := range schema.Tables {
+			for _, column := range table.Columns {
+				if skippedColumns[api.SensitiveData{
+					Schema: api.NormalizeSensitiveDataSchema(dbType, schema.Name),
+					Table:  table.Name,
+					Column: column.Name,
+				}] {
+					continue
+				}
+				classificationColumn := &classificationColumn{
+					name:       column.Name,
+					comment:    column.Comment,
+					columnType: column.Type,
+				}
+				if classification.SampleLimit > 0 {
+					if statement, ok := getSampleStatement(dbType, schema.Name, table.Name, column.Name); ok {
+						classificationColumn.sampleValue = func() ([]string, error) {
+							return sampleColumnValue(ctx, driver, statement, classification.SampleLimit)
+						}
+					}
+				}
+
+				rule, reason, err := classifyColumn(ruleList, classificationColumn)
+				if err != nil {
+					// The sampling failure of a column shouldn't stop classifying others.
+					log.Warn("Failed to sample the column values",
+						zap.String("database", database.Name),
+						zap.String("table", table.Name),
+						zap.String("column", column.Name),
+						zap.Error(err))
+					continue
+				}
+				if rule == nil {
+					continue
+				}
+
+				payload, err := json.Marshal(api.SensitiveDataProposalPayload{
+					RuleTitle:  rule.rule.Title,
+					Reason:     reason,
+					MaskType:   rule.rule.MaskType,
+					MaskOption: rule.rule.MaskOption,
+				})
+				if err != nil {
+					return errors.Wrapf(err, "failed to marshal sensitive data proposal payload")
+				}
+				if _, err := stores.CreateSensitiveDataProposalIfNotExist(ctx, &api.SensitiveDataProposalCreate{
+					CreatorID:  api.SystemBotID,
+					DatabaseID: database.ID,
+					Schema:     schema.Name,
+					Table:      table.Name,
+					Column:     column.Name,
+					Payload:    string(payload),
+				}); err != nil {
+					return errors.Wrapf(err, "failed to create sensitive data proposal for column %q.%q", table.Name, column.Name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// getSampleStatement returns the statement selecting the column, and false if the engine doesn't support sampling.
+func getSampleStatement(dbType db.Type, schema string, table string, column string) (string, bool) {
+	switch dbType {
+	case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
+		quote := func(identifier string) string {
+			return fmt.Sprintf("`%s`", strings.ReplaceAll(identifier, "`", "``"))
+		}
+		return fmt.Sprintf("SELECT %s FROM %s", quote(column), quote(table)), true
+	case db.Postgres:
+		quote := func(identifier string) string {
+			return fmt.Sprintf(`"%s"`, strings.ReplaceAll(identifier, `"`, `""`))
+		}
+		return fmt.Sprintf("SELECT %s FROM %s.%s", quote(column), quote(schema), quote(table)), true
+	default:
+		return "", false
+	}
+}
+
+// sampleColumnValue returns at most limit non-NULL values of the column.
+func sampleColumnValue(ctx context.Context, driver db.Driver, statement string, limit int) ([]string, error) {
+	result, err := driver.Query(ctx, statement, &db.QueryContext{
+		Limit:    limit,
+		ReadOnly: true,
+		Timeout:  sampleQueryTimeout,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(result) < 3 {
+		return nil, nil
+	}
+	rowList, ok := result[2].([]interface{})
+	if !ok {
+		return nil, errors.Errorf("unexpected query result type %T", result[2])
+	}
+	var valueList []string
+	for _, row := range rowList {
+		row, ok := row.([]interface{})
+		if !ok || len(row) == 0 || row[0] == nil {
+			continue
+		}
+		valueList = append(valueList, fmt.Sprint(row[0]))
+	}
+	return valueList, nil
+}
@@ -0,0 +1,109 @@
+package schemasync
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestClassifyColumn(t *testing.T) {
+	ruleList, err := compileClassificationRules(api.GetDefaultSensitiveDataClassification().RuleList)
+	require.NoError(t, err)
+	typedRuleList, err := compileClassificationRules([]api.SensitiveDataClassificationRule{
+		{
+			Title:          "Salary",
+			CommentPattern: `(?i)salary`,
+			TypePattern:    `(?i)^(int|decimal)`,
+			MaskType:       api.SensitiveDataMaskTypeRange,
+			MaskOption:     &api.SensitiveDataMaskOption{RangeWidth: 1000},
+		},
+	})
+	require.NoError(t, err)
+
+	sample := func(valueList ...string) func() ([]string, error) {
+		return func() ([]string, error) {
+			return valueList, nil
+		}
+	}
+	tests := []struct {
+		ruleList   []*classificationRule
+		column     *classificationColumn
+		wantTitle  string
+		wantReason string
+	}{
+		{
+			ruleList:   ruleList,
+			column:     &classificationColumn{name: "user_email", columnType: "varchar(255)"},
+			wantTitle:  "Email",
+			wantReason: "column name",
+		},
+		{
+			ruleList:   ruleList,
+			column:     &classificationColumn{name: "mobile_no", columnType: "varchar(20)"},
+			wantTitle:  "Phone number",
+			wantReason: "column name",
+		},
+		{
+			ruleList: ruleList,
+			column:   &classificationColumn{name: "emails_sent", columnType: "int"},
+		},
+		{
+			ruleList:   ruleList,
+			column:     &classificationColumn{name: "contact", columnType: "text", sampleValue: sample("alice@example.com", "bob@example.org")},
+			wantTitle:  "Email",
+			wantReason: "sampled values",
+		},
+		{
+			ruleList: ruleList,
+			column:   &classificationColumn{name: "contact", columnType: "text", sampleValue: sample("alice@example.com", "bob")},
+		},
+		{
+			ruleList:   ruleList,
+			column:     &classificationColumn{name: "payment", columnType: "text", sampleValue: sample("4111 1111 1111 1111")},
+			wantTitle:  "Bank card",
+			wantReason: "sampled values",
+		},
+		{
+			ruleList: ruleList,
+			column:   &classificationColumn{name: "contact", columnType: "text", sampleValue: sample()},
+		},
+		{
+			ruleList:   typedRuleList,
+			column:     &classificationColumn{name: "amount", comment: "Monthly salary", columnType: "decimal(10,2)"},
+			wantTitle:  "Salary",
+			wantReason: "comment",
+		},
+		{
+			ruleList: typedRuleList,
+			column:   &classificationColumn{name: "note", comment: "Monthly salary", columnType: "text"},
+		},
+	}
+
+	for _, test := range tests {
+		rule, reason, err := classifyColumn(test.ruleList, test.column)
+		require.NoError(t, err)
+		if test.wantTitle == "" {
+			require.Nil(t, rule, test.column.name)
+			continue
+		}
+		require.NotNil(t, rule, test.column.name)
+		require.Equal(t, test.wantTitle, rule.rule.Title, test.column.name)
+		require.Equal(t, test.wantReason, reason, test.column.name)
+	}
+}
+
+func TestGetSampleStatement(t *testing.T) {
+	statement, ok := getSampleStatement(db.MySQL, "", "user", "e`mail")
+	require.True(t, ok)
+	require.Equal(t, "SELECT `e``mail` FROM `user`", statement)
+
+	statement, ok = getSampleStatement(db.Postgres, "public", "User", `e"mail`)
+	require.True(t, ok)
+	require.Equal(t, `SELECT "e""mail" FROM "public"."User"`, statement)
+
+	_, ok = getSampleStatement(db.MongoDB, "", "user", "email")
+	require.False(t, ok)
+}
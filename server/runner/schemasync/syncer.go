@@ -169,11 +169,11 @@ func (s *Syncer) syncAllDatabases(ctx context.Context, instanceID *int) {
 
 // SyncInstance syncs the schema for all databases in an instance.
 func (s *Syncer) SyncInstance(ctx context.Context, instance *api.Instance) ([]string, error) {
-	driver, err := s.dbFactory.GetAdminDatabaseDriver(ctx, instance, "")
+	driver, release, err := s.dbFactory.AcquireAdminDatabaseDriver(ctx, instance, "")
 	if err != nil {
 		return nil, err
 	}
-	defer driver.Close(ctx)
+	defer release()
 
 	return s.syncInstanceSchema(ctx, instance, driver)
 }
@@ -324,11 +324,11 @@ func (s *Syncer) syncInstanceSchema(ctx context.Context, instance *api.Instance,
 
 // SyncDatabaseSchema will sync the schema for a database.
 func (s *Syncer) SyncDatabaseSchema(ctx context.Context, instance *api.Instance, databaseName string, force bool) error {
-	driver, err := s.dbFactory.GetAdminDatabaseDriver(ctx, instance, databaseName)
+	driver, release, err := s.dbFactory.AcquireAdminDatabaseDriver(ctx, instance, databaseName)
 	if err != nil {
 		return err
 	}
-	defer driver.Close(ctx)
+	defer release()
 
 	databaseFind := &api.DatabaseFind{
 		InstanceID: &instance.ID,
@@ -430,6 +430,13 @@ func syncDBSchema(ctx context.Context, stores *store.Store, database *api.Databa
 		}); err != nil {
 			return err
 		}
+
+		// The classification is best effort and shouldn't fail the schema sync.
+		if err := classifySensitiveData(ctx, stores, database, databaseMetadata, driver); err != nil {
+			log.Error("Failed to classify the sensitive data",
+				zap.String("database", database.Name),
+				zap.Error(err))
+		}
 	}
 	return nil
 }
@@ -34,8 +34,9 @@ func (e *DatabaseConnectExecutor) Run(ctx context.Context, _ *api.TaskCheckRun,
 		return []api.TaskCheckResult{}, common.Errorf(common.Internal, "database ID not found %v", task.DatabaseID)
 	}
 
-	driver, err := e.dbFactory.GetAdminDatabaseDriver(ctx, database.Instance, database.Name)
+	driver, release, err := e.dbFactory.AcquireAdminDatabaseDriver(ctx, database.Instance, database.Name)
 	if err == nil {
+		defer release()
 		err = driver.Ping(ctx)
 	}
 	if err != nil {
@@ -49,7 +50,6 @@ func (e *DatabaseConnectExecutor) Run(ctx context.Context, _ *api.TaskCheckRun,
 			},
 		}, nil
 	}
-	defer driver.Close(ctx)
 
 	return []api.TaskCheckResult{
 		{
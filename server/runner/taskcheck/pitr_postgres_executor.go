@@ -0,0 +1,92 @@
+package taskcheck
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/db/pg"
+	bbs3 "github.com/bytebase/bytebase/plugin/storage/s3"
+	"github.com/bytebase/bytebase/server/component/dbfactory"
+	"github.com/bytebase/bytebase/store"
+)
+
+// NewPITRPostgresExecutor creates a task check PostgreSQL PITR executor.
+func NewPITRPostgresExecutor(store *store.Store, dbFactory *dbfactory.DBFactory, s3Client *bbs3.Client) Executor {
+	return &PITRPostgresExecutor{
+		store:     store,
+		dbFactory: dbFactory,
+		s3Client:  s3Client,
+	}
+}
+
+// PITRPostgresExecutor is the task check PostgreSQL PITR executor.
+type PITRPostgresExecutor struct {
+	store     *store.Store
+	dbFactory *dbfactory.DBFactory
+	s3Client  *bbs3.Client
+}
+
+// Run will run the task check PostgreSQL PITR executor once.
+func (e *PITRPostgresExecutor) Run(ctx context.Context, _ *api.TaskCheckRun, task *api.Task) (result []api.TaskCheckResult, err error) {
+	payload := api.TaskDatabasePITRRestorePayload{}
+	if err := json.Unmarshal([]byte(task.Payload), &payload); err != nil {
+		return nil, errors.Wrapf(err, "invalid PITR restore payload: %s", task.Payload)
+	}
+
+	if payload.BackupID != nil {
+		return []api.TaskCheckResult{
+			{
+				Status:    api.TaskCheckStatusSuccess,
+				Namespace: api.BBNamespace,
+				Code:      common.Ok.Int(),
+				Title:     "OK",
+				Content:   "Ready to do backup restore",
+			},
+		}, nil
+	}
+
+	policy, err := e.store.GetBackupPlanPolicyByEnvID(ctx, task.Instance.EnvironmentID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get backup plan policy of environment %d", task.Instance.EnvironmentID)
+	}
+	if !policy.PITR {
+		return wrapTaskCheckError(errors.Errorf("PITR is not enabled in the backup plan policy of environment %q", task.Instance.Environment.Name)), nil
+	}
+
+	driver, err := e.dbFactory.GetAdminDatabaseDriver(ctx, task.Instance, "" /* databaseName */)
+	if err != nil {
+		return nil, err
+	}
+	defer driver.Close(ctx)
+	pgDriver, ok := driver.(*pg.Driver)
+	if !ok {
+		return nil, errors.Errorf("Failed to cast driver to pg.Driver")
+	}
+
+	if err := pgDriver.CheckPITR(ctx); err != nil {
+		return wrapTaskCheckError(err), nil
+	}
+
+	backupList, err := pgDriver.ListBaseBackups(ctx, e.s3Client)
+	if err != nil {
+		return nil, err
+	}
+	if len(backupList) == 0 || backupList[0].EndTs > *payload.PointInTimeTs {
+		return wrapTaskCheckError(errors.Errorf("no base backup found before %s", time.Unix(*payload.PointInTimeTs, 0).Format(time.RFC822))), nil
+	}
+
+	return []api.TaskCheckResult{
+		{
+			Status:    api.TaskCheckStatusSuccess,
+			Namespace: api.BBNamespace,
+			Code:      common.Ok.Int(),
+			Title:     "OK",
+			Content:   "Ready to do PITR",
+		},
+	}, nil
+}
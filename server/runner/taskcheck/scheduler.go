@@ -15,6 +15,7 @@ import (
 	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/common/log"
 	enterpriseAPI "github.com/bytebase/bytebase/enterprise/api"
+	"github.com/bytebase/bytebase/plugin/db"
 	"github.com/bytebase/bytebase/server/component/state"
 	"github.com/bytebase/bytebase/server/utils"
 	"github.com/bytebase/bytebase/store"
@@ -401,11 +402,15 @@ func (*Scheduler) getPITRTaskCheck(_ context.Context, task *api.Task, creatorID
 	if task.Type != api.TaskDatabaseRestorePITRRestore {
 		return nil, nil
 	}
+	checkType := api.TaskCheckPITRMySQL
+	if task.Instance.Engine == db.Postgres {
+		checkType = api.TaskCheckPITRPostgres
+	}
 	return []*api.TaskCheckRunCreate{
 		{
 			CreatorID: creatorID,
 			TaskID:    task.ID,
-			Type:      api.TaskCheckPITRMySQL,
+			Type:      checkType,
 		},
 	}, nil
 }
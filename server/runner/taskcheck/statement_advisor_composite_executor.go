@@ -101,6 +101,8 @@ func (e *StatementAdvisorCompositeExecutor) Run(ctx context.Context, taskCheckRu
 			status = api.TaskCheckStatusWarn
 		case advisor.Error:
 			status = api.TaskCheckStatusError
+		case advisor.Suppressed:
+			status = api.TaskCheckStatusSuppressed
 		}
 
 		result = append(result, api.TaskCheckResult{
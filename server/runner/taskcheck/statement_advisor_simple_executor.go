@@ -33,10 +33,14 @@ func (*StatementAdvisorSimpleExecutor) Run(_ context.Context, taskCheckRun *api.
 		advisorType = advisor.Fake
 	case api.TaskCheckDatabaseStatementSyntax:
 		switch payload.DbType {
-		case db.MySQL, db.TiDB:
+		case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 			advisorType = advisor.MySQLSyntax
 		case db.Postgres:
 			advisorType = advisor.PostgreSQLSyntax
+		case db.Snowflake:
+			advisorType = advisor.SnowflakeSyntax
+		case db.ClickHouse:
+			advisorType = advisor.ClickHouseSyntax
 		default:
 			return nil, common.Errorf(common.Invalid, "invalid database type: %s for syntax statement advisor", payload.DbType)
 		}
@@ -70,6 +74,8 @@ func (*StatementAdvisorSimpleExecutor) Run(_ context.Context, taskCheckRun *api.
 			status = api.TaskCheckStatusWarn
 		case advisor.Error:
 			status = api.TaskCheckStatusError
+		case advisor.Suppressed:
+			status = api.TaskCheckStatusSuppressed
 		}
 
 		result = append(result, api.TaskCheckResult{
@@ -42,7 +42,7 @@ func (*StatementTypeExecutor) Run(_ context.Context, taskCheckRun *api.TaskCheck
 		if err != nil {
 			return nil, err
 		}
-	case db.MySQL, db.TiDB:
+	case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 		result, err = mysqlStatementTypeCheck(payload.Statement, payload.Charset, payload.Collation, task.Type)
 		if err != nil {
 			return nil, err
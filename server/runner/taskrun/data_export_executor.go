@@ -0,0 +1,480 @@
+package taskrun
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/parser"
+	bbs3 "github.com/bytebase/bytebase/plugin/storage/s3"
+	"github.com/bytebase/bytebase/server/component/activity"
+	"github.com/bytebase/bytebase/server/component/config"
+	"github.com/bytebase/bytebase/server/component/dbfactory"
+	"github.com/bytebase/bytebase/server/utils"
+	"github.com/bytebase/bytebase/store"
+)
+
+const (
+	// dataExportExpiration is how long the exported file can be downloaded.
+	dataExportExpiration = 24 * time.Hour
+	// defaultDataExportTableName is the table name in the INSERT statements if the table name is not specified.
+	defaultDataExportTableName = "exported_data"
+)
+
+// NewDataExportExecutor creates a data export task executor.
+func NewDataExportExecutor(store *store.Store, dbFactory *dbfactory.DBFactory, activityManager *activity.Manager, s3Client *bbs3.Client, profile config.Profile) Executor {
+	return &DataExportExecutor{
+		store:           store,
+		dbFactory:       dbFactory,
+		activityManager: activityManager,
+		s3Client:        s3Client,
+		profile:         profile,
+	}
+}
+
+// DataExportExecutor is the data export task executor.
+type DataExportExecutor struct {
+	store           *store.Store
+	dbFactory       *dbfactory.DBFactory
+	activityManager *activity.Manager
+	s3Client        *bbs3.Client
+	profile         config.Profile
+}
+
+// RunOnce will run the data export task executor once.
+func (exec *DataExportExecutor) RunOnce(ctx context.Context, task *api.Task) (terminated bool, result *api.TaskRunResultPayload, err error) {
+	payload := &api.TaskDatabaseDataExportPayload{}
+	if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+		return true, nil, errors.Wrap(err, "invalid database data export payload")
+	}
+	if task.Database == nil {
+		return true, nil, errors.Errorf("missing database when exporting data")
+	}
+	issue, err := exec.store.GetIssueByPipelineID(ctx, task.PipelineID)
+	if err != nil {
+		return true, nil, errors.Wrapf(err, "failed to fetch issue with pipeline ID %d", task.PipelineID)
+	}
+	if issue == nil {
+		return true, nil, errors.Errorf("issue not found with pipeline ID %d", task.PipelineID)
+	}
+
+	rowCount, exportErr := exec.exportData(ctx, task, payload)
+	exec.createExportActivity(ctx, task, issue, payload, rowCount, exportErr)
+	if exportErr != nil {
+		return true, nil, exportErr
+	}
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return true, nil, errors.Wrap(err, "failed to marshal task payload")
+	}
+	payloadString := string(payloadBytes)
+	if _, err := exec.store.PatchTask(ctx, &api.TaskPatch{
+		ID:        task.ID,
+		UpdaterID: api.SystemBotID,
+		Payload:   &payloadString,
+	}); err != nil {
+		return true, nil, errors.Wrapf(err, "failed to patch task %d with the exported file", task.ID)
+	}
+
+	return true, &api.TaskRunResultPayload{
+		Detail: fmt.Sprintf("Exported %d rows from database %q, the file expires at %s", rowCount, task.Database.Name, time.Unix(payload.ExpireTs, 0).UTC().Format(time.RFC3339)),
+	}, nil
+}
+
+// exportData runs the statement with the read-only data source and writes the masked result to the storage backend.
+// The storage backend, the path and the expiration time are set to the payload.
+func (exec *DataExportExecutor) exportData(ctx context.Context, task *api.Task, payload *api.TaskDatabaseDataExportPayload) (int, error) {
+	// The statement is checked again before running like the SQL editor, it must not export the data of other databases.
+	if err := utils.CheckCrossDatabaseQuery(task.Instance.Engine, payload.Statement, task.Database.Name); err != nil {
+		return 0, err
+	}
+	sensitiveSchemaInfo, err := exec.getSensitiveSchemaInfo(ctx, task, payload.Statement)
+	if err != nil {
+		return 0, err
+	}
+	saltName := api.SettingMaskingSalt
+	salt, err := exec.store.GetSetting(ctx, &api.SettingFind{Name: &saltName})
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to get setting %q", saltName)
+	}
+	maskingSalt := ""
+	if salt != nil {
+		maskingSalt = salt.Value
+	}
+
+	payload.StorageBackend = exec.profile.BackupStorageBackend
+	payload.Path = getDataExportRelativeFilePath(task.Database.ID, task.ID, payload.Format)
+	filePathLocal := filepath.Join(exec.profile.DataDir, payload.Path)
+	if err := os.MkdirAll(filepath.Dir(filePathLocal), os.ModePerm); err != nil {
+		return 0, errors.Wrapf(err, "failed to create the data export directory for %q", filePathLocal)
+	}
+
+	rowCount, err := func() (int, error) {
+		file, err := os.Create(filePathLocal)
+		if err != nil {
+			return 0, errors.Wrapf(err, "failed to create the data export file %q", filePathLocal)
+		}
+		defer file.Close()
+
+		driver, err := exec.dbFactory.GetReadOnlyDatabaseDriver(ctx, task.Instance, task.Database.Name)
+		if err != nil {
+			return 0, err
+		}
+		defer driver.Close(ctx)
+
+		limit := payload.Limit
+		if limit <= 0 || limit > api.MaxDataExportLimit {
+			limit = api.MaxDataExportLimit
+		}
+		writer := newDataExportWriter(file, payload.Format, task.Instance.Engine, payload.TableName)
+		if err := queryToHandler(ctx, driver, payload.Statement, &db.QueryContext{
+			Limit:                 limit,
+			ReadOnly:              true,
+			CurrentDatabase:       task.Database.Name,
+			SensitiveDataMaskType: db.SensitiveDataMaskTypeDefault,
+			MaskingSalt:           maskingSalt,
+			SensitiveSchemaInfo:   sensitiveSchemaInfo,
+		}, writer); err != nil {
+			return 0, err
+		}
+		if err := writer.Close(); err != nil {
+			return 0, errors.Wrapf(err, "failed to write the data export file %q", filePathLocal)
+		}
+		return writer.rowCount, nil
+	}()
+	if err != nil {
+		if err := os.Remove(filePathLocal); err != nil && !os.IsNotExist(err) {
+			log.Warn("Failed to remove the data export file.", zap.String("path", filePathLocal), zap.Error(err))
+		}
+		return 0, err
+	}
+
+	switch payload.StorageBackend {
+	case api.BackupStorageBackendLocal:
+	case api.BackupStorageBackendS3:
+		if err := uploadDataExportFile(ctx, exec.s3Client, filePathLocal, payload.Path); err != nil {
+			return 0, err
+		}
+		if err := os.Remove(filePathLocal); err != nil {
+			log.Warn("Failed to remove the local data export file after uploading to s3 bucket.", zap.String("path", filePathLocal), zap.Error(err))
+		}
+	default:
+		return 0, errors.Errorf("data export to %s not implemented yet", payload.StorageBackend)
+	}
+	payload.RowCount = rowCount
+	payload.ExpireTs = time.Now().Add(dataExportExpiration).Unix()
+	return rowCount, nil
+}
+
+// getSensitiveSchemaInfo gets the sensitive columns accessed by the statement.
+// The engines without the sensitive data masking can only export the databases without sensitive data.
+func (exec *DataExportExecutor) getSensitiveSchemaInfo(ctx context.Context, task *api.Task, statement string) (*db.SensitiveSchemaInfo, error) {
+	engine := task.Instance.Engine
+	switch engine {
+	case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
+		databaseList, err := parser.ExtractDatabaseList(parser.MySQL, statement)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get database list: %s", statement)
+		}
+		return utils.GetSensitiveSchemaInfo(ctx, exec.store, engine, task.Instance.ID, databaseList, task.Database.Name)
+	case db.Postgres:
+		return utils.GetSensitiveSchemaInfo(ctx, exec.store, engine, task.Instance.ID, []string{task.Database.Name}, task.Database.Name)
+	default:
+		policy, err := exec.store.GetSensitiveDataPolicy(ctx, task.Database.ID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get the sensitive data policy for database %q", task.Database.Name)
+		}
+		if len(policy.SensitiveDataList) > 0 {
+			return nil, errors.Errorf("cannot export database %q with sensitive data, because %s doesn't support sensitive data masking", task.Database.Name, engine)
+		}
+		return nil, nil
+	}
+}
+
+func (exec *DataExportExecutor) createExportActivity(ctx context.Context, task *api.Task, issue *api.Issue, payload *api.TaskDatabaseDataExportPayload, rowCount int, exportErr error) {
+	level := api.ActivityInfo
+	comment := fmt.Sprintf("Exported %d rows from database %q.", rowCount, task.Database.Name)
+	errMessage := ""
+	if exportErr != nil {
+		level = api.ActivityError
+		comment = fmt.Sprintf("Failed to export data from database %q.", task.Database.Name)
+		errMessage = exportErr.Error()
+	}
+	activityPayload, err := json.Marshal(api.ActivityDatabaseDataExportPayload{
+		IssueID:      issue.ID,
+		TaskID:       task.ID,
+		DatabaseID:   task.Database.ID,
+		DatabaseName: task.Database.Name,
+		Statement:    payload.Statement,
+		Format:       payload.Format,
+		RowCount:     rowCount,
+		Error:        errMessage,
+	})
+	if err != nil {
+		log.Error("failed to marshal data export activity", zap.Error(err))
+		return
+	}
+	if _, err := exec.activityManager.CreateActivity(ctx, &api.ActivityCreate{
+		CreatorID:   issue.CreatorID,
+		ContainerID: issue.ProjectID,
+		Type:        api.ActivityDatabaseDataExport,
+		Level:       level,
+		Payload:     string(activityPayload),
+		Comment:     comment,
+	}, &activity.Metadata{Issue: issue}); err != nil {
+		log.Error("cannot create a data export activity", zap.Error(err))
+	}
+}
+
+// getDataExportRelativeFilePath returns the path of the exported file relative to the data directory or the bucket.
+func getDataExportRelativeFilePath(databaseID int, taskID int, format api.DataExportFormat) string {
+	return filepath.Join("export", "db", fmt.Sprintf("%d", databaseID), fmt.Sprintf("%d.%s", taskID, format.Extension()))
+}
+
+func uploadDataExportFile(ctx context.Context, s3Client *bbs3.Client, filePathLocal string, path string) error {
+	file, err := os.Open(filePathLocal)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open data export file %q for uploading to s3 bucket", filePathLocal)
+	}
+	defer file.Close()
+	if _, err := s3Client.UploadObject(ctx, path, file); err != nil {
+		return errors.Wrap(err, "failed to upload the data export file to AWS S3")
+	}
+	return nil
+}
+
+// queryToHandler runs the query and passes the result to the handler.
+// The drivers which cannot stream the results fall back to Driver.Query.
+func queryToHandler(ctx context.Context, driver db.Driver, statement string, queryContext *db.QueryContext, handler db.RowHandler) error {
+	if querier, ok := driver.(db.StreamQuerier); ok {
+		return querier.QueryStream(ctx, statement, queryContext, handler)
+	}
+	rowSet, err := driver.Query(ctx, statement, queryContext)
+	if err != nil {
+		return err
+	}
+	if len(rowSet) < 3 {
+		return errors.Errorf("the statement returns no result set")
+	}
+	names, ok := rowSet[0].([]string)
+	if !ok {
+		return errors.Errorf("unexpected column names type %T", rowSet[0])
+	}
+	types, ok := rowSet[1].([]string)
+	if !ok {
+		return errors.Errorf("unexpected column types type %T", rowSet[1])
+	}
+	rows, ok := rowSet[2].([]interface{})
+	if !ok {
+		return errors.Errorf("unexpected rows type %T", rowSet[2])
+	}
+	if err := handler.HandleColumns(names, types); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		values, ok := row.([]interface{})
+		if !ok {
+			return errors.Errorf("unexpected row type %T", row)
+		}
+		if err := handler.HandleRow(values); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var (
+	_ db.RowHandler = (*dataExportWriter)(nil)
+)
+
+// dataExportWriter writes the query result in the export format as the rows arrive.
+type dataExportWriter struct {
+	w         *bufio.Writer
+	format    api.DataExportFormat
+	engine    db.Type
+	tableName string
+
+	columnNames []string
+	csvWriter   *csv.Writer
+	rowCount    int
+}
+
+func newDataExportWriter(w io.Writer, format api.DataExportFormat, engine db.Type, tableName string) *dataExportWriter {
+	if tableName == "" {
+		tableName = defaultDataExportTableName
+	}
+	writer := &dataExportWriter{
+		w:         bufio.NewWriter(w),
+		format:    format,
+		engine:    engine,
+		tableName: tableName,
+	}
+	if format == api.DataExportFormatCSV {
+		writer.csvWriter = csv.NewWriter(writer.w)
+	}
+	return writer
+}
+
+// HandleColumns implements the db.RowHandler interface.
+func (w *dataExportWriter) HandleColumns(names []string, _ []string) error {
+	w.columnNames = names
+	switch w.format {
+	case api.DataExportFormatCSV:
+		return w.csvWriter.Write(names)
+	case api.DataExportFormatJSON:
+		_, err := w.w.WriteString("[")
+		return err
+	}
+	return nil
+}
+
+// HandleRow implements the db.RowHandler interface.
+func (w *dataExportWriter) HandleRow(row []interface{}) error {
+	if len(row) != len(w.columnNames) {
+		return errors.Errorf("the row has %d values but there are %d columns", len(row), len(w.columnNames))
+	}
+	var err error
+	switch w.format {
+	case api.DataExportFormatCSV:
+		err = w.writeCSVRow(row)
+	case api.DataExportFormatJSON:
+		err = w.writeJSONRow(row)
+	case api.DataExportFormatSQL:
+		err = w.writeSQLRow(row)
+	default:
+		err = errors.Errorf("unsupported data export format %q", w.format)
+	}
+	if err != nil {
+		return err
+	}
+	w.rowCount++
+	return nil
+}
+
+// Close writes the rest of the file and flushes the buffer.
+func (w *dataExportWriter) Close() error {
+	switch w.format {
+	case api.DataExportFormatCSV:
+		w.csvWriter.Flush()
+		if err := w.csvWriter.Error(); err != nil {
+			return err
+		}
+	case api.DataExportFormatJSON:
+		if w.columnNames == nil {
+			// The query returns no result set.
+			if _, err := w.w.WriteString("["); err != nil {
+				return err
+			}
+		}
+		if _, err := w.w.WriteString("]\n"); err != nil {
+			return err
+		}
+	}
+	return w.w.Flush()
+}
+
+func (w *dataExportWriter) writeCSVRow(row []interface{}) error {
+	record := make([]string, len(row))
+	for i, value := range row {
+		if value != nil {
+			record[i] = fmt.Sprint(value)
+		}
+	}
+	return w.csvWriter.Write(record)
+}
+
+func (w *dataExportWriter) writeJSONRow(row []interface{}) error {
+	var buf strings.Builder
+	if w.rowCount > 0 {
+		buf.WriteString(",")
+	}
+	buf.WriteString("\n{")
+	for i, value := range row {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		name, err := json.Marshal(w.columnNames[i])
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		buf.Write(name)
+		buf.WriteString(":")
+		buf.Write(data)
+	}
+	buf.WriteString("}")
+	_, err := w.w.WriteString(buf.String())
+	return err
+}
+
+func (w *dataExportWriter) writeSQLRow(row []interface{}) error {
+	var buf strings.Builder
+	buf.WriteString("INSERT INTO ")
+	buf.WriteString(w.quoteIdentifier(w.tableName))
+	buf.WriteString(" (")
+	for i, name := range w.columnNames {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(w.quoteIdentifier(name))
+	}
+	buf.WriteString(") VALUES (")
+	for i, value := range row {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(w.quoteValue(value))
+	}
+	buf.WriteString(");\n")
+	_, err := w.w.WriteString(buf.String())
+	return err
+}
+
+func (w *dataExportWriter) isMySQLFamily() bool {
+	return w.engine == db.MySQL || w.engine == db.TiDB || w.engine == db.MariaDB || w.engine == db.OceanBase
+}
+
+func (w *dataExportWriter) quoteIdentifier(identifier string) string {
+	if w.isMySQLFamily() {
+		return fmt.Sprintf("`%s`", strings.ReplaceAll(identifier, "`", "``"))
+	}
+	return fmt.Sprintf(`"%s"`, strings.ReplaceAll(identifier, `"`, `""`))
+}
+
+func (w *dataExportWriter) quoteValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprint(v)
+	default:
+		s := fmt.Sprint(v)
+		if w.isMySQLFamily() {
+			// MySQL treats the backslash as the escape character by default.
+			s = strings.ReplaceAll(s, `\`, `\\`)
+		}
+		return fmt.Sprintf("'%s'", strings.ReplaceAll(s, "'", "''"))
+	}
+}
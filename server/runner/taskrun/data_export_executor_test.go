@@ -0,0 +1,67 @@
+package taskrun
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+func TestDataExportWriter(t *testing.T) {
+	columnNames := []string{"id", "name", "note"}
+	rowList := [][]interface{}{
+		{int64(1), "O'Brien", nil},
+		{int64(2), `a\b`, true},
+	}
+	tests := []struct {
+		format    api.DataExportFormat
+		engine    db.Type
+		tableName string
+		want      string
+	}{
+		{
+			format: api.DataExportFormatCSV,
+			engine: db.MySQL,
+			want:   "id,name,note\n1,O'Brien,\n2,a\\b,true\n",
+		},
+		{
+			format: api.DataExportFormatJSON,
+			engine: db.Postgres,
+			want:   "[\n{\"id\":1,\"name\":\"O'Brien\",\"note\":null},\n{\"id\":2,\"name\":\"a\\\\b\",\"note\":true}]\n",
+		},
+		{
+			format: api.DataExportFormatSQL,
+			engine: db.MySQL,
+			want: "INSERT INTO `exported_data` (`id`, `name`, `note`) VALUES (1, 'O''Brien', NULL);\n" +
+				"INSERT INTO `exported_data` (`id`, `name`, `note`) VALUES (2, 'a\\\\b', TRUE);\n",
+		},
+		{
+			format:    api.DataExportFormatSQL,
+			engine:    db.Postgres,
+			tableName: "user",
+			want: "INSERT INTO \"user\" (\"id\", \"name\", \"note\") VALUES (1, 'O''Brien', NULL);\n" +
+				"INSERT INTO \"user\" (\"id\", \"name\", \"note\") VALUES (2, 'a\\b', TRUE);\n",
+		},
+	}
+
+	for _, test := range tests {
+		var buf strings.Builder
+		writer := newDataExportWriter(&buf, test.format, test.engine, test.tableName)
+		require.NoError(t, writer.HandleColumns(columnNames, nil))
+		for _, row := range rowList {
+			require.NoError(t, writer.HandleRow(row))
+		}
+		require.NoError(t, writer.Close())
+		require.Equal(t, test.want, buf.String(), test.format)
+		require.Equal(t, len(rowList), writer.rowCount)
+	}
+
+	// The query returns no result set.
+	var buf strings.Builder
+	writer := newDataExportWriter(&buf, api.DataExportFormatJSON, db.Postgres, "")
+	require.NoError(t, writer.Close())
+	require.Equal(t, "[]\n", buf.String())
+}
@@ -230,7 +230,7 @@ func (exec *DatabaseCreateExecutor) RunOnce(ctx context.Context, task *api.Task)
 
 func getConnectionStatement(dbType db.Type, databaseName string) (string, error) {
 	switch dbType {
-	case db.MySQL, db.TiDB:
+	case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
 		return fmt.Sprintf("USE `%s`;\n", databaseName), nil
 	case db.Postgres:
 		return fmt.Sprintf("\\connect \"%s\";\n", databaseName), nil
@@ -240,6 +240,9 @@ func getConnectionStatement(dbType db.Type, databaseName string) (string, error)
 		return fmt.Sprintf("USE DATABASE %s;\n", databaseName), nil
 	case db.SQLite:
 		return fmt.Sprintf("USE `%s`;\n", databaseName), nil
+	case db.MSSQL:
+		// The USE statement is in its own batch because the database is created in the previous batch.
+		return fmt.Sprintf("GO\nUSE [%s];\nGO\n", databaseName), nil
 	}
 
 	return "", errors.Errorf("unsupported database type %s", dbType)
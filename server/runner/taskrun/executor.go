@@ -16,6 +16,7 @@ import (
 	"github.com/bytebase/bytebase/common/log"
 	"github.com/bytebase/bytebase/plugin/db"
 	"github.com/bytebase/bytebase/plugin/db/mysql"
+	"github.com/bytebase/bytebase/plugin/db/pg"
 	"github.com/bytebase/bytebase/plugin/parser"
 	"github.com/bytebase/bytebase/plugin/parser/transform"
 	vcsPlugin "github.com/bytebase/bytebase/plugin/vcs"
@@ -162,11 +163,33 @@ func executeMigration(ctx context.Context, store *store.Store, dbFactory *dbfact
 		task = updatedTask
 	}
 
+	if task.Type == api.TaskDatabaseDataUpdate && task.Instance.Engine == db.Postgres {
+		payload := &api.TaskDatabaseDataUpdatePayload{}
+		if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+			return 0, "", errors.Wrap(err, "invalid database data update payload")
+		}
+		// We cannot support rollback SQL generation for sheets because it can take lots of resources.
+		if payload.SheetID == 0 {
+			pgDriver, ok := driver.(*pg.Driver)
+			if !ok {
+				return 0, "", errors.Errorf("failed to cast driver to pg.Driver")
+			}
+			pgDriver.EnableRollbackSQLGeneration()
+		}
+	}
+
 	migrationID, schema, err = driver.ExecuteMigration(ctx, mi, statement)
 	if err != nil {
 		return 0, "", err
 	}
 
+	if task.Type == api.TaskDatabaseDataUpdate && task.Instance.Engine == db.Postgres {
+		if err := setPGRollbackStatement(ctx, driver, task, store); err != nil {
+			// The migration has been done, so we don't fail the task for the rollback statement.
+			log.Error("failed to update the task payload for PostgreSQL rollback SQL", zap.Int("task", task.ID), zap.Error(err))
+		}
+	}
+
 	if task.Type == api.TaskDatabaseDataUpdate && task.Instance.Engine == db.MySQL {
 		updatedTask, err := setMigrationIDAndEndBinlogCoordinate(ctx, driver, task, store, migrationID)
 		if err != nil {
@@ -265,6 +288,47 @@ func setMigrationIDAndEndBinlogCoordinate(ctx context.Context, driver db.Driver,
 	return updatedTask, nil
 }
 
+// setPGRollbackStatement sets the rollback statement generated by the Postgres driver during the migration, or the reason why it cannot be generated.
+func setPGRollbackStatement(ctx context.Context, driver db.Driver, task *api.Task, store *store.Store) error {
+	pgDriver, ok := driver.(*pg.Driver)
+	if !ok {
+		return errors.Errorf("failed to cast driver to pg.Driver")
+	}
+	payload := &api.TaskDatabaseDataUpdatePayload{}
+	if err := json.Unmarshal([]byte(task.Payload), payload); err != nil {
+		return errors.Wrap(err, "invalid database data update payload")
+	}
+
+	if payload.SheetID > 0 {
+		payload.RollbackError = "rollback SQL isn't supported for large sheet"
+	} else {
+		rollbackStatement, err := pgDriver.GetRollbackSQL()
+		switch {
+		case err != nil:
+			payload.RollbackError = err.Error()
+		case rollbackStatement == "":
+			payload.RollbackError = "no data is changed by the migration"
+		default:
+			payload.RollbackStatement = rollbackStatement
+		}
+	}
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal task payload")
+	}
+	payloadString := string(payloadBytes)
+	patch := &api.TaskPatch{
+		ID:        task.ID,
+		UpdaterID: api.SystemBotID,
+		Payload:   &payloadString,
+	}
+	if _, err := store.PatchTask(ctx, patch); err != nil {
+		return errors.Wrapf(err, "failed to patch task %d with the rollback statement", task.ID)
+	}
+	return nil
+}
+
 func postMigration(ctx context.Context, store *store.Store, activityManager *activity.Manager, profile config.Profile, task *api.Task, vcsPushEvent *vcsPlugin.PushEvent, mi *db.MigrationInfo, migrationID int64, schema string) (bool, *api.TaskRunResultPayload, error) {
 	databaseName := task.Database.Name
 	issue, err := findIssueByTask(ctx, store, task)
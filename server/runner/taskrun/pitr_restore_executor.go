@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net"
 	"os"
 	"path"
 	"path/filepath"
@@ -21,6 +23,7 @@ import (
 	"github.com/bytebase/bytebase/plugin/db/pg"
 	"github.com/bytebase/bytebase/plugin/db/util"
 	bbs3 "github.com/bytebase/bytebase/plugin/storage/s3"
+	"github.com/bytebase/bytebase/resources/postgres"
 	"github.com/bytebase/bytebase/server/component/config"
 	"github.com/bytebase/bytebase/server/component/dbfactory"
 	"github.com/bytebase/bytebase/server/component/state"
@@ -30,13 +33,14 @@ import (
 )
 
 // NewPITRRestoreExecutor creates a PITR restore task executor.
-func NewPITRRestoreExecutor(store *store.Store, dbFactory *dbfactory.DBFactory, s3Client *bbs3.Client, schemaSyncer *schemasync.Syncer, stateCfg *state.State, profile config.Profile) Executor {
+func NewPITRRestoreExecutor(store *store.Store, dbFactory *dbfactory.DBFactory, s3Client *bbs3.Client, schemaSyncer *schemasync.Syncer, stateCfg *state.State, pgBinDir string, profile config.Profile) Executor {
 	return &PITRRestoreExecutor{
 		store:        store,
 		dbFactory:    dbFactory,
 		s3Client:     s3Client,
 		schemaSyncer: schemaSyncer,
 		stateCfg:     stateCfg,
+		pgBinDir:     pgBinDir,
 		profile:      profile,
 	}
 }
@@ -48,6 +52,7 @@ type PITRRestoreExecutor struct {
 	s3Client     *bbs3.Client
 	schemaSyncer *schemasync.Syncer
 	stateCfg     *state.State
+	pgBinDir     string
 	profile      config.Profile
 }
 
@@ -77,6 +82,10 @@ func (exec *PITRRestoreExecutor) RunOnce(ctx context.Context, task *api.Task) (t
 		return true, resultPayload, err
 	}
 
+	if task.Instance.Engine == db.Postgres {
+		resultPayload, err := exec.doPITRRestorePostgres(ctx, task, payload)
+		return true, resultPayload, err
+	}
 	resultPayload, err := exec.doPITRRestore(ctx, exec.store, exec.dbFactory, exec.s3Client, exec.profile, task, payload)
 	return true, resultPayload, err
 }
@@ -309,6 +318,165 @@ func (exec *PITRRestoreExecutor) doPITRRestore(ctx context.Context, store *store
 	}, nil
 }
 
+// doPITRRestorePostgres recovers the instance to the point in time in a temporary PostgreSQL server from the base backup and
+// the WAL archive, then dumps the database from it and restores the dump to the new database or the PITR database.
+func (exec *PITRRestoreExecutor) doPITRRestorePostgres(ctx context.Context, task *api.Task, payload api.TaskDatabasePITRRestorePayload) (*api.TaskRunResultPayload, error) {
+	issue, err := getIssueByPipelineID(ctx, exec.store, task.PipelineID)
+	if err != nil {
+		return nil, err
+	}
+	sourceDriver, err := exec.dbFactory.GetAdminDatabaseDriver(ctx, task.Instance, "")
+	if err != nil {
+		return nil, err
+	}
+	defer sourceDriver.Close(ctx)
+	pgSourceDriver, ok := sourceDriver.(*pg.Driver)
+	if !ok {
+		log.Error("Failed to cast driver to pg.Driver")
+		return nil, errors.Errorf("[internal] cast driver to pg.Driver failed")
+	}
+
+	// Archive the latest WAL files with a commit record after the target time, so that the recovery can stop at the target time.
+	targetTs := *payload.PointInTimeTs
+	if err := pgSourceDriver.WriteCommitRecord(ctx); err != nil {
+		return nil, err
+	}
+	if err := pgSourceDriver.ArchiveWAL(ctx, exec.s3Client); err != nil {
+		return nil, errors.Wrap(err, "failed to archive the latest WAL files")
+	}
+	baseBackupList, err := pgSourceDriver.ListBaseBackups(ctx, exec.s3Client)
+	if err != nil {
+		return nil, err
+	}
+	var baseBackup *pg.BaseBackup
+	for _, backup := range baseBackupList {
+		if backup.EndTs <= targetTs {
+			baseBackup = backup
+		}
+	}
+	if baseBackup == nil {
+		return nil, errors.Errorf("no base backup found before %s", time.Unix(targetTs, 0).Format(time.RFC822))
+	}
+	log.Debug("Got latest base backup before or equal to targetTs", zap.String("backup", baseBackup.Name), zap.Int64("targetTs", targetTs))
+
+	recoveryDir := filepath.Join(exec.profile.DataDir, "tmp", fmt.Sprintf("pitr-%d", task.ID))
+	// Clean up the dirty state left from a former task execution.
+	if err := os.RemoveAll(recoveryDir); err != nil {
+		return nil, errors.Wrapf(err, "failed to remove the recovery directory %q", recoveryDir)
+	}
+	defer os.RemoveAll(recoveryDir)
+	dataDir := filepath.Join(recoveryDir, "pgdata")
+	if err := pgSourceDriver.PrepareRecovery(ctx, exec.s3Client, baseBackup, dataDir, targetTs); err != nil {
+		return nil, errors.Wrapf(err, "failed to prepare the recovery from base backup %q", baseBackup.Name)
+	}
+	if err := postgres.ChownDataDir(dataDir); err != nil {
+		return nil, err
+	}
+	port, err := getFreePort()
+	if err != nil {
+		return nil, err
+	}
+	// The server may be still running even if it fails to start in time.
+	defer func() {
+		if err := postgres.Stop(exec.pgBinDir, dataDir); err != nil {
+			log.Warn("Failed to stop the PostgreSQL server for PITR", zap.String("dataDir", dataDir), zap.Error(err))
+		}
+	}()
+	if err := postgres.Start(port, exec.pgBinDir, dataDir); err != nil {
+		return nil, errors.Wrap(err, "failed to start the PostgreSQL server for PITR")
+	}
+
+	adminDataSource := api.DataSourceFromInstanceWithType(task.Instance, api.Admin)
+	if adminDataSource == nil {
+		return nil, common.Errorf(common.Internal, "admin data source not found for instance %d", task.Instance.ID)
+	}
+	recoveryDriver, err := db.Open(
+		ctx,
+		db.Postgres,
+		db.DriverConfig{DbBinDir: exec.pgBinDir},
+		db.ConnectionConfig{
+			Username: adminDataSource.Username,
+			Host:     common.GetPostgresSocketDir(),
+			Port:     strconv.Itoa(port),
+			Database: task.Database.Name,
+		},
+		db.ConnectionContext{},
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to the PostgreSQL server for PITR")
+	}
+	defer recoveryDriver.Close(ctx)
+	pgRecoveryDriver, ok := recoveryDriver.(*pg.Driver)
+	if !ok {
+		log.Error("Failed to cast driver to pg.Driver")
+		return nil, errors.Errorf("[internal] cast driver to pg.Driver failed")
+	}
+	if err := pgRecoveryDriver.WaitRecoveryDone(ctx); err != nil {
+		return nil, err
+	}
+
+	dumpFilePath := filepath.Join(recoveryDir, "dump.sql")
+	dumpFile, err := os.Create(dumpFilePath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create the dump file %q", dumpFilePath)
+	}
+	defer dumpFile.Close()
+	if _, err := recoveryDriver.Dump(ctx, task.Database.Name, dumpFile, false /* schemaOnly */); err != nil {
+		return nil, errors.Wrapf(err, "failed to dump the recovered database %q", task.Database.Name)
+	}
+	if _, err := dumpFile.Seek(0, io.SeekStart); err != nil {
+		return nil, errors.Wrapf(err, "failed to seek the dump file %q", dumpFilePath)
+	}
+
+	if payload.DatabaseName != nil {
+		// case 1: PITR to a new database.
+		targetInstance, err := exec.store.GetInstanceByID(ctx, *payload.TargetInstanceID)
+		if err != nil {
+			return nil, err
+		}
+		targetDriver, err := exec.dbFactory.GetAdminDatabaseDriver(ctx, targetInstance, *payload.DatabaseName)
+		if err != nil {
+			return nil, err
+		}
+		defer targetDriver.Close(ctx)
+		if err := targetDriver.Restore(ctx, dumpFile); err != nil {
+			log.Error("failed to perform a PITR restore in the new database",
+				zap.Int("issueID", issue.ID),
+				zap.String("databaseName", *payload.DatabaseName),
+				zap.Error(err))
+			return nil, errors.Wrap(err, "failed to perform a PITR restore in the new database")
+		}
+		log.Info("PITR restore success", zap.String("target database", *payload.DatabaseName))
+		return &api.TaskRunResultPayload{
+			Detail: fmt.Sprintf("PITR restore success for target database %q", *payload.DatabaseName),
+		}, nil
+	}
+
+	// case 2: in-place PITR.
+	pitrDatabaseName, err := restoreToPITRDatabasePostgres(ctx, exec.dbFactory, issue, task, dumpFile)
+	if err != nil {
+		log.Error("failed to perform a PITR restore in the PITR database",
+			zap.Int("issueID", issue.ID),
+			zap.String("databaseName", task.Database.Name),
+			zap.Error(err))
+		return nil, err
+	}
+	log.Info("PITR restore success", zap.String("target database", pitrDatabaseName))
+	return &api.TaskRunResultPayload{
+		Detail: fmt.Sprintf("PITR restore success for the temporary PITR database %q", pitrDatabaseName),
+	}, nil
+}
+
+// getFreePort returns a free TCP port on the local host.
+func getFreePort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to find a free port")
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
+
 func downloadBinlogFilesFromCloud(ctx context.Context, client *bbs3.Client, startBinlogInfo, targetBinlogInfo api.BinlogInfo, binlogDir string) ([]string, error) {
 	replayBinlogPathList, err := mysql.GetBinlogReplayList(startBinlogInfo, targetBinlogInfo, binlogDir)
 	if err != nil {
@@ -325,10 +493,6 @@ func downloadBinlogFilesFromCloud(ctx context.Context, client *bbs3.Client, star
 }
 
 func (*PITRRestoreExecutor) doRestoreInPlacePostgres(ctx context.Context, store *store.Store, dbFactory *dbfactory.DBFactory, profile config.Profile, issue *api.Issue, task *api.Task, payload api.TaskDatabasePITRRestorePayload) (*api.TaskRunResultPayload, error) {
-	if payload.BackupID == nil {
-		return nil, errors.Errorf("PITR for Postgres is not implemented")
-	}
-
 	backup, err := store.GetBackupByID(ctx, *payload.BackupID)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to find backup with ID %d", *payload.BackupID)
@@ -343,46 +507,56 @@ func (*PITRRestoreExecutor) doRestoreInPlacePostgres(ctx context.Context, store
 	}
 	defer backupFile.Close()
 
-	driver, err := dbFactory.GetAdminDatabaseDriver(ctx, task.Instance, task.Database.Name)
+	pitrDatabaseName, err := restoreToPITRDatabasePostgres(ctx, dbFactory, issue, task, backupFile)
 	if err != nil {
 		return nil, err
 	}
+	return &api.TaskRunResultPayload{
+		Detail: fmt.Sprintf("Restored backup %q to the temporary PITR database %q", backup.Name, pitrDatabaseName),
+	}, nil
+}
+
+// restoreToPITRDatabasePostgres restores the dump to the PITR database, which is swapped with the original database in the PITR cutover task.
+// Returns the PITR database name on success.
+func restoreToPITRDatabasePostgres(ctx context.Context, dbFactory *dbfactory.DBFactory, issue *api.Issue, task *api.Task, dump io.Reader) (string, error) {
+	driver, err := dbFactory.GetAdminDatabaseDriver(ctx, task.Instance, task.Database.Name)
+	if err != nil {
+		return "", err
+	}
 	defer driver.Close(ctx)
 
 	pgDriver, ok := driver.(*pg.Driver)
 	if !ok {
 		log.Error("Failed to cast driver to pg.Driver")
-		return nil, errors.Errorf("[internal] cast driver to pg.Driver failed")
+		return "", errors.Errorf("[internal] cast driver to pg.Driver failed")
 	}
 	originalOwner, err := pgDriver.GetCurrentDatabaseOwner()
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to get the OWNER of database %q", task.Database.Name)
+		return "", errors.Wrapf(err, "failed to get the OWNER of database %q", task.Database.Name)
 	}
 
 	db, err := driver.GetDBConnection(ctx, db.BytebaseDatabase)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get connection for PostgreSQL")
+		return "", errors.Wrap(err, "failed to get connection for PostgreSQL")
 	}
 	pitrDatabaseName := util.GetPITRDatabaseName(task.Database.Name, issue.CreatedTs)
 	// If there's already a PITR database, it means there's a failed trial before this task execution.
 	// We need to clean up the dirty state and start clean for idempotent task execution.
 	if _, err := db.ExecContext(ctx, fmt.Sprintf("DROP DATABASE IF EXISTS %s;", pitrDatabaseName)); err != nil {
-		return nil, errors.Wrapf(err, "failed to drop the dirty PITR database %q left from a former task execution", pitrDatabaseName)
+		return "", errors.Wrapf(err, "failed to drop the dirty PITR database %q left from a former task execution", pitrDatabaseName)
 	}
 	if _, err := db.ExecContext(ctx, fmt.Sprintf("CREATE DATABASE %s WITH OWNER %s;", pitrDatabaseName, originalOwner)); err != nil {
-		return nil, errors.Wrapf(err, "failed to create the PITR database %q", pitrDatabaseName)
+		return "", errors.Wrapf(err, "failed to create the PITR database %q", pitrDatabaseName)
 	}
 	// Switch to the PITR database.
 	// TODO(dragonly): This is a trick, needs refactor.
 	if _, err := driver.GetDBConnection(ctx, pitrDatabaseName); err != nil {
-		return nil, errors.Wrapf(err, "failed to switch connection to database %q", pitrDatabaseName)
+		return "", errors.Wrapf(err, "failed to switch connection to database %q", pitrDatabaseName)
 	}
-	if err := driver.Restore(ctx, backupFile); err != nil {
-		return nil, errors.Wrapf(err, "failed to restore backup to the PITR database %q", pitrDatabaseName)
+	if err := driver.Restore(ctx, dump); err != nil {
+		return "", errors.Wrapf(err, "failed to restore backup to the PITR database %q", pitrDatabaseName)
 	}
-	return &api.TaskRunResultPayload{
-		Detail: fmt.Sprintf("Restored backup %q to the temporary PITR database %q", backup.Name, pitrDatabaseName),
-	}, nil
+	return pitrDatabaseName, nil
 }
 
 func (exec *PITRRestoreExecutor) updateProgress(ctx context.Context, driver *mysql.Driver, taskID int, backupFile *os.File, startBinlogInfo, targetBinlogInfo api.BinlogInfo, binlogDir string) error {
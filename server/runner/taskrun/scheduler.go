@@ -892,6 +892,10 @@ func (s *Scheduler) scheduleAutoApprovedTasks(ctx context.Context) error {
 	}
 
 	for _, task := range taskList {
+		// Data export tasks reveal the data, so they always need to be approved manually.
+		if task.Type == api.TaskDatabaseDataExport {
+			continue
+		}
 		policy, err := s.store.GetPipelineApprovalPolicy(ctx, task.Instance.EnvironmentID)
 		if err != nil {
 			return errors.Wrapf(err, "failed to get approval policy for environment ID %d", task.Instance.EnvironmentID)
@@ -1119,7 +1123,8 @@ func (s *Scheduler) GetDefaultAssigneeID(ctx context.Context, environmentID int,
 	if err != nil {
 		return api.UnknownID, errors.Wrapf(err, "failed to GetPipelineApprovalPolicy for environmentID %d", environmentID)
 	}
-	if policy.Value == api.PipelineApprovalValueManualNever {
+	// Data export issues are never approved automatically.
+	if policy.Value == api.PipelineApprovalValueManualNever && issueType != api.IssueDatabaseDataExport {
 		// use SystemBot for auto approval tasks.
 		return api.SystemBotID, nil
 	}
@@ -0,0 +1,160 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/google/jsonapi"
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+func (s *Server) registerSensitiveDataProposalRoutes(g *echo.Group) {
+	g.GET("/database/:databaseID/sensitive-data-proposal", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		id, err := strconv.Atoi(c.Param("databaseID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("ID is not a number: %s", c.Param("databaseID"))).SetInternal(err)
+		}
+
+		proposalFind := &api.SensitiveDataProposalFind{
+			DatabaseID: &id,
+		}
+		if statusStr := c.QueryParam("status"); statusStr != "" {
+			status := api.SensitiveDataProposalStatus(statusStr)
+			proposalFind.Status = &status
+		}
+		proposalList, err := s.store.FindSensitiveDataProposal(ctx, proposalFind)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch sensitive data proposal list for database ID: %d", id)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, proposalList); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal sensitive data proposal list response").SetInternal(err)
+		}
+		return nil
+	})
+
+	g.PATCH("/database/:databaseID/sensitive-data-proposal/:proposalID", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		if !s.licenseService.IsFeatureEnabled(api.FeatureSensitiveData) {
+			return echo.NewHTTPError(http.StatusForbidden, api.FeatureSensitiveData.AccessErrorMessage())
+		}
+		databaseID, err := strconv.Atoi(c.Param("databaseID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Database ID is not a number: %s", c.Param("databaseID"))).SetInternal(err)
+		}
+		id, err := strconv.Atoi(c.Param("proposalID"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Proposal ID is not a number: %s", c.Param("proposalID"))).SetInternal(err)
+		}
+
+		proposalPatch := &api.SensitiveDataProposalPatch{
+			ID:        id,
+			UpdaterID: c.Get(getPrincipalIDContextKey()).(int),
+		}
+		if err := jsonapi.UnmarshalPayload(c.Request().Body, proposalPatch); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Malformed patch sensitive data proposal request").SetInternal(err)
+		}
+		if proposalPatch.Status != api.SensitiveDataProposalApproved && proposalPatch.Status != api.SensitiveDataProposalRejected {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Sensitive data proposal can only be %s or %s", api.SensitiveDataProposalApproved, api.SensitiveDataProposalRejected))
+		}
+
+		proposal, err := s.store.GetSensitiveDataProposalByID(ctx, id)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch sensitive data proposal ID: %d", id)).SetInternal(err)
+		}
+		if proposal == nil || proposal.DatabaseID != databaseID {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Sensitive data proposal not found with ID %d", id))
+		}
+		if proposal.Status != api.SensitiveDataProposalPending {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Sensitive data proposal %d has been reviewed", id))
+		}
+
+		if proposalPatch.Status == api.SensitiveDataProposalApproved {
+			if err := s.addSensitiveDataProposalToPolicy(c, proposal, proposalPatch.UpdaterID); err != nil {
+				return err
+			}
+		}
+
+		updatedProposal, err := s.store.PatchSensitiveDataProposal(ctx, proposalPatch)
+		if err != nil {
+			if common.ErrorCode(err) == common.NotFound {
+				return echo.NewHTTPError(http.StatusNotFound, err.Error()).SetInternal(err)
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to patch sensitive data proposal ID: %d", id)).SetInternal(err)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		if err := jsonapi.MarshalPayload(c.Response().Writer, updatedProposal); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal sensitive data proposal response").SetInternal(err)
+		}
+		return nil
+	})
+}
+
+// addSensitiveDataProposalToPolicy adds the proposed column to the sensitive data policy of its database.
+func (s *Server) addSensitiveDataProposalToPolicy(c echo.Context, proposal *api.SensitiveDataProposal, updaterID int) error {
+	ctx := c.Request().Context()
+	var payload api.SensitiveDataProposalPayload
+	if err := json.Unmarshal([]byte(proposal.Payload), &payload); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to unmarshal sensitive data proposal payload %q", proposal.Payload)).SetInternal(err)
+	}
+	maskType := payload.MaskType
+	if maskType == "" {
+		maskType = api.SensitiveDataMaskTypeDefault
+	}
+
+	database, err := s.store.GetDatabase(ctx, &api.DatabaseFind{ID: &proposal.DatabaseID})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get database ID: %d", proposal.DatabaseID)).SetInternal(err)
+	}
+	if database == nil {
+		return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database ID not found: %d", proposal.DatabaseID))
+	}
+	policy, err := s.store.GetSensitiveDataPolicy(ctx, proposal.DatabaseID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get sensitive data policy for database ID: %d", proposal.DatabaseID)).SetInternal(err)
+	}
+	dbType := database.Instance.Engine
+	for _, data := range policy.SensitiveDataList {
+		if api.NormalizeSensitiveDataSchema(dbType, data.Schema) == api.NormalizeSensitiveDataSchema(dbType, proposal.Schema) && data.Table == proposal.Table && data.Column == proposal.Column {
+			// The column has been added to the policy manually.
+			return nil
+		}
+	}
+	policy.SensitiveDataList = append(policy.SensitiveDataList, api.SensitiveData{
+		Schema:     proposal.Schema,
+		Table:      proposal.Table,
+		Column:     proposal.Column,
+		Type:       maskType,
+		MaskOption: payload.MaskOption,
+	})
+	policyPayload, err := policy.String()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal sensitive data policy").SetInternal(err)
+	}
+
+	policyUpsert := &api.PolicyUpsert{
+		UpdaterID:    updaterID,
+		ResourceType: api.PolicyResourceTypeDatabase,
+		ResourceID:   proposal.DatabaseID,
+		Type:         api.PolicyTypeSensitiveData,
+		Payload:      &policyPayload,
+	}
+	if err := api.ValidatePolicy(policyUpsert.ResourceType, policyUpsert.Type, policyUpsert.Payload); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid policy payload %s", err.Error())).SetInternal(err)
+	}
+	if _, err := s.store.UpsertPolicy(ctx, policyUpsert); err != nil {
+		if common.ErrorCode(err) == common.Invalid {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to set sensitive data policy for database ID: %d", proposal.DatabaseID)).SetInternal(err)
+	}
+	return nil
+}
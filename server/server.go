@@ -3,6 +3,7 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net"
 	"net/http"
@@ -73,6 +74,12 @@ import (
 	_ "github.com/bytebase/bytebase/plugin/db/mongodb"
 	// Register spanner driver.
 	_ "github.com/bytebase/bytebase/plugin/db/spanner"
+	// Register mssql driver.
+	_ "github.com/bytebase/bytebase/plugin/db/mssql"
+	// Register oracle driver.
+	_ "github.com/bytebase/bytebase/plugin/db/oracle"
+	// Register redis driver.
+	_ "github.com/bytebase/bytebase/plugin/db/redis"
 
 	// Register pingcap parser driver.
 	_ "github.com/pingcap/tidb/types/parser_driver"
@@ -80,8 +87,12 @@ import (
 	_ "github.com/bytebase/bytebase/plugin/advisor/fake"
 	// Register mysql advisor.
 	_ "github.com/bytebase/bytebase/plugin/advisor/mysql"
+	// Register clickhouse advisor.
+	_ "github.com/bytebase/bytebase/plugin/advisor/clickhouse"
 	// Register postgresql advisor.
 	_ "github.com/bytebase/bytebase/plugin/advisor/pg"
+	// Register snowflake advisor.
+	_ "github.com/bytebase/bytebase/plugin/advisor/snowflake"
 
 	// Register mysql differ driver.
 	_ "github.com/bytebase/bytebase/plugin/parser/differ/mysql"
@@ -93,6 +104,8 @@ import (
 	_ "github.com/bytebase/bytebase/plugin/parser/edit/pg"
 	// Register postgres parser driver.
 	_ "github.com/bytebase/bytebase/plugin/parser/engine/pg"
+	// Register snowflake and clickhouse parser driver.
+	_ "github.com/bytebase/bytebase/plugin/parser/engine/standard"
 	// Register mysql transform driver.
 	_ "github.com/bytebase/bytebase/plugin/parser/transform/mysql"
 )
@@ -132,6 +145,7 @@ type Server struct {
 	startedTs       int64
 	secret          string
 	workspaceID     string
+	maskingSalt     string
 	errorRecordRing api.ErrorRecordRing
 
 	// MySQL utility binaries
@@ -288,9 +302,14 @@ func NewServer(ctx context.Context, profile config.Profile) (*Server, error) {
 	}
 	s.secret = config.secret
 	s.workspaceID = config.workspaceID
+	s.maskingSalt = config.maskingSalt
 
 	s.ActivityManager = activity.NewManager(storeInstance, profile)
-	s.dbFactory = dbfactory.New(s.mysqlBinDir, s.mongoBinDir, s.pgBinDir, profile.DataDir)
+	s.dbFactory = dbfactory.New(s.mysqlBinDir, s.mongoBinDir, s.pgBinDir, profile.DataDir, dbfactory.CredentialConfig{
+		CommandMap:       profile.CredentialCommands,
+		SecretEnvPrefix:  profile.SecretEnvPrefix,
+		SecretVaultMount: profile.SecretVaultMount,
+	})
 	e := echo.New()
 	e.Debug = profile.Debug
 	e.HideBanner = true
@@ -334,8 +353,9 @@ func NewServer(ctx context.Context, profile config.Profile) (*Server, error) {
 		s.TaskScheduler.Register(api.TaskDatabaseBackup, taskrun.NewDatabaseBackupExecutor(storeInstance, s.dbFactory, s.s3Client, profile))
 		s.TaskScheduler.Register(api.TaskDatabaseSchemaUpdateGhostSync, taskrun.NewSchemaUpdateGhostSyncExecutor(storeInstance, s.stateCfg))
 		s.TaskScheduler.Register(api.TaskDatabaseSchemaUpdateGhostCutover, taskrun.NewSchemaUpdateGhostCutoverExecutor(storeInstance, s.dbFactory, s.ActivityManager, s.stateCfg, s.SchemaSyncer, profile))
-		s.TaskScheduler.Register(api.TaskDatabaseRestorePITRRestore, taskrun.NewPITRRestoreExecutor(storeInstance, s.dbFactory, s.s3Client, s.SchemaSyncer, s.stateCfg, profile))
+		s.TaskScheduler.Register(api.TaskDatabaseRestorePITRRestore, taskrun.NewPITRRestoreExecutor(storeInstance, s.dbFactory, s.s3Client, s.SchemaSyncer, s.stateCfg, s.pgBinDir, profile))
 		s.TaskScheduler.Register(api.TaskDatabaseRestorePITRCutover, taskrun.NewPITRCutoverExecutor(storeInstance, s.dbFactory, s.SchemaSyncer, s.BackupRunner, s.ActivityManager, profile))
+		s.TaskScheduler.Register(api.TaskDatabaseDataExport, taskrun.NewDataExportExecutor(storeInstance, s.dbFactory, s.ActivityManager, s.s3Client, profile))
 
 		s.TaskCheckScheduler = taskcheck.NewScheduler(storeInstance, s.licenseService, s.stateCfg)
 		statementSimpleExecutor := taskcheck.NewStatementAdvisorSimpleExecutor()
@@ -355,6 +375,8 @@ func NewServer(ctx context.Context, profile config.Profile) (*Server, error) {
 		s.TaskCheckScheduler.Register(api.TaskCheckIssueLGTM, checkLGTMExecutor)
 		pitrMySQLExecutor := taskcheck.NewPITRMySQLExecutor(storeInstance, s.dbFactory)
 		s.TaskCheckScheduler.Register(api.TaskCheckPITRMySQL, pitrMySQLExecutor)
+		pitrPostgresExecutor := taskcheck.NewPITRPostgresExecutor(storeInstance, s.dbFactory, s.s3Client)
+		s.TaskCheckScheduler.Register(api.TaskCheckPITRPostgres, pitrPostgresExecutor)
 
 		// Anomaly scanner
 		s.AnomalyScanner = anomaly.NewScanner(storeInstance, s.dbFactory, s.licenseService)
@@ -419,6 +441,7 @@ func NewServer(ctx context.Context, profile config.Profile) (*Server, error) {
 	s.registerIssueRoutes(apiGroup)
 	s.registerIssueSubscriberRoutes(apiGroup)
 	s.registerTaskRoutes(apiGroup)
+	s.registerDataExportRoutes(apiGroup)
 	s.registerStageRoutes(apiGroup)
 	s.registerActivityRoutes(apiGroup)
 	s.registerInboxRoutes(apiGroup)
@@ -430,6 +453,7 @@ func NewServer(ctx context.Context, profile config.Profile) (*Server, error) {
 	s.registerSheetRoutes(apiGroup)
 	s.registerSheetOrganizerRoutes(apiGroup)
 	s.registerAnomalyRoutes(apiGroup)
+	s.registerSensitiveDataProposalRoutes(apiGroup)
 
 	// Register healthz endpoint.
 	e.GET("/healthz", func(c echo.Context) error {
@@ -444,7 +468,7 @@ func NewServer(ctx context.Context, profile config.Profile) (*Server, error) {
 	mux := runtime.NewServeMux(runtime.WithForwardResponseOption(auth.GatewayResponseModifier))
 	v1pb.RegisterAuthServiceServer(s.grpcServer, v1.NewAuthService(s.store, s.secret, s.MetricReporter, &profile))
 	v1pb.RegisterEnvironmentServiceServer(s.grpcServer, v1.NewEnvironmentService(s.store, s.licenseService))
-	v1pb.RegisterInstanceServiceServer(s.grpcServer, v1.NewInstanceService(s.store, s.licenseService))
+	v1pb.RegisterInstanceServiceServer(s.grpcServer, v1.NewInstanceService(s.store, s.licenseService, s.dbFactory))
 	v1pb.RegisterProjectServiceServer(s.grpcServer, v1.NewProjectService(s.store))
 	v1pb.RegisterDatabaseServiceServer(s.grpcServer, v1.NewDatabaseService(s.store))
 	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
@@ -533,6 +557,8 @@ type workspaceConfig struct {
 	secret string
 	// workspaceID used to initial the identify for a new workspace.
 	workspaceID string
+	// maskingSalt is the salt for the HASH sensitive data mask.
+	maskingSalt string
 }
 
 func getInitSetting(ctx context.Context, store *store.Store) (*workspaceConfig, error) {
@@ -579,6 +605,36 @@ func getInitSetting(ctx context.Context, store *store.Store) (*workspaceConfig,
 	}
 	conf.workspaceID = workspaceSetting.Value
 
+	// initial masking salt
+	value, err = common.RandomString(secretLength)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate random masking salt")
+	}
+	maskingSaltSetting, _, err := store.CreateSettingIfNotExist(ctx, &api.SettingCreate{
+		CreatorID:   api.SystemBotID,
+		Name:        api.SettingMaskingSalt,
+		Value:       value,
+		Description: "Random string used to salt the hash of the sensitive data.",
+	})
+	if err != nil {
+		return nil, err
+	}
+	conf.maskingSalt = maskingSaltSetting.Value
+
+	// initial sensitive data classification
+	classificationValue, err := json.Marshal(api.GetDefaultSensitiveDataClassification())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal default sensitive data classification")
+	}
+	if _, _, err := store.CreateSettingIfNotExist(ctx, &api.SettingCreate{
+		CreatorID:   api.SystemBotID,
+		Name:        api.SettingSensitiveDataClassification,
+		Value:       string(classificationValue),
+		Description: "Rules to classify the sensitive columns after the schema sync.",
+	}); err != nil {
+		return nil, err
+	}
+
 	// initial license
 	if _, _, err = store.CreateSettingIfNotExist(ctx, &api.SettingCreate{
 		CreatorID:   api.SystemBotID,
@@ -606,6 +662,9 @@ func getInitSetting(ctx context.Context, store *store.Store) (*workspaceConfig,
 func (s *Server) Run(ctx context.Context, port int) error {
 	ctx, cancel := context.WithCancel(ctx)
 	s.cancel = cancel
+	// The driver pool is used by the SQL editor in the readonly mode too.
+	s.runnerWG.Add(1)
+	go s.dbFactory.Run(ctx, &s.runnerWG)
 	if !s.profile.Readonly {
 		if err := s.TaskScheduler.ClearRunningTasks(ctx); err != nil {
 			return errors.Wrap(err, "failed to clear existing RUNNING tasks before starting the task scheduler")
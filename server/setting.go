@@ -17,6 +17,7 @@ import (
 var whitelistSettings = []api.SettingName{
 	api.SettingBrandingLogo,
 	api.SettingAppIM,
+	api.SettingSensitiveDataClassification,
 }
 
 func (s *Server) registerSettingRoutes(g *echo.Group) {
@@ -105,6 +106,12 @@ func (s *Server) registerSettingRoutes(g *echo.Group) {
 			}
 		}
 
+		if settingPatch.Name == api.SettingSensitiveDataClassification {
+			if _, err := api.UnmarshalSensitiveDataClassification(settingPatch.Value); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid sensitive data classification: %v", err)).SetInternal(err)
+			}
+		}
+
 		setting, err := s.store.PatchSetting(ctx, settingPatch)
 		if err != nil {
 			if common.ErrorCode(err) == common.NotFound {
@@ -22,10 +22,13 @@ import (
 	"github.com/bytebase/bytebase/plugin/advisor/catalog"
 	advisorDB "github.com/bytebase/bytebase/plugin/advisor/db"
 	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/db/mongodb"
 	"github.com/bytebase/bytebase/plugin/db/util"
 	"github.com/bytebase/bytebase/plugin/parser"
 	"github.com/bytebase/bytebase/plugin/parser/ast"
 	"github.com/bytebase/bytebase/server/component/activity"
+	"github.com/bytebase/bytebase/server/component/dbfactory"
+	"github.com/bytebase/bytebase/server/utils"
 )
 
 func (s *Server) registerSQLRoutes(g *echo.Group) {
@@ -53,7 +56,7 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 		}
 
 		var tlsConfig db.TLSConfig
-		supportTLS := (connectionInfo.Engine == db.ClickHouse || connectionInfo.Engine == db.MySQL || connectionInfo.Engine == db.TiDB)
+		supportTLS := (connectionInfo.Engine == db.ClickHouse || connectionInfo.Engine == db.MySQL || connectionInfo.Engine == db.TiDB || connectionInfo.Engine == db.MariaDB || connectionInfo.Engine == db.OceanBase)
 		if supportTLS {
 			if connectionInfo.SslCa == nil && connectionInfo.SslCert == nil && connectionInfo.SslKey == nil && connectionInfo.InstanceID != nil {
 				// Frontend will not pass ssl related field if user don't modify ssl suite, we need get ssl suite from database for this case.
@@ -75,6 +78,63 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 			}
 		}
 
+		sshConfig := db.SSHConfig{
+			Host:       connectionInfo.SSHHost,
+			Port:       connectionInfo.SSHPort,
+			User:       connectionInfo.SSHUser,
+			HostKey:    connectionInfo.SSHHostKey,
+			Password:   connectionInfo.SSHPassword,
+			PrivateKey: connectionInfo.SSHPrivateKey,
+		}
+		// Like the admin password, the SSH secrets are not transferred back to client.
+		if sshConfig.Host != "" && sshConfig.Password == "" && sshConfig.PrivateKey == "" && connectionInfo.InstanceID != nil {
+			storedConfig, err := s.store.GetInstanceAdminSSHConfigByID(ctx, *connectionInfo.InstanceID)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to retrieve SSH config for instance: %d", *connectionInfo.InstanceID)).SetInternal(err)
+			}
+			sshConfig.Password = storedConfig.Password
+			sshConfig.PrivateKey = storedConfig.PrivateKey
+		}
+
+		// The password of the command credential provider or the secret would be sent to the host in the request, which can be any host.
+		if connectionInfo.CredentialProvider == api.CredentialProviderCommand {
+			return echo.NewHTTPError(http.StatusBadRequest, "The command credential provider is not supported for testing the connection")
+		}
+		if connectionInfo.PasswordSecret != "" {
+			return echo.NewHTTPError(http.StatusBadRequest, "The password secret is not supported for testing the connection")
+		}
+		// The cloud IAM token is signed by the server credentials, so the roles other than the workspace owner and DBA can only send it to the stored instance.
+		if usesCloudIAMCredential(connectionInfo.CredentialProvider) {
+			role := c.Get(getRoleContextKey()).(api.Role)
+			if role != api.Owner && role != api.DBA {
+				if connectionInfo.InstanceID == nil {
+					return echo.NewHTTPError(http.StatusForbidden, "Only the workspace owner and DBA can test the connection using the cloud IAM authentication to a new instance")
+				}
+				instance, err := s.store.GetInstanceByID(ctx, *connectionInfo.InstanceID)
+				if err != nil {
+					return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch instance ID: %d", *connectionInfo.InstanceID)).SetInternal(err)
+				}
+				if instance == nil {
+					return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance ID not found: %d", *connectionInfo.InstanceID))
+				}
+				if instance.Host != connectionInfo.Host || instance.Port != connectionInfo.Port {
+					return echo.NewHTTPError(http.StatusForbidden, "Only the workspace owner and DBA can test the connection using the cloud IAM authentication to a host other than the instance host")
+				}
+			}
+		}
+		password, err := s.dbFactory.GetPassword(ctx, api.DataSourceOptions{
+			CredentialProvider: connectionInfo.CredentialProvider,
+			Region:             connectionInfo.Region,
+		}, dbfactory.CredentialRequest{
+			Host:     connectionInfo.Host,
+			Port:     connectionInfo.Port,
+			Username: connectionInfo.Username,
+			Password: password,
+		})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Failed to get the credential for user %q: %v", connectionInfo.Username, err)).SetInternal(err)
+		}
+
 		db, err := db.Open(
 			ctx,
 			connectionInfo.Engine,
@@ -87,7 +147,9 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 				TLSConfig:              tlsConfig,
 				SRV:                    connectionInfo.SRV,
 				AuthenticationDatabase: connectionInfo.AuthenticationDatabase,
+				ServiceName:            connectionInfo.ServiceName,
 				Database:               connectionInfo.Database,
+				SSHConfig:              sshConfig,
 			},
 			db.ConnectionContext{},
 		)
@@ -174,9 +236,6 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 		if !exec.Readonly {
 			return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql execute request, only support readonly sql statement")
 		}
-		if !validateSQLSelectStatement(exec.Statement) {
-			return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql execute request, only support SELECT sql statement")
-		}
 
 		instance, err := s.store.GetInstanceByID(ctx, exec.InstanceID)
 		if err != nil {
@@ -185,6 +244,18 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 		if instance == nil {
 			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Instance ID not found: %d", exec.InstanceID))
 		}
+		switch instance.Engine {
+		case db.Redis:
+			// Redis statements are commands rather than SQL, and the driver rejects the write commands itself.
+		case db.MongoDB:
+			if err := mongodb.ValidateReadOnlyQuery(exec.Statement); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Malformed sql execute request, %s", err.Error()))
+			}
+		default:
+			if !validateSQLSelectStatement(exec.Statement) {
+				return echo.NewHTTPError(http.StatusBadRequest, "Malformed sql execute request, only support SELECT sql statement")
+			}
+		}
 		principalID := c.Get(getPrincipalIDContextKey()).(int)
 		role := c.Get(getRoleContextKey()).(api.Role)
 		var database *api.Database
@@ -206,7 +277,7 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 		// Database Access Control for MySQL dialect.
 		// MySQL dialect can query cross the database.
 		// We need special check.
-		if instance.Engine == db.MySQL || instance.Engine == db.TiDB {
+		if instance.Engine == db.MySQL || instance.Engine == db.TiDB || instance.Engine == db.MariaDB || instance.Engine == db.OceanBase {
 			databaseList, err := parser.ExtractDatabaseList(parser.MySQL, exec.Statement)
 			if err != nil {
 				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to extract database list: %q", exec.Statement)).SetInternal(err)
@@ -265,11 +336,11 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create a catalog")
 			}
 
-			driver, err := s.dbFactory.GetReadOnlyDatabaseDriver(ctx, instance, exec.DatabaseName)
+			driver, release, err := s.dbFactory.AcquireReadOnlyDatabaseDriver(ctx, instance, exec.DatabaseName)
 			if err != nil {
 				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get database driver").SetInternal(err)
 			}
-			defer driver.Close(ctx)
+			defer release()
 			connection, err := driver.GetDBConnection(ctx, exec.DatabaseName)
 			if err != nil {
 				return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get database connection").SetInternal(err)
@@ -316,42 +387,31 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 		}
 
 		var sensitiveSchemaInfo *db.SensitiveSchemaInfo
-		if instance.Engine == db.MySQL || instance.Engine == db.TiDB {
+		if instance.Engine == db.MySQL || instance.Engine == db.TiDB || instance.Engine == db.MariaDB || instance.Engine == db.OceanBase {
 			databaseList, err := parser.ExtractDatabaseList(parser.MySQL, exec.Statement)
 			if err != nil {
 				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get database list: %s", exec.Statement)).SetInternal(err)
 			}
 
-			sensitiveSchemaInfo, err = s.getSensitiveSchemaInfo(ctx, instance.Engine, instance.ID, databaseList, exec.DatabaseName)
+			sensitiveSchemaInfo, err = utils.GetSensitiveSchemaInfo(ctx, s.store, instance.Engine, instance.ID, databaseList, exec.DatabaseName)
 			if err != nil {
 				return err
 			}
-		}
-
-		start := time.Now().UnixNano()
-
-		bytes, queryErr := func() ([]byte, error) {
-			driver, err := s.dbFactory.GetReadOnlyDatabaseDriver(ctx, instance, exec.DatabaseName)
+		} else if instance.Engine == db.Postgres && exec.DatabaseName != "" {
+			// Postgres queries cannot access other databases, so only the current one is needed.
+			sensitiveSchemaInfo, err = utils.GetSensitiveSchemaInfo(ctx, s.store, instance.Engine, instance.ID, []string{exec.DatabaseName}, exec.DatabaseName)
 			if err != nil {
-				return nil, err
+				return err
 			}
-			defer driver.Close(ctx)
-
-			rowSet, err := driver.Query(ctx, exec.Statement, &db.QueryContext{
-				Limit:           exec.Limit,
-				ReadOnly:        true,
-				CurrentDatabase: exec.DatabaseName,
-				// TODO(rebelice): we cannot deal with multi-SensitiveDataMaskType now. Fix it.
-				SensitiveDataMaskType: db.SensitiveDataMaskTypeDefault,
-				SensitiveSchemaInfo:   sensitiveSchemaInfo,
-			})
+		} else if instance.Engine == db.MongoDB && database != nil {
+			sensitiveSchemaInfo, err = utils.GetMongoDBSensitiveSchemaInfo(ctx, s.store, database)
 			if err != nil {
-				return nil, err
+				return err
 			}
+		}
 
-			return json.Marshal(rowSet)
-		}()
-
+		// Parse the statement before the query, because the response is committed once the rows are streamed.
+		isExplain := false
 		if instance.Engine == db.Postgres {
 			stmts, err := parser.Parse(parser.Postgres, parser.ParseContext{}, exec.Statement)
 			if err != nil {
@@ -360,12 +420,42 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 			if len(stmts) != 1 {
 				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Expected one statement, but found %d, statement: %s", len(stmts), exec.Statement))
 			}
-			if _, ok := stmts[0].(*ast.ExplainStmt); ok {
-				indexAdvice := checkPostgreSQLIndexHit(exec.Statement, string(bytes))
-				if len(indexAdvice) > 0 {
-					adviceLevel = advisor.Error
-					adviceList = append(adviceList, indexAdvice...)
-				}
+			_, isExplain = stmts[0].(*ast.ExplainStmt)
+		}
+
+		queryCtx, execution, err := s.startSQLExecution(ctx, exec.ExecutionID, principalID, instance)
+		if err != nil {
+			return err
+		}
+		start := time.Now().UnixNano()
+
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		writer := newSQLResultWriter(c.Response(), maxSQLResultSize, isExplain)
+		queryErr := func() error {
+			driver, release, err := s.dbFactory.AcquireReadOnlyDatabaseDriver(queryCtx, instance, exec.DatabaseName)
+			if err != nil {
+				return err
+			}
+			defer release()
+
+			return streamQuery(queryCtx, driver, exec.Statement, &db.QueryContext{
+				Limit:                 exec.Limit,
+				ReadOnly:              true,
+				CurrentDatabase:       exec.DatabaseName,
+				SensitiveDataMaskType: db.SensitiveDataMaskTypeDefault,
+				MaskingSalt:           s.maskingSalt,
+				SensitiveSchemaInfo:   sensitiveSchemaInfo,
+				Timeout:               execution.timeout,
+				SessionHandler:        execution.setSessionID,
+			}, writer)
+		}()
+		queryErr = s.finishSQLExecution(execution, queryErr)
+
+		if isExplain {
+			indexAdvice := checkPostgreSQLIndexHit(exec.Statement, writer.plan.String())
+			if len(indexAdvice) > 0 {
+				adviceLevel = advisor.Error
+				adviceList = append(adviceList, indexAdvice...)
 			}
 		}
 
@@ -403,37 +493,37 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 			DatabaseName:           exec.DatabaseName,
 			Error:                  errMessage,
 			AdviceList:             adviceList,
+			Canceled:               execution.isCanceled() || execution.isTimedOut(),
 		}); err != nil {
-			return err
+			// The failure has been logged, and we cannot respond with the error once the rows are streamed.
+			if !writer.Started() {
+				return err
+			}
 		}
 
-		resultSet := &api.SQLResultSet{AdviceList: adviceList}
 		if queryErr == nil {
-			resultSet.Data = string(bytes)
 			log.Debug("Query result advice",
 				zap.String("statement", exec.Statement),
-				zap.Array("advice", advisor.ZapAdviceArray(resultSet.AdviceList)),
+				zap.Array("advice", advisor.ZapAdviceArray(adviceList)),
 			)
 		} else {
-			resultSet.Error = queryErr.Error()
 			if s.profile.Mode == common.ReleaseModeDev {
 				log.Error("Failed to execute query",
-					zap.Error(err),
+					zap.Error(queryErr),
 					zap.String("statement", exec.Statement),
-					zap.Array("advice", advisor.ZapAdviceArray(resultSet.AdviceList)),
+					zap.Array("advice", advisor.ZapAdviceArray(adviceList)),
 				)
 			} else {
 				log.Debug("Failed to execute query",
-					zap.Error(err),
+					zap.Error(queryErr),
 					zap.String("statement", exec.Statement),
-					zap.Array("advice", advisor.ZapAdviceArray(resultSet.AdviceList)),
+					zap.Array("advice", advisor.ZapAdviceArray(adviceList)),
 				)
 			}
 		}
 
-		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
-		if err := jsonapi.MarshalPayload(c.Response().Writer, resultSet); err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal sql result set response").SetInternal(err)
+		if err := writer.Close(queryErr, adviceList); err != nil {
+			log.Warn("Failed to write sql result set response", zap.String("statement", exec.Statement), zap.Error(err))
 		}
 		return nil
 	})
@@ -479,27 +569,31 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 
 		// Admin API always executes with read-only off.
 		exec.Readonly = true
+		queryCtx, execution, err := s.startSQLExecution(ctx, exec.ExecutionID, c.Get(getPrincipalIDContextKey()).(int), instance)
+		if err != nil {
+			return err
+		}
 		start := time.Now().UnixNano()
 
-		bytes, queryErr := func() ([]byte, error) {
-			driver, err := s.dbFactory.GetAdminDatabaseDriver(ctx, instance, exec.DatabaseName)
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+		writer := newSQLResultWriter(c.Response(), maxSQLResultSize, false /* keepPlan */)
+		queryErr := func() error {
+			driver, err := s.dbFactory.GetAdminDatabaseDriver(queryCtx, instance, exec.DatabaseName)
 			if err != nil {
-				return nil, err
+				return err
 			}
 			defer driver.Close(ctx)
 
-			rowSet, err := driver.Query(ctx, exec.Statement, &db.QueryContext{
+			return streamQuery(queryCtx, driver, exec.Statement, &db.QueryContext{
 				Limit:               exec.Limit,
 				ReadOnly:            false,
 				CurrentDatabase:     exec.DatabaseName,
 				SensitiveSchemaInfo: nil,
-			})
-			if err != nil {
-				return nil, err
-			}
-
-			return json.Marshal(rowSet)
+				Timeout:             execution.timeout,
+				SessionHandler:      execution.setSessionID,
+			}, writer)
 		}()
+		queryErr = s.finishSQLExecution(execution, queryErr)
 
 		level := api.ActivityInfo
 		errMessage := ""
@@ -519,39 +613,57 @@ func (s *Server) registerSQLRoutes(g *echo.Group) {
 			DatabaseID:             databaseID,
 			DatabaseName:           exec.DatabaseName,
 			Error:                  errMessage,
+			Canceled:               execution.isCanceled() || execution.isTimedOut(),
 		}); err != nil {
-			return err
+			// The failure has been logged, and we cannot respond with the error once the rows are streamed.
+			if !writer.Started() {
+				return err
+			}
 		}
 
-		resultSet := &api.SQLResultSet{
-			AdviceList: []advisor.Advice{},
-		}
 		if queryErr == nil {
-			resultSet.Data = string(bytes)
 			log.Debug("Query result advice",
 				zap.String("statement", exec.Statement),
 			)
 		} else {
-			resultSet.Error = queryErr.Error()
 			if s.profile.Mode == common.ReleaseModeDev {
 				log.Error("Failed to execute query",
-					zap.Error(err),
+					zap.Error(queryErr),
 					zap.String("statement", exec.Statement),
 				)
 			} else {
 				log.Debug("Failed to execute query",
-					zap.Error(err),
+					zap.Error(queryErr),
 					zap.String("statement", exec.Statement),
 				)
 			}
 		}
 
-		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
-		if err := jsonapi.MarshalPayload(c.Response().Writer, resultSet); err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to marshal sql result set response").SetInternal(err)
+		if err := writer.Close(queryErr, []advisor.Advice{}); err != nil {
+			log.Warn("Failed to write sql result set response", zap.String("statement", exec.Statement), zap.Error(err))
 		}
 		return nil
 	})
+
+	g.POST("/sql/execute/:executionID/cancel", func(c echo.Context) error {
+		ctx := c.Request().Context()
+		executionID := c.Param("executionID")
+		value, ok := s.stateCfg.RunningSQLExecutions.Load(executionID)
+		if !ok {
+			return echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("SQL execution %q not found, it may have finished", executionID))
+		}
+		execution := value.(*sqlExecution)
+
+		principalID := c.Get(getPrincipalIDContextKey()).(int)
+		role := c.Get(getRoleContextKey()).(api.Role)
+		if execution.creatorID != principalID && role != api.Owner && role != api.DBA {
+			return echo.NewHTTPError(http.StatusForbidden, "Only the creator, workspace owner and DBA can cancel the SQL execution")
+		}
+		if err := s.cancelSQLExecution(ctx, execution); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to cancel SQL execution %q", executionID)).SetInternal(err)
+		}
+		return c.String(http.StatusOK, "ok")
+	})
 }
 
 func validateSQLSelectStatement(sqlStatement string) bool {
@@ -707,114 +819,6 @@ func (s *Server) getDatabase(ctx context.Context, instanceID int, databaseName s
 	return dbList[0], nil
 }
 
-func (s *Server) getSensitiveSchemaInfo(ctx context.Context, engineType db.Type, instanceID int, databaseList []string, currentDatabase string) (*db.SensitiveSchemaInfo, error) {
-	type sensitiveDataMap map[api.SensitiveData]api.SensitiveDataMaskType
-	isEmpty := true
-	result := &db.SensitiveSchemaInfo{
-		DatabaseList: []db.DatabaseSchema{},
-	}
-	for _, name := range databaseList {
-		databaseName := name
-		if name == "" {
-			if currentDatabase == "" {
-				continue
-			}
-			databaseName = currentDatabase
-		}
-
-		if isExcludeDatabase(engineType, databaseName) {
-			continue
-		}
-
-		database, err := s.getDatabase(ctx, instanceID, databaseName)
-		if err != nil {
-			return nil, err
-		}
-
-		columnMap := make(sensitiveDataMap)
-
-		policy, err := s.store.GetSensitiveDataPolicy(ctx, database.ID)
-		if err != nil {
-			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find sensitive data policy for database `%s` in instance ID: %d", databaseName, instanceID))
-		}
-		for _, data := range policy.SensitiveDataList {
-			columnMap[api.SensitiveData{
-				Table:  data.Table,
-				Column: data.Column,
-			}] = data.Type
-		}
-
-		dbSchema, err := s.store.GetDBSchema(ctx, database.ID)
-		if err != nil {
-			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find table list for database %q", databaseName))
-		}
-
-		databaseSchema := db.DatabaseSchema{
-			Name:      databaseName,
-			TableList: []db.TableSchema{},
-		}
-		for _, schema := range dbSchema.Metadata.Schemas {
-			for _, table := range schema.Tables {
-				tableSchema := db.TableSchema{
-					Name:       table.Name,
-					ColumnList: []db.ColumnInfo{},
-				}
-				for _, column := range table.Columns {
-					_, sensitive := columnMap[api.SensitiveData{
-						Table:  table.Name,
-						Column: column.Name,
-					}]
-					tableSchema.ColumnList = append(tableSchema.ColumnList, db.ColumnInfo{
-						Name:      column.Name,
-						Sensitive: sensitive,
-					})
-				}
-				databaseSchema.TableList = append(databaseSchema.TableList, tableSchema)
-			}
-		}
-		if len(databaseSchema.TableList) > 0 {
-			isEmpty = false
-		}
-		result.DatabaseList = append(result.DatabaseList, databaseSchema)
-	}
-
-	if isEmpty {
-		// If there is no tables, this query may access system databases, such as INFORMATION_SCHEMA.
-		// Skip to extract sensitive column for this query.
-		result = nil
-	}
-	return result, nil
-}
-
-func isExcludeDatabase(dbType db.Type, database string) bool {
-	switch dbType {
-	case db.MySQL:
-		return isMySQLExcludeDatabase(database)
-	case db.TiDB:
-		if isMySQLExcludeDatabase(database) {
-			return true
-		}
-		return database == "metrics_schema"
-	default:
-		return false
-	}
-}
-
-func isMySQLExcludeDatabase(database string) bool {
-	if strings.ToLower(database) == "information_schema" {
-		return true
-	}
-
-	switch database {
-	case "mysql":
-	case "sys":
-	case "performance_schema":
-	default:
-		return false
-	}
-	return true
-}
-
 func (s *Server) hasDatabaseAccessRights(ctx context.Context, principalID int, role api.Role, database *api.Database) (bool, error) {
 	// Workspace Owners and DBAs always have database access rights.
 	if role == api.Owner || role == api.DBA {
@@ -0,0 +1,194 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common/log"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// killQueryTimeout is the timeout of killing the query exceeding the max execution time.
+const killQueryTimeout = 10 * time.Second
+
+// sqlExecution is a running query of the SQL editor.
+// It can be canceled by the creator, the workspace owner and DBA, and it's canceled when exceeding the max execution time.
+type sqlExecution struct {
+	id        string
+	creatorID int
+	instance  *api.Instance
+	// timeout is the max execution time of the query, and there is no timeout if it's zero.
+	timeout time.Duration
+	// cancel cancels the context of the query.
+	cancel context.CancelFunc
+	// timer kills the query exceeding the max execution time, it's nil if there is no timeout.
+	timer *time.Timer
+
+	mu sync.Mutex
+	// killDone is signaled when the kill of the session finishes.
+	killDone  *sync.Cond
+	sessionID string
+	// killing is true while the session is being killed, and the session cannot be released until the kill finishes.
+	killing  bool
+	canceled bool
+	timedOut bool
+}
+
+// setSessionID implements the db.QueryContext.SessionHandler.
+// The returned function is called before the session is released, so the session isn't killed after it's reused by other queries.
+func (e *sqlExecution) setSessionID(sessionID string) func() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.sessionID = sessionID
+	return func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		for e.killing {
+			e.killDone.Wait()
+		}
+		e.sessionID = ""
+	}
+}
+
+// beginKill returns the session ID running the query, and holds the session until endKill is called.
+// It returns an empty string if the query hasn't started or has finished.
+func (e *sqlExecution) beginKill() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.sessionID != "" {
+		e.killing = true
+	}
+	return e.sessionID
+}
+
+func (e *sqlExecution) endKill() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.killing = false
+	e.killDone.Broadcast()
+}
+
+func (e *sqlExecution) markCanceled() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.canceled = true
+}
+
+func (e *sqlExecution) markTimedOut() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.timedOut = true
+}
+
+func (e *sqlExecution) isCanceled() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.canceled
+}
+
+func (e *sqlExecution) isTimedOut() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.timedOut
+}
+
+// startSQLExecution registers the execution, and the returned context is canceled when the execution is canceled.
+// The query is killed while it's still running if it exceeds the max execution time, because the context cancellation doesn't stop the query for some engines.
+// The caller must call finishSQLExecution after the query.
+func (s *Server) startSQLExecution(ctx context.Context, executionID string, creatorID int, instance *api.Instance) (context.Context, *sqlExecution, error) {
+	policy, err := s.store.GetQueryTimeoutPolicyByEnvID(ctx, instance.EnvironmentID)
+	if err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to get query timeout policy for environment %d", instance.EnvironmentID)).SetInternal(err)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	execution := &sqlExecution{
+		id:        executionID,
+		creatorID: creatorID,
+		instance:  instance,
+		timeout:   time.Duration(policy.MaxExecutionSeconds) * time.Second,
+		cancel:    cancel,
+	}
+	execution.killDone = sync.NewCond(&execution.mu)
+	if executionID != "" {
+		if _, loaded := s.stateCfg.RunningSQLExecutions.LoadOrStore(executionID, execution); loaded {
+			cancel()
+			return nil, nil, echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("SQL execution %q is running", executionID))
+		}
+	}
+	if execution.timeout > 0 {
+		execution.timer = time.AfterFunc(execution.timeout, func() {
+			execution.markTimedOut()
+			defer execution.cancel()
+			killCtx, killCancel := context.WithTimeout(context.Background(), killQueryTimeout)
+			defer killCancel()
+			if err := s.killSQLExecution(killCtx, execution); err != nil {
+				log.Warn("Failed to kill the query exceeding the max execution time",
+					zap.Int("instance_id", execution.instance.ID),
+					zap.Error(err))
+			}
+		})
+	}
+	return ctx, execution, nil
+}
+
+// finishSQLExecution unregisters the execution and converts the query error if the execution is canceled or timed out.
+func (s *Server) finishSQLExecution(execution *sqlExecution, queryErr error) error {
+	if execution.id != "" {
+		s.stateCfg.RunningSQLExecutions.Delete(execution.id)
+	}
+	if execution.timer != nil {
+		execution.timer.Stop()
+	}
+	execution.cancel()
+
+	if queryErr == nil {
+		return nil
+	}
+	if execution.isCanceled() {
+		return errors.New("the query is canceled")
+	}
+	if execution.isTimedOut() {
+		return errors.Errorf("the query exceeds the max execution time of %v", execution.timeout)
+	}
+	return queryErr
+}
+
+// cancelSQLExecution kills the query with the engine-native command if supported, and cancels the context of the query.
+func (s *Server) cancelSQLExecution(ctx context.Context, execution *sqlExecution) error {
+	execution.markCanceled()
+	defer execution.cancel()
+	return s.killSQLExecution(ctx, execution)
+}
+
+// killSQLExecution kills the query if it's still running, and the session is held until the kill finishes.
+func (s *Server) killSQLExecution(ctx context.Context, execution *sqlExecution) error {
+	sessionID := execution.beginKill()
+	if sessionID == "" {
+		return nil
+	}
+	defer execution.endKill()
+	return s.killQuery(ctx, execution.instance, sessionID)
+}
+
+// killQuery kills the query running in the session with the admin data source.
+func (s *Server) killQuery(ctx context.Context, instance *api.Instance, sessionID string) error {
+	driver, err := s.dbFactory.GetAdminDatabaseDriver(ctx, instance, "")
+	if err != nil {
+		return err
+	}
+	defer driver.Close(ctx)
+
+	canceler, ok := driver.(db.QueryCanceler)
+	if !ok {
+		return nil
+	}
+	return canceler.CancelQuery(ctx, sessionID)
+}
@@ -0,0 +1,38 @@
+package server
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLExecutionSession(t *testing.T) {
+	a := require.New(t)
+	execution := &sqlExecution{}
+	execution.killDone = sync.NewCond(&execution.mu)
+
+	// No session to kill before the query starts.
+	a.Equal("", execution.beginKill())
+
+	release := execution.setSessionID("42")
+	a.Equal("42", execution.beginKill())
+
+	// The session cannot be released while it's being killed.
+	released := make(chan struct{})
+	go func() {
+		release()
+		close(released)
+	}()
+	select {
+	case <-released:
+		t.Fatal("the session is released before the kill finishes")
+	case <-time.After(50 * time.Millisecond):
+	}
+	execution.endKill()
+	<-released
+
+	// The released session is never killed, it may run the query of another user.
+	a.Equal("", execution.beginKill())
+}
@@ -0,0 +1,220 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/google/jsonapi"
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+	"github.com/bytebase/bytebase/plugin/advisor"
+	"github.com/bytebase/bytebase/plugin/db"
+)
+
+// maxSQLResultSize is the maximum size of the marshalled rows returned by the SQL editor.
+const maxSQLResultSize = 10 * 1024 * 1024
+
+var (
+	_ db.RowHandler = (*sqlResultWriter)(nil)
+
+	// sqlResultEnvelopePrefix and sqlResultEnvelopeSuffix wrap the attributes object, they are generated by jsonapi,
+	// so the resource type and id are the same as jsonapi.MarshalPayload(api.SQLResultSet).
+	sqlResultEnvelopePrefix, sqlResultEnvelopeSuffix = mustGetSQLResultEnvelope()
+)
+
+// mustGetSQLResultEnvelope returns the jsonapi payload of api.SQLResultSet before and after the attributes object.
+func mustGetSQLResultEnvelope() (string, string) {
+	const placeholder = `{"attributesPlaceholder":null}`
+	payload, err := jsonapi.Marshal(&api.SQLResultSet{})
+	if err != nil {
+		panic(err)
+	}
+	onePayload, ok := payload.(*jsonapi.OnePayload)
+	if !ok {
+		panic("the jsonapi payload of api.SQLResultSet should be a OnePayload")
+	}
+	onePayload.Data.Attributes = map[string]interface{}{"attributesPlaceholder": nil}
+	envelope, err := json.Marshal(onePayload)
+	if err != nil {
+		panic(err)
+	}
+	prefix, suffix, found := strings.Cut(string(envelope), placeholder)
+	if !found {
+		panic("failed to find the attributes in the jsonapi payload of api.SQLResultSet")
+	}
+	return prefix, suffix
+}
+
+// sqlResultWriter writes the query result to the response in the format of the jsonapi payload of api.SQLResultSet.
+// The rows are written as soon as they arrive, so the memory usage doesn't grow with the number of rows.
+// The data attribute is a JSON string holding [columnNames, columnTypes, rows], and it's still a valid JSON if the query fails halfway.
+type sqlResultWriter struct {
+	w     io.Writer
+	limit int
+	// plan keeps a copy of the data for checking the EXPLAIN result, it's nil if not needed.
+	plan *bytes.Buffer
+
+	started   bool
+	streaming bool
+	rowCount  int
+	size      int
+	// writeErr is the first error writing to the response, the following writes are skipped.
+	writeErr error
+}
+
+func newSQLResultWriter(w io.Writer, limit int, keepPlan bool) *sqlResultWriter {
+	writer := &sqlResultWriter{
+		w:     w,
+		limit: limit,
+	}
+	if keepPlan {
+		writer.plan = &bytes.Buffer{}
+	}
+	return writer
+}
+
+// HandleColumns implements the db.RowHandler interface.
+func (w *sqlResultWriter) HandleColumns(names []string, types []string) error {
+	if w.started {
+		return errors.New("the columns have been written")
+	}
+	namesBytes, err := json.Marshal(names)
+	if err != nil {
+		return err
+	}
+	typesBytes, err := json.Marshal(types)
+	if err != nil {
+		return err
+	}
+	w.start()
+	w.streaming = true
+	w.writeData([]byte("["))
+	w.writeData(namesBytes)
+	w.writeData([]byte(","))
+	w.writeData(typesBytes)
+	w.writeData([]byte(",["))
+	return w.writeErr
+}
+
+// HandleRow implements the db.RowHandler interface.
+func (w *sqlResultWriter) HandleRow(row []interface{}) error {
+	rowBytes, err := json.Marshal(row)
+	if err != nil {
+		return err
+	}
+	if w.size+len(rowBytes)+1 > w.limit {
+		return errSQLResultTooLarge(w.limit)
+	}
+	if w.rowCount > 0 {
+		w.writeData([]byte(","))
+	}
+	w.writeData(rowBytes)
+	w.rowCount++
+	return w.writeErr
+}
+
+// writeRowSet writes the result returned by Driver.Query for the drivers which cannot stream the results.
+func (w *sqlResultWriter) writeRowSet(rowSet []interface{}) error {
+	data, err := json.Marshal(rowSet)
+	if err != nil {
+		return err
+	}
+	if len(data) > w.limit {
+		return errSQLResultTooLarge(w.limit)
+	}
+	w.start()
+	w.writeData(data)
+	return w.writeErr
+}
+
+// Close writes the rest of the payload with the query error and the advice list.
+// The rows written before the error are kept in the data.
+func (w *sqlResultWriter) Close(queryErr error, adviceList []advisor.Advice) error {
+	w.start()
+	if w.streaming {
+		w.writeData([]byte("]]"))
+	}
+	errMessage := ""
+	if queryErr != nil {
+		errMessage = queryErr.Error()
+	}
+	errBytes, err := json.Marshal(errMessage)
+	if err != nil {
+		return err
+	}
+	adviceBytes, err := json.Marshal(adviceList)
+	if err != nil {
+		return err
+	}
+	w.write([]byte(`","error":`))
+	w.write(errBytes)
+	w.write([]byte(`,"adviceList":`))
+	w.write(adviceBytes)
+	w.write([]byte("}"))
+	w.write([]byte(sqlResultEnvelopeSuffix))
+	w.write([]byte("\n"))
+	return w.writeErr
+}
+
+// Started returns true if the response has been written, and it's too late to respond with another status.
+func (w *sqlResultWriter) Started() bool {
+	return w.started
+}
+
+func (w *sqlResultWriter) start() {
+	if w.started {
+		return
+	}
+	w.started = true
+	w.write([]byte(sqlResultEnvelopePrefix))
+	w.write([]byte(`{"data":"`))
+}
+
+// writeData writes the data escaped as the content of a JSON string.
+func (w *sqlResultWriter) writeData(data []byte) {
+	w.size += len(data)
+	if w.plan != nil {
+		_, _ = w.plan.Write(data)
+	}
+	escaped, err := json.Marshal(string(data))
+	if err != nil {
+		w.writeErr = err
+		return
+	}
+	w.write(escaped[1 : len(escaped)-1])
+}
+
+func (w *sqlResultWriter) write(data []byte) {
+	if w.writeErr != nil {
+		return
+	}
+	if _, err := w.w.Write(data); err != nil {
+		w.writeErr = errors.Wrap(err, "failed to write the sql result set response")
+	}
+}
+
+func errSQLResultTooLarge(limit int) error {
+	return common.Errorf(common.Invalid, "the query result exceeds the limit of %d bytes, please add a LIMIT clause or select fewer columns", limit)
+}
+
+// streamQuery runs the query and writes the result to the writer.
+// The drivers which cannot stream the results fall back to Driver.Query.
+func streamQuery(ctx context.Context, driver db.Driver, statement string, queryContext *db.QueryContext, writer *sqlResultWriter) error {
+	if querier, ok := driver.(db.StreamQuerier); ok {
+		return querier.QueryStream(ctx, statement, queryContext, writer)
+	}
+	rowSet, err := driver.Query(ctx, statement, queryContext)
+	if err != nil {
+		return err
+	}
+	// Keep the same response as the streaming drivers if there are no columns.
+	if rowSet == nil {
+		return nil
+	}
+	return writer.writeRowSet(rowSet)
+}
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/jsonapi"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/advisor"
+)
+
+func TestSQLResultWriter(t *testing.T) {
+	a := require.New(t)
+	adviceList := []advisor.Advice{{Status: advisor.Success, Code: advisor.Ok, Title: "OK"}}
+
+	// Streamed rows.
+	var buf bytes.Buffer
+	writer := newSQLResultWriter(&buf, maxSQLResultSize, true /* keepPlan */)
+	a.NoError(writer.HandleColumns([]string{"id", "name"}, []string{"INT", "TEXT"}))
+	a.NoError(writer.HandleRow([]interface{}{1, `a "quoted" <name>`}))
+	a.NoError(writer.HandleRow([]interface{}{2, nil}))
+	a.NoError(writer.Close(nil, adviceList))
+	resultSet := &api.SQLResultSet{}
+	a.NoError(jsonapi.UnmarshalPayload(&buf, resultSet))
+	want := `[["id","name"],["INT","TEXT"],[[1,"a \"quoted\" \u003cname\u003e"],[2,null]]]`
+	a.Equal(want, resultSet.Data)
+	a.Equal("", resultSet.Error)
+	a.Len(resultSet.AdviceList, 1)
+	a.Equal(want, writer.plan.String())
+
+	// The rows exceeding the limit are rejected, and the data is still a valid JSON.
+	buf.Reset()
+	writer = newSQLResultWriter(&buf, 40, false /* keepPlan */)
+	a.NoError(writer.HandleColumns([]string{"id"}, []string{"INT"}))
+	a.NoError(writer.HandleRow([]interface{}{1}))
+	err := writer.HandleRow([]interface{}{"a long row exceeding the limit"})
+	a.Error(err)
+	a.NoError(writer.Close(err, adviceList))
+	resultSet = &api.SQLResultSet{}
+	a.NoError(jsonapi.UnmarshalPayload(&buf, resultSet))
+	a.Equal(`[["id"],["INT"],[[1]]]`, resultSet.Data)
+	a.Equal(err.Error(), resultSet.Error)
+
+	// No columns.
+	buf.Reset()
+	writer = newSQLResultWriter(&buf, maxSQLResultSize, false /* keepPlan */)
+	a.NoError(writer.Close(errors.New("syntax error"), []advisor.Advice{}))
+	resultSet = &api.SQLResultSet{}
+	a.NoError(jsonapi.UnmarshalPayload(&buf, resultSet))
+	a.Equal("", resultSet.Data)
+	a.Equal("syntax error", resultSet.Error)
+
+	// The result of Driver.Query.
+	buf.Reset()
+	writer = newSQLResultWriter(&buf, maxSQLResultSize, false /* keepPlan */)
+	a.NoError(writer.writeRowSet([]interface{}{[]string{"command", "result"}, []string{"TEXT", "TEXT"}, [][]interface{}{{"GET a", "1"}}}))
+	a.NoError(writer.Close(nil, []advisor.Advice{}))
+	resultSet = &api.SQLResultSet{}
+	a.NoError(jsonapi.UnmarshalPayload(&buf, resultSet))
+	a.Equal(`[["command","result"],["TEXT","TEXT"],[["GET a","1"]]]`, resultSet.Data)
+}
+
+func TestSQLResultWriterPayload(t *testing.T) {
+	a := require.New(t)
+	adviceList := []advisor.Advice{{Status: advisor.Success, Code: advisor.Ok, Title: "OK"}}
+
+	var buf bytes.Buffer
+	writer := newSQLResultWriter(&buf, maxSQLResultSize, false /* keepPlan */)
+	a.NoError(writer.HandleColumns([]string{"id"}, []string{"INT"}))
+	a.NoError(writer.HandleRow([]interface{}{1}))
+	a.NoError(writer.Close(errors.New("canceled"), adviceList))
+
+	// The streamed payload is the same as the one marshalled by jsonapi, including the resource type and id.
+	var want bytes.Buffer
+	a.NoError(jsonapi.MarshalPayload(&want, &api.SQLResultSet{
+		Data:       `[["id"],["INT"],[[1]]]`,
+		Error:      "canceled",
+		AdviceList: adviceList,
+	}))
+	var got, wantPayload map[string]interface{}
+	a.NoError(json.Unmarshal(buf.Bytes(), &got))
+	a.NoError(json.Unmarshal(want.Bytes(), &wantPayload))
+	a.Equal(wantPayload, got)
+}
@@ -0,0 +1,222 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/store"
+)
+
+// GetSensitiveSchemaInfo gets the sensitive columns of the databases from their sensitive data policies.
+// The empty database name in the list means the current database. It returns nil if no tables are accessed.
+func GetSensitiveSchemaInfo(ctx context.Context, s *store.Store, engineType db.Type, instanceID int, databaseList []string, currentDatabase string) (*db.SensitiveSchemaInfo, error) {
+	type sensitiveDataMap map[api.SensitiveData]*db.MaskAlgorithm
+	isEmpty := true
+	result := &db.SensitiveSchemaInfo{
+		DatabaseList: []db.DatabaseSchema{},
+	}
+	for _, name := range databaseList {
+		databaseName := name
+		if name == "" {
+			if currentDatabase == "" {
+				continue
+			}
+			databaseName = currentDatabase
+		}
+
+		if isExcludeDatabase(engineType, databaseName) {
+			continue
+		}
+
+		database, err := getDatabase(ctx, s, instanceID, databaseName)
+		if err != nil {
+			return nil, err
+		}
+
+		columnMap := make(sensitiveDataMap)
+
+		policy, err := s.GetSensitiveDataPolicy(ctx, database.ID)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find sensitive data policy for database `%s` in instance ID: %d", databaseName, instanceID))
+		}
+		for _, data := range policy.SensitiveDataList {
+			schemaName := data.Schema
+			if engineType == db.Postgres && schemaName == "" {
+				schemaName = "public"
+			}
+			columnMap[api.SensitiveData{
+				Schema: schemaName,
+				Table:  data.Table,
+				Column: data.Column,
+			}] = ConvertToMaskAlgorithm(data)
+		}
+
+		dbSchema, err := s.GetDBSchema(ctx, database.ID)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find table list for database %q", databaseName))
+		}
+
+		databaseSchema := db.DatabaseSchema{
+			Name:      databaseName,
+			TableList: []db.TableSchema{},
+		}
+		for _, schema := range dbSchema.Metadata.Schemas {
+			// Postgres tables are in the schemas, and the tables of other engines are in the database directly.
+			schemaName := ""
+			if engineType == db.Postgres {
+				schemaName = schema.Name
+			}
+			var tableList []db.TableSchema
+			for _, table := range schema.Tables {
+				tableSchema := db.TableSchema{
+					Name:       table.Name,
+					ColumnList: []db.ColumnInfo{},
+				}
+				for _, column := range table.Columns {
+					maskAlgorithm, sensitive := columnMap[api.SensitiveData{
+						Schema: schemaName,
+						Table:  table.Name,
+						Column: column.Name,
+					}]
+					tableSchema.ColumnList = append(tableSchema.ColumnList, db.ColumnInfo{
+						Name:          column.Name,
+						Sensitive:     sensitive,
+						MaskAlgorithm: maskAlgorithm,
+					})
+				}
+				tableList = append(tableList, tableSchema)
+			}
+			if engineType == db.Postgres {
+				databaseSchema.SchemaList = append(databaseSchema.SchemaList, db.SchemaSchema{
+					Name:      schema.Name,
+					TableList: tableList,
+				})
+			} else {
+				databaseSchema.TableList = append(databaseSchema.TableList, tableList...)
+			}
+			if len(tableList) > 0 {
+				isEmpty = false
+			}
+		}
+		result.DatabaseList = append(result.DatabaseList, databaseSchema)
+	}
+
+	if isEmpty {
+		// If there is no tables, this query may access system databases, such as INFORMATION_SCHEMA.
+		// Skip to extract sensitive column for this query.
+		result = nil
+	}
+	return result, nil
+}
+
+// GetMongoDBSensitiveSchemaInfo gets the sensitive fields from the sensitive data policy directly, because MongoDB collections have no fixed columns.
+// The table and the column of the sensitive data are the collection and the field.
+func GetMongoDBSensitiveSchemaInfo(ctx context.Context, s *store.Store, database *api.Database) (*db.SensitiveSchemaInfo, error) {
+	policy, err := s.GetSensitiveDataPolicy(ctx, database.ID)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to find sensitive data policy for database `%s` in instance ID: %d", database.Name, database.InstanceID))
+	}
+	if len(policy.SensitiveDataList) == 0 {
+		return nil, nil
+	}
+
+	databaseSchema := db.DatabaseSchema{
+		Name:      database.Name,
+		TableList: []db.TableSchema{},
+	}
+	tableIndex := make(map[string]int)
+	for _, data := range policy.SensitiveDataList {
+		i, ok := tableIndex[data.Table]
+		if !ok {
+			i = len(databaseSchema.TableList)
+			tableIndex[data.Table] = i
+			databaseSchema.TableList = append(databaseSchema.TableList, db.TableSchema{
+				Name:       data.Table,
+				ColumnList: []db.ColumnInfo{},
+			})
+		}
+		databaseSchema.TableList[i].ColumnList = append(databaseSchema.TableList[i].ColumnList, db.ColumnInfo{
+			Name:          data.Column,
+			Sensitive:     true,
+			MaskAlgorithm: ConvertToMaskAlgorithm(data),
+		})
+	}
+	return &db.SensitiveSchemaInfo{
+		DatabaseList: []db.DatabaseSchema{databaseSchema},
+	}, nil
+}
+
+// ConvertToMaskAlgorithm converts the mask type and option of the sensitive data to the mask algorithm, nil means the default one.
+func ConvertToMaskAlgorithm(data api.SensitiveData) *db.MaskAlgorithm {
+	if data.Type == api.SensitiveDataMaskTypeDefault {
+		return nil
+	}
+	algorithm := &db.MaskAlgorithm{
+		Type: db.SensitiveDataMaskType(data.Type),
+	}
+	if v := data.MaskOption; v != nil {
+		algorithm.PrefixLength = v.PrefixLength
+		algorithm.SuffixLength = v.SuffixLength
+		algorithm.RangeWidth = v.RangeWidth
+		algorithm.RangeUnit = string(v.RangeUnit)
+	}
+	return algorithm
+}
+
+func getDatabase(ctx context.Context, s *store.Store, instanceID int, databaseName string) (*api.Database, error) {
+	databaseFind := &api.DatabaseFind{
+		InstanceID: &instanceID,
+		Name:       &databaseName,
+	}
+	dbList, err := s.FindDatabase(ctx, databaseFind)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("Failed to fetch database `%s` for instance ID: %d", databaseName, instanceID)).SetInternal(err)
+	}
+	if len(dbList) == 0 {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("Database `%s` for instance ID: %d not found", databaseName, instanceID))
+	}
+	if len(dbList) > 1 {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("There are multiple database `%s` for instance ID: %d", databaseName, instanceID))
+	}
+	return dbList[0], nil
+}
+
+func isExcludeDatabase(dbType db.Type, database string) bool {
+	switch dbType {
+	case db.MySQL, db.MariaDB:
+		return isMySQLExcludeDatabase(database)
+	case db.TiDB:
+		if isMySQLExcludeDatabase(database) {
+			return true
+		}
+		return database == "metrics_schema"
+	case db.OceanBase:
+		if isMySQLExcludeDatabase(database) {
+			return true
+		}
+		return database == "oceanbase" || database == "__public" || database == "__recyclebin"
+	default:
+		return false
+	}
+}
+
+func isMySQLExcludeDatabase(database string) bool {
+	if strings.ToLower(database) == "information_schema" {
+		return true
+	}
+
+	switch database {
+	case "mysql":
+	case "sys":
+	case "performance_schema":
+	default:
+		return false
+	}
+	return true
+}
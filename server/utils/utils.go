@@ -18,9 +18,33 @@ import (
 	"github.com/bytebase/bytebase/api"
 	"github.com/bytebase/bytebase/common"
 	"github.com/bytebase/bytebase/plugin/db"
+	"github.com/bytebase/bytebase/plugin/parser"
 	"github.com/bytebase/bytebase/store"
 )
 
+// CheckCrossDatabaseQuery returns an error if the statement accesses the databases other than the current database,
+// the information schema is allowed. Only the MySQL dialect can query across the databases.
+func CheckCrossDatabaseQuery(engine db.Type, statement string, currentDatabase string) error {
+	switch engine {
+	case db.MySQL, db.TiDB, db.MariaDB, db.OceanBase:
+	default:
+		return nil
+	}
+	databaseList, err := parser.ExtractDatabaseList(parser.MySQL, statement)
+	if err != nil {
+		return errors.Wrapf(err, "failed to extract database list: %q", statement)
+	}
+	for _, databaseName := range databaseList {
+		if databaseName == "" || strings.ToUpper(databaseName) == "INFORMATION_SCHEMA" {
+			continue
+		}
+		if databaseName != currentDatabase {
+			return common.Errorf(common.Invalid, "the statement on database %q cannot access database %q", currentDatabase, databaseName)
+		}
+	}
+	return nil
+}
+
 // GetLatestSchemaVersion gets the latest schema version for a database.
 func GetLatestSchemaVersion(ctx context.Context, driver db.Driver, databaseName string) (string, error) {
 	// TODO(d): support semantic versioning.
@@ -6,7 +6,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	// Register pingcap parser driver.
+	_ "github.com/pingcap/tidb/types/parser_driver"
+
 	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/plugin/db"
 )
 
 func TestValidateDatabaseLabelList(t *testing.T) {
@@ -399,3 +403,46 @@ func TestMergeTaskCreateLists(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckCrossDatabaseQuery(t *testing.T) {
+	tests := []struct {
+		engine    db.Type
+		statement string
+		wantErr   bool
+	}{
+		{
+			engine:    db.MySQL,
+			statement: "SELECT * FROM t",
+			wantErr:   false,
+		},
+		{
+			engine:    db.MySQL,
+			statement: "SELECT * FROM db.t JOIN INFORMATION_SCHEMA.TABLES",
+			wantErr:   false,
+		},
+		{
+			engine:    db.MySQL,
+			statement: "SELECT * FROM t JOIN otherdb.t2",
+			wantErr:   true,
+		},
+		{
+			engine:    db.TiDB,
+			statement: "SELECT * FROM (SELECT * FROM otherdb.t) AS sub",
+			wantErr:   true,
+		},
+		{
+			engine:    db.Postgres,
+			statement: "SELECT * FROM otherschema.t",
+			wantErr:   false,
+		},
+	}
+
+	for _, test := range tests {
+		err := CheckCrossDatabaseQuery(test.engine, test.statement, "db")
+		if test.wantErr {
+			require.Error(t, err, test.statement)
+		} else {
+			require.NoError(t, err, test.statement)
+		}
+	}
+}
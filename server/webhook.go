@@ -1204,7 +1204,7 @@ func convertSQLAdviceToGitLabCIResult(adviceMap map[string][]advisor.Advice) *ap
 		adviceList := adviceMap[filePath]
 		testcaseList := []string{}
 		for _, advice := range adviceList {
-			if advice.Code == 0 {
+			if advice.Code == 0 || advice.Status == advisor.Suppressed {
 				continue
 			}
 
@@ -1272,7 +1272,7 @@ func convertSQLAdviceToGitHubActionResult(adviceMap map[string][]advisor.Advice)
 	for _, filePath := range fileList {
 		adviceList := adviceMap[filePath]
 		for _, advice := range adviceList {
-			if advice.Code == 0 || advice.Status == advisor.Success {
+			if advice.Code == 0 || advice.Status == advisor.Success || advice.Status == advisor.Suppressed {
 				continue
 			}
 
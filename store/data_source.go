@@ -28,17 +28,23 @@ type dataSourceRaw struct {
 	DatabaseID int
 
 	// Domain specific fields
-	Name     string
-	Type     api.DataSourceType
-	Username string
-	Password string
-	SslCa    string
-	SslCert  string
-	SslKey   string
-	Host     string
-	Port     string
-	Options  api.DataSourceOptions
-	Database string
+	Name          string
+	Type          api.DataSourceType
+	Username      string
+	Password      string
+	SslCa         string
+	SslCert       string
+	SslKey        string
+	Host          string
+	Port          string
+	Options       api.DataSourceOptions
+	Database      string
+	SSHHost       string
+	SSHPort       string
+	SSHUser       string
+	SSHHostKey    string
+	SSHPassword   string
+	SSHPrivateKey string
 }
 
 // toDataSource creates an instance of DataSource based on the dataSourceRaw.
@@ -58,17 +64,23 @@ func (raw *dataSourceRaw) toDataSource() *api.DataSource {
 		DatabaseID: raw.DatabaseID,
 
 		// Domain specific fields
-		Name:     raw.Name,
-		Type:     raw.Type,
-		Username: raw.Username,
-		Password: raw.Password,
-		SslCa:    raw.SslCa,
-		SslCert:  raw.SslCert,
-		SslKey:   raw.SslKey,
-		Host:     raw.Host,
-		Port:     raw.Port,
-		Options:  raw.Options,
-		Database: raw.Database,
+		Name:          raw.Name,
+		Type:          raw.Type,
+		Username:      raw.Username,
+		Password:      raw.Password,
+		SslCa:         raw.SslCa,
+		SslCert:       raw.SslCert,
+		SslKey:        raw.SslKey,
+		Host:          raw.Host,
+		Port:          raw.Port,
+		Options:       raw.Options,
+		Database:      raw.Database,
+		SSHHost:       raw.SSHHost,
+		SSHPort:       raw.SSHPort,
+		SSHUser:       raw.SSHUser,
+		SSHHostKey:    raw.SSHHostKey,
+		SSHPassword:   raw.SSHPassword,
+		SSHPrivateKey: raw.SSHPrivateKey,
 	}
 }
 
@@ -307,10 +319,16 @@ func (*Store) createDataSourceImpl(ctx context.Context, tx *Tx, create *api.Data
 			host,
 			port,
 			options,
-			database
+			database,
+			ssh_host,
+			ssh_port,
+			ssh_user,
+			ssh_host_key,
+			ssh_password,
+			ssh_private_key
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
-		RETURNING id, creator_id, created_ts, updater_id, updated_ts, instance_id, database_id, name, type, username, password, ssl_key, ssl_cert, ssl_ca, host, port, options, database
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, instance_id, database_id, name, type, username, password, ssl_key, ssl_cert, ssl_ca, host, port, options, database, ssh_host, ssh_port, ssh_user, ssh_host_key, ssh_password, ssh_private_key
 	`
 	var dataSourceRaw dataSourceRaw
 	if err := tx.QueryRowContext(ctx, query,
@@ -329,6 +347,12 @@ func (*Store) createDataSourceImpl(ctx context.Context, tx *Tx, create *api.Data
 		create.Port,
 		create.Options,
 		create.Database,
+		create.SSHHost,
+		create.SSHPort,
+		create.SSHUser,
+		create.SSHHostKey,
+		create.SSHPassword,
+		create.SSHPrivateKey,
 	).Scan(
 		&dataSourceRaw.ID,
 		&dataSourceRaw.CreatorID,
@@ -348,6 +372,12 @@ func (*Store) createDataSourceImpl(ctx context.Context, tx *Tx, create *api.Data
 		&dataSourceRaw.Port,
 		&dataSourceRaw.Options,
 		&dataSourceRaw.Database,
+		&dataSourceRaw.SSHHost,
+		&dataSourceRaw.SSHPort,
+		&dataSourceRaw.SSHUser,
+		&dataSourceRaw.SSHHostKey,
+		&dataSourceRaw.SSHPassword,
+		&dataSourceRaw.SSHPrivateKey,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, common.FormatDBErrorEmptyRowWithQuery(query)
@@ -392,7 +422,13 @@ func (*Store) findDataSourceImpl(ctx context.Context, tx *Tx, find *api.DataSour
 			host,
 			port,
 			options,
-			database
+			database,
+			ssh_host,
+			ssh_port,
+			ssh_user,
+			ssh_host_key,
+			ssh_password,
+			ssh_private_key
 		FROM data_source
 		WHERE `+strings.Join(where, " AND "),
 		args...,
@@ -425,6 +461,12 @@ func (*Store) findDataSourceImpl(ctx context.Context, tx *Tx, find *api.DataSour
 			&dataSourceRaw.Port,
 			&dataSourceRaw.Options,
 			&dataSourceRaw.Database,
+			&dataSourceRaw.SSHHost,
+			&dataSourceRaw.SSHPort,
+			&dataSourceRaw.SSHUser,
+			&dataSourceRaw.SSHHostKey,
+			&dataSourceRaw.SSHPassword,
+			&dataSourceRaw.SSHPrivateKey,
 		); err != nil {
 			return nil, FormatError(err)
 		}
@@ -469,6 +511,24 @@ func (*Store) patchDataSourceImpl(ctx context.Context, tx *Tx, patch *api.DataSo
 	if v := patch.Database; v != nil {
 		set, args = append(set, fmt.Sprintf("database = $%d", len(args)+1)), append(args, *v)
 	}
+	if v := patch.SSHHost; v != nil {
+		set, args = append(set, fmt.Sprintf("ssh_host = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.SSHPort; v != nil {
+		set, args = append(set, fmt.Sprintf("ssh_port = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.SSHUser; v != nil {
+		set, args = append(set, fmt.Sprintf("ssh_user = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.SSHHostKey; v != nil {
+		set, args = append(set, fmt.Sprintf("ssh_host_key = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.SSHPassword; v != nil {
+		set, args = append(set, fmt.Sprintf("ssh_password = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := patch.SSHPrivateKey; v != nil {
+		set, args = append(set, fmt.Sprintf("ssh_private_key = $%d", len(args)+1)), append(args, *v)
+	}
 	args = append(args, patch.ID)
 
 	var dataSourceRaw dataSourceRaw
@@ -477,7 +537,7 @@ func (*Store) patchDataSourceImpl(ctx context.Context, tx *Tx, patch *api.DataSo
 			UPDATE data_source
 			SET `+strings.Join(set, ", ")+`
 			WHERE id = $%d
-			RETURNING id, creator_id, created_ts, updater_id, updated_ts, instance_id, database_id, name, type, username, password, ssl_key, ssl_cert, ssl_ca, host, port, options, database
+			RETURNING id, creator_id, created_ts, updater_id, updated_ts, instance_id, database_id, name, type, username, password, ssl_key, ssl_cert, ssl_ca, host, port, options, database, ssh_host, ssh_port, ssh_user, ssh_host_key, ssh_password, ssh_private_key
 		`, len(args)),
 		args...,
 	).Scan(
@@ -499,6 +559,12 @@ func (*Store) patchDataSourceImpl(ctx context.Context, tx *Tx, patch *api.DataSo
 		&dataSourceRaw.Port,
 		&dataSourceRaw.Options,
 		&dataSourceRaw.Database,
+		&dataSourceRaw.SSHHost,
+		&dataSourceRaw.SSHPort,
+		&dataSourceRaw.SSHUser,
+		&dataSourceRaw.SSHHostKey,
+		&dataSourceRaw.SSHPassword,
+		&dataSourceRaw.SSHPrivateKey,
 	); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, &common.Error{Code: common.NotFound, Err: errors.Errorf("DataSource not found with ID %d", patch.ID)}
@@ -536,6 +602,13 @@ type DataSourceMessage struct {
 	Host     string
 	Port     string
 	Database string
+	// The SSH tunnel is used if the SSH host isn't empty.
+	SSHHost       string
+	SSHPort       string
+	SSHUser       string
+	SSHHostKey    string
+	SSHPassword   string
+	SSHPrivateKey string
 }
 
 func (*Store) listDataSourceV2(ctx context.Context, tx *Tx, instanceID string) ([]*DataSourceMessage, error) {
@@ -551,7 +624,13 @@ func (*Store) listDataSourceV2(ctx context.Context, tx *Tx, instanceID string) (
 			data_source.ssl_ca,
 			data_source.host,
 			data_source.port,
-			data_source.database
+			data_source.database,
+			data_source.ssh_host,
+			data_source.ssh_port,
+			data_source.ssh_user,
+			data_source.ssh_host_key,
+			data_source.ssh_password,
+			data_source.ssh_private_key
 		FROM data_source
 		LEFT JOIN instance ON instance.id = data_source.instance_id
 		WHERE instance.resource_id = $1`,
@@ -574,6 +653,12 @@ func (*Store) listDataSourceV2(ctx context.Context, tx *Tx, instanceID string) (
 			&dataSourceMessage.Host,
 			&dataSourceMessage.Port,
 			&dataSourceMessage.Database,
+			&dataSourceMessage.SSHHost,
+			&dataSourceMessage.SSHPort,
+			&dataSourceMessage.SSHUser,
+			&dataSourceMessage.SSHHostKey,
+			&dataSourceMessage.SSHPassword,
+			&dataSourceMessage.SSHPrivateKey,
 		); err != nil {
 			return nil, FormatError(err)
 		}
@@ -295,6 +295,30 @@ func (s *Store) GetInstanceAdminPasswordByID(ctx context.Context, instanceID int
 	return "", &common.Error{Code: common.NotFound, Err: errors.Errorf("missing admin password for instance with ID %d", instanceID)}
 }
 
+// GetInstanceAdminSSHConfigByID gets the SSH tunnel config of the instance admin data source.
+func (s *Store) GetInstanceAdminSSHConfigByID(ctx context.Context, instanceID int) (db.SSHConfig, error) {
+	dataSourceFind := &api.DataSourceFind{
+		InstanceID: &instanceID,
+	}
+	dataSourceRawList, err := s.findDataSource(ctx, dataSourceFind)
+	if err != nil {
+		return db.SSHConfig{}, err
+	}
+	for _, dataSourceRaw := range dataSourceRawList {
+		if dataSourceRaw.Type == api.Admin {
+			return db.SSHConfig{
+				Host:       dataSourceRaw.SSHHost,
+				Port:       dataSourceRaw.SSHPort,
+				User:       dataSourceRaw.SSHUser,
+				HostKey:    dataSourceRaw.SSHHostKey,
+				Password:   dataSourceRaw.SSHPassword,
+				PrivateKey: dataSourceRaw.SSHPrivateKey,
+			}, nil
+		}
+	}
+	return db.SSHConfig{}, &common.Error{Code: common.NotFound, Err: errors.Errorf("missing admin data source for instance with ID %d", instanceID)}
+}
+
 // GetInstanceSslSuiteByID gets ssl suite of instance.
 func (s *Store) GetInstanceSslSuiteByID(ctx context.Context, instanceID int) (db.TLSConfig, error) {
 	dataSourceFind := &api.DataSourceFind{
@@ -388,20 +412,26 @@ func (s *Store) createInstanceRaw(ctx context.Context, create *InstanceCreate) (
 
 	for _, dataSource := range create.DataSourceList {
 		dataSourceCreate := &api.DataSourceCreate{
-			CreatorID:  create.CreatorID,
-			InstanceID: instance.ID,
-			DatabaseID: allDatabase.ID,
-			Name:       dataSource.Name,
-			Type:       dataSource.Type,
-			Username:   dataSource.Username,
-			Password:   dataSource.Password,
-			SslKey:     dataSource.SslKey,
-			SslCert:    dataSource.SslCert,
-			SslCa:      dataSource.SslCa,
-			Host:       dataSource.Host,
-			Port:       dataSource.Port,
-			Options:    dataSource.Options,
-			Database:   dataSource.Database,
+			CreatorID:     create.CreatorID,
+			InstanceID:    instance.ID,
+			DatabaseID:    allDatabase.ID,
+			Name:          dataSource.Name,
+			Type:          dataSource.Type,
+			Username:      dataSource.Username,
+			Password:      dataSource.Password,
+			SslKey:        dataSource.SslKey,
+			SslCert:       dataSource.SslCert,
+			SslCa:         dataSource.SslCa,
+			Host:          dataSource.Host,
+			Port:          dataSource.Port,
+			Options:       dataSource.Options,
+			Database:      dataSource.Database,
+			SSHHost:       dataSource.SSHHost,
+			SSHPort:       dataSource.SSHPort,
+			SSHUser:       dataSource.SSHUser,
+			SSHHostKey:    dataSource.SSHHostKey,
+			SSHPassword:   dataSource.SSHPassword,
+			SSHPrivateKey: dataSource.SSHPrivateKey,
 		}
 		if err := s.createDataSourceRawTx(ctx, tx, dataSourceCreate); err != nil {
 			return nil, err
@@ -513,20 +543,26 @@ func (s *Store) patchInstanceRaw(ctx context.Context, patch *InstancePatch) (*in
 
 		for _, dataSource := range patch.DataSourceList {
 			dataSourceCreate := &api.DataSourceCreate{
-				CreatorID:  patch.UpdaterID,
-				InstanceID: instance.ID,
-				DatabaseID: database.ID,
-				Name:       dataSource.Name,
-				Type:       dataSource.Type,
-				Username:   dataSource.Username,
-				Password:   dataSource.Password,
-				SslKey:     dataSource.SslKey,
-				SslCert:    dataSource.SslCert,
-				SslCa:      dataSource.SslCa,
-				Host:       dataSource.Host,
-				Port:       dataSource.Port,
-				Options:    dataSource.Options,
-				Database:   dataSource.Database,
+				CreatorID:     patch.UpdaterID,
+				InstanceID:    instance.ID,
+				DatabaseID:    database.ID,
+				Name:          dataSource.Name,
+				Type:          dataSource.Type,
+				Username:      dataSource.Username,
+				Password:      dataSource.Password,
+				SslKey:        dataSource.SslKey,
+				SslCert:       dataSource.SslCert,
+				SslCa:         dataSource.SslCa,
+				Host:          dataSource.Host,
+				Port:          dataSource.Port,
+				Options:       dataSource.Options,
+				Database:      dataSource.Database,
+				SSHHost:       dataSource.SSHHost,
+				SSHPort:       dataSource.SSHPort,
+				SSHUser:       dataSource.SSHUser,
+				SSHHostKey:    dataSource.SSHHostKey,
+				SSHPassword:   dataSource.SSHPassword,
+				SSHPrivateKey: dataSource.SSHPrivateKey,
 			}
 			if err := s.createDataSourceRawTx(ctx, tx, dataSourceCreate); err != nil {
 				return nil, err
@@ -753,6 +789,8 @@ func findInstanceQuery(find *api.InstanceFind) (string, []interface{}) {
 
 // InstanceMessage is the mssage for instance.
 type InstanceMessage struct {
+	// UID is the unique immutable ID of the instance.
+	UID           int
 	EnvironmentID string
 	ResourceID    string
 	Title         string
@@ -891,19 +929,25 @@ func (s *Store) CreateInstanceV2(ctx context.Context, environmentID string, inst
 
 	for _, ds := range instanceCreate.DataSources {
 		dataSourceCreate := &api.DataSourceCreate{
-			CreatorID:  creatorID,
-			InstanceID: instanceID,
-			DatabaseID: allDatabase.ID,
-			Name:       ds.Title,
-			Type:       ds.Type,
-			Username:   ds.Username,
-			Password:   ds.Password,
-			SslKey:     ds.SslKey,
-			SslCert:    ds.SslCert,
-			SslCa:      ds.SslCa,
-			Host:       ds.Host,
-			Port:       ds.Port,
-			Database:   ds.Database,
+			CreatorID:     creatorID,
+			InstanceID:    instanceID,
+			DatabaseID:    allDatabase.ID,
+			Name:          ds.Title,
+			Type:          ds.Type,
+			Username:      ds.Username,
+			Password:      ds.Password,
+			SslKey:        ds.SslKey,
+			SslCert:       ds.SslCert,
+			SslCa:         ds.SslCa,
+			Host:          ds.Host,
+			Port:          ds.Port,
+			Database:      ds.Database,
+			SSHHost:       ds.SSHHost,
+			SSHPort:       ds.SSHPort,
+			SSHUser:       ds.SSHUser,
+			SSHHostKey:    ds.SSHHostKey,
+			SSHPassword:   ds.SSHPassword,
+			SSHPrivateKey: ds.SSHPrivateKey,
 		}
 		if err := s.createDataSourceRawTx(ctx, tx, dataSourceCreate); err != nil {
 			return nil, err
@@ -915,6 +959,7 @@ func (s *Store) CreateInstanceV2(ctx context.Context, environmentID string, inst
 	}
 
 	instance := &InstanceMessage{
+		UID:           instanceID,
 		EnvironmentID: environmentID,
 		ResourceID:    instanceCreate.ResourceID,
 		Title:         instanceCreate.Title,
@@ -1009,25 +1054,32 @@ func (s *Store) UpdateInstanceV2(ctx context.Context, patch *UpdateInstanceMessa
 
 		for _, ds := range patch.DataSources {
 			dataSourceCreate := &api.DataSourceCreate{
-				CreatorID:  patch.UpdaterID,
-				InstanceID: instanceID,
-				DatabaseID: database.ID,
-				Name:       ds.Title,
-				Type:       ds.Type,
-				Username:   ds.Username,
-				Password:   ds.Password,
-				SslKey:     ds.SslKey,
-				SslCert:    ds.SslCert,
-				SslCa:      ds.SslCa,
-				Host:       ds.Host,
-				Port:       ds.Port,
-				Database:   ds.Database,
+				CreatorID:     patch.UpdaterID,
+				InstanceID:    instanceID,
+				DatabaseID:    database.ID,
+				Name:          ds.Title,
+				Type:          ds.Type,
+				Username:      ds.Username,
+				Password:      ds.Password,
+				SslKey:        ds.SslKey,
+				SslCert:       ds.SslCert,
+				SslCa:         ds.SslCa,
+				Host:          ds.Host,
+				Port:          ds.Port,
+				Database:      ds.Database,
+				SSHHost:       ds.SSHHost,
+				SSHPort:       ds.SSHPort,
+				SSHUser:       ds.SSHUser,
+				SSHHostKey:    ds.SSHHostKey,
+				SSHPassword:   ds.SSHPassword,
+				SSHPrivateKey: ds.SSHPrivateKey,
 			}
 			if err := s.createDataSourceRawTx(ctx, tx, dataSourceCreate); err != nil {
 				return nil, err
 			}
 		}
 	}
+	instance.UID = instanceID
 	instance.Deleted = convertRowStatusToDeleted(rowStatus)
 	dataSourceList, err := s.listDataSourceV2(ctx, tx, patch.ResourceID)
 	if err != nil {
@@ -1058,6 +1110,7 @@ func (s *Store) listInstanceImplV2(ctx context.Context, tx *Tx, find *FindInstan
 	var instanceMessages []*InstanceMessage
 	rows, err := tx.QueryContext(ctx, `
 		SELECT
+			instance.id AS instance_uid,
 			environment.resource_id as environment_id,
 			instance.resource_id AS resource_id,
 			instance.name AS name,
@@ -1077,6 +1130,7 @@ func (s *Store) listInstanceImplV2(ctx context.Context, tx *Tx, find *FindInstan
 		var instanceMessage InstanceMessage
 		var rowStatus string
 		if err := rows.Scan(
+			&instanceMessage.UID,
 			&instanceMessage.EnvironmentID,
 			&instanceMessage.ResourceID,
 			&instanceMessage.Title,
@@ -0,0 +1,6 @@
+ALTER TABLE data_source ADD COLUMN ssh_host TEXT NOT NULL DEFAULT '';
+ALTER TABLE data_source ADD COLUMN ssh_port TEXT NOT NULL DEFAULT '';
+ALTER TABLE data_source ADD COLUMN ssh_user TEXT NOT NULL DEFAULT '';
+ALTER TABLE data_source ADD COLUMN ssh_host_key TEXT NOT NULL DEFAULT '';
+ALTER TABLE data_source ADD COLUMN ssh_password TEXT NOT NULL DEFAULT '';
+ALTER TABLE data_source ADD COLUMN ssh_private_key TEXT NOT NULL DEFAULT '';
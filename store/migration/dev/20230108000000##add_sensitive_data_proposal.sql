@@ -0,0 +1,26 @@
+CREATE TABLE sensitive_data_proposal (
+    id SERIAL PRIMARY KEY,
+    row_status row_status NOT NULL DEFAULT 'NORMAL',
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id) ON DELETE CASCADE,
+    schema TEXT NOT NULL,
+    "table" TEXT NOT NULL,
+    "column" TEXT NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('PENDING', 'APPROVED', 'REJECTED')),
+    payload JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE UNIQUE INDEX idx_sensitive_data_proposal_unique_database_id_schema_table_column ON sensitive_data_proposal(database_id, schema, "table", "column");
+
+CREATE INDEX idx_sensitive_data_proposal_status ON sensitive_data_proposal(status);
+
+ALTER SEQUENCE sensitive_data_proposal_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_sensitive_data_proposal_updated_ts
+BEFORE
+UPDATE
+    ON sensitive_data_proposal FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
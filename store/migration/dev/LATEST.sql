@@ -282,7 +282,7 @@ CREATE TABLE instance (
     updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
     environment_id INTEGER NOT NULL REFERENCES environment (id),
     name TEXT NOT NULL,
-    engine TEXT NOT NULL CONSTRAINT instance_engine_check CHECK (engine IN ('MYSQL', 'POSTGRES', 'TIDB', 'CLICKHOUSE', 'SNOWFLAKE', 'SQLITE', 'MONGODB', 'SPANNER')),
+    engine TEXT NOT NULL CONSTRAINT instance_engine_check CHECK (engine IN ('MYSQL', 'POSTGRES', 'TIDB', 'CLICKHOUSE', 'SNOWFLAKE', 'SQLITE', 'MONGODB', 'SPANNER', 'MSSQL', 'ORACLE', 'REDIS', 'MARIADB', 'OCEANBASE')),
     engine_version TEXT NOT NULL DEFAULT '',
     host TEXT NOT NULL,
     port TEXT NOT NULL,
@@ -558,7 +558,13 @@ CREATE TABLE data_source (
     host TEXT NOT NULL DEFAULT '',
     port TEXT NOT NULL DEFAULT '',
     options JSONB NOT NULL DEFAULT '{}',
-    database TEXT NOT NULL DEFAULT ''
+    database TEXT NOT NULL DEFAULT '',
+    ssh_host TEXT NOT NULL DEFAULT '',
+    ssh_port TEXT NOT NULL DEFAULT '',
+    ssh_user TEXT NOT NULL DEFAULT '',
+    ssh_host_key TEXT NOT NULL DEFAULT '',
+    ssh_password TEXT NOT NULL DEFAULT '',
+    ssh_private_key TEXT NOT NULL DEFAULT ''
 );
 
 CREATE INDEX idx_data_source_instance_id ON data_source(instance_id);
@@ -1193,3 +1199,32 @@ BEFORE
 UPDATE
     ON external_approval FOR EACH ROW
 EXECUTE FUNCTION trigger_update_updated_ts();
+
+-- sensitive_data_proposal stores the sensitive columns found by the classifier after the schema sync.
+-- The approved proposal is added to the sensitive data policy of the database.
+CREATE TABLE sensitive_data_proposal (
+    id SERIAL PRIMARY KEY,
+    row_status row_status NOT NULL DEFAULT 'NORMAL',
+    creator_id INTEGER NOT NULL REFERENCES principal (id),
+    created_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    updater_id INTEGER NOT NULL REFERENCES principal (id),
+    updated_ts BIGINT NOT NULL DEFAULT extract(epoch from now()),
+    database_id INTEGER NOT NULL REFERENCES db (id) ON DELETE CASCADE,
+    schema TEXT NOT NULL,
+    "table" TEXT NOT NULL,
+    "column" TEXT NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('PENDING', 'APPROVED', 'REJECTED')),
+    payload JSONB NOT NULL DEFAULT '{}'
+);
+
+CREATE UNIQUE INDEX idx_sensitive_data_proposal_unique_database_id_schema_table_column ON sensitive_data_proposal(database_id, schema, "table", "column");
+
+CREATE INDEX idx_sensitive_data_proposal_status ON sensitive_data_proposal(status);
+
+ALTER SEQUENCE sensitive_data_proposal_id_seq RESTART WITH 101;
+
+CREATE TRIGGER update_sensitive_data_proposal_updated_ts
+BEFORE
+UPDATE
+    ON sensitive_data_proposal FOR EACH ROW
+EXECUTE FUNCTION trigger_update_updated_ts();
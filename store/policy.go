@@ -179,6 +179,20 @@ func (s *Store) GetBackupPlanPolicyByEnvID(ctx context.Context, environmentID in
 	return api.UnmarshalBackupPlanPolicy(policy.Payload)
 }
 
+// GetQueryTimeoutPolicyByEnvID will get the query timeout policy for an environment.
+func (s *Store) GetQueryTimeoutPolicyByEnvID(ctx context.Context, environmentID int) (*api.QueryTimeoutPolicy, error) {
+	environmentResourceType := api.PolicyResourceTypeEnvironment
+	policy, err := s.getPolicyRaw(ctx, &api.PolicyFind{
+		ResourceType: &environmentResourceType,
+		ResourceID:   &environmentID,
+		Type:         api.PolicyTypeQueryTimeout,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return api.UnmarshalQueryTimeoutPolicy(policy.Payload)
+}
+
 // GetPipelineApprovalPolicy will get the pipeline approval policy for an environment.
 func (s *Store) GetPipelineApprovalPolicy(ctx context.Context, environmentID int) (*api.PipelineApprovalPolicy, error) {
 	var payload *string
@@ -0,0 +1,274 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/bytebase/bytebase/api"
+	"github.com/bytebase/bytebase/common"
+)
+
+// sensitiveDataProposalRaw is the store model for a SensitiveDataProposal.
+// Fields have exactly the same meanings as SensitiveDataProposal.
+type sensitiveDataProposalRaw struct {
+	ID int
+
+	// Standard fields
+	CreatorID int
+	CreatedTs int64
+	UpdaterID int
+	UpdatedTs int64
+
+	// Related fields
+	DatabaseID int
+
+	// Domain specific fields
+	Schema  string
+	Table   string
+	Column  string
+	Status  api.SensitiveDataProposalStatus
+	Payload string
+}
+
+// toSensitiveDataProposal creates an instance of SensitiveDataProposal based on the sensitiveDataProposalRaw.
+// This is intended to be called when we need to compose a SensitiveDataProposal relationship.
+func (raw *sensitiveDataProposalRaw) toSensitiveDataProposal() *api.SensitiveDataProposal {
+	return &api.SensitiveDataProposal{
+		ID: raw.ID,
+
+		// Standard fields
+		CreatorID: raw.CreatorID,
+		CreatedTs: raw.CreatedTs,
+		UpdaterID: raw.UpdaterID,
+		UpdatedTs: raw.UpdatedTs,
+
+		// Related fields
+		DatabaseID: raw.DatabaseID,
+
+		// Domain specific fields
+		Schema:  raw.Schema,
+		Table:   raw.Table,
+		Column:  raw.Column,
+		Status:  raw.Status,
+		Payload: raw.Payload,
+	}
+}
+
+// CreateSensitiveDataProposalIfNotExist creates a pending sensitive data proposal if the column has never been proposed.
+// The column proposed before is not proposed again, no matter whether the proposal is approved or rejected.
+// Returns true if the proposal is created.
+func (s *Store) CreateSensitiveDataProposalIfNotExist(ctx context.Context, create *api.SensitiveDataProposalCreate) (bool, error) {
+	if create.Payload == "" {
+		create.Payload = "{}"
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO sensitive_data_proposal (
+			creator_id,
+			updater_id,
+			database_id,
+			schema,
+			"table",
+			"column",
+			status,
+			payload
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT DO NOTHING
+	`,
+		create.CreatorID,
+		create.CreatorID,
+		create.DatabaseID,
+		create.Schema,
+		create.Table,
+		create.Column,
+		api.SensitiveDataProposalPending,
+		create.Payload,
+	)
+	if err != nil {
+		return false, FormatError(err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, FormatError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, FormatError(err)
+	}
+	return rows > 0, nil
+}
+
+// FindSensitiveDataProposal finds a list of SensitiveDataProposal instances.
+func (s *Store) FindSensitiveDataProposal(ctx context.Context, find *api.SensitiveDataProposalFind) ([]*api.SensitiveDataProposal, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	rawList, err := findSensitiveDataProposalImpl(ctx, tx, find)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find SensitiveDataProposal list with SensitiveDataProposalFind[%+v]", find)
+	}
+	var proposalList []*api.SensitiveDataProposal
+	for _, raw := range rawList {
+		proposal, err := s.composeSensitiveDataProposal(ctx, raw)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to compose SensitiveDataProposal with sensitiveDataProposalRaw[%+v]", raw)
+		}
+		proposalList = append(proposalList, proposal)
+	}
+	return proposalList, nil
+}
+
+// GetSensitiveDataProposalByID gets an instance of SensitiveDataProposal.
+func (s *Store) GetSensitiveDataProposalByID(ctx context.Context, id int) (*api.SensitiveDataProposal, error) {
+	proposalList, err := s.FindSensitiveDataProposal(ctx, &api.SensitiveDataProposalFind{ID: &id})
+	if err != nil {
+		return nil, err
+	}
+	if len(proposalList) == 0 {
+		return nil, nil
+	}
+	return proposalList[0], nil
+}
+
+// PatchSensitiveDataProposal patches the status of a pending SensitiveDataProposal.
+// Returns ENOTFOUND if the pending proposal does not exist, so that the proposal is reviewed at most once.
+func (s *Store) PatchSensitiveDataProposal(ctx context.Context, patch *api.SensitiveDataProposalPatch) (*api.SensitiveDataProposal, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE sensitive_data_proposal
+		SET updater_id = $1, status = $2
+		WHERE id = $3 AND status = $4
+		RETURNING id, creator_id, created_ts, updater_id, updated_ts, database_id, schema, "table", "column", status, payload
+	`
+	var raw sensitiveDataProposalRaw
+	if err := tx.QueryRowContext(ctx, query,
+		patch.UpdaterID,
+		patch.Status,
+		patch.ID,
+		api.SensitiveDataProposalPending,
+	).Scan(
+		&raw.ID,
+		&raw.CreatorID,
+		&raw.CreatedTs,
+		&raw.UpdaterID,
+		&raw.UpdatedTs,
+		&raw.DatabaseID,
+		&raw.Schema,
+		&raw.Table,
+		&raw.Column,
+		&raw.Status,
+		&raw.Payload,
+	); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &common.Error{Code: common.NotFound, Err: errors.Errorf("pending sensitive data proposal ID not found: %d", patch.ID)}
+		}
+		return nil, FormatError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, FormatError(err)
+	}
+	return s.composeSensitiveDataProposal(ctx, &raw)
+}
+
+//
+// private functions
+//
+
+func (s *Store) composeSensitiveDataProposal(ctx context.Context, raw *sensitiveDataProposalRaw) (*api.SensitiveDataProposal, error) {
+	proposal := raw.toSensitiveDataProposal()
+
+	creator, err := s.GetPrincipalByID(ctx, proposal.CreatorID)
+	if err != nil {
+		return nil, err
+	}
+	proposal.Creator = creator
+
+	updater, err := s.GetPrincipalByID(ctx, proposal.UpdaterID)
+	if err != nil {
+		return nil, err
+	}
+	proposal.Updater = updater
+
+	return proposal, nil
+}
+
+func findSensitiveDataProposalImpl(ctx context.Context, tx *Tx, find *api.SensitiveDataProposalFind) ([]*sensitiveDataProposalRaw, error) {
+	// Build WHERE clause.
+	where, args := []string{"1 = 1"}, []interface{}{}
+	if v := find.ID; v != nil {
+		where, args = append(where, fmt.Sprintf("id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.DatabaseID; v != nil {
+		where, args = append(where, fmt.Sprintf("database_id = $%d", len(args)+1)), append(args, *v)
+	}
+	if v := find.Status; v != nil {
+		where, args = append(where, fmt.Sprintf("status = $%d", len(args)+1)), append(args, *v)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			id,
+			creator_id,
+			created_ts,
+			updater_id,
+			updated_ts,
+			database_id,
+			schema,
+			"table",
+			"column",
+			status,
+			payload
+		FROM sensitive_data_proposal
+		WHERE `+strings.Join(where, " AND ")+`
+		ORDER BY id ASC`,
+		args...,
+	)
+	if err != nil {
+		return nil, FormatError(err)
+	}
+	defer rows.Close()
+
+	var rawList []*sensitiveDataProposalRaw
+	for rows.Next() {
+		var raw sensitiveDataProposalRaw
+		if err := rows.Scan(
+			&raw.ID,
+			&raw.CreatorID,
+			&raw.CreatedTs,
+			&raw.UpdaterID,
+			&raw.UpdatedTs,
+			&raw.DatabaseID,
+			&raw.Schema,
+			&raw.Table,
+			&raw.Column,
+			&raw.Status,
+			&raw.Payload,
+		); err != nil {
+			return nil, FormatError(err)
+		}
+		rawList = append(rawList, &raw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, FormatError(err)
+	}
+	return rawList, nil
+}